@@ -4,7 +4,9 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/services"
 	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
 	"backend-developer-assignment/pkg/utils"
+	"encoding/json"
 	"errors"
 
 	fiber "github.com/gofiber/fiber/v2"
@@ -13,12 +15,22 @@ import (
 
 // AccountController handles account-related HTTP requests
 type AccountController struct {
-	accountService services.AccountService
+	accountService   services.AccountService
+	challengeService services.ChallengeService
+	auditService     services.AuditService
 }
 
 // NewAccountController creates a new AccountController
-func NewAccountController(accountService services.AccountService) *AccountController {
-	return &AccountController{accountService: accountService}
+func NewAccountController(
+	accountService services.AccountService,
+	challengeService services.ChallengeService,
+	auditService services.AuditService,
+) *AccountController {
+	return &AccountController{
+		accountService:   accountService,
+		challengeService: challengeService,
+		auditService:     auditService,
+	}
 }
 
 // ListAccounts retrieves all accounts for a user
@@ -83,13 +95,13 @@ func (ac *AccountController) GetAccount(ctx *fiber.Ctx) error {
 //		@Router			/accounts [post]
 func (ac *AccountController) CreateAccount(ctx *fiber.Ctx) error {
 	type createAccountRequest struct {
-		Type          string  `json:"type" validate:"required,oneof=saving-account credit-loan goal-driven-saving"`
-		Currency      string  `json:"currency" validate:"required,alpha"`
-		AccountNumber string  `json:"account_number" validate:"required"`
-		Issuer        string  `json:"issuer" validate:"required,alpha"`
-		Color         string  `json:"color" validate:"iscolor"`
-		IsMainAccount bool    `json:"is_main_account"`
-		Amount        float64 `json:"amount"`
+		Type          string      `json:"type" validate:"required,oneof=saving-account credit-loan goal-driven-saving"`
+		Currency      string      `json:"currency" validate:"required,alpha,len=3"`
+		AccountNumber string      `json:"account_number" validate:"required"`
+		Issuer        string      `json:"issuer" validate:"required,alpha"`
+		Color         string      `json:"color" validate:"iscolor"`
+		IsMainAccount bool        `json:"is_main_account"`
+		Amount        json.Number `json:"amount" swaggertype:"string" example:"1000.00"`
 	}
 
 	// Parse request body
@@ -110,23 +122,32 @@ func (ac *AccountController) CreateAccount(ctx *fiber.Ctx) error {
 		request.Color = configs.DEFAULT_ACCOUNT_COLOR
 	}
 
+	if request.Amount == "" {
+		request.Amount = "0"
+	}
+
+	initialBalance, err := types.ParseMoney(request.Amount.String(), request.Currency)
+	if err != nil || initialBalance.IsNegative() {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid amount")
+	}
+
 	userID := ctx.Locals("userID").(string)
 
 	// Create the account with details
 	account := &models.AccountWithDetails{
 		UserID:        userID,
 		Type:          request.Type,
-		Currency:      request.Currency,
+		Currency:      initialBalance.Currency,
 		AccountNumber: request.AccountNumber,
 		Issuer:        request.Issuer,
 		Color:         request.Color,
 		IsMainAccount: request.IsMainAccount,
 		Progress:      0,
-		Amount:        request.Amount,
+		Amount:        initialBalance,
 	}
 
 	// Create the account with all its details
-	err := ac.accountService.CreateAccountWithDetails(account)
+	err = ac.accountService.CreateAccountWithDetails(account)
 	if err != nil {
 		logger.Error("Failed to create account", zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, err.Error())
@@ -139,6 +160,8 @@ func (ac *AccountController) CreateAccount(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Account created but failed to retrieve details")
 	}
 
+	ac.auditService.Record(auditEntry(ctx, models.AuditAccountCreate, "account", createdAccount.AccountID), nil, createdAccount)
+
 	return ctx.Status(fiber.StatusCreated).JSON(createdAccount)
 }
 
@@ -212,6 +235,8 @@ func (ac *AccountController) UpdateAccount(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Account updated but failed to retrieve details")
 	}
 
+	ac.auditService.Record(auditEntry(ctx, models.AuditAccountUpdate, "account", accountID), existingAccount, updatedAccount)
+
 	return ctx.Status(fiber.StatusOK).JSON(updatedAccount)
 }
 
@@ -244,6 +269,8 @@ func (ac *AccountController) SetMainAccount(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to set main account: "+err.Error())
 	}
 
+	ac.auditService.Record(auditEntry(ctx, models.AuditAccountSetMain, "account", accountID), nil, fiber.Map{"is_main_account": true})
+
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Main account set successfully"})
 }
 
@@ -257,11 +284,12 @@ func (ac *AccountController) SetMainAccount(ctx *fiber.Ctx) error {
 //	 @Security ApiKeyAuth
 //		@Param			id		path		string			true	"Account ID"
 //		@Param			amount	body		controllers.Withdraw.withdrawRequest	true	"Amount to withdraw"
+//		@Param			Idempotency-Key	header	string	false	"Client generated key, retries with the same key replay the first response"
 //		@Success		200		{object}	map[string]interface{}
 //		@Router			/accounts/{id}/withdraw [post]
 func (ac *AccountController) Withdraw(ctx *fiber.Ctx) error {
 	type withdrawRequest struct {
-		Amount float64 `json:"amount" validate:"required,gt=0"`
+		Amount json.Number `json:"amount" validate:"required" swaggertype:"string" example:"100.50"`
 	}
 
 	// Get account_id from path parameters
@@ -287,23 +315,38 @@ func (ac *AccountController) Withdraw(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
 	}
 
-	// Check if account has sufficient funds
-	if account.Amount < request.Amount {
+	amount, err := parseAmount(request.Amount, account.Currency)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// Check if account has sufficient funds, held funds cannot be withdrawn
+	if account.AvailableAmount.LessThan(amount) {
 		return ErrorResponse(ctx, fiber.StatusBadRequest, "Insufficient funds")
 	}
 
-	updatedBalance, err := ac.accountService.WithdrawFromAccount(accountID, request.Amount)
+	updatedBalance, err := ac.accountService.WithdrawFromAccount(accountID, amount)
+	entry := auditEntry(ctx, models.AuditWithdrawal, "account", accountID)
 	if err != nil {
-		if err.Error() == "insufficient funds" {
+		entry.Outcome = models.AuditFailure
+		ac.auditService.Record(entry, fiber.Map{"balance": account.Amount}, fiber.Map{"amount": amount, "error": err.Error()})
+		if errors.Is(err, services.ErrAccountFrozen) {
+			return ErrorResponse(ctx, fiber.StatusForbidden, "Account is frozen")
+		}
+		if errors.Is(err, services.ErrInsufficientFunds) {
 			return ErrorResponse(ctx, fiber.StatusBadRequest, "Insufficient funds")
 		}
-		logger.Error("Failed to withdraw from account", zap.String("account_id", accountID), zap.Float64("amount", request.Amount), zap.Error(err))
+		if errors.Is(err, services.ErrTransferLimitExceeded) {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		logger.Error("Failed to withdraw from account", zap.String("account_id", accountID), zap.Stringer("amount", amount), zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process withdrawal")
 	}
+	ac.auditService.Record(entry, fiber.Map{"balance": account.Amount}, fiber.Map{"amount": amount, "balance": updatedBalance})
 
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Withdrawal successful",
-		"amount":  request.Amount,
+		"amount":  amount,
 		"balance": updatedBalance,
 	})
 }
@@ -318,11 +361,12 @@ func (ac *AccountController) Withdraw(ctx *fiber.Ctx) error {
 //	 @Security ApiKeyAuth
 //		@Param			id		path		string			true	"Account ID"
 //		@Param			amount	body		controllers.Deposit.depositRequest	true	"Amount to deposit"
+//		@Param			Idempotency-Key	header	string	false	"Client generated key, retries with the same key replay the first response"
 //		@Success		200		{object}	map[string]interface{}
 //		@Router			/accounts/{id}/deposit [post]
 func (ac *AccountController) Deposit(ctx *fiber.Ctx) error {
 	type depositRequest struct {
-		Amount float64 `json:"amount" validate:"required,gt=0"`
+		Amount json.Number `json:"amount" validate:"required" swaggertype:"string" example:"100.50"`
 	}
 
 	// Get account_id from path parameters
@@ -342,15 +386,33 @@ func (ac *AccountController) Deposit(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
 	}
 
-	updatedBalance, err := ac.accountService.DepositToAccount(accountID, request.Amount)
+	// Get the account to ensure it exists and to know its currency
+	account, err := ac.accountService.GetAccountWithDetailByID(accountID)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	amount, err := parseAmount(request.Amount, account.Currency)
 	if err != nil {
-		logger.Error("Failed to deposit to account", zap.String("account_id", accountID), zap.Float64("amount", request.Amount), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	updatedBalance, err := ac.accountService.DepositToAccount(accountID, amount)
+	entry := auditEntry(ctx, models.AuditDeposit, "account", accountID)
+	if err != nil {
+		entry.Outcome = models.AuditFailure
+		ac.auditService.Record(entry, fiber.Map{"balance": account.Amount}, fiber.Map{"amount": amount, "error": err.Error()})
+		if errors.Is(err, services.ErrAccountFrozen) {
+			return ErrorResponse(ctx, fiber.StatusForbidden, "Account is frozen")
+		}
+		logger.Error("Failed to deposit to account", zap.String("account_id", accountID), zap.Stringer("amount", amount), zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process deposit")
 	}
+	ac.auditService.Record(entry, fiber.Map{"balance": account.Amount}, fiber.Map{"amount": amount, "balance": updatedBalance})
 
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Deposit successful",
-		"amount":  request.Amount,
+		"amount":  amount,
 		"balance": updatedBalance,
 	})
 }
@@ -358,19 +420,22 @@ func (ac *AccountController) Deposit(ctx *fiber.Ctx) error {
 // Transfer handles transferring money between accounts
 //
 //		@Summary		Transfer money
-//		@Description	Transfer money between accounts
+//		@Description	Transfer money between two different accounts. The amount is in the source account's currency, transfers to an account of another currency must pass the quote_id of an fx quote for that amount. Transfers above the step-up threshold of the currency respond 202 with a challenge_id and run once the challenge is confirmed through /challenges/{id}/confirm.
 //		@Tags			accounts
 //		@Accept			json
 //		@Produce		json
 //	 @Security ApiKeyAuth
 //		@Param			transfer	body		controllers.Transfer.transferRequest	true	"Transfer details"
+//		@Param			Idempotency-Key	header	string	false	"Client generated key, retries with the same key replay the first response"
 //		@Success		200			{object}	map[string]interface{}
+//		@Success		202			{object}	map[string]interface{}	"Transfer waits for step-up authentication"
 //		@Router			/accounts/transfer [post]
 func (ac *AccountController) Transfer(ctx *fiber.Ctx) error {
 	type transferRequest struct {
-		FromAccountID string  `json:"from_account_id" validate:"required"`
-		ToAccountID   string  `json:"to_account_id" validate:"required"`
-		Amount        float64 `json:"amount" validate:"required,gt=0"`
+		FromAccountID string      `json:"from_account_id" validate:"required"`
+		ToAccountID   string      `json:"to_account_id" validate:"required"`
+		Amount        json.Number `json:"amount" validate:"required" swaggertype:"string" example:"100.50"`
+		QuoteID       string      `json:"quote_id"` // required when the accounts use different currencies
 	}
 
 	var request transferRequest
@@ -383,31 +448,124 @@ func (ac *AccountController) Transfer(ctx *fiber.Ctx) error {
 		logger.Info("Validation error", zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
 	}
+	if request.FromAccountID == request.ToAccountID {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, services.ErrSameAccount.Error())
+	}
 
-	result, err := ac.accountService.TransferBetweenAccounts(
-		request.FromAccountID,
-		request.ToAccountID,
-		request.Amount,
-	)
+	// The transfer amount is expressed in the source account's currency. Money can be sent to any account,
+	// but only taken from the accounts of the authenticated user.
+	userID := ctx.Locals("userID").(string)
+	sourceAccount, err := ac.accountService.GetAccountWithDetailByID(request.FromAccountID)
+	if err != nil || sourceAccount.UserID != userID {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Source account not found")
+	}
 
+	amount, err := parseAmount(request.Amount, sourceAccount.Currency)
 	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	transfer := &models.PendingTransfer{
+		FromAccountID: request.FromAccountID,
+		ToAccountID:   request.ToAccountID,
+		Amount:        amount,
+		QuoteID:       request.QuoteID,
+	}
+
+	// Large transfers run once the user confirms them with a second factor
+	if ac.challengeService.RequiresStepUp(amount) {
+		challenge, err := ac.challengeService.CreateTransferChallenge(userID, transfer)
+		if err != nil {
+			return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process transfer")
+		}
+		return challengeResponse(ctx, challenge, "Confirm the transfer with your PIN or authenticator code")
+	}
+
+	return executeTransfer(ctx, ac.accountService, ac.auditService, transfer)
+}
+
+// executeTransfer runs a transfer, records it in the audit log and responds with its result
+func executeTransfer(ctx *fiber.Ctx, accountService services.AccountService, auditService services.AuditService, transfer *models.PendingTransfer) error {
+	var result *types.TransferResult
+	var err error
+	if transfer.QuoteID != "" {
+		result, err = accountService.TransferWithQuote(transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, transfer.QuoteID)
+	} else {
+		result, err = accountService.TransferBetweenAccounts(transfer.FromAccountID, transfer.ToAccountID, transfer.Amount)
+	}
+
+	entry := auditEntry(ctx, models.AuditTransfer, "account", transfer.FromAccountID)
+	if err != nil {
+		entry.Outcome = models.AuditFailure
+		auditService.Record(entry, nil, fiber.Map{"transfer": transfer, "error": err.Error()})
+
+		if errors.Is(err, services.ErrAccountFrozen) {
+			return ErrorResponse(ctx, fiber.StatusForbidden, "Account is frozen")
+		}
 		if errors.Is(err, services.ErrInsufficientFunds) {
 			return ErrorResponse(ctx, fiber.StatusBadRequest, "Insufficient funds in source account")
 		}
+		if errors.Is(err, services.ErrCurrencyMismatch) {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, "Amount must be in the source account's currency")
+		}
+		if errors.Is(err, services.ErrSameAccount) {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, services.ErrTransferLimitExceeded) {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, services.ErrQuoteRequired) || errors.Is(err, services.ErrQuoteMismatch) {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, services.ErrQuoteNotFound) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, services.ErrQuoteExpired) || errors.Is(err, services.ErrQuoteUsed) {
+			return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+		}
 		logger.Error("Failed to transfer between accounts",
-			zap.String("from_account_id", request.FromAccountID),
-			zap.String("to_account_id", request.ToAccountID),
-			zap.Float64("amount", request.Amount),
+			zap.String("from_account_id", transfer.FromAccountID),
+			zap.String("to_account_id", transfer.ToAccountID),
+			zap.Stringer("amount", transfer.Amount),
 			zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process transfer")
 	}
+	auditService.Record(entry, nil, fiber.Map{
+		"transfer":            transfer,
+		"credited_amount":     result.CreditedAmount,
+		"source_balance":      result.SourceBalance,
+		"destination_balance": result.DestinationBalance,
+		"exchange_rate":       result.ExchangeRate,
+	})
 
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+	response := fiber.Map{
 		"message":             "Transfer successful",
-		"amount":              request.Amount,
-		"from_account":        request.FromAccountID,
-		"to_account":          request.ToAccountID,
+		"amount":              transfer.Amount,
+		"credited_amount":     result.CreditedAmount,
+		"from_account":        transfer.FromAccountID,
+		"to_account":          transfer.ToAccountID,
 		"source_balance":      result.SourceBalance,
 		"destination_balance": result.DestinationBalance,
-	})
+	}
+	if result.ExchangeRate != nil {
+		response["exchange_rate"] = result.ExchangeRate
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+var errAmountNotPositive = errors.New("amount must be greater than zero")
+
+// parseAmount converts a decimal amount from a request body into Money in the given currency
+func parseAmount(raw json.Number, currency string) (types.Money, error) {
+	amount, err := types.ParseMoney(raw.String(), currency)
+	if err != nil {
+		return types.Money{}, err
+	}
+
+	if !amount.IsPositive() {
+		return types.Money{}, errAmountNotPositive
+	}
+
+	return amount, nil
 }
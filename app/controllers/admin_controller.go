@@ -0,0 +1,459 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"errors"
+	"time"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// AdminController handles the requests of operations staff, it is not scoped to the authenticated user and
+// every route is guarded by a permission instead
+type AdminController struct {
+	userService      services.UserService
+	accountService   services.AccountService
+	debitCardService services.DebitCardService
+	bannerService    services.BannerService
+	auditService     services.AuditService
+}
+
+// NewAdminController creates a new AdminController
+func NewAdminController(
+	userService services.UserService,
+	accountService services.AccountService,
+	debitCardService services.DebitCardService,
+	bannerService services.BannerService,
+	auditService services.AuditService,
+) *AdminController {
+	return &AdminController{
+		userService:      userService,
+		accountService:   accountService,
+		debitCardService: debitCardService,
+		bannerService:    bannerService,
+		auditService:     auditService,
+	}
+}
+
+// staffAuditEntry starts an audit log entry of the signed-in staff member acting on a resource
+func staffAuditEntry(ctx *fiber.Ctx, action, resourceType, resourceID string) *models.AuditLog {
+	entry := auditEntry(ctx, action, resourceType, resourceID)
+	entry.ActorType = models.AuditActorStaff
+	return entry
+}
+
+// GetUser returns a user with their roles, accounts and debit cards
+//
+//	@Summary		Look up user
+//	@Description	Get a user with their roles, accounts and debit cards. Requires the users:read permission.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	models.UserOverview
+//	@Failure		403	{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404	{object}	base.ErrorResponse	"User not found"
+//	@Router			/admin/users/{id} [get]
+func (ac *AdminController) GetUser(ctx *fiber.Ctx) error {
+	userID := ctx.Params("id")
+
+	user, err := ac.userService.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "User not found")
+		}
+		logger.Error("Failed to get user", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get user")
+	}
+
+	roles, err := ac.userService.GetUserRoles(userID)
+	if err != nil {
+		logger.Error("Failed to get user roles", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get user")
+	}
+
+	accounts, err := ac.accountService.GetAccountsWithDetailByUserID(userID)
+	if err != nil {
+		logger.Error("Failed to get user accounts", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get user")
+	}
+
+	cards, err := ac.debitCardService.GetCardWithDetailByUserID(userID)
+	if err != nil {
+		logger.Error("Failed to get user debit cards", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get user")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&models.UserOverview{
+		User:       user,
+		Roles:      roles,
+		Accounts:   accounts,
+		DebitCards: cards,
+	})
+}
+
+// FreezeAccount stops an account from moving money
+//
+//	@Summary		Freeze account
+//	@Description	Stop deposits, withdrawals, transfers and new holds on an account until it is unfrozen, operations already running complete. Requires the accounts:freeze permission.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string									true	"Account ID"
+//	@Param			freeze	body		controllers.FreezeAccount.freezeRequest	true	"Reason for the freeze"
+//	@Success		200		{object}	models.AccountWithDetails
+//	@Failure		403		{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404		{object}	base.ErrorResponse	"Account not found"
+//	@Failure		409		{object}	base.ErrorResponse	"Account is already frozen"
+//	@Router			/admin/accounts/{id}/freeze [post]
+func (ac *AdminController) FreezeAccount(ctx *fiber.Ctx) error {
+	type freezeRequest struct {
+		Reason string `json:"reason" validate:"required,max=255" example:"Reported as compromised"`
+	}
+
+	staffID := ctx.Locals("userID").(string)
+	accountID := ctx.Params("id")
+
+	var request freezeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	account, err := ac.accountService.FreezeAccount(accountID)
+	if err != nil {
+		return accountFreezeErrorResponse(ctx, accountID, err)
+	}
+
+	logger.Info("Account frozen", zap.String("account_id", accountID), zap.String("staff_id", staffID), zap.String("reason", request.Reason))
+	ac.auditService.Record(staffAuditEntry(ctx, models.AuditAccountFreeze, "account", accountID),
+		fiber.Map{"frozen": false}, fiber.Map{"frozen": true, "reason": request.Reason})
+	return ctx.Status(fiber.StatusOK).JSON(account)
+}
+
+// UnfreezeAccount lets a frozen account move money again
+//
+//	@Summary		Unfreeze account
+//	@Description	Lift the freeze of an account. Requires the accounts:freeze permission.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"Account ID"
+//	@Success		200	{object}	models.AccountWithDetails
+//	@Failure		403	{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404	{object}	base.ErrorResponse	"Account not found"
+//	@Failure		409	{object}	base.ErrorResponse	"Account is not frozen"
+//	@Router			/admin/accounts/{id}/unfreeze [post]
+func (ac *AdminController) UnfreezeAccount(ctx *fiber.Ctx) error {
+	staffID := ctx.Locals("userID").(string)
+	accountID := ctx.Params("id")
+
+	account, err := ac.accountService.UnfreezeAccount(accountID)
+	if err != nil {
+		return accountFreezeErrorResponse(ctx, accountID, err)
+	}
+
+	logger.Info("Account unfrozen", zap.String("account_id", accountID), zap.String("staff_id", staffID))
+	ac.auditService.Record(staffAuditEntry(ctx, models.AuditAccountUnfreeze, "account", accountID),
+		fiber.Map{"frozen": true}, fiber.Map{"frozen": false})
+	return ctx.Status(fiber.StatusOK).JSON(account)
+}
+
+func accountFreezeErrorResponse(ctx *fiber.Ctx, accountID string, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	case errors.Is(err, services.ErrAccountFrozen), errors.Is(err, services.ErrAccountNotFrozen):
+		return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+	}
+	logger.Error("Failed to change account freeze", zap.String("account_id", accountID), zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to update account")
+}
+
+// UpdateCardStatus sets the status of a debit card
+//
+//	@Summary		Update debit card status
+//	@Description	Set the status of a debit card, e.g. unblock it. Requires the cards:status permission.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string											true	"Card ID"
+//	@Param			status	body		controllers.UpdateCardStatus.cardStatusRequest	true	"New status"
+//	@Success		200		{object}	models.DebitCardWithDetails
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid status"
+//	@Failure		403		{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404		{object}	base.ErrorResponse	"Debit card not found"
+//	@Router			/admin/debit-cards/{id}/status [put]
+func (ac *AdminController) UpdateCardStatus(ctx *fiber.Ctx) error {
+	type cardStatusRequest struct {
+		Status string `json:"status" validate:"required,oneof=active inactive in-progress blocked" example:"active"`
+	}
+
+	staffID := ctx.Locals("userID").(string)
+	cardID := ctx.Params("id")
+
+	var request cardStatusRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	previous, err := ac.debitCardService.GetCardWithDetailByID(cardID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Debit card not found")
+		}
+		logger.Error("Failed to get debit card", zap.String("card_id", cardID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to update debit card")
+	}
+
+	card, err := ac.debitCardService.UpdateCardStatus(cardID, models.CardStatus(request.Status))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Debit card not found")
+		case errors.Is(err, services.ErrInvalidCardStatus):
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		logger.Error("Failed to update card status", zap.String("card_id", cardID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to update debit card")
+	}
+
+	logger.Info("Debit card status changed", zap.String("card_id", cardID), zap.String("staff_id", staffID), zap.String("status", request.Status))
+	ac.auditService.Record(staffAuditEntry(ctx, models.AuditCardStatusChange, "debit_card", cardID),
+		fiber.Map{"status": previous.Status}, fiber.Map{"status": card.Status})
+	return ctx.Status(fiber.StatusOK).JSON(card)
+}
+
+// ListUserBanners returns the banners of a user
+//
+//	@Summary		List banners of user
+//	@Description	List the banners shown to a user. Requires the banners:manage permission.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	[]models.Banner
+//	@Failure		403	{object}	base.ErrorResponse	"Missing permission"
+//	@Router			/admin/users/{id}/banners [get]
+func (ac *AdminController) ListUserBanners(ctx *fiber.Ctx) error {
+	banners, err := ac.bannerService.GetBannersByUserID(ctx.Params("id"))
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get banners")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(banners)
+}
+
+// CreateBanner adds a banner shown to a user
+//
+//	@Summary		Create banner
+//	@Description	Add a banner shown to a user. Requires the banners:manage permission.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			banner	body		controllers.CreateBanner.createBannerRequest	true	"Banner"
+//	@Success		201		{object}	models.Banner
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid banner"
+//	@Failure		403		{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404		{object}	base.ErrorResponse	"User not found"
+//	@Router			/admin/banners [post]
+func (ac *AdminController) CreateBanner(ctx *fiber.Ctx) error {
+	type createBannerRequest struct {
+		UserID      string `json:"user_id" validate:"required"`
+		Title       string `json:"title" validate:"required,max=255" example:"Want some money?"`
+		Description string `json:"description" validate:"required" example:"You can start applying"`
+		Image       string `json:"image" validate:"omitempty,url,max=255" example:"https://dummyimage.com/54x54/999/fff"`
+	}
+
+	var request createBannerRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	if _, err := ac.userService.GetUserByID(request.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "User not found")
+		}
+		logger.Error("Failed to get user", zap.String("user_id", request.UserID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to create banner")
+	}
+
+	banner, err := ac.bannerService.CreateBanner(request.UserID, request.Title, request.Description, request.Image)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to create banner")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(banner)
+}
+
+// UpdateBanner changes the content of a banner
+//
+//	@Summary		Update banner
+//	@Description	Change the title, description or image of a banner, omitted fields are left unchanged. Requires the banners:manage permission.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string											true	"Banner ID"
+//	@Param			banner	body		controllers.UpdateBanner.updateBannerRequest	true	"Banner fields to change"
+//	@Success		200		{object}	models.Banner
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid banner"
+//	@Failure		403		{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404		{object}	base.ErrorResponse	"Banner not found"
+//	@Router			/admin/banners/{id} [patch]
+func (ac *AdminController) UpdateBanner(ctx *fiber.Ctx) error {
+	type updateBannerRequest struct {
+		Title       string `json:"title" validate:"max=255" example:"Want some money?"`
+		Description string `json:"description" example:"You can start applying"`
+		Image       string `json:"image" validate:"omitempty,url,max=255" example:"https://dummyimage.com/54x54/999/fff"`
+	}
+
+	bannerID := ctx.Params("id")
+
+	var request updateBannerRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	banner, err := ac.bannerService.UpdateBanner(bannerID, request.Title, request.Description, request.Image)
+	if err != nil {
+		if errors.Is(err, services.ErrBannerNotFound) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Banner not found")
+		}
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to update banner")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(banner)
+}
+
+// DeleteBanner stops showing a banner
+//
+//	@Summary		Delete banner
+//	@Description	Stop showing a banner. Requires the banners:manage permission.
+//	@Tags			admin
+//	@Security		ApiKeyAuth
+//	@Param			id	path	string	true	"Banner ID"
+//	@Success		204
+//	@Failure		403	{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404	{object}	base.ErrorResponse	"Banner not found"
+//	@Router			/admin/banners/{id} [delete]
+func (ac *AdminController) DeleteBanner(ctx *fiber.Ctx) error {
+	if err := ac.bannerService.DeleteBanner(ctx.Params("id")); err != nil {
+		if errors.Is(err, services.ErrBannerNotFound) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Banner not found")
+		}
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to delete banner")
+	}
+
+	return ctx.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// ListAuditLogs returns audit log entries matching the filters, newest first
+//
+//	@Summary		List audit logs
+//	@Description	List audit log entries newest first. Every filter is optional, from and to are RFC 3339 times. Pass next_before_id of a page as before_id to get the next one. Requires the audit:read permission.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			actor_id		query		string	false	"User or staff member who acted"
+//	@Param			action			query		string	false	"Action, e.g. account.withdraw"
+//	@Param			resource_type	query		string	false	"Type of the resource acted on, e.g. account"
+//	@Param			resource_id		query		string	false	"ID of the resource acted on"
+//	@Param			from			query		string	false	"Earliest time, inclusive"
+//	@Param			to				query		string	false	"Latest time, exclusive"
+//	@Param			before_id		query		int		false	"Only entries older than this audit_id"
+//	@Param			limit			query		int		false	"Page size, 50 by default and at most 200"
+//	@Success		200				{object}	object{audit_logs=[]models.AuditLog,next_before_id=int}
+//	@Failure		400				{object}	base.ErrorResponse	"Invalid filter"
+//	@Failure		403				{object}	base.ErrorResponse	"Missing permission"
+//	@Router			/admin/audit-logs [get]
+func (ac *AdminController) ListAuditLogs(ctx *fiber.Ctx) error {
+	type auditLogQuery struct {
+		ActorID      string `query:"actor_id" validate:"max=50"`
+		Action       string `query:"action" validate:"max=50"`
+		ResourceType string `query:"resource_type" validate:"max=50"`
+		ResourceID   string `query:"resource_id" validate:"max=50"`
+		From         string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		To           string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		BeforeID     int64  `query:"before_id" validate:"min=0"`
+		Limit        int    `query:"limit" validate:"min=0,max=200"`
+	}
+	type auditLogResponse struct {
+		AuditLogs    []*models.AuditLog `json:"audit_logs"`
+		NextBeforeID int64              `json:"next_before_id,omitempty"` // absent on the last page
+	}
+
+	var query auditLogQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid query")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(query); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	filter := models.AuditLogFilter{
+		ActorID:      query.ActorID,
+		Action:       query.Action,
+		ResourceType: query.ResourceType,
+		ResourceID:   query.ResourceID,
+		BeforeID:     query.BeforeID,
+		Limit:        query.Limit,
+	}
+	if query.From != "" {
+		from, _ := time.Parse(time.RFC3339, query.From)
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, _ := time.Parse(time.RFC3339, query.To)
+		filter.To = &to
+	}
+
+	entries, err := ac.auditService.List(filter)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to list audit logs")
+	}
+
+	response := auditLogResponse{AuditLogs: entries}
+	limit := query.Limit
+	if limit == 0 {
+		limit = services.DefaultAuditLogLimit
+	}
+	if len(entries) == limit {
+		response.NextBeforeID = entries[len(entries)-1].AuditID
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -4,8 +4,12 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/services"
 	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"strconv"
 	"time"
 
 	fiber "github.com/gofiber/fiber/v2"
@@ -14,18 +18,34 @@ import (
 
 // AuthController holds the services related to users.
 type AuthController struct {
-	UserService services.UserService
+	UserService       services.UserService
+	AuthService       services.AuthService
+	PinLockoutService services.PinLockoutService
+	PinService        services.PinService
+	AuditService      services.AuditService
 }
 
 // NewAuthController creates a new AuthController.
-func NewAuthController(userService services.UserService) *AuthController {
+func NewAuthController(
+	userService services.UserService,
+	authService services.AuthService,
+	pinLockoutService services.PinLockoutService,
+	pinService services.PinService,
+	auditService services.AuditService,
+) *AuthController {
 	return &AuthController{
-		UserService: userService,
+		UserService:       userService,
+		AuthService:       authService,
+		PinLockoutService: pinLockoutService,
+		PinService:        pinService,
+		AuditService:      auditService,
 	}
 }
 
 // VerifyPin method for user PIN verification.
-// @Description Verify user PIN and return JWT token
+// @Description Verify user PIN and return JWT token. Failed attempts back off exponentially, lock the PIN for a while
+// @Description after 5 consecutive failures and for good after 10, and an address is throttled after 20 failures across users.
+// @Description Every sign-in starts a session listed by /user/sessions, signing in again with the same device_id replaces the session of the device.
 // @Summary Verify PIN and get JWT token
 // @Tags Authentication
 // @Accept json
@@ -35,39 +55,82 @@ func NewAuthController(userService services.UserService) *AuthController {
 // @Failure 400 {object} base.ErrorResponse "Invalid input format"
 // @Failure 401 {object} base.ErrorResponse "Invalid PIN"
 // @Failure 404 {object} base.ErrorResponse "User does not exist"
+// @Failure 423 {object} base.ErrorResponse "PIN is locked, Retry-After is set for a temporary lock"
+// @Failure 429 {object} base.ErrorResponse "Too many failed attempts, retry after Retry-After seconds"
 // @Failure 500 {object} base.ErrorResponse "Failed to generate token"
 // @Router /auth/verify-pin [post]
 func (c *AuthController) VerifyPin(ctx *fiber.Ctx) error {
 	type verifyPinRequest struct {
-		UserID string `json:"user_id"`
-		PIN    string `json:"pin"`
+		UserID     string `json:"user_id"`
+		PIN        string `json:"pin"`
+		DeviceID   string `json:"device_id"` // the refresh token is bound to it, renewals must send the same device_id
+		DeviceName string `json:"device_name" validate:"max=100" example:"Jane's iPhone"`
+		Platform   string `json:"platform" validate:"max=50" example:"ios"`
 	}
 	var request verifyPinRequest
 	if err := ctx.BodyParser(&request); err != nil {
 		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid input format: "+err.Error())
 	}
 
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	// Count the attempt as failed before verifying it, refusing it while the PIN or the address is locked
+	attempt, err := c.PinLockoutService.Reserve(request.UserID, ctx.IP())
+	if err != nil {
+		return pinLockedResponse(ctx, attempt, err)
+	}
+
+	// The user is not signed in yet, the attempt is attributed to the user it is made for
+	entry := auditEntry(ctx, models.AuditPinVerify, "user", request.UserID)
+	entry.ActorID = truncate(request.UserID, 50)
+
 	// Fetch stored PIN hash
 	var user *models.User
-	user, err := c.UserService.GetUserByID(request.UserID)
+	user, err = c.UserService.GetUserByID(request.UserID)
 	if err != nil {
+		entry.Outcome = models.AuditFailure
+		c.AuditService.Record(entry, nil, fiber.Map{"reason": "unknown user"})
 		logger.Error("Failed to get user by ID", zap.String("user_id", request.UserID), zap.Error(err))
+		if !errors.Is(err, sql.ErrNoRows) {
+			releasePinAttempt(c.PinLockoutService.Release, attempt)
+		} else if err := c.PinLockoutService.Unlock(request.UserID); err != nil {
+			// Guessing users counts against the address only
+			logger.Error("Failed to release PIN attempt", zap.String("user_id", request.UserID), zap.Error(err))
+		}
 		return ErrorResponse(ctx, fiber.StatusNotFound, "User does not exist")
 	}
 
-	// Verify PIN
+	// Verify PIN, a wrong one stays counted
 	if !utils.VerifyPIN(user.PIN, request.PIN) {
 		logger.Error("Invalid PIN", zap.String("user_id", request.UserID))
+		entry.Outcome = models.AuditFailure
+		c.AuditService.Record(entry, nil, fiber.Map{"reason": "invalid PIN"})
+		if attempt.State != nil && attempt.State.RetryAt != nil {
+			setRetryAfter(ctx, *attempt.State.RetryAt)
+		}
 		return ErrorResponse(ctx, fiber.StatusUnauthorized, "Invalid PIN")
 	}
 
-	// Generate JWT Token
-	token, err := utils.GenerateNewTokens(user.UserID)
+	releasePinAttempt(c.PinLockoutService.RecordSuccess, attempt)
+
+	// Start a session on the device and generate JWT Token
+	token, err := c.AuthService.IssueTokens(user.UserID, models.DeviceInfo{
+		DeviceID:   request.DeviceID,
+		DeviceName: request.DeviceName,
+		Platform:   request.Platform,
+		UserAgent:  truncate(ctx.Get(fiber.HeaderUserAgent), 255),
+		IPAddress:  ctx.IP(),
+	})
 	if err != nil {
 		logger.Error("Cannot generate token", zap.String("user_id", request.UserID), zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, fmt.Sprintf("Failed to generate token for user: %s. %s", user.UserID, err.Error()))
 	}
 
+	c.AuditService.Record(entry, nil, fiber.Map{"device_id": request.DeviceID, "device_name": request.DeviceName, "platform": request.Platform})
+
 	return ctx.JSON(fiber.Map{
 		"tokens": fiber.Map{
 			"access":  token.Access,
@@ -76,14 +139,142 @@ func (c *AuthController) VerifyPin(ctx *fiber.Ctx) error {
 	})
 }
 
+// RequestPinReset method for sending a one-time code to reset a forgotten PIN.
+// @Description Send a one-time code to reset the PIN through the notifier. The response does not tell whether the user exists, a code expires after 10 minutes and a new request replaces it.
+// @Summary Request a PIN reset code
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body controllers.RequestPinReset.requestPinResetRequest true "User to reset the PIN of"
+// @Success 202 {object} object{message=string} "Reset code sent if the user exists"
+// @Failure 400 {object} base.ErrorResponse "Invalid input format"
+// @Failure 429 {object} base.ErrorResponse "A code was requested moments ago"
+// @Failure 500 {object} base.ErrorResponse "Failed to send reset code"
+// @Router /auth/pin-reset [post]
+func (c *AuthController) RequestPinReset(ctx *fiber.Ctx) error {
+	type requestPinResetRequest struct {
+		UserID string `json:"user_id" validate:"required"`
+	}
+
+	var request requestPinResetRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid input format: "+err.Error())
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	_, err := c.PinService.RequestReset(request.UserID)
+	switch {
+	case err == nil, errors.Is(err, sql.ErrNoRows):
+		// Unknown users get the same answer
+	case errors.Is(err, services.ErrResetTooSoon):
+		return ErrorResponse(ctx, fiber.StatusTooManyRequests, err.Error())
+	default:
+		logger.Error("Failed to request PIN reset", zap.String("user_id", request.UserID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to send reset code")
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the user exists, a reset code has been sent",
+	})
+}
+
+// ConfirmPinReset method for setting a new PIN with a reset code.
+// @Description Set a new PIN with the code sent by /auth/pin-reset. This lifts a PIN lock and revokes all refresh tokens of the user, a code stops working after 5 wrong attempts.
+// @Summary Reset PIN with a reset code
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body controllers.ConfirmPinReset.confirmPinResetRequest true "Reset code and new PIN"
+// @Success 204 "PIN reset"
+// @Failure 400 {object} base.ErrorResponse "Invalid or expired reset code, or new PIN rejected"
+// @Failure 429 {object} base.ErrorResponse "Too many failed attempts from this address"
+// @Failure 500 {object} base.ErrorResponse "Failed to reset PIN"
+// @Router /auth/pin-reset/confirm [post]
+func (c *AuthController) ConfirmPinReset(ctx *fiber.Ctx) error {
+	type confirmPinResetRequest struct {
+		UserID string `json:"user_id" validate:"required"`
+		Code   string `json:"code" validate:"required"`
+		NewPIN string `json:"new_pin" validate:"required"`
+	}
+
+	var request confirmPinResetRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid input format: "+err.Error())
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	// Addresses guessing codes are throttled like those guessing PINs, a locked PIN is what the reset is for. The
+	// attempt is counted against the address before the code is checked, a wrong code stays counted.
+	attempt, err := c.PinLockoutService.ReserveIP(ctx.IP())
+	if err != nil {
+		return pinLockedResponse(ctx, attempt, err)
+	}
+
+	err = c.PinService.ConfirmReset(request.UserID, request.Code, request.NewPIN)
+	switch {
+	case err == nil:
+		releasePinAttempt(c.PinLockoutService.RecordSuccess, attempt)
+		return ctx.Status(fiber.StatusNoContent).Send(nil)
+	case errors.Is(err, services.ErrInvalidResetCode):
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPinPolicy), errors.Is(err, services.ErrPinReused):
+		releasePinAttempt(c.PinLockoutService.Release, attempt)
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	releasePinAttempt(c.PinLockoutService.Release, attempt)
+
+	logger.Error("Failed to reset PIN", zap.String("user_id", request.UserID), zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to reset PIN")
+}
+
+// pinLockedResponse maps an error of PinLockoutService.Reserve to the response refusing the attempt
+func pinLockedResponse(ctx *fiber.Ctx, attempt *models.PinAttempt, err error) error {
+	if attempt != nil && attempt.State != nil && attempt.State.RetryAt != nil {
+		setRetryAfter(ctx, *attempt.State.RetryAt)
+	}
+
+	switch {
+	case errors.Is(err, services.ErrPinLocked), errors.Is(err, services.ErrPinTemporarilyLocked):
+		return ErrorResponse(ctx, fiber.StatusLocked, err.Error())
+	case errors.Is(err, services.ErrPinBackoff), errors.Is(err, services.ErrPinThrottled):
+		return ErrorResponse(ctx, fiber.StatusTooManyRequests, err.Error())
+	}
+
+	logger.Error("Failed to count PIN attempt", zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to verify PIN")
+}
+
+// releasePinAttempt takes a reserved PIN attempt back with PinLockoutService.RecordSuccess or Release
+func releasePinAttempt(release func(attempt *models.PinAttempt) error, attempt *models.PinAttempt) {
+	if err := release(attempt); err != nil {
+		logger.Error("Failed to release PIN attempt", zap.String("user_id", attempt.UserID), zap.String("ip", attempt.IPAddress), zap.Error(err))
+	}
+}
+
+// setRetryAfter tells the client in whole seconds when it can try again
+func setRetryAfter(ctx *fiber.Ctx, retryAt time.Time) {
+	seconds := int(math.Ceil(time.Until(retryAt).Seconds()))
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+}
+
 // RenewTokens method for renew access and refresh tokens.
-// @Description Renew access and refresh tokens.
+// @Description Renew access and refresh tokens. The refresh token can be used once, presenting a used refresh token again revokes every token of its sign-in.
 // @Summary renew access and refresh tokens
 // @Tags Token
 // @Accept json
 // @Produce json
 // @Param refresh_token body models.Renew true "Refresh token"
 // @Success 200 {string} status "ok"
+// @Failure 400 {object} base.ErrorResponse "Malformed refresh token"
+// @Failure 401 {object} base.ErrorResponse "Unknown, expired, revoked or reused refresh token"
 // @Security ApiKeyAuth
 // @Router /token/renew [post]
 func (c *AuthController) RenewTokens(ctx *fiber.Ctx) error {
@@ -115,42 +306,167 @@ func (c *AuthController) RenewTokens(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
 	}
 
-	// Set expiration time from Refresh token of current user.
-	expiresRefreshToken, err := utils.ParseRefreshToken(renew.RefreshToken)
-	if err != nil {
+	// Reject malformed refresh tokens before looking them up.
+	if _, err := utils.ParseRefreshToken(renew.RefreshToken); err != nil {
 		// Return status 400 and error message.
 		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
 	}
 
-	// Checking, if now time greather than Refresh token expiration time.
-	if now < expiresRefreshToken {
-		// Define user ID.
-		userID := claims.UserID
+	// Define user ID.
+	userID := claims.UserID
+
+	// Get user by ID.
+	_, err = c.UserService.GetUserByID(userID)
+	if err != nil {
+		log.Printf("Failed to get user by ID: %s. %s", userID, err.Error())
+		// Return, if user not found.
+		return ErrorResponse(ctx, fiber.StatusNotFound, "user not found")
+	}
+
+	// Consume the refresh token and generate JWT Access & Refresh tokens.
+	tokens, err := c.AuthService.RenewTokens(userID, renew.DeviceID, renew.RefreshToken)
+	entry := auditEntry(ctx, models.AuditTokenRenew, "session", claims.SessionID)
+	entry.ActorID = truncate(userID, 50)
+	if err != nil {
+		entry.Outcome = models.AuditFailure
+		c.AuditService.Record(entry, nil, fiber.Map{"device_id": renew.DeviceID, "reason": err.Error()})
 
-		// Get user by ID.
-		_, err = c.UserService.GetUserByID(userID)
-		if err != nil {
-			log.Printf("Failed to get user by ID: %s. %s", userID, err.Error())
-			// Return, if user not found.
-			return ErrorResponse(ctx, fiber.StatusNotFound, "user not found")
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenExpired):
+			// Return status 401 and unauthorized error message.
+			return ErrorResponse(ctx, fiber.StatusUnauthorized, "unauthorized, your refresh token is expired")
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+			return ErrorResponse(ctx, fiber.StatusUnauthorized, "unauthorized, "+err.Error())
 		}
+		// Return status 500 and token generation error.
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	c.AuditService.Record(entry, nil, fiber.Map{"device_id": renew.DeviceID})
 
-		// Generate JWT Access & Refresh tokens.
-		tokens, err := utils.GenerateNewTokens(userID)
-		if err != nil {
-			// Return status 500 and token generation error.
-			return ErrorResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	// Return status 200 and new tokens.
+	return ctx.JSON(fiber.Map{
+		"tokens": fiber.Map{
+			"access":  tokens.Access,
+			"refresh": tokens.Refresh,
+		},
+	})
+}
+
+// Logout revokes the refresh token of the current sign-in.
+// @Description Revoke the refresh token and every token rotated from the same sign-in. Access tokens of the sign-in are rejected from now on.
+// @Summary Sign out of the current device
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param refresh_token body controllers.Logout.logoutRequest true "Refresh token of the sign-in"
+// @Success 204 "Signed out"
+// @Failure 400 {object} base.ErrorResponse "Invalid input format"
+// @Failure 401 {object} base.ErrorResponse "Unknown refresh token"
+// @Failure 500 {object} base.ErrorResponse "Failed to sign out"
+// @Security ApiKeyAuth
+// @Router /auth/logout [post]
+func (c *AuthController) Logout(ctx *fiber.Ctx) error {
+	type logoutRequest struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+
+	userID := ctx.Locals("userID").(string)
+
+	var request logoutRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid input format: "+err.Error())
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	if err := c.AuthService.Logout(userID, request.RefreshToken); err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			return ErrorResponse(ctx, fiber.StatusUnauthorized, "unauthorized, "+err.Error())
 		}
+		logger.Error("Failed to sign out", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to sign out")
+	}
+
+	return ctx.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// LogoutAll revokes the refresh tokens of the user on every device.
+// @Description Revoke every refresh token and session of the user, e.g. after losing a device. Access tokens of every device are rejected from now on.
+// @Summary Sign out of all devices
+// @Tags Authentication
+// @Produce json
+// @Success 204 "Signed out"
+// @Failure 500 {object} base.ErrorResponse "Failed to sign out"
+// @Security ApiKeyAuth
+// @Router /auth/logout-all [post]
+func (c *AuthController) LogoutAll(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+
+	revoked, err := c.AuthService.LogoutAll(userID)
+	if err != nil {
+		logger.Error("Failed to sign out of all devices", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to sign out")
+	}
+	logger.Info("Signed out of all devices", zap.String("user_id", userID), zap.Int64("revoked", revoked))
+
+	return ctx.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// ListSessions returns the devices the user is signed in on.
+// @Description List the signed-in devices of the user, most recently seen first. The session of the access token is marked current.
+// @Summary List signed-in devices
+// @Tags User
+// @Produce json
+// @Success 200 {array} models.Session
+// @Failure 500 {object} base.ErrorResponse "Failed to get sessions"
+// @Security ApiKeyAuth
+// @Router /user/sessions [get]
+func (c *AuthController) ListSessions(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+	sessionID, _ := ctx.Locals("sessionID").(string)
+
+	sessions, err := c.AuthService.ListSessions(userID, sessionID)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get sessions")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(sessions)
+}
 
-		// Return status 200 and new tokens.
-		return ctx.JSON(fiber.Map{
-			"tokens": fiber.Map{
-				"access":  tokens.Access,
-				"refresh": tokens.Refresh,
-			},
-		})
+// RevokeSession signs a device of the user out.
+// @Description Sign a device out, e.g. a lost phone. Its refresh tokens are revoked and its access tokens are rejected immediately.
+// @Summary Sign out a device
+// @Tags User
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 204 "Signed out"
+// @Failure 404 {object} base.ErrorResponse "Session not found"
+// @Failure 500 {object} base.ErrorResponse "Failed to sign out"
+// @Security ApiKeyAuth
+// @Router /user/sessions/{id} [delete]
+func (c *AuthController) RevokeSession(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+	sessionID := ctx.Params("id")
+
+	if err := c.AuthService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Session not found")
+		}
+		logger.Error("Failed to sign out session", zap.String("user_id", userID), zap.String("session_id", sessionID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to sign out")
 	}
+	logger.Info("Signed out session", zap.String("user_id", userID), zap.String("session_id", sessionID))
 
-	// Return status 401 and unauthorized error message.
-	return ErrorResponse(ctx, fiber.StatusUnauthorized, "unauthorized, your refresh token is expired")
+	return ctx.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// truncate cuts a client supplied value to the number of characters of its column
+func truncate(value string, length int) string {
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
 }
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/utils"
+	"errors"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// ChallengeController handles step-up authentication HTTP requests
+type ChallengeController struct {
+	challengeService         services.ChallengeService
+	accountService           services.AccountService
+	scheduledTransferService services.ScheduledTransferService
+	pinLockoutService        services.PinLockoutService
+	auditService             services.AuditService
+}
+
+// NewChallengeController creates a new ChallengeController
+func NewChallengeController(
+	challengeService services.ChallengeService,
+	accountService services.AccountService,
+	scheduledTransferService services.ScheduledTransferService,
+	pinLockoutService services.PinLockoutService,
+	auditService services.AuditService,
+) *ChallengeController {
+	return &ChallengeController{
+		challengeService:         challengeService,
+		accountService:           accountService,
+		scheduledTransferService: scheduledTransferService,
+		pinLockoutService:        pinLockoutService,
+		auditService:             auditService,
+	}
+}
+
+// challengeResponse tells the client to confirm the operation held by the challenge
+func challengeResponse(ctx *fiber.Ctx, challenge *models.Challenge, message string) error {
+	return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message":      message,
+		"challenge_id": challenge.ChallengeID,
+		"expires_at":   challenge.ExpiresAt,
+	})
+}
+
+// ConfirmChallenge confirms a challenge with a second factor and runs the operation it holds
+//
+//	@Summary		Confirm challenge
+//	@Description	Confirm a challenge returned by a transfer, or a schedule creation or change, above the step-up threshold with the PIN or a code of the enabled authenticator app, then run the operation. A challenge expires after 5 minutes, can be confirmed once and fails after 3 wrong answers. Wrong PINs and authenticator codes both count towards the PIN lockout of the user, so new challenges do not give more guesses.
+//	@Tags			challenges
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id				path		string												true	"Challenge ID"
+//	@Param			confirmation	body		controllers.ConfirmChallenge.confirmChallengeRequest	true	"Second factor"
+//	@Param			Idempotency-Key	header		string												false	"Client generated key, retries with the same key replay the first response"
+//	@Success		200				{object}	map[string]interface{}								"Result of the transfer, or the schedule"
+//	@Failure		400				{object}	base.ErrorResponse									"Invalid method or no authenticator app enabled"
+//	@Failure		401				{object}	base.ErrorResponse									"Wrong PIN or authenticator code"
+//	@Failure		404				{object}	base.ErrorResponse									"Challenge not found"
+//	@Failure		409				{object}	base.ErrorResponse									"Challenge expired, failed or already confirmed"
+//	@Failure		423				{object}	base.ErrorResponse									"PIN is locked"
+//	@Failure		429				{object}	base.ErrorResponse									"Too many wrong answers, wait before trying again"
+//	@Router			/challenges/{id}/confirm [post]
+func (cc *ChallengeController) ConfirmChallenge(ctx *fiber.Ctx) error {
+	type confirmChallengeRequest struct {
+		Method string `json:"method" validate:"required,oneof=pin totp" example:"pin"`
+		Code   string `json:"code" validate:"required" example:"123456"` // the PIN or the authenticator code
+	}
+
+	userID := ctx.Locals("userID").(string)
+	challengeID := ctx.Params("id")
+
+	var request confirmChallengeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	// The attempts of a challenge are capped, but a new challenge starts over. Wrong PINs and authenticator codes
+	// are counted per user in the PIN lockout so that 6 digit codes can't be guessed across challenges, the attempt
+	// is counted before the code is verified.
+	attempt, err := cc.pinLockoutService.Reserve(userID, ctx.IP())
+	if err != nil {
+		return pinLockedResponse(ctx, attempt, err)
+	}
+
+	challenge, err := cc.challengeService.Confirm(userID, challengeID, request.Method, request.Code)
+	if err != nil {
+		// Only a wrong PIN or authenticator code stays counted
+		if !errors.Is(err, services.ErrIncorrectPin) && !errors.Is(err, services.ErrInvalidTOTP) {
+			releasePinAttempt(cc.pinLockoutService.Release, attempt)
+		}
+
+		switch {
+		case errors.Is(err, services.ErrIncorrectPin):
+			return ErrorResponse(ctx, fiber.StatusUnauthorized, "Invalid PIN")
+		case errors.Is(err, services.ErrInvalidTOTP):
+			return ErrorResponse(ctx, fiber.StatusUnauthorized, err.Error())
+		case errors.Is(err, services.ErrTOTPNotEnabled), errors.Is(err, services.ErrInvalidChallengeMethod):
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrChallengeNotFound):
+			return ErrorResponse(ctx, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrChallengeExpired), errors.Is(err, services.ErrChallengeUsed), errors.Is(err, services.ErrChallengeFailed):
+			return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+		}
+		logger.Error("Failed to confirm challenge", zap.String("challenge_id", challengeID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to confirm challenge")
+	}
+
+	releasePinAttempt(cc.pinLockoutService.RecordSuccess, attempt)
+
+	switch challenge.Action {
+	case models.ChallengeActionSchedule:
+		schedule, err := challenge.Schedule()
+		if err != nil {
+			return challengeDecodeError(ctx, challengeID, err)
+		}
+		return createSchedule(ctx, cc.scheduledTransferService, schedule)
+	case models.ChallengeActionScheduleUpdate:
+		update, err := challenge.ScheduleUpdate()
+		if err != nil {
+			return challengeDecodeError(ctx, challengeID, err)
+		}
+		return updateSchedule(ctx, cc.scheduledTransferService, update)
+	}
+
+	transfer, err := challenge.Transfer()
+	if err != nil {
+		return challengeDecodeError(ctx, challengeID, err)
+	}
+
+	return executeTransfer(ctx, cc.accountService, cc.auditService, transfer)
+}
+
+func challengeDecodeError(ctx *fiber.Ctx, challengeID string, err error) error {
+	logger.Error("Failed to decode challenge", zap.String("challenge_id", challengeID), zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to confirm challenge")
+}
@@ -1,32 +1,66 @@
 package controllers
 
 import (
+	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/services"
 	"backend-developer-assignment/pkg/middleware"
+	"database/sql"
 	"strconv"
 
 	fiber "github.com/gofiber/fiber/v2"
 )
 
 type Controller struct {
-	AuthController        AuthController
-	UserController        UserController
-	TransactionController TransactionController
-	DebitCardController   DebitCardController
-	AccountController     AccountController
-	BannerController      BannerController
+	AuthController              AuthController
+	UserController              UserController
+	TransactionController       TransactionController
+	DebitCardController         DebitCardController
+	AccountController           AccountController
+	BannerController            BannerController
+	ScheduledTransferController ScheduledTransferController
+	TransferLimitController     TransferLimitController
+	FXController                FXController
+	HoldController              HoldController
+	ChallengeController         ChallengeController
+	WellKnownController         WellKnownController
+	AdminController             AdminController
+	WebhookController           WebhookController
+	StreamController            StreamController
+	StatementController         StatementController
+
+	// Policy resolves resource owners for the Owned middleware on routes addressing a single resource
+	Policy Policy
+
+	// IdempotencyStore backs the Idempotency middleware on money movement routes
+	IdempotencyStore middleware.IdempotencyStore
+
+	// SessionRevocations rejects access tokens of signed-out sessions in the AuthProtected middleware
+	SessionRevocations middleware.SessionRevocationList
 }
 
 var logger = middleware.GetLogger()
 
 func InitController(service *services.Service) *Controller {
 	return &Controller{
-		AuthController:        *NewAuthController(service.UserService),
-		UserController:        *NewUserController(service.UserService),
-		TransactionController: *NewTransactionController(service.TransactionService),
-		DebitCardController:   *NewDebitCardController(service.DebitCardService),
-		AccountController:     *NewAccountController(service.AccountService),
-		BannerController:      *NewBannerController(service.BannerService),
+		AuthController:              *NewAuthController(service.UserService, service.AuthService, service.PinLockoutService, service.PinService, service.AuditService),
+		UserController:              *NewUserController(service.UserService, service.PinLockoutService, service.PinService, service.TOTPService),
+		TransactionController:       *NewTransactionController(service.TransactionService, service.AuditService),
+		DebitCardController:         *NewDebitCardController(service.DebitCardService, service.AuditService),
+		AccountController:           *NewAccountController(service.AccountService, service.ChallengeService, service.AuditService),
+		BannerController:            *NewBannerController(service.BannerService),
+		ScheduledTransferController: *NewScheduledTransferController(service.AccountService, service.ScheduledTransferService, service.ChallengeService),
+		TransferLimitController:     *NewTransferLimitController(service.AccountService, service.TransferLimitService),
+		FXController:                *NewFXController(service.FXService),
+		HoldController:              *NewHoldController(service.AccountService, service.AuditService),
+		ChallengeController:         *NewChallengeController(service.ChallengeService, service.AccountService, service.ScheduledTransferService, service.PinLockoutService, service.AuditService),
+		WellKnownController:         *NewWellKnownController(),
+		AdminController:             *NewAdminController(service.UserService, service.AccountService, service.DebitCardService, service.BannerService, service.AuditService),
+		WebhookController:           *NewWebhookController(service.WebhookService, service.AuditService),
+		StreamController:            *NewStreamController(service.StreamService),
+		StatementController:         *NewStatementController(service.StatementService, service.AuditService),
+		Policy:                      *NewPolicy(service.AccountService, service.DebitCardService, service.BannerService, service.ChallengeService, service.WebhookService),
+		IdempotencyStore:            service.IdempotencyService,
+		SessionRevocations:          service.AuthService,
 	}
 }
 
@@ -36,3 +70,34 @@ func ErrorResponse(ctx *fiber.Ctx, statusCode int, message string) error {
 		"message": message,
 	})
 }
+
+// getOwnedAccount loads the account in the path, accounts of other users are reported as not found
+func getOwnedAccount(ctx *fiber.Ctx, accountService services.AccountService) (*models.Account, error) {
+	userID := ctx.Locals("userID").(string)
+
+	account, err := accountService.GetAccountByID(ctx.Params("id"))
+	if err != nil {
+		return nil, err
+	}
+	if account.UserID != userID {
+		return nil, sql.ErrNoRows
+	}
+
+	return account, nil
+}
+
+// auditEntry starts an audit log entry of the signed-in user acting on a resource in this request
+func auditEntry(ctx *fiber.Ctx, action, resourceType, resourceID string) *models.AuditLog {
+	actorID, _ := ctx.Locals("userID").(string)
+	requestID, _ := ctx.Locals("requestid").(string)
+
+	return &models.AuditLog{
+		Action:       action,
+		ActorID:      truncate(actorID, 50),
+		ActorType:    models.AuditActorUser,
+		ResourceType: resourceType,
+		ResourceID:   truncate(resourceID, 50),
+		IPAddress:    ctx.IP(),
+		RequestID:    truncate(requestID, 64),
+	}
+}
@@ -13,12 +13,14 @@ import (
 // DebitCardController handles HTTP requests related to debit cards
 type DebitCardController struct {
 	debitCardService services.DebitCardService
+	auditService     services.AuditService
 }
 
 // NewDebitCardController creates a new instance of DebitCardController
-func NewDebitCardController(service services.DebitCardService) *DebitCardController {
+func NewDebitCardController(service services.DebitCardService, auditService services.AuditService) *DebitCardController {
 	return &DebitCardController{
 		debitCardService: service,
+		auditService:     auditService,
 	}
 }
 
@@ -211,7 +213,7 @@ func (c *DebitCardController) DeleteDebitCard(ctx *fiber.Ctx) error {
 	}
 
 	// Check if the card exists
-	_, err := c.debitCardService.GetCardByID(cardID)
+	card, err := c.debitCardService.GetCardByID(cardID)
 	if err != nil {
 		return ErrorResponse(ctx, fiber.StatusNotFound, "Debit card not found")
 	}
@@ -221,5 +223,8 @@ func (c *DebitCardController) DeleteDebitCard(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to delete card: "+err.Error())
 	}
 
+	c.auditService.Record(auditEntry(ctx, models.AuditCardStatusChange, "debit_card", cardID), card,
+		fiber.Map{"status": models.CardStatusInactive})
+
 	return ctx.Status(fiber.StatusNoContent).Send(nil)
 }
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/utils"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// FXController handles currency conversion HTTP requests
+type FXController struct {
+	fxService services.FXService
+}
+
+// NewFXController creates a new FXController
+func NewFXController(fxService services.FXService) *FXController {
+	return &FXController{
+		fxService: fxService,
+	}
+}
+
+// CreateQuote locks an exchange rate for a cross-currency transfer
+//
+//	@Summary		Create fx quote
+//	@Description	Lock the rate for converting an amount into another currency. Pass the quote_id to a transfer between accounts of those currencies before expires_at, a quote can be used once.
+//	@Tags			fx
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			quote	body		controllers.CreateQuote.createQuoteRequest	true	"Conversion to quote"
+//	@Success		201		{object}	models.FXQuote
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid currency pair or amount"
+//	@Failure		404		{object}	base.ErrorResponse	"No rate for the currency pair"
+//	@Router			/fx/quotes [post]
+func (fc *FXController) CreateQuote(ctx *fiber.Ctx) error {
+	type createQuoteRequest struct {
+		FromCurrency string      `json:"from_currency" validate:"required,len=3" example:"THB"`
+		ToCurrency   string      `json:"to_currency" validate:"required,len=3" example:"USD"`
+		Amount       json.Number `json:"amount" validate:"required" swaggertype:"string" example:"1000.00"` // in from_currency
+	}
+
+	userID := ctx.Locals("userID").(string)
+
+	var request createQuoteRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	amount, err := parseAmount(request.Amount, request.FromCurrency)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	quote, err := fc.fxService.CreateQuote(userID, strings.ToUpper(request.ToCurrency), amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRateUnavailable):
+			return ErrorResponse(ctx, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrInvalidCurrencyPair), errors.Is(err, services.ErrInvalidAmount):
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		logger.Error("Failed to create fx quote", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to create fx quote")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(quote)
+}
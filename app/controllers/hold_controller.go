@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"encoding/json"
+	"errors"
+	"time"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// HoldController handles account hold HTTP requests
+type HoldController struct {
+	accountService services.AccountService
+	auditService   services.AuditService
+}
+
+// NewHoldController creates a new HoldController
+func NewHoldController(accountService services.AccountService, auditService services.AuditService) *HoldController {
+	return &HoldController{
+		accountService: accountService,
+		auditService:   auditService,
+	}
+}
+
+// ListHolds retrieves the holds placed on an account
+//
+//	@Summary		List account holds
+//	@Description	Get the holds placed on an account, newest first. Active holds are subtracted from the available balance.
+//	@Tags			holds
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"Account ID"
+//	@Success		200	{object}	[]models.AccountHold
+//	@Failure		404	{object}	base.ErrorResponse	"Account not found"
+//	@Router			/accounts/{id}/holds [get]
+func (hc *HoldController) ListHolds(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, hc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	holds, err := hc.accountService.GetHolds(account.AccountID)
+	if err != nil {
+		logger.Error("Failed to get holds", zap.String("account_id", account.AccountID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve holds")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(holds)
+}
+
+// PlaceHold reserves funds on an account
+//
+//	@Summary		Place hold
+//	@Description	Reserve an amount of the available balance, e.g. for a card authorization. The amount is in the account's currency, expires_at defaults to 7 days and can be at most 30 days away.
+//	@Tags			holds
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string									true	"Account ID"
+//	@Param			hold	body		controllers.PlaceHold.placeHoldRequest	true	"Hold details"
+//	@Success		201		{object}	models.AccountHold
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid hold or insufficient funds"
+//	@Failure		404		{object}	base.ErrorResponse	"Account not found"
+//	@Router			/accounts/{id}/holds [post]
+func (hc *HoldController) PlaceHold(ctx *fiber.Ctx) error {
+	type placeHoldRequest struct {
+		Amount      json.Number `json:"amount" validate:"required" swaggertype:"string" example:"250.00"`
+		Description string      `json:"description" validate:"max=255"`
+		ExpiresAt   *time.Time  `json:"expires_at"`
+	}
+
+	account, err := getOwnedAccount(ctx, hc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	var request placeHoldRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	amount, err := parseAmount(request.Amount, account.Currency)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	var expiresAt time.Time
+	if request.ExpiresAt != nil {
+		expiresAt = *request.ExpiresAt
+	}
+
+	hold, err := hc.accountService.PlaceHold(account.AccountID, amount, request.Description, expiresAt)
+	if err != nil {
+		return holdErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(hold)
+}
+
+// CaptureHold debits held funds from an account
+//
+//	@Summary		Capture hold
+//	@Description	Debit a hold from the account. Omit amount to capture the full hold, a smaller amount captures part of it and releases the rest. Like a withdrawal, the capture counts against the transfer limits and is refused on a frozen account.
+//	@Tags			holds
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string										true	"Account ID"
+//	@Param			holdId	path		string										true	"Hold ID"
+//	@Param			capture	body		controllers.CaptureHold.captureHoldRequest	false	"Amount to capture"
+//	@Success		200		{object}	models.AccountHold
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid amount or transfer limit exceeded"
+//	@Failure		403		{object}	base.ErrorResponse	"Account is frozen"
+//	@Failure		404		{object}	base.ErrorResponse	"Account or hold not found"
+//	@Failure		409		{object}	base.ErrorResponse	"Hold no longer active"
+//	@Router			/accounts/{id}/holds/{holdId}/capture [post]
+func (hc *HoldController) CaptureHold(ctx *fiber.Ctx) error {
+	type captureHoldRequest struct {
+		Amount json.Number `json:"amount" swaggertype:"string" example:"200.00"`
+	}
+
+	account, err := getOwnedAccount(ctx, hc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	var request captureHoldRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&request); err != nil {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	var amount *types.Money
+	if request.Amount != "" {
+		parsed, err := parseAmount(request.Amount, account.Currency)
+		if err != nil {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		amount = &parsed
+	}
+
+	holdID := ctx.Params("holdId")
+	hold, err := hc.accountService.CaptureHold(account.AccountID, holdID, amount)
+	entry := auditEntry(ctx, models.AuditHoldCapture, "hold", holdID)
+	if err != nil {
+		entry.Outcome = models.AuditFailure
+		hc.auditService.Record(entry, nil, fiber.Map{"account_id": account.AccountID, "amount": amount, "error": err.Error()})
+		return holdErrorResponse(ctx, err)
+	}
+	hc.auditService.Record(entry, nil, hold)
+
+	return ctx.Status(fiber.StatusOK).JSON(hold)
+}
+
+// ReleaseHold cancels a hold
+//
+//	@Summary		Release hold
+//	@Description	Cancel an active hold, its funds become available again
+//	@Tags			holds
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string	true	"Account ID"
+//	@Param			holdId	path		string	true	"Hold ID"
+//	@Success		200		{object}	models.AccountHold
+//	@Failure		404		{object}	base.ErrorResponse	"Account or hold not found"
+//	@Failure		409		{object}	base.ErrorResponse	"Hold no longer active"
+//	@Router			/accounts/{id}/holds/{holdId}/release [post]
+func (hc *HoldController) ReleaseHold(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, hc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	holdID := ctx.Params("holdId")
+	hold, err := hc.accountService.ReleaseHold(account.AccountID, holdID)
+	entry := auditEntry(ctx, models.AuditHoldRelease, "hold", holdID)
+	if err != nil {
+		entry.Outcome = models.AuditFailure
+		hc.auditService.Record(entry, nil, fiber.Map{"account_id": account.AccountID, "error": err.Error()})
+		return holdErrorResponse(ctx, err)
+	}
+	hc.auditService.Record(entry, nil, hold)
+
+	return ctx.Status(fiber.StatusOK).JSON(hold)
+}
+
+func holdErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrHoldNotFound):
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Hold not found")
+	case errors.Is(err, services.ErrHoldNotActive), errors.Is(err, services.ErrHoldExpired):
+		return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrAccountFrozen):
+		return ErrorResponse(ctx, fiber.StatusForbidden, "Account is frozen")
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Insufficient funds")
+	case errors.Is(err, services.ErrInvalidHold), errors.Is(err, services.ErrInvalidAmount),
+		errors.Is(err, services.ErrCurrencyMismatch), errors.Is(err, services.ErrCaptureExceedsHold),
+		errors.Is(err, services.ErrTransferLimitExceeded):
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	logger.Error("Hold request failed", zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process hold")
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/services"
+	"database/sql"
+	"errors"
+)
+
+// Policy resolves the owners of the resources addressed by route parameters for the middleware.Owned guard
+type Policy struct {
+	accountService   services.AccountService
+	debitCardService services.DebitCardService
+	bannerService    services.BannerService
+	challengeService services.ChallengeService
+	webhookService   services.WebhookService
+}
+
+// NewPolicy creates a new Policy
+func NewPolicy(
+	accountService services.AccountService,
+	debitCardService services.DebitCardService,
+	bannerService services.BannerService,
+	challengeService services.ChallengeService,
+	webhookService services.WebhookService,
+) *Policy {
+	return &Policy{
+		accountService:   accountService,
+		debitCardService: debitCardService,
+		bannerService:    bannerService,
+		challengeService: challengeService,
+		webhookService:   webhookService,
+	}
+}
+
+// AccountOwner returns the user owning an account
+func (p *Policy) AccountOwner(accountID string) (string, error) {
+	account, err := p.accountService.GetAccountByID(accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return account.UserID, nil
+}
+
+// DebitCardOwner returns the user owning a debit card
+func (p *Policy) DebitCardOwner(cardID string) (string, error) {
+	card, err := p.debitCardService.GetCardByID(cardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return card.UserID, nil
+}
+
+// BannerOwner returns the user a banner is shown to
+func (p *Policy) BannerOwner(bannerID string) (string, error) {
+	banner, err := p.bannerService.GetBannerByID(bannerID)
+	if err != nil || banner == nil {
+		return "", err
+	}
+	return banner.UserID, nil
+}
+
+// ChallengeOwner returns the user a challenge was issued to
+func (p *Policy) ChallengeOwner(challengeID string) (string, error) {
+	challenge, err := p.challengeService.GetChallengeByID(challengeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return challenge.UserID, nil
+}
+
+// WebhookOwner returns the user who registered a webhook endpoint
+func (p *Policy) WebhookOwner(endpointID string) (string, error) {
+	endpoint, err := p.webhookService.GetEndpointByID(endpointID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return endpoint.UserID, nil
+}
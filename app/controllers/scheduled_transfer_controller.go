@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// ScheduledTransferController handles standing order HTTP requests
+type ScheduledTransferController struct {
+	accountService           services.AccountService
+	scheduledTransferService services.ScheduledTransferService
+	challengeService         services.ChallengeService
+}
+
+// NewScheduledTransferController creates a new ScheduledTransferController
+func NewScheduledTransferController(
+	accountService services.AccountService,
+	scheduledTransferService services.ScheduledTransferService,
+	challengeService services.ChallengeService,
+) *ScheduledTransferController {
+	return &ScheduledTransferController{
+		accountService:           accountService,
+		scheduledTransferService: scheduledTransferService,
+		challengeService:         challengeService,
+	}
+}
+
+// ListSchedules retrieves the scheduled transfers paid from an account
+//
+//	@Summary		List scheduled transfers
+//	@Description	Get all scheduled transfers paid from an account
+//	@Tags			schedules
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"Account ID"
+//	@Success		200	{object}	[]models.ScheduledTransfer
+//	@Failure		404	{object}	base.ErrorResponse	"Account not found"
+//	@Router			/accounts/{id}/schedules [get]
+func (sc *ScheduledTransferController) ListSchedules(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, sc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	schedules, err := sc.scheduledTransferService.GetSchedulesByAccountID(account.AccountID)
+	if err != nil {
+		logger.Error("Failed to get scheduled transfers", zap.String("account_id", account.AccountID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve scheduled transfers")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(schedules)
+}
+
+// GetSchedule retrieves a single scheduled transfer
+//
+//	@Summary		Get scheduled transfer
+//	@Description	Get a scheduled transfer paid from an account
+//	@Tags			schedules
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Account ID"
+//	@Param			scheduleId	path		string	true	"Schedule ID"
+//	@Success		200			{object}	models.ScheduledTransfer
+//	@Failure		404			{object}	base.ErrorResponse	"Account or schedule not found"
+//	@Router			/accounts/{id}/schedules/{scheduleId} [get]
+func (sc *ScheduledTransferController) GetSchedule(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, sc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	schedule, err := sc.scheduledTransferService.GetSchedule(account.AccountID, ctx.Params("scheduleId"))
+	if err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// CreateSchedule creates a standing order from an account
+//
+//	@Summary		Create scheduled transfer
+//	@Description	Create a one-off or recurring transfer from an account. The amount is in the account's currency. A schedule paying more than the step-up threshold is created once the challenge returned with 202 is confirmed.
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Account ID"
+//	@Param			schedule	body		controllers.CreateSchedule.createScheduleRequest	true	"Schedule details"
+//	@Success		201			{object}	models.ScheduledTransfer
+//	@Success		202			{object}	map[string]interface{}	"Challenge to confirm with POST /challenges/{id}/confirm"
+//	@Failure		400			{object}	base.ErrorResponse	"Invalid schedule"
+//	@Failure		404			{object}	base.ErrorResponse	"Account not found"
+//	@Router			/accounts/{id}/schedules [post]
+func (sc *ScheduledTransferController) CreateSchedule(ctx *fiber.Ctx) error {
+	type createScheduleRequest struct {
+		ToAccountID             string      `json:"to_account_id" validate:"required"`
+		Amount                  json.Number `json:"amount" validate:"required" swaggertype:"string" example:"1500.00"`
+		Description             string      `json:"description" validate:"max=255"`
+		Frequency               string      `json:"frequency" validate:"required,oneof=once daily weekly monthly"`
+		StartAt                 *time.Time  `json:"start_at"` // defaults to now
+		EndAt                   *time.Time  `json:"end_at"`
+		InsufficientFundsPolicy string      `json:"insufficient_funds_policy" validate:"omitempty,oneof=skip retry"`
+	}
+
+	account, err := getOwnedAccount(ctx, sc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	var request createScheduleRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	amount, err := parseAmount(request.Amount, account.Currency)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	schedule := &models.ScheduledTransfer{
+		UserID:                  account.UserID,
+		FromAccountID:           account.AccountID,
+		ToAccountID:             request.ToAccountID,
+		Amount:                  amount,
+		Description:             request.Description,
+		Frequency:               models.ScheduleFrequency(request.Frequency),
+		EndAt:                   request.EndAt,
+		InsufficientFundsPolicy: models.InsufficientFundsPolicy(request.InsufficientFundsPolicy),
+	}
+	if request.StartAt != nil {
+		schedule.StartAt = *request.StartAt
+	}
+
+	// Every occurrence pays the amount, so a large schedule needs the same second factor as a large transfer
+	if sc.challengeService.RequiresStepUp(amount) {
+		challenge, err := sc.challengeService.CreateScheduleChallenge(account.UserID, schedule)
+		if err != nil {
+			return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process scheduled transfer")
+		}
+		return challengeResponse(ctx, challenge, "Confirm the scheduled transfer with your PIN or authenticator code")
+	}
+
+	return createSchedule(ctx, sc.scheduledTransferService, schedule)
+}
+
+// createSchedule stores a new schedule and responds with it
+func createSchedule(ctx *fiber.Ctx, scheduledTransferService services.ScheduledTransferService, schedule *models.ScheduledTransfer) error {
+	if err := scheduledTransferService.CreateSchedule(schedule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Destination account not found")
+		}
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(schedule)
+}
+
+// UpdateSchedule changes, pauses or resumes a scheduled transfer
+//
+//	@Summary		Update scheduled transfer
+//	@Description	Change a scheduled transfer, set status to paused or active to pause or resume it. Occurrences missed while paused are not executed. An amount above the step-up threshold is applied once the challenge returned with 202 is confirmed.
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Account ID"
+//	@Param			scheduleId	path		string	true	"Schedule ID"
+//	@Param			schedule	body		controllers.UpdateSchedule.updateScheduleRequest	true	"Fields to change"
+//	@Success		200			{object}	models.ScheduledTransfer
+//	@Success		202			{object}	map[string]interface{}	"Challenge to confirm with POST /challenges/{id}/confirm"
+//	@Failure		400			{object}	base.ErrorResponse	"Invalid schedule"
+//	@Failure		404			{object}	base.ErrorResponse	"Account or schedule not found"
+//	@Failure		409			{object}	base.ErrorResponse	"Schedule already completed"
+//	@Router			/accounts/{id}/schedules/{scheduleId} [patch]
+func (sc *ScheduledTransferController) UpdateSchedule(ctx *fiber.Ctx) error {
+	type updateScheduleRequest struct {
+		Amount                  json.Number `json:"amount" swaggertype:"string" example:"1500.00"`
+		Description             *string     `json:"description" validate:"omitempty,max=255"`
+		Frequency               string      `json:"frequency" validate:"omitempty,oneof=once daily weekly monthly"`
+		EndAt                   *time.Time  `json:"end_at"`
+		Status                  string      `json:"status" validate:"omitempty,oneof=active paused"`
+		InsufficientFundsPolicy string      `json:"insufficient_funds_policy" validate:"omitempty,oneof=skip retry"`
+	}
+
+	account, err := getOwnedAccount(ctx, sc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	var request updateScheduleRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	update := &models.ScheduledTransferUpdate{
+		Description: request.Description,
+		EndAt:       request.EndAt,
+	}
+	if request.Amount != "" {
+		amount, err := parseAmount(request.Amount, account.Currency)
+		if err != nil {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		update.Amount = &amount
+	}
+	if request.Frequency != "" {
+		frequency := models.ScheduleFrequency(request.Frequency)
+		update.Frequency = &frequency
+	}
+	if request.Status != "" {
+		status := models.ScheduleStatus(request.Status)
+		update.Status = &status
+	}
+	if request.InsufficientFundsPolicy != "" {
+		policy := models.InsufficientFundsPolicy(request.InsufficientFundsPolicy)
+		update.InsufficientFundsPolicy = &policy
+	}
+
+	pending := &models.PendingScheduleUpdate{
+		AccountID:  account.AccountID,
+		ScheduleID: ctx.Params("scheduleId"),
+		Update:     *update,
+	}
+	if update.Amount != nil && sc.challengeService.RequiresStepUp(*update.Amount) {
+		challenge, err := sc.challengeService.CreateScheduleUpdateChallenge(account.UserID, pending)
+		if err != nil {
+			return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process scheduled transfer")
+		}
+		return challengeResponse(ctx, challenge, "Confirm the scheduled transfer change with your PIN or authenticator code")
+	}
+
+	return updateSchedule(ctx, sc.scheduledTransferService, pending)
+}
+
+// updateSchedule applies an update to a schedule and responds with the schedule
+func updateSchedule(ctx *fiber.Ctx, scheduledTransferService services.ScheduledTransferService, pending *models.PendingScheduleUpdate) error {
+	schedule, err := scheduledTransferService.UpdateSchedule(pending.AccountID, pending.ScheduleID, &pending.Update)
+	if err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// CancelSchedule cancels a scheduled transfer
+//
+//	@Summary		Cancel scheduled transfer
+//	@Description	Cancel a scheduled transfer, its execution history is kept
+//	@Tags			schedules
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Account ID"
+//	@Param			scheduleId	path		string	true	"Schedule ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		404			{object}	base.ErrorResponse	"Account or schedule not found"
+//	@Router			/accounts/{id}/schedules/{scheduleId} [delete]
+func (sc *ScheduledTransferController) CancelSchedule(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, sc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	if err := sc.scheduledTransferService.CancelSchedule(account.AccountID, ctx.Params("scheduleId")); err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Scheduled transfer cancelled successfully"})
+}
+
+// ListExecutions retrieves the execution history of a scheduled transfer
+//
+//	@Summary		List scheduled transfer executions
+//	@Description	Get the most recent executions of a scheduled transfer, including skipped and retried attempts
+//	@Tags			schedules
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Account ID"
+//	@Param			scheduleId	path		string	true	"Schedule ID"
+//	@Success		200			{object}	[]models.ScheduledTransferExecution
+//	@Failure		404			{object}	base.ErrorResponse	"Account or schedule not found"
+//	@Router			/accounts/{id}/schedules/{scheduleId}/executions [get]
+func (sc *ScheduledTransferController) ListExecutions(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, sc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	executions, err := sc.scheduledTransferService.GetExecutions(account.AccountID, ctx.Params("scheduleId"))
+	if err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(executions)
+}
+
+func scheduleErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrScheduleNotFound):
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Scheduled transfer not found")
+	case errors.Is(err, services.ErrScheduleNotEditable):
+		return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidSchedule), errors.Is(err, services.ErrInvalidAmount), errors.Is(err, services.ErrCurrencyMismatch):
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	logger.Error("Scheduled transfer request failed", zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to process scheduled transfer")
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/utils"
+	"bufio"
+	"database/sql"
+	"errors"
+	"time"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// statementContentTypes maps the statement formats to the content type of the response
+var statementContentTypes = map[models.StatementFormat]string{
+	models.StatementCSV:  "text/csv; charset=utf-8",
+	models.StatementJSON: fiber.MIMEApplicationJSONCharsetUTF8,
+	models.StatementPDF:  "application/pdf",
+}
+
+// StatementController serves account statements to their owners and to auditors
+type StatementController struct {
+	statementService services.StatementService
+	auditService     services.AuditService
+}
+
+// NewStatementController creates a new StatementController
+func NewStatementController(statementService services.StatementService, auditService services.AuditService) *StatementController {
+	return &StatementController{
+		statementService: statementService,
+		auditService:     auditService,
+	}
+}
+
+// GetStatement returns the statement of an account of the authenticated user
+//
+//	@Summary		Get account statement
+//	@Description	Get the opening balance, every transaction with the running balance and the closing balance of an account from the day from to the day to, both included. Days run in Asia/Bangkok time. The statement is streamed as CSV, JSON or PDF, a failure while streaming ends the response early.
+//	@Tags			accounts
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/pdf
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string	true	"Account ID"
+//	@Param			from	query		string	true	"First day, e.g. 2026-09-01"
+//	@Param			to		query		string	true	"Last day, e.g. 2026-09-30"
+//	@Param			format	query		string	false	"csv, json (default) or pdf"
+//	@Success		200		{object}	object{account_id=string,account_number=string,currency=string,from=string,to=string,opening_balance=types.Money,transactions=[]models.StatementLine,closing_balance=types.Money}
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid period or format"
+//	@Failure		404		{object}	base.ErrorResponse	"Account not found"
+//	@Router			/accounts/{id}/statements [get]
+func (sc *StatementController) GetStatement(ctx *fiber.Ctx) error {
+	return sc.streamStatement(ctx)
+}
+
+// GetAnyStatement returns the statement of any account for auditors, the access is audited
+//
+//	@Summary		Get any account statement
+//	@Description	Get the statement of any account, see GET /accounts/{id}/statements. Requires the audit:read permission.
+//	@Tags			admin
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/pdf
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string	true	"Account ID"
+//	@Param			from	query		string	true	"First day, e.g. 2026-09-01"
+//	@Param			to		query		string	true	"Last day, e.g. 2026-09-30"
+//	@Param			format	query		string	false	"csv, json (default) or pdf"
+//	@Success		200		{object}	object{account_id=string,account_number=string,currency=string,from=string,to=string,opening_balance=types.Money,transactions=[]models.StatementLine,closing_balance=types.Money}
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid period or format"
+//	@Failure		403		{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404		{object}	base.ErrorResponse	"Account not found"
+//	@Router			/admin/accounts/{id}/statements [get]
+func (sc *StatementController) GetAnyStatement(ctx *fiber.Ctx) error {
+	if err := sc.streamStatement(ctx); err != nil {
+		return err
+	}
+	if ctx.Response().StatusCode() == fiber.StatusOK {
+		sc.auditService.Record(staffAuditEntry(ctx, models.AuditStatementRead, "account", ctx.Params("id")), nil,
+			fiber.Map{"from": ctx.Query("from"), "to": ctx.Query("to"), "format": ctx.Query("format", string(models.StatementJSON))})
+	}
+	return nil
+}
+
+// streamStatement opens the statement of the account in the path and streams it in the requested format
+func (sc *StatementController) streamStatement(ctx *fiber.Ctx) error {
+	type statementQuery struct {
+		From   string `query:"from" validate:"required,datetime=2006-01-02"`
+		To     string `query:"to" validate:"required,datetime=2006-01-02"`
+		Format string `query:"format" validate:"omitempty,oneof=csv json pdf"`
+	}
+
+	accountID := ctx.Params("id")
+
+	var query statementQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid query")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(query); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	format := models.StatementFormat(query.Format)
+	if format == "" {
+		format = models.StatementJSON
+	}
+	from, _ := time.ParseInLocation(time.DateOnly, query.From, configs.STATEMENT_LOCATION)
+	to, _ := time.ParseInLocation(time.DateOnly, query.To, configs.STATEMENT_LOCATION)
+
+	statement, err := sc.statementService.OpenStatement(accountID, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrStatementPeriod):
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+		default:
+			return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to get statement")
+		}
+	}
+
+	if format != models.StatementJSON {
+		ctx.Attachment("statement-" + statement.AccountNumber + "-" + query.From + "-" + query.To + "." + string(format))
+	}
+	ctx.Set(fiber.HeaderContentType, statementContentTypes[format])
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := sc.statementService.WriteStatement(w, statement, format); err != nil {
+			// The status is already sent, the client is left with a truncated statement
+			logger.Error("Failed to write statement", zap.String("account_id", accountID), zap.Error(err))
+			return
+		}
+		w.Flush()
+	})
+	return nil
+}
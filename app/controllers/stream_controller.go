@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/configs"
+	"bufio"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// streamRetry is how long an SSE client waits before reconnecting, in milliseconds
+const streamRetry = 3000
+
+// StreamController pushes balance updates and new transactions of the signed-in user over SSE or WebSocket
+type StreamController struct {
+	streamService services.StreamService
+}
+
+// NewStreamController creates a new StreamController
+func NewStreamController(streamService services.StreamService) *StreamController {
+	return &StreamController{
+		streamService: streamService,
+	}
+}
+
+// Stream pushes the messages of the authenticated user
+//
+//	@Summary		Stream balances and transactions
+//	@Description	Push balance updates and new transactions of the user's accounts as they are committed, as Server-Sent Events or, on a WebSocket upgrade, as JSON text messages. Messages are balance, transaction or reset: a reset means messages since the last event id are no longer kept and the accounts are to be reloaded. To resume after a disconnection send the id of the last message received in the Last-Event-ID header or the last_event_id query parameter. Transactions may be pushed twice, deduplicate them by transaction_id.
+//	@Tags			stream
+//	@Produce		text/event-stream
+//	@Security		ApiKeyAuth
+//	@Param			Last-Event-ID	header		string	false	"ID of the last message received"
+//	@Param			last_event_id	query		string	false	"ID of the last message received, for clients that cannot set headers"
+//	@Success		200				{object}	models.StreamMessage
+//	@Failure		503				{object}	base.ErrorResponse	"Stream is unavailable"
+//	@Router			/stream [get]
+func (sc *StreamController) Stream(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+	lastEventID := ctx.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+	// The stream outlives the request buffers the value points into
+	lastEventID = strings.Clone(lastEventID)
+
+	messages, closeSubscription, err := sc.streamService.Subscribe(ctx.Context(), userID, lastEventID)
+	if err != nil {
+		logger.Error("Failed to subscribe to the stream", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusServiceUnavailable, "Stream is unavailable")
+	}
+
+	if websocket.IsWebSocketUpgrade(ctx) {
+		err := websocket.New(func(conn *websocket.Conn) {
+			defer closeSubscription()
+			streamWebSocket(conn, messages, closeSubscription)
+		})(ctx)
+		if err != nil {
+			closeSubscription()
+		}
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no") // keep proxies from buffering the stream
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer closeSubscription()
+		streamEvents(w, messages)
+	})
+	return nil
+}
+
+// streamEvents writes messages as Server-Sent Events until the messages end or the client goes away, which the
+// next write after a heartbeat at the latest notices
+func streamEvents(w *bufio.Writer, messages <-chan *models.StreamMessage) {
+	heartbeat := time.NewTicker(configs.STREAM_HEARTBEAT_INTERVAL)
+	defer heartbeat.Stop()
+
+	w.WriteString("retry: " + strconv.Itoa(streamRetry) + "\n\n")
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			if message.ID != "" {
+				w.WriteString("id: " + message.ID + "\n")
+			}
+			w.WriteString("event: " + string(message.Event) + "\n")
+			w.WriteString("data: ")
+			w.Write(message.Data)
+			w.WriteString("\n\n")
+		case <-heartbeat.C:
+			w.WriteString(": heartbeat\n\n")
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// streamWebSocket writes messages as JSON text messages until the messages end or the client goes away
+func streamWebSocket(conn *websocket.Conn, messages <-chan *models.StreamMessage, closeSubscription func()) {
+	// Clients send nothing, reading only notices a close or a broken connection
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				closeSubscription()
+				return
+			}
+		}
+	}()
+	defer func() {
+		conn.Close()
+		<-readerDone
+	}()
+
+	heartbeat := time.NewTicker(configs.STREAM_HEARTBEAT_INTERVAL)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				// The client fell behind, the server is shutting down or the client went away: a client still
+				// there reconnects with its last event id
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, ""), time.Now().Add(time.Second))
+				return
+			}
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(configs.STREAM_HEARTBEAT_INTERVAL)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -3,7 +3,12 @@ package controllers
 import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"encoding/json"
+	"errors"
 	"strconv"
+	"time"
 
 	fiber "github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -12,24 +17,36 @@ import (
 // TransactionController holds the services related to transactions.
 type TransactionController struct {
 	TransactionService services.TransactionService
+	AuditService       services.AuditService
 }
 
 // NewTransactionController creates a new TransactionController.
-func NewTransactionController(transactionService services.TransactionService) *TransactionController {
+func NewTransactionController(transactionService services.TransactionService, auditService services.AuditService) *TransactionController {
 	return &TransactionController{
 		TransactionService: transactionService,
+		AuditService:       auditService,
 	}
 }
 
-// ListTransactions retrieves all transactions for a user.
-// @Description Retrieves all transactions for a user.
+// ListTransactions retrieves the transactions of a user, a page number at a time or filtered a cursor at a time.
+// @Description Without filters, returns a page of 10 transactions newest first with the total count. Any of the filters, sort, limit or cursor switches to a filtered listing instead: amounts are compared as decimals whatever the currency, from is inclusive and to exclusive (RFC 3339), q matches part of the name. Pass next_cursor of a page as cursor, with the same filters and sort, to get the next one, it is absent on the last page. page cannot be combined with the other parameters.
 // @Summary List transactions
 // @Tags Transactions
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Param page query int false "Page number"
-// @Success 200 {object} object{transactions=[]models.Transaction,total=int} "List of transactions"
+// @Param page query int false "Page number, without filters"
+// @Param account_id query string false "Account ID"
+// @Param type query string false "deposit, withdrawal, transfer or reversal"
+// @Param min_amount query string false "Smallest amount, e.g. 100.00"
+// @Param max_amount query string false "Largest amount, e.g. 500.00"
+// @Param from query string false "Earliest time, inclusive"
+// @Param to query string false "Latest time, exclusive"
+// @Param q query string false "Part of the name"
+// @Param sort query string false "newest (default) or oldest"
+// @Param limit query int false "Page size, 10 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{transactions=[]models.Transaction,total=int,next_cursor=string} "List of transactions, total without filters, next_cursor with them"
 // @Failure 400 {object} base.ErrorResponse "Invalid input format"
 // @Failure 401 {object} base.ErrorResponse "Unauthorized"
 // @Failure 500 {object} base.ErrorResponse "Failed to retrieve transactions"
@@ -39,23 +56,258 @@ func (c *TransactionController) ListTransactions(ctx *fiber.Ctx) error {
 		Transactions []*models.Transaction `json:"transactions"`
 		Total        int                   `json:"total"`
 	}
-	pageQuery := ctx.Query("page", "1")
-	page, err := strconv.Atoi(pageQuery)
+
+	userID := ctx.Locals("userID").(string)
+
+	if !hasTransactionSearch(ctx) {
+		pageQuery := ctx.Query("page", "1")
+		page, err := strconv.Atoi(pageQuery)
+		if err != nil {
+			logger.Warn("Cannot parse page query to int, default to 1", zap.String("page", pageQuery), zap.Error(err))
+			page = 1
+		}
+
+		transactions, total, err := c.TransactionService.GetTransactionsByUserID(userID, page)
+		if err != nil {
+			logger.Error("Failed to get transactions", zap.String("user_id", userID), zap.Error(err))
+			return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve transactions")
+		}
+
+		return ctx.JSON(listTransactionResponse{
+			Transactions: transactions,
+			Total:        total,
+		})
+	}
+
+	if ctx.Query("page") != "" {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "page cannot be combined with filters, sort, limit or cursor")
+	}
+
+	filter, cursor, err := parseTransactionSearch(ctx)
 	if err != nil {
-		logger.Warn("Cannot parse page query to int, default to 1", zap.String("page", pageQuery), zap.Error(err))
-		page = 1
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
 	}
+	filter.UserID = userID
 
-	userID := ctx.Locals("userID").(string)
+	transactions, nextCursor, err := c.TransactionService.SearchTransactions(filter, cursor)
+	return transactionSearchResponse(ctx, transactions, nextCursor, err)
+}
 
-	transactions, total, err := c.TransactionService.GetTransactionsByUserID(userID, page)
+// ListAccountTransactions retrieves the transactions of an account of the user, filtered a cursor at a time.
+// @Description Lists the transactions of the account newest first, with the filters, sort and cursors of GET /transactions. Pass next_cursor of a page as cursor, with the same filters and sort, to get the next one, it is absent on the last page.
+// @Summary List account transactions
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account ID"
+// @Param type query string false "deposit, withdrawal, transfer or reversal"
+// @Param min_amount query string false "Smallest amount, e.g. 100.00"
+// @Param max_amount query string false "Largest amount, e.g. 500.00"
+// @Param from query string false "Earliest time, inclusive"
+// @Param to query string false "Latest time, exclusive"
+// @Param q query string false "Part of the name"
+// @Param sort query string false "newest (default) or oldest"
+// @Param limit query int false "Page size, 10 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} object{transactions=[]models.Transaction,next_cursor=string} "Transactions of the account"
+// @Failure 400 {object} base.ErrorResponse "Invalid filter or cursor"
+// @Failure 401 {object} base.ErrorResponse "Unauthorized"
+// @Failure 404 {object} base.ErrorResponse "Account not found"
+// @Failure 500 {object} base.ErrorResponse "Failed to retrieve transactions"
+// @Router /accounts/{id}/transactions [get]
+func (c *TransactionController) ListAccountTransactions(ctx *fiber.Ctx) error {
+	filter, cursor, err := parseTransactionSearch(ctx)
 	if err != nil {
-		logger.Error("Failed to get transactions", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	filter.AccountID = ctx.Params("id")
+
+	transactions, nextCursor, err := c.TransactionService.GetAccountTransactions(filter, cursor)
+	return transactionSearchResponse(ctx, transactions, nextCursor, err)
+}
+
+// transactionSearchParams are the query parameters of a filtered transaction listing
+var transactionSearchParams = []string{"account_id", "type", "min_amount", "max_amount", "from", "to", "q", "sort", "limit", "cursor"}
+
+func hasTransactionSearch(ctx *fiber.Ctx) bool {
+	for _, param := range transactionSearchParams {
+		if ctx.Query(param) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTransactionSearch reads the filters, sort and page size of a transaction listing and the cursor of the page
+func parseTransactionSearch(ctx *fiber.Ctx) (models.TransactionFilter, string, error) {
+	type transactionQuery struct {
+		AccountID       string `query:"account_id" validate:"max=50"`
+		TransactionType string `query:"type" validate:"omitempty,oneof=deposit withdrawal transfer reversal"`
+		MinAmount       string `query:"min_amount" validate:"omitempty,numeric,max=20"`
+		MaxAmount       string `query:"max_amount" validate:"omitempty,numeric,max=20"`
+		From            string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		To              string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		Query           string `query:"q" validate:"max=100"`
+		Sort            string `query:"sort" validate:"omitempty,oneof=newest oldest"`
+		Limit           int    `query:"limit" validate:"min=0,max=100"`
+		Cursor          string `query:"cursor" validate:"max=512"`
+	}
+
+	var query transactionQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return models.TransactionFilter{}, "", errors.New("Invalid query")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(query); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return models.TransactionFilter{}, "", errors.New(utils.ValidatorErrors(err))
+	}
+
+	filter := models.TransactionFilter{
+		AccountID:       query.AccountID,
+		TransactionType: query.TransactionType,
+		MinAmount:       query.MinAmount,
+		MaxAmount:       query.MaxAmount,
+		Query:           query.Query,
+		Sort:            models.TransactionSort(query.Sort),
+		Limit:           query.Limit,
+	}
+	if query.From != "" {
+		from, _ := time.Parse(time.RFC3339, query.From)
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, _ := time.Parse(time.RFC3339, query.To)
+		filter.To = &to
+	}
+
+	return filter, query.Cursor, nil
+}
+
+// transactionSearchResponse writes a page of a filtered transaction listing or the error reading it
+func transactionSearchResponse(ctx *fiber.Ctx, transactions []*models.Transaction, nextCursor string, err error) error {
+	type searchTransactionResponse struct {
+		Transactions []*models.Transaction `json:"transactions"`
+		NextCursor   string                `json:"next_cursor,omitempty"` // absent on the last page
+	}
+
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTransactionCursor) {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		logger.Error("Failed to search transactions", zap.Error(err))
 		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve transactions")
 	}
 
-	return ctx.JSON(listTransactionResponse{
+	return ctx.JSON(searchTransactionResponse{
 		Transactions: transactions,
-		Total:        total,
+		NextCursor:   nextCursor,
 	})
 }
+
+// ReverseTransaction returns a transfer the user received to its sender, fully or in part.
+// @Description Returns a transfer received by the user to its sender by creating compensating transactions on both legs. Only the credit leg of a transfer can be returned by its recipient, other reversals are made by staff. Omit the amount to return everything not returned yet.
+// @Summary Return received transfer
+// @Tags Transactions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Transaction ID of the received transfer"
+// @Param reversal body controllers.reverseTransactionRequest false "Amount to return"
+// @Param Idempotency-Key header string false "Client generated key, retries with the same key replay the first response"
+// @Success 200 {object} models.ReversalResult "Reversed transaction and its compensating transactions"
+// @Failure 400 {object} base.ErrorResponse "Invalid amount or insufficient funds"
+// @Failure 401 {object} base.ErrorResponse "Unauthorized"
+// @Failure 403 {object} base.ErrorResponse "Account is frozen"
+// @Failure 404 {object} base.ErrorResponse "Transaction not found"
+// @Failure 409 {object} base.ErrorResponse "Transaction already reversed or not a received transfer"
+// @Failure 500 {object} base.ErrorResponse "Failed to reverse transaction"
+// @Router /transactions/{id}/reverse [post]
+func (c *TransactionController) ReverseTransaction(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+	return c.reverse(ctx, auditEntry, func(transaction *models.Transaction) bool { return transaction.UserID == userID },
+		func(transactionID string, amount *types.Money) (*models.ReversalResult, error) {
+			return c.TransactionService.ReturnTransfer(userID, transactionID, amount)
+		})
+}
+
+// ReverseAnyTransaction reverses or partially refunds a transaction of any user.
+// @Description Reverses a deposit, withdrawal, hold capture or transfer of any user by creating compensating transactions. Omit the amount to reverse everything not reversed yet. Requires the transactions:reverse permission.
+// @Summary Reverse any transaction
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Transaction ID"
+// @Param reversal body controllers.reverseTransactionRequest false "Amount to refund"
+// @Param Idempotency-Key header string false "Client generated key, retries with the same key replay the first response"
+// @Success 200 {object} models.ReversalResult "Reversed transaction and its compensating transactions"
+// @Failure 400 {object} base.ErrorResponse "Invalid amount or insufficient funds"
+// @Failure 403 {object} base.ErrorResponse "Missing permission or account is frozen"
+// @Failure 404 {object} base.ErrorResponse "Transaction not found"
+// @Failure 409 {object} base.ErrorResponse "Transaction already reversed or not reversible"
+// @Failure 500 {object} base.ErrorResponse "Failed to reverse transaction"
+// @Router /admin/transactions/{id}/reverse [post]
+func (c *TransactionController) ReverseAnyTransaction(ctx *fiber.Ctx) error {
+	return c.reverse(ctx, staffAuditEntry, func(transaction *models.Transaction) bool { return true },
+		c.TransactionService.ReverseTransaction)
+}
+
+// reverseTransactionRequest is the optional body of a reversal
+type reverseTransactionRequest struct {
+	Amount json.Number `json:"amount" swaggertype:"string" example:"50.00"`
+}
+
+// reverse looks up the transaction in the path, answering 404 unless visible accepts it, and reverses it with
+// reverseFn, the amount is parsed in the currency of the transaction. The attempt is audited with newAuditEntry.
+func (c *TransactionController) reverse(ctx *fiber.Ctx, newAuditEntry func(*fiber.Ctx, string, string, string) *models.AuditLog,
+	visible func(transaction *models.Transaction) bool,
+	reverseFn func(transactionID string, amount *types.Money) (*models.ReversalResult, error)) error {
+	transactionID := ctx.Params("id")
+
+	var request reverseTransactionRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&request); err != nil {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	transaction, err := c.TransactionService.GetTransactionByID(transactionID)
+	if err != nil || !visible(transaction) {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Transaction not found")
+	}
+
+	var amount *types.Money
+	if request.Amount != "" {
+		refund, err := parseAmount(request.Amount, transaction.Amount.Currency)
+		if err != nil {
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		amount = &refund
+	}
+
+	result, err := reverseFn(transactionID, amount)
+	entry := newAuditEntry(ctx, models.AuditTransactionReverse, "transaction", transactionID)
+	if err != nil {
+		entry.Outcome = models.AuditFailure
+		c.AuditService.Record(entry, transaction, fiber.Map{"amount": amount, "error": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrTransactionNotFound):
+			return ErrorResponse(ctx, fiber.StatusNotFound, "Transaction not found")
+		case errors.Is(err, services.ErrTransactionAlreadyReversed), errors.Is(err, services.ErrTransactionNotReversible),
+			errors.Is(err, services.ErrTransferNotReturnable):
+			return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrReversalExceedsAmount), errors.Is(err, services.ErrInsufficientFunds):
+			return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAccountFrozen):
+			return ErrorResponse(ctx, fiber.StatusForbidden, "Account is frozen")
+		}
+		logger.Error("Failed to reverse transaction", zap.String("transaction_id", transactionID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to reverse transaction")
+	}
+	c.AuditService.Record(entry, transaction, result)
+
+	return ctx.JSON(result)
+}
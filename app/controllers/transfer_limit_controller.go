@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/services"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// TransferLimitController handles transfer limit HTTP requests
+type TransferLimitController struct {
+	accountService       services.AccountService
+	transferLimitService services.TransferLimitService
+}
+
+// NewTransferLimitController creates a new TransferLimitController
+func NewTransferLimitController(accountService services.AccountService, transferLimitService services.TransferLimitService) *TransferLimitController {
+	return &TransferLimitController{
+		accountService:       accountService,
+		transferLimitService: transferLimitService,
+	}
+}
+
+// GetAccountLimits retrieves the limits of an account and the allowance left
+//
+//	@Summary		Get account limits
+//	@Description	Get the per transaction, daily and monthly limits on money leaving an account and how much is left. Windows reset at midnight Asia/Bangkok, a null limit is unlimited.
+//	@Tags			accounts
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"Account ID"
+//	@Success		200	{object}	models.AccountLimits
+//	@Failure		404	{object}	base.ErrorResponse	"Account not found"
+//	@Router			/accounts/{id}/limits [get]
+func (lc *TransferLimitController) GetAccountLimits(ctx *fiber.Ctx) error {
+	account, err := getOwnedAccount(ctx, lc.accountService)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Account not found")
+	}
+
+	limits, err := lc.transferLimitService.GetAccountLimits(account)
+	if err != nil {
+		logger.Error("Failed to get account limits", zap.String("account_id", account.AccountID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve account limits")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(limits)
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/utils"
+	"errors"
 
 	fiber "github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -10,13 +12,24 @@ import (
 
 // UserController holds the services related to users.
 type UserController struct {
-	UserService services.UserService
+	UserService       services.UserService
+	PinLockoutService services.PinLockoutService
+	PinService        services.PinService
+	TOTPService       services.TOTPService
 }
 
 // NewUserController creates a new UserController.
-func NewUserController(userService services.UserService) *UserController {
+func NewUserController(
+	userService services.UserService,
+	pinLockoutService services.PinLockoutService,
+	pinService services.PinService,
+	totpService services.TOTPService,
+) *UserController {
 	return &UserController{
-		UserService: userService,
+		UserService:       userService,
+		PinLockoutService: pinLockoutService,
+		PinService:        pinService,
+		TOTPService:       totpService,
 	}
 }
 
@@ -102,16 +115,21 @@ func (c *UserController) UpdateUserGreeting(ctx *fiber.Ctx) error {
 
 // GetUser get user's information
 // @Summary Get user's information
-// @Description Retrieves the information of the authenticated user
+// @Description Retrieves the information of the authenticated user and whether the PIN is locked
 // @Tags User
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Success 200 {object} models.User "Returns the user information"
+// @Success 200 {object} controllers.GetUser.getUserResponse "Returns the user information"
 // @Failure 401 {object} base.ErrorResponse "Unauthorized - Invalid or missing token"
 // @Failure 404 {object} base.ErrorResponse "User not found"
 // @Router /user/profile [get]
 func (c *UserController) GetUser(ctx *fiber.Ctx) error {
+	type getUserResponse struct {
+		*models.User
+		PinLock *models.PinLockState `json:"pin_lock,omitempty"` // omitted when the lock state cannot be read
+	}
+
 	var user *models.User
 
 	userID := ctx.Locals("userID").(string)
@@ -121,7 +139,15 @@ func (c *UserController) GetUser(ctx *fiber.Ctx) error {
 		return ErrorResponse(ctx, fiber.StatusNotFound, "User not found")
 	}
 
-	return ctx.JSON(user)
+	pinLock, err := c.PinLockoutService.GetLockState(userID)
+	if err != nil {
+		logger.Error("Failed to get PIN lock state", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	return ctx.JSON(getUserResponse{
+		User:    user,
+		PinLock: pinLock,
+	})
 }
 
 func (c *UserController) UpdateUser(ctx *fiber.Ctx) error {
@@ -150,3 +176,132 @@ func (c *UserController) UpdateUser(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(user)
 }
+
+// ChangePin change user's PIN
+// @Summary Change user's PIN
+// @Description Replace the PIN of the authenticated user. The new PIN must be 6 digits without a digit repeated or sequential digits more than twice in a row, and differ from the last 5 PINs. Wrong current PINs count towards the PIN lockout and all refresh tokens of the user are revoked.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body controllers.ChangePin.changePinRequest true "Current and new PIN"
+// @Success 204 "PIN changed"
+// @Failure 400 {object} base.ErrorResponse "New PIN rejected by the PIN policy or used recently"
+// @Failure 401 {object} base.ErrorResponse "Current PIN is incorrect"
+// @Failure 423 {object} base.ErrorResponse "PIN is locked"
+// @Failure 429 {object} base.ErrorResponse "Too many failed attempts"
+// @Failure 500 {object} base.ErrorResponse "Internal server error"
+// @Router /user/pin [put]
+func (c *UserController) ChangePin(ctx *fiber.Ctx) error {
+	type changePinRequest struct {
+		CurrentPIN string `json:"current_pin" validate:"required"`
+		NewPIN     string `json:"new_pin" validate:"required"`
+	}
+
+	userID := ctx.Locals("userID").(string)
+
+	var request changePinRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid input format: "+err.Error())
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	// The current PIN is guessed as easily here as on sign-in, the attempt is counted before the PIN is verified
+	attempt, err := c.PinLockoutService.Reserve(userID, ctx.IP())
+	if err != nil {
+		return pinLockedResponse(ctx, attempt, err)
+	}
+
+	err = c.PinService.ChangePin(userID, request.CurrentPIN, request.NewPIN)
+	switch {
+	case err == nil:
+		releasePinAttempt(c.PinLockoutService.RecordSuccess, attempt)
+		return ctx.Status(fiber.StatusNoContent).Send(nil)
+	case errors.Is(err, services.ErrIncorrectPin):
+		return ErrorResponse(ctx, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrPinPolicy), errors.Is(err, services.ErrPinReused):
+		// The new PIN is only checked once the current one is verified
+		releasePinAttempt(c.PinLockoutService.RecordSuccess, attempt)
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	releasePinAttempt(c.PinLockoutService.Release, attempt)
+	logger.Error("Failed to change PIN", zap.String("user_id", userID), zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to change PIN")
+}
+
+// EnrollTOTP set up an authenticator app
+// @Summary Set up an authenticator app
+// @Description Generate a TOTP secret for an authenticator app, replacing one that was not enabled yet. The secret confirms challenges once enabled through /user/totp/enable.
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 201 {object} models.TOTPEnrollment "Secret and otpauth URI for the authenticator app"
+// @Failure 409 {object} base.ErrorResponse "An authenticator app is already enabled"
+// @Failure 500 {object} base.ErrorResponse "Internal server error"
+// @Router /user/totp [post]
+func (c *UserController) EnrollTOTP(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+
+	enrollment, err := c.TOTPService.Enroll(userID)
+	if errors.Is(err, services.ErrTOTPAlreadyEnabled) {
+		return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to set up authenticator app", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to set up authenticator app")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(enrollment)
+}
+
+// EnableTOTP enable the authenticator app
+// @Summary Enable the authenticator app
+// @Description Enable the secret from /user/totp with a code the authenticator app shows.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body controllers.EnableTOTP.enableTOTPRequest true "Code of the authenticator app"
+// @Success 204 "Authenticator app enabled"
+// @Failure 400 {object} base.ErrorResponse "No authenticator app set up"
+// @Failure 401 {object} base.ErrorResponse "Invalid code"
+// @Failure 409 {object} base.ErrorResponse "An authenticator app is already enabled"
+// @Failure 500 {object} base.ErrorResponse "Internal server error"
+// @Router /user/totp/enable [post]
+func (c *UserController) EnableTOTP(ctx *fiber.Ctx) error {
+	type enableTOTPRequest struct {
+		Code string `json:"code" validate:"required" example:"123456"`
+	}
+
+	userID := ctx.Locals("userID").(string)
+
+	var request enableTOTPRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid input format: "+err.Error())
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(request); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	err := c.TOTPService.Enable(userID, request.Code)
+	switch {
+	case err == nil:
+		return ctx.Status(fiber.StatusNoContent).Send(nil)
+	case errors.Is(err, services.ErrTOTPNotEnrolled):
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrInvalidTOTP):
+		return ErrorResponse(ctx, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrTOTPAlreadyEnabled):
+		return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+	}
+
+	logger.Error("Failed to enable authenticator app", zap.String("user_id", userID), zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to enable authenticator app")
+}
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// WebhookController handles webhook endpoint HTTP requests. Users register endpoints receiving the events
+// of their own accounts and cards, staff register endpoints receiving every event through the admin routes.
+type WebhookController struct {
+	webhookService services.WebhookService
+	auditService   services.AuditService
+}
+
+// NewWebhookController creates a new WebhookController
+func NewWebhookController(webhookService services.WebhookService, auditService services.AuditService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+		auditService:   auditService,
+	}
+}
+
+// ListWebhooks retrieves the endpoints of the authenticated user
+//
+//	@Summary		List webhooks
+//	@Description	Get the webhook endpoints registered by the authenticated user
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	[]models.WebhookEndpoint
+//	@Router			/webhooks [get]
+func (wc *WebhookController) ListWebhooks(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("userID").(string)
+
+	endpoints, err := wc.webhookService.GetEndpointsByUserID(userID)
+	if err != nil {
+		logger.Error("Failed to get webhook endpoints", zap.String("user_id", userID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve webhooks")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(endpoints)
+}
+
+// CreateWebhook registers an endpoint receiving the events of the authenticated user
+//
+//	@Summary		Register webhook
+//	@Description	Register an endpoint receiving the events of the authenticated user's accounts and cards. Deliveries are signed with the returned secret, which is not shown again: the X-Webhook-Signature header is "t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			request	body		object{url=string,description=string,event_types=[]string}	true	"Endpoint URL, description and event types, none subscribes to every type"
+//	@Success		201		{object}	models.WebhookEndpointWithSecret
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid URL or event type"
+//	@Router			/webhooks [post]
+func (wc *WebhookController) CreateWebhook(ctx *fiber.Ctx) error {
+	return wc.createWebhook(ctx, models.WebhookScopeUser, auditEntry)
+}
+
+// DeleteWebhook deletes an endpoint of the authenticated user
+//
+//	@Summary		Delete webhook
+//	@Description	Delete a webhook endpoint, its pending deliveries are no longer attempted
+//	@Tags			webhooks
+//	@Security		ApiKeyAuth
+//	@Param			id	path	string	true	"Endpoint ID"
+//	@Success		204
+//	@Failure		404	{object}	base.ErrorResponse	"Webhook not found"
+//	@Router			/webhooks/{id} [delete]
+func (wc *WebhookController) DeleteWebhook(ctx *fiber.Ctx) error {
+	return wc.deleteWebhook(ctx, auditEntry)
+}
+
+// ListDeliveries retrieves the delivery log of an endpoint
+//
+//	@Summary		List webhook deliveries
+//	@Description	Get the deliveries of a webhook endpoint newest first with the result of their last attempt, paged with before_id
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Endpoint ID"
+//	@Param			status		query		string	false	"Delivery status"	Enums(pending, delivered, dead)
+//	@Param			before_id	query		int		false	"List deliveries before this one"
+//	@Param			limit		query		int		false	"Page size, at most 200"	default(50)
+//	@Success		200			{object}	object{deliveries=[]models.WebhookDelivery,next_before_id=int}
+//	@Failure		400			{object}	base.ErrorResponse	"Invalid query"
+//	@Failure		404			{object}	base.ErrorResponse	"Webhook not found"
+//	@Router			/webhooks/{id}/deliveries [get]
+func (wc *WebhookController) ListDeliveries(ctx *fiber.Ctx) error {
+	type deliveryQuery struct {
+		Status   string `query:"status" validate:"omitempty,oneof=pending delivered dead"`
+		BeforeID int64  `query:"before_id" validate:"min=0"`
+		Limit    int    `query:"limit" validate:"min=0,max=200"`
+	}
+	type deliveryResponse struct {
+		Deliveries   []*models.WebhookDelivery `json:"deliveries"`
+		NextBeforeID int64                     `json:"next_before_id,omitempty"` // absent on the last page
+	}
+
+	endpoint, err := wc.webhookService.GetEndpointByID(ctx.Params("id"))
+	if err != nil {
+		return endpointErrorResponse(ctx, err)
+	}
+
+	var query deliveryQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid query")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(query); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	deliveries, err := wc.webhookService.ListDeliveries(endpoint.EndpointID, models.WebhookDeliveryFilter{
+		Status:   models.WebhookDeliveryStatus(query.Status),
+		BeforeID: query.BeforeID,
+		Limit:    query.Limit,
+	})
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries", zap.String("endpoint_id", endpoint.EndpointID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to list webhook deliveries")
+	}
+
+	response := deliveryResponse{Deliveries: deliveries}
+	limit := query.Limit
+	if limit == 0 {
+		limit = services.DefaultWebhookDeliveryLimit
+	}
+	if len(deliveries) == limit {
+		response.NextBeforeID = deliveries[len(deliveries)-1].DeliveryID
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ReplayDelivery sends a delivery again
+//
+//	@Summary		Replay webhook delivery
+//	@Description	Send a delivered or dead delivery again with the same body and a fresh set of attempts
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			id			path		string	true	"Endpoint ID"
+//	@Param			deliveryId	path		int		true	"Delivery ID"
+//	@Success		202			{object}	models.WebhookDelivery
+//	@Failure		404			{object}	base.ErrorResponse	"Webhook or delivery not found"
+//	@Failure		409			{object}	base.ErrorResponse	"Delivery is still being attempted"
+//	@Router			/webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (wc *WebhookController) ReplayDelivery(ctx *fiber.Ctx) error {
+	endpoint, err := wc.webhookService.GetEndpointByID(ctx.Params("id"))
+	if err != nil {
+		return endpointErrorResponse(ctx, err)
+	}
+
+	deliveryID, err := strconv.ParseInt(ctx.Params("deliveryId"), 10, 64)
+	if err != nil {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Delivery not found")
+	}
+
+	delivery, err := wc.webhookService.ReplayDelivery(endpoint.EndpointID, deliveryID)
+	switch {
+	case errors.Is(err, services.ErrDeliveryNotFound):
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Delivery not found")
+	case errors.Is(err, services.ErrDeliveryInProgress):
+		return ErrorResponse(ctx, fiber.StatusConflict, err.Error())
+	case err != nil:
+		logger.Error("Failed to replay webhook delivery", zap.Int64("delivery_id", deliveryID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to replay delivery")
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(delivery)
+}
+
+// ListGlobalWebhooks retrieves the endpoints receiving every event
+//
+//	@Summary		List global webhooks
+//	@Description	Get the webhook endpoints receiving the events of every user. Requires the webhooks:manage permission.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	[]models.WebhookEndpoint
+//	@Failure		403	{object}	base.ErrorResponse	"Missing permission"
+//	@Router			/admin/webhooks [get]
+func (wc *WebhookController) ListGlobalWebhooks(ctx *fiber.Ctx) error {
+	endpoints, err := wc.webhookService.GetEndpointsByScope(models.WebhookScopeAll)
+	if err != nil {
+		logger.Error("Failed to get global webhook endpoints", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve webhooks")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(endpoints)
+}
+
+// CreateGlobalWebhook registers an endpoint receiving every event
+//
+//	@Summary		Register global webhook
+//	@Description	Register an endpoint receiving the events of every user. Requires the webhooks:manage permission.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			request	body		object{url=string,description=string,event_types=[]string}	true	"Endpoint URL, description and event types, none subscribes to every type"
+//	@Success		201		{object}	models.WebhookEndpointWithSecret
+//	@Failure		400		{object}	base.ErrorResponse	"Invalid URL or event type"
+//	@Failure		403		{object}	base.ErrorResponse	"Missing permission"
+//	@Router			/admin/webhooks [post]
+func (wc *WebhookController) CreateGlobalWebhook(ctx *fiber.Ctx) error {
+	return wc.createWebhook(ctx, models.WebhookScopeAll, staffAuditEntry)
+}
+
+// DeleteAnyWebhook deletes an endpoint of any user
+//
+//	@Summary		Delete any webhook
+//	@Description	Delete a webhook endpoint of any user or a global one. Requires the webhooks:manage permission.
+//	@Tags			admin
+//	@Security		ApiKeyAuth
+//	@Param			id	path	string	true	"Endpoint ID"
+//	@Success		204
+//	@Failure		403	{object}	base.ErrorResponse	"Missing permission"
+//	@Failure		404	{object}	base.ErrorResponse	"Webhook not found"
+//	@Router			/admin/webhooks/{id} [delete]
+func (wc *WebhookController) DeleteAnyWebhook(ctx *fiber.Ctx) error {
+	return wc.deleteWebhook(ctx, staffAuditEntry)
+}
+
+func (wc *WebhookController) createWebhook(ctx *fiber.Ctx, scope models.WebhookScope, newAuditEntry func(*fiber.Ctx, string, string, string) *models.AuditLog) error {
+	type createWebhookRequest struct {
+		URL         string   `json:"url" validate:"required,url,max=500"`
+		Description string   `json:"description" validate:"max=255"`
+		EventTypes  []string `json:"event_types" validate:"max=10,dive,max=50"`
+	}
+
+	userID := ctx.Locals("userID").(string)
+
+	var req createWebhookRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ErrorResponse(ctx, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(req); err != nil {
+		logger.Info("Validation error", zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusBadRequest, utils.ValidatorErrors(err))
+	}
+
+	eventTypes := make(models.EventTypeList, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		eventTypes = append(eventTypes, models.EventType(eventType))
+	}
+
+	endpoint, err := wc.webhookService.CreateEndpoint(userID, scope, req.URL, req.Description, eventTypes)
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrPrivateWebhookURL), errors.Is(err, services.ErrUnknownEventType):
+		return ErrorResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to register webhook")
+	}
+
+	wc.auditService.Record(newAuditEntry(ctx, models.AuditWebhookCreate, "webhook", endpoint.EndpointID), nil, endpoint)
+
+	return ctx.Status(fiber.StatusCreated).JSON(models.WebhookEndpointWithSecret{WebhookEndpoint: endpoint, Secret: endpoint.Secret})
+}
+
+func (wc *WebhookController) deleteWebhook(ctx *fiber.Ctx, newAuditEntry func(*fiber.Ctx, string, string, string) *models.AuditLog) error {
+	endpoint, err := wc.webhookService.GetEndpointByID(ctx.Params("id"))
+	if err != nil {
+		return endpointErrorResponse(ctx, err)
+	}
+
+	err = wc.webhookService.DeleteEndpoint(endpoint.EndpointID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Webhook not found")
+	case err != nil:
+		logger.Error("Failed to delete webhook endpoint", zap.String("endpoint_id", endpoint.EndpointID), zap.Error(err))
+		return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to delete webhook")
+	}
+
+	wc.auditService.Record(newAuditEntry(ctx, models.AuditWebhookDelete, "webhook", endpoint.EndpointID), endpoint, nil)
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// endpointErrorResponse responds to a failed lookup of the endpoint in the path
+func endpointErrorResponse(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorResponse(ctx, fiber.StatusNotFound, "Webhook not found")
+	}
+	logger.Error("Failed to get webhook endpoint", zap.String("endpoint_id", ctx.Params("id")), zap.Error(err))
+	return ErrorResponse(ctx, fiber.StatusInternalServerError, "Failed to retrieve webhook")
+}
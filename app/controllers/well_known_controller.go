@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/utils"
+	"fmt"
+	"time"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+// WellKnownController serves the public documents of the /.well-known path
+type WellKnownController struct{}
+
+// NewWellKnownController creates a new WellKnownController
+func NewWellKnownController() *WellKnownController {
+	return &WellKnownController{}
+}
+
+// GetJWKS returns the public keys access tokens are signed with
+//
+//	@Summary		Get JSON Web Key Set
+//	@Description	Public keys other services verify access tokens with, pick the key by the kid in the token header. Keys that start signing soon are already listed.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	utils.JWKSet
+//	@Failure		503	{object}	base.ErrorResponse	"No keyring loaded"
+//	@Router			/.well-known/jwks.json [get]
+func (wc *WellKnownController) GetJWKS(ctx *fiber.Ctx) error {
+	keyring := utils.CurrentKeyring()
+	if keyring == nil {
+		return ErrorResponse(ctx, fiber.StatusServiceUnavailable, "No signing keys available")
+	}
+
+	ctx.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(configs.JWKS_MAX_AGE/time.Second)))
+	return ctx.JSON(keyring.JWKS(time.Now()))
+}
@@ -1,5 +1,12 @@
 package models
 
+// Flag types, and the system flag value of accounts frozen by operations staff
+const (
+	AccountFlagSystem = "system"
+	AccountFlagUser   = "user"
+	AccountFlagFrozen = "frozen"
+)
+
 // AccountFlag represents the account_flags table
 type AccountFlag struct {
 	*BaseModel
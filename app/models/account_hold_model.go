@@ -0,0 +1,36 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+type HoldStatus string
+
+const (
+	HoldActive   HoldStatus = "active"
+	HoldCaptured HoldStatus = "captured" // a partial capture releases the rest of the hold
+	HoldReleased HoldStatus = "released"
+	HoldExpired  HoldStatus = "expired"
+)
+
+// AccountHold represents the account_holds table, funds reserved on an account until they are captured,
+// released or the hold expires
+type AccountHold struct {
+	HoldID         string      `db:"hold_id" json:"hold_id"`
+	AccountID      string      `db:"account_id" json:"account_id"`
+	UserID         string      `db:"user_id" json:"user_id"`
+	Amount         types.Money `db:"amount" json:"amount"` // currency is stored in the currency column
+	CapturedAmount types.Money `db:"captured_amount" json:"captured_amount"`
+	Description    string      `db:"description" json:"description"`
+	Status         HoldStatus  `db:"status" json:"status"` // active, captured, released, expired
+	ExpiresAt      time.Time   `db:"expires_at" json:"expires_at"`
+	TransactionID  *string     `db:"transaction_id" json:"transaction_id,omitempty"` // withdrawal created by the capture
+	CreatedAt      time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+// IsExpired reports whether the hold no longer reserves funds at the given time
+func (h *AccountHold) IsExpired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
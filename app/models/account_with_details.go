@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
 
 // AccountWithDetailsFlat represents a flattened view of an account with all its related information
 type AccountWithDetails struct {
@@ -21,8 +24,19 @@ type AccountWithDetails struct {
 	Progress      int    `json:"progress" db:"progress"`
 
 	// AccountBalance fields
-	Amount float64 `json:"amount" db:"amount"`
+	Amount          types.Money `json:"amount" db:"amount"`                     // ledger balance
+	AvailableAmount types.Money `json:"available_amount" db:"available_amount"` // ledger balance less active holds
 
 	// AccountFlags
 	Flags []*AccountFlag `json:"flags" db:"-"` // Using db:"-" to indicate this field is not directly mapped from DB
 }
+
+// IsFrozen reports whether operations staff froze the account, a frozen account cannot move money
+func (a *AccountWithDetails) IsFrozen() bool {
+	for _, flag := range a.Flags {
+		if flag.FlagType == AccountFlagSystem && flag.FlagValue == AccountFlagFrozen {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditPinVerify          = "auth.pin_verify"
+	AuditTokenRenew         = "auth.token_renew"
+	AuditAccountCreate      = "account.create"
+	AuditAccountUpdate      = "account.update"
+	AuditAccountSetMain     = "account.set_main"
+	AuditAccountFreeze      = "account.freeze"
+	AuditAccountUnfreeze    = "account.unfreeze"
+	AuditDeposit            = "account.deposit"
+	AuditWithdrawal         = "account.withdraw"
+	AuditTransfer           = "account.transfer"
+	AuditHoldCapture        = "account.hold_capture"
+	AuditHoldRelease        = "account.hold_release"
+	AuditTransactionReverse = "transaction.reverse"
+	AuditScheduleExecute    = "schedule.execute"
+	AuditCardStatusChange   = "card.status_change"
+	AuditWebhookCreate      = "webhook.create"
+	AuditWebhookDelete      = "webhook.delete"
+	AuditStatementRead      = "account.statement_read"
+)
+
+// Outcomes of audited actions, failures are recorded for security events such as a wrong PIN
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// Kinds of actors, staff act through the admin API and the system runs scheduled work
+const (
+	AuditActorUser   = "user"
+	AuditActorStaff  = "staff"
+	AuditActorSystem = "system"
+)
+
+// AuditLog represents the audit_logs table, an entry is never changed once written
+type AuditLog struct {
+	AuditID      int64            `db:"audit_id" json:"audit_id"`
+	Action       string           `db:"action" json:"action" example:"account.withdraw"`
+	Outcome      string           `db:"outcome" json:"outcome" example:"success"`
+	ActorID      string           `db:"actor_id" json:"actor_id"`
+	ActorType    string           `db:"actor_type" json:"actor_type" example:"user"`
+	ResourceType string           `db:"resource_type" json:"resource_type" example:"account"`
+	ResourceID   string           `db:"resource_id" json:"resource_id"`
+	Before       *json.RawMessage `db:"before_state" json:"before,omitempty" swaggertype:"object"` // snapshot before the action
+	After        *json.RawMessage `db:"after_state" json:"after,omitempty" swaggertype:"object"`   // snapshot after the action
+	IPAddress    string           `db:"ip_address" json:"ip_address"`
+	RequestID    string           `db:"request_id" json:"request_id"`
+	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+}
+
+// AuditLogFilter selects audit entries, empty fields match any entry. Entries are listed newest first,
+// BeforeID continues a listing after the last entry of the previous page.
+type AuditLogFilter struct {
+	ActorID      string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	From         *time.Time
+	To           *time.Time
+	BeforeID     int64
+	Limit        int
+}
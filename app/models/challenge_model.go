@@ -0,0 +1,80 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"encoding/json"
+	"time"
+)
+
+type ChallengeAction string
+
+const (
+	ChallengeActionTransfer       ChallengeAction = "transfer"
+	ChallengeActionSchedule       ChallengeAction = "schedule"        // creates a ScheduledTransfer
+	ChallengeActionScheduleUpdate ChallengeAction = "schedule_update" // applies a PendingScheduleUpdate
+)
+
+// Second factors a challenge can be confirmed with
+const (
+	ChallengeMethodPIN  = "pin"
+	ChallengeMethodTOTP = "totp"
+)
+
+// Challenge represents the challenges table, an operation waiting for the user to confirm it with a second factor
+type Challenge struct {
+	ChallengeID string          `db:"challenge_id" json:"challenge_id"`
+	UserID      string          `db:"user_id" json:"user_id"`
+	Action      ChallengeAction `db:"action" json:"action"`
+	Payload     json.RawMessage `db:"payload" json:"-"` // the operation to run, e.g. a PendingTransfer
+	Attempts    int             `db:"attempts" json:"attempts"`
+	ExpiresAt   time.Time       `db:"expires_at" json:"expires_at"`
+	ConfirmedAt *time.Time      `db:"confirmed_at" json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+}
+
+// IsExpired reports whether the challenge can no longer be confirmed at the given time
+func (c *Challenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// Transfer decodes the transfer a transfer challenge was created for
+func (c *Challenge) Transfer() (*PendingTransfer, error) {
+	var transfer PendingTransfer
+	if err := json.Unmarshal(c.Payload, &transfer); err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// Schedule decodes the scheduled transfer a schedule challenge was created for
+func (c *Challenge) Schedule() (*ScheduledTransfer, error) {
+	var schedule ScheduledTransfer
+	if err := json.Unmarshal(c.Payload, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ScheduleUpdate decodes the update a schedule update challenge was created for
+func (c *Challenge) ScheduleUpdate() (*PendingScheduleUpdate, error) {
+	var update PendingScheduleUpdate
+	if err := json.Unmarshal(c.Payload, &update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// PendingTransfer is a transfer waiting for step-up authentication
+type PendingTransfer struct {
+	FromAccountID string      `json:"from_account_id"`
+	ToAccountID   string      `json:"to_account_id"`
+	Amount        types.Money `json:"amount"`
+	QuoteID       string      `json:"quote_id,omitempty"`
+}
+
+// PendingScheduleUpdate is an update of a scheduled transfer waiting for step-up authentication
+type PendingScheduleUpdate struct {
+	AccountID  string                  `json:"account_id"`
+	ScheduleID string                  `json:"schedule_id"`
+	Update     ScheduledTransferUpdate `json:"update"`
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"encoding/json"
+	"time"
+)
+
+// EventType names a domain event, other systems subscribe to events by their type
+type EventType string
+
+const (
+	EventAccountCreated      EventType = "AccountCreated"
+	EventFundsDeposited      EventType = "FundsDeposited"
+	EventFundsWithdrawn      EventType = "FundsWithdrawn"
+	EventTransferCompleted   EventType = "TransferCompleted"
+	EventCardStatusChanged   EventType = "CardStatusChanged"
+	EventTransactionReversed EventType = "TransactionReversed"
+)
+
+// EventTypes lists the types of the events that are published
+var EventTypes = []EventType{EventAccountCreated, EventFundsDeposited, EventFundsWithdrawn, EventTransferCompleted, EventCardStatusChanged, EventTransactionReversed}
+
+// IsValid reports whether the type is one of EventTypes
+func (t EventType) IsValid() bool {
+	for _, eventType := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Kinds of aggregates events are about, events of one aggregate are published in the order they happened
+const (
+	AggregateAccount   = "account"
+	AggregateDebitCard = "debit_card"
+)
+
+// DomainEvent represents the outbox_events table, a committed change other systems are told about
+type DomainEvent struct {
+	Sequence           int64           `db:"sequence" json:"sequence"`
+	EventID            string          `db:"event_id" json:"event_id"` // consumers deduplicate by it, an event may be published more than once
+	EventType          EventType       `db:"event_type" json:"event_type"`
+	AggregateType      string          `db:"aggregate_type" json:"aggregate_type"`
+	AggregateID        string          `db:"aggregate_id" json:"aggregate_id"`
+	RelatedAggregateID string          `db:"related_aggregate_id" json:"related_aggregate_id,omitempty"` // e.g. the destination of a transfer
+	Payload            json.RawMessage `db:"payload" json:"payload" swaggertype:"object"`
+	OccurredAt         time.Time       `db:"occurred_at" json:"occurred_at"`
+	PublishedAt        *time.Time      `db:"published_at" json:"-"`
+	Attempts           int             `db:"attempts" json:"-"`
+	LastError          string          `db:"last_error" json:"-"`
+}
+
+// AccountCreatedPayload is the payload of EventAccountCreated
+type AccountCreatedPayload struct {
+	AccountID string      `json:"account_id"`
+	UserID    string      `json:"user_id"`
+	Type      string      `json:"type"`
+	Currency  string      `json:"currency"`
+	Balance   types.Money `json:"balance"`
+}
+
+// FundsMovedPayload is the payload of EventFundsDeposited and EventFundsWithdrawn
+type FundsMovedPayload struct {
+	AccountID     string      `json:"account_id"`
+	UserID        string      `json:"user_id"`
+	TransactionID string      `json:"transaction_id"`
+	Amount        types.Money `json:"amount"`
+	Balance       types.Money `json:"balance"` // balance after the change
+}
+
+// TransferCompletedPayload is the payload of EventTransferCompleted
+type TransferCompletedPayload struct {
+	FromAccountID       string      `json:"from_account_id"`
+	FromUserID          string      `json:"from_user_id"`
+	ToAccountID         string      `json:"to_account_id"`
+	ToUserID            string      `json:"to_user_id"`
+	DebitTransactionID  string      `json:"debit_transaction_id"`
+	CreditTransactionID string      `json:"credit_transaction_id"`
+	Amount              types.Money `json:"amount"`
+	CreditedAmount      types.Money `json:"credited_amount"`
+	ExchangeRate        *types.Rate `json:"exchange_rate,omitempty"`
+	SourceBalance       types.Money `json:"source_balance"`
+	DestinationBalance  types.Money `json:"destination_balance"`
+}
+
+// TransferLegPayload is one side of a TransferCompletedPayload, what the user of that side may see of the transfer
+type TransferLegPayload struct {
+	AccountID             string           `json:"account_id"`
+	UserID                string           `json:"user_id"`
+	TransactionID         string           `json:"transaction_id"`
+	Direction             PostingDirection `json:"direction"`
+	Amount                types.Money      `json:"amount"` // in the currency of the account
+	CounterpartyAccountID string           `json:"counterparty_account_id"`
+	ExchangeRate          *types.Rate      `json:"exchange_rate,omitempty"`
+	Balance               types.Money      `json:"balance"` // balance after the transfer
+}
+
+// DebitLeg returns the side of the sender
+func (p *TransferCompletedPayload) DebitLeg() TransferLegPayload {
+	return TransferLegPayload{
+		AccountID:             p.FromAccountID,
+		UserID:                p.FromUserID,
+		TransactionID:         p.DebitTransactionID,
+		Direction:             Debit,
+		Amount:                p.Amount,
+		CounterpartyAccountID: p.ToAccountID,
+		ExchangeRate:          p.ExchangeRate,
+		Balance:               p.SourceBalance,
+	}
+}
+
+// CreditLeg returns the side of the recipient
+func (p *TransferCompletedPayload) CreditLeg() TransferLegPayload {
+	return TransferLegPayload{
+		AccountID:             p.ToAccountID,
+		UserID:                p.ToUserID,
+		TransactionID:         p.CreditTransactionID,
+		Direction:             Credit,
+		Amount:                p.CreditedAmount,
+		CounterpartyAccountID: p.FromAccountID,
+		ExchangeRate:          p.ExchangeRate,
+		Balance:               p.DestinationBalance,
+	}
+}
+
+// TransactionReversedPayload is the payload of EventTransactionReversed, a reversed transfer records one event per leg
+type TransactionReversedPayload struct {
+	AccountID     string           `json:"account_id"`
+	UserID        string           `json:"user_id"`
+	TransactionID string           `json:"transaction_id"`
+	ReversalOf    string           `json:"reversal_of"`
+	Direction     PostingDirection `json:"direction"`
+	Amount        types.Money      `json:"amount"`
+	Balance       types.Money      `json:"balance"` // balance after the change
+}
+
+// CardStatusChangedPayload is the payload of EventCardStatusChanged
+type CardStatusChangedPayload struct {
+	CardID         string `json:"card_id"`
+	UserID         string `json:"user_id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+}
+
+// UserIDs returns the users an event concerns, e.g. the sender and the recipient of a transfer
+func (e *DomainEvent) UserIDs() []string {
+	var users struct {
+		UserID     string `json:"user_id"`
+		FromUserID string `json:"from_user_id"`
+		ToUserID   string `json:"to_user_id"`
+	}
+	if err := json.Unmarshal(e.Payload, &users); err != nil {
+		return nil
+	}
+
+	userIDs := []string{}
+	for _, userID := range []string{users.UserID, users.FromUserID, users.ToUserID} {
+		if userID != "" && (len(userIDs) == 0 || userIDs[len(userIDs)-1] != userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
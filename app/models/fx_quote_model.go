@@ -0,0 +1,26 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+// FXQuote represents the fx_quotes table. A quote locks the rate and both amounts of a conversion for one user
+// until it expires, and can be used by a single transfer.
+type FXQuote struct {
+	QuoteID      string      `db:"quote_id" json:"quote_id"`
+	UserID       string      `db:"user_id" json:"user_id"`
+	FromCurrency string      `db:"from_currency" json:"from_currency"`
+	ToCurrency   string      `db:"to_currency" json:"to_currency"`
+	Rate         types.Rate  `db:"rate" json:"rate" swaggertype:"string" example:"36.50000000"` // units of to_currency bought by one unit of from_currency
+	SourceAmount types.Money `db:"source_amount" json:"source_amount"`                          // debited from the source account
+	TargetAmount types.Money `db:"target_amount" json:"target_amount"`                          // credited to the destination account
+	ExpiresAt    time.Time   `db:"expires_at" json:"expires_at"`
+	UsedAt       *time.Time  `db:"used_at" json:"used_at"`
+	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
+}
+
+// IsExpired reports whether the quote can no longer be used at the given time
+func (q *FXQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
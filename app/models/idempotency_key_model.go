@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyProcessing IdempotencyStatus = "processing"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
+// IdempotencyKey represents the idempotency_keys table, keys are scoped per user
+type IdempotencyKey struct {
+	UserID         string            `db:"user_id" json:"user_id"`
+	IdempotencyKey string            `db:"idempotency_key" json:"idempotency_key"`
+	RequestHash    string            `db:"request_hash" json:"request_hash"`           // sha256 of method, path and body
+	Status         IdempotencyStatus `db:"status" json:"status"`                       // processing, completed
+	LockedUntil    *time.Time        `db:"locked_until" json:"locked_until,omitempty"` // lease of the request processing the key
+	ResponseStatus int               `db:"response_status" json:"response_status"`
+	ResponseBody   []byte            `db:"response_body" json:"response_body"`
+	CreatedAt      time.Time         `db:"created_at" json:"created_at"`
+	ExpiresAt      time.Time         `db:"expires_at" json:"expires_at"`
+}
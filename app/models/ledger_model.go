@@ -0,0 +1,60 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+type PostingDirection string
+
+const (
+	Debit  PostingDirection = "debit"  // decreases the account balance
+	Credit PostingDirection = "credit" // increases the account balance
+)
+
+type JournalEntryType string
+
+const (
+	DepositEntry        JournalEntryType = "deposit"
+	WithdrawalEntry     JournalEntryType = "withdrawal"
+	TransferEntry       JournalEntryType = "transfer"
+	ReversalEntry       JournalEntryType = "reversal"
+	OpeningBalanceEntry JournalEntryType = "opening-balance"
+)
+
+// LedgerExternalAccountPrefix prefixes the per-currency system account that money enters and leaves the bank through.
+// Deposits and withdrawals post their counter leg to "ledger:external:<currency>".
+const LedgerExternalAccountPrefix = "ledger:external:"
+
+// LedgerFXAccountPrefix prefixes the per-currency system account that cross-currency transfers convert through.
+// A THB to USD transfer credits "ledger:fx:THB" and debits "ledger:fx:USD".
+const LedgerFXAccountPrefix = "ledger:fx:"
+
+// JournalEntry represents the journal_entries table. Entries are immutable once posted.
+type JournalEntry struct {
+	EntryID     string           `db:"entry_id" json:"entry_id"`
+	EntryType   JournalEntryType `db:"entry_type" json:"entry_type"`     // deposit, withdrawal, transfer, reversal, opening-balance
+	ReferenceID string           `db:"reference_id" json:"reference_id"` // transaction_id that caused the entry
+	Description string           `db:"description" json:"description"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+
+	Postings []*LedgerPosting `db:"-" json:"postings"`
+}
+
+// LedgerPosting represents the ledger_postings table, one debit or credit leg of a journal entry
+type LedgerPosting struct {
+	PostingID    int64            `db:"posting_id" json:"posting_id"`
+	EntryID      string           `db:"entry_id" json:"entry_id"`
+	AccountID    string           `db:"account_id" json:"account_id"`
+	Direction    PostingDirection `db:"direction" json:"direction"`         // debit, credit
+	Amount       types.Money      `db:"amount" json:"amount"`               // always positive
+	BalanceAfter types.Money      `db:"balance_after" json:"balance_after"` // running balance of the account after this posting
+	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+}
+
+// TrialBalance holds the total debits and credits posted in one currency
+type TrialBalance struct {
+	Currency     string      `db:"currency" json:"currency"`
+	TotalDebits  types.Money `db:"total_debits" json:"total_debits"`
+	TotalCredits types.Money `db:"total_credits" json:"total_credits"`
+}
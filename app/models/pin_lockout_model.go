@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PinLockout represents the pin_lockouts table, the failed PIN verifications of a user since the last successful one
+type PinLockout struct {
+	UserID         string     `db:"user_id" json:"user_id"`
+	FailedAttempts int        `db:"failed_attempts" json:"failed_attempts"`
+	LastFailedAt   *time.Time `db:"last_failed_at" json:"last_failed_at,omitempty"`
+	LockedUntil    *time.Time `db:"locked_until" json:"locked_until,omitempty"` // temporary lockout
+	LockedAt       *time.Time `db:"locked_at" json:"locked_at,omitempty"`       // permanent lock, lifted by a PIN reset
+}
+
+// PinLockState describes whether a user can verify the PIN right now
+type PinLockState struct {
+	FailedAttempts int        `json:"failed_attempts"`
+	Locked         bool       `json:"locked"`
+	Permanent      bool       `json:"permanent"`          // the PIN has to be reset
+	RetryAt        *time.Time `json:"retry_at,omitempty"` // when the next attempt is allowed, unless locked for good
+}
+
+// PinAttempt is a PIN verification counted as failed before the PIN is checked, so that concurrent guesses cannot
+// all pass the lockout checks. It stays counted unless the PIN turns out right or no guess was made. An attempt
+// counted against the IP address only has no UserID.
+type PinAttempt struct {
+	UserID         string
+	IPAddress      string
+	WindowStart    time.Time     // the window the IP address failure was counted in
+	CountedInCache bool          // whether the IP address failure was counted in the cache or in the database
+	Previous       *PinLockout   // the failed attempts of the user before this one
+	State          *PinLockState // the lock state once this attempt is counted as failed
+}
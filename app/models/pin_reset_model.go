@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PinResetCode represents the pin_reset_codes table, the outstanding one-time code to reset the PIN of a user
+type PinResetCode struct {
+	UserID    string    `db:"user_id" json:"user_id"`
+	CodeHash  string    `db:"code_hash" json:"-"`
+	Attempts  int       `db:"attempts" json:"attempts"` // wrong codes entered
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// IsExpired reports whether the code can no longer be used at the given time
+func (c *PinResetCode) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
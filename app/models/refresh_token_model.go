@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RefreshToken represents the refresh_tokens table. Tokens are stored hashed and belong to a family, the chain
+// of tokens rotated from one sign-in of a user on a device.
+type RefreshToken struct {
+	TokenHash string     `db:"token_hash" json:"token_hash"` // sha256 of the token handed to the client
+	FamilyID  string     `db:"family_id" json:"family_id"`
+	UserID    string     `db:"user_id" json:"user_id"`
+	DeviceID  string     `db:"device_id" json:"device_id"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at,omitempty"`       // set when the token was rotated
+	RevokedAt *time.Time `db:"revoked_at" json:"revoked_at,omitempty"` // set on logout or reuse of the family
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// IsExpired reports whether the token can no longer be renewed at the given time
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+type ScheduleFrequency string
+
+const (
+	FrequencyOnce    ScheduleFrequency = "once"
+	FrequencyDaily   ScheduleFrequency = "daily"
+	FrequencyWeekly  ScheduleFrequency = "weekly"
+	FrequencyMonthly ScheduleFrequency = "monthly" // runs on the day of month of start_at, clamped to the last day of shorter months
+)
+
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"
+	SchedulePaused    ScheduleStatus = "paused"
+	ScheduleCompleted ScheduleStatus = "completed"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+type InsufficientFundsPolicy string
+
+const (
+	SkipOnInsufficientFunds  InsufficientFundsPolicy = "skip"  // record the occurrence as skipped and wait for the next one
+	RetryOnInsufficientFunds InsufficientFundsPolicy = "retry" // retry the occurrence with backoff
+)
+
+// ScheduledTransfer represents the scheduled_transfers table, a standing order between two accounts
+type ScheduledTransfer struct {
+	*BaseModel
+	ScheduleID              string                  `db:"schedule_id" json:"schedule_id"`
+	UserID                  string                  `db:"user_id" json:"user_id"`
+	FromAccountID           string                  `db:"from_account_id" json:"from_account_id"`
+	ToAccountID             string                  `db:"to_account_id" json:"to_account_id"`
+	Amount                  types.Money             `db:"amount" json:"amount"` // currency is stored in the currency column
+	Description             string                  `db:"description" json:"description"`
+	Frequency               ScheduleFrequency       `db:"frequency" json:"frequency"` // once, daily, weekly, monthly
+	StartAt                 time.Time               `db:"start_at" json:"start_at"`
+	EndAt                   *time.Time              `db:"end_at" json:"end_at"`
+	NextRunAt               time.Time               `db:"next_run_at" json:"next_run_at"`         // occurrence the next execution is for
+	NextAttemptAt           time.Time               `db:"next_attempt_at" json:"next_attempt_at"` // next_run_at delayed by retry backoff
+	LastRunAt               *time.Time              `db:"last_run_at" json:"last_run_at"`
+	Status                  ScheduleStatus          `db:"status" json:"status"` // active, paused, completed, cancelled
+	InsufficientFundsPolicy InsufficientFundsPolicy `db:"insufficient_funds_policy" json:"insufficient_funds_policy"`
+	RetryCount              int                     `db:"retry_count" json:"retry_count"`
+
+	// Set while a scheduler instance is executing the schedule
+	LeaseOwner     *string    `db:"lease_owner" json:"-"`
+	LeaseExpiresAt *time.Time `db:"lease_expires_at" json:"-"`
+}
+
+type ScheduleExecutionStatus string
+
+const (
+	ExecutionSucceeded ScheduleExecutionStatus = "succeeded"
+	ExecutionSkipped   ScheduleExecutionStatus = "skipped"
+	ExecutionRetrying  ScheduleExecutionStatus = "retrying"
+	ExecutionFailed    ScheduleExecutionStatus = "failed"
+)
+
+// ScheduledTransferExecution represents the scheduled_transfer_executions table, one row per attempt
+type ScheduledTransferExecution struct {
+	ExecutionID  string                  `db:"execution_id" json:"execution_id"`
+	ScheduleID   string                  `db:"schedule_id" json:"schedule_id"`
+	ScheduledFor time.Time               `db:"scheduled_for" json:"scheduled_for"`
+	ExecutedAt   time.Time               `db:"executed_at" json:"executed_at"`
+	Attempt      int                     `db:"attempt" json:"attempt"`
+	Status       ScheduleExecutionStatus `db:"status" json:"status"` // succeeded, skipped, retrying, failed
+	Message      string                  `db:"message" json:"message"`
+	CreatedAt    time.Time               `db:"created_at" json:"created_at"`
+}
+
+// ScheduledTransferUpdate holds the fields a user may change on a schedule, nil fields are left unchanged
+type ScheduledTransferUpdate struct {
+	Amount                  *types.Money             `json:"amount,omitempty"`
+	Description             *string                  `json:"description,omitempty"`
+	Frequency               *ScheduleFrequency       `json:"frequency,omitempty"`
+	EndAt                   *time.Time               `json:"end_at,omitempty"`
+	Status                  *ScheduleStatus          `json:"status,omitempty"` // active or paused
+	InsufficientFundsPolicy *InsufficientFundsPolicy `json:"insufficient_funds_policy,omitempty"`
+}
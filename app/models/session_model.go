@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Session represents the user_sessions table, a sign-in of a user on a device. The session id is also the
+// family id of the refresh tokens of the sign-in and the sid claim of its access tokens.
+type Session struct {
+	SessionID  string     `db:"session_id" json:"session_id"`
+	UserID     string     `db:"user_id" json:"-"`
+	DeviceID   string     `db:"device_id" json:"device_id"`
+	DeviceName string     `db:"device_name" json:"device_name" example:"Jane's iPhone"`
+	Platform   string     `db:"platform" json:"platform" example:"ios"`
+	UserAgent  string     `db:"user_agent" json:"user_agent"`
+	IPAddress  string     `db:"ip_address" json:"ip_address"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	LastSeenAt time.Time  `db:"last_seen_at" json:"last_seen_at"` // last sign-in or token renewal
+	RevokedAt  *time.Time `db:"revoked_at" json:"-"`
+	Current    bool       `db:"-" json:"current"` // whether the listing was requested from this session
+}
+
+// DeviceInfo describes the device a user signs in from
+type DeviceInfo struct {
+	DeviceID   string
+	DeviceName string
+	Platform   string
+	UserAgent  string
+	IPAddress  string
+}
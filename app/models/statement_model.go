@@ -0,0 +1,43 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+// StatementFormat is the file format of an account statement
+type StatementFormat string
+
+const (
+	StatementCSV  StatementFormat = "csv"
+	StatementJSON StatementFormat = "json"
+	StatementPDF  StatementFormat = "pdf"
+)
+
+// Statement is the account statement of a period of whole days, From to To inclusive. Its transactions are written
+// one StatementLine at a time, the closing balance is known once the last one is written.
+type Statement struct {
+	AccountID      string
+	AccountNumber  string
+	Currency       string
+	From           time.Time // midnight starting the first day, in configs.STATEMENT_LOCATION
+	To             time.Time // midnight starting the last day
+	OpeningBalance types.Money
+	ClosingBalance types.Money
+}
+
+// End is the midnight ending the last day of the statement
+func (s *Statement) End() time.Time {
+	return s.To.AddDate(0, 0, 1)
+}
+
+// StatementLine is a transaction of a statement with the balance of the account after it
+type StatementLine struct {
+	TransactionID   string           `json:"transaction_id"`
+	Date            time.Time        `json:"date"`
+	TransactionType string           `json:"transaction_type" example:"transfer"`
+	Direction       PostingDirection `json:"direction" example:"debit"`
+	Description     string           `json:"description"`
+	Amount          types.Money      `json:"amount"`
+	Balance         types.Money      `json:"balance"` // running balance after the transaction
+}
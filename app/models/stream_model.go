@@ -0,0 +1,82 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamEvent names a message pushed to the real-time stream of a user
+type StreamEvent string
+
+const (
+	StreamBalance     StreamEvent = "balance"     // the balance of an account changed, data is a BalanceUpdate
+	StreamTransaction StreamEvent = "transaction" // a transaction was posted, data is a TransactionNotice
+	StreamReset       StreamEvent = "reset"       // messages since the last event id are no longer kept, the client reloads its accounts
+)
+
+// StreamMessage is a message of the real-time stream of a user. IDs are "<unix ms>-<sequence>", increasing within
+// the stream of a user, and a client resumes after the last ID it received.
+type StreamMessage struct {
+	ID    string          `json:"id" example:"1760000000000-0"`
+	Event StreamEvent     `json:"event" example:"balance"`
+	Data  json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// BalanceUpdate is the data of a StreamBalance message
+type BalanceUpdate struct {
+	AccountID string      `json:"account_id"`
+	Balance   types.Money `json:"balance"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TransactionNotice is the data of a StreamTransaction message
+type TransactionNotice struct {
+	TransactionID         string           `json:"transaction_id"`
+	AccountID             string           `json:"account_id"`
+	TransactionType       TransactionType  `json:"transaction_type"`
+	Direction             PostingDirection `json:"direction"`
+	Amount                types.Money      `json:"amount"`
+	CounterpartyAccountID string           `json:"counterparty_account_id,omitempty"` // the other account of a transfer
+	OccurredAt            time.Time        `json:"occurred_at"`
+}
+
+// CompareStreamIDs orders two stream message IDs, it returns -1, 0 or +1. IDs that cannot be parsed sort first.
+func CompareStreamIDs(a, b string) int {
+	aMillis, aSequence, _ := ParseStreamID(a)
+	bMillis, bSequence, _ := ParseStreamID(b)
+	if aMillis != bMillis {
+		return compareUint(aMillis, bMillis)
+	}
+	return compareUint(aSequence, bSequence)
+}
+
+// ParseStreamID splits a stream message ID into its time and sequence
+func ParseStreamID(id string) (millis, sequence uint64, ok bool) {
+	rawMillis, rawSequence, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	millis, err := strconv.ParseUint(rawMillis, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	sequence, err = strconv.ParseUint(rawSequence, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return millis, sequence, true
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
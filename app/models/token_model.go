@@ -3,4 +3,5 @@ package models
 // Renew struct to describe refresh token object.
 type Renew struct {
 	RefreshToken string `json:"refresh_token"`
+	DeviceID     string `json:"device_id"` // device the refresh token was issued to
 }
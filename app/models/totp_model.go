@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserTOTP represents the user_totp table, the TOTP authenticator of a user
+type UserTOTP struct {
+	UserID       string     `db:"user_id" json:"user_id"`
+	Secret       string     `db:"secret" json:"-"` // base32, shared with the authenticator app
+	EnabledAt    *time.Time `db:"enabled_at" json:"enabled_at,omitempty"`
+	LastUsedStep *int64     `db:"last_used_step" json:"-"` // time step of the last accepted code
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+}
+
+// TOTPEnrollment is returned once when a user sets up an authenticator app
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// URI, usually shown as a QR code
+}
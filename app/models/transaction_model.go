@@ -1,22 +1,73 @@
 package models
 
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
 type TransactionType string
 
 const (
 	Deposit    TransactionType = "deposit"
 	Withdrawal TransactionType = "withdrawal"
 	Transfer   TransactionType = "transfer"
+	Reversal   TransactionType = "reversal"
 )
 
 // Transaction represents the transactions table
 type Transaction struct {
 	*BaseModel
-	TransactionID   string  `db:"transaction_id" json:"transaction_id" validate:"required"`
-	AccountID       string  `db:"account_id" json:"account_id" validate:"required"`
-	UserID          string  `db:"user_id" json:"user_id" validate:"required"`
-	Name            string  `db:"name" json:"name"`
-	Image           string  `db:"image" json:"image"`
-	IsBank          bool    `db:"isBank" json:"is_bank"`
-	Amount          float64 `db:"amount" json:"amount" validate:"required"`
-	TransactionType string  `db:"transaction_type" json:"transaction_type" validate:"required"` // deposit, withdrawal, transfer
+	TransactionID   string      `db:"transaction_id" json:"transaction_id" validate:"required"`
+	AccountID       string      `db:"account_id" json:"account_id" validate:"required"`
+	UserID          string      `db:"user_id" json:"user_id" validate:"required"`
+	Name            string      `db:"name" json:"name"`
+	Image           string      `db:"image" json:"image"`
+	IsBank          bool        `db:"isBank" json:"is_bank"`
+	Amount          types.Money `db:"amount" json:"amount" validate:"required"`                     // currency is stored in the currency column
+	TransactionType string      `db:"transaction_type" json:"transaction_type" validate:"required"` // deposit, withdrawal, transfer, reversal
+
+	Direction           PostingDirection `db:"direction" json:"direction"`                                        // debit decreases the account balance, credit increases it
+	LinkedTransactionID *string          `db:"linked_transaction_id" json:"linked_transaction_id,omitempty"`      // other leg of a transfer
+	ReversalOf          *string          `db:"reversal_of" json:"reversal_of,omitempty"`                          // transaction this one reverses
+	ReversedAmount      types.Money      `db:"reversed_amount" json:"reversed_amount"`                            // total refunded so far
+	ExchangeRate        *types.Rate      `db:"exchange_rate" json:"exchange_rate,omitempty" swaggertype:"string"` // rate applied to a cross-currency transfer, source to destination currency
+	FXQuoteID           *string          `db:"fx_quote_id" json:"fx_quote_id,omitempty"`                          // quote a cross-currency transfer was executed at
+}
+
+// ReversalResult holds the reversed transaction and the compensating transactions created for it
+type ReversalResult struct {
+	Original  *Transaction   `json:"original"`
+	Reversals []*Transaction `json:"reversals"`
+}
+
+// TransactionCursor is the position of a transaction in a listing ordered by creation time, the transaction ID
+// breaks ties between transactions created in the same second
+type TransactionCursor struct {
+	CreatedAt     time.Time
+	TransactionID string
+}
+
+// TransactionSort is the order of a transaction listing by creation time
+type TransactionSort string
+
+const (
+	SortNewest TransactionSort = "newest"
+	SortOldest TransactionSort = "oldest"
+)
+
+// TransactionFilter selects the transactions of a user, empty fields match any transaction. MinAmount and
+// MaxAmount are decimals compared whatever the currency, From is inclusive and To exclusive, Query matches part
+// of the name. After continues a listing after the last transaction of the previous page in the same sort.
+type TransactionFilter struct {
+	UserID          string
+	AccountID       string
+	TransactionType string
+	MinAmount       string
+	MaxAmount       string
+	From            *time.Time
+	To              *time.Time
+	Query           string
+	Sort            TransactionSort
+	After           *TransactionCursor
+	Limit           int
 }
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+// TransferLimit represents the transfer_limits table. An empty AccountType or UserID matches any,
+// a nil limit is inherited from a less specific row.
+type TransferLimit struct {
+	AccountType    string       `db:"account_type" json:"account_type"`
+	UserID         string       `db:"user_id" json:"user_id"`
+	Currency       string       `db:"currency" json:"currency"`
+	PerTransaction *types.Money `db:"per_transaction_limit" json:"per_transaction_limit"`
+	Daily          *types.Money `db:"daily_limit" json:"daily_limit"`
+	Monthly        *types.Money `db:"monthly_limit" json:"monthly_limit"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+// Specificity orders limits from the least to the most specific, user overrides beat account type defaults
+func (l *TransferLimit) Specificity() int {
+	specificity := 0
+	if l.UserID != "" {
+		specificity += 2
+	}
+	if l.AccountType != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// LimitWindow is the usage of a daily or monthly limit. Limit and Remaining are nil when the window is unlimited.
+type LimitWindow struct {
+	Limit     *types.Money `json:"limit"`
+	Used      types.Money  `json:"used"`
+	Remaining *types.Money `json:"remaining"`
+	ResetsAt  time.Time    `json:"resets_at"`
+}
+
+// AccountLimits is the effective limits of an account and the allowance left
+type AccountLimits struct {
+	AccountID      string       `json:"account_id"`
+	Currency       string       `json:"currency"`
+	PerTransaction *types.Money `json:"per_transaction"`
+	Daily          LimitWindow  `json:"daily"`
+	Monthly        LimitWindow  `json:"monthly"`
+	Available      *types.Money `json:"available"` // largest amount that can leave the account right now, nil when unlimited
+}
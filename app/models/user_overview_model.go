@@ -0,0 +1,11 @@
+package models
+
+import "backend-developer-assignment/pkg/types"
+
+// UserOverview is what staff see of a user on the admin API
+type UserOverview struct {
+	User       *User                   `json:"user"`
+	Roles      []types.Role            `json:"roles"`
+	Accounts   []*AccountWithDetails   `json:"accounts"`
+	DebitCards []*DebitCardWithDetails `json:"debit_cards"`
+}
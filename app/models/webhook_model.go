@@ -0,0 +1,124 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WebhookScope selects the events sent to an endpoint
+type WebhookScope string
+
+const (
+	WebhookScopeUser WebhookScope = "user" // events concerning the user owning the endpoint
+	WebhookScopeAll  WebhookScope = "all"  // events of every user, endpoints registered by staff
+)
+
+// WebhookDeliveryStatus is the state of a delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // waiting for its next attempt
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // the endpoint answered with a 2xx status
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"      // every attempt failed, it is only sent again when replayed
+)
+
+// EventTypeList is a list of event types stored comma separated
+type EventTypeList []EventType
+
+// Value implements driver.Valuer
+func (l EventTypeList) Value() (driver.Value, error) {
+	types := make([]string, len(l))
+	for i, eventType := range l {
+		types[i] = string(eventType)
+	}
+	return strings.Join(types, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (l *EventTypeList) Scan(src interface{}) error {
+	var value string
+	switch src := src.(type) {
+	case nil:
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	default:
+		return fmt.Errorf("cannot scan %T into EventTypeList", src)
+	}
+
+	*l = EventTypeList{}
+	for _, eventType := range strings.Split(value, ",") {
+		if eventType != "" {
+			*l = append(*l, EventType(eventType))
+		}
+	}
+	return nil
+}
+
+// Contains reports whether an endpoint subscribed to the list receives events of the type, an empty list
+// subscribes to every type
+func (l EventTypeList) Contains(eventType EventType) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, subscribed := range l {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpoint represents the webhook_endpoints table, a URL partner apps receive events at
+type WebhookEndpoint struct {
+	EndpointID  string        `db:"endpoint_id" json:"endpoint_id"`
+	UserID      string        `db:"user_id" json:"user_id"` // the user or staff member who registered it
+	Scope       WebhookScope  `db:"scope" json:"scope" example:"user"`
+	URL         string        `db:"url" json:"url" example:"https://partner.example.com/webhooks"`
+	Description string        `db:"description" json:"description"`
+	EventTypes  EventTypeList `db:"event_types" json:"event_types" swaggertype:"array,string" example:"TransferCompleted,CardStatusChanged"`
+	Secret      string        `db:"secret" json:"-"` // signs the deliveries, only shown when the endpoint is registered
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+}
+
+// WebhookDelivery represents the webhook_deliveries table, an event sent to an endpoint and the result of its
+// last attempt. A delivery is kept after it succeeded or died so that it can be looked up and replayed.
+type WebhookDelivery struct {
+	DeliveryID     int64                 `db:"delivery_id" json:"delivery_id"`
+	EndpointID     string                `db:"endpoint_id" json:"endpoint_id"`
+	EventID        string                `db:"event_id" json:"event_id"`
+	EventType      EventType             `db:"event_type" json:"event_type"`
+	Payload        json.RawMessage       `db:"payload" json:"payload" swaggertype:"object"` // the body sent to the endpoint
+	Status         WebhookDeliveryStatus `db:"status" json:"status" example:"delivered"`
+	Attempts       int                   `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time             `db:"next_attempt_at" json:"next_attempt_at"`
+	LastStatusCode int                   `db:"last_status_code" json:"last_status_code"` // 0 when no response was received
+	LastError      string                `db:"last_error" json:"last_error"`
+	DeliveredAt    *time.Time            `db:"delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time             `db:"created_at" json:"created_at"`
+}
+
+// WebhookDispatch is a delivery claimed for an attempt with the endpoint it is sent to
+type WebhookDispatch struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// WebhookDeliveryFilter selects deliveries of an endpoint, newest first. BeforeID continues a listing after the
+// last delivery of the previous page.
+type WebhookDeliveryFilter struct {
+	Status   WebhookDeliveryStatus
+	BeforeID int64
+	Limit    int
+}
+
+// WebhookEndpointWithSecret is a registered endpoint with its signing secret, the secret is only returned once
+type WebhookEndpointWithSecret struct {
+	*WebhookEndpoint
+	Secret string `json:"secret" example:"whsec_5f3a..."`
+}
@@ -26,11 +26,13 @@ type AccountRepository interface {
 	SetMainAccount(accountID, userID string) error
 	UpdateAccount(account *models.Account) error
 	UpdateAccountDetail(detail *models.AccountDetail) error
-	UpdateAccountBalance(accountID string, updateFn func(currentBalance float64) (float64, error)) error
+	UpdateAccountBalance(accountID string, updateFn func(currentBalance types.Money) (types.Money, error)) error
+	FreezeAccount(accountID, userID string) (bool, error)
+	UnfreezeAccount(accountID string) (bool, error)
 
 	// Transfer operations
-	TransferFunds(fromAccountID, toAccountID string, amount float64,
-		updateFn func(sourceBalance, destBalance float64) (*types.TransferResult, error)) error
+	TransferFunds(fromAccountID, toAccountID string, amount types.Money,
+		updateFn func(sourceBalance, destBalance types.Money) (*types.TransferResult, error)) error
 
 	// Create operations
 	CreateAccount(account *models.AccountWithDetails) error
@@ -51,6 +53,11 @@ func NewAccountRepository(db DB) AccountRepository {
 	}
 }
 
+// heldAmountSubquery sums the active holds of the account in the enclosing query that have not expired at the time
+// bound to its placeholder, see HoldRepository.GetHeldAmount
+const heldAmountSubquery = `COALESCE((SELECT SUM(h.amount) FROM account_holds h
+				WHERE h.account_id = a.account_id AND h.status = 'active' AND h.expires_at > ?), 0)`
+
 // GetAccountWithDetailByID retrieves a complete account with all related information by ID
 func (r *AccountRepositoryImpl) GetAccountWithDetailByID(accountID string) (*models.AccountWithDetails, error) {
 	account := &models.AccountWithDetails{}
@@ -59,7 +66,8 @@ func (r *AccountRepositoryImpl) GetAccountWithDetailByID(accountID string) (*mod
 		SELECT 
 			a.account_id, a.user_id, a.type, a.currency, a.account_number, a.issuer, a.created_at, a.updated_at, a.deleted_at,
 			d.color, d.is_main_account, d.progress,
-			b.amount
+			CONCAT(b.amount, ' ', a.currency) AS amount,
+			CONCAT(b.amount - ` + heldAmountSubquery + `, ' ', a.currency) AS available_amount
 		FROM 
 			accounts a
 		LEFT JOIN 
@@ -70,7 +78,7 @@ func (r *AccountRepositoryImpl) GetAccountWithDetailByID(accountID string) (*mod
 			a.account_id = ? AND a.deleted_at IS NULL
 	`
 
-	err := r.DB.Get(account, query, accountID)
+	err := r.DB.Get(account, query, time.Now(), accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +99,8 @@ func (r *AccountRepositoryImpl) GetAccountsWithDetailByUserID(userID string) ([]
 		SELECT 
 			a.account_id, a.user_id, a.type, a.currency, a.account_number, a.issuer, a.created_at, a.updated_at,
 			d.color, d.is_main_account, d.progress,
-			b.amount,
+			CONCAT(b.amount, ' ', a.currency) AS amount,
+			CONCAT(b.amount - ` + heldAmountSubquery + `, ' ', a.currency) AS available_amount,
 			f.flag_id, f.flag_type, f.flag_value
 		FROM 
 			accounts a
@@ -106,7 +115,7 @@ func (r *AccountRepositoryImpl) GetAccountsWithDetailByUserID(userID string) ([]
 	`
 
 	// Execute the query
-	rows, err := r.DB.Query(query, userID)
+	rows, err := r.DB.Query(query, time.Now(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +133,7 @@ func (r *AccountRepositoryImpl) GetAccountsWithDetailByUserID(userID string) ([]
 			&account.AccountID, &account.UserID, &account.Type, &account.Currency,
 			&account.AccountNumber, &account.Issuer, &account.CreatedAt, &account.UpdatedAt,
 			&account.Color, &account.IsMainAccount, &account.Progress,
-			&account.Amount, &flag.FlagID, &flag.FlagType, &flag.FlagValue,
+			&account.Amount, &account.AvailableAmount, &flag.FlagID, &flag.FlagType, &flag.FlagValue,
 		)
 		if err != nil {
 			return nil, err
@@ -204,7 +213,12 @@ func (r *AccountRepositoryImpl) GetAccountDetailByID(accountID string) (*models.
 // GetAccountBalanceByID retrieves account balance by account ID
 func (r *AccountRepositoryImpl) GetAccountBalanceByID(accountID string) (*models.AccountBalance, error) {
 	balance := &models.AccountBalance{}
-	query := `SELECT account_id, user_id, amount FROM account_balances WHERE account_id = ? AND deleted_at IS NULL`
+	query := `
+		SELECT b.account_id, b.user_id, CONCAT(b.amount, ' ', a.currency) AS amount
+		FROM account_balances b
+		JOIN accounts a ON a.account_id = b.account_id
+		WHERE b.account_id = ? AND b.deleted_at IS NULL
+	`
 	err := r.DB.Get(balance, query, accountID)
 	if err != nil {
 		return nil, err
@@ -407,11 +421,17 @@ func (r *AccountRepositoryImpl) UpdateAccountDetail(detail *models.AccountDetail
 }
 
 // UpdateAccountBalance updates an account balance with proper locking to prevent race conditions
-func (r *AccountRepositoryImpl) UpdateAccountBalance(accountID string, updateFn func(currentBalance float64) (float64, error)) error {
+func (r *AccountRepositoryImpl) UpdateAccountBalance(accountID string, updateFn func(currentBalance types.Money) (types.Money, error)) error {
 	return runInTx(r.DB, func(tx *sqlx.Tx) error {
 		// Get the current balance with a row lock
-		var currentBalance float64
-		query := `SELECT amount FROM account_balances WHERE account_id = ? FOR UPDATE`
+		var currentBalance types.Money
+		query := `
+			SELECT CONCAT(b.amount, ' ', a.currency)
+			FROM account_balances b
+			JOIN accounts a ON a.account_id = b.account_id
+			WHERE b.account_id = ?
+			FOR UPDATE
+		`
 		err := tx.Get(&currentBalance, query, accountID)
 		if err != nil {
 			return err
@@ -519,6 +539,40 @@ func (r *AccountRepositoryImpl) CreateAccount(account *models.AccountWithDetails
 	})
 }
 
+// FreezeAccount sets the frozen system flag of an account, it returns false when the account is already frozen
+func (r *AccountRepositoryImpl) FreezeAccount(accountID, userID string) (bool, error) {
+	query := `INSERT INTO account_flags (account_id, user_id, flag_type, flag_value)
+			  SELECT ?, ?, ?, ? FROM DUAL WHERE NOT EXISTS (
+			  SELECT 1 FROM account_flags WHERE account_id = ? AND flag_type = ? AND flag_value = ? AND deleted_at IS NULL)`
+	result, err := r.DB.Exec(query, accountID, userID, models.AccountFlagSystem, models.AccountFlagFrozen,
+		accountID, models.AccountFlagSystem, models.AccountFlagFrozen)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// UnfreezeAccount removes the frozen system flag of an account, it returns false when the account is not frozen
+func (r *AccountRepositoryImpl) UnfreezeAccount(accountID string) (bool, error) {
+	query := `UPDATE account_flags SET deleted_at = ?
+			  WHERE account_id = ? AND flag_type = ? AND flag_value = ? AND deleted_at IS NULL`
+	result, err := r.DB.Exec(query, time.Now(), accountID, models.AccountFlagSystem, models.AccountFlagFrozen)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
 // DeleteAccount marks an account as deleted without removing it
 func (r *AccountRepositoryImpl) DeleteAccount(accountID string) error {
 	now := time.Now()
@@ -528,8 +582,8 @@ func (r *AccountRepositoryImpl) DeleteAccount(accountID string) error {
 }
 
 // TransferFunds transfers funds between accounts with proper locking to prevent race conditions
-func (r *AccountRepositoryImpl) TransferFunds(fromAccountID, toAccountID string, amount float64,
-	updateFn func(sourceBalance, destBalance float64) (*types.TransferResult, error)) error {
+func (r *AccountRepositoryImpl) TransferFunds(fromAccountID, toAccountID string, amount types.Money,
+	updateFn func(sourceBalance, destBalance types.Money) (*types.TransferResult, error)) error {
 
 	return runInTx(r.DB, func(tx *sqlx.Tx) error {
 		// Lock both accounts in a consistent order to prevent deadlocks
@@ -540,11 +594,17 @@ func (r *AccountRepositoryImpl) TransferFunds(fromAccountID, toAccountID string,
 		}
 
 		// Get the balances with row locks
-		var firstBalance, secondBalance float64
-		var sourceBalance, destBalance float64
+		var firstBalance, secondBalance types.Money
+		var sourceBalance, destBalance types.Money
 
 		// Lock first account
-		query := `SELECT account_id, amount FROM account_balances WHERE account_id = ? FOR UPDATE`
+		query := `
+			SELECT b.account_id, CONCAT(b.amount, ' ', a.currency)
+			FROM account_balances b
+			JOIN accounts a ON a.account_id = b.account_id
+			WHERE b.account_id = ?
+			FOR UPDATE
+		`
 		var firstAccountID string
 		err := tx.QueryRow(query, firstLockID).Scan(&firstAccountID, &firstBalance)
 		if err != nil {
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"strings"
+)
+
+// AuditLogRepository is an interface for audit log operations, entries can only be added and read
+type AuditLogRepository interface {
+	Create(entry *models.AuditLog) error
+	List(filter models.AuditLogFilter) ([]*models.AuditLog, error)
+}
+
+// AuditLogRepositoryImpl implements AuditLogRepository
+type AuditLogRepositoryImpl struct {
+	DB DB
+}
+
+// NewAuditLogRepository creates a new instance of AuditLogRepository
+func NewAuditLogRepository(db DB) AuditLogRepository {
+	return &AuditLogRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create appends an entry to the audit log
+func (r *AuditLogRepositoryImpl) Create(entry *models.AuditLog) error {
+	query := `INSERT INTO audit_logs (action, outcome, actor_id, actor_type, resource_type, resource_id, before_state, after_state, ip_address, request_id, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.DB.Exec(
+		query,
+		entry.Action,
+		entry.Outcome,
+		entry.ActorID,
+		entry.ActorType,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.Before,
+		entry.After,
+		entry.IPAddress,
+		entry.RequestID,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	entry.AuditID, err = result.LastInsertId()
+	return err
+}
+
+// List retrieves the entries matching the filter, newest first
+func (r *AuditLogRepositoryImpl) List(filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.ActorID != "" {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.ResourceType != "" {
+		conditions = append(conditions, "resource_type = ?")
+		args = append(args, filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		conditions = append(conditions, "resource_id = ?")
+		args = append(args, filter.ResourceID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "audit_id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	query := `SELECT audit_id, action, outcome, actor_id, actor_type, resource_type, resource_id, before_state, after_state, ip_address, request_id, created_at
+			  FROM audit_logs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY audit_id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	entries := []*models.AuditLog{}
+	err := r.DB.Select(&entries, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"backend-developer-assignment/app/models"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -11,6 +12,9 @@ import (
 type BannerRepository interface {
 	GetBannerByID(bannerID string) (*models.Banner, error)
 	GetBannersByUserID(userID string) ([]*models.Banner, error)
+	CreateBanner(banner *models.Banner) error
+	UpdateBanner(banner *models.Banner) error
+	DeleteBanner(bannerID string) (bool, error)
 }
 
 // BannerRepositoryImpl implements BannerRepository
@@ -28,7 +32,7 @@ func NewBannerRepository(db *sqlx.DB) BannerRepository {
 // GetBannerByID retrieves a banner by its ID
 func (r *BannerRepositoryImpl) GetBannerByID(bannerID string) (*models.Banner, error) {
 	banner := &models.Banner{}
-	query := `SELECT banner_id, user_id, title, description, image, created_at, updated_at FROM banners WHERE banner_id = ?`
+	query := `SELECT banner_id, user_id, title, description, image, created_at, updated_at FROM banners WHERE banner_id = ? AND deleted_at IS NULL`
 
 	err := r.db.Get(banner, query, bannerID)
 	if err != nil {
@@ -44,7 +48,7 @@ func (r *BannerRepositoryImpl) GetBannerByID(bannerID string) (*models.Banner, e
 // GetBannersByUserID retrieves all banners for a specific user
 func (r *BannerRepositoryImpl) GetBannersByUserID(userID string) ([]*models.Banner, error) {
 	banners := []*models.Banner{}
-	query := `SELECT banner_id, user_id, title, description, image, created_at, updated_at FROM banners WHERE user_id = ? ORDER BY created_at DESC`
+	query := `SELECT banner_id, user_id, title, description, image, created_at, updated_at FROM banners WHERE user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC`
 
 	err := r.db.Select(&banners, query, userID)
 	if err != nil {
@@ -53,3 +57,32 @@ func (r *BannerRepositoryImpl) GetBannersByUserID(userID string) ([]*models.Bann
 
 	return banners, nil
 }
+
+// CreateBanner adds a banner for a user
+func (r *BannerRepositoryImpl) CreateBanner(banner *models.Banner) error {
+	query := `INSERT INTO banners (banner_id, user_id, title, description, image) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.Exec(query, banner.BannerID, banner.UserID, banner.Title, banner.Description, banner.Image)
+	return err
+}
+
+// UpdateBanner updates the content of a banner
+func (r *BannerRepositoryImpl) UpdateBanner(banner *models.Banner) error {
+	query := `UPDATE banners SET title = ?, description = ?, image = ? WHERE banner_id = ? AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, banner.Title, banner.Description, banner.Image, banner.BannerID)
+	return err
+}
+
+// DeleteBanner marks a banner as deleted without removing it, it returns false when there is no such banner
+func (r *BannerRepositoryImpl) DeleteBanner(bannerID string) (bool, error) {
+	query := `UPDATE banners SET deleted_at = ? WHERE banner_id = ? AND deleted_at IS NULL`
+	result, err := r.db.Exec(query, time.Now(), bannerID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+)
+
+// ChallengeRepository is an interface for step-up authentication challenge operations
+type ChallengeRepository interface {
+	Create(challenge *models.Challenge) error
+	GetByID(challengeID string) (*models.Challenge, error)
+	IncrementAttempts(challengeID string) error
+	MarkConfirmed(challengeID string, now time.Time, maxAttempts int) (bool, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// ChallengeRepositoryImpl implements ChallengeRepository
+type ChallengeRepositoryImpl struct {
+	DB DB
+}
+
+// NewChallengeRepository creates a new instance of ChallengeRepository
+func NewChallengeRepository(db DB) ChallengeRepository {
+	return &ChallengeRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create stores a new challenge
+func (r *ChallengeRepositoryImpl) Create(challenge *models.Challenge) error {
+	query := `INSERT INTO challenges (challenge_id, user_id, action, payload, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		challenge.ChallengeID,
+		challenge.UserID,
+		challenge.Action,
+		challenge.Payload,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a challenge by ID
+func (r *ChallengeRepositoryImpl) GetByID(challengeID string) (*models.Challenge, error) {
+	challenge := &models.Challenge{}
+	query := `SELECT challenge_id, user_id, action, payload, attempts, expires_at, confirmed_at, created_at
+			  FROM challenges WHERE challenge_id = ?`
+	err := r.DB.Get(challenge, query, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// IncrementAttempts counts a wrong second factor
+func (r *ChallengeRepositoryImpl) IncrementAttempts(challengeID string) error {
+	query := `UPDATE challenges SET attempts = attempts + 1 WHERE challenge_id = ?`
+	_, err := r.DB.Exec(query, challengeID)
+	return err
+}
+
+// MarkConfirmed consumes a challenge that is unconfirmed, unexpired and below maxAttempts. It returns false without
+// error otherwise, the conditional update keeps concurrent confirmations from running the operation twice.
+func (r *ChallengeRepositoryImpl) MarkConfirmed(challengeID string, now time.Time, maxAttempts int) (bool, error) {
+	query := `UPDATE challenges SET confirmed_at = ?
+			  WHERE challenge_id = ? AND confirmed_at IS NULL AND expires_at > ? AND attempts < ?`
+	result, err := r.DB.Exec(query, now, challengeID, now, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteExpired removes challenges that can no longer be confirmed
+func (r *ChallengeRepositoryImpl) DeleteExpired(now time.Time) (int64, error) {
+	query := `DELETE FROM challenges WHERE expires_at <= ?`
+	result, err := r.DB.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -15,8 +15,15 @@ type DB interface {
 }
 
 type Adapters struct {
-	AccountRepository     AccountRepository
-	TransactionRepository TransactionRepository
+	AccountRepository           AccountRepository
+	TransactionRepository       TransactionRepository
+	LedgerRepository            LedgerRepository
+	TransferLimitRepository     TransferLimitRepository
+	FXRepository                FXRepository
+	HoldRepository              HoldRepository
+	DebitCardRepository         DebitCardRepository
+	OutboxRepository            OutboxRepository
+	ScheduledTransferRepository ScheduledTransferRepository
 }
 
 type TxProvider interface {
@@ -36,8 +43,15 @@ func NewTransactionProvider(db *sqlx.DB) *TransactionProvider {
 func (p *TransactionProvider) Transact(txFunc func(adapters Adapters) error) error {
 	return runInTx(p.db, func(tx *sqlx.Tx) error {
 		adapters := Adapters{
-			AccountRepository:     NewAccountRepository(tx),
-			TransactionRepository: NewTransactionRepository(tx),
+			AccountRepository:           NewAccountRepository(tx),
+			TransactionRepository:       NewTransactionRepository(tx),
+			LedgerRepository:            NewLedgerRepository(tx),
+			TransferLimitRepository:     NewTransferLimitRepository(tx),
+			FXRepository:                NewFXRepository(tx),
+			HoldRepository:              NewHoldRepository(tx),
+			DebitCardRepository:         NewDebitCardRepository(tx),
+			OutboxRepository:            NewOutboxRepository(tx),
+			ScheduledTransferRepository: NewScheduledTransferRepository(tx),
 		}
 
 		return txFunc(adapters)
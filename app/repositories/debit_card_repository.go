@@ -34,11 +34,11 @@ type DebitCardRepository interface {
 
 // DebitCardRepositoryImpl implements DebitCardRepository
 type DebitCardRepositoryImpl struct {
-	DB *sqlx.DB
+	DB DB
 }
 
 // NewDebitCardRepository creates a new instance of DebitCardRepository
-func NewDebitCardRepository(db *sqlx.DB) DebitCardRepository {
+func NewDebitCardRepository(db DB) DebitCardRepository {
 	return &DebitCardRepositoryImpl{
 		DB: db,
 	}
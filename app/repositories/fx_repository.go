@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/types"
+	"errors"
+	"time"
+)
+
+// ErrQuoteAlreadyUsed is returned when a quote is consumed by a second transfer
+var ErrQuoteAlreadyUsed = errors.New("fx quote already used")
+
+// FXRepository is an interface for exchange rate and quote operations
+type FXRepository interface {
+	GetRate(baseCurrency, quoteCurrency string) (types.Rate, error)
+	CreateQuote(quote *models.FXQuote) error
+	GetQuoteByIDForUpdate(quoteID string) (*models.FXQuote, error)
+	MarkQuoteUsed(quoteID string, usedAt time.Time) error
+}
+
+// FXRepositoryImpl implements FXRepository
+type FXRepositoryImpl struct {
+	DB DB
+}
+
+// NewFXRepository creates a new instance of FXRepository
+func NewFXRepository(db DB) FXRepository {
+	return &FXRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetRate retrieves the reference rate of one unit of baseCurrency in quoteCurrency
+func (r *FXRepositoryImpl) GetRate(baseCurrency, quoteCurrency string) (types.Rate, error) {
+	var rate types.Rate
+	query := `SELECT rate FROM fx_rates WHERE base_currency = ? AND quote_currency = ?`
+	err := r.DB.Get(&rate, query, baseCurrency, quoteCurrency)
+	if err != nil {
+		return types.Rate{}, err
+	}
+	return rate, nil
+}
+
+// CreateQuote adds a new quote
+func (r *FXRepositoryImpl) CreateQuote(quote *models.FXQuote) error {
+	quote.CreatedAt = time.Now()
+
+	query := `INSERT INTO fx_quotes (
+		quote_id, user_id, from_currency, to_currency, rate, source_amount, target_amount, expires_at, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		quote.QuoteID,
+		quote.UserID,
+		quote.FromCurrency,
+		quote.ToCurrency,
+		quote.Rate,
+		quote.SourceAmount,
+		quote.TargetAmount,
+		quote.ExpiresAt,
+		quote.CreatedAt,
+	)
+	return err
+}
+
+// GetQuoteByIDForUpdate retrieves a quote and locks it until the surrounding transaction ends
+func (r *FXRepositoryImpl) GetQuoteByIDForUpdate(quoteID string) (*models.FXQuote, error) {
+	quote := &models.FXQuote{}
+	query := `SELECT quote_id, user_id, from_currency, to_currency, rate,
+			  CONCAT(source_amount, ' ', from_currency) AS source_amount,
+			  CONCAT(target_amount, ' ', to_currency) AS target_amount,
+			  expires_at, used_at, created_at
+			  FROM fx_quotes WHERE quote_id = ? FOR UPDATE`
+	err := r.DB.Get(quote, query, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// MarkQuoteUsed consumes a quote, failing with ErrQuoteAlreadyUsed if another transfer used it first
+func (r *FXRepositoryImpl) MarkQuoteUsed(quoteID string, usedAt time.Time) error {
+	query := `UPDATE fx_quotes SET used_at = ? WHERE quote_id = ? AND used_at IS NULL`
+	result, err := r.DB.Exec(query, usedAt, quoteID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrQuoteAlreadyUsed
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+// HoldRepository is an interface for account hold operations
+type HoldRepository interface {
+	GetHoldsByAccountID(accountID string) ([]*models.AccountHold, error)
+	GetHoldByIDForUpdate(holdID string) (*models.AccountHold, error)
+	GetHeldAmount(accountID, currency string, now time.Time) (types.Money, error)
+	CreateHold(hold *models.AccountHold) error
+	UpdateHold(hold *models.AccountHold) error
+	ExpireHolds(now time.Time) (int64, error)
+}
+
+// HoldRepositoryImpl implements HoldRepository
+type HoldRepositoryImpl struct {
+	DB DB
+}
+
+// NewHoldRepository creates a new instance of HoldRepository
+func NewHoldRepository(db DB) HoldRepository {
+	return &HoldRepositoryImpl{
+		DB: db,
+	}
+}
+
+const holdColumns = `hold_id, account_id, user_id,
+			  CONCAT(amount, ' ', currency) AS amount,
+			  CONCAT(captured_amount, ' ', currency) AS captured_amount,
+			  description, status, expires_at, transaction_id, created_at, updated_at`
+
+// GetHoldsByAccountID retrieves the holds of an account, newest first
+func (r *HoldRepositoryImpl) GetHoldsByAccountID(accountID string) ([]*models.AccountHold, error) {
+	holds := []*models.AccountHold{}
+	query := `SELECT ` + holdColumns + ` FROM account_holds WHERE account_id = ? ORDER BY created_at DESC`
+	err := r.DB.Select(&holds, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// GetHoldByIDForUpdate retrieves a hold and locks it until the surrounding transaction ends
+func (r *HoldRepositoryImpl) GetHoldByIDForUpdate(holdID string) (*models.AccountHold, error) {
+	hold := &models.AccountHold{}
+	query := `SELECT ` + holdColumns + ` FROM account_holds WHERE hold_id = ? FOR UPDATE`
+	err := r.DB.Get(hold, query, holdID)
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// GetHeldAmount sums the active holds of an account that have not expired at the given time.
+// It is a locking read so holds committed after the surrounding transaction started are counted.
+func (r *HoldRepositoryImpl) GetHeldAmount(accountID, currency string, now time.Time) (types.Money, error) {
+	var held types.Money
+	query := `SELECT CONCAT(COALESCE(SUM(amount), 0), ' ', ?) FROM account_holds
+			  WHERE account_id = ? AND status = ? AND expires_at > ?
+			  FOR SHARE`
+	err := r.DB.Get(&held, query, currency, accountID, models.HoldActive, now)
+	if err != nil {
+		return types.Money{}, err
+	}
+	return held, nil
+}
+
+// CreateHold adds a new hold
+func (r *HoldRepositoryImpl) CreateHold(hold *models.AccountHold) error {
+	now := time.Now()
+	hold.CreatedAt = now
+	hold.UpdatedAt = now
+
+	query := `INSERT INTO account_holds (
+		hold_id, account_id, user_id, amount, captured_amount, currency, description, status, expires_at, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		hold.HoldID,
+		hold.AccountID,
+		hold.UserID,
+		hold.Amount,
+		hold.CapturedAmount,
+		hold.Amount.Currency,
+		hold.Description,
+		hold.Status,
+		hold.ExpiresAt,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	)
+	return err
+}
+
+// UpdateHold saves the status, captured amount and capture transaction of a hold
+func (r *HoldRepositoryImpl) UpdateHold(hold *models.AccountHold) error {
+	hold.UpdatedAt = time.Now()
+
+	query := `UPDATE account_holds SET status = ?, captured_amount = ?, transaction_id = ?, updated_at = ? WHERE hold_id = ?`
+	_, err := r.DB.Exec(query, hold.Status, hold.CapturedAmount, hold.TransactionID, hold.UpdatedAt, hold.HoldID)
+	return err
+}
+
+// ExpireHolds marks the active holds that expired before the given time and returns how many were marked
+func (r *HoldRepositoryImpl) ExpireHolds(now time.Time) (int64, error) {
+	query := `UPDATE account_holds SET status = ?, updated_at = ? WHERE status = ? AND expires_at <= ?`
+	result, err := r.DB.Exec(query, models.HoldExpired, now, models.HoldActive, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+)
+
+// IdempotencyRepository is an interface for idempotency key operations
+type IdempotencyRepository interface {
+	Create(record *models.IdempotencyKey) (bool, error)
+	GetByKey(userID, key string) (*models.IdempotencyKey, error)
+	Reclaim(record *models.IdempotencyKey, now time.Time) (bool, error)
+	Complete(record *models.IdempotencyKey) error
+	Delete(userID, key string) error
+}
+
+// IdempotencyRepositoryImpl implements IdempotencyRepository
+type IdempotencyRepositoryImpl struct {
+	DB DB
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository
+func NewIdempotencyRepository(db DB) IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create claims an idempotency key. It returns false without error when the key is already taken,
+// the primary key on (user_id, idempotency_key) makes the claim atomic across instances.
+func (r *IdempotencyRepositoryImpl) Create(record *models.IdempotencyKey) (bool, error) {
+	query := `INSERT IGNORE INTO idempotency_keys (user_id, idempotency_key, request_hash, status, locked_until, created_at, expires_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.DB.Exec(
+		query,
+		record.UserID,
+		record.IdempotencyKey,
+		record.RequestHash,
+		record.Status,
+		record.LockedUntil,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// GetByKey retrieves an idempotency key for a user
+func (r *IdempotencyRepositoryImpl) GetByKey(userID, key string) (*models.IdempotencyKey, error) {
+	record := &models.IdempotencyKey{}
+	query := `SELECT user_id, idempotency_key, request_hash, status, locked_until, response_status, response_body, created_at, expires_at
+			  FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?`
+	err := r.DB.Get(record, query, userID, key)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Reclaim claims a key that is taken when it expired, or when its lease ran out before the same request completed.
+// It returns false without error when another request claimed or completed the key first.
+func (r *IdempotencyRepositoryImpl) Reclaim(record *models.IdempotencyKey, now time.Time) (bool, error) {
+	query := `UPDATE idempotency_keys
+			  SET request_hash = ?, status = ?, locked_until = ?, response_status = 0, response_body = NULL, created_at = ?, expires_at = ?
+			  WHERE user_id = ? AND idempotency_key = ?
+			  AND (expires_at < ? OR (status = 'processing' AND request_hash = ? AND locked_until < ?))`
+	result, err := r.DB.Exec(
+		query,
+		record.RequestHash,
+		record.Status,
+		record.LockedUntil,
+		record.CreatedAt,
+		record.ExpiresAt,
+		record.UserID,
+		record.IdempotencyKey,
+		now,
+		record.RequestHash,
+		now,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// Complete stores the response of a processed request
+func (r *IdempotencyRepositoryImpl) Complete(record *models.IdempotencyKey) error {
+	query := `UPDATE idempotency_keys SET status = ?, response_status = ?, response_body = ?
+			  WHERE user_id = ? AND idempotency_key = ?`
+	_, err := r.DB.Exec(
+		query,
+		record.Status,
+		record.ResponseStatus,
+		record.ResponseBody,
+		record.UserID,
+		record.IdempotencyKey,
+	)
+	return err
+}
+
+// Delete removes an idempotency key so the request can be retried
+func (r *IdempotencyRepositoryImpl) Delete(userID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?`
+	_, err := r.DB.Exec(query, userID, key)
+	return err
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/types"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LedgerRepository is an interface for double-entry ledger operations
+type LedgerRepository interface {
+	PostEntry(entry *models.JournalEntry) error
+	GetEntryByID(entryID string) (*models.JournalEntry, error)
+	GetBalanceAt(accountID string, at time.Time) (types.Money, error)
+	GetTrialBalance() ([]*models.TrialBalance, error)
+}
+
+// LedgerRepositoryImpl implements LedgerRepository
+type LedgerRepositoryImpl struct {
+	DB DB
+}
+
+// NewLedgerRepository creates a new instance of LedgerRepository
+func NewLedgerRepository(db DB) LedgerRepository {
+	return &LedgerRepositoryImpl{
+		DB: db,
+	}
+}
+
+// PostEntry writes a journal entry and its postings, updating the running balance of every account involved.
+// The caller is responsible for making sure the postings balance.
+func (r *LedgerRepositoryImpl) PostEntry(entry *models.JournalEntry) error {
+	return runInTx(r.DB, func(tx *sqlx.Tx) error {
+		now := time.Now()
+		entry.CreatedAt = now
+
+		query := `INSERT INTO journal_entries (entry_id, entry_type, reference_id, description, created_at)
+				  VALUES (?, ?, ?, ?, ?)`
+		_, err := tx.Exec(query, entry.EntryID, entry.EntryType, entry.ReferenceID, entry.Description, entry.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		// Lock the ledger accounts in a consistent order to prevent deadlocks
+		accountIDs := make([]string, 0, len(entry.Postings))
+		currencies := make(map[string]string, len(entry.Postings))
+		for _, posting := range entry.Postings {
+			if _, ok := currencies[posting.AccountID]; !ok {
+				accountIDs = append(accountIDs, posting.AccountID)
+			}
+			currencies[posting.AccountID] = posting.Amount.Currency
+		}
+		sort.Strings(accountIDs)
+
+		balances := make(map[string]types.Money, len(accountIDs))
+		for _, accountID := range accountIDs {
+			// Ledger accounts are opened on their first posting
+			_, err = tx.Exec(`INSERT IGNORE INTO ledger_accounts (account_id, currency, balance) VALUES (?, ?, 0)`,
+				accountID, currencies[accountID])
+			if err != nil {
+				return err
+			}
+
+			var balance types.Money
+			query = `SELECT CONCAT(balance, ' ', currency) FROM ledger_accounts WHERE account_id = ? FOR UPDATE`
+			if err = tx.Get(&balance, query, accountID); err != nil {
+				return err
+			}
+			balances[accountID] = balance
+		}
+
+		// Apply postings in order so balance_after reflects each leg
+		for _, posting := range entry.Postings {
+			balance := balances[posting.AccountID]
+			if posting.Direction == models.Credit {
+				balance, err = balance.Add(posting.Amount)
+			} else {
+				balance, err = balance.Sub(posting.Amount)
+			}
+			if err != nil {
+				return err
+			}
+			balances[posting.AccountID] = balance
+
+			posting.EntryID = entry.EntryID
+			posting.BalanceAfter = balance
+			posting.CreatedAt = now
+
+			query = `INSERT INTO ledger_postings (entry_id, account_id, direction, amount, currency, balance_after, created_at)
+					 VALUES (?, ?, ?, ?, ?, ?, ?)`
+			result, execErr := tx.Exec(
+				query,
+				posting.EntryID,
+				posting.AccountID,
+				posting.Direction,
+				posting.Amount,
+				posting.Amount.Currency,
+				posting.BalanceAfter,
+				posting.CreatedAt,
+			)
+			if execErr != nil {
+				return execErr
+			}
+
+			if posting.PostingID, err = result.LastInsertId(); err != nil {
+				return err
+			}
+		}
+
+		for _, accountID := range accountIDs {
+			_, err = tx.Exec(`UPDATE ledger_accounts SET balance = ? WHERE account_id = ?`, balances[accountID], accountID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetEntryByID retrieves a journal entry with its postings
+func (r *LedgerRepositoryImpl) GetEntryByID(entryID string) (*models.JournalEntry, error) {
+	entry := &models.JournalEntry{}
+	query := `SELECT entry_id, entry_type, reference_id, description, created_at FROM journal_entries WHERE entry_id = ?`
+	err := r.DB.Get(entry, query, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT posting_id, entry_id, account_id, direction,
+			CONCAT(amount, ' ', currency) AS amount,
+			CONCAT(balance_after, ' ', currency) AS balance_after,
+			created_at
+		FROM ledger_postings
+		WHERE entry_id = ?
+		ORDER BY posting_id
+	`
+	err = r.DB.Select(&entry.Postings, query, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetBalanceAt rebuilds an account balance from its postings as of the given time.
+// Returns sql.ErrNoRows if the account has never been posted to.
+func (r *LedgerRepositoryImpl) GetBalanceAt(accountID string, at time.Time) (types.Money, error) {
+	var balance types.Money
+	query := `
+		SELECT CONCAT(COALESCE(SUM(IF(p.direction = 'credit', p.amount, -p.amount)), 0), ' ', l.currency)
+		FROM ledger_accounts l
+		LEFT JOIN ledger_postings p ON p.account_id = l.account_id AND p.created_at <= ?
+		WHERE l.account_id = ?
+		GROUP BY l.account_id, l.currency
+	`
+	err := r.DB.Get(&balance, query, at, accountID)
+	if err != nil {
+		return types.Money{}, err
+	}
+	return balance, nil
+}
+
+// GetTrialBalance sums all debit and credit postings per currency
+func (r *LedgerRepositoryImpl) GetTrialBalance() ([]*models.TrialBalance, error) {
+	trialBalances := []*models.TrialBalance{}
+	query := `
+		SELECT currency,
+			CONCAT(SUM(IF(direction = 'debit', amount, 0)), ' ', currency) AS total_debits,
+			CONCAT(SUM(IF(direction = 'credit', amount, 0)), ' ', currency) AS total_credits
+		FROM ledger_postings
+		GROUP BY currency
+		ORDER BY currency
+	`
+	err := r.DB.Select(&trialBalances, query)
+	if err != nil {
+		return nil, err
+	}
+	return trialBalances, nil
+}
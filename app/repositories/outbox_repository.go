@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+)
+
+// OutboxRepository is an interface for the outbox of domain events
+type OutboxRepository interface {
+	Create(event *models.DomainEvent) error
+	GetUnpublished(limit int) ([]*models.DomainEvent, error)
+	MarkPublished(sequence int64, now time.Time) error
+	RecordFailure(sequence int64, message string) error
+	DeletePublished(before time.Time) (int64, error)
+	ClaimRelayLease(owner string, now time.Time, leaseDuration time.Duration) (bool, error)
+}
+
+// OutboxRepositoryImpl implements OutboxRepository
+type OutboxRepositoryImpl struct {
+	DB DB
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository
+func NewOutboxRepository(db DB) OutboxRepository {
+	return &OutboxRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Name of the lease row the relay instance holds
+const outboxRelayLease = "outbox-relay"
+
+// Create adds an event to the outbox, it must run in the transaction of the change it describes
+func (r *OutboxRepositoryImpl) Create(event *models.DomainEvent) error {
+	query := `INSERT INTO outbox_events (event_id, event_type, aggregate_type, aggregate_id, related_aggregate_id, payload, occurred_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.DB.Exec(
+		query,
+		event.EventID,
+		event.EventType,
+		event.AggregateType,
+		event.AggregateID,
+		event.RelatedAggregateID,
+		event.Payload,
+		event.OccurredAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	event.Sequence, err = result.LastInsertId()
+	return err
+}
+
+// GetUnpublished retrieves the oldest events that have not been published yet, in sequence order
+func (r *OutboxRepositoryImpl) GetUnpublished(limit int) ([]*models.DomainEvent, error) {
+	query := `SELECT sequence, event_id, event_type, aggregate_type, aggregate_id, related_aggregate_id, payload,
+			  occurred_at, published_at, attempts, last_error
+			  FROM outbox_events WHERE published_at IS NULL ORDER BY sequence LIMIT ?`
+	events := []*models.DomainEvent{}
+	err := r.DB.Select(&events, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// MarkPublished records that an event has been published
+func (r *OutboxRepositoryImpl) MarkPublished(sequence int64, now time.Time) error {
+	query := `UPDATE outbox_events SET published_at = ?, attempts = attempts + 1, last_error = '' WHERE sequence = ?`
+	_, err := r.DB.Exec(query, now, sequence)
+	return err
+}
+
+// RecordFailure records a failed attempt to publish an event, it is retried by the next relay run
+func (r *OutboxRepositoryImpl) RecordFailure(sequence int64, message string) error {
+	query := `UPDATE outbox_events SET attempts = attempts + 1, last_error = LEFT(?, 255) WHERE sequence = ?`
+	_, err := r.DB.Exec(query, message, sequence)
+	return err
+}
+
+// DeletePublished removes events published before the given time and returns how many were removed
+func (r *OutboxRepositoryImpl) DeletePublished(before time.Time) (int64, error) {
+	result, err := r.DB.Exec(`DELETE FROM outbox_events WHERE published_at IS NOT NULL AND published_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ClaimRelayLease takes or renews the relay lease for owner, it fails while another owner holds an unexpired lease.
+// The lease is taken with a single UPDATE so two instances can never hold it at once.
+func (r *OutboxRepositoryImpl) ClaimRelayLease(owner string, now time.Time, leaseDuration time.Duration) (bool, error) {
+	query := `UPDATE outbox_relay_lease SET lease_owner = ?, lease_expires_at = ?
+			  WHERE lease_name = ? AND (lease_owner = ? OR lease_expires_at IS NULL OR lease_expires_at < ?)`
+	result, err := r.DB.Exec(query, owner, now.Add(leaseDuration), outboxRelayLease, owner, now)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
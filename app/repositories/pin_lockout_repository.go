@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PinLockoutRepository is an interface for failed PIN verification operations
+type PinLockoutRepository interface {
+	GetByUserID(userID string) (*models.PinLockout, error)
+	ReserveAttempt(userID string, reserve func(lockout *models.PinLockout) error) (*models.PinLockout, error)
+	ReleaseAttempt(previous *models.PinLockout, reserved int) error
+	Delete(userID string) error
+	IncrementIPFailures(ipAddress string, windowStart time.Time) (int64, error)
+	DecrementIPFailures(ipAddress string, windowStart time.Time) error
+}
+
+// PinLockoutRepositoryImpl implements PinLockoutRepository
+type PinLockoutRepositoryImpl struct {
+	DB DB
+}
+
+// NewPinLockoutRepository creates a new instance of PinLockoutRepository
+func NewPinLockoutRepository(db DB) PinLockoutRepository {
+	return &PinLockoutRepositoryImpl{
+		DB: db,
+	}
+}
+
+const pinLockoutColumns = `user_id, failed_attempts, last_failed_at, locked_until, locked_at`
+
+// GetByUserID retrieves the failed PIN verifications of a user
+func (r *PinLockoutRepositoryImpl) GetByUserID(userID string) (*models.PinLockout, error) {
+	lockout := &models.PinLockout{}
+	query := `SELECT ` + pinLockoutColumns + ` FROM pin_lockouts WHERE user_id = ?`
+	err := r.DB.Get(lockout, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return lockout, nil
+}
+
+// ReserveAttempt locks the failed PIN verifications of a user, a user without failures gets an empty row, and
+// passes them to reserve. The row reserve leaves is stored unless it returns an error, concurrent reservations for
+// the same user wait for each other.
+func (r *PinLockoutRepositoryImpl) ReserveAttempt(userID string, reserve func(lockout *models.PinLockout) error) (*models.PinLockout, error) {
+	lockout := &models.PinLockout{}
+	err := runInTx(r.DB, func(tx *sqlx.Tx) error {
+		query := `INSERT IGNORE INTO pin_lockouts (user_id, failed_attempts) VALUES (?, 0)`
+		if _, err := tx.Exec(query, userID); err != nil {
+			return err
+		}
+
+		query = `SELECT ` + pinLockoutColumns + ` FROM pin_lockouts WHERE user_id = ? FOR UPDATE`
+		if err := tx.Get(lockout, query, userID); err != nil {
+			return err
+		}
+
+		if err := reserve(lockout); err != nil {
+			return err
+		}
+
+		query = `UPDATE pin_lockouts SET failed_attempts = ?, last_failed_at = ?, locked_until = ?, locked_at = ? WHERE user_id = ?`
+		_, err := tx.Exec(query, lockout.FailedAttempts, lockout.LastFailedAt, lockout.LockedUntil, lockout.LockedAt, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lockout, nil
+}
+
+// ReleaseAttempt puts back the failed PIN verifications of a user from before a reservation, unless other attempts
+// were counted since
+func (r *PinLockoutRepositoryImpl) ReleaseAttempt(previous *models.PinLockout, reserved int) error {
+	if previous.FailedAttempts == 0 {
+		query := `DELETE FROM pin_lockouts WHERE user_id = ? AND failed_attempts = ?`
+		_, err := r.DB.Exec(query, previous.UserID, reserved)
+		return err
+	}
+
+	query := `UPDATE pin_lockouts SET failed_attempts = ?, last_failed_at = ?, locked_until = ?, locked_at = ?
+			  WHERE user_id = ? AND failed_attempts = ?`
+	_, err := r.DB.Exec(query, previous.FailedAttempts, previous.LastFailedAt, previous.LockedUntil, previous.LockedAt,
+		previous.UserID, reserved)
+	return err
+}
+
+// Delete forgets the failed PIN verifications of a user, lifting any lock
+func (r *PinLockoutRepositoryImpl) Delete(userID string) error {
+	query := `DELETE FROM pin_lockouts WHERE user_id = ?`
+	_, err := r.DB.Exec(query, userID)
+	return err
+}
+
+// IncrementIPFailures counts a failed PIN verification from an IP address in the window and returns the new count
+func (r *PinLockoutRepositoryImpl) IncrementIPFailures(ipAddress string, windowStart time.Time) (int64, error) {
+	var failures int64
+	err := runInTx(r.DB, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO pin_ip_failures (ip_address, window_start, failures) VALUES (?, ?, 1)
+				  ON DUPLICATE KEY UPDATE failures = failures + 1`
+		if _, err := tx.Exec(query, ipAddress, windowStart); err != nil {
+			return err
+		}
+
+		query = `SELECT failures FROM pin_ip_failures WHERE ip_address = ? AND window_start = ?`
+		return tx.Get(&failures, query, ipAddress, windowStart)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+// DecrementIPFailures takes back a failed PIN verification from an IP address in the window
+func (r *PinLockoutRepositoryImpl) DecrementIPFailures(ipAddress string, windowStart time.Time) error {
+	query := `UPDATE pin_ip_failures SET failures = failures - 1 WHERE ip_address = ? AND window_start = ? AND failures > 0`
+	_, err := r.DB.Exec(query, ipAddress, windowStart)
+	return err
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PinRepository is an interface for PIN history and PIN reset operations
+type PinRepository interface {
+	GetPinHistory(userID string, limit int) ([]string, error)
+	ReplacePin(userID, pinHash string, historySize int) error
+	GetResetCode(userID string) (*models.PinResetCode, error)
+	SaveResetCode(code *models.PinResetCode) error
+	ReserveResetAttempt(code *models.PinResetCode, maxAttempts int) (bool, error)
+	ReleaseResetAttempt(code *models.PinResetCode) error
+	DeleteResetCode(code *models.PinResetCode) (bool, error)
+}
+
+// PinRepositoryImpl implements PinRepository
+type PinRepositoryImpl struct {
+	DB DB
+}
+
+// NewPinRepository creates a new instance of PinRepository
+func NewPinRepository(db DB) PinRepository {
+	return &PinRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetPinHistory retrieves the hashes of the PINs a user replaced, most recent first
+func (r *PinRepositoryImpl) GetPinHistory(userID string, limit int) ([]string, error) {
+	hashes := []string{}
+	query := `SELECT pin_hash FROM pin_history WHERE user_id = ? ORDER BY id DESC LIMIT ?`
+	err := r.DB.Select(&hashes, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// ReplacePin sets the PIN of a user, moving the current PIN to the history and keeping the historySize most
+// recent replaced PINs
+func (r *PinRepositoryImpl) ReplacePin(userID, pinHash string, historySize int) error {
+	return runInTx(r.DB, func(tx *sqlx.Tx) error {
+		var currentHash string
+		query := `SELECT pin FROM users WHERE user_id = ? AND deleted_at IS NULL FOR UPDATE`
+		if err := tx.Get(&currentHash, query, userID); err != nil {
+			return err
+		}
+
+		query = `UPDATE users SET pin = ? WHERE user_id = ?`
+		if _, err := tx.Exec(query, pinHash, userID); err != nil {
+			return err
+		}
+
+		if currentHash == "" {
+			return nil
+		}
+
+		query = `INSERT INTO pin_history (user_id, pin_hash) VALUES (?, ?)`
+		if _, err := tx.Exec(query, userID, currentHash); err != nil {
+			return err
+		}
+
+		// Older entries are never compared again
+		query = `DELETE FROM pin_history WHERE user_id = ? AND id < (
+					SELECT id FROM (
+						SELECT id FROM pin_history WHERE user_id = ? ORDER BY id DESC LIMIT 1 OFFSET ?
+					) AS oldest_kept
+				)`
+		_, err := tx.Exec(query, userID, userID, historySize-1)
+		return err
+	})
+}
+
+// GetResetCode retrieves the outstanding PIN reset code of a user
+func (r *PinRepositoryImpl) GetResetCode(userID string) (*models.PinResetCode, error) {
+	code := &models.PinResetCode{}
+	query := `SELECT user_id, code_hash, attempts, expires_at, created_at FROM pin_reset_codes WHERE user_id = ?`
+	err := r.DB.Get(code, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// SaveResetCode stores a new PIN reset code, replacing the outstanding one
+func (r *PinRepositoryImpl) SaveResetCode(code *models.PinResetCode) error {
+	query := `INSERT INTO pin_reset_codes (user_id, code_hash, attempts, expires_at, created_at) VALUES (?, ?, 0, ?, ?)
+			  ON DUPLICATE KEY UPDATE code_hash = VALUES(code_hash), attempts = 0,
+			  expires_at = VALUES(expires_at), created_at = VALUES(created_at)`
+	_, err := r.DB.Exec(query, code.UserID, code.CodeHash, code.ExpiresAt, code.CreatedAt)
+	return err
+}
+
+// ReserveResetAttempt counts an attempt at a PIN reset code before it is checked and reports whether the code had
+// attempts left. The code is matched by its hash, so an attempt is never counted against a code issued since.
+func (r *PinRepositoryImpl) ReserveResetAttempt(code *models.PinResetCode, maxAttempts int) (bool, error) {
+	query := `UPDATE pin_reset_codes SET attempts = attempts + 1 WHERE user_id = ? AND code_hash = ? AND attempts < ?`
+	result, err := r.DB.Exec(query, code.UserID, code.CodeHash, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// ReleaseResetAttempt takes back an attempt counted by ReserveResetAttempt
+func (r *PinRepositoryImpl) ReleaseResetAttempt(code *models.PinResetCode) error {
+	query := `UPDATE pin_reset_codes SET attempts = attempts - 1 WHERE user_id = ? AND code_hash = ? AND attempts > 0`
+	_, err := r.DB.Exec(query, code.UserID, code.CodeHash)
+	return err
+}
+
+// DeleteResetCode removes a PIN reset code unless another was issued since and reports whether it was still
+// outstanding, only one of concurrent requests with the same code deletes it
+func (r *PinRepositoryImpl) DeleteResetCode(code *models.PinResetCode) (bool, error) {
+	query := `DELETE FROM pin_reset_codes WHERE user_id = ? AND code_hash = ?`
+	result, err := r.DB.Exec(query, code.UserID, code.CodeHash)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+)
+
+// RefreshTokenRepository is an interface for refresh token operations
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	GetByHash(tokenHash string) (*models.RefreshToken, error)
+	MarkUsed(tokenHash string, now time.Time) (bool, error)
+	RevokeFamily(familyID string, now time.Time) (int64, error)
+	RevokeByUserID(userID string, now time.Time) (int64, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// RefreshTokenRepositoryImpl implements RefreshTokenRepository
+type RefreshTokenRepositoryImpl struct {
+	DB DB
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository
+func NewRefreshTokenRepository(db DB) RefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create stores a newly issued refresh token
+func (r *RefreshTokenRepositoryImpl) Create(token *models.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (token_hash, family_id, user_id, device_id, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		token.TokenHash,
+		token.FamilyID,
+		token.UserID,
+		token.DeviceID,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// GetByHash retrieves a refresh token by the hash of the token
+func (r *RefreshTokenRepositoryImpl) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+	query := `SELECT token_hash, family_id, user_id, device_id, expires_at, used_at, revoked_at, created_at
+			  FROM refresh_tokens WHERE token_hash = ?`
+	err := r.DB.Get(token, query, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkUsed consumes a token that is neither used nor revoked. It returns false without error when the token was
+// already consumed, the conditional update makes rotation atomic across concurrent renewals.
+func (r *RefreshTokenRepositoryImpl) MarkUsed(tokenHash string, now time.Time) (bool, error) {
+	query := `UPDATE refresh_tokens SET used_at = ?
+			  WHERE token_hash = ? AND used_at IS NULL AND revoked_at IS NULL`
+	result, err := r.DB.Exec(query, now, tokenHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeFamily revokes every token rotated from the same sign-in
+func (r *RefreshTokenRepositoryImpl) RevokeFamily(familyID string, now time.Time) (int64, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+	result, err := r.DB.Exec(query, now, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RevokeByUserID revokes every token of a user on all devices
+func (r *RefreshTokenRepositoryImpl) RevokeByUserID(userID string, now time.Time) (int64, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	result, err := r.DB.Exec(query, now, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteExpired removes tokens that expired, a reuse of them is rejected as unknown
+func (r *RefreshTokenRepositoryImpl) DeleteExpired(now time.Time) (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE expires_at <= ?`
+	result, err := r.DB.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -5,21 +5,53 @@ import (
 )
 
 type Repository struct {
-	UserRepository          UserRepository
-	UserGreetingsRepository UserGreetingRepository
-	TransactionRepository   TransactionRepository
-	DebitCardRepository     DebitCardRepository
-	AccountRepository       AccountRepository
-	BannerRepository        BannerRepository
+	UserRepository              UserRepository
+	UserGreetingsRepository     UserGreetingRepository
+	TransactionRepository       TransactionRepository
+	DebitCardRepository         DebitCardRepository
+	AccountRepository           AccountRepository
+	BannerRepository            BannerRepository
+	LedgerRepository            LedgerRepository
+	IdempotencyRepository       IdempotencyRepository
+	ScheduledTransferRepository ScheduledTransferRepository
+	TransferLimitRepository     TransferLimitRepository
+	FXRepository                FXRepository
+	HoldRepository              HoldRepository
+	RefreshTokenRepository      RefreshTokenRepository
+	PinLockoutRepository        PinLockoutRepository
+	PinRepository               PinRepository
+	ChallengeRepository         ChallengeRepository
+	TOTPRepository              TOTPRepository
+	RoleRepository              RoleRepository
+	SessionRepository           SessionRepository
+	AuditLogRepository          AuditLogRepository
+	OutboxRepository            OutboxRepository
+	WebhookRepository           WebhookRepository
 }
 
 func InitRepository(db *sqlx.DB) *Repository {
 	return &Repository{
-		UserRepository:          NewUserRepository(db),
-		UserGreetingsRepository: NewUserGreetingsRepository(db),
-		TransactionRepository:   NewTransactionRepository(db),
-		DebitCardRepository:     NewDebitCardRepository(db),
-		AccountRepository:       NewAccountRepository(db),
-		BannerRepository:        NewBannerRepository(db),
+		UserRepository:              NewUserRepository(db),
+		UserGreetingsRepository:     NewUserGreetingsRepository(db),
+		TransactionRepository:       NewTransactionRepository(db),
+		DebitCardRepository:         NewDebitCardRepository(db),
+		AccountRepository:           NewAccountRepository(db),
+		BannerRepository:            NewBannerRepository(db),
+		LedgerRepository:            NewLedgerRepository(db),
+		IdempotencyRepository:       NewIdempotencyRepository(db),
+		ScheduledTransferRepository: NewScheduledTransferRepository(db),
+		TransferLimitRepository:     NewTransferLimitRepository(db),
+		FXRepository:                NewFXRepository(db),
+		HoldRepository:              NewHoldRepository(db),
+		RefreshTokenRepository:      NewRefreshTokenRepository(db),
+		PinLockoutRepository:        NewPinLockoutRepository(db),
+		PinRepository:               NewPinRepository(db),
+		ChallengeRepository:         NewChallengeRepository(db),
+		TOTPRepository:              NewTOTPRepository(db),
+		RoleRepository:              NewRoleRepository(db),
+		SessionRepository:           NewSessionRepository(db),
+		AuditLogRepository:          NewAuditLogRepository(db),
+		OutboxRepository:            NewOutboxRepository(db),
+		WebhookRepository:           NewWebhookRepository(db),
 	}
 }
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"backend-developer-assignment/pkg/types"
+)
+
+// RoleRepository is an interface for staff role operations
+type RoleRepository interface {
+	GetRolesByUserID(userID string) ([]types.Role, error)
+}
+
+// RoleRepositoryImpl implements RoleRepository
+type RoleRepositoryImpl struct {
+	DB DB
+}
+
+// NewRoleRepository creates a new instance of RoleRepository
+func NewRoleRepository(db DB) RoleRepository {
+	return &RoleRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetRolesByUserID retrieves the roles of a user, customers have none
+func (r *RoleRepositoryImpl) GetRolesByUserID(userID string) ([]types.Role, error) {
+	roles := []types.Role{}
+	query := `SELECT role FROM user_roles WHERE user_id = ? ORDER BY role`
+	err := r.DB.Select(&roles, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
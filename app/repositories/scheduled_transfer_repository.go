@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrScheduleLeaseLost is returned when a schedule is written back by an instance that no longer holds its lease
+var ErrScheduleLeaseLost = errors.New("scheduled transfer lease lost")
+
+// ScheduledTransferRepository is an interface for scheduled transfer operations
+type ScheduledTransferRepository interface {
+	GetByID(scheduleID string) (*models.ScheduledTransfer, error)
+	GetByAccountID(accountID string) ([]*models.ScheduledTransfer, error)
+	Create(schedule *models.ScheduledTransfer) error
+	Update(schedule *models.ScheduledTransfer) error
+	Cancel(scheduleID string) error
+
+	// Scheduler operations
+	ClaimDue(leaseOwner string, now time.Time, leaseDuration time.Duration, limit int) ([]*models.ScheduledTransfer, error)
+	RecordExecution(schedule *models.ScheduledTransfer, execution *models.ScheduledTransferExecution) error
+	GetExecutions(scheduleID string, limit int) ([]*models.ScheduledTransferExecution, error)
+}
+
+// ScheduledTransferRepositoryImpl implements ScheduledTransferRepository
+type ScheduledTransferRepositoryImpl struct {
+	DB DB
+}
+
+// NewScheduledTransferRepository creates a new instance of ScheduledTransferRepository
+func NewScheduledTransferRepository(db DB) ScheduledTransferRepository {
+	return &ScheduledTransferRepositoryImpl{
+		DB: db,
+	}
+}
+
+const scheduledTransferColumns = `schedule_id, user_id, from_account_id, to_account_id, CONCAT(amount, ' ', currency) AS amount, description,
+	frequency, start_at, end_at, next_run_at, next_attempt_at, last_run_at, status, insufficient_funds_policy, retry_count,
+	lease_owner, lease_expires_at, created_at, updated_at`
+
+// GetByID retrieves a scheduled transfer by ID
+func (r *ScheduledTransferRepositoryImpl) GetByID(scheduleID string) (*models.ScheduledTransfer, error) {
+	schedule := &models.ScheduledTransfer{}
+	query := `SELECT ` + scheduledTransferColumns + `
+			  FROM scheduled_transfers WHERE schedule_id = ? AND deleted_at IS NULL`
+	err := r.DB.Get(schedule, query, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetByAccountID retrieves all scheduled transfers paid from an account
+func (r *ScheduledTransferRepositoryImpl) GetByAccountID(accountID string) ([]*models.ScheduledTransfer, error) {
+	schedules := []*models.ScheduledTransfer{}
+	query := `SELECT ` + scheduledTransferColumns + `
+			  FROM scheduled_transfers WHERE from_account_id = ? AND deleted_at IS NULL ORDER BY created_at DESC`
+	err := r.DB.Select(&schedules, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Create adds a new scheduled transfer
+func (r *ScheduledTransferRepositoryImpl) Create(schedule *models.ScheduledTransfer) error {
+	now := time.Now()
+	schedule.BaseModel = &models.BaseModel{CreatedAt: now, UpdatedAt: now}
+
+	query := `INSERT INTO scheduled_transfers (
+		schedule_id, user_id, from_account_id, to_account_id, amount, currency, description, frequency,
+		start_at, end_at, next_run_at, next_attempt_at, status, insufficient_funds_policy, retry_count, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		schedule.ScheduleID,
+		schedule.UserID,
+		schedule.FromAccountID,
+		schedule.ToAccountID,
+		schedule.Amount,
+		schedule.Amount.Currency,
+		schedule.Description,
+		schedule.Frequency,
+		schedule.StartAt,
+		schedule.EndAt,
+		schedule.NextRunAt,
+		schedule.NextAttemptAt,
+		schedule.Status,
+		schedule.InsufficientFundsPolicy,
+		schedule.RetryCount,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+	return err
+}
+
+// Update saves the user editable fields of a scheduled transfer
+func (r *ScheduledTransferRepositoryImpl) Update(schedule *models.ScheduledTransfer) error {
+	query := `UPDATE scheduled_transfers SET amount = ?, currency = ?, description = ?, frequency = ?, end_at = ?,
+			  next_run_at = ?, next_attempt_at = ?, status = ?, insufficient_funds_policy = ?, retry_count = ?, updated_at = ?
+			  WHERE schedule_id = ? AND deleted_at IS NULL`
+	_, err := r.DB.Exec(
+		query,
+		schedule.Amount,
+		schedule.Amount.Currency,
+		schedule.Description,
+		schedule.Frequency,
+		schedule.EndAt,
+		schedule.NextRunAt,
+		schedule.NextAttemptAt,
+		schedule.Status,
+		schedule.InsufficientFundsPolicy,
+		schedule.RetryCount,
+		time.Now(),
+		schedule.ScheduleID,
+	)
+	return err
+}
+
+// Cancel stops a scheduled transfer and marks it as deleted
+func (r *ScheduledTransferRepositoryImpl) Cancel(scheduleID string) error {
+	now := time.Now()
+	query := `UPDATE scheduled_transfers SET status = ?, updated_at = ?, deleted_at = ? WHERE schedule_id = ? AND deleted_at IS NULL`
+	_, err := r.DB.Exec(query, models.ScheduleCancelled, now, now, scheduleID)
+	return err
+}
+
+// ClaimDue leases up to limit due schedules to leaseOwner and returns them.
+// The lease is taken with a single UPDATE so two instances can never claim the same schedule,
+// an expired lease (e.g. the owner crashed) can be claimed again.
+func (r *ScheduledTransferRepositoryImpl) ClaimDue(leaseOwner string, now time.Time, leaseDuration time.Duration, limit int) ([]*models.ScheduledTransfer, error) {
+	query := `UPDATE scheduled_transfers SET lease_owner = ?, lease_expires_at = ?
+			  WHERE status = ? AND deleted_at IS NULL AND next_attempt_at <= ?
+			  AND (lease_expires_at IS NULL OR lease_expires_at < ?)
+			  ORDER BY next_attempt_at LIMIT ?`
+	_, err := r.DB.Exec(query, leaseOwner, now.Add(leaseDuration), models.ScheduleActive, now, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := []*models.ScheduledTransfer{}
+	query = `SELECT ` + scheduledTransferColumns + `
+			 FROM scheduled_transfers WHERE lease_owner = ? AND status = ? ORDER BY next_attempt_at`
+	err = r.DB.Select(&schedules, query, leaseOwner, models.ScheduleActive)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// RecordExecution stores an execution and writes back the schedule's run state, releasing its lease.
+// It fails with ErrScheduleLeaseLost when schedule.LeaseOwner no longer holds the lease.
+func (r *ScheduledTransferRepositoryImpl) RecordExecution(schedule *models.ScheduledTransfer, execution *models.ScheduledTransferExecution) error {
+	return runInTx(r.DB, func(tx *sqlx.Tx) error {
+		// A schedule paused or cancelled while it was executing keeps its status
+		query := `UPDATE scheduled_transfers SET next_run_at = ?, next_attempt_at = ?, last_run_at = ?, retry_count = ?,
+				  status = CASE WHEN status = ? THEN ? ELSE status END, lease_owner = NULL, lease_expires_at = NULL
+				  WHERE schedule_id = ? AND lease_owner = ?`
+		result, err := tx.Exec(
+			query,
+			schedule.NextRunAt,
+			schedule.NextAttemptAt,
+			schedule.LastRunAt,
+			schedule.RetryCount,
+			models.ScheduleActive,
+			schedule.Status,
+			schedule.ScheduleID,
+			schedule.LeaseOwner,
+		)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected != 1 {
+			return ErrScheduleLeaseLost
+		}
+
+		execution.CreatedAt = time.Now()
+		query = `INSERT INTO scheduled_transfer_executions (execution_id, schedule_id, scheduled_for, executed_at, attempt, status, message, created_at)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		_, err = tx.Exec(
+			query,
+			execution.ExecutionID,
+			execution.ScheduleID,
+			execution.ScheduledFor,
+			execution.ExecutedAt,
+			execution.Attempt,
+			execution.Status,
+			execution.Message,
+			execution.CreatedAt,
+		)
+		return err
+	})
+}
+
+// GetExecutions retrieves the most recent executions of a scheduled transfer
+func (r *ScheduledTransferRepositoryImpl) GetExecutions(scheduleID string, limit int) ([]*models.ScheduledTransferExecution, error) {
+	executions := []*models.ScheduledTransferExecution{}
+	query := `SELECT execution_id, schedule_id, scheduled_for, executed_at, attempt, status, message, created_at
+			  FROM scheduled_transfer_executions WHERE schedule_id = ? ORDER BY executed_at DESC LIMIT ?`
+	err := r.DB.Select(&executions, query, scheduleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+)
+
+// SessionRepository is an interface for device session operations
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByID(sessionID string) (*models.Session, error)
+	GetActiveByUserID(userID string) ([]*models.Session, error)
+	Touch(sessionID string, now time.Time) error
+	Revoke(sessionID string, now time.Time) (bool, error)
+	DeleteInactive(before time.Time) (int64, error)
+}
+
+// SessionRepositoryImpl implements SessionRepository
+type SessionRepositoryImpl struct {
+	DB DB
+}
+
+// NewSessionRepository creates a new instance of SessionRepository
+func NewSessionRepository(db DB) SessionRepository {
+	return &SessionRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create stores the session of a new sign-in
+func (r *SessionRepositoryImpl) Create(session *models.Session) error {
+	query := `INSERT INTO user_sessions (session_id, user_id, device_id, device_name, platform, user_agent, ip_address, created_at, last_seen_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		session.SessionID,
+		session.UserID,
+		session.DeviceID,
+		session.DeviceName,
+		session.Platform,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+	)
+	return err
+}
+
+// GetByID retrieves a session, revoked sessions included
+func (r *SessionRepositoryImpl) GetByID(sessionID string) (*models.Session, error) {
+	session := &models.Session{}
+	query := `SELECT session_id, user_id, device_id, device_name, platform, user_agent, ip_address, created_at, last_seen_at, revoked_at
+			  FROM user_sessions WHERE session_id = ?`
+	err := r.DB.Get(session, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetActiveByUserID retrieves the sessions of a user that are not revoked, most recently seen first
+func (r *SessionRepositoryImpl) GetActiveByUserID(userID string) ([]*models.Session, error) {
+	sessions := []*models.Session{}
+	query := `SELECT session_id, user_id, device_id, device_name, platform, user_agent, ip_address, created_at, last_seen_at, revoked_at
+			  FROM user_sessions WHERE user_id = ? AND revoked_at IS NULL
+			  ORDER BY last_seen_at DESC`
+	err := r.DB.Select(&sessions, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Touch records that a session renewed its tokens
+func (r *SessionRepositoryImpl) Touch(sessionID string, now time.Time) error {
+	query := `UPDATE user_sessions SET last_seen_at = ? WHERE session_id = ? AND revoked_at IS NULL`
+	_, err := r.DB.Exec(query, now, sessionID)
+	return err
+}
+
+// Revoke ends a session. It returns false without error when the session is unknown or already revoked.
+func (r *SessionRepositoryImpl) Revoke(sessionID string, now time.Time) (bool, error) {
+	query := `UPDATE user_sessions SET revoked_at = ? WHERE session_id = ? AND revoked_at IS NULL`
+	result, err := r.DB.Exec(query, now, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteInactive removes sessions revoked or last seen before the given time, their refresh tokens have expired
+func (r *SessionRepositoryImpl) DeleteInactive(before time.Time) (int64, error) {
+	query := `DELETE FROM user_sessions WHERE last_seen_at < ? OR revoked_at < ?`
+	result, err := r.DB.Exec(query, before, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"time"
+)
+
+// TOTPRepository is an interface for TOTP authenticator operations
+type TOTPRepository interface {
+	GetByUserID(userID string) (*models.UserTOTP, error)
+	Save(totp *models.UserTOTP) error
+	Enable(userID string, now time.Time) error
+	MarkUsedStep(userID string, step int64) (bool, error)
+}
+
+// TOTPRepositoryImpl implements TOTPRepository
+type TOTPRepositoryImpl struct {
+	DB DB
+}
+
+// NewTOTPRepository creates a new instance of TOTPRepository
+func NewTOTPRepository(db DB) TOTPRepository {
+	return &TOTPRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetByUserID retrieves the TOTP authenticator of a user
+func (r *TOTPRepositoryImpl) GetByUserID(userID string) (*models.UserTOTP, error) {
+	totp := &models.UserTOTP{}
+	query := `SELECT user_id, secret, enabled_at, last_used_step, created_at FROM user_totp WHERE user_id = ?`
+	err := r.DB.Get(totp, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return totp, nil
+}
+
+// Save stores a new secret for a user, replacing a secret that was not enabled yet
+func (r *TOTPRepositoryImpl) Save(totp *models.UserTOTP) error {
+	query := `INSERT INTO user_totp (user_id, secret, created_at) VALUES (?, ?, ?)
+			  ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled_at = NULL, last_used_step = NULL,
+			  created_at = VALUES(created_at)`
+	_, err := r.DB.Exec(query, totp.UserID, totp.Secret, totp.CreatedAt)
+	return err
+}
+
+// Enable lets the secret of a user confirm challenges
+func (r *TOTPRepositoryImpl) Enable(userID string, now time.Time) error {
+	query := `UPDATE user_totp SET enabled_at = ? WHERE user_id = ? AND enabled_at IS NULL`
+	_, err := r.DB.Exec(query, now, userID)
+	return err
+}
+
+// MarkUsedStep records the time step of an accepted code. It returns false without error when a code of the same
+// or a later step was accepted before, so every code is used at most once.
+func (r *TOTPRepositoryImpl) MarkUsedStep(userID string, step int64) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = ?
+			  WHERE user_id = ? AND (last_used_step IS NULL OR last_used_step < ?)`
+	result, err := r.DB.Exec(query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -2,13 +2,29 @@ package repositories
 
 import (
 	"backend-developer-assignment/app/models"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// transactionOrders maps the orders GetByUserIDWithPagination accepts to their ORDER BY clause. A placeholder
+// can only bind a value, so the order is picked from this list rather than bound.
+var transactionOrders = map[string]string{
+	"created_at desc": "created_at DESC, transaction_id DESC",
+	"created_at asc":  "created_at ASC, transaction_id ASC",
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern so that they match themselves
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // TransactionRepository is an interface for transaction repository
 type TransactionRepository interface {
 	GetByID(id string) (*models.Transaction, error)
+	GetByIDForUpdate(id string) (*models.Transaction, error)
 	GetByUserIDWithPagination(userID, orderBy string, limit, offset int) ([]*models.Transaction, int, error)
+	Search(filter models.TransactionFilter) ([]*models.Transaction, error)
+	SearchByAccountID(filter models.TransactionFilter) ([]*models.Transaction, error)
+	GetByAccountIDInPeriod(accountID string, from, to time.Time, after *models.TransactionCursor, limit int) ([]*models.Transaction, error)
 	Create(transaction *models.Transaction) error
 	Update(transaction *models.Transaction) error
 }
@@ -29,7 +45,7 @@ func NewTransactionRepository(db DB) TransactionRepository {
 func (r *TransactionRepositoryImpl) GetByID(id string) (*models.Transaction, error) {
 	transaction := &models.Transaction{}
 
-	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, amount, transaction_type, created_at, updated_at
+	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, CONCAT(amount, ' ', currency) AS amount, transaction_type, direction, linked_transaction_id, reversal_of, CONCAT(reversed_amount, ' ', currency) AS reversed_amount, exchange_rate, fx_quote_id, created_at, updated_at
 	 FROM transactions WHERE transaction_id = ? and deleted_at IS NULL`
 
 	err := r.DB.Get(transaction, query, id)
@@ -40,11 +56,26 @@ func (r *TransactionRepositoryImpl) GetByID(id string) (*models.Transaction, err
 	return transaction, nil
 }
 
+// GetByIDForUpdate retrieves one Transaction by given ID and locks the row until the surrounding transaction ends.
+func (r *TransactionRepositoryImpl) GetByIDForUpdate(id string) (*models.Transaction, error) {
+	transaction := &models.Transaction{}
+
+	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, CONCAT(amount, ' ', currency) AS amount, transaction_type, direction, linked_transaction_id, reversal_of, CONCAT(reversed_amount, ' ', currency) AS reversed_amount, exchange_rate, fx_quote_id, created_at, updated_at
+	 FROM transactions WHERE transaction_id = ? and deleted_at IS NULL FOR UPDATE`
+
+	err := r.DB.Get(transaction, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
 // GetByUserID retrieves all transactions for a given user ID.
 func (r *TransactionRepositoryImpl) GetByUserID(userID string) ([]*models.Transaction, error) {
 	transactions := []*models.Transaction{}
 
-	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, amount, transaction_type, created_at, updated_at
+	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, CONCAT(amount, ' ', currency) AS amount, transaction_type, direction, linked_transaction_id, reversal_of, CONCAT(reversed_amount, ' ', currency) AS reversed_amount, exchange_rate, fx_quote_id, created_at, updated_at
 	 FROM transactions WHERE user_id = ? and deleted_at IS NULL ORDER BY created_at DESC`
 
 	err := r.DB.Select(&transactions, query, userID)
@@ -55,15 +86,21 @@ func (r *TransactionRepositoryImpl) GetByUserID(userID string) ([]*models.Transa
 	return transactions, nil
 }
 
-// GetByUserIDWithPagination retrieves paginated transactions for a given user ID.
+// GetByUserIDWithPagination retrieves paginated transactions for a given user ID, orderBy is "created_at desc"
+// or "created_at asc".
 func (r *TransactionRepositoryImpl) GetByUserIDWithPagination(userID, orderBy string, limit, offset int) ([]*models.Transaction, int, error) {
 	transactions := []*models.Transaction{}
 
+	order, ok := transactionOrders[strings.ToLower(orderBy)]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported transaction order %q", orderBy)
+	}
+
 	// Query for paginated results
-	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, amount, transaction_type, created_at, updated_at
-	FROM transactions WHERE user_id = ? and deleted_at IS NULL ORDER BY ? DESC LIMIT ? OFFSET ?`
+	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, CONCAT(amount, ' ', currency) AS amount, transaction_type, direction, linked_transaction_id, reversal_of, CONCAT(reversed_amount, ' ', currency) AS reversed_amount, exchange_rate, fx_quote_id, created_at, updated_at
+	FROM transactions WHERE user_id = ? and deleted_at IS NULL ORDER BY ` + order + ` LIMIT ? OFFSET ?`
 
-	err := r.DB.Select(&transactions, query, userID, orderBy, limit, offset)
+	err := r.DB.Select(&transactions, query, userID, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -79,6 +116,103 @@ func (r *TransactionRepositoryImpl) GetByUserIDWithPagination(userID, orderBy st
 	return transactions, total, nil
 }
 
+// Search retrieves the transactions of filter.UserID matching the filter in the order of filter.Sort, the
+// transaction ID breaking ties. Pages seek past filter.After rather than skip rows, so that a deep page costs no
+// more than the first one.
+func (r *TransactionRepositoryImpl) Search(filter models.TransactionFilter) ([]*models.Transaction, error) {
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{filter.UserID}
+
+	if filter.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+	}
+
+	return r.search(conditions, args, filter)
+}
+
+// SearchByAccountID retrieves the transactions of filter.AccountID, whoever made them, like Search. The
+// (account_id, created_at) index serves the listing.
+func (r *TransactionRepositoryImpl) SearchByAccountID(filter models.TransactionFilter) ([]*models.Transaction, error) {
+	return r.search([]string{"account_id = ?"}, []interface{}{filter.AccountID}, filter)
+}
+
+// search lists the transactions matching the scope conditions and the rest of the filter
+func (r *TransactionRepositoryImpl) search(conditions []string, args []interface{}, filter models.TransactionFilter) ([]*models.Transaction, error) {
+	conditions = append(conditions, "deleted_at IS NULL")
+
+	if filter.TransactionType != "" {
+		conditions = append(conditions, "transaction_type = ?")
+		args = append(args, filter.TransactionType)
+	}
+	if filter.MinAmount != "" {
+		conditions = append(conditions, "amount >= CAST(? AS DECIMAL(15, 2))")
+		args = append(args, filter.MinAmount)
+	}
+	if filter.MaxAmount != "" {
+		conditions = append(conditions, "amount <= CAST(? AS DECIMAL(15, 2))")
+		args = append(args, filter.MaxAmount)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+	if filter.Query != "" {
+		conditions = append(conditions, "name LIKE ?")
+		args = append(args, "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+
+	order, seek := "created_at DESC, transaction_id DESC", "<"
+	if filter.Sort == models.SortOldest {
+		order, seek = "created_at ASC, transaction_id ASC", ">"
+	}
+	if filter.After != nil {
+		conditions = append(conditions, "(created_at "+seek+" ? OR (created_at = ? AND transaction_id "+seek+" ?))")
+		args = append(args, filter.After.CreatedAt, filter.After.CreatedAt, filter.After.TransactionID)
+	}
+
+	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, CONCAT(amount, ' ', currency) AS amount, transaction_type, direction, linked_transaction_id, reversal_of, CONCAT(reversed_amount, ' ', currency) AS reversed_amount, exchange_rate, fx_quote_id, created_at, updated_at
+	FROM transactions WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY ` + order + ` LIMIT ?`
+	args = append(args, filter.Limit)
+
+	transactions := []*models.Transaction{}
+	err := r.DB.Select(&transactions, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// GetByAccountIDInPeriod retrieves the transactions of an account created from from up to to, oldest first, a page
+// at a time. The next page starts after the cursor of the last transaction of the previous one.
+func (r *TransactionRepositoryImpl) GetByAccountIDInPeriod(accountID string, from, to time.Time, after *models.TransactionCursor, limit int) ([]*models.Transaction, error) {
+	transactions := []*models.Transaction{}
+
+	query := `SELECT transaction_id, account_id, user_id, name, image, isBank, CONCAT(amount, ' ', currency) AS amount, transaction_type, direction, linked_transaction_id, reversal_of, CONCAT(reversed_amount, ' ', currency) AS reversed_amount, exchange_rate, fx_quote_id, created_at, updated_at
+	FROM transactions WHERE account_id = ? AND deleted_at IS NULL AND created_at >= ? AND created_at < ?`
+	args := []any{accountID, from, to}
+
+	if after != nil {
+		query += ` AND (created_at > ? OR (created_at = ? AND transaction_id > ?))`
+		args = append(args, after.CreatedAt, after.CreatedAt, after.TransactionID)
+	}
+
+	query += ` ORDER BY created_at, transaction_id LIMIT ?`
+	args = append(args, limit)
+
+	err := r.DB.Select(&transactions, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
 // Create adds a new transaction to the database.
 func (r *TransactionRepositoryImpl) Create(transaction *models.Transaction) error {
 	now := time.Now()
@@ -86,8 +220,9 @@ func (r *TransactionRepositoryImpl) Create(transaction *models.Transaction) erro
 	transaction.UpdatedAt = now
 
 	query := `INSERT INTO transactions (
-		transaction_id, user_id, account_id, name, image, isBank, amount, transaction_type, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		transaction_id, user_id, account_id, name, image, isBank, amount, currency, transaction_type,
+		direction, linked_transaction_id, reversal_of, exchange_rate, fx_quote_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := r.DB.Exec(
 		query,
@@ -98,7 +233,13 @@ func (r *TransactionRepositoryImpl) Create(transaction *models.Transaction) erro
 		transaction.Image,
 		transaction.IsBank,
 		transaction.Amount,
+		transaction.Amount.Currency,
 		transaction.TransactionType,
+		transaction.Direction,
+		transaction.LinkedTransactionID,
+		transaction.ReversalOf,
+		transaction.ExchangeRate,
+		transaction.FXQuoteID,
 		transaction.CreatedAt,
 		transaction.UpdatedAt,
 	)
@@ -114,8 +255,8 @@ func (r *TransactionRepositoryImpl) Update(transaction *models.Transaction) erro
 	transaction.UpdatedAt = time.Now()
 
 	query := `UPDATE transactions 
-              SET user_id = ?, name = ?, image = ?, isBank = ?, amount = ?, 
-                  transaction_type = ?, updated_at = ? 
+              SET user_id = ?, name = ?, image = ?, isBank = ?, amount = ?, currency = ?,
+                  transaction_type = ?, direction = ?, linked_transaction_id = ?, reversal_of = ?, reversed_amount = ?, updated_at = ? 
               WHERE transaction_id = ? and deleted_at IS NULL`
 
 	_, err := r.DB.Exec(
@@ -125,7 +266,12 @@ func (r *TransactionRepositoryImpl) Update(transaction *models.Transaction) erro
 		transaction.Image,
 		transaction.IsBank,
 		transaction.Amount,
+		transaction.Amount.Currency,
 		transaction.TransactionType,
+		transaction.Direction,
+		transaction.LinkedTransactionID,
+		transaction.ReversalOf,
+		transaction.ReversedAmount,
 		transaction.UpdatedAt,
 		transaction.TransactionID,
 	)
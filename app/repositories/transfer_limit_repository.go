@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/types"
+	"time"
+)
+
+// TransferLimitRepository is an interface for transfer limit operations
+type TransferLimitRepository interface {
+	GetApplicableLimits(userID, accountType, currency string) ([]*models.TransferLimit, error)
+	GetDebitTotalSince(accountID, currency string, since time.Time) (types.Money, error)
+}
+
+// TransferLimitRepositoryImpl implements TransferLimitRepository
+type TransferLimitRepositoryImpl struct {
+	DB DB
+}
+
+// NewTransferLimitRepository creates a new instance of TransferLimitRepository
+func NewTransferLimitRepository(db DB) TransferLimitRepository {
+	return &TransferLimitRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetApplicableLimits retrieves every limit row that applies to an account of the given user and type
+func (r *TransferLimitRepositoryImpl) GetApplicableLimits(userID, accountType, currency string) ([]*models.TransferLimit, error) {
+	limits := []*models.TransferLimit{}
+	query := `SELECT account_type, user_id, currency,
+			  CONCAT(per_transaction_limit, ' ', currency) AS per_transaction_limit,
+			  CONCAT(daily_limit, ' ', currency) AS daily_limit,
+			  CONCAT(monthly_limit, ' ', currency) AS monthly_limit,
+			  created_at, updated_at
+			  FROM transfer_limits
+			  WHERE currency = ? AND account_type IN (?, '') AND user_id IN (?, '')`
+	err := r.DB.Select(&limits, query, currency, accountType, userID)
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// GetDebitTotalSince sums the withdrawals and outgoing transfers of an account made since the given time.
+// Reversals do not give the allowance back.
+func (r *TransferLimitRepositoryImpl) GetDebitTotalSince(accountID, currency string, since time.Time) (types.Money, error) {
+	var total types.Money
+	query := `SELECT CONCAT(COALESCE(SUM(amount), 0), ' ', ?) FROM transactions
+			  WHERE account_id = ? AND direction = ? AND transaction_type IN (?, ?)
+			  AND created_at >= ? AND deleted_at IS NULL`
+	err := r.DB.Get(&total, query, currency, accountID, models.Debit, models.Withdrawal, models.Transfer, since)
+	if err != nil {
+		return types.Money{}, err
+	}
+	return total, nil
+}
@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"backend-developer-assignment/app/models"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WebhookRepository is an interface for webhook endpoints and their deliveries
+type WebhookRepository interface {
+	CreateEndpoint(endpoint *models.WebhookEndpoint) error
+	GetEndpointByID(endpointID string) (*models.WebhookEndpoint, error)
+	GetEndpointsByUserID(userID string) ([]*models.WebhookEndpoint, error)
+	GetEndpointsByScope(scope models.WebhookScope) ([]*models.WebhookEndpoint, error)
+	GetEndpointsForUsers(userIDs []string) ([]*models.WebhookEndpoint, error)
+	DeleteEndpoint(endpointID string) error
+
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	GetDelivery(endpointID string, deliveryID int64) (*models.WebhookDelivery, error)
+	ListDeliveries(endpointID string, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error)
+	ClaimDueDeliveries(leaseOwner string, now time.Time, leaseDuration time.Duration, limit int) ([]*models.WebhookDispatch, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	ResetDelivery(endpointID string, deliveryID int64, now time.Time) error
+}
+
+// WebhookRepositoryImpl implements WebhookRepository
+type WebhookRepositoryImpl struct {
+	DB DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(db DB) WebhookRepository {
+	return &WebhookRepositoryImpl{
+		DB: db,
+	}
+}
+
+const webhookEndpointColumns = `endpoint_id, user_id, scope, url, description, event_types, secret, created_at`
+
+const webhookDeliveryColumns = `delivery_id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, delivered_at, created_at`
+
+// CreateEndpoint creates a new webhook endpoint
+func (r *WebhookRepositoryImpl) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	query := `INSERT INTO webhook_endpoints (endpoint_id, user_id, scope, url, description, event_types, secret, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.DB.Exec(
+		query,
+		endpoint.EndpointID,
+		endpoint.UserID,
+		endpoint.Scope,
+		endpoint.URL,
+		endpoint.Description,
+		endpoint.EventTypes,
+		endpoint.Secret,
+		endpoint.CreatedAt,
+	)
+	return err
+}
+
+// GetEndpointByID retrieves an endpoint that has not been deleted
+func (r *WebhookRepositoryImpl) GetEndpointByID(endpointID string) (*models.WebhookEndpoint, error) {
+	endpoint := &models.WebhookEndpoint{}
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoints WHERE endpoint_id = ? AND deleted_at IS NULL`
+	err := r.DB.Get(endpoint, query, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// GetEndpointsByUserID retrieves the endpoints a user registered
+func (r *WebhookRepositoryImpl) GetEndpointsByUserID(userID string) ([]*models.WebhookEndpoint, error) {
+	endpoints := []*models.WebhookEndpoint{}
+	query := `SELECT ` + webhookEndpointColumns + `
+			  FROM webhook_endpoints WHERE user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC`
+	err := r.DB.Select(&endpoints, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// GetEndpointsByScope retrieves the endpoints of a scope
+func (r *WebhookRepositoryImpl) GetEndpointsByScope(scope models.WebhookScope) ([]*models.WebhookEndpoint, error) {
+	endpoints := []*models.WebhookEndpoint{}
+	query := `SELECT ` + webhookEndpointColumns + `
+			  FROM webhook_endpoints WHERE scope = ? AND deleted_at IS NULL ORDER BY created_at DESC`
+	err := r.DB.Select(&endpoints, query, scope)
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// GetEndpointsForUsers retrieves the endpoints receiving the events of the users: the endpoints the users registered
+// and the endpoints of the all scope
+func (r *WebhookRepositoryImpl) GetEndpointsForUsers(userIDs []string) ([]*models.WebhookEndpoint, error) {
+	endpoints := []*models.WebhookEndpoint{}
+	if len(userIDs) == 0 {
+		return endpoints, r.DB.Select(&endpoints, `SELECT `+webhookEndpointColumns+`
+			  FROM webhook_endpoints WHERE scope = ? AND deleted_at IS NULL`, models.WebhookScopeAll)
+	}
+
+	query, args, err := sqlx.In(`SELECT `+webhookEndpointColumns+`
+			  FROM webhook_endpoints WHERE deleted_at IS NULL AND (scope = ? OR (scope = ? AND user_id IN (?)))`,
+		models.WebhookScopeAll, models.WebhookScopeUser, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.DB.Select(&endpoints, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint soft deletes an endpoint, its pending deliveries are no longer attempted
+func (r *WebhookRepositoryImpl) DeleteEndpoint(endpointID string) error {
+	result, err := r.DB.Exec(`UPDATE webhook_endpoints SET deleted_at = NOW() WHERE endpoint_id = ? AND deleted_at IS NULL`, endpointID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateDelivery adds a delivery, an event relayed again does not add a second delivery to the same endpoint
+func (r *WebhookRepositoryImpl) CreateDelivery(delivery *models.WebhookDelivery) error {
+	query := `INSERT IGNORE INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, status, next_attempt_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.DB.Exec(
+		query,
+		delivery.EndpointID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.NextAttemptAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	delivery.DeliveryID, err = result.LastInsertId()
+	return err
+}
+
+// GetDelivery retrieves a delivery of an endpoint
+func (r *WebhookRepositoryImpl) GetDelivery(endpointID string, deliveryID int64) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE endpoint_id = ? AND delivery_id = ?`
+	err := r.DB.Get(delivery, query, endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ListDeliveries retrieves the deliveries of an endpoint matching the filter, newest first
+func (r *WebhookRepositoryImpl) ListDeliveries(endpointID string, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE endpoint_id = ?`
+	args := []interface{}{endpointID}
+
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.BeforeID > 0 {
+		query += ` AND delivery_id < ?`
+		args = append(args, filter.BeforeID)
+	}
+	query += ` ORDER BY delivery_id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	deliveries := []*models.WebhookDelivery{}
+	err := r.DB.Select(&deliveries, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries due for an attempt to leaseOwner and returns them with
+// their endpoints. The lease is taken with a single UPDATE so two instances can never claim the same delivery,
+// an expired lease (e.g. the owner crashed) can be claimed again.
+func (r *WebhookRepositoryImpl) ClaimDueDeliveries(leaseOwner string, now time.Time, leaseDuration time.Duration, limit int) ([]*models.WebhookDispatch, error) {
+	query := `UPDATE webhook_deliveries SET lease_owner = ?, lease_expires_at = ?
+			  WHERE status = ? AND next_attempt_at <= ?
+			  AND (lease_expires_at IS NULL OR lease_expires_at < ?)
+			  AND endpoint_id IN (SELECT endpoint_id FROM webhook_endpoints WHERE deleted_at IS NULL)
+			  ORDER BY next_attempt_at LIMIT ?`
+	_, err := r.DB.Exec(query, leaseOwner, now.Add(leaseDuration), models.WebhookDeliveryPending, now, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatches := []*models.WebhookDispatch{}
+	query = `SELECT d.delivery_id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+			 d.last_status_code, d.last_error, d.delivered_at, d.created_at, e.url, e.secret
+			 FROM webhook_deliveries d JOIN webhook_endpoints e ON e.endpoint_id = d.endpoint_id
+			 WHERE d.lease_owner = ? AND d.status = ? ORDER BY d.next_attempt_at`
+	err = r.DB.Select(&dispatches, query, leaseOwner, models.WebhookDeliveryPending)
+	if err != nil {
+		return nil, err
+	}
+	return dispatches, nil
+}
+
+// UpdateDelivery stores the result of an attempt and releases the lease of the delivery
+func (r *WebhookRepositoryImpl) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?,
+			  last_error = LEFT(?, 255), delivered_at = ?, lease_owner = NULL, lease_expires_at = NULL
+			  WHERE delivery_id = ?`
+	_, err := r.DB.Exec(
+		query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		delivery.DeliveryID,
+	)
+	return err
+}
+
+// ResetDelivery makes a delivery that is not pending due again with a fresh set of attempts
+func (r *WebhookRepositoryImpl) ResetDelivery(endpointID string, deliveryID int64, now time.Time) error {
+	query := `UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, last_error = '',
+			  lease_owner = NULL, lease_expires_at = NULL
+			  WHERE endpoint_id = ? AND delivery_id = ? AND status <> ?`
+	result, err := r.DB.Exec(query, models.WebhookDeliveryPending, now, endpointID, deliveryID, models.WebhookDeliveryPending)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -9,13 +9,36 @@ import (
 
 func AccountRoute(route fiber.Router, controller *controllers.Controller) {
 	// Group user routes with JWT protection
-	accountRoutes := route.Group("/accounts", middleware.AuthProtected()...)
+	accountRoutes := route.Group("/accounts", middleware.AuthProtected(controller.SessionRevocations)...)
 	accountRoutes.Get("/", controller.AccountController.ListAccounts)
-	accountRoutes.Get("/:id", controller.AccountController.GetAccount)
-	accountRoutes.Patch("/:id", controller.AccountController.UpdateAccount)
 	accountRoutes.Post("", controller.AccountController.CreateAccount)
-	accountRoutes.Put("/:id/main", controller.AccountController.SetMainAccount)
-	accountRoutes.Post("/:id/deposit", controller.AccountController.Deposit)
-	accountRoutes.Post("/:id/withdraw", controller.AccountController.Withdraw)
-	accountRoutes.Post("/:id/transfer", controller.AccountController.Transfer)
+
+	// Routes addressing a single account only serve the accounts of the authenticated user
+	owned := middleware.Owned("id", "Account not found", controller.Policy.AccountOwner)
+	accountRoutes.Get("/:id", owned, controller.AccountController.GetAccount)
+	accountRoutes.Patch("/:id", owned, controller.AccountController.UpdateAccount)
+	accountRoutes.Put("/:id/main", owned, controller.AccountController.SetMainAccount)
+	accountRoutes.Get("/:id/limits", owned, controller.TransferLimitController.GetAccountLimits)
+	accountRoutes.Get("/:id/statements", owned, controller.StatementController.GetStatement)
+	accountRoutes.Get("/:id/transactions", owned, controller.TransactionController.ListAccountTransactions)
+
+	// Money movement routes can be retried safely with an Idempotency-Key header
+	idempotent := middleware.Idempotency(controller.IdempotencyStore)
+	accountRoutes.Post("/:id/deposit", owned, idempotent, controller.AccountController.Deposit)
+	accountRoutes.Post("/:id/withdraw", owned, idempotent, controller.AccountController.Withdraw)
+	accountRoutes.Post("/:id/transfer", owned, idempotent, controller.AccountController.Transfer)
+
+	// Standing orders paid from the account
+	accountRoutes.Get("/:id/schedules", owned, controller.ScheduledTransferController.ListSchedules)
+	accountRoutes.Post("/:id/schedules", owned, controller.ScheduledTransferController.CreateSchedule)
+	accountRoutes.Get("/:id/schedules/:scheduleId", owned, controller.ScheduledTransferController.GetSchedule)
+	accountRoutes.Patch("/:id/schedules/:scheduleId", owned, controller.ScheduledTransferController.UpdateSchedule)
+	accountRoutes.Delete("/:id/schedules/:scheduleId", owned, controller.ScheduledTransferController.CancelSchedule)
+	accountRoutes.Get("/:id/schedules/:scheduleId/executions", owned, controller.ScheduledTransferController.ListExecutions)
+
+	// Funds reserved on the account, e.g. card authorizations
+	accountRoutes.Get("/:id/holds", owned, controller.HoldController.ListHolds)
+	accountRoutes.Post("/:id/holds", owned, controller.HoldController.PlaceHold)
+	accountRoutes.Post("/:id/holds/:holdId/capture", owned, idempotent, controller.HoldController.CaptureHold)
+	accountRoutes.Post("/:id/holds/:holdId/release", owned, controller.HoldController.ReleaseHold)
 }
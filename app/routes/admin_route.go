@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/pkg/middleware"
+	"backend-developer-assignment/pkg/types"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+// AdminRoute registers the routes of operations staff, they address resources of any user so every route
+// requires a permission instead of ownership
+func AdminRoute(route fiber.Router, controller *controllers.Controller) {
+	adminRoutes := route.Group("/admin", middleware.AuthProtected(controller.SessionRevocations)...)
+
+	usersRead := middleware.RequirePermission(types.PermissionUsersRead)
+	adminRoutes.Get("/users/:id", usersRead, controller.AdminController.GetUser)
+
+	accountsFreeze := middleware.RequirePermission(types.PermissionAccountsFreeze)
+	adminRoutes.Post("/accounts/:id/freeze", accountsFreeze, controller.AdminController.FreezeAccount)
+	adminRoutes.Post("/accounts/:id/unfreeze", accountsFreeze, controller.AdminController.UnfreezeAccount)
+
+	cardsStatus := middleware.RequirePermission(types.PermissionCardsStatus)
+	adminRoutes.Put("/debit-cards/:id/status", cardsStatus, controller.AdminController.UpdateCardStatus)
+
+	bannersManage := middleware.RequirePermission(types.PermissionBannersManage)
+	adminRoutes.Get("/users/:id/banners", bannersManage, controller.AdminController.ListUserBanners)
+	adminRoutes.Post("/banners", bannersManage, controller.AdminController.CreateBanner)
+	adminRoutes.Patch("/banners/:id", bannersManage, controller.AdminController.UpdateBanner)
+	adminRoutes.Delete("/banners/:id", bannersManage, controller.AdminController.DeleteBanner)
+
+	transactionsReverse := middleware.RequirePermission(types.PermissionTransactionsReverse)
+	adminRoutes.Post("/transactions/:id/reverse", transactionsReverse, middleware.Idempotency(controller.IdempotencyStore), controller.TransactionController.ReverseAnyTransaction)
+
+	auditRead := middleware.RequirePermission(types.PermissionAuditRead)
+	adminRoutes.Get("/audit-logs", auditRead, controller.AdminController.ListAuditLogs)
+	adminRoutes.Get("/accounts/:id/statements", auditRead, controller.StatementController.GetAnyStatement)
+
+	webhooksManage := middleware.RequirePermission(types.PermissionWebhooksManage)
+	adminRoutes.Get("/webhooks", webhooksManage, controller.WebhookController.ListGlobalWebhooks)
+	adminRoutes.Post("/webhooks", webhooksManage, controller.WebhookController.CreateGlobalWebhook)
+	adminRoutes.Delete("/webhooks/:id", webhooksManage, controller.WebhookController.DeleteAnyWebhook)
+	adminRoutes.Get("/webhooks/:id/deliveries", webhooksManage, controller.WebhookController.ListDeliveries)
+	adminRoutes.Post("/webhooks/:id/deliveries/:deliveryId/replay", webhooksManage, controller.WebhookController.ReplayDelivery)
+}
@@ -9,5 +9,9 @@ import (
 
 func AuthRoute(route fiber.Router, controller *controllers.Controller) {
 	route.Post("/auth/verify-pin", controller.AuthController.VerifyPin)
+	route.Post("/auth/pin-reset", controller.AuthController.RequestPinReset)
+	route.Post("/auth/pin-reset/confirm", controller.AuthController.ConfirmPinReset)
+	route.Post("/auth/logout", append(middleware.AuthProtected(controller.SessionRevocations), controller.AuthController.Logout)...)
+	route.Post("/auth/logout-all", append(middleware.AuthProtected(controller.SessionRevocations), controller.AuthController.LogoutAll)...)
 	route.Post("/token/renew", middleware.JWTProtected(), controller.AuthController.RenewTokens)
 }
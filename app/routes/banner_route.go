@@ -8,7 +8,7 @@ import (
 )
 
 func BannerRoute(route fiber.Router, controller *controllers.Controller) {
-	bannerRoutes := route.Group("/banners", middleware.AuthProtected()...)
+	bannerRoutes := route.Group("/banners", middleware.AuthProtected(controller.SessionRevocations)...)
 	bannerRoutes.Get("/", controller.BannerController.ListBanners)
-	bannerRoutes.Get("/:id", controller.BannerController.GetBanner)
+	bannerRoutes.Get("/:id", middleware.Owned("id", "Banner not found", controller.Policy.BannerOwner), controller.BannerController.GetBanner)
 }
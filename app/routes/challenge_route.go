@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/pkg/middleware"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+func ChallengeRoute(route fiber.Router, controller *controllers.Controller) {
+	challengeRoutes := route.Group("/challenges", middleware.AuthProtected(controller.SessionRevocations)...)
+
+	// Confirming runs the held transfer, so retries are safe with an Idempotency-Key header
+	owned := middleware.Owned("id", "Challenge not found", controller.Policy.ChallengeOwner)
+	challengeRoutes.Post("/:id/confirm", owned, middleware.Idempotency(controller.IdempotencyStore), controller.ChallengeController.ConfirmChallenge)
+}
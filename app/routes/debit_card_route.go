@@ -9,10 +9,13 @@ import (
 
 func DebitCardRoute(route fiber.Router, controller *controllers.Controller) {
 	// Group user routes with JWT protection
-	debitCardRoutes := route.Group("/debit-cards", middleware.AuthProtected()...)
+	debitCardRoutes := route.Group("/debit-cards", middleware.AuthProtected(controller.SessionRevocations)...)
 	debitCardRoutes.Get("", controller.DebitCardController.ListDebitCards)
-	debitCardRoutes.Get("/:id", controller.DebitCardController.GetDebitCard)
 	debitCardRoutes.Post("", controller.DebitCardController.CreateDebitCard)
-	debitCardRoutes.Put("/:id", controller.DebitCardController.UpdateDebitCard)
-	debitCardRoutes.Delete("/:id", controller.DebitCardController.DeleteDebitCard)
+
+	// Routes addressing a single card only serve the cards of the authenticated user
+	owned := middleware.Owned("id", "Debit card not found", controller.Policy.DebitCardOwner)
+	debitCardRoutes.Get("/:id", owned, controller.DebitCardController.GetDebitCard)
+	debitCardRoutes.Put("/:id", owned, controller.DebitCardController.UpdateDebitCard)
+	debitCardRoutes.Delete("/:id", owned, controller.DebitCardController.DeleteDebitCard)
 }
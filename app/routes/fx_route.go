@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/pkg/middleware"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+func FXRoute(route fiber.Router, controller *controllers.Controller) {
+	fxRoutes := route.Group("/fx", middleware.AuthProtected(controller.SessionRevocations)...)
+	fxRoutes.Post("/quotes", controller.FXController.CreateQuote)
+}
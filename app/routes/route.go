@@ -15,7 +15,13 @@ func InitRoutes(app *fiber.App, controller *controllers.Controller) {
 	TransactionRoute(route, controller)
 	DebitCardRoute(route, controller)
 	BannerRoute(route, controller)
+	FXRoute(route, controller)
+	ChallengeRoute(route, controller)
+	WebhookRoute(route, controller)
+	StreamRoute(route, controller)
+	AdminRoute(route, controller)
 
-	SwaggerRoute(app)  // Register a route for API Docs (Swagger).
-	NotFoundRoute(app) // Register route for 404 Error.
+	WellKnownRoute(app, controller) // Register the JWKS document of the access token keys.
+	SwaggerRoute(app)               // Register a route for API Docs (Swagger).
+	NotFoundRoute(app)              // Register route for 404 Error.
 }
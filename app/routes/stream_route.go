@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/pkg/middleware"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+func StreamRoute(route fiber.Router, controller *controllers.Controller) {
+	streamRoutes := route.Group("/stream", middleware.AuthProtected(controller.SessionRevocations)...)
+	// Server-Sent Events, or a WebSocket when the request asks for an upgrade
+	streamRoutes.Get("/", controller.StreamController.Stream)
+}
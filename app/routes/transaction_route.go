@@ -9,6 +9,7 @@ import (
 
 func TransactionRoute(route fiber.Router, controller *controllers.Controller) {
 	// Group user routes with JWT protection
-	transactionRoutes := route.Group("/transactions", middleware.AuthProtected()...)
+	transactionRoutes := route.Group("/transactions", middleware.AuthProtected(controller.SessionRevocations)...)
 	transactionRoutes.Get("", controller.TransactionController.ListTransactions)
+	transactionRoutes.Post("/:id/reverse", middleware.Idempotency(controller.IdempotencyStore), controller.TransactionController.ReverseTransaction)
 }
@@ -9,9 +9,14 @@ import (
 
 func UserRoute(route fiber.Router, controller *controllers.Controller) {
 	// Group user routes with JWT protection
-	userRoutes := route.Group("/user", middleware.AuthProtected()...)
+	userRoutes := route.Group("/user", middleware.AuthProtected(controller.SessionRevocations)...)
 	userRoutes.Get("/greeting", controller.UserController.GetUserGreeting)
 	userRoutes.Put("/greeting", controller.UserController.UpdateUserGreeting)
 	userRoutes.Get("/profile", controller.UserController.GetUser)
 	userRoutes.Patch("/profile", controller.UserController.UpdateUser)
+	userRoutes.Put("/pin", controller.UserController.ChangePin)
+	userRoutes.Post("/totp", controller.UserController.EnrollTOTP)
+	userRoutes.Post("/totp/enable", controller.UserController.EnableTOTP)
+	userRoutes.Get("/sessions", controller.AuthController.ListSessions)
+	userRoutes.Delete("/sessions/:id", controller.AuthController.RevokeSession)
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/pkg/middleware"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+func WebhookRoute(route fiber.Router, controller *controllers.Controller) {
+	webhookRoutes := route.Group("/webhooks", middleware.AuthProtected(controller.SessionRevocations)...)
+	webhookRoutes.Get("/", controller.WebhookController.ListWebhooks)
+	webhookRoutes.Post("/", controller.WebhookController.CreateWebhook)
+
+	owned := middleware.Owned("id", "Webhook not found", controller.Policy.WebhookOwner)
+	webhookRoutes.Delete("/:id", owned, controller.WebhookController.DeleteWebhook)
+	webhookRoutes.Get("/:id/deliveries", owned, controller.WebhookController.ListDeliveries)
+	webhookRoutes.Post("/:id/deliveries/:deliveryId/replay", owned, controller.WebhookController.ReplayDelivery)
+}
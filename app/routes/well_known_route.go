@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend-developer-assignment/app/controllers"
+
+	fiber "github.com/gofiber/fiber/v2"
+)
+
+// WellKnownRoute registers the public documents other services discover us with, outside of the API prefix
+func WellKnownRoute(a *fiber.App, controller *controllers.Controller) {
+	route := a.Group("/.well-known")
+	route.Get("/jwks.json", controller.WellKnownController.GetJWKS)
+}
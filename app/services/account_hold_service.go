@@ -0,0 +1,266 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Custom errors for account holds
+var (
+	ErrHoldNotFound       = errors.New("hold not found")
+	ErrInvalidHold        = errors.New("invalid hold")
+	ErrHoldNotActive      = errors.New("hold is no longer active")
+	ErrHoldExpired        = errors.New("hold expired")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+)
+
+// GetHolds retrieves the holds placed on an account, newest first
+func (s *AccountServiceImpl) GetHolds(accountID string) ([]*models.AccountHold, error) {
+	return s.holdRepository.GetHoldsByAccountID(accountID)
+}
+
+// PlaceHold reserves amount on an account until expiresAt, a zero expiresAt uses configs.HOLD_DEFAULT_TTL.
+// The held funds stay in the ledger balance but can no longer be withdrawn or transferred.
+func (s *AccountServiceImpl) PlaceHold(accountID string, amount types.Money, description string, expiresAt time.Time) (*models.AccountHold, error) {
+	if !amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+
+	now := time.Now()
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(configs.HOLD_DEFAULT_TTL)
+	}
+	if !expiresAt.After(now) || expiresAt.After(now.Add(configs.HOLD_MAX_TTL)) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future and within %s", ErrInvalidHold, configs.HOLD_MAX_TTL)
+	}
+
+	account, err := s.GetAccountWithDetailByID(accountID)
+	if err != nil {
+		logger.Error("Failed to get account details", zap.String("account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+
+	if account.IsFrozen() {
+		return nil, ErrAccountFrozen
+	}
+
+	if amount.Currency != account.Currency {
+		return nil, ErrCurrencyMismatch
+	}
+
+	hold := &models.AccountHold{
+		HoldID:         uuid.New().String(),
+		AccountID:      accountID,
+		UserID:         account.UserID,
+		Amount:         amount,
+		CapturedAmount: types.NewMoney(0, amount.Currency),
+		Description:    description,
+		Status:         models.HoldActive,
+		ExpiresAt:      expiresAt,
+	}
+
+	err = s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		// The balance is left unchanged, locking it serializes the hold with withdrawals and transfers
+		balanceErr := adapters.AccountRepository.UpdateAccountBalance(accountID, func(currentBalance types.Money) (types.Money, error) {
+			available, err := availableBalance(adapters.HoldRepository, accountID, currentBalance)
+			if err != nil {
+				return types.Money{}, err
+			}
+			if available.LessThan(amount) {
+				return types.Money{}, ErrInsufficientFunds
+			}
+			return currentBalance, nil
+		})
+		if balanceErr != nil {
+			return balanceErr
+		}
+
+		return adapters.HoldRepository.CreateHold(hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// CaptureHold debits a hold from the account with a withdrawal. A nil amount captures the full hold, a smaller
+// amount captures part of it and releases the rest. Like a withdrawal, a capture is refused on a frozen account and
+// counts against the transfer limits.
+func (s *AccountServiceImpl) CaptureHold(accountID, holdID string, amount *types.Money) (*models.AccountHold, error) {
+	var hold *models.AccountHold
+
+	account, err := s.GetAccountWithDetailByID(accountID)
+	if err != nil {
+		logger.Error("Failed to get account details", zap.String("account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+
+	if account.IsFrozen() {
+		return nil, ErrAccountFrozen
+	}
+
+	err = s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		var captured, updatedBalance types.Money
+
+		// The balance is locked before the hold, the same order withdrawals and transfers read holds in
+		balanceErr := adapters.AccountRepository.UpdateAccountBalance(accountID, func(currentBalance types.Money) (types.Money, error) {
+			var err error
+			if hold, err = lockActiveHold(adapters.HoldRepository, accountID, holdID); err != nil {
+				return types.Money{}, err
+			}
+
+			captured = hold.Amount
+			if amount != nil {
+				switch {
+				case !amount.IsPositive():
+					return types.Money{}, ErrInvalidAmount
+				case amount.Currency != hold.Amount.Currency:
+					return types.Money{}, ErrCurrencyMismatch
+				case hold.Amount.LessThan(*amount):
+					return types.Money{}, ErrCaptureExceedsHold
+				}
+				captured = *amount
+			}
+
+			// The held funds were reserved, only a balance changed outside of the available checks can fall short
+			if currentBalance.LessThan(captured) {
+				return types.Money{}, ErrInsufficientFunds
+			}
+
+			// The balance row is locked, so concurrent withdrawals are already counted against the limits
+			if err := checkTransferLimits(adapters.TransferLimitRepository, accountID, account.UserID, account.Type, captured); err != nil {
+				return types.Money{}, err
+			}
+			updatedBalance, err = currentBalance.Sub(captured)
+			return updatedBalance, err
+		})
+		if balanceErr != nil {
+			return balanceErr
+		}
+
+		name := hold.Description
+		if name == "" {
+			name = "Card payment"
+		}
+
+		withdrawalTx := &models.Transaction{
+			BaseModel:       &models.BaseModel{},
+			TransactionID:   uuid.New().String(),
+			UserID:          hold.UserID,
+			Name:            name,
+			IsBank:          true,
+			Amount:          captured,
+			TransactionType: string(models.Withdrawal),
+			Direction:       models.Debit,
+			AccountID:       accountID,
+		}
+
+		if err := adapters.TransactionRepository.Create(withdrawalTx); err != nil {
+			logger.Error("Failed to create hold capture transaction record",
+				zap.String("account_id", accountID),
+				zap.String("hold_id", holdID),
+				zap.Error(err))
+			return err
+		}
+
+		if err := recordEvent(adapters.OutboxRepository, models.EventFundsWithdrawn, models.AggregateAccount, accountID, "",
+			models.FundsMovedPayload{AccountID: accountID, UserID: hold.UserID, TransactionID: withdrawalTx.TransactionID, Amount: captured, Balance: updatedBalance}); err != nil {
+			return err
+		}
+
+		// Money leaves the bank: debit the customer account, credit the external account
+		entry := newJournalEntry(models.WithdrawalEntry, withdrawalTx.TransactionID, withdrawalTx.Name,
+			accountID, externalLedgerAccount(captured.Currency), captured)
+		if err := postJournalEntry(adapters.LedgerRepository, entry); err != nil {
+			return err
+		}
+
+		hold.Status = models.HoldCaptured
+		hold.CapturedAmount = captured
+		hold.TransactionID = &withdrawalTx.TransactionID
+		return adapters.HoldRepository.UpdateHold(hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateTransactionLists(s.redisClient, hold.UserID, accountID)
+	return hold, nil
+}
+
+// ReleaseHold cancels a hold, making its funds available again
+func (s *AccountServiceImpl) ReleaseHold(accountID, holdID string) (*models.AccountHold, error) {
+	var hold *models.AccountHold
+
+	// Releasing only frees funds, so the balance does not need to be locked
+	err := s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		var err error
+		if hold, err = lockActiveHold(adapters.HoldRepository, accountID, holdID); err != nil {
+			return err
+		}
+
+		hold.Status = models.HoldReleased
+		return adapters.HoldRepository.UpdateHold(hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ExpireHolds marks the holds that passed their expiry, it is called periodically by the scheduler.
+// Expired holds already stop counting against the available balance, this only keeps their status accurate.
+func (s *AccountServiceImpl) ExpireHolds(_ context.Context) error {
+	expired, err := s.holdRepository.ExpireHolds(time.Now())
+	if err != nil {
+		logger.Error("Failed to expire holds", zap.Error(err))
+		return err
+	}
+	if expired > 0 {
+		logger.Info("Expired holds", zap.Int64("count", expired))
+	}
+	return nil
+}
+
+// lockActiveHold locks a hold of the account that can still be captured or released
+func lockActiveHold(holdRepository repositories.HoldRepository, accountID, holdID string) (*models.AccountHold, error) {
+	hold, err := holdRepository.GetHoldByIDForUpdate(holdID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrHoldNotFound
+		}
+		return nil, err
+	}
+
+	switch {
+	case hold.AccountID != accountID:
+		return nil, ErrHoldNotFound
+	case hold.Status != models.HoldActive:
+		return nil, fmt.Errorf("%w: hold is %s", ErrHoldNotActive, hold.Status)
+	case hold.IsExpired(time.Now()):
+		return nil, ErrHoldExpired
+	}
+
+	return hold, nil
+}
+
+// availableBalance is the balance of an account less its active holds. The balance row must be locked by the caller.
+func availableBalance(holdRepository repositories.HoldRepository, accountID string, balance types.Money) (types.Money, error) {
+	held, err := holdRepository.GetHeldAmount(accountID, balance.Currency, time.Now())
+	if err != nil {
+		return types.Money{}, err
+	}
+	return balance.Sub(held)
+}
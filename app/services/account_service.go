@@ -4,7 +4,9 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
 	"backend-developer-assignment/pkg/types"
+	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -13,6 +15,11 @@ import (
 // Custom errors for account operations
 var (
 	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrInvalidAmount     = errors.New("amount must be greater than zero")
+	ErrCurrencyMismatch  = types.ErrCurrencyMismatch
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrAccountNotFrozen  = errors.New("account is not frozen")
+	ErrSameAccount       = errors.New("source and destination accounts must differ")
 )
 
 // AccountService defines the interface for account operations
@@ -30,10 +37,25 @@ type AccountService interface {
 	UpdateAccount(account *models.AccountWithDetails) error
 	SetMainAccount(account *models.Account) error
 
+	// Freeze operations, a frozen account cannot move money until it is unfrozen
+	FreezeAccount(accountID string) (*models.AccountWithDetails, error)
+	UnfreezeAccount(accountID string) (*models.AccountWithDetails, error)
+
 	// Transaction operations
-	WithdrawFromAccount(accountID string, amount float64) (float64, error)
-	TransferBetweenAccounts(fromAccountID, toAccountID string, amount float64) (*types.TransferResult, error)
-	DepositToAccount(accountID string, amount float64) (float64, error)
+	WithdrawFromAccount(accountID string, amount types.Money) (types.Money, error)
+	TransferBetweenAccounts(fromAccountID, toAccountID string, amount types.Money) (*types.TransferResult, error)
+	// TransferBetweenAccountsWith runs inTx in the transaction of the transfer, an error of inTx rolls it back
+	TransferBetweenAccountsWith(fromAccountID, toAccountID string, amount types.Money, inTx func(adapters repositories.Adapters) error) (*types.TransferResult, error)
+	TransferWithQuote(fromAccountID, toAccountID string, amount types.Money, quoteID string) (*types.TransferResult, error)
+	DepositToAccount(accountID string, amount types.Money) (types.Money, error)
+
+	// Hold operations
+	GetHolds(accountID string) ([]*models.AccountHold, error)
+	PlaceHold(accountID string, amount types.Money, description string, expiresAt time.Time) (*models.AccountHold, error)
+	CaptureHold(accountID, holdID string, amount *types.Money) (*models.AccountHold, error)
+	ReleaseHold(accountID, holdID string) (*models.AccountHold, error)
+	// ExpireHolds marks the holds that passed their expiry, it is called periodically by the scheduler
+	ExpireHolds(ctx context.Context) error
 
 	// Delete operations
 	DeleteAccount(accountID string) error
@@ -43,15 +65,19 @@ type AccountService interface {
 type AccountServiceImpl struct {
 	accountRepository     repositories.AccountRepository
 	transactionRepository repositories.TransactionRepository
+	holdRepository        repositories.HoldRepository
 	txProvider            repositories.TxProvider
+	redisClient           types.CacheClient // holds the cached transaction listings of the users and accounts
 }
 
 // NewAccountService creates a new instance of AccountService
-func NewAccountService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, txProvider repositories.TxProvider) AccountService {
+func NewAccountService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, holdRepo repositories.HoldRepository, txProvider repositories.TxProvider, redisClient types.CacheClient) AccountService {
 	return &AccountServiceImpl{
 		accountRepository:     accountRepo,
 		transactionRepository: transactionRepo,
+		holdRepository:        holdRepo,
 		txProvider:            txProvider,
+		redisClient:           redisClient,
 	}
 }
 
@@ -82,11 +108,37 @@ func (s *AccountServiceImpl) CreateAccountWithDetails(accountWithDetails *models
 		accountWithDetails.AccountID = uuid.New().String()
 	}
 
-	if err := s.accountRepository.CreateAccount(accountWithDetails); err != nil {
-		return err
-	}
+	return s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		if err := adapters.AccountRepository.CreateAccount(accountWithDetails); err != nil {
+			return err
+		}
 
-	return nil
+		if err := recordEvent(adapters.OutboxRepository, models.EventAccountCreated, models.AggregateAccount, accountWithDetails.AccountID, "",
+			models.AccountCreatedPayload{
+				AccountID: accountWithDetails.AccountID,
+				UserID:    accountWithDetails.UserID,
+				Type:      accountWithDetails.Type,
+				Currency:  accountWithDetails.Currency,
+				Balance:   accountWithDetails.Amount,
+			}); err != nil {
+			return err
+		}
+
+		if accountWithDetails.Amount.IsZero() {
+			return nil
+		}
+
+		// A non-zero initial balance must be backed by an opening-balance entry in the ledger
+		amount := accountWithDetails.Amount
+		debitAccountID, creditAccountID := externalLedgerAccount(amount.Currency), accountWithDetails.AccountID
+		if amount.IsNegative() {
+			amount = amount.Neg()
+			debitAccountID, creditAccountID = creditAccountID, debitAccountID
+		}
+
+		entry := newJournalEntry(models.OpeningBalanceEntry, accountWithDetails.AccountID, "Opening balance", debitAccountID, creditAccountID, amount)
+		return postJournalEntry(adapters.LedgerRepository, entry)
+	})
 }
 
 // UpdateAccount updates an existing account
@@ -142,28 +194,49 @@ func (s *AccountServiceImpl) SetMainAccount(account *models.Account) error {
 }
 
 // WithdrawFromAccount withdraws money from an account with proper locking to prevent race conditions
-func (s *AccountServiceImpl) WithdrawFromAccount(accountID string, amount float64) (float64, error) {
-	var updatedBalance float64
+func (s *AccountServiceImpl) WithdrawFromAccount(accountID string, amount types.Money) (types.Money, error) {
+	var updatedBalance types.Money
+
+	if !amount.IsPositive() {
+		return types.Money{}, ErrInvalidAmount
+	}
 
 	// Get account details for transaction record
 	account, err := s.GetAccountWithDetailByID(accountID)
 	if err != nil {
 		logger.Error("Failed to get account details", zap.String("account_id", accountID), zap.Error(err))
-		return 0, err
+		return types.Money{}, err
+	}
+
+	if account.IsFrozen() {
+		return types.Money{}, ErrAccountFrozen
+	}
+
+	if amount.Currency != account.Currency {
+		return types.Money{}, ErrCurrencyMismatch
 	}
 
 	// Use transaction provider to handle the transaction
 	err = s.txProvider.Transact(func(adapters repositories.Adapters) error {
 		// Update account balance within transaction
-		balanceErr := adapters.AccountRepository.UpdateAccountBalance(accountID, func(currentBalance float64) (float64, error) {
-			// Check if there are sufficient funds
-			if currentBalance < amount {
-				return 0, ErrInsufficientFunds
+		balanceErr := adapters.AccountRepository.UpdateAccountBalance(accountID, func(currentBalance types.Money) (types.Money, error) {
+			// Check if there are sufficient funds, held funds cannot be withdrawn
+			available, err := availableBalance(adapters.HoldRepository, accountID, currentBalance)
+			if err != nil {
+				return types.Money{}, err
+			}
+			if available.LessThan(amount) {
+				return types.Money{}, ErrInsufficientFunds
+			}
+
+			// The balance row is locked, so concurrent withdrawals are already counted against the limits
+			if err := checkTransferLimits(adapters.TransferLimitRepository, accountID, account.UserID, account.Type, amount); err != nil {
+				return types.Money{}, err
 			}
 
 			// Calculate the new balance
-			updatedBalance = currentBalance - amount
-			return updatedBalance, nil
+			updatedBalance, err = currentBalance.Sub(amount)
+			return updatedBalance, err
 		})
 
 		if balanceErr != nil {
@@ -179,6 +252,7 @@ func (s *AccountServiceImpl) WithdrawFromAccount(accountID string, amount float6
 			IsBank:          true,
 			Amount:          amount,
 			TransactionType: string(models.Withdrawal),
+			Direction:       models.Debit,
 			AccountID:       accountID,
 		}
 
@@ -190,34 +264,56 @@ func (s *AccountServiceImpl) WithdrawFromAccount(accountID string, amount float6
 			return err
 		}
 
-		return nil
+		if err := recordEvent(adapters.OutboxRepository, models.EventFundsWithdrawn, models.AggregateAccount, accountID, "",
+			models.FundsMovedPayload{AccountID: accountID, UserID: account.UserID, TransactionID: withdrawalTx.TransactionID, Amount: amount, Balance: updatedBalance}); err != nil {
+			return err
+		}
+
+		// Money leaves the bank: debit the customer account, credit the external account
+		entry := newJournalEntry(models.WithdrawalEntry, withdrawalTx.TransactionID, withdrawalTx.Name,
+			accountID, externalLedgerAccount(amount.Currency), amount)
+		return postJournalEntry(adapters.LedgerRepository, entry)
 	})
 
 	if err != nil {
-		return 0, err
+		return types.Money{}, err
 	}
 
+	invalidateTransactionLists(s.redisClient, account.UserID, accountID)
 	return updatedBalance, nil
 }
 
 // DepositToAccount deposits money to an account with proper locking to prevent race conditions
-func (s *AccountServiceImpl) DepositToAccount(accountID string, amount float64) (float64, error) {
-	var updatedBalance float64
+func (s *AccountServiceImpl) DepositToAccount(accountID string, amount types.Money) (types.Money, error) {
+	var updatedBalance types.Money
+
+	if !amount.IsPositive() {
+		return types.Money{}, ErrInvalidAmount
+	}
 
 	// Get account details for transaction record
 	account, err := s.GetAccountWithDetailByID(accountID)
 	if err != nil {
 		logger.Error("Failed to get account details", zap.String("account_id", accountID), zap.Error(err))
-		return 0, err
+		return types.Money{}, err
+	}
+
+	if account.IsFrozen() {
+		return types.Money{}, ErrAccountFrozen
+	}
+
+	if amount.Currency != account.Currency {
+		return types.Money{}, ErrCurrencyMismatch
 	}
 
 	// Use transaction provider to handle the transaction
 	err = s.txProvider.Transact(func(adapters repositories.Adapters) error {
 		// Update account balance within transaction
-		balanceErr := adapters.AccountRepository.UpdateAccountBalance(accountID, func(currentBalance float64) (float64, error) {
+		balanceErr := adapters.AccountRepository.UpdateAccountBalance(accountID, func(currentBalance types.Money) (types.Money, error) {
 			// Calculate the new balance
-			updatedBalance = currentBalance + amount
-			return updatedBalance, nil
+			var err error
+			updatedBalance, err = currentBalance.Add(amount)
+			return updatedBalance, err
 		})
 
 		if balanceErr != nil {
@@ -233,6 +329,7 @@ func (s *AccountServiceImpl) DepositToAccount(accountID string, amount float64)
 			IsBank:          true,
 			Amount:          amount,
 			TransactionType: string(models.Deposit),
+			Direction:       models.Credit,
 			AccountID:       accountID,
 		}
 
@@ -244,21 +341,57 @@ func (s *AccountServiceImpl) DepositToAccount(accountID string, amount float64)
 			return err
 		}
 
-		return nil
+		if err := recordEvent(adapters.OutboxRepository, models.EventFundsDeposited, models.AggregateAccount, accountID, "",
+			models.FundsMovedPayload{AccountID: accountID, UserID: account.UserID, TransactionID: depositTx.TransactionID, Amount: amount, Balance: updatedBalance}); err != nil {
+			return err
+		}
+
+		// Money enters the bank: debit the external account, credit the customer account
+		entry := newJournalEntry(models.DepositEntry, depositTx.TransactionID, depositTx.Name,
+			externalLedgerAccount(amount.Currency), accountID, amount)
+		return postJournalEntry(adapters.LedgerRepository, entry)
 	})
 
 	if err != nil {
-		return 0, err
+		return types.Money{}, err
 	}
 
+	invalidateTransactionLists(s.redisClient, account.UserID, accountID)
 	return updatedBalance, nil
 }
 
-// TransferBetweenAccounts transfers money between accounts with proper locking to prevent race conditions
-func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID string, amount float64) (*types.TransferResult, error) {
+// TransferBetweenAccounts transfers money between accounts of the same currency with proper locking to prevent race conditions
+func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID string, amount types.Money) (*types.TransferResult, error) {
+	return s.transfer(fromAccountID, toAccountID, amount, "", nil)
+}
+
+// TransferBetweenAccountsWith transfers money like TransferBetweenAccounts and runs inTx in the same database
+// transaction once the transfer is written, so that bookkeeping of the caller commits or rolls back with it
+func (s *AccountServiceImpl) TransferBetweenAccountsWith(fromAccountID, toAccountID string, amount types.Money, inTx func(adapters repositories.Adapters) error) (*types.TransferResult, error) {
+	return s.transfer(fromAccountID, toAccountID, amount, "", inTx)
+}
+
+// TransferWithQuote transfers money to an account of another currency at the rate locked by an fx quote.
+// The amount is in the source currency and must match the quote, which is consumed by the transfer.
+func (s *AccountServiceImpl) TransferWithQuote(fromAccountID, toAccountID string, amount types.Money, quoteID string) (*types.TransferResult, error) {
+	return s.transfer(fromAccountID, toAccountID, amount, quoteID, nil)
+}
+
+// transfer moves amount out of the source account and credits the destination, converted at the quote's rate
+// when the accounts use different currencies. A non-nil inTx runs last in the transaction of the transfer.
+func (s *AccountServiceImpl) transfer(fromAccountID, toAccountID string, amount types.Money, quoteID string, inTx func(adapters repositories.Adapters) error) (*types.TransferResult, error) {
 	// Use a transaction with row locking to prevent race conditions
 	result := &types.TransferResult{}
 
+	if !amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+
+	// Both legs would update the same balance row, crediting the amount without the debit
+	if fromAccountID == toAccountID {
+		return nil, ErrSameAccount
+	}
+
 	// Get source and destination account details for transaction records
 	sourceAccount, err := s.GetAccountWithDetailByID(fromAccountID)
 	if err != nil {
@@ -272,18 +405,60 @@ func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID
 		return nil, err
 	}
 
+	if sourceAccount.IsFrozen() || destAccount.IsFrozen() {
+		return nil, ErrAccountFrozen
+	}
+
+	if amount.Currency != sourceAccount.Currency {
+		return nil, ErrCurrencyMismatch
+	}
+
+	crossCurrency := destAccount.Currency != sourceAccount.Currency
+	if crossCurrency && quoteID == "" {
+		return nil, ErrQuoteRequired
+	}
+	if !crossCurrency && quoteID != "" {
+		return nil, ErrQuoteMismatch
+	}
+
 	// Begin a database transaction that encompasses both the fund transfer and transaction record creation
 	err = s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		// The quote is consumed in the same transaction, a failed transfer leaves it usable
+		credited := amount
+		var quote *models.FXQuote
+		if crossCurrency {
+			var err error
+			if quote, err = useQuote(adapters.FXRepository, quoteID, sourceAccount.UserID, destAccount.Currency, amount); err != nil {
+				return err
+			}
+			credited = quote.TargetAmount
+			result.ExchangeRate = &quote.Rate
+		}
+		result.CreditedAmount = credited
+
 		// Transfer funds within the transaction
-		transferErr := adapters.AccountRepository.TransferFunds(fromAccountID, toAccountID, amount, func(sourceBalance, destBalance float64) (*types.TransferResult, error) {
-			// Check if source account has sufficient funds
-			if sourceBalance < amount {
+		transferErr := adapters.AccountRepository.TransferFunds(fromAccountID, toAccountID, amount, func(sourceBalance, destBalance types.Money) (*types.TransferResult, error) {
+			// Check if source account has sufficient funds, held funds cannot be transferred
+			available, err := availableBalance(adapters.HoldRepository, fromAccountID, sourceBalance)
+			if err != nil {
+				return nil, err
+			}
+			if available.LessThan(amount) {
 				return nil, ErrInsufficientFunds
 			}
 
+			// Both balance rows are locked, so concurrent debits of the source account are already counted against its limits
+			if err := checkTransferLimits(adapters.TransferLimitRepository, fromAccountID, sourceAccount.UserID, sourceAccount.Type, amount); err != nil {
+				return nil, err
+			}
+
 			// Calculate the new balances
-			result.SourceBalance = sourceBalance - amount
-			result.DestinationBalance = destBalance + amount
+			if result.SourceBalance, err = sourceBalance.Sub(amount); err != nil {
+				return nil, err
+			}
+			if result.DestinationBalance, err = destBalance.Add(credited); err != nil {
+				return nil, err
+			}
 
 			return result, nil
 		})
@@ -302,6 +477,7 @@ func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID
 			IsBank:          true,
 			Amount:          amount,
 			TransactionType: string(models.Transfer),
+			Direction:       models.Debit,
 			AccountID:       fromAccountID,
 		}
 
@@ -312,11 +488,24 @@ func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID
 			UserID:          destAccount.UserID,
 			Name:            "Transfer from " + sourceAccount.AccountNumber,
 			IsBank:          true,
-			Amount:          amount,
+			Amount:          credited,
 			TransactionType: string(models.Transfer),
+			Direction:       models.Credit,
 			AccountID:       toAccountID,
 		}
 
+		// Link both legs so the transfer can be reversed as a whole
+		withdrawalTx.LinkedTransactionID = &depositTx.TransactionID
+		depositTx.LinkedTransactionID = &withdrawalTx.TransactionID
+
+		// Each leg is in its own account's currency and keeps the rate it was converted at
+		if quote != nil {
+			for _, leg := range []*models.Transaction{withdrawalTx, depositTx} {
+				leg.ExchangeRate = &quote.Rate
+				leg.FXQuoteID = &quote.QuoteID
+			}
+		}
+
 		// Save the transaction records within the same database transaction
 		if err := adapters.TransactionRepository.Create(withdrawalTx); err != nil {
 			logger.Error("Failed to create withdrawal transaction record",
@@ -336,6 +525,41 @@ func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID
 			return err
 		}
 
+		// The event is ordered with the events of both accounts
+		if err := recordEvent(adapters.OutboxRepository, models.EventTransferCompleted, models.AggregateAccount, fromAccountID, toAccountID,
+			models.TransferCompletedPayload{
+				FromAccountID:       fromAccountID,
+				FromUserID:          sourceAccount.UserID,
+				ToAccountID:         toAccountID,
+				ToUserID:            destAccount.UserID,
+				DebitTransactionID:  withdrawalTx.TransactionID,
+				CreditTransactionID: depositTx.TransactionID,
+				Amount:              amount,
+				CreditedAmount:      credited,
+				ExchangeRate:        result.ExchangeRate,
+				SourceBalance:       result.SourceBalance,
+				DestinationBalance:  result.DestinationBalance,
+			}); err != nil {
+			return err
+		}
+
+		var entry *models.JournalEntry
+		if quote != nil {
+			// The conversion goes through the fx position accounts so each currency stays balanced
+			entry = newFXJournalEntry(models.TransferEntry, withdrawalTx.TransactionID, withdrawalTx.Name,
+				fromAccountID, toAccountID, amount, credited)
+		} else {
+			// A single entry moves the money between the two customer accounts
+			entry = newJournalEntry(models.TransferEntry, withdrawalTx.TransactionID, withdrawalTx.Name,
+				fromAccountID, toAccountID, amount)
+		}
+		if err := postJournalEntry(adapters.LedgerRepository, entry); err != nil {
+			return err
+		}
+
+		if inTx != nil {
+			return inTx(adapters)
+		}
 		return nil
 	})
 
@@ -344,9 +568,48 @@ func (s *AccountServiceImpl) TransferBetweenAccounts(fromAccountID, toAccountID
 		return nil, err
 	}
 
+	invalidateTransactionLists(s.redisClient, sourceAccount.UserID, fromAccountID)
+	invalidateTransactionLists(s.redisClient, destAccount.UserID, toAccountID)
 	return result, nil
 }
 
+// FreezeAccount stops an account from moving money, operations already running complete
+func (s *AccountServiceImpl) FreezeAccount(accountID string) (*models.AccountWithDetails, error) {
+	account, err := s.GetAccountWithDetailByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen, err := s.accountRepository.FreezeAccount(accountID, account.UserID)
+	if err != nil {
+		logger.Error("Failed to freeze account", zap.String("account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+	if !frozen {
+		return nil, ErrAccountFrozen
+	}
+
+	return s.GetAccountWithDetailByID(accountID)
+}
+
+// UnfreezeAccount lets a frozen account move money again
+func (s *AccountServiceImpl) UnfreezeAccount(accountID string) (*models.AccountWithDetails, error) {
+	if _, err := s.GetAccountWithDetailByID(accountID); err != nil {
+		return nil, err
+	}
+
+	unfrozen, err := s.accountRepository.UnfreezeAccount(accountID)
+	if err != nil {
+		logger.Error("Failed to unfreeze account", zap.String("account_id", accountID), zap.Error(err))
+		return nil, err
+	}
+	if !unfrozen {
+		return nil, ErrAccountNotFrozen
+	}
+
+	return s.GetAccountWithDetailByID(accountID)
+}
+
 // DeleteAccount marks an account as deleted without removing it
 func (s *AccountServiceImpl) DeleteAccount(accountID string) error {
 	return s.accountRepository.DeleteAccount(accountID)
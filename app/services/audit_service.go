@@ -0,0 +1,94 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Page sizes of audit log listings
+const (
+	DefaultAuditLogLimit = 50
+	MaxAuditLogLimit     = 200
+)
+
+// AuditService defines the interface for the audit trail
+type AuditService interface {
+	Record(entry *models.AuditLog, before, after interface{})
+	List(filter models.AuditLogFilter) ([]*models.AuditLog, error)
+}
+
+// AuditServiceImpl implements AuditService
+type AuditServiceImpl struct {
+	auditLogRepository repositories.AuditLogRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditLogRepository repositories.AuditLogRepository) AuditService {
+	return &AuditServiceImpl{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+// Record appends an entry with JSON snapshots of the resource before and after the action, a nil snapshot is
+// left out. Recording never fails the audited action, an entry that cannot be stored is written to the log instead.
+func (s *AuditServiceImpl) Record(entry *models.AuditLog, before, after interface{}) {
+	entry.Before = snapshot(before)
+	entry.After = snapshot(after)
+	if entry.Outcome == "" {
+		entry.Outcome = models.AuditSuccess
+	}
+	if entry.ActorType == "" {
+		entry.ActorType = models.AuditActorUser
+	}
+	entry.CreatedAt = time.Now()
+
+	if err := s.auditLogRepository.Create(entry); err != nil {
+		logger.Error("Failed to record audit log",
+			zap.String("action", entry.Action),
+			zap.String("outcome", entry.Outcome),
+			zap.String("actor_id", entry.ActorID),
+			zap.String("resource_type", entry.ResourceType),
+			zap.String("resource_id", entry.ResourceID),
+			zap.String("request_id", entry.RequestID),
+			zap.Error(err))
+	}
+}
+
+// List retrieves the entries matching the filter, newest first, at most MaxAuditLogLimit at a time
+func (s *AuditServiceImpl) List(filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditLogLimit
+	}
+	if filter.Limit > MaxAuditLogLimit {
+		filter.Limit = MaxAuditLogLimit
+	}
+
+	entries, err := s.auditLogRepository.List(filter)
+	if err != nil {
+		logger.Error("Failed to list audit logs", zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+func snapshot(state interface{}) *json.RawMessage {
+	if state == nil {
+		return nil
+	}
+
+	content, err := json.Marshal(state)
+	if err != nil {
+		logger.Error("Failed to encode audit snapshot", zap.Error(err))
+		return nil
+	}
+	if string(content) == "null" {
+		return nil
+	}
+
+	raw := json.RawMessage(content)
+	return &raw
+}
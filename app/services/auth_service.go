@@ -1 +1,401 @@
 package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Custom errors for refresh tokens
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token is expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, all sessions of the sign-in are revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// AuthService defines the interface for issuing, rotating and revoking tokens and the device sessions they belong to
+type AuthService interface {
+	IssueTokens(userID string, device models.DeviceInfo) (*utils.Tokens, error)
+	RenewTokens(userID, deviceID, refreshToken string) (*utils.Tokens, error)
+	Logout(userID, refreshToken string) error
+	LogoutAll(userID string) (int64, error)
+	PurgeExpiredTokens(ctx context.Context) error
+
+	// Session operations
+	ListSessions(userID, currentSessionID string) ([]*models.Session, error)
+	RevokeSession(userID, sessionID string) error
+	IsSessionRevoked(sessionID string) (bool, error)
+}
+
+// AuthServiceImpl implements AuthService with MySQL as the refresh token and session store, and Redis caching
+// token lookups and holding the revocation list of sessions
+type AuthServiceImpl struct {
+	refreshTokenRepository repositories.RefreshTokenRepository
+	sessionRepository      repositories.SessionRepository
+	roleRepository         repositories.RoleRepository
+	redisClient            types.CacheClient
+}
+
+// NewAuthService creates a new instance of AuthService
+func NewAuthService(refreshTokenRepository repositories.RefreshTokenRepository, sessionRepository repositories.SessionRepository, roleRepository repositories.RoleRepository, redisClient types.CacheClient) AuthService {
+	return &AuthServiceImpl{
+		refreshTokenRepository: refreshTokenRepository,
+		sessionRepository:      sessionRepository,
+		roleRepository:         roleRepository,
+		redisClient:            redisClient,
+	}
+}
+
+func refreshTokenCacheKey(tokenHash string) string {
+	return fmt.Sprintf("refresh_token:%s", tokenHash)
+}
+
+func revokedSessionCacheKey(sessionID string) string {
+	return fmt.Sprintf("revoked_session:%s", sessionID)
+}
+
+// IssueTokens signs a user in on a device, starting a new session and its refresh token family. A device has one
+// session, signing in again on it revokes the previous one.
+func (s *AuthServiceImpl) IssueTokens(userID string, device models.DeviceInfo) (*utils.Tokens, error) {
+	now := time.Now()
+
+	if device.DeviceID != "" {
+		sessions, err := s.sessionRepository.GetActiveByUserID(userID)
+		if err != nil {
+			logger.Error("Failed to get sessions of user", zap.String("user_id", userID), zap.Error(err))
+			return nil, err
+		}
+		for _, session := range sessions {
+			if session.DeviceID != device.DeviceID {
+				continue
+			}
+			if err := s.revokeSession(session.SessionID, now); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	session := &models.Session{
+		SessionID:  uuid.New().String(),
+		UserID:     userID,
+		DeviceID:   device.DeviceID,
+		DeviceName: device.DeviceName,
+		Platform:   device.Platform,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.sessionRepository.Create(session); err != nil {
+		logger.Error("Failed to store session", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	return s.issueTokens(userID, device.DeviceID, session.SessionID)
+}
+
+// RenewTokens exchanges a refresh token for new tokens. The presented token is consumed, presenting it again
+// revokes every token of its family and returns ErrRefreshTokenReused.
+func (s *AuthServiceImpl) RenewTokens(userID, deviceID, refreshToken string) (*utils.Tokens, error) {
+	tokenHash := utils.HashRefreshToken(refreshToken)
+
+	token, err := s.getRefreshToken(tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if token.UserID != userID || token.DeviceID != deviceID {
+		return nil, ErrInvalidRefreshToken
+	}
+	if token.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		return nil, s.revokeReusedFamily(token)
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	// The cached copy may be stale, the conditional update decides which of concurrent renewals wins
+	claimed, err := s.refreshTokenRepository.MarkUsed(tokenHash, now)
+	if err != nil {
+		logger.Error("Failed to mark refresh token used", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	s.forgetRefreshToken(tokenHash)
+	if !claimed {
+		current, err := s.refreshTokenRepository.GetByHash(tokenHash)
+		if err != nil {
+			return nil, err
+		}
+		if current.RevokedAt != nil {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, s.revokeReusedFamily(current)
+	}
+
+	if err := s.sessionRepository.Touch(token.FamilyID, now); err != nil {
+		logger.Warn("Failed to update last seen time of session", zap.String("session_id", token.FamilyID), zap.Error(err))
+	}
+
+	return s.issueTokens(userID, deviceID, token.FamilyID)
+}
+
+// Logout revokes the session and refresh token family of the sign-in the token belongs to
+func (s *AuthServiceImpl) Logout(userID, refreshToken string) error {
+	tokenHash := utils.HashRefreshToken(refreshToken)
+
+	token, err := s.getRefreshToken(tokenHash)
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID {
+		return ErrInvalidRefreshToken
+	}
+
+	if err := s.revokeSession(token.FamilyID, time.Now()); err != nil {
+		return err
+	}
+	s.forgetRefreshToken(tokenHash)
+
+	return nil
+}
+
+// LogoutAll revokes the sessions and refresh tokens of the user on every device and returns how many tokens
+// were revoked
+func (s *AuthServiceImpl) LogoutAll(userID string) (int64, error) {
+	now := time.Now()
+
+	sessions, err := s.sessionRepository.GetActiveByUserID(userID)
+	if err != nil {
+		logger.Error("Failed to get sessions of user", zap.String("user_id", userID), zap.Error(err))
+		return 0, err
+	}
+
+	// Cached copies of the revoked tokens are rejected when they fail to be marked used
+	revoked, err := s.refreshTokenRepository.RevokeByUserID(userID, now)
+	if err != nil {
+		logger.Error("Failed to revoke refresh tokens", zap.String("user_id", userID), zap.Error(err))
+		return 0, err
+	}
+
+	for _, session := range sessions {
+		if err := s.endSession(session.SessionID, now); err != nil {
+			return 0, err
+		}
+	}
+
+	return revoked, nil
+}
+
+// ListSessions returns the signed-in devices of a user, marking the session the request came from
+func (s *AuthServiceImpl) ListSessions(userID, currentSessionID string) ([]*models.Session, error) {
+	sessions, err := s.sessionRepository.GetActiveByUserID(userID)
+	if err != nil {
+		logger.Error("Failed to get sessions of user", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.SessionID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a device of the user out, its refresh tokens are revoked and its access tokens rejected
+// from now on. Sessions of other users and revoked sessions are reported as ErrSessionNotFound.
+func (s *AuthServiceImpl) RevokeSession(userID, sessionID string) error {
+	session, err := s.sessionRepository.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		logger.Error("Failed to get session", zap.String("session_id", sessionID), zap.Error(err))
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+
+	return s.revokeSession(sessionID, time.Now())
+}
+
+// IsSessionRevoked tells whether access tokens of a session are rejected. The revocation list in Redis answers,
+// the session row does when Redis is unavailable.
+func (s *AuthServiceImpl) IsSessionRevoked(sessionID string) (bool, error) {
+	_, err := s.redisClient.Get(context.Background(), revokedSessionCacheKey(sessionID))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, types.ErrCacheMiss) {
+		return false, nil
+	}
+	logger.Warn("Failed to check session revocation list, falling back to the database", zap.Error(err))
+
+	session, err := s.sessionRepository.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Sessions are deleted long after their last token expired
+			return true, nil
+		}
+		return false, err
+	}
+
+	return session.RevokedAt != nil, nil
+}
+
+// PurgeExpiredTokens removes refresh tokens that can no longer be renewed and the sessions they belonged to
+func (s *AuthServiceImpl) PurgeExpiredTokens(ctx context.Context) error {
+	now := time.Now()
+
+	deleted, err := s.refreshTokenRepository.DeleteExpired(now)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.Info("Purged expired refresh tokens", zap.Int64("count", deleted))
+	}
+
+	// The last refresh token of a session expires a refresh token lifetime after it was last seen
+	deleted, err = s.sessionRepository.DeleteInactive(now.Add(-utils.RefreshTokenTTL()))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.Info("Purged inactive sessions", zap.Int64("count", deleted))
+	}
+
+	return nil
+}
+
+// issueTokens generates new tokens and stores the refresh token in the given family, the family id is the session
+// id carried by the access token. The roles are read again for every access token, so granted and revoked roles
+// apply from the next renewal.
+func (s *AuthServiceImpl) issueTokens(userID, deviceID, familyID string) (*utils.Tokens, error) {
+	roles, err := s.roleRepository.GetRolesByUserID(userID)
+	if err != nil {
+		logger.Error("Failed to get roles of user", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	tokens, err := utils.GenerateNewTokens(userID, familyID, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &models.RefreshToken{
+		TokenHash: utils.HashRefreshToken(tokens.Refresh),
+		FamilyID:  familyID,
+		UserID:    userID,
+		DeviceID:  deviceID,
+		ExpiresAt: tokens.RefreshExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.refreshTokenRepository.Create(token); err != nil {
+		logger.Error("Failed to store refresh token", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	s.cacheRefreshToken(token)
+
+	return tokens, nil
+}
+
+// revokeReusedFamily revokes the family of a token that was presented after it was used
+func (s *AuthServiceImpl) revokeReusedFamily(token *models.RefreshToken) error {
+	logger.Warn("Refresh token reused, revoking its family",
+		zap.String("user_id", token.UserID), zap.String("family_id", token.FamilyID))
+
+	if err := s.revokeSession(token.FamilyID, time.Now()); err != nil {
+		return err
+	}
+	s.forgetRefreshToken(token.TokenHash)
+
+	return ErrRefreshTokenReused
+}
+
+// revokeSession revokes the refresh token family of a session and ends the session
+func (s *AuthServiceImpl) revokeSession(sessionID string, now time.Time) error {
+	if _, err := s.refreshTokenRepository.RevokeFamily(sessionID, now); err != nil {
+		logger.Error("Failed to revoke refresh token family", zap.String("family_id", sessionID), zap.Error(err))
+		return err
+	}
+
+	return s.endSession(sessionID, now)
+}
+
+// endSession marks a session revoked and puts it on the revocation list until its last access token expired
+func (s *AuthServiceImpl) endSession(sessionID string, now time.Time) error {
+	if _, err := s.sessionRepository.Revoke(sessionID, now); err != nil {
+		logger.Error("Failed to revoke session", zap.String("session_id", sessionID), zap.Error(err))
+		return err
+	}
+
+	// Without Redis the revoked session row is checked instead
+	if ttl := utils.AccessTokenTTL(); ttl > 0 {
+		if err := s.redisClient.Set(context.Background(), revokedSessionCacheKey(sessionID), "1", ttl); err != nil {
+			logger.Error("Failed to add session to the revocation list", zap.String("session_id", sessionID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// getRefreshToken looks a token up in the cache first, unknown tokens are reported as ErrInvalidRefreshToken
+func (s *AuthServiceImpl) getRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	cachedData, err := s.redisClient.Get(context.Background(), refreshTokenCacheKey(tokenHash))
+	if err == nil {
+		var token models.RefreshToken
+		if err := json.Unmarshal([]byte(cachedData), &token); err == nil {
+			return &token, nil
+		}
+	}
+
+	token, err := s.refreshTokenRepository.GetByHash(tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		logger.Error("Failed to get refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	if token.UsedAt == nil && token.RevokedAt == nil {
+		s.cacheRefreshToken(token)
+	}
+	return token, nil
+}
+
+// cacheRefreshToken stores a renewable token in the cache until it expires
+func (s *AuthServiceImpl) cacheRefreshToken(token *models.RefreshToken) {
+	ttl := time.Until(token.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+
+	if data, err := json.Marshal(token); err == nil {
+		if err := s.redisClient.Set(context.Background(), refreshTokenCacheKey(token.TokenHash), data, ttl); err != nil {
+			logger.Warn("Failed to set cache for refresh token", zap.String("user_id", token.UserID), zap.Error(err))
+		}
+	}
+}
+
+// forgetRefreshToken removes a token that is no longer renewable from the cache
+func (s *AuthServiceImpl) forgetRefreshToken(tokenHash string) {
+	if err := s.redisClient.Delete(context.Background(), refreshTokenCacheKey(tokenHash)); err != nil {
+		logger.Warn("Failed to delete refresh token from cache", zap.Error(err))
+	}
+}
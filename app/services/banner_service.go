@@ -3,14 +3,22 @@ package services
 import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
+	"errors"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// ErrBannerNotFound is returned when updating or deleting a banner that does not exist
+var ErrBannerNotFound = errors.New("banner not found")
+
 // BannerService defines the interface for banner operations
 type BannerService interface {
 	GetBannerByID(bannerID string) (*models.Banner, error)
 	GetBannersByUserID(userID string) ([]*models.Banner, error)
+	CreateBanner(userID, title, description, image string) (*models.Banner, error)
+	UpdateBanner(bannerID, title, description, image string) (*models.Banner, error)
+	DeleteBanner(bannerID string) error
 }
 
 // BannerServiceImpl implements BannerService
@@ -50,4 +58,64 @@ func (s *BannerServiceImpl) GetBannersByUserID(userID string) ([]*models.Banner,
 	}
 	
 	return banners, nil
-}
\ No newline at end of file
+}
+
+// CreateBanner adds a banner shown to a user
+func (s *BannerServiceImpl) CreateBanner(userID, title, description, image string) (*models.Banner, error) {
+	banner := &models.Banner{
+		BannerID:    uuid.New().String(),
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		Image:       image,
+	}
+
+	if err := s.bannerRepository.CreateBanner(banner); err != nil {
+		logger.Error("Failed to create banner", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	return s.bannerRepository.GetBannerByID(banner.BannerID)
+}
+
+// UpdateBanner changes the content of a banner, empty values are left unchanged
+func (s *BannerServiceImpl) UpdateBanner(bannerID, title, description, image string) (*models.Banner, error) {
+	banner, err := s.bannerRepository.GetBannerByID(bannerID)
+	if err != nil {
+		return nil, err
+	}
+	if banner == nil {
+		return nil, ErrBannerNotFound
+	}
+
+	if title != "" {
+		banner.Title = title
+	}
+	if description != "" {
+		banner.Description = description
+	}
+	if image != "" {
+		banner.Image = image
+	}
+
+	if err := s.bannerRepository.UpdateBanner(banner); err != nil {
+		logger.Error("Failed to update banner", zap.String("banner_id", bannerID), zap.Error(err))
+		return nil, err
+	}
+
+	return banner, nil
+}
+
+// DeleteBanner stops showing a banner
+func (s *BannerServiceImpl) DeleteBanner(bannerID string) error {
+	deleted, err := s.bannerRepository.DeleteBanner(bannerID)
+	if err != nil {
+		logger.Error("Failed to delete banner", zap.String("banner_id", bannerID), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return ErrBannerNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Custom errors for step-up authentication challenges
+var (
+	ErrChallengeNotFound      = errors.New("challenge not found")
+	ErrChallengeExpired       = errors.New("challenge expired, start the operation again")
+	ErrChallengeUsed          = errors.New("challenge was already confirmed")
+	ErrChallengeFailed        = errors.New("too many failed attempts, start the operation again")
+	ErrInvalidChallengeMethod = errors.New("method must be pin or totp")
+)
+
+// ChallengeService defines the interface for step-up authentication challenges
+type ChallengeService interface {
+	RequiresStepUp(amount types.Money) bool
+	CreateTransferChallenge(userID string, transfer *models.PendingTransfer) (*models.Challenge, error)
+	CreateScheduleChallenge(userID string, schedule *models.ScheduledTransfer) (*models.Challenge, error)
+	CreateScheduleUpdateChallenge(userID string, update *models.PendingScheduleUpdate) (*models.Challenge, error)
+	GetChallengeByID(challengeID string) (*models.Challenge, error)
+	Confirm(userID, challengeID, method, code string) (*models.Challenge, error)
+	PurgeExpiredChallenges(ctx context.Context) error
+}
+
+// ChallengeServiceImpl implements ChallengeService. A challenge holds an operation until the user confirms it with
+// the PIN or a TOTP code, the caller runs the operation of the confirmed challenge.
+type ChallengeServiceImpl struct {
+	challengeRepository repositories.ChallengeRepository
+	userRepository      repositories.UserRepository
+	totpService         TOTPService
+	thresholds          map[string]types.Money
+}
+
+// NewChallengeService creates a new instance of ChallengeService. Transfers above the threshold of their currency,
+// and the schedules paying them, need step-up authentication.
+func NewChallengeService(
+	challengeRepository repositories.ChallengeRepository,
+	userRepository repositories.UserRepository,
+	totpService TOTPService,
+	thresholds map[string]types.Money,
+) ChallengeService {
+	return &ChallengeServiceImpl{
+		challengeRepository: challengeRepository,
+		userRepository:      userRepository,
+		totpService:         totpService,
+		thresholds:          thresholds,
+	}
+}
+
+// RequiresStepUp reports whether a transfer of the amount needs a confirmed challenge
+func (s *ChallengeServiceImpl) RequiresStepUp(amount types.Money) bool {
+	threshold, ok := s.thresholds[amount.Currency]
+	if !ok {
+		return true
+	}
+	return threshold.LessThan(amount)
+}
+
+// CreateTransferChallenge holds a transfer until the user confirms it
+func (s *ChallengeServiceImpl) CreateTransferChallenge(userID string, transfer *models.PendingTransfer) (*models.Challenge, error) {
+	return s.createChallenge(userID, models.ChallengeActionTransfer, transfer)
+}
+
+// CreateScheduleChallenge holds a new scheduled transfer until the user confirms it
+func (s *ChallengeServiceImpl) CreateScheduleChallenge(userID string, schedule *models.ScheduledTransfer) (*models.Challenge, error) {
+	return s.createChallenge(userID, models.ChallengeActionSchedule, schedule)
+}
+
+// CreateScheduleUpdateChallenge holds an update of a scheduled transfer until the user confirms it
+func (s *ChallengeServiceImpl) CreateScheduleUpdateChallenge(userID string, update *models.PendingScheduleUpdate) (*models.Challenge, error) {
+	return s.createChallenge(userID, models.ChallengeActionScheduleUpdate, update)
+}
+
+// createChallenge stores the operation as the payload of a new challenge of the user
+func (s *ChallengeServiceImpl) createChallenge(userID string, action models.ChallengeAction, operation interface{}) (*models.Challenge, error) {
+	payload, err := json.Marshal(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	challenge := &models.Challenge{
+		ChallengeID: uuid.New().String(),
+		UserID:      userID,
+		Action:      action,
+		Payload:     payload,
+		ExpiresAt:   now.Add(configs.CHALLENGE_TTL),
+		CreatedAt:   now,
+	}
+	if err := s.challengeRepository.Create(challenge); err != nil {
+		logger.Error("Failed to create challenge", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// GetChallengeByID retrieves a challenge by ID
+func (s *ChallengeServiceImpl) GetChallengeByID(challengeID string) (*models.Challenge, error) {
+	return s.challengeRepository.GetByID(challengeID)
+}
+
+// Confirm checks the second factor for a challenge of the user and consumes the challenge. A wrong PIN returns
+// ErrIncorrectPin and a wrong TOTP code ErrInvalidTOTP, the challenge fails after configs.CHALLENGE_MAX_ATTEMPTS
+// of them. Callers also count both per user in the PinLockoutService, a new challenge does not reset them.
+func (s *ChallengeServiceImpl) Confirm(userID, challengeID, method, code string) (*models.Challenge, error) {
+	challenge, err := s.challengeRepository.GetByID(challengeID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && challenge.UserID != userID) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case challenge.ConfirmedAt != nil:
+		return nil, ErrChallengeUsed
+	case challenge.IsExpired(now):
+		return nil, ErrChallengeExpired
+	case challenge.Attempts >= configs.CHALLENGE_MAX_ATTEMPTS:
+		return nil, ErrChallengeFailed
+	}
+
+	if err := s.verifyFactor(userID, method, code); err != nil {
+		if errors.Is(err, ErrIncorrectPin) || errors.Is(err, ErrInvalidTOTP) {
+			if err := s.challengeRepository.IncrementAttempts(challengeID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	// Another request may have confirmed the challenge since it was read
+	confirmed, err := s.challengeRepository.MarkConfirmed(challengeID, now, configs.CHALLENGE_MAX_ATTEMPTS)
+	if err != nil {
+		logger.Error("Failed to confirm challenge", zap.String("challenge_id", challengeID), zap.Error(err))
+		return nil, err
+	}
+	if !confirmed {
+		return nil, ErrChallengeUsed
+	}
+
+	challenge.ConfirmedAt = &now
+	return challenge, nil
+}
+
+// PurgeExpiredChallenges removes challenges that can no longer be confirmed
+func (s *ChallengeServiceImpl) PurgeExpiredChallenges(ctx context.Context) error {
+	deleted, err := s.challengeRepository.DeleteExpired(time.Now())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.Info("Purged expired challenges", zap.Int64("count", deleted))
+	}
+
+	return nil
+}
+
+// verifyFactor checks the PIN or the TOTP code of the user
+func (s *ChallengeServiceImpl) verifyFactor(userID, method, code string) error {
+	switch method {
+	case models.ChallengeMethodPIN:
+		user, err := s.userRepository.GetByID(userID)
+		if err != nil {
+			return err
+		}
+		if !utils.VerifyPIN(user.PIN, code) {
+			return ErrIncorrectPin
+		}
+		return nil
+	case models.ChallengeMethodTOTP:
+		return s.totpService.Verify(userID, code)
+	}
+
+	return ErrInvalidChallengeMethod
+}
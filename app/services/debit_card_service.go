@@ -3,10 +3,14 @@ package services
 import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
+	"errors"
 
 	"github.com/google/uuid"
 )
 
+// ErrInvalidCardStatus is returned for a status debit cards cannot have
+var ErrInvalidCardStatus = errors.New("invalid card status")
+
 // DebitCardService defines the interface for debit card operations
 type DebitCardService interface {
 	// Card operations
@@ -20,6 +24,7 @@ type DebitCardService interface {
 
 	// Update operations
 	UpdateCard(card *models.DebitCard, name, color, borderColor string) error
+	UpdateCardStatus(cardID string, status models.CardStatus) (*models.DebitCardWithDetails, error)
 
 	// Delete operations
 	DeleteCard(cardID string) error
@@ -28,12 +33,14 @@ type DebitCardService interface {
 // DebitCardServiceImpl implements DebitCardService
 type DebitCardServiceImpl struct {
 	debitCardRepository repositories.DebitCardRepository
+	txProvider          repositories.TxProvider
 }
 
 // NewDebitCardService creates a new instance of DebitCardService
-func NewDebitCardService(repo repositories.DebitCardRepository) DebitCardService {
+func NewDebitCardService(repo repositories.DebitCardRepository, txProvider repositories.TxProvider) DebitCardService {
 	return &DebitCardServiceImpl{
 		debitCardRepository: repo,
+		txProvider:          txProvider,
 	}
 }
 
@@ -96,12 +103,50 @@ func (s *DebitCardServiceImpl) UpdateCard(card *models.DebitCard, name, color, b
 	})
 }
 
+// UpdateCardStatus sets the status of a card, e.g. to unblock it
+func (s *DebitCardServiceImpl) UpdateCardStatus(cardID string, status models.CardStatus) (*models.DebitCardWithDetails, error) {
+	switch status {
+	case models.CardStatusActive, models.CardStatusInactive, models.CardStatusInprogress, models.CardStatusBlocked:
+	default:
+		return nil, ErrInvalidCardStatus
+	}
+
+	var card *models.DebitCardWithDetails
+	err := s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		var err error
+		card, err = changeCardStatus(adapters, cardID, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
 // DeleteCard marks a card as deleted without removing it
 func (s *DebitCardServiceImpl) DeleteCard(cardID string) error {
 	// update status card to inactive
-	cardStatus := models.DebitCardStatus{
-		CardID: cardID,
-		Status: string(models.CardStatusInactive),
+	return s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		_, err := changeCardStatus(adapters, cardID, models.CardStatusInactive)
+		return err
+	})
+}
+
+// changeCardStatus sets the status of a card and records a CardStatusChanged event in the same transaction
+func changeCardStatus(adapters repositories.Adapters, cardID string, status models.CardStatus) (*models.DebitCardWithDetails, error) {
+	card, err := adapters.DebitCardRepository.GetCardWithDetailByID(cardID)
+	if err != nil {
+		return nil, err
 	}
-	return s.debitCardRepository.UpdateCardStatus(&cardStatus)
+
+	if err := adapters.DebitCardRepository.UpdateCardStatus(&models.DebitCardStatus{CardID: cardID, Status: string(status)}); err != nil {
+		return nil, err
+	}
+
+	previousStatus := card.Status
+	card.Status = string(status)
+
+	return card, recordEvent(adapters.OutboxRepository, models.EventCardStatusChanged, models.AggregateDebitCard, cardID, "",
+		models.CardStatusChangedPayload{CardID: cardID, UserID: card.UserID, Status: card.Status, PreviousStatus: previousStatus})
 }
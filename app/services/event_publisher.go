@@ -0,0 +1,155 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventPublisher delivers domain events relayed from the outbox to other systems. An event may be published more
+// than once, e.g. when the relay stops between publishing it and marking it published, consumers deduplicate by
+// its event_id.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.DomainEvent) error
+}
+
+// EventHandler consumes a published event, an error makes the relay publish the event again
+type EventHandler func(ctx context.Context, event *models.DomainEvent) error
+
+// memoryPublisherCapacity is the number of recent events MemoryEventPublisher keeps
+const memoryPublisherCapacity = 1000
+
+// MemoryEventPublisher keeps the recent published events in memory and hands them to the handlers subscribed in
+// this process
+type MemoryEventPublisher struct {
+	mu       sync.Mutex
+	events   []*models.DomainEvent
+	handlers []EventHandler
+}
+
+// NewMemoryEventPublisher creates an EventPublisher keeping events in memory
+func NewMemoryEventPublisher() *MemoryEventPublisher {
+	return &MemoryEventPublisher{}
+}
+
+// Subscribe adds a handler called with every event published from now on
+func (p *MemoryEventPublisher) Subscribe(handler EventHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, handler)
+}
+
+// Publish keeps the event and hands it to the subscribed handlers in order, stopping at the first failure
+func (p *MemoryEventPublisher) Publish(ctx context.Context, event *models.DomainEvent) error {
+	p.mu.Lock()
+	handlers := append([]EventHandler(nil), p.handlers...)
+	p.mu.Unlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	if len(p.events) > memoryPublisherCapacity {
+		p.events = append([]*models.DomainEvent(nil), p.events[len(p.events)-memoryPublisherCapacity:]...)
+	}
+	return nil
+}
+
+// Events returns the recent published events, oldest first
+func (p *MemoryEventPublisher) Events() []*models.DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*models.DomainEvent(nil), p.events...)
+}
+
+// MultiEventPublisher publishes events to several publishers in order
+type MultiEventPublisher struct {
+	publishers []EventPublisher
+}
+
+// NewMultiEventPublisher creates an EventPublisher handing every event to each of the publishers
+func NewMultiEventPublisher(publishers ...EventPublisher) *MultiEventPublisher {
+	return &MultiEventPublisher{publishers: publishers}
+}
+
+// Publish hands the event to the publishers in order, stopping at the first failure. The event is then published
+// again to all of them, the publishers before the failed one receive it twice.
+func (p *MultiEventPublisher) Publish(ctx context.Context, event *models.DomainEvent) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamClient appends entries to streams, implemented by the Redis client of platform/cache
+type StreamClient interface {
+	AddToStream(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error)
+}
+
+// RedisStreamPublisher appends events to a Redis stream, consumer groups read them in the order they were relayed
+type RedisStreamPublisher struct {
+	client StreamClient
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamPublisher creates an EventPublisher appending to the stream, which keeps about maxLen events
+func NewRedisStreamPublisher(client StreamClient, stream string, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{client: client, stream: stream, maxLen: maxLen}
+}
+
+// Publish appends the event to the stream
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event *models.DomainEvent) error {
+	_, err := p.client.AddToStream(ctx, p.stream, p.maxLen, map[string]interface{}{
+		"event_id":       event.EventID,
+		"event_type":     string(event.EventType),
+		"aggregate_type": event.AggregateType,
+		"aggregate_id":   event.AggregateID,
+		"sequence":       strconv.FormatInt(event.Sequence, 10),
+		"occurred_at":    event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		"payload":        string(event.Payload),
+	})
+	return err
+}
+
+// FileEventPublisher appends events to a file as JSON lines, a local sink for development and tests
+type FileEventPublisher struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileEventPublisher creates an EventPublisher appending to the file at path
+func NewFileEventPublisher(path string) *FileEventPublisher {
+	return &FileEventPublisher{path: path}
+}
+
+// Publish appends the event to the file
+func (p *FileEventPublisher) Publish(_ context.Context, event *models.DomainEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	file, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
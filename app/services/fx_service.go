@@ -0,0 +1,117 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Custom errors for currency conversion
+var (
+	ErrInvalidCurrencyPair = errors.New("source and target currencies must be different valid currencies")
+	ErrQuoteRequired       = errors.New("transfers between accounts of different currencies require an fx quote")
+	ErrQuoteNotFound       = errors.New("fx quote not found")
+	ErrQuoteExpired        = errors.New("fx quote expired")
+	ErrQuoteUsed           = errors.New("fx quote already used")
+	ErrQuoteMismatch       = errors.New("fx quote does not match the transfer")
+)
+
+// FXService defines the interface for currency conversion quotes
+type FXService interface {
+	CreateQuote(userID, toCurrency string, amount types.Money) (*models.FXQuote, error)
+}
+
+// FXServiceImpl implements FXService
+type FXServiceImpl struct {
+	fxRepository repositories.FXRepository
+	rateProvider RateProvider
+}
+
+// NewFXService creates a new instance of FXService
+func NewFXService(fxRepository repositories.FXRepository, rateProvider RateProvider) FXService {
+	return &FXServiceImpl{
+		fxRepository: fxRepository,
+		rateProvider: rateProvider,
+	}
+}
+
+// CreateQuote locks the current rate for converting amount into toCurrency. The quote expires after
+// configs.FX_QUOTE_TTL and can be used by one transfer of exactly amount.
+func (s *FXServiceImpl) CreateQuote(userID, toCurrency string, amount types.Money) (*models.FXQuote, error) {
+	if !amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+	if !types.IsValidCurrency(toCurrency) || toCurrency == amount.Currency {
+		return nil, ErrInvalidCurrencyPair
+	}
+
+	rate, err := s.rateProvider.GetRate(amount.Currency, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	targetAmount, err := rate.Convert(amount, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if !targetAmount.IsPositive() {
+		return nil, fmt.Errorf("%w: %s converts to nothing in %s", ErrInvalidAmount, amount, toCurrency)
+	}
+
+	quote := &models.FXQuote{
+		QuoteID:      uuid.New().String(),
+		UserID:       userID,
+		FromCurrency: amount.Currency,
+		ToCurrency:   toCurrency,
+		Rate:         rate,
+		SourceAmount: amount,
+		TargetAmount: targetAmount,
+		ExpiresAt:    time.Now().Add(configs.FX_QUOTE_TTL),
+	}
+	if err := s.fxRepository.CreateQuote(quote); err != nil {
+		logger.Error("Failed to create fx quote", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// useQuote locks and consumes a quote for a transfer of amount into toCurrency made by userID
+func useQuote(fxRepository repositories.FXRepository, quoteID, userID, toCurrency string, amount types.Money) (*models.FXQuote, error) {
+	quote, err := fxRepository.GetQuoteByIDForUpdate(quoteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case quote.UserID != userID:
+		return nil, ErrQuoteNotFound
+	case quote.UsedAt != nil:
+		return nil, ErrQuoteUsed
+	case quote.IsExpired(now):
+		return nil, ErrQuoteExpired
+	case quote.ToCurrency != toCurrency || quote.SourceAmount != amount:
+		return nil, ErrQuoteMismatch
+	}
+
+	if err := fxRepository.MarkQuoteUsed(quoteID, now); err != nil {
+		if errors.Is(err, repositories.ErrQuoteAlreadyUsed) {
+			return nil, ErrQuoteUsed
+		}
+		return nil, err
+	}
+
+	return quote, nil
+}
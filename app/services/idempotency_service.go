@@ -0,0 +1,189 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyTTL is how long a completed response is kept for replay
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyLeaseTTL is how long a request holds its key while processing. A retry of the same request claims a key
+// still processing after the lease, so a key is not stuck when the instance processing it went away.
+const IdempotencyLeaseTTL = 5 * time.Minute
+
+// Custom errors for idempotent requests
+var (
+	ErrIdempotencyKeyReused     = types.ErrIdempotencyKeyReused
+	ErrIdempotencyKeyInProgress = types.ErrIdempotencyKeyInProgress
+)
+
+// IdempotencyService defines the interface for idempotency key operations
+type IdempotencyService interface {
+	Begin(userID, key, requestHash string) (*types.IdempotentResponse, error)
+	Complete(userID, key string, response *types.IdempotentResponse) error
+	Release(userID, key string) error
+}
+
+// IdempotencyServiceImpl implements IdempotencyService with MySQL as the source of truth and Redis as a fast path for replays
+type IdempotencyServiceImpl struct {
+	idempotencyRepository repositories.IdempotencyRepository
+	redisClient           types.CacheClient
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService
+func NewIdempotencyService(idempotencyRepository repositories.IdempotencyRepository, redisClient types.CacheClient) IdempotencyService {
+	return &IdempotencyServiceImpl{
+		idempotencyRepository: idempotencyRepository,
+		redisClient:           redisClient,
+	}
+}
+
+func idempotencyCacheKey(userID, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s", userID, key)
+}
+
+// Begin claims the key for a new request. It returns the stored response when the same request was already completed,
+// nil when the caller should process the request, ErrIdempotencyKeyReused when the key was used for a different request
+// and ErrIdempotencyKeyInProgress when the original request has not finished yet.
+func (s *IdempotencyServiceImpl) Begin(userID, key, requestHash string) (*types.IdempotentResponse, error) {
+	ctx := context.Background()
+
+	// Fast path: completed responses are cached
+	cachedData, err := s.redisClient.Get(ctx, idempotencyCacheKey(userID, key))
+	if err == nil {
+		var record models.IdempotencyKey
+		if err := json.Unmarshal([]byte(cachedData), &record); err == nil {
+			return replayIdempotencyKey(&record, requestHash)
+		}
+	}
+
+	now := time.Now()
+	lockedUntil := now.Add(IdempotencyLeaseTTL)
+	record := &models.IdempotencyKey{
+		UserID:         userID,
+		IdempotencyKey: key,
+		RequestHash:    requestHash,
+		Status:         models.IdempotencyProcessing,
+		LockedUntil:    &lockedUntil,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(IdempotencyKeyTTL),
+	}
+
+	created, err := s.idempotencyRepository.Create(record)
+	if err != nil {
+		logger.Error("Failed to create idempotency key", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+
+	existing, err := s.idempotencyRepository.GetByKey(userID, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released between our insert and read, let the client retry
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		logger.Error("Failed to get idempotency key", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	// An expired key, or a key left processing past its lease by the same request, can be claimed again
+	if existing.ExpiresAt.Before(now) || leaseExpired(existing, requestHash, now) {
+		claimed, err := s.idempotencyRepository.Reclaim(record, now)
+		if err != nil {
+			logger.Error("Failed to reclaim idempotency key", zap.String("user_id", userID), zap.Error(err))
+			return nil, err
+		}
+		if !claimed {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, nil
+	}
+
+	if existing.Status == models.IdempotencyCompleted {
+		s.cacheIdempotencyKey(ctx, existing)
+	}
+
+	return replayIdempotencyKey(existing, requestHash)
+}
+
+// leaseExpired reports whether the request processing a key stopped holding it before completing
+func leaseExpired(record *models.IdempotencyKey, requestHash string, now time.Time) bool {
+	return record.Status == models.IdempotencyProcessing && record.RequestHash == requestHash &&
+		record.LockedUntil != nil && record.LockedUntil.Before(now)
+}
+
+// replayIdempotencyKey returns the stored response if the key belongs to the same request
+func replayIdempotencyKey(record *models.IdempotencyKey, requestHash string) (*types.IdempotentResponse, error) {
+	if record.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if record.Status != models.IdempotencyCompleted {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &types.IdempotentResponse{
+		StatusCode: record.ResponseStatus,
+		Body:       record.ResponseBody,
+	}, nil
+}
+
+// Complete stores the response of a processed request so retries replay it
+func (s *IdempotencyServiceImpl) Complete(userID, key string, response *types.IdempotentResponse) error {
+	record, err := s.idempotencyRepository.GetByKey(userID, key)
+	if err != nil {
+		logger.Error("Failed to get idempotency key", zap.String("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	record.Status = models.IdempotencyCompleted
+	record.ResponseStatus = response.StatusCode
+	record.ResponseBody = response.Body
+
+	if err := s.idempotencyRepository.Complete(record); err != nil {
+		logger.Error("Failed to complete idempotency key", zap.String("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	s.cacheIdempotencyKey(context.Background(), record)
+	return nil
+}
+
+// Release forgets a key whose request failed before producing a final response, so it can be retried
+func (s *IdempotencyServiceImpl) Release(userID, key string) error {
+	if err := s.redisClient.Delete(context.Background(), idempotencyCacheKey(userID, key)); err != nil {
+		logger.Warn("Failed to delete idempotency key from cache", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	if err := s.idempotencyRepository.Delete(userID, key); err != nil {
+		logger.Error("Failed to release idempotency key", zap.String("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// cacheIdempotencyKey stores a completed key in the cache until it expires
+func (s *IdempotencyServiceImpl) cacheIdempotencyKey(ctx context.Context, record *models.IdempotencyKey) {
+	ttl := time.Until(record.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+
+	if data, err := json.Marshal(record); err == nil {
+		if err := s.redisClient.Set(ctx, idempotencyCacheKey(record.UserID, record.IdempotencyKey), data, ttl); err != nil {
+			logger.Warn("Failed to set cache for idempotency key", zap.String("user_id", record.UserID), zap.Error(err))
+		}
+	}
+}
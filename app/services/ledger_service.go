@@ -0,0 +1,157 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Custom errors for ledger operations
+var (
+	ErrLedgerUnbalanced     = errors.New("ledger entry debits and credits do not balance")
+	ErrLedgerInvalidPosting = errors.New("ledger posting amount must be greater than zero")
+)
+
+// LedgerService defines the interface for ledger operations
+type LedgerService interface {
+	GetBalanceAt(accountID string, at time.Time) (types.Money, error)
+	CheckBooksBalanced() ([]*models.TrialBalance, error)
+}
+
+// LedgerServiceImpl implements LedgerService
+type LedgerServiceImpl struct {
+	ledgerRepository repositories.LedgerRepository
+}
+
+// NewLedgerService creates a new instance of LedgerService
+func NewLedgerService(ledgerRepository repositories.LedgerRepository) LedgerService {
+	return &LedgerServiceImpl{
+		ledgerRepository: ledgerRepository,
+	}
+}
+
+// GetBalanceAt rebuilds the balance of an account from its postings as of the given time.
+// An account that has never been posted to has no ledger history and returns sql.ErrNoRows.
+func (s *LedgerServiceImpl) GetBalanceAt(accountID string, at time.Time) (types.Money, error) {
+	balance, err := s.ledgerRepository.GetBalanceAt(accountID, at)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Error("Failed to rebuild ledger balance", zap.String("account_id", accountID), zap.Time("at", at), zap.Error(err))
+		}
+		return types.Money{}, err
+	}
+	return balance, nil
+}
+
+// CheckBooksBalanced returns the trial balance per currency and ErrLedgerUnbalanced if any currency's debits differ from its credits
+func (s *LedgerServiceImpl) CheckBooksBalanced() ([]*models.TrialBalance, error) {
+	trialBalances, err := s.ledgerRepository.GetTrialBalance()
+	if err != nil {
+		logger.Error("Failed to get trial balance", zap.Error(err))
+		return nil, err
+	}
+
+	for _, trialBalance := range trialBalances {
+		if trialBalance.TotalDebits != trialBalance.TotalCredits {
+			logger.Error("Ledger is out of balance",
+				zap.String("currency", trialBalance.Currency),
+				zap.Stringer("total_debits", trialBalance.TotalDebits),
+				zap.Stringer("total_credits", trialBalance.TotalCredits))
+			return trialBalances, ErrLedgerUnbalanced
+		}
+	}
+
+	return trialBalances, nil
+}
+
+// externalLedgerAccount returns the system account money enters and leaves the bank through for a currency
+func externalLedgerAccount(currency string) string {
+	return models.LedgerExternalAccountPrefix + currency
+}
+
+// fxLedgerAccount returns the system account that holds the bank's position in a currency after conversions
+func fxLedgerAccount(currency string) string {
+	return models.LedgerFXAccountPrefix + currency
+}
+
+// newFXJournalEntry builds an entry that debits debitAmount from one account and credits creditAmount, in another
+// currency, to the other. Each currency is balanced against its fx position account.
+func newFXJournalEntry(entryType models.JournalEntryType, referenceID, description, debitAccountID, creditAccountID string, debitAmount, creditAmount types.Money) *models.JournalEntry {
+	return &models.JournalEntry{
+		EntryID:     uuid.New().String(),
+		EntryType:   entryType,
+		ReferenceID: referenceID,
+		Description: description,
+		Postings: []*models.LedgerPosting{
+			{AccountID: debitAccountID, Direction: models.Debit, Amount: debitAmount},
+			{AccountID: fxLedgerAccount(debitAmount.Currency), Direction: models.Credit, Amount: debitAmount},
+			{AccountID: fxLedgerAccount(creditAmount.Currency), Direction: models.Debit, Amount: creditAmount},
+			{AccountID: creditAccountID, Direction: models.Credit, Amount: creditAmount},
+		},
+	}
+}
+
+// newJournalEntry builds an entry that moves amount from the debited account to the credited account
+func newJournalEntry(entryType models.JournalEntryType, referenceID, description, debitAccountID, creditAccountID string, amount types.Money) *models.JournalEntry {
+	return &models.JournalEntry{
+		EntryID:     uuid.New().String(),
+		EntryType:   entryType,
+		ReferenceID: referenceID,
+		Description: description,
+		Postings: []*models.LedgerPosting{
+			{AccountID: debitAccountID, Direction: models.Debit, Amount: amount},
+			{AccountID: creditAccountID, Direction: models.Credit, Amount: amount},
+		},
+	}
+}
+
+// validateJournalEntry checks every posting is positive and debits equal credits in each currency
+func validateJournalEntry(entry *models.JournalEntry) error {
+	if len(entry.Postings) < 2 {
+		return ErrLedgerUnbalanced
+	}
+
+	totals := make(map[string]int64)
+	for _, posting := range entry.Postings {
+		if !posting.Amount.IsPositive() {
+			return ErrLedgerInvalidPosting
+		}
+		if posting.Direction == models.Credit {
+			totals[posting.Amount.Currency] += posting.Amount.Amount
+		} else {
+			totals[posting.Amount.Currency] -= posting.Amount.Amount
+		}
+	}
+
+	for _, total := range totals {
+		if total != 0 {
+			return ErrLedgerUnbalanced
+		}
+	}
+
+	return nil
+}
+
+// postJournalEntry validates and writes an entry, normally through the ledger adapter of an open transaction
+func postJournalEntry(ledgerRepository repositories.LedgerRepository, entry *models.JournalEntry) error {
+	if err := validateJournalEntry(entry); err != nil {
+		logger.Error("Rejected invalid journal entry", zap.String("entry_type", string(entry.EntryType)), zap.Error(err))
+		return err
+	}
+
+	if err := ledgerRepository.PostEntry(entry); err != nil {
+		logger.Error("Failed to post journal entry",
+			zap.String("entry_type", string(entry.EntryType)),
+			zap.String("reference_id", entry.ReferenceID),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
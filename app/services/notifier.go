@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notifier delivers messages to users, e.g. one-time codes. LogNotifier and FileNotifier stand in for an SMS or
+// push provider.
+type Notifier interface {
+	Notify(userID, message string) error
+}
+
+// LogNotifier writes messages to the application log
+type LogNotifier struct{}
+
+// NewLogNotifier creates a Notifier writing to the application log
+func NewLogNotifier() Notifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the message for the user
+func (n *LogNotifier) Notify(userID, message string) error {
+	logger.Info("Notification", zap.String("user_id", userID), zap.String("message", message))
+	return nil
+}
+
+// FileNotifier appends messages to a file as JSON lines
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// notification is a line written by FileNotifier
+type notification struct {
+	UserID  string    `json:"user_id"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// NewFileNotifier creates a Notifier appending to the file at path
+func NewFileNotifier(path string) Notifier {
+	return &FileNotifier{path: path}
+}
+
+// Notify appends the message for the user to the file
+func (n *FileNotifier) Notify(userID, message string) error {
+	line, err := json.Marshal(notification{UserID: userID, Message: message, SentAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
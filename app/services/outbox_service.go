@@ -0,0 +1,162 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OutboxService relays the domain events of the outbox to the EventPublisher
+type OutboxService interface {
+	// RelayEvents publishes the unpublished events, it is called periodically by the scheduler
+	RelayEvents(ctx context.Context) error
+	// PurgePublishedEvents deletes events published longer than the retention ago
+	PurgePublishedEvents(ctx context.Context) error
+}
+
+// OutboxServiceImpl implements OutboxService
+type OutboxServiceImpl struct {
+	outboxRepository repositories.OutboxRepository
+	publisher        EventPublisher
+	leaseOwner       string
+}
+
+// NewOutboxService creates a new outbox service
+func NewOutboxService(outboxRepository repositories.OutboxRepository, publisher EventPublisher) OutboxService {
+	return &OutboxServiceImpl{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+		leaseOwner:       uuid.New().String(),
+	}
+}
+
+// RelayEvents publishes unpublished events in sequence order while this instance holds the relay lease.
+// Delivery is at least once: an event is marked published after it was published. Events of an aggregate stay
+// in order, once an event fails the later events of its aggregates wait for the next run.
+func (s *OutboxServiceImpl) RelayEvents(ctx context.Context) error {
+	for ctx.Err() == nil {
+		leased, err := s.outboxRepository.ClaimRelayLease(s.leaseOwner, time.Now(), configs.OUTBOX_LEASE_DURATION)
+		if err != nil {
+			return err
+		}
+		if !leased {
+			return nil // another instance relays
+		}
+
+		events, err := s.outboxRepository.GetUnpublished(configs.OUTBOX_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		failed, err := s.publishBatch(ctx, events)
+		if err != nil {
+			return err
+		}
+		if failed || len(events) < configs.OUTBOX_BATCH_SIZE {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// publishBatch publishes a batch of events and reports whether any of them failed
+func (s *OutboxServiceImpl) publishBatch(ctx context.Context, events []*models.DomainEvent) (bool, error) {
+	blocked := map[string]bool{}
+	failed := false
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return failed, err
+		}
+
+		keys := eventOrderingKeys(event)
+		if isAnyBlocked(blocked, keys) {
+			// An earlier event of the same aggregate is not published yet
+			blockAll(blocked, keys)
+			continue
+		}
+
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			logger.Warn("Failed to publish event",
+				zap.Int64("sequence", event.Sequence),
+				zap.String("event_id", event.EventID),
+				zap.String("event_type", string(event.EventType)),
+				zap.Error(err))
+			if err := s.outboxRepository.RecordFailure(event.Sequence, err.Error()); err != nil {
+				logger.Error("Failed to record event publishing failure", zap.Int64("sequence", event.Sequence), zap.Error(err))
+			}
+			blockAll(blocked, keys)
+			failed = true
+			continue
+		}
+
+		// An event that is not marked is published again by the next run
+		if err := s.outboxRepository.MarkPublished(event.Sequence, time.Now()); err != nil {
+			return failed, err
+		}
+	}
+
+	return failed, nil
+}
+
+// PurgePublishedEvents deletes events published longer than configs.OUTBOX_RETENTION ago
+func (s *OutboxServiceImpl) PurgePublishedEvents(_ context.Context) error {
+	deleted, err := s.outboxRepository.DeletePublished(time.Now().Add(-configs.OUTBOX_RETENTION))
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		logger.Info("Purged published events", zap.Int64("count", deleted))
+	}
+	return nil
+}
+
+// eventOrderingKeys returns the aggregates whose events must be published in order with the event
+func eventOrderingKeys(event *models.DomainEvent) []string {
+	keys := []string{event.AggregateType + ":" + event.AggregateID}
+	if event.RelatedAggregateID != "" {
+		keys = append(keys, event.AggregateType+":"+event.RelatedAggregateID)
+	}
+	return keys
+}
+
+func isAnyBlocked(blocked map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if blocked[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func blockAll(blocked map[string]bool, keys []string) {
+	for _, key := range keys {
+		blocked[key] = true
+	}
+}
+
+// recordEvent adds an event to the outbox, pass the outbox of the running transaction so that the event is
+// only published if the change it describes commits
+func recordEvent(outbox repositories.OutboxRepository, eventType models.EventType, aggregateType, aggregateID, relatedAggregateID string, payload interface{}) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return outbox.Create(&models.DomainEvent{
+		EventID:            uuid.New().String(),
+		EventType:          eventType,
+		AggregateType:      aggregateType,
+		AggregateID:        aggregateID,
+		RelatedAggregateID: relatedAggregateID,
+		Payload:            content,
+		OccurredAt:         time.Now(),
+	})
+}
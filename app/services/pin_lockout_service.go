@@ -0,0 +1,292 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Custom errors for PIN verification attempts
+var (
+	ErrPinLocked            = errors.New("PIN is locked after too many failed attempts, reset the PIN to unlock it")
+	ErrPinTemporarilyLocked = errors.New("PIN is temporarily locked after too many failed attempts")
+	ErrPinBackoff           = errors.New("too many failed PIN attempts, wait before trying again")
+	ErrPinThrottled         = errors.New("too many failed PIN attempts from this address")
+)
+
+// PinLockoutService defines the interface for tracking failed PIN verifications
+type PinLockoutService interface {
+	Reserve(userID, ipAddress string) (*models.PinAttempt, error)
+	ReserveIP(ipAddress string) (*models.PinAttempt, error)
+	RecordSuccess(attempt *models.PinAttempt) error
+	Release(attempt *models.PinAttempt) error
+	GetLockState(userID string) (*models.PinLockState, error)
+	Unlock(userID string) error
+}
+
+// PinLockoutServiceImpl implements PinLockoutService. Redis counts failures per IP address and caches the failures
+// of a user, MySQL keeps the failures of a user and counts failures per IP address while Redis is unavailable.
+type PinLockoutServiceImpl struct {
+	pinLockoutRepository repositories.PinLockoutRepository
+	redisClient          types.CacheClient
+}
+
+// NewPinLockoutService creates a new instance of PinLockoutService
+func NewPinLockoutService(pinLockoutRepository repositories.PinLockoutRepository, redisClient types.CacheClient) PinLockoutService {
+	return &PinLockoutServiceImpl{
+		pinLockoutRepository: pinLockoutRepository,
+		redisClient:          redisClient,
+	}
+}
+
+func pinLockoutCacheKey(userID string) string {
+	return fmt.Sprintf("pin_lockout:%s", userID)
+}
+
+func pinIPFailuresCacheKey(ipAddress string, windowStart time.Time) string {
+	return fmt.Sprintf("pin_ip_failures:%s:%d", ipAddress, windowStart.Unix())
+}
+
+// Reserve counts a PIN verification by the user from the IP address as failed before the PIN is checked, refusing it
+// while the PIN or the address is locked. Counting first means concurrent guesses are each counted and refused past
+// the thresholds, rather than all passing the same check. The attempt stays counted as failed, locking the PIN once
+// the user reaches configs.PIN_LOCKOUT_THRESHOLD consecutive failures and for good at
+// configs.PIN_PERMANENT_LOCK_THRESHOLD, unless RecordSuccess or Release takes it back. A refused attempt carries when
+// the next attempt is allowed for ErrPinBackoff, ErrPinTemporarilyLocked and ErrPinThrottled.
+func (s *PinLockoutServiceImpl) Reserve(userID, ipAddress string) (*models.PinAttempt, error) {
+	attempt, err := s.ReserveIP(ipAddress)
+	if err != nil {
+		return attempt, err
+	}
+	attempt.UserID = userID
+
+	now := time.Now()
+	var refused error
+	lockout, err := s.pinLockoutRepository.ReserveAttempt(userID, func(lockout *models.PinLockout) error {
+		previous := *lockout
+		attempt.Previous = &previous
+
+		attempt.State = pinLockState(lockout, now)
+		switch {
+		case attempt.State.Permanent:
+			refused = ErrPinLocked
+		case attempt.State.Locked:
+			refused = ErrPinTemporarilyLocked
+		case attempt.State.RetryAt != nil:
+			refused = ErrPinBackoff
+		}
+		if refused != nil {
+			return refused
+		}
+
+		countFailure(lockout, now)
+		return nil
+	})
+	if err != nil {
+		// The attempt is refused or never counted for the user, so the address does not count it either
+		s.releaseIP(attempt)
+		if refused != nil {
+			s.cacheLockout(attempt.Previous)
+			return attempt, refused
+		}
+		logger.Error("Failed to count PIN attempt", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	s.cacheLockout(lockout)
+
+	attempt.State = pinLockState(lockout, now)
+	if attempt.State.Locked {
+		logger.Warn("PIN locked after failed attempts", zap.String("user_id", userID),
+			zap.Int("failed_attempts", lockout.FailedAttempts), zap.Bool("permanent", attempt.State.Permanent))
+	}
+
+	return attempt, nil
+}
+
+// ReserveIP counts a PIN verification from the IP address as failed before it is checked, also for users that do
+// not exist, refusing it with ErrPinThrottled once the address reaches configs.PIN_IP_MAX_FAILURES in the window
+func (s *PinLockoutServiceImpl) ReserveIP(ipAddress string) (*models.PinAttempt, error) {
+	attempt := &models.PinAttempt{IPAddress: ipAddress, WindowStart: time.Now().Truncate(configs.PIN_IP_WINDOW)}
+
+	failures, err := s.redisClient.Increment(context.Background(), pinIPFailuresCacheKey(ipAddress, attempt.WindowStart), configs.PIN_IP_WINDOW)
+	if err == nil {
+		attempt.CountedInCache = true
+	} else {
+		logger.Warn("Failed to count PIN attempt in cache, counting it in the database", zap.String("ip", ipAddress), zap.Error(err))
+		failures, err = s.pinLockoutRepository.IncrementIPFailures(ipAddress, attempt.WindowStart)
+		if err != nil {
+			logger.Error("Failed to count PIN attempt", zap.String("ip", ipAddress), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// A refused attempt stays counted, the count only grows until the window ends
+	if failures > configs.PIN_IP_MAX_FAILURES {
+		retryAt := attempt.WindowStart.Add(configs.PIN_IP_WINDOW)
+		attempt.State = &models.PinLockState{Locked: true, RetryAt: &retryAt}
+		return attempt, ErrPinThrottled
+	}
+
+	return attempt, nil
+}
+
+// RecordSuccess takes an attempt back after the right PIN, forgetting the failed attempts of the user
+func (s *PinLockoutServiceImpl) RecordSuccess(attempt *models.PinAttempt) error {
+	if attempt == nil {
+		return nil
+	}
+
+	s.releaseIP(attempt)
+	if attempt.UserID == "" {
+		return nil
+	}
+	return s.Unlock(attempt.UserID)
+}
+
+// Release takes an attempt back when no PIN was guessed, restoring the failed attempts of the user from before it
+// unless other attempts were counted since
+func (s *PinLockoutServiceImpl) Release(attempt *models.PinAttempt) error {
+	if attempt == nil {
+		return nil
+	}
+
+	s.releaseIP(attempt)
+	if attempt.UserID == "" || attempt.Previous == nil {
+		return nil
+	}
+
+	reserved := attempt.Previous.FailedAttempts + 1
+	if err := s.pinLockoutRepository.ReleaseAttempt(attempt.Previous, reserved); err != nil {
+		logger.Error("Failed to release PIN attempt", zap.String("user_id", attempt.UserID), zap.Error(err))
+		return err
+	}
+	// The row may have moved on meanwhile, so the cache is refreshed from the database on the next read
+	if err := s.redisClient.Delete(context.Background(), pinLockoutCacheKey(attempt.UserID)); err != nil {
+		logger.Warn("Failed to delete cache for failed PIN attempts", zap.String("user_id", attempt.UserID), zap.Error(err))
+	}
+
+	return nil
+}
+
+// GetLockState returns whether the PIN of a user is locked
+func (s *PinLockoutServiceImpl) GetLockState(userID string) (*models.PinLockState, error) {
+	lockout, err := s.getLockout(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pinLockState(lockout, time.Now()), nil
+}
+
+// Unlock forgets the failed attempts of a user, lifting a temporary or permanent lock
+func (s *PinLockoutServiceImpl) Unlock(userID string) error {
+	if err := s.pinLockoutRepository.Delete(userID); err != nil {
+		logger.Error("Failed to unlock PIN", zap.String("user_id", userID), zap.Error(err))
+		return err
+	}
+	s.cacheLockout(&models.PinLockout{UserID: userID})
+
+	return nil
+}
+
+// getLockout looks the failed attempts of a user up in the cache first, a user without failures gets an empty row
+func (s *PinLockoutServiceImpl) getLockout(userID string) (*models.PinLockout, error) {
+	cachedData, err := s.redisClient.Get(context.Background(), pinLockoutCacheKey(userID))
+	if err == nil {
+		var lockout models.PinLockout
+		if err := json.Unmarshal([]byte(cachedData), &lockout); err == nil {
+			return &lockout, nil
+		}
+	}
+
+	lockout, err := s.pinLockoutRepository.GetByUserID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		lockout, err = &models.PinLockout{UserID: userID}, nil
+	}
+	if err != nil {
+		logger.Error("Failed to get failed PIN attempts", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	s.cacheLockout(lockout)
+	return lockout, nil
+}
+
+// releaseIP takes back the failure an attempt counted for its IP address, in the store it was counted in
+func (s *PinLockoutServiceImpl) releaseIP(attempt *models.PinAttempt) {
+	var err error
+	if attempt.CountedInCache {
+		_, err = s.redisClient.Decrement(context.Background(), pinIPFailuresCacheKey(attempt.IPAddress, attempt.WindowStart))
+	} else {
+		err = s.pinLockoutRepository.DecrementIPFailures(attempt.IPAddress, attempt.WindowStart)
+	}
+	if err != nil {
+		logger.Warn("Failed to release PIN attempt", zap.String("ip", attempt.IPAddress), zap.Error(err))
+	}
+}
+
+// cacheLockout stores the failed attempts of a user in the cache
+func (s *PinLockoutServiceImpl) cacheLockout(lockout *models.PinLockout) {
+	if data, err := json.Marshal(lockout); err == nil {
+		if err := s.redisClient.Set(context.Background(), pinLockoutCacheKey(lockout.UserID), data, configs.PIN_LOCKOUT_CACHE_TTL); err != nil {
+			logger.Warn("Failed to set cache for failed PIN attempts", zap.String("user_id", lockout.UserID), zap.Error(err))
+		}
+	}
+}
+
+// pinLockState describes the failed attempts of a user at the given time
+func pinLockState(lockout *models.PinLockout, now time.Time) *models.PinLockState {
+	state := &models.PinLockState{FailedAttempts: lockout.FailedAttempts}
+
+	switch {
+	case lockout.LockedAt != nil:
+		state.Locked = true
+		state.Permanent = true
+	case lockout.LockedUntil != nil && now.Before(*lockout.LockedUntil):
+		state.Locked = true
+		state.RetryAt = lockout.LockedUntil
+	case lockout.LastFailedAt != nil && lockout.FailedAttempts > 0:
+		retryAt := lockout.LastFailedAt.Add(pinBackoff(lockout.FailedAttempts))
+		if now.Before(retryAt) {
+			state.RetryAt = &retryAt
+		}
+	}
+
+	return state
+}
+
+// countFailure counts a failed attempt on the row of a user, locking the PIN at the thresholds
+func countFailure(lockout *models.PinLockout, now time.Time) {
+	lockout.FailedAttempts++
+	lockout.LastFailedAt = &now
+
+	switch {
+	case lockout.FailedAttempts >= configs.PIN_PERMANENT_LOCK_THRESHOLD:
+		lockout.LockedAt = &now
+		lockout.LockedUntil = nil
+	case lockout.FailedAttempts >= configs.PIN_LOCKOUT_THRESHOLD:
+		lockedUntil := now.Add(configs.PIN_LOCKOUT_DURATION)
+		lockout.LockedUntil = &lockedUntil
+	}
+}
+
+// pinBackoff returns the wait after the given number of consecutive failures, doubling from the base backoff up to the cap
+func pinBackoff(failedAttempts int) time.Duration {
+	backoff := configs.PIN_BACKOFF_BASE
+	for i := 1; i < failedAttempts; i++ {
+		backoff *= 2
+		if backoff >= configs.PIN_BACKOFF_MAX {
+			return configs.PIN_BACKOFF_MAX
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/utils"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Custom errors for PIN changes
+var (
+	ErrIncorrectPin     = errors.New("current PIN is incorrect")
+	ErrPinPolicy        = errors.New("PIN does not meet the PIN policy")
+	ErrPinReused        = errors.New("PIN was used recently, choose a different PIN")
+	ErrInvalidResetCode = errors.New("invalid or expired reset code")
+	ErrResetTooSoon     = errors.New("a reset code was requested moments ago, wait before requesting another")
+)
+
+// PinService defines the interface for changing and resetting PINs
+type PinService interface {
+	ChangePin(userID, currentPIN, newPIN string) error
+	RequestReset(userID string) (time.Time, error)
+	ConfirmReset(userID, code, newPIN string) error
+}
+
+// PinServiceImpl implements PinService. Setting a PIN revokes the refresh tokens of the user on every device.
+type PinServiceImpl struct {
+	userRepository    repositories.UserRepository
+	pinRepository     repositories.PinRepository
+	authService       AuthService
+	pinLockoutService PinLockoutService
+	notifier          Notifier
+}
+
+// NewPinService creates a new instance of PinService
+func NewPinService(
+	userRepository repositories.UserRepository,
+	pinRepository repositories.PinRepository,
+	authService AuthService,
+	pinLockoutService PinLockoutService,
+	notifier Notifier,
+) PinService {
+	return &PinServiceImpl{
+		userRepository:    userRepository,
+		pinRepository:     pinRepository,
+		authService:       authService,
+		pinLockoutService: pinLockoutService,
+		notifier:          notifier,
+	}
+}
+
+// ChangePin replaces the PIN of a user who knows the current PIN
+func (s *PinServiceImpl) ChangePin(userID, currentPIN, newPIN string) error {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !utils.VerifyPIN(user.PIN, currentPIN) {
+		return ErrIncorrectPin
+	}
+
+	return s.setPin(user, newPIN)
+}
+
+// RequestReset sends a one-time code to reset the PIN through the notifier and returns when the code expires
+func (s *PinServiceImpl) RequestReset(userID string) (time.Time, error) {
+	if _, err := s.userRepository.GetByID(userID); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	existing, err := s.pinRepository.GetResetCode(userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, err
+	}
+	if existing != nil && now.Before(existing.CreatedAt.Add(configs.PIN_RESET_REQUEST_INTERVAL)) {
+		return time.Time{}, ErrResetTooSoon
+	}
+
+	code, err := generateResetCode()
+	if err != nil {
+		return time.Time{}, err
+	}
+	codeHash, err := utils.HashPIN(code)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resetCode := &models.PinResetCode{
+		UserID:    userID,
+		CodeHash:  codeHash,
+		ExpiresAt: now.Add(configs.PIN_RESET_CODE_TTL),
+		CreatedAt: now,
+	}
+	if err := s.pinRepository.SaveResetCode(resetCode); err != nil {
+		logger.Error("Failed to save PIN reset code", zap.String("user_id", userID), zap.Error(err))
+		return time.Time{}, err
+	}
+
+	message := fmt.Sprintf("Your PIN reset code is %s, it expires in %s.", code, configs.PIN_RESET_CODE_TTL)
+	if err := s.notifier.Notify(userID, message); err != nil {
+		logger.Error("Failed to send PIN reset code", zap.String("user_id", userID), zap.Error(err))
+		return time.Time{}, err
+	}
+
+	return resetCode.ExpiresAt, nil
+}
+
+// ConfirmReset sets a new PIN with the one-time code sent by RequestReset, lifting any PIN lock. A code that
+// expired or was entered wrong configs.PIN_RESET_MAX_ATTEMPTS times has to be requested again.
+func (s *PinServiceImpl) ConfirmReset(userID, code, newPIN string) error {
+	resetCode, err := s.pinRepository.GetResetCode(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidResetCode
+	}
+	if err != nil {
+		return err
+	}
+	if resetCode.IsExpired(time.Now()) {
+		if _, err := s.pinRepository.DeleteResetCode(resetCode); err != nil {
+			return err
+		}
+		return ErrInvalidResetCode
+	}
+
+	// The attempt is counted before the code is verified, so concurrent guesses can't exceed the cap
+	reserved, err := s.pinRepository.ReserveResetAttempt(resetCode, configs.PIN_RESET_MAX_ATTEMPTS)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		if _, err := s.pinRepository.DeleteResetCode(resetCode); err != nil {
+			return err
+		}
+		return ErrInvalidResetCode
+	}
+	if !utils.VerifyPIN(resetCode.CodeHash, code) {
+		return ErrInvalidResetCode
+	}
+
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// The code stays valid when the new PIN is rejected, so another PIN can be tried
+	pinHash, err := s.checkNewPin(user, newPIN)
+	if err != nil {
+		if err := s.pinRepository.ReleaseResetAttempt(resetCode); err != nil {
+			logger.Error("Failed to release PIN reset attempt", zap.String("user_id", userID), zap.Error(err))
+		}
+		return err
+	}
+
+	// Only the request that deletes the code sets the PIN with it
+	deleted, err := s.pinRepository.DeleteResetCode(resetCode)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInvalidResetCode
+	}
+	if err := s.storePin(user, pinHash); err != nil {
+		return err
+	}
+
+	return s.pinLockoutService.Unlock(userID)
+}
+
+// setPin checks the new PIN against the policy and the recent PINs, stores it and signs the user out everywhere
+func (s *PinServiceImpl) setPin(user *models.User, newPIN string) error {
+	pinHash, err := s.checkNewPin(user, newPIN)
+	if err != nil {
+		return err
+	}
+	return s.storePin(user, pinHash)
+}
+
+// checkNewPin checks the new PIN against the policy and the recent PINs of the user and returns its hash
+func (s *PinServiceImpl) checkNewPin(user *models.User, newPIN string) (string, error) {
+	if err := checkPinPolicy(newPIN); err != nil {
+		return "", err
+	}
+
+	recentHashes, err := s.pinRepository.GetPinHistory(user.UserID, configs.PIN_HISTORY_SIZE-1)
+	if err != nil {
+		return "", err
+	}
+	for _, hash := range append([]string{user.PIN}, recentHashes...) {
+		if hash != "" && utils.VerifyPIN(hash, newPIN) {
+			return "", ErrPinReused
+		}
+	}
+
+	return utils.HashPIN(newPIN)
+}
+
+// storePin stores the hash of the new PIN and signs the user out everywhere
+func (s *PinServiceImpl) storePin(user *models.User, pinHash string) error {
+	if err := s.pinRepository.ReplacePin(user.UserID, pinHash, configs.PIN_HISTORY_SIZE-1); err != nil {
+		logger.Error("Failed to replace PIN", zap.String("user_id", user.UserID), zap.Error(err))
+		return err
+	}
+
+	// Sessions signed in with the old PIN can no longer renew their tokens
+	if _, err := s.authService.LogoutAll(user.UserID); err != nil {
+		logger.Error("Failed to revoke refresh tokens after PIN change", zap.String("user_id", user.UserID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// checkPinPolicy rejects PINs that are not configs.PIN_LENGTH digits or contain a run of the same or sequential
+// digits longer than configs.PIN_MAX_DIGIT_RUN
+func checkPinPolicy(pin string) error {
+	if len(pin) != configs.PIN_LENGTH {
+		return fmt.Errorf("%w: PIN must be %d digits", ErrPinPolicy, configs.PIN_LENGTH)
+	}
+
+	repeated, ascending, descending := 1, 1, 1
+	for i := range len(pin) {
+		if pin[i] < '0' || pin[i] > '9' {
+			return fmt.Errorf("%w: PIN must be %d digits", ErrPinPolicy, configs.PIN_LENGTH)
+		}
+		if i == 0 {
+			continue
+		}
+
+		repeated = nextRun(repeated, pin[i] == pin[i-1])
+		ascending = nextRun(ascending, pin[i] == pin[i-1]+1)
+		descending = nextRun(descending, pin[i] == pin[i-1]-1)
+
+		if repeated > configs.PIN_MAX_DIGIT_RUN {
+			return fmt.Errorf("%w: PIN must not repeat a digit more than %d times in a row", ErrPinPolicy, configs.PIN_MAX_DIGIT_RUN)
+		}
+		if ascending > configs.PIN_MAX_DIGIT_RUN || descending > configs.PIN_MAX_DIGIT_RUN {
+			return fmt.Errorf("%w: PIN must not contain more than %d sequential digits", ErrPinPolicy, configs.PIN_MAX_DIGIT_RUN)
+		}
+	}
+
+	return nil
+}
+
+// nextRun extends a run of digits or starts a new one
+func nextRun(run int, continues bool) int {
+	if continues {
+		return run + 1
+	}
+	return 1
+}
+
+// generateResetCode returns a random code of configs.PIN_LENGTH digits
+func generateResetCode() (string, error) {
+	limit := big.NewInt(1)
+	for range configs.PIN_LENGTH {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", configs.PIN_LENGTH, n), nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrRateUnavailable is returned when no exchange rate is known for a currency pair
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// RateProvider supplies the exchange rate of one unit of a currency in another
+type RateProvider interface {
+	GetRate(fromCurrency, toCurrency string) (types.Rate, error)
+}
+
+// DBRateProvider reads rates from the fx_rates table
+type DBRateProvider struct {
+	fxRepository repositories.FXRepository
+}
+
+// NewDBRateProvider creates a RateProvider backed by the fx_rates table
+func NewDBRateProvider(fxRepository repositories.FXRepository) RateProvider {
+	return &DBRateProvider{
+		fxRepository: fxRepository,
+	}
+}
+
+// GetRate returns the stored rate of the pair, or the inverse of the opposite pair
+func (p *DBRateProvider) GetRate(fromCurrency, toCurrency string) (types.Rate, error) {
+	return lookupRate(fromCurrency, toCurrency, func(base, quote string) (types.Rate, bool, error) {
+		rate, err := p.fxRepository.GetRate(base, quote)
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.Rate{}, false, nil
+		}
+		return rate, err == nil, err
+	})
+}
+
+// FileRateProvider serves fixed rates loaded from a JSON file, e.g. {"USD/THB": "36.50"}
+type FileRateProvider struct {
+	rates map[string]types.Rate
+}
+
+// NewFileRateProvider loads the rates of a FileRateProvider from the given path
+func NewFileRateProvider(path string) (RateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]types.Rate{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]types.Rate, len(raw))
+	for pair, rate := range raw {
+		base, quote, ok := strings.Cut(strings.ToUpper(pair), "/")
+		if !ok || !types.IsValidCurrency(base) || !types.IsValidCurrency(quote) {
+			return nil, fmt.Errorf("invalid currency pair %q in %s", pair, path)
+		}
+		rates[base+"/"+quote] = rate
+	}
+
+	return &FileRateProvider{rates: rates}, nil
+}
+
+// GetRate returns the configured rate of the pair, or the inverse of the opposite pair
+func (p *FileRateProvider) GetRate(fromCurrency, toCurrency string) (types.Rate, error) {
+	return lookupRate(fromCurrency, toCurrency, func(base, quote string) (types.Rate, bool, error) {
+		rate, ok := p.rates[base+"/"+quote]
+		return rate, ok, nil
+	})
+}
+
+// lookupRate finds the rate of a pair through find, falling back to inverting the opposite pair
+func lookupRate(fromCurrency, toCurrency string, find func(base, quote string) (types.Rate, bool, error)) (types.Rate, error) {
+	rate, ok, err := find(fromCurrency, toCurrency)
+	if err != nil {
+		return types.Rate{}, err
+	}
+	if ok {
+		return rate, nil
+	}
+
+	rate, ok, err = find(toCurrency, fromCurrency)
+	if err != nil {
+		return types.Rate{}, err
+	}
+	if !ok {
+		return types.Rate{}, fmt.Errorf("%w: %s/%s", ErrRateUnavailable, fromCurrency, toCurrency)
+	}
+
+	return rate.Inverse()
+}
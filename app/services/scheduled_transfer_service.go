@@ -0,0 +1,419 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Custom errors for scheduled transfers
+var (
+	ErrScheduleNotFound    = errors.New("scheduled transfer not found")
+	ErrInvalidSchedule     = errors.New("invalid scheduled transfer")
+	ErrScheduleNotEditable = errors.New("scheduled transfer is completed and can no longer be changed")
+)
+
+// ScheduledTransferService defines the interface for standing orders between accounts
+type ScheduledTransferService interface {
+	GetSchedulesByAccountID(accountID string) ([]*models.ScheduledTransfer, error)
+	GetSchedule(accountID, scheduleID string) (*models.ScheduledTransfer, error)
+	CreateSchedule(schedule *models.ScheduledTransfer) error
+	UpdateSchedule(accountID, scheduleID string, update *models.ScheduledTransferUpdate) (*models.ScheduledTransfer, error)
+	CancelSchedule(accountID, scheduleID string) error
+	GetExecutions(accountID, scheduleID string) ([]*models.ScheduledTransferExecution, error)
+
+	// RunDueSchedules executes every schedule that is due, it is called periodically by the scheduler
+	RunDueSchedules(ctx context.Context) error
+}
+
+// ScheduledTransferServiceImpl implements ScheduledTransferService
+type ScheduledTransferServiceImpl struct {
+	scheduledTransferRepository repositories.ScheduledTransferRepository
+	accountService              AccountService
+	auditService                AuditService
+}
+
+// NewScheduledTransferService creates a new instance of ScheduledTransferService
+func NewScheduledTransferService(scheduledTransferRepository repositories.ScheduledTransferRepository, accountService AccountService, auditService AuditService) ScheduledTransferService {
+	return &ScheduledTransferServiceImpl{
+		scheduledTransferRepository: scheduledTransferRepository,
+		accountService:              accountService,
+		auditService:                auditService,
+	}
+}
+
+// GetSchedulesByAccountID retrieves the schedules paid from an account
+func (s *ScheduledTransferServiceImpl) GetSchedulesByAccountID(accountID string) ([]*models.ScheduledTransfer, error) {
+	return s.scheduledTransferRepository.GetByAccountID(accountID)
+}
+
+// GetSchedule retrieves a schedule paid from the given account
+func (s *ScheduledTransferServiceImpl) GetSchedule(accountID, scheduleID string) (*models.ScheduledTransfer, error) {
+	schedule, err := s.scheduledTransferRepository.GetByID(scheduleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	if schedule.FromAccountID != accountID {
+		return nil, ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+// CreateSchedule validates and stores a new schedule. The first occurrence runs at StartAt, or now when it is not set.
+func (s *ScheduledTransferServiceImpl) CreateSchedule(schedule *models.ScheduledTransfer) error {
+	if !schedule.Amount.IsPositive() {
+		return ErrInvalidAmount
+	}
+	if schedule.FromAccountID == schedule.ToAccountID {
+		return fmt.Errorf("%w: %w", ErrInvalidSchedule, ErrSameAccount)
+	}
+
+	sourceAccount, err := s.accountService.GetAccountByID(schedule.FromAccountID)
+	if err != nil {
+		return err
+	}
+	destAccount, err := s.accountService.GetAccountByID(schedule.ToAccountID)
+	if err != nil {
+		return err
+	}
+	if schedule.Amount.Currency != sourceAccount.Currency || schedule.Amount.Currency != destAccount.Currency {
+		return ErrCurrencyMismatch
+	}
+
+	now := time.Now().UTC()
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	schedule.StartAt = schedule.StartAt.UTC()
+	// Allow a little clock skew for clients that send "now"
+	if schedule.StartAt.Before(now.Add(-time.Minute)) {
+		return fmt.Errorf("%w: start_at must not be in the past", ErrInvalidSchedule)
+	}
+	if schedule.EndAt != nil {
+		endAt := schedule.EndAt.UTC()
+		if !endAt.After(schedule.StartAt) {
+			return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidSchedule)
+		}
+		schedule.EndAt = &endAt
+	}
+
+	if schedule.InsufficientFundsPolicy == "" {
+		schedule.InsufficientFundsPolicy = models.SkipOnInsufficientFunds
+	}
+
+	schedule.ScheduleID = uuid.New().String()
+	schedule.Status = models.ScheduleActive
+	schedule.NextRunAt = schedule.StartAt
+	schedule.NextAttemptAt = schedule.StartAt
+	schedule.RetryCount = 0
+
+	return s.scheduledTransferRepository.Create(schedule)
+}
+
+// UpdateSchedule applies a partial update. Resuming a paused schedule or changing its frequency
+// moves the next run to the first occurrence from now, missed occurrences are not executed.
+func (s *ScheduledTransferServiceImpl) UpdateSchedule(accountID, scheduleID string, update *models.ScheduledTransferUpdate) (*models.ScheduledTransfer, error) {
+	schedule, err := s.GetSchedule(accountID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status == models.ScheduleCompleted || schedule.Status == models.ScheduleCancelled {
+		return nil, ErrScheduleNotEditable
+	}
+
+	if update.Amount != nil {
+		if !update.Amount.IsPositive() {
+			return nil, ErrInvalidAmount
+		}
+		if !update.Amount.SameCurrency(schedule.Amount) {
+			return nil, ErrCurrencyMismatch
+		}
+		schedule.Amount = *update.Amount
+	}
+	if update.Description != nil {
+		schedule.Description = *update.Description
+	}
+	if update.InsufficientFundsPolicy != nil {
+		schedule.InsufficientFundsPolicy = *update.InsufficientFundsPolicy
+	}
+	if update.EndAt != nil {
+		endAt := update.EndAt.UTC()
+		if !endAt.After(schedule.StartAt) {
+			return nil, fmt.Errorf("%w: end_at must be after start_at", ErrInvalidSchedule)
+		}
+		schedule.EndAt = &endAt
+	}
+
+	reschedule := false
+	if update.Frequency != nil && *update.Frequency != schedule.Frequency {
+		schedule.Frequency = *update.Frequency
+		reschedule = true
+	}
+	if update.Status != nil && *update.Status != schedule.Status {
+		switch *update.Status {
+		case models.ScheduleActive:
+			reschedule = true
+		case models.SchedulePaused:
+		default:
+			return nil, fmt.Errorf("%w: status can only be set to active or paused", ErrInvalidSchedule)
+		}
+		schedule.Status = *update.Status
+	}
+
+	if reschedule && schedule.Status == models.ScheduleActive {
+		schedule.NextRunAt = firstOccurrenceFrom(schedule, time.Now().UTC())
+		schedule.NextAttemptAt = schedule.NextRunAt
+		schedule.RetryCount = 0
+	}
+
+	// Shortening a schedule past its next run ends it
+	if schedule.EndAt != nil && schedule.NextRunAt.After(*schedule.EndAt) {
+		schedule.Status = models.ScheduleCompleted
+	}
+
+	if err := s.scheduledTransferRepository.Update(schedule); err != nil {
+		logger.Error("Failed to update scheduled transfer", zap.String("schedule_id", scheduleID), zap.Error(err))
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// CancelSchedule stops a schedule, executions already made are kept
+func (s *ScheduledTransferServiceImpl) CancelSchedule(accountID, scheduleID string) error {
+	if _, err := s.GetSchedule(accountID, scheduleID); err != nil {
+		return err
+	}
+	return s.scheduledTransferRepository.Cancel(scheduleID)
+}
+
+// GetExecutions retrieves the execution history of a schedule, most recent first
+func (s *ScheduledTransferServiceImpl) GetExecutions(accountID, scheduleID string) ([]*models.ScheduledTransferExecution, error) {
+	if _, err := s.GetSchedule(accountID, scheduleID); err != nil {
+		return nil, err
+	}
+	return s.scheduledTransferRepository.GetExecutions(scheduleID, configs.SCHEDULE_EXECUTIONS_LIMIT)
+}
+
+// RunDueSchedules leases a batch of due schedules and executes them one by one.
+// Leasing makes sure a schedule is executed by a single instance even when several instances run the scheduler.
+func (s *ScheduledTransferServiceImpl) RunDueSchedules(ctx context.Context) error {
+	now := time.Now().UTC()
+	leaseOwner := uuid.New().String()
+
+	schedules, err := s.scheduledTransferRepository.ClaimDue(leaseOwner, now, configs.SCHEDULER_LEASE_DURATION, configs.SCHEDULER_BATCH_SIZE)
+	if err != nil {
+		logger.Error("Failed to claim due scheduled transfers", zap.Error(err))
+		return err
+	}
+
+	for _, schedule := range schedules {
+		// Schedules left over are picked up again once their lease expires
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := s.executeSchedule(schedule, now); err != nil {
+			logger.Error("Failed to record scheduled transfer execution", zap.String("schedule_id", schedule.ScheduleID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// executeSchedule runs one occurrence of a leased schedule and records the outcome, in the audit trail as well.
+// A transfer that goes through is recorded in its own database transaction, which only commits while this instance
+// still holds the lease, so that an occurrence is never paid twice. Should the bookkeeping fail, the transfer is
+// rolled back with it and the occurrence is run again once the lease expires. A transfer that did not go through
+// moved no money and is recorded on its own.
+func (s *ScheduledTransferServiceImpl) executeSchedule(schedule *models.ScheduledTransfer, now time.Time) error {
+	attempt := schedule.RetryCount + 1
+	execution := &models.ScheduledTransferExecution{
+		ExecutionID:  uuid.New().String(),
+		ScheduleID:   schedule.ScheduleID,
+		ScheduledFor: schedule.NextRunAt,
+		ExecutedAt:   now,
+		Attempt:      attempt,
+	}
+
+	bookkept := false
+	_, err := s.accountService.TransferBetweenAccountsWith(schedule.FromAccountID, schedule.ToAccountID, schedule.Amount,
+		func(adapters repositories.Adapters) error {
+			bookkept = true
+			execution.Status = models.ExecutionSucceeded
+			advanceSchedule(schedule, now)
+			schedule.LastRunAt = &now
+			return adapters.ScheduledTransferRepository.RecordExecution(schedule, execution)
+		})
+	if bookkept {
+		if err != nil {
+			return err
+		}
+		s.auditExecution(schedule, execution)
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, ErrInsufficientFunds) && schedule.InsufficientFundsPolicy == models.SkipOnInsufficientFunds:
+		execution.Status = models.ExecutionSkipped
+		execution.Message = err.Error()
+		advanceSchedule(schedule, now)
+	case isRetryableTransferError(err) && attempt <= configs.SCHEDULER_MAX_RETRIES:
+		execution.Status = models.ExecutionRetrying
+		execution.Message = err.Error()
+		schedule.RetryCount = attempt
+		schedule.NextAttemptAt = now.Add(retryBackoff(attempt))
+	default:
+		execution.Status = models.ExecutionFailed
+		execution.Message = err.Error()
+		advanceSchedule(schedule, now)
+	}
+	schedule.LastRunAt = &now
+
+	logger.Warn("Scheduled transfer did not go through",
+		zap.String("schedule_id", schedule.ScheduleID),
+		zap.String("status", string(execution.Status)),
+		zap.Int("attempt", attempt),
+		zap.Error(err))
+	s.auditExecution(schedule, execution)
+
+	return s.scheduledTransferRepository.RecordExecution(schedule, execution)
+}
+
+// auditExecution records an execution as an action of the system on the schedule, a transfer that did not go
+// through is a failure
+func (s *ScheduledTransferServiceImpl) auditExecution(schedule *models.ScheduledTransfer, execution *models.ScheduledTransferExecution) {
+	entry := &models.AuditLog{
+		Action:       models.AuditScheduleExecute,
+		ActorType:    models.AuditActorSystem,
+		ResourceType: "schedule",
+		ResourceID:   schedule.ScheduleID,
+	}
+	if execution.Status != models.ExecutionSucceeded {
+		entry.Outcome = models.AuditFailure
+	}
+
+	s.auditService.Record(entry, nil, map[string]interface{}{
+		"execution_id":    execution.ExecutionID,
+		"user_id":         schedule.UserID,
+		"from_account_id": schedule.FromAccountID,
+		"to_account_id":   schedule.ToAccountID,
+		"amount":          schedule.Amount,
+		"attempt":         execution.Attempt,
+		"status":          execution.Status,
+		"message":         execution.Message,
+	})
+}
+
+// isRetryableTransferError reports whether a failed transfer may succeed when tried again
+func isRetryableTransferError(err error) bool {
+	return !errors.Is(err, ErrInvalidAmount) &&
+		!errors.Is(err, ErrCurrencyMismatch) &&
+		!errors.Is(err, ErrTransferLimitExceeded) &&
+		!errors.Is(err, ErrAccountFrozen) &&
+		!errors.Is(err, sql.ErrNoRows)
+}
+
+// retryBackoff returns the delay before the given attempt is retried, doubling from the base backoff up to the cap
+func retryBackoff(attempt int) time.Duration {
+	backoff := configs.SCHEDULER_RETRY_BASE_BACKOFF
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= configs.SCHEDULER_RETRY_MAX_BACKOFF {
+			return configs.SCHEDULER_RETRY_MAX_BACKOFF
+		}
+	}
+	return backoff
+}
+
+// advanceSchedule moves a schedule to its next occurrence after now, or completes it when there is none.
+// Occurrences missed while no scheduler was running are not replayed.
+func advanceSchedule(schedule *models.ScheduledTransfer, now time.Time) {
+	schedule.RetryCount = 0
+
+	if schedule.Frequency == models.FrequencyOnce {
+		schedule.Status = models.ScheduleCompleted
+		return
+	}
+
+	after := schedule.NextRunAt
+	if now.After(after) {
+		after = now
+	}
+	next := nextScheduleOccurrence(schedule, after)
+	if schedule.EndAt != nil && next.After(*schedule.EndAt) {
+		schedule.Status = models.ScheduleCompleted
+		return
+	}
+
+	schedule.NextRunAt = next
+	schedule.NextAttemptAt = next
+}
+
+// firstOccurrenceFrom returns the first occurrence of the schedule at or after from
+func firstOccurrenceFrom(schedule *models.ScheduledTransfer, from time.Time) time.Time {
+	if !schedule.StartAt.Before(from) {
+		return schedule.StartAt
+	}
+	if schedule.Frequency == models.FrequencyOnce {
+		return from
+	}
+	return nextScheduleOccurrence(schedule, from.Add(-time.Nanosecond))
+}
+
+// nextScheduleOccurrence returns the first occurrence of a recurring schedule strictly after the given time
+func nextScheduleOccurrence(schedule *models.ScheduledTransfer, after time.Time) time.Time {
+	start := schedule.StartAt
+
+	// Estimate the occurrence index, then step forward to the exact one
+	n := 0
+	switch schedule.Frequency {
+	case models.FrequencyDaily:
+		n = int(after.Sub(start) / (24 * time.Hour))
+	case models.FrequencyWeekly:
+		n = int(after.Sub(start) / (7 * 24 * time.Hour))
+	case models.FrequencyMonthly:
+		n = (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+	default:
+		// A one-off schedule has no further occurrences
+		return start
+	}
+	n = max(n-1, 0)
+
+	for !scheduleOccurrence(schedule, n).After(after) {
+		n++
+	}
+	return scheduleOccurrence(schedule, n)
+}
+
+// scheduleOccurrence returns the n-th occurrence of a schedule, counting StartAt as occurrence 0
+func scheduleOccurrence(schedule *models.ScheduledTransfer, n int) time.Time {
+	start := schedule.StartAt
+	switch schedule.Frequency {
+	case models.FrequencyDaily:
+		return start.AddDate(0, 0, n)
+	case models.FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case models.FrequencyMonthly:
+		// Keep the day of month of the start date, clamped to the length of the month
+		firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(n), 1,
+			start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+		return firstOfMonth.AddDate(0, 0, min(start.Day(), lastDay)-1)
+	default:
+		return start
+	}
+}
@@ -2,26 +2,169 @@ package services
 
 import (
 	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
 	"backend-developer-assignment/pkg/middleware"
 	"backend-developer-assignment/pkg/types"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
 )
 
 type Service struct {
-	UserService        UserService
-	TransactionService TransactionService
-	DebitCardService   DebitCardService
-	AccountService     AccountService
-	BannerService      BannerService
+	AuthService              AuthService
+	UserService              UserService
+	PinLockoutService        PinLockoutService
+	PinService               PinService
+	TransactionService       TransactionService
+	DebitCardService         DebitCardService
+	AccountService           AccountService
+	BannerService            BannerService
+	LedgerService            LedgerService
+	IdempotencyService       IdempotencyService
+	ScheduledTransferService ScheduledTransferService
+	TransferLimitService     TransferLimitService
+	FXService                FXService
+	TOTPService              TOTPService
+	ChallengeService         ChallengeService
+	AuditService             AuditService
+	OutboxService            OutboxService
+	WebhookService           WebhookService
+	StreamService            StreamService
+	StatementService         StatementService
 }
 
 var logger = middleware.GetLogger()
 
 func InitService(repo *repositories.Repository, txProvider repositories.TxProvider, redisClient types.CacheClient) *Service {
+	auditService := NewAuditService(repo.AuditLogRepository)
+	accountService := NewAccountService(repo.AccountRepository, repo.TransactionRepository, repo.HoldRepository, txProvider, redisClient)
+	authService := NewAuthService(repo.RefreshTokenRepository, repo.SessionRepository, repo.RoleRepository, redisClient)
+	pinLockoutService := NewPinLockoutService(repo.PinLockoutRepository, redisClient)
+	totpService := NewTOTPService(repo.TOTPRepository)
+	webhookService := NewWebhookService(repo.WebhookRepository, NewWebhookHTTPClient(WebhookPrivateNetworksAllowed()))
+	streamService := NewStreamService(newStreamBroker(redisClient))
+	ledgerService := NewLedgerService(repo.LedgerRepository)
+
 	return &Service{
-		UserService:        NewUserService(repo.UserRepository, repo.UserGreetingsRepository),
-		TransactionService: NewTransactionService(repo.TransactionRepository, redisClient),
-		DebitCardService:   NewDebitCardService(repo.DebitCardRepository),
-		AccountService:     NewAccountService(repo.AccountRepository, repo.TransactionRepository, txProvider),
-		BannerService:      NewBannerService(repo.BannerRepository),
+		AuthService:              authService,
+		UserService:              NewUserService(repo.UserRepository, repo.UserGreetingsRepository, repo.RoleRepository),
+		PinLockoutService:        pinLockoutService,
+		PinService:               NewPinService(repo.UserRepository, repo.PinRepository, authService, pinLockoutService, newNotifier()),
+		TransactionService:       NewTransactionService(repo.TransactionRepository, txProvider, redisClient),
+		DebitCardService:         NewDebitCardService(repo.DebitCardRepository, txProvider),
+		AccountService:           accountService,
+		BannerService:            NewBannerService(repo.BannerRepository),
+		LedgerService:            ledgerService,
+		IdempotencyService:       NewIdempotencyService(repo.IdempotencyRepository, redisClient),
+		ScheduledTransferService: NewScheduledTransferService(repo.ScheduledTransferRepository, accountService, auditService),
+		TransferLimitService:     NewTransferLimitService(repo.TransferLimitRepository),
+		FXService:                NewFXService(repo.FXRepository, newRateProvider(repo.FXRepository)),
+		TOTPService:              totpService,
+		ChallengeService:         NewChallengeService(repo.ChallengeRepository, repo.UserRepository, totpService, newStepUpThresholds()),
+		AuditService:             auditService,
+		OutboxService:            NewOutboxService(repo.OutboxRepository, NewMultiEventPublisher(newEventPublisher(redisClient), webhookService, streamService)),
+		WebhookService:           webhookService,
+		StreamService:            streamService,
+		StatementService:         NewStatementService(repo.AccountRepository, repo.TransactionRepository, ledgerService),
+	}
+}
+
+// newRateProvider serves the rates of the file named by FX_RATES_FILE when it is set, and the fx_rates table otherwise
+func newRateProvider(fxRepository repositories.FXRepository) RateProvider {
+	path := os.Getenv("FX_RATES_FILE")
+	if path == "" {
+		return NewDBRateProvider(fxRepository)
+	}
+
+	rateProvider, err := NewFileRateProvider(path)
+	if err != nil {
+		logger.Fatal("Failed to load exchange rates", zap.String("path", path), zap.Error(err))
+	}
+	return rateProvider
+}
+
+// newNotifier appends notifications to the file named by NOTIFIER_FILE when it is set, and logs them otherwise
+func newNotifier() Notifier {
+	if path := os.Getenv("NOTIFIER_FILE"); path != "" {
+		return NewFileNotifier(path)
+	}
+	return NewLogNotifier()
+}
+
+// newEventPublisher selects where outbox events are published with EVENT_PUBLISHER: "memory" (default), "file"
+// appending to EVENT_PUBLISHER_FILE, or "redis" appending to the stream named by EVENT_STREAM
+func newEventPublisher(redisClient types.CacheClient) EventPublisher {
+	switch publisher := os.Getenv("EVENT_PUBLISHER"); publisher {
+	case "", "memory":
+		return NewMemoryEventPublisher()
+	case "file":
+		path := os.Getenv("EVENT_PUBLISHER_FILE")
+		if path == "" {
+			logger.Fatal("EVENT_PUBLISHER_FILE is required for the file event publisher")
+		}
+		return NewFileEventPublisher(path)
+	case "redis":
+		streamClient, ok := sharedCacheClient(redisClient).(StreamClient)
+		if !ok {
+			logger.Fatal("The cache client does not support Redis streams")
+		}
+		stream := os.Getenv("EVENT_STREAM")
+		if stream == "" {
+			stream = configs.OUTBOX_STREAM_NAME
+		}
+		return NewRedisStreamPublisher(streamClient, stream, configs.OUTBOX_STREAM_MAX_SIZE)
+	default:
+		logger.Fatal("Unknown event publisher", zap.String("publisher", publisher))
+		return nil
+	}
+}
+
+// newStreamBroker selects the StreamBroker named by STREAM_BROKER: memory (the default) for a single instance, or
+// redis to fan stream messages out to the clients of every instance
+func newStreamBroker(redisClient types.CacheClient) StreamBroker {
+	switch broker := os.Getenv("STREAM_BROKER"); broker {
+	case "", "memory":
+		return NewMemoryStreamBroker()
+	case "redis":
+		pubSubClient, ok := sharedCacheClient(redisClient).(PubSubClient)
+		if !ok {
+			logger.Fatal("The cache client does not support Redis pub/sub")
+		}
+		return NewRedisStreamBroker(pubSubClient, configs.STREAM_CHANNEL)
+	default:
+		logger.Fatal("Unknown stream broker", zap.String("broker", broker))
+		return nil
+	}
+}
+
+// sharedCacheClient returns the cache shared by every instance, Redis behind the local copies of a two-tier cache
+func sharedCacheClient(redisClient types.CacheClient) types.CacheClient {
+	if tieredClient, ok := redisClient.(interface{ Shared() types.CacheClient }); ok {
+		return tieredClient.Shared()
+	}
+	return redisClient
+}
+
+// newStepUpThresholds parses STEP_UP_THRESHOLDS, e.g. "THB:50000,USD:1500", falling back to
+// configs.STEP_UP_THRESHOLDS when it is not set
+func newStepUpThresholds() map[string]types.Money {
+	raw := configs.STEP_UP_THRESHOLDS
+	if env := os.Getenv("STEP_UP_THRESHOLDS"); env != "" {
+		raw = map[string]string{}
+		for _, entry := range strings.Split(env, ",") {
+			currency, amount, _ := strings.Cut(strings.TrimSpace(entry), ":")
+			raw[currency] = amount
+		}
+	}
+
+	thresholds := make(map[string]types.Money, len(raw))
+	for currency, amount := range raw {
+		threshold, err := types.ParseMoney(amount, currency)
+		if err != nil {
+			logger.Fatal("Invalid step-up threshold", zap.String("currency", currency), zap.String("amount", amount), zap.Error(err))
+		}
+		thresholds[threshold.Currency] = threshold
 	}
+	return thresholds
 }
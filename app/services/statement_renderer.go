@@ -0,0 +1,227 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// statementRenderer writes a statement in one format as its lines come, end is given the closing balance
+type statementRenderer interface {
+	begin(statement *models.Statement) error
+	line(line *models.StatementLine) error
+	end(statement *models.Statement) error
+}
+
+func newStatementRenderer(w io.Writer, format models.StatementFormat) (statementRenderer, error) {
+	switch format {
+	case models.StatementCSV:
+		return &csvStatementRenderer{w: csv.NewWriter(w)}, nil
+	case models.StatementJSON:
+		return &jsonStatementRenderer{w: w}, nil
+	case models.StatementPDF:
+		return &pdfStatementRenderer{pdf: utils.NewPDFWriter(w)}, nil
+	default:
+		return nil, ErrStatementFormat
+	}
+}
+
+const (
+	statementDateFormat = "2006-01-02"
+	statementTimeFormat = "2006-01-02 15:04:05"
+)
+
+// csvStatementRenderer writes one row per transaction between an opening and a closing balance row. Amounts are
+// signed, debits negative, so that they add up to the balance.
+type csvStatementRenderer struct {
+	w *csv.Writer
+}
+
+func (r *csvStatementRenderer) begin(statement *models.Statement) error {
+	r.w.Write([]string{"date", "transaction_id", "type", "direction", "description", "amount", "balance", "currency"})
+	return r.w.Write([]string{statement.From.Format(statementTimeFormat), "", "opening_balance", "", "Opening balance", "",
+		statement.OpeningBalance.Decimal(), statement.Currency})
+}
+
+func (r *csvStatementRenderer) line(line *models.StatementLine) error {
+	return r.w.Write([]string{line.Date.Format(statementTimeFormat), line.TransactionID, line.TransactionType, string(line.Direction),
+		csvSafe(line.Description), signedAmount(line).Decimal(), line.Balance.Decimal(), line.Amount.Currency})
+}
+
+func (r *csvStatementRenderer) end(statement *models.Statement) error {
+	r.w.Write([]string{statement.End().Add(-1).Format(statementTimeFormat), "", "closing_balance", "", "Closing balance", "",
+		statement.ClosingBalance.Decimal(), statement.Currency})
+	r.w.Flush()
+	return r.w.Error()
+}
+
+// csvSafe keeps spreadsheets from evaluating a description chosen by someone else as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func signedAmount(line *models.StatementLine) types.Money {
+	if line.Direction == models.Debit {
+		return line.Amount.Neg()
+	}
+	return line.Amount
+}
+
+// jsonStatementRenderer writes one JSON object whose transactions array is written a line at a time
+type jsonStatementRenderer struct {
+	w     io.Writer
+	lines int
+}
+
+func (r *jsonStatementRenderer) begin(statement *models.Statement) error {
+	header, err := json.Marshal(struct {
+		AccountID      string      `json:"account_id"`
+		AccountNumber  string      `json:"account_number"`
+		Currency       string      `json:"currency"`
+		From           string      `json:"from"`
+		To             string      `json:"to"`
+		OpeningBalance types.Money `json:"opening_balance"`
+	}{
+		AccountID:      statement.AccountID,
+		AccountNumber:  statement.AccountNumber,
+		Currency:       statement.Currency,
+		From:           statement.From.Format(statementDateFormat),
+		To:             statement.To.Format(statementDateFormat),
+		OpeningBalance: statement.OpeningBalance,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Leave the object open for the transactions
+	_, err = fmt.Fprintf(r.w, `%s,"transactions":[`, header[:len(header)-1])
+	return err
+}
+
+func (r *jsonStatementRenderer) line(line *models.StatementLine) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	if r.lines > 0 {
+		data = append([]byte{','}, data...)
+	}
+	r.lines++
+	_, err = r.w.Write(data)
+	return err
+}
+
+func (r *jsonStatementRenderer) end(statement *models.Statement) error {
+	closingBalance, err := json.Marshal(statement.ClosingBalance)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.w, `],"closing_balance":%s}`+"\n", closingBalance)
+	return err
+}
+
+// Layout of the PDF statement, in points. Text is set in Courier so that columns line up by padding.
+const (
+	pdfStatementMargin           = 40
+	pdfStatementFontSize         = 8
+	pdfStatementTitleSize        = 14
+	pdfStatementLeading          = 11
+	pdfStatementFooterY          = 24
+	pdfStatementTop              = utils.PDFPageHeight - pdfStatementMargin
+	pdfStatementBottom           = pdfStatementMargin
+	pdfStatementDescriptionWidth = 40 // characters of the description column
+)
+
+// pdfStatementRenderer writes an A4 table of the transactions, repeating the column headers on every page
+type pdfStatementRenderer struct {
+	pdf  *utils.PDFWriter
+	page int
+	y    float64
+}
+
+func (r *pdfStatementRenderer) begin(statement *models.Statement) error {
+	if err := r.newPage(); err != nil {
+		return err
+	}
+
+	r.pdf.Text(utils.PDFCourierBold, pdfStatementTitleSize, pdfStatementMargin, r.y, "Account statement")
+	r.y -= 2 * pdfStatementLeading
+	r.text(utils.PDFCourier, fmt.Sprintf("%-16s %s", "Account number", statement.AccountNumber))
+	r.text(utils.PDFCourier, fmt.Sprintf("%-16s %s", "Account ID", statement.AccountID))
+	r.text(utils.PDFCourier, fmt.Sprintf("%-16s %s to %s", "Period", statement.From.Format(statementDateFormat), statement.To.Format(statementDateFormat)))
+	r.text(utils.PDFCourier, fmt.Sprintf("%-16s %s %s", "Opening balance", statement.OpeningBalance.Decimal(), statement.Currency))
+	r.y -= pdfStatementLeading
+	r.columnHeaders()
+	return nil
+}
+
+func (r *pdfStatementRenderer) line(line *models.StatementLine) error {
+	if err := r.makeRoom(1); err != nil {
+		return err
+	}
+
+	debit, credit := "", ""
+	if line.Direction == models.Debit {
+		debit = line.Amount.Decimal()
+	} else {
+		credit = line.Amount.Decimal()
+	}
+	r.text(utils.PDFCourier, pdfStatementRow(line.Date.Format("2006-01-02 15:04"), line.Description, debit, credit, line.Balance.Decimal()))
+	return nil
+}
+
+func (r *pdfStatementRenderer) end(statement *models.Statement) error {
+	if err := r.makeRoom(2); err != nil {
+		return err
+	}
+	r.y -= pdfStatementLeading
+	r.text(utils.PDFCourierBold, fmt.Sprintf("%-16s %s %s", "Closing balance", statement.ClosingBalance.Decimal(), statement.Currency))
+	return r.pdf.Close()
+}
+
+func (r *pdfStatementRenderer) newPage() error {
+	if err := r.pdf.NewPage(); err != nil {
+		return err
+	}
+	r.page++
+	r.y = pdfStatementTop
+	r.pdf.Text(utils.PDFCourier, pdfStatementFontSize, pdfStatementMargin, pdfStatementFooterY, fmt.Sprintf("Page %d", r.page))
+	return nil
+}
+
+// makeRoom starts a new page unless the lines fit on the current one
+func (r *pdfStatementRenderer) makeRoom(lines int) error {
+	if r.y-float64(lines-1)*pdfStatementLeading >= pdfStatementBottom {
+		return nil
+	}
+	if err := r.newPage(); err != nil {
+		return err
+	}
+	r.columnHeaders()
+	return nil
+}
+
+func (r *pdfStatementRenderer) columnHeaders() {
+	r.text(utils.PDFCourierBold, pdfStatementRow("Date", "Description", "Debit", "Credit", "Balance"))
+}
+
+func (r *pdfStatementRenderer) text(font utils.PDFFont, text string) {
+	r.pdf.Text(font, pdfStatementFontSize, pdfStatementMargin, r.y, text)
+	r.y -= pdfStatementLeading
+}
+
+// pdfStatementRow pads the cells into columns, amounts aligned right
+func pdfStatementRow(date, description, debit, credit, balance string) string {
+	if runes := []rune(description); len(runes) > pdfStatementDescriptionWidth {
+		description = string(runes[:pdfStatementDescriptionWidth-3]) + "..."
+	}
+	return fmt.Sprintf("%-16s %-*s %14s %14s %16s", date, pdfStatementDescriptionWidth, description, debit, credit, balance)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"errors"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Custom errors for account statements
+var (
+	ErrStatementFormat = errors.New("statement format must be csv, json or pdf")
+	ErrStatementPeriod = errors.New("statement period must end on or after its first day")
+)
+
+// StatementService defines the interface for account statements. A statement is opened first, so that a missing
+// account is reported before anything is written, then written out in a format.
+type StatementService interface {
+	OpenStatement(accountID string, from, to time.Time) (*models.Statement, error)
+	WriteStatement(w io.Writer, statement *models.Statement, format models.StatementFormat) error
+}
+
+// StatementServiceImpl implements StatementService
+type StatementServiceImpl struct {
+	accountRepository     repositories.AccountRepository
+	transactionRepository repositories.TransactionRepository
+	ledgerService         LedgerService
+}
+
+// NewStatementService creates a new instance of StatementService
+func NewStatementService(accountRepository repositories.AccountRepository, transactionRepository repositories.TransactionRepository, ledgerService LedgerService) StatementService {
+	return &StatementServiceImpl{
+		accountRepository:     accountRepository,
+		transactionRepository: transactionRepository,
+		ledgerService:         ledgerService,
+	}
+}
+
+// OpenStatement returns the statement of the account from the day of from to the day of to, both included, with
+// its opening balance rebuilt from the ledger postings before the first day. Days run in configs.STATEMENT_LOCATION.
+func (s *StatementServiceImpl) OpenStatement(accountID string, from, to time.Time) (*models.Statement, error) {
+	statement := &models.Statement{
+		AccountID: accountID,
+		From:      startOfDay(from),
+		To:        startOfDay(to),
+	}
+	if statement.To.Before(statement.From) {
+		return nil, ErrStatementPeriod
+	}
+
+	account, err := s.accountRepository.GetAccountByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	statement.AccountNumber = account.AccountNumber
+	statement.Currency = account.Currency
+
+	// The ledger counts postings up to and including the given time, the first day starts the period
+	statement.OpeningBalance, err = s.ledgerService.GetBalanceAt(accountID, statement.From.Add(-time.Nanosecond))
+	if errors.Is(err, sql.ErrNoRows) {
+		// Never posted to, the account has no ledger history yet
+		statement.OpeningBalance, err = types.NewMoney(0, account.Currency), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// WriteStatement writes the transactions of the statement with their running balance and its closing balance.
+// Transactions are read configs.STATEMENT_PAGE_SIZE at a time and written as they are read, the writer is
+// typically the response body.
+func (s *StatementServiceImpl) WriteStatement(w io.Writer, statement *models.Statement, format models.StatementFormat) error {
+	renderer, err := newStatementRenderer(w, format)
+	if err != nil {
+		return err
+	}
+	if err := renderer.begin(statement); err != nil {
+		return err
+	}
+
+	balance := statement.OpeningBalance
+	var after *models.TransactionCursor
+	for {
+		transactions, err := s.transactionRepository.GetByAccountIDInPeriod(statement.AccountID, statement.From, statement.End(), after, configs.STATEMENT_PAGE_SIZE)
+		if err != nil {
+			logger.Error("Failed to get statement transactions", zap.String("account_id", statement.AccountID), zap.Error(err))
+			return err
+		}
+
+		for _, transaction := range transactions {
+			if transaction.Direction == models.Debit {
+				balance, err = balance.Sub(transaction.Amount)
+			} else {
+				balance, err = balance.Add(transaction.Amount)
+			}
+			if err != nil {
+				return err
+			}
+
+			line := &models.StatementLine{
+				TransactionID:   transaction.TransactionID,
+				Date:            transaction.CreatedAt.In(configs.STATEMENT_LOCATION),
+				TransactionType: transaction.TransactionType,
+				Direction:       transaction.Direction,
+				Description:     transaction.Name,
+				Amount:          transaction.Amount,
+				Balance:         balance,
+			}
+			if err := renderer.line(line); err != nil {
+				return err
+			}
+		}
+
+		if len(transactions) < configs.STATEMENT_PAGE_SIZE {
+			break
+		}
+		last := transactions[len(transactions)-1]
+		after = &models.TransactionCursor{CreatedAt: last.CreatedAt, TransactionID: last.TransactionID}
+	}
+
+	statement.ClosingBalance = balance
+	return renderer.end(statement)
+}
+
+// startOfDay returns the midnight starting the day of t in configs.STATEMENT_LOCATION
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.In(configs.STATEMENT_LOCATION).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, configs.STATEMENT_LOCATION)
+}
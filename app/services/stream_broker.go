@@ -0,0 +1,293 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrStreamClosed is returned when subscribing to a broker that was closed for shutdown
+var ErrStreamClosed = errors.New("stream is closed")
+
+// StreamBroker keeps the recent stream messages of each user and fans new ones out to the subscribers of the user
+type StreamBroker interface {
+	// Publish assigns the message its ID, adds it to the history of the user and hands it to the subscribers
+	Publish(ctx context.Context, userID string, message *models.StreamMessage) error
+	// History returns the kept messages of the user from the message fromID on, oldest first
+	History(ctx context.Context, userID, fromID string) ([]*models.StreamMessage, error)
+	// Subscribe returns the messages published to the user from now on until unsubscribe is called. The channel is
+	// closed when the subscriber falls too far behind or the broker is closed.
+	Subscribe(userID string) (messages <-chan *models.StreamMessage, unsubscribe func(), err error)
+	// Close ends every subscription, long-lived connections then finish before the server shuts down
+	Close()
+}
+
+// streamHub hands messages to the subscribers connected to this instance
+type streamHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan *models.StreamMessage]struct{}
+	closed      bool
+}
+
+func newStreamHub() *streamHub {
+	return &streamHub{subscribers: map[string]map[chan *models.StreamMessage]struct{}{}}
+}
+
+func (h *streamHub) subscribe(userID string) (<-chan *models.StreamMessage, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, nil, ErrStreamClosed
+	}
+
+	messages := make(chan *models.StreamMessage, configs.STREAM_BUFFER_SIZE)
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[chan *models.StreamMessage]struct{}{}
+	}
+	h.subscribers[userID][messages] = struct{}{}
+
+	return messages, func() { h.remove(userID, messages) }, nil
+}
+
+// remove closes the channel of a subscriber unless it was already removed
+func (h *streamHub) remove(userID string, messages chan *models.StreamMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[userID][messages]; !ok {
+		return
+	}
+	delete(h.subscribers[userID], messages)
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+	close(messages)
+}
+
+// dispatch hands a message to the subscribers of the user without blocking, a subscriber whose buffer is full is
+// dropped so that it reconnects and resumes from its last event id instead of silently missing messages
+func (h *streamHub) dispatch(userID string, message *models.StreamMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for messages := range h.subscribers[userID] {
+		select {
+		case messages <- message:
+		default:
+			logger.Warn("Stream subscriber fell behind", zap.String("user_id", userID))
+			delete(h.subscribers[userID], messages)
+			close(messages)
+		}
+	}
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+}
+
+func (h *streamHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for userID, subscribers := range h.subscribers {
+		for messages := range subscribers {
+			close(messages)
+		}
+		delete(h.subscribers, userID)
+	}
+}
+
+// MemoryStreamBroker keeps stream messages in memory, subscribers only receive the messages published by this
+// instance. It suits a single instance and tests, use RedisStreamBroker to fan out across instances.
+type MemoryStreamBroker struct {
+	*streamHub
+	mu       sync.Mutex
+	history  map[string][]*models.StreamMessage
+	lastTime int64
+	sequence int64
+}
+
+// NewMemoryStreamBroker creates a StreamBroker keeping messages in memory
+func NewMemoryStreamBroker() *MemoryStreamBroker {
+	return &MemoryStreamBroker{streamHub: newStreamHub(), history: map[string][]*models.StreamMessage{}}
+}
+
+// Publish keeps the message in the history of the user and hands it to its subscribers
+func (b *MemoryStreamBroker) Publish(_ context.Context, userID string, message *models.StreamMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// IDs increase like those of Redis streams: the time in ms, and a sequence within the same ms
+	now := time.Now().UnixMilli()
+	if now > b.lastTime {
+		b.lastTime, b.sequence = now, 0
+	} else {
+		b.sequence++
+	}
+	message.ID = fmt.Sprintf("%d-%d", b.lastTime, b.sequence)
+
+	history := append(b.history[userID], message)
+	if len(history) > configs.STREAM_HISTORY_SIZE {
+		history = append([]*models.StreamMessage(nil), history[len(history)-configs.STREAM_HISTORY_SIZE:]...)
+	}
+	b.history[userID] = history
+
+	b.dispatch(userID, message)
+	return nil
+}
+
+// History returns the kept messages of the user from the message fromID on
+func (b *MemoryStreamBroker) History(_ context.Context, userID, fromID string) ([]*models.StreamMessage, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	messages := []*models.StreamMessage{}
+	for _, message := range b.history[userID] {
+		if models.CompareStreamIDs(message.ID, fromID) >= 0 {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// Subscribe returns the messages published to the user from now on
+func (b *MemoryStreamBroker) Subscribe(userID string) (<-chan *models.StreamMessage, func(), error) {
+	return b.subscribe(userID)
+}
+
+// Close ends every subscription
+func (b *MemoryStreamBroker) Close() {
+	b.close()
+}
+
+// PubSubClient keeps streams and fans messages out over a pub/sub channel, implemented by the Redis client of
+// platform/cache
+type PubSubClient interface {
+	StreamClient
+	ReadStream(ctx context.Context, stream, fromID string, count int64) ([]types.StreamEntry, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	PublishMessage(ctx context.Context, channel, message string) error
+	SubscribeChannel(ctx context.Context, channel string) (<-chan string, error)
+}
+
+// RedisStreamBroker keeps the history of each user in a Redis stream and fans messages out to every instance over
+// a pub/sub channel, so a client receives its messages whichever instance it is connected to
+type RedisStreamBroker struct {
+	*streamHub
+	client  PubSubClient
+	channel string
+
+	mu       sync.Mutex
+	started  bool
+	stopFeed context.CancelFunc
+}
+
+// NewRedisStreamBroker creates a StreamBroker on top of Redis, fanning messages out over the channel
+func NewRedisStreamBroker(client PubSubClient, channel string) *RedisStreamBroker {
+	return &RedisStreamBroker{streamHub: newStreamHub(), client: client, channel: channel}
+}
+
+// redisStreamKey is the Redis stream keeping the history of a user
+func redisStreamKey(userID string) string {
+	return "stream:" + userID
+}
+
+// fanOutMessage is a stream message sent over the pub/sub channel
+type fanOutMessage struct {
+	UserID  string                `json:"user_id"`
+	Message *models.StreamMessage `json:"message"`
+}
+
+// Publish appends the message to the stream of the user and sends it over the channel
+func (b *RedisStreamBroker) Publish(ctx context.Context, userID string, message *models.StreamMessage) error {
+	key := redisStreamKey(userID)
+	id, err := b.client.AddToStream(ctx, key, configs.STREAM_HISTORY_SIZE, map[string]interface{}{
+		"event": string(message.Event),
+		"data":  string(message.Data),
+	})
+	if err != nil {
+		return err
+	}
+	message.ID = id
+
+	// The history of an idle user disappears, a client resuming later reloads instead
+	if err := b.client.Expire(ctx, key, configs.STREAM_HISTORY_TTL); err != nil {
+		return err
+	}
+
+	fanOut, err := json.Marshal(fanOutMessage{UserID: userID, Message: message})
+	if err != nil {
+		return err
+	}
+	return b.client.PublishMessage(ctx, b.channel, string(fanOut))
+}
+
+// History returns the messages kept in the stream of the user from the message fromID on
+func (b *RedisStreamBroker) History(ctx context.Context, userID, fromID string) ([]*models.StreamMessage, error) {
+	entries, err := b.client.ReadStream(ctx, redisStreamKey(userID), fromID, configs.STREAM_HISTORY_SIZE+1)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*models.StreamMessage, 0, len(entries))
+	for _, entry := range entries {
+		event, _ := entry.Values["event"].(string)
+		data, _ := entry.Values["data"].(string)
+		messages = append(messages, &models.StreamMessage{ID: entry.ID, Event: models.StreamEvent(event), Data: json.RawMessage(data)})
+	}
+	return messages, nil
+}
+
+// Subscribe returns the messages published to the user from now on by any instance. The first subscription
+// subscribes this instance to the channel.
+func (b *RedisStreamBroker) Subscribe(userID string) (<-chan *models.StreamMessage, func(), error) {
+	if err := b.startFeed(); err != nil {
+		return nil, nil, err
+	}
+	return b.subscribe(userID)
+}
+
+// startFeed subscribes to the channel once and dispatches its messages to the local subscribers
+func (b *RedisStreamBroker) startFeed() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	feed, err := b.client.SubscribeChannel(ctx, b.channel)
+	if err != nil {
+		cancel()
+		return err
+	}
+	b.started, b.stopFeed = true, cancel
+
+	go func() {
+		for raw := range feed {
+			var fanOut fanOutMessage
+			if err := json.Unmarshal([]byte(raw), &fanOut); err != nil || fanOut.Message == nil {
+				logger.Error("Failed to decode stream message", zap.Error(err))
+				continue
+			}
+			b.dispatch(fanOut.UserID, fanOut.Message)
+		}
+	}()
+	return nil
+}
+
+// Close ends every subscription and unsubscribes from the channel
+func (b *RedisStreamBroker) Close() {
+	b.close()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopFeed != nil {
+		b.stopFeed()
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/pkg/configs"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// StreamService defines the interface for the real-time stream of balance updates and new transactions. It is an
+// EventPublisher: the outbox relay hands it the committed events, which it turns into messages for the users they
+// concern.
+type StreamService interface {
+	EventPublisher
+
+	// Subscribe streams the messages of the user, those after lastEventID are replayed first. The channel is closed
+	// when the subscription is closed, the client falls behind or the service is closed, the client then
+	// reconnects with the ID of the last message it received. closeSubscription may be called more than once.
+	Subscribe(ctx context.Context, userID, lastEventID string) (messages <-chan *models.StreamMessage, closeSubscription func(), err error)
+	// Close ends every subscription, it is called on shutdown
+	Close()
+}
+
+// StreamServiceImpl implements StreamService
+type StreamServiceImpl struct {
+	broker StreamBroker
+}
+
+// NewStreamService creates a new stream service on top of the broker
+func NewStreamService(broker StreamBroker) StreamService {
+	return &StreamServiceImpl{
+		broker: broker,
+	}
+}
+
+// streamDelivery is a message for a user
+type streamDelivery struct {
+	userID string
+	event  models.StreamEvent
+	data   interface{}
+}
+
+// Publish turns an event into the messages of the users it concerns: the transactions it posted, then the new
+// balances. A message may be pushed twice when the relay publishes the event again, clients deduplicate
+// transactions by their transaction_id.
+func (s *StreamServiceImpl) Publish(ctx context.Context, event *models.DomainEvent) error {
+	deliveries, err := streamDeliveries(event)
+	if err != nil {
+		// A payload that cannot be read never will be, holding back the events of the account would not help
+		logger.Error("Failed to read event payload for the stream", zap.String("event_id", event.EventID), zap.Error(err))
+		return nil
+	}
+
+	for _, delivery := range deliveries {
+		data, err := json.Marshal(delivery.data)
+		if err != nil {
+			return err
+		}
+		if err := s.broker.Publish(ctx, delivery.userID, &models.StreamMessage{Event: delivery.event, Data: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func streamDeliveries(event *models.DomainEvent) ([]streamDelivery, error) {
+	switch event.EventType {
+	case models.EventAccountCreated:
+		var payload models.AccountCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return []streamDelivery{
+			{payload.UserID, models.StreamBalance, models.BalanceUpdate{AccountID: payload.AccountID, Balance: payload.Balance, UpdatedAt: event.OccurredAt}},
+		}, nil
+
+	case models.EventFundsDeposited, models.EventFundsWithdrawn:
+		var payload models.FundsMovedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, err
+		}
+		transactionType, direction := models.Deposit, models.Credit
+		if event.EventType == models.EventFundsWithdrawn {
+			transactionType, direction = models.Withdrawal, models.Debit
+		}
+		return []streamDelivery{
+			{payload.UserID, models.StreamTransaction, models.TransactionNotice{
+				TransactionID:   payload.TransactionID,
+				AccountID:       payload.AccountID,
+				TransactionType: transactionType,
+				Direction:       direction,
+				Amount:          payload.Amount,
+				OccurredAt:      event.OccurredAt,
+			}},
+			{payload.UserID, models.StreamBalance, models.BalanceUpdate{AccountID: payload.AccountID, Balance: payload.Balance, UpdatedAt: event.OccurredAt}},
+		}, nil
+
+	case models.EventTransferCompleted:
+		var payload models.TransferCompletedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return []streamDelivery{
+			{payload.FromUserID, models.StreamTransaction, models.TransactionNotice{
+				TransactionID:         payload.DebitTransactionID,
+				AccountID:             payload.FromAccountID,
+				TransactionType:       models.Transfer,
+				Direction:             models.Debit,
+				Amount:                payload.Amount,
+				CounterpartyAccountID: payload.ToAccountID,
+				OccurredAt:            event.OccurredAt,
+			}},
+			{payload.FromUserID, models.StreamBalance, models.BalanceUpdate{AccountID: payload.FromAccountID, Balance: payload.SourceBalance, UpdatedAt: event.OccurredAt}},
+			{payload.ToUserID, models.StreamTransaction, models.TransactionNotice{
+				TransactionID:         payload.CreditTransactionID,
+				AccountID:             payload.ToAccountID,
+				TransactionType:       models.Transfer,
+				Direction:             models.Credit,
+				Amount:                payload.CreditedAmount,
+				CounterpartyAccountID: payload.FromAccountID,
+				OccurredAt:            event.OccurredAt,
+			}},
+			{payload.ToUserID, models.StreamBalance, models.BalanceUpdate{AccountID: payload.ToAccountID, Balance: payload.DestinationBalance, UpdatedAt: event.OccurredAt}},
+		}, nil
+
+	case models.EventTransactionReversed:
+		var payload models.TransactionReversedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return []streamDelivery{
+			{payload.UserID, models.StreamTransaction, models.TransactionNotice{
+				TransactionID:   payload.TransactionID,
+				AccountID:       payload.AccountID,
+				TransactionType: models.Reversal,
+				Direction:       payload.Direction,
+				Amount:          payload.Amount,
+				OccurredAt:      event.OccurredAt,
+			}},
+			{payload.UserID, models.StreamBalance, models.BalanceUpdate{AccountID: payload.AccountID, Balance: payload.Balance, UpdatedAt: event.OccurredAt}},
+		}, nil
+
+	default:
+		// Card events change neither balances nor transactions
+		return nil, nil
+	}
+}
+
+// Subscribe streams the messages of the user. The live subscription starts before the history is read so that no
+// message falls in between, messages received both ways are pushed once.
+func (s *StreamServiceImpl) Subscribe(ctx context.Context, userID, lastEventID string) (<-chan *models.StreamMessage, func(), error) {
+	live, unsubscribe, err := s.broker.Subscribe(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replay, err := s.replay(ctx, userID, lastEventID)
+	if err != nil {
+		unsubscribe()
+		return nil, nil, err
+	}
+
+	messages := make(chan *models.StreamMessage, configs.STREAM_BUFFER_SIZE)
+	done := make(chan struct{})
+	go func() {
+		defer close(messages)
+
+		lastID := lastEventID
+		for _, message := range replay {
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+			if message.ID != "" {
+				lastID = message.ID
+			}
+		}
+
+		for {
+			select {
+			case message, ok := <-live:
+				if !ok {
+					return
+				}
+				if lastID != "" && models.CompareStreamIDs(message.ID, lastID) <= 0 {
+					continue // already replayed
+				}
+				select {
+				case messages <- message:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var closeOnce sync.Once
+	closeSubscription := func() {
+		closeOnce.Do(func() {
+			close(done)
+			unsubscribe()
+		})
+	}
+	return messages, closeSubscription, nil
+}
+
+// replay returns the messages after lastEventID. When that message is no longer kept some messages may be lost, a
+// StreamReset message then makes the client reload its accounts and resume from the latest message.
+func (s *StreamServiceImpl) replay(ctx context.Context, userID, lastEventID string) ([]*models.StreamMessage, error) {
+	if lastEventID == "" {
+		return nil, nil
+	}
+
+	var history []*models.StreamMessage
+	if _, _, ok := models.ParseStreamID(lastEventID); ok {
+		var err error
+		history, err = s.broker.History(ctx, userID, lastEventID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(history) > 0 && history[0].ID == lastEventID {
+		return history[1:], nil
+	}
+
+	reset := &models.StreamMessage{Event: models.StreamReset, Data: json.RawMessage(`{}`)}
+	if len(history) > 0 {
+		reset.ID = history[len(history)-1].ID
+	}
+	return []*models.StreamMessage{reset}, nil
+}
+
+// Close ends every subscription
+func (s *StreamServiceImpl) Close() {
+	s.broker.Close()
+}
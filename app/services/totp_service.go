@@ -0,0 +1,122 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Custom errors for TOTP authenticators
+var (
+	ErrTOTPAlreadyEnabled = errors.New("an authenticator app is already enabled")
+	ErrTOTPNotEnrolled    = errors.New("set up an authenticator app first")
+	ErrTOTPNotEnabled     = errors.New("no authenticator app is enabled")
+	ErrInvalidTOTP        = errors.New("invalid or already used authenticator code")
+)
+
+// TOTPService defines the interface for TOTP authenticator operations
+type TOTPService interface {
+	Enroll(userID string) (*models.TOTPEnrollment, error)
+	Enable(userID, code string) error
+	Verify(userID, code string) error
+}
+
+// TOTPServiceImpl implements TOTPService
+type TOTPServiceImpl struct {
+	totpRepository repositories.TOTPRepository
+}
+
+// NewTOTPService creates a new instance of TOTPService
+func NewTOTPService(totpRepository repositories.TOTPRepository) TOTPService {
+	return &TOTPServiceImpl{
+		totpRepository: totpRepository,
+	}
+}
+
+// Enroll generates a new secret for the user. The secret confirms nothing until Enable receives a code of it, an
+// enabled secret is never replaced so a stolen access token cannot swap the authenticator.
+func (s *TOTPServiceImpl) Enroll(userID string) (*models.TOTPEnrollment, error) {
+	existing, err := s.totpRepository.GetByUserID(userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if existing != nil && existing.EnabledAt != nil {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	totp := &models.UserTOTP{UserID: userID, Secret: secret, CreatedAt: time.Now()}
+	if err := s.totpRepository.Save(totp); err != nil {
+		logger.Error("Failed to save TOTP secret", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	return &models.TOTPEnrollment{
+		Secret: secret,
+		URI:    utils.TOTPURI(configs.TOTP_ISSUER, userID, secret),
+	}, nil
+}
+
+// Enable turns on the secret of the user once the authenticator app shows a valid code
+func (s *TOTPServiceImpl) Enable(userID, code string) error {
+	totp, err := s.totpRepository.GetByUserID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTOTPNotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	if totp.EnabledAt != nil {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	if err := s.useCode(totp, code); err != nil {
+		return err
+	}
+
+	return s.totpRepository.Enable(userID, time.Now())
+}
+
+// Verify checks a code of the enabled authenticator of the user, every code is accepted once
+func (s *TOTPServiceImpl) Verify(userID, code string) error {
+	totp, err := s.totpRepository.GetByUserID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTOTPNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if totp.EnabledAt == nil {
+		return ErrTOTPNotEnabled
+	}
+
+	return s.useCode(totp, code)
+}
+
+// useCode accepts a code within configs.TOTP_SKEW_STEPS of now that is newer than the last accepted one
+func (s *TOTPServiceImpl) useCode(totp *models.UserTOTP, code string) error {
+	step, ok := utils.VerifyTOTP(totp.Secret, code, time.Now(), configs.TOTP_SKEW_STEPS)
+	if !ok {
+		return ErrInvalidTOTP
+	}
+
+	used, err := s.totpRepository.MarkUsedStep(totp.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTOTP
+	}
+
+	return nil
+}
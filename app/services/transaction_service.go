@@ -6,9 +6,14 @@ import (
 	"backend-developer-assignment/pkg/configs"
 	"backend-developer-assignment/pkg/types"
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,26 +23,44 @@ import (
 // Cache expiration time constants
 const (
 	TransactionCacheDuration     = 10 * time.Minute
-	TransactionListCacheDuration = 5 * time.Minute
+	TransactionListCacheDuration = 5 * time.Minute // well within types.NamespaceVersionTTL
+)
+
+// Custom errors for transaction reversals
+var (
+	ErrTransactionNotFound        = errors.New("transaction not found")
+	ErrTransactionAlreadyReversed = errors.New("transaction has already been fully reversed")
+	ErrReversalExceedsAmount      = errors.New("reversal amount exceeds the amount left to reverse")
+	ErrTransactionNotReversible   = errors.New("transaction cannot be reversed")
+	ErrTransferNotReturnable      = errors.New("only a transfer received can be returned")
+	ErrInvalidTransactionCursor   = errors.New("cursor is invalid or was issued for another sort")
 )
 
 // TransactionService interface defines the methods for transaction business logic
 type TransactionService interface {
 	GetTransactionByID(id string) (*models.Transaction, error)
 	GetTransactionsByUserID(userID string, page int) ([]*models.Transaction, int, error)
+	SearchTransactions(filter models.TransactionFilter, cursor string) ([]*models.Transaction, string, error)
+	GetAccountTransactions(filter models.TransactionFilter, cursor string) ([]*models.Transaction, string, error)
 	CreateTransaction(transaction *models.Transaction) error
+	// ReverseTransaction reverses any transaction, it is a staff operation
+	ReverseTransaction(transactionID string, amount *types.Money) (*models.ReversalResult, error)
+	// ReturnTransfer sends a transfer the user received back to its sender
+	ReturnTransfer(userID, transactionID string, amount *types.Money) (*models.ReversalResult, error)
 }
 
 // TransactionServiceImpl contains business logic related to transactions.
 type TransactionServiceImpl struct {
 	TransactionRepository repositories.TransactionRepository
+	txProvider            repositories.TxProvider
 	redisClient           types.CacheClient
 }
 
 // NewTransactionService creates a new TransactionService.
-func NewTransactionService(transactionRepository repositories.TransactionRepository, redisClient types.CacheClient) TransactionService {
+func NewTransactionService(transactionRepository repositories.TransactionRepository, txProvider repositories.TxProvider, redisClient types.CacheClient) TransactionService {
 	return &TransactionServiceImpl{
 		TransactionRepository: transactionRepository,
+		txProvider:            txProvider,
 		redisClient:           redisClient,
 	}
 }
@@ -76,21 +99,27 @@ func (s *TransactionServiceImpl) GetTransactionByID(id string) (*models.Transact
 // GetTransactionsByUserID retrieves all transactions for a user.
 func (s *TransactionServiceImpl) GetTransactionsByUserID(userID string, page int) ([]*models.Transaction, int, error) {
 	ctx := context.Background()
-	cacheKey := fmt.Sprintf("transactions:user:%s:page:%d", userID, page)
-	countCacheKey := fmt.Sprintf("transactions:user:%s:count", userID)
+
+	// The pages of a user are cached under the version of the user's listings, a new transaction moves it on
+	namespace := userTransactionsNamespace(userID)
+	version, versionErr := s.redisClient.NamespaceVersion(ctx, namespace)
+	cacheKey := types.NamespacedKey(namespace, version, fmt.Sprintf("page:%d", page))
+	countCacheKey := types.NamespacedKey(namespace, version, "count")
 
 	// Try to get from cache first
-	cachedData, err := s.redisClient.Get(ctx, cacheKey)
-	cachedCount, countErr := s.redisClient.Get(ctx, countCacheKey)
+	if versionErr == nil {
+		cachedData, err := s.redisClient.Get(ctx, cacheKey)
+		cachedCount, countErr := s.redisClient.Get(ctx, countCacheKey)
 
-	if err == nil && countErr == nil {
-		// Cache hit - deserialize and return
-		var transactions []*models.Transaction
-		var count int
+		if err == nil && countErr == nil {
+			// Cache hit - deserialize and return
+			var transactions []*models.Transaction
+			var count int
 
-		if err := json.Unmarshal([]byte(cachedData), &transactions); err == nil {
-			if _, err := fmt.Sscanf(cachedCount, "%d", &count); err == nil {
-				return transactions, count, nil
+			if err := json.Unmarshal([]byte(cachedData), &transactions); err == nil {
+				if _, err := fmt.Sscanf(cachedCount, "%d", &count); err == nil {
+					return transactions, count, nil
+				}
 			}
 		}
 	}
@@ -104,8 +133,8 @@ func (s *TransactionServiceImpl) GetTransactionsByUserID(userID string, page int
 		return nil, 0, err
 	}
 
-	// Store in cache for future requests
-	if transactionsData, err := json.Marshal(transactions); err == nil {
+	// Store in cache for future requests, without a version the pages could outlive the next transaction
+	if transactionsData, err := json.Marshal(transactions); err == nil && versionErr == nil {
 		if err := s.redisClient.Set(ctx, cacheKey, transactionsData, TransactionListCacheDuration); err != nil {
 			logger.Warn("Failed to set cache for transactions", zap.String("user_id", userID), zap.Error(err))
 		}
@@ -117,12 +146,165 @@ func (s *TransactionServiceImpl) GetTransactionsByUserID(userID string, page int
 	return transactions, count, nil
 }
 
+// SearchTransactions retrieves a page of the transactions of filter.UserID matching the filter and the cursor of
+// the next page, empty on the last one. Results are not cached, filters make too many combinations for a cache entry
+// to be hit again.
+func (s *TransactionServiceImpl) SearchTransactions(filter models.TransactionFilter, cursor string) ([]*models.Transaction, string, error) {
+	return searchTransactionPage(filter, cursor, s.TransactionRepository.Search)
+}
+
+// GetAccountTransactions retrieves a page of the transactions of filter.AccountID like SearchTransactions. Pages are
+// cached under the version of the account's listings, which every new transaction on the account moves on.
+func (s *TransactionServiceImpl) GetAccountTransactions(filter models.TransactionFilter, cursor string) ([]*models.Transaction, string, error) {
+	type transactionPage struct {
+		Transactions []*models.Transaction `json:"transactions"`
+		NextCursor   string                `json:"next_cursor"`
+	}
+
+	ctx := context.Background()
+	cacheKey := ""
+	namespace := accountTransactionsNamespace(filter.AccountID)
+	if version, err := s.redisClient.NamespaceVersion(ctx, namespace); err == nil {
+		cacheKey = types.NamespacedKey(namespace, version, transactionQueryDigest(filter, cursor))
+		if cachedData, err := s.redisClient.Get(ctx, cacheKey); err == nil {
+			var page transactionPage
+			if err := json.Unmarshal([]byte(cachedData), &page); err == nil {
+				return page.Transactions, page.NextCursor, nil
+			}
+		}
+	}
+
+	transactions, nextCursor, err := searchTransactionPage(filter, cursor, s.TransactionRepository.SearchByAccountID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Without a version the page could outlive the next transaction, it is not cached
+	if cacheKey != "" {
+		if pageData, err := json.Marshal(transactionPage{Transactions: transactions, NextCursor: nextCursor}); err == nil {
+			if err := s.redisClient.Set(ctx, cacheKey, pageData, TransactionListCacheDuration); err != nil {
+				logger.Warn("Failed to set cache for account transactions", zap.String("account_id", filter.AccountID), zap.Error(err))
+			}
+		}
+	}
+
+	return transactions, nextCursor, nil
+}
+
+// searchTransactionPage reads a page of the listing with search, one transaction more than the page to tell
+// whether there is a next one. An empty cursor starts from the first page. The page size defaults to
+// configs.DEFAULT_PAGE_SIZE and is capped at configs.MAX_PAGE_SIZE.
+func searchTransactionPage(filter models.TransactionFilter, cursor string, search func(models.TransactionFilter) ([]*models.Transaction, error)) ([]*models.Transaction, string, error) {
+	if filter.Sort == "" {
+		filter.Sort = models.SortNewest
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = configs.DEFAULT_PAGE_SIZE
+	}
+	if filter.Limit > configs.MAX_PAGE_SIZE {
+		filter.Limit = configs.MAX_PAGE_SIZE
+	}
+	if cursor != "" {
+		after, err := decodeTransactionCursor(cursor, filter.Sort)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.After = after
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+	transactions, err := search(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(transactions) <= limit {
+		return transactions, "", nil
+	}
+
+	transactions = transactions[:limit]
+	last := transactions[limit-1]
+	return transactions, encodeTransactionCursor(&models.TransactionCursor{CreatedAt: last.CreatedAt, TransactionID: last.TransactionID}, filter.Sort), nil
+}
+
+// userTransactionsNamespace holds the cached pages of the transactions of a user
+func userTransactionsNamespace(userID string) string {
+	return fmt.Sprintf("transactions:user:%s", userID)
+}
+
+// accountTransactionsNamespace holds the cached pages of the transactions of an account
+func accountTransactionsNamespace(accountID string) string {
+	return fmt.Sprintf("transactions:account:%s", accountID)
+}
+
+// transactionQueryDigest names a page of a filtered listing by its filter and cursor
+func transactionQueryDigest(filter models.TransactionFilter, cursor string) string {
+	query, _ := json.Marshal(struct {
+		Filter models.TransactionFilter
+		Cursor string
+	}{filter, cursor})
+	digest := sha256.Sum256(query)
+	return hex.EncodeToString(digest[:16])
+}
+
+// invalidateTransactionLists drops the cached listings a new or changed transaction of the user on the account
+// appears in. Every write path creating transactions calls it once the database transaction committed.
+func invalidateTransactionLists(redisClient types.CacheClient, userID, accountID string) {
+	ctx := context.Background()
+	namespaces := []string{}
+	if userID != "" {
+		namespaces = append(namespaces, userTransactionsNamespace(userID))
+	}
+	if accountID != "" {
+		namespaces = append(namespaces, accountTransactionsNamespace(accountID))
+	}
+
+	for _, namespace := range namespaces {
+		if err := redisClient.InvalidateNamespace(ctx, namespace); err != nil {
+			logger.Warn("Failed to invalidate cached transaction lists", zap.String("namespace", namespace), zap.Error(err))
+		}
+	}
+}
+
+// transactionCursorToken is what a cursor handed to clients holds, the sort keeps it from being used in another
+type transactionCursorToken struct {
+	Sort          models.TransactionSort `json:"s"`
+	CreatedAt     time.Time              `json:"c"`
+	TransactionID string                 `json:"t"`
+}
+
+// encodeTransactionCursor makes an opaque, URL safe cursor of the position in a listing in the given sort
+func encodeTransactionCursor(cursor *models.TransactionCursor, sort models.TransactionSort) string {
+	data, _ := json.Marshal(transactionCursorToken{Sort: sort, CreatedAt: cursor.CreatedAt, TransactionID: cursor.TransactionID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTransactionCursor(cursor string, sort models.TransactionSort) (*models.TransactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidTransactionCursor
+	}
+
+	var token transactionCursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Sort != sort || token.TransactionID == "" {
+		return nil, ErrInvalidTransactionCursor
+	}
+
+	return &models.TransactionCursor{CreatedAt: token.CreatedAt, TransactionID: token.TransactionID}, nil
+}
+
 // CreateTransaction creates a new transaction.
 func (s *TransactionServiceImpl) CreateTransaction(transaction *models.Transaction) error {
 	// Generate a new UUID if not provided
 	if transaction.TransactionID == "" {
 		transaction.TransactionID = uuid.New().String()
 	}
+	if transaction.Direction == "" {
+		transaction.Direction = models.Credit
+		if transaction.TransactionType == string(models.Withdrawal) {
+			transaction.Direction = models.Debit
+		}
+	}
 
 	// Create transaction in database
 	err := s.TransactionRepository.Create(transaction)
@@ -132,8 +314,7 @@ func (s *TransactionServiceImpl) CreateTransaction(transaction *models.Transacti
 
 	// Invalidate user transactions cache
 	ctx := context.Background()
-	userTransactionsPattern := fmt.Sprintf("transactions:user:%s:*", transaction.UserID)
-	s.invalidateCache(ctx, userTransactionsPattern)
+	invalidateTransactionLists(s.redisClient, transaction.UserID, transaction.AccountID)
 
 	// Cache the new transaction
 	cacheKey := fmt.Sprintf("transaction:%s", transaction.TransactionID)
@@ -146,9 +327,379 @@ func (s *TransactionServiceImpl) CreateTransaction(transaction *models.Transacti
 	return nil
 }
 
-// invalidateCache invalidates cache entries matching the given pattern
-func (s *TransactionServiceImpl) invalidateCache(ctx context.Context, pattern string) {
-	if err := s.redisClient.Delete(ctx, strings.TrimSuffix(pattern, "*")); err != nil {
-		logger.Warn("Failed to invalidate cache", zap.String("pattern", pattern), zap.Error(err))
+// invalidateCache drops a cached entry
+func (s *TransactionServiceImpl) invalidateCache(ctx context.Context, key string) {
+	if err := s.redisClient.Delete(ctx, key); err != nil {
+		logger.Warn("Failed to invalidate cache", zap.String("key", key), zap.Error(err))
 	}
 }
+
+// ReverseTransaction refunds a transaction by creating compensating transactions and restoring the balances.
+// A nil amount reverses everything not reversed yet, a smaller amount makes a partial refund.
+// Transfers are reversed on both legs, moving the money from the destination back to the source.
+func (s *TransactionServiceImpl) ReverseTransaction(transactionID string, amount *types.Money) (*models.ReversalResult, error) {
+	return s.reverse(transactionID, amount, func(original *models.Transaction) error { return nil })
+}
+
+// ReturnTransfer reverses the credit leg of a transfer received by the user, the only reversal customers may make:
+// it moves their own money back to the sender, never money into their accounts
+func (s *TransactionServiceImpl) ReturnTransfer(userID, transactionID string, amount *types.Money) (*models.ReversalResult, error) {
+	return s.reverse(transactionID, amount, func(original *models.Transaction) error {
+		if original.UserID != userID {
+			return ErrTransactionNotFound
+		}
+		if models.TransactionType(original.TransactionType) != models.Transfer || original.Direction != models.Credit {
+			return ErrTransferNotReturnable
+		}
+		return nil
+	})
+}
+
+// reverse reverses the transaction once authorize accepts it, the original is locked by then
+func (s *TransactionServiceImpl) reverse(transactionID string, amount *types.Money, authorize func(original *models.Transaction) error) (*models.ReversalResult, error) {
+	result := &models.ReversalResult{}
+
+	err := s.txProvider.Transact(func(adapters repositories.Adapters) error {
+		// Lock the original, and the other leg of a transfer, so concurrent reversals cannot exceed its amount
+		original, counterpart, err := lockTransactionLegs(adapters.TransactionRepository, transactionID)
+		if err != nil {
+			return err
+		}
+		if err := authorize(original); err != nil {
+			return err
+		}
+		if original.ReversalOf != nil {
+			return ErrTransactionNotReversible
+		}
+
+		refund, err := reversalAmount(original, amount)
+		if err != nil {
+			return err
+		}
+
+		switch models.TransactionType(original.TransactionType) {
+		case models.Deposit, models.Withdrawal:
+			result.Reversals, err = reverseSingleLeg(adapters, original, refund)
+		case models.Transfer:
+			result.Reversals, err = reverseTransfer(adapters, original, counterpart, refund)
+		default:
+			err = ErrTransactionNotReversible
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Original = original
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrTransactionNotFound) {
+			logger.Warn("Failed to reverse transaction", zap.String("transaction_id", transactionID), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	// Reversed transactions changed, drop them from the cache
+	ctx := context.Background()
+	if result.Original.LinkedTransactionID != nil {
+		s.invalidateCache(ctx, fmt.Sprintf("transaction:%s", *result.Original.LinkedTransactionID))
+	}
+	for _, transaction := range append([]*models.Transaction{result.Original}, result.Reversals...) {
+		s.invalidateCache(ctx, fmt.Sprintf("transaction:%s", transaction.TransactionID))
+		invalidateTransactionLists(s.redisClient, transaction.UserID, transaction.AccountID)
+	}
+
+	return result, nil
+}
+
+// lockTransactionLegs locks a transaction and the other leg of a transfer in transaction_id order, whichever leg is
+// reversed, so that reversals of both legs of a transfer at once wait for each other instead of deadlocking. The
+// other leg is nil when it does not exist.
+func lockTransactionLegs(transactionRepository repositories.TransactionRepository, transactionID string) (*models.Transaction, *models.Transaction, error) {
+	// Legs are linked when the transfer is made and never relinked, the link can be read before locking
+	original, err := transactionRepository.GetByID(transactionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	legIDs := []string{transactionID}
+	if original.LinkedTransactionID != nil {
+		legIDs = append(legIDs, *original.LinkedTransactionID)
+		sort.Strings(legIDs)
+	}
+
+	legs := make(map[string]*models.Transaction, len(legIDs))
+	for _, legID := range legIDs {
+		leg, err := transactionRepository.GetByIDForUpdate(legID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		legs[legID] = leg
+	}
+
+	if legs[transactionID] == nil {
+		return nil, nil, ErrTransactionNotFound
+	}
+	if original.LinkedTransactionID == nil {
+		return legs[transactionID], nil, nil
+	}
+	return legs[transactionID], legs[*original.LinkedTransactionID], nil
+}
+
+// reversalAmount returns the amount to refund, defaulting to what is left of the original
+func reversalAmount(original *models.Transaction, amount *types.Money) (types.Money, error) {
+	reversed := original.ReversedAmount
+	if reversed.Currency == "" {
+		reversed.Currency = original.Amount.Currency
+	}
+
+	remaining, err := original.Amount.Sub(reversed)
+	if err != nil {
+		return types.Money{}, err
+	}
+	if !remaining.IsPositive() {
+		return types.Money{}, ErrTransactionAlreadyReversed
+	}
+
+	if amount == nil {
+		return remaining, nil
+	}
+	if !amount.IsPositive() {
+		return types.Money{}, ErrInvalidAmount
+	}
+	if !amount.SameCurrency(remaining) {
+		return types.Money{}, ErrCurrencyMismatch
+	}
+	if remaining.LessThan(*amount) {
+		return types.Money{}, ErrReversalExceedsAmount
+	}
+
+	return *amount, nil
+}
+
+// newReversalTransaction builds the compensating transaction for one leg, moving money in the opposite direction
+func newReversalTransaction(original *models.Transaction, refund types.Money) *models.Transaction {
+	direction := models.Credit
+	if original.Direction == models.Credit {
+		direction = models.Debit
+	}
+
+	return &models.Transaction{
+		BaseModel:       &models.BaseModel{},
+		TransactionID:   uuid.New().String(),
+		UserID:          original.UserID,
+		AccountID:       original.AccountID,
+		Name:            "Reversal of " + original.Name,
+		IsBank:          true,
+		Amount:          refund,
+		TransactionType: string(models.Reversal),
+		Direction:       direction,
+		ReversalOf:      &original.TransactionID,
+	}
+}
+
+// markReversed adds the refund to the original's reversed amount and saves it
+func markReversed(adapters repositories.Adapters, original *models.Transaction, refund types.Money) error {
+	reversed := original.ReversedAmount
+	if reversed.Currency == "" {
+		reversed.Currency = refund.Currency
+	}
+
+	var err error
+	if original.ReversedAmount, err = reversed.Add(refund); err != nil {
+		return err
+	}
+
+	return adapters.TransactionRepository.Update(original)
+}
+
+// reverseSingleLeg reverses a deposit or withdrawal against the external ledger account
+func reverseSingleLeg(adapters repositories.Adapters, original *models.Transaction, refund types.Money) ([]*models.Transaction, error) {
+	reversal := newReversalTransaction(original, refund)
+	if err := checkNotFrozen(adapters.AccountRepository, original.AccountID); err != nil {
+		return nil, err
+	}
+
+	var balance types.Money
+	err := adapters.AccountRepository.UpdateAccountBalance(original.AccountID, func(currentBalance types.Money) (types.Money, error) {
+		var err error
+		if reversal.Direction == models.Credit {
+			balance, err = currentBalance.Add(refund)
+			return balance, err
+		}
+		// Held funds cannot be reversed out of the account
+		if err := checkAvailable(adapters.HoldRepository, original.AccountID, currentBalance, refund); err != nil {
+			return types.Money{}, err
+		}
+		balance, err = currentBalance.Sub(refund)
+		return balance, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := adapters.TransactionRepository.Create(reversal); err != nil {
+		return nil, err
+	}
+	if err := markReversed(adapters, original, refund); err != nil {
+		return nil, err
+	}
+	if err := recordReversalEvent(adapters.OutboxRepository, reversal, balance); err != nil {
+		return nil, err
+	}
+
+	debitAccountID, creditAccountID := original.AccountID, externalLedgerAccount(refund.Currency)
+	if reversal.Direction == models.Credit {
+		debitAccountID, creditAccountID = creditAccountID, debitAccountID
+	}
+	entry := newJournalEntry(models.ReversalEntry, reversal.TransactionID, reversal.Name, debitAccountID, creditAccountID, refund)
+	if err := postJournalEntry(adapters.LedgerRepository, entry); err != nil {
+		return nil, err
+	}
+
+	return []*models.Transaction{reversal}, nil
+}
+
+// reverseTransfer moves the refund from the destination back to the source and reverses both legs, both are locked
+func reverseTransfer(adapters repositories.Adapters, original, counterpart *models.Transaction, refund types.Money) ([]*models.Transaction, error) {
+	if counterpart == nil {
+		return nil, ErrTransactionNotReversible
+	}
+
+	// A cross-currency transfer refunds the other leg in its own currency at the rate it was executed at
+	counterpartRefund, err := counterpartAmount(original, counterpart, refund)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceLeg, destLeg := original, counterpart
+	sourceRefund, destRefund := refund, counterpartRefund
+	if original.Direction == models.Credit {
+		sourceLeg, destLeg = counterpart, original
+		sourceRefund, destRefund = counterpartRefund, refund
+	}
+	for _, accountID := range []string{destLeg.AccountID, sourceLeg.AccountID} {
+		if err := checkNotFrozen(adapters.AccountRepository, accountID); err != nil {
+			return nil, err
+		}
+	}
+
+	balances := &types.TransferResult{}
+	err = adapters.AccountRepository.TransferFunds(destLeg.AccountID, sourceLeg.AccountID, destRefund, func(sourceBalance, destBalance types.Money) (*types.TransferResult, error) {
+		// The destination of the original transfer is the source of the refund, its held funds stay put
+		if err := checkAvailable(adapters.HoldRepository, destLeg.AccountID, sourceBalance, destRefund); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if balances.SourceBalance, err = sourceBalance.Sub(destRefund); err != nil {
+			return nil, err
+		}
+		if balances.DestinationBalance, err = destBalance.Add(sourceRefund); err != nil {
+			return nil, err
+		}
+		return balances, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	destReversal := newReversalTransaction(destLeg, destRefund)
+	sourceReversal := newReversalTransaction(sourceLeg, sourceRefund)
+	destReversal.LinkedTransactionID = &sourceReversal.TransactionID
+	sourceReversal.LinkedTransactionID = &destReversal.TransactionID
+	destReversal.ExchangeRate, sourceReversal.ExchangeRate = destLeg.ExchangeRate, sourceLeg.ExchangeRate
+
+	for _, reversal := range []*models.Transaction{destReversal, sourceReversal} {
+		if err := adapters.TransactionRepository.Create(reversal); err != nil {
+			return nil, err
+		}
+	}
+	if err := markReversed(adapters, sourceLeg, sourceRefund); err != nil {
+		return nil, err
+	}
+	if err := markReversed(adapters, destLeg, destRefund); err != nil {
+		return nil, err
+	}
+	if err := recordReversalEvent(adapters.OutboxRepository, destReversal, balances.SourceBalance); err != nil {
+		return nil, err
+	}
+	if err := recordReversalEvent(adapters.OutboxRepository, sourceReversal, balances.DestinationBalance); err != nil {
+		return nil, err
+	}
+
+	entry := newJournalEntry(models.ReversalEntry, destReversal.TransactionID, sourceReversal.Name,
+		destLeg.AccountID, sourceLeg.AccountID, destRefund)
+	if !destRefund.SameCurrency(sourceRefund) {
+		entry = newFXJournalEntry(models.ReversalEntry, destReversal.TransactionID, sourceReversal.Name,
+			destLeg.AccountID, sourceLeg.AccountID, destRefund, sourceRefund)
+	}
+	if err := postJournalEntry(adapters.LedgerRepository, entry); err != nil {
+		return nil, err
+	}
+
+	return []*models.Transaction{sourceReversal, destReversal}, nil
+}
+
+// checkNotFrozen fails with ErrAccountFrozen when staff froze the account, reversals move money like any transfer
+func checkNotFrozen(accountRepository repositories.AccountRepository, accountID string) error {
+	account, err := accountRepository.GetAccountWithDetailByID(accountID)
+	if err != nil {
+		return err
+	}
+	if account.IsFrozen() {
+		return ErrAccountFrozen
+	}
+	return nil
+}
+
+// checkAvailable fails with ErrInsufficientFunds when the balance less the held funds does not cover amount
+func checkAvailable(holdRepository repositories.HoldRepository, accountID string, balance, amount types.Money) error {
+	available, err := availableBalance(holdRepository, accountID, balance)
+	if err != nil {
+		return err
+	}
+	if available.LessThan(amount) {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// recordReversalEvent records the reversal of one leg with the balance of its account after it
+func recordReversalEvent(outbox repositories.OutboxRepository, reversal *models.Transaction, balance types.Money) error {
+	return recordEvent(outbox, models.EventTransactionReversed, models.AggregateAccount, reversal.AccountID, "",
+		models.TransactionReversedPayload{
+			AccountID:     reversal.AccountID,
+			UserID:        reversal.UserID,
+			TransactionID: reversal.TransactionID,
+			ReversalOf:    *reversal.ReversalOf,
+			Direction:     reversal.Direction,
+			Amount:        reversal.Amount,
+			Balance:       balance,
+		})
+}
+
+// counterpartAmount returns how much of the other leg of a transfer matches refunding refund of original.
+// Legs in the same currency move the same amount. Across currencies the refund is proportional to the executed
+// amounts, and the last refund returns whatever is left so a full reversal restores both legs exactly.
+func counterpartAmount(original, counterpart *models.Transaction, refund types.Money) (types.Money, error) {
+	if counterpart.Amount.SameCurrency(original.Amount) {
+		return refund, nil
+	}
+
+	remaining, err := reversalAmount(original, nil)
+	if err != nil {
+		return types.Money{}, err
+	}
+	if refund == remaining {
+		return reversalAmount(counterpart, nil)
+	}
+
+	return counterpart.Amount.MulDiv(refund.Amount, original.Amount.Amount)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/types"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrTransferLimitExceeded is returned when money leaving an account would exceed one of its limits
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// TransferLimitService defines the interface for transfer limit operations
+type TransferLimitService interface {
+	GetAccountLimits(account *models.Account) (*models.AccountLimits, error)
+}
+
+// TransferLimitServiceImpl implements TransferLimitService
+type TransferLimitServiceImpl struct {
+	transferLimitRepository repositories.TransferLimitRepository
+}
+
+// NewTransferLimitService creates a new instance of TransferLimitService
+func NewTransferLimitService(transferLimitRepository repositories.TransferLimitRepository) TransferLimitService {
+	return &TransferLimitServiceImpl{
+		transferLimitRepository: transferLimitRepository,
+	}
+}
+
+// GetAccountLimits returns the effective limits of an account and the allowance left in the current windows
+func (s *TransferLimitServiceImpl) GetAccountLimits(account *models.Account) (*models.AccountLimits, error) {
+	return resolveAccountLimits(s.transferLimitRepository, account.AccountID, account.UserID, account.Type, account.Currency, time.Now())
+}
+
+// checkTransferLimits fails with ErrTransferLimitExceeded when debiting amount from the account would exceed
+// one of its limits. It must run inside the transaction holding the lock on the account balance so that
+// concurrent debits of the same account are counted.
+func checkTransferLimits(limitRepo repositories.TransferLimitRepository, accountID, userID, accountType string, amount types.Money) error {
+	limits, err := resolveAccountLimits(limitRepo, accountID, userID, accountType, amount.Currency, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if limits.PerTransaction != nil && limits.PerTransaction.LessThan(amount) {
+		return fmt.Errorf("%w: per transaction limit is %s", ErrTransferLimitExceeded, limits.PerTransaction)
+	}
+	if limits.Daily.Remaining != nil && limits.Daily.Remaining.LessThan(amount) {
+		return fmt.Errorf("%w: %s left of the daily limit", ErrTransferLimitExceeded, limits.Daily.Remaining)
+	}
+	if limits.Monthly.Remaining != nil && limits.Monthly.Remaining.LessThan(amount) {
+		return fmt.Errorf("%w: %s left of the monthly limit", ErrTransferLimitExceeded, limits.Monthly.Remaining)
+	}
+
+	return nil
+}
+
+// resolveAccountLimits merges the applicable limit rows, most specific last, and computes the usage of each window
+func resolveAccountLimits(limitRepo repositories.TransferLimitRepository, accountID, userID, accountType, currency string, now time.Time) (*models.AccountLimits, error) {
+	rows, err := limitRepo.GetApplicableLimits(userID, accountType, currency)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Specificity() < rows[j].Specificity()
+	})
+
+	limits := &models.AccountLimits{
+		AccountID: accountID,
+		Currency:  currency,
+	}
+	for _, row := range rows {
+		if row.PerTransaction != nil {
+			limits.PerTransaction = row.PerTransaction
+		}
+		if row.Daily != nil {
+			limits.Daily.Limit = row.Daily
+		}
+		if row.Monthly != nil {
+			limits.Monthly.Limit = row.Monthly
+		}
+	}
+
+	local := now.In(configs.LIMIT_WINDOW_LOCATION)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	monthStart := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, local.Location())
+
+	if err := fillLimitWindow(limitRepo, &limits.Daily, accountID, currency, dayStart, dayStart.AddDate(0, 0, 1)); err != nil {
+		return nil, err
+	}
+	if err := fillLimitWindow(limitRepo, &limits.Monthly, accountID, currency, monthStart, monthStart.AddDate(0, 1, 0)); err != nil {
+		return nil, err
+	}
+
+	// The largest single debit is bounded by every limit that is set
+	for _, bound := range []*types.Money{limits.PerTransaction, limits.Daily.Remaining, limits.Monthly.Remaining} {
+		if bound != nil && (limits.Available == nil || bound.LessThan(*limits.Available)) {
+			available := *bound
+			limits.Available = &available
+		}
+	}
+
+	return limits, nil
+}
+
+// fillLimitWindow sums the debits made since start and the allowance left until the window resets
+func fillLimitWindow(limitRepo repositories.TransferLimitRepository, window *models.LimitWindow, accountID, currency string, start, resetsAt time.Time) error {
+	used, err := limitRepo.GetDebitTotalSince(accountID, currency, start)
+	if err != nil {
+		return err
+	}
+
+	window.Used = used
+	window.ResetsAt = resetsAt
+	if window.Limit == nil {
+		return nil
+	}
+
+	remaining, err := window.Limit.Sub(used)
+	if err != nil {
+		return err
+	}
+	if remaining.IsNegative() {
+		remaining = types.NewMoney(0, currency)
+	}
+	window.Remaining = &remaining
+
+	return nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/types"
 )
 
 type UserService interface {
@@ -10,19 +11,22 @@ type UserService interface {
 	GetUserGreetingByID(id string) (*models.UserGreeting, error)
 	UpdateUserGreeting(greeting *models.UserGreeting) error
 	UpdateUser(user *models.User) error
+	GetUserRoles(id string) ([]types.Role, error)
 }
 
 // UserService contains business logic related to users.
 type UserServiceImpl struct {
 	UserRepository         repositories.UserRepository
 	UserGreetingRepository repositories.UserGreetingRepository
+	RoleRepository         repositories.RoleRepository
 }
 
 // NewUserService creates a new UserService.
-func NewUserService(userRepository repositories.UserRepository, userGreetingRepository repositories.UserGreetingRepository) UserService {
+func NewUserService(userRepository repositories.UserRepository, userGreetingRepository repositories.UserGreetingRepository, roleRepository repositories.RoleRepository) UserService {
 	return &UserServiceImpl{
 		UserRepository:         userRepository,
 		UserGreetingRepository: userGreetingRepository,
+		RoleRepository:         roleRepository,
 	}
 }
 
@@ -43,3 +47,8 @@ func (s *UserServiceImpl) GetUserGreetingByID(id string) (*models.UserGreeting,
 func (s *UserServiceImpl) UpdateUserGreeting(greeting *models.UserGreeting) error {
 	return s.UserGreetingRepository.Update(greeting)
 }
+
+// GetUserRoles retrieves the staff roles of a user.
+func (s *UserServiceImpl) GetUserRoles(id string) ([]types.Role, error) {
+	return s.RoleRepository.GetRolesByUserID(id)
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/pkg/utils"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Page sizes of webhook delivery listings
+const (
+	DefaultWebhookDeliveryLimit = 50
+	MaxWebhookDeliveryLimit     = 200
+)
+
+var (
+	ErrInvalidWebhookURL    = errors.New("webhook URL must be an absolute https URL")
+	ErrPrivateWebhookURL    = errors.New("webhook URL must not point to a local or private network address")
+	ErrUnknownEventType     = errors.New("unknown event type")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryInProgress   = errors.New("webhook delivery is still being attempted")
+	errUnexpectedStatusCode = errors.New("unexpected status code")
+)
+
+// WebhookService defines the interface for webhook endpoints and the delivery of domain events to them.
+// It is an EventPublisher: the outbox relay hands it every event, which it stores as deliveries to the
+// subscribed endpoints that DeliverDue then sends.
+type WebhookService interface {
+	EventPublisher
+
+	CreateEndpoint(userID string, scope models.WebhookScope, endpointURL, description string, eventTypes models.EventTypeList) (*models.WebhookEndpoint, error)
+	GetEndpointByID(endpointID string) (*models.WebhookEndpoint, error)
+	GetEndpointsByUserID(userID string) ([]*models.WebhookEndpoint, error)
+	GetEndpointsByScope(scope models.WebhookScope) ([]*models.WebhookEndpoint, error)
+	DeleteEndpoint(endpointID string) error
+
+	ListDeliveries(endpointID string, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error)
+	ReplayDelivery(endpointID string, deliveryID int64) (*models.WebhookDelivery, error)
+
+	// DeliverDue attempts the due deliveries, it is called periodically by the scheduler
+	DeliverDue(ctx context.Context) error
+}
+
+// WebhookServiceImpl implements WebhookService
+type WebhookServiceImpl struct {
+	webhookRepository repositories.WebhookRepository
+	client            *http.Client
+	leaseOwner        string
+}
+
+// NewWebhookService creates a new webhook service sending deliveries with the client
+func NewWebhookService(webhookRepository repositories.WebhookRepository, client *http.Client) WebhookService {
+	return &WebhookServiceImpl{
+		webhookRepository: webhookRepository,
+		client:            client,
+		leaseOwner:        uuid.New().String(),
+	}
+}
+
+// NewWebhookHTTPClient creates the client deliveries are sent with. Redirects are not followed, a delivery
+// goes to the registered URL only. Unless allowPrivateNetworks, connections to the addresses isPublicAddress
+// rejects are refused once the host name is resolved, so a name rebound to an internal address after it was
+// registered reaches nothing either. Proxies are not used as they would connect on the client's behalf.
+func NewWebhookHTTPClient(allowPrivateNetworks bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivateNetworks {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   refusePrivateAddress,
+		}
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &http.Client{
+		Timeout:   configs.WEBHOOK_TIMEOUT,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// WebhookPrivateNetworksAllowed reports whether webhooks may target local and private addresses, for receivers
+// on a developer machine. WEBHOOK_ALLOW_PRIVATE_NETWORKS turns it on outside of production.
+func WebhookPrivateNetworksAllowed() bool {
+	return os.Getenv("APP_ENV") != "prod" && os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true"
+}
+
+// refusePrivateAddress is the net.Dialer Control hook refusing to connect to a non public address
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateWebhookURL, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), not routable on the internet
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicAddress reports whether ip may receive webhooks: loopback, RFC 1918 and IPv6 unique local (fc00::/7),
+// link-local such as the 169.254.169.254 metadata service, shared, unspecified and multicast addresses may not.
+// IPv4-mapped IPv6 addresses are checked as IPv4.
+func isPublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified() && !sharedAddressSpace.Contains(ip)
+}
+
+// CreateEndpoint registers an endpoint with a new signing secret, an empty list of event types subscribes to
+// every type
+func (s *WebhookServiceImpl) CreateEndpoint(userID string, scope models.WebhookScope, endpointURL, description string, eventTypes models.EventTypeList) (*models.WebhookEndpoint, error) {
+	if err := validateWebhookURL(endpointURL); err != nil {
+		return nil, err
+	}
+	for _, eventType := range eventTypes {
+		if !eventType.IsValid() {
+			return nil, fmt.Errorf("%w %q", ErrUnknownEventType, eventType)
+		}
+	}
+
+	secret, err := utils.NewWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		EndpointID:  uuid.New().String(),
+		UserID:      userID,
+		Scope:       scope,
+		URL:         endpointURL,
+		Description: description,
+		EventTypes:  eventTypes,
+		Secret:      secret,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.webhookRepository.CreateEndpoint(endpoint); err != nil {
+		logger.Error("Failed to create webhook endpoint", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// validateWebhookURL accepts absolute https URLs, plain http is accepted outside of production for local receivers.
+// Local and private addresses are rejected unless WebhookPrivateNetworksAllowed, host names are checked once
+// resolved by the delivery client.
+func validateWebhookURL(endpointURL string) error {
+	parsed, err := url.Parse(endpointURL)
+	if err != nil || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if os.Getenv("APP_ENV") == "prod" {
+			return ErrInvalidWebhookURL
+		}
+	default:
+		return ErrInvalidWebhookURL
+	}
+
+	if WebhookPrivateNetworksAllowed() {
+		return nil
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateWebhookURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicAddress(ip) {
+		return ErrPrivateWebhookURL
+	}
+	return nil
+}
+
+// GetEndpointByID retrieves an endpoint
+func (s *WebhookServiceImpl) GetEndpointByID(endpointID string) (*models.WebhookEndpoint, error) {
+	return s.webhookRepository.GetEndpointByID(endpointID)
+}
+
+// GetEndpointsByUserID retrieves the endpoints a user registered
+func (s *WebhookServiceImpl) GetEndpointsByUserID(userID string) ([]*models.WebhookEndpoint, error) {
+	return s.webhookRepository.GetEndpointsByUserID(userID)
+}
+
+// GetEndpointsByScope retrieves the endpoints of a scope
+func (s *WebhookServiceImpl) GetEndpointsByScope(scope models.WebhookScope) ([]*models.WebhookEndpoint, error) {
+	return s.webhookRepository.GetEndpointsByScope(scope)
+}
+
+// DeleteEndpoint deletes an endpoint, its deliveries stay in the log but are no longer attempted
+func (s *WebhookServiceImpl) DeleteEndpoint(endpointID string) error {
+	return s.webhookRepository.DeleteEndpoint(endpointID)
+}
+
+// ListDeliveries retrieves the deliveries of an endpoint, newest first, at most MaxWebhookDeliveryLimit at a time
+func (s *WebhookServiceImpl) ListDeliveries(endpointID string, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultWebhookDeliveryLimit
+	}
+	if filter.Limit > MaxWebhookDeliveryLimit {
+		filter.Limit = MaxWebhookDeliveryLimit
+	}
+
+	return s.webhookRepository.ListDeliveries(endpointID, filter)
+}
+
+// ReplayDelivery sends a delivered or dead delivery again with the same body and a fresh set of attempts
+func (s *WebhookServiceImpl) ReplayDelivery(endpointID string, deliveryID int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepository.GetDelivery(endpointID, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		return nil, ErrDeliveryInProgress
+	}
+
+	now := time.Now()
+	err = s.webhookRepository.ResetDelivery(endpointID, deliveryID, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		// It was replayed concurrently
+		return nil, ErrDeliveryInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.LastError = ""
+	return delivery, nil
+}
+
+// webhookBody is the body of a delivery
+type webhookBody struct {
+	ID         string           `json:"id"` // the event id, receivers deduplicate by it
+	Type       models.EventType `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       json.RawMessage  `json:"data"`
+}
+
+// Publish stores a delivery of the event to every endpoint subscribed to it. An event relayed again does not
+// add deliveries, so a failure here lets the relay retry the event safely.
+func (s *WebhookServiceImpl) Publish(_ context.Context, event *models.DomainEvent) error {
+	endpoints, err := s.webhookRepository.GetEndpointsForUsers(event.UserIDs())
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.EventTypes.Contains(event.EventType) {
+			continue
+		}
+
+		data, err := webhookData(event, endpoint)
+		if err != nil {
+			// Retrying would not help, the endpoint misses the event rather than receive more than it may see
+			logger.Error("Failed to build webhook data", zap.String("event_id", event.EventID),
+				zap.String("endpoint_id", endpoint.EndpointID), zap.Error(err))
+			continue
+		}
+		body, err := json.Marshal(webhookBody{ID: event.EventID, Type: event.EventType, OccurredAt: event.OccurredAt, Data: data})
+		if err != nil {
+			return err
+		}
+
+		err = s.webhookRepository.CreateDelivery(&models.WebhookDelivery{
+			EndpointID:    endpoint.EndpointID,
+			EventID:       event.EventID,
+			EventType:     event.EventType,
+			Payload:       body,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// webhookData returns what the endpoint receives of the event. Staff endpoints receive the whole payload, the
+// endpoint of a user receives only the user's own side of a transfer with another user, as the stream does.
+func webhookData(event *models.DomainEvent, endpoint *models.WebhookEndpoint) (json.RawMessage, error) {
+	if endpoint.Scope == models.WebhookScopeAll || event.EventType != models.EventTransferCompleted {
+		return event.Payload, nil
+	}
+
+	var payload models.TransferCompletedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, err
+	}
+	switch endpoint.UserID {
+	case payload.FromUserID:
+		if payload.ToUserID == endpoint.UserID {
+			// A transfer between the accounts of the user
+			return event.Payload, nil
+		}
+		return json.Marshal(payload.DebitLeg())
+	case payload.ToUserID:
+		return json.Marshal(payload.CreditLeg())
+	}
+	return nil, fmt.Errorf("endpoint of user %s is not a party to the transfer", endpoint.UserID)
+}
+
+// DeliverDue claims the due deliveries and attempts them concurrently. A failed attempt is retried with an
+// exponential backoff, after configs.WEBHOOK_MAX_ATTEMPTS attempts the delivery is dead-lettered.
+func (s *WebhookServiceImpl) DeliverDue(ctx context.Context) error {
+	dispatches, err := s.webhookRepository.ClaimDueDeliveries(s.leaseOwner, time.Now(), configs.WEBHOOK_LEASE_DURATION, configs.WEBHOOK_BATCH_SIZE)
+	if err != nil {
+		logger.Error("Failed to claim due webhook deliveries", zap.Error(err))
+		return err
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, configs.WEBHOOK_CONCURRENCY)
+	for _, dispatch := range dispatches {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(dispatch *models.WebhookDispatch) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.attempt(ctx, dispatch)
+		}(dispatch)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// attempt sends a delivery once and stores the result, the lease is released either way
+func (s *WebhookServiceImpl) attempt(ctx context.Context, dispatch *models.WebhookDispatch) {
+	delivery := &dispatch.WebhookDelivery
+	statusCode, err := s.send(ctx, dispatch)
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= configs.WEBHOOK_MAX_ATTEMPTS {
+			delivery.Status = models.WebhookDeliveryDead
+			logger.Warn("Webhook delivery dead-lettered",
+				zap.Int64("delivery_id", delivery.DeliveryID),
+				zap.String("endpoint_id", delivery.EndpointID),
+				zap.String("event_id", delivery.EventID),
+				zap.Error(err))
+		} else {
+			delivery.NextAttemptAt = now.Add(WebhookRetryDelay(delivery.Attempts))
+		}
+	}
+
+	if err := s.webhookRepository.UpdateDelivery(delivery); err != nil {
+		// The lease expires and the delivery is attempted again
+		logger.Error("Failed to store webhook delivery attempt", zap.Int64("delivery_id", delivery.DeliveryID), zap.Error(err))
+	}
+}
+
+// send posts the signed body to the endpoint, any status other than 2xx is a failure
+func (s *WebhookServiceImpl) send(ctx context.Context, dispatch *models.WebhookDispatch) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(dispatch.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "backend-developer-assignment-webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", strconv.FormatInt(dispatch.DeliveryID, 10))
+	req.Header.Set("X-Webhook-Event", string(dispatch.EventType))
+	req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhook(dispatch.Secret, time.Now(), dispatch.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w %d", errUnexpectedStatusCode, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// WebhookRetryDelay returns the delay before the next attempt of a delivery that failed attempts times
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := configs.WEBHOOK_RETRY_BASE
+	for i := 1; i < attempts && delay < configs.WEBHOOK_RETRY_MAX; i++ {
+		delay *= 2
+	}
+	if delay > configs.WEBHOOK_RETRY_MAX {
+		delay = configs.WEBHOOK_RETRY_MAX
+	}
+	return delay
+}
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -19,6 +20,7 @@ import (
 	"backend-developer-assignment/app/services"
 	"backend-developer-assignment/pkg/configs"
 	"backend-developer-assignment/pkg/middleware"
+	"backend-developer-assignment/pkg/scheduler"
 	"backend-developer-assignment/pkg/utils"
 	"backend-developer-assignment/platform/database"
 
@@ -76,6 +78,13 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Load the keys access tokens are signed with
+	keyring, err := utils.LoadKeyringFromEnv()
+	if err != nil {
+		log.Fatal("JWT keyring loading failed:", err)
+	}
+	utils.SetKeyring(keyring)
+
 	// Define Fiber config.
 	config := configs.FiberConfig()
 
@@ -83,6 +92,10 @@ func main() {
 	app := fiber.New(config)
 
 	redisClient := configs.RedisConnection()
+	cacheClient, stopCache, err := configs.CacheConnection(redisClient)
+	if err != nil {
+		log.Fatal("Cache connection failed:", err)
+	}
 
 	// Middlewares.
 	middleware.FiberMiddleware(app) // Register Fiber's middleware for app.
@@ -90,10 +103,63 @@ func main() {
 	// Initialize repoList, services, and controllers
 	txProvider := repositories.NewTransactionProvider(db)
 	repoList := repositories.InitRepository(db)
-	serviceList := services.InitService(repoList, txProvider, redisClient)
+	serviceList := services.InitService(repoList, txProvider, cacheClient)
 	controllerList := controllers.InitController(serviceList)
 	// Routes
 	routes.InitRoutes(app, controllerList)
 
-	utils.StartServerWithGracefulShutdown(app, redisClient)
+	// Execute due scheduled transfers in the background, leasing makes this safe to run on every instance
+	transferScheduler := scheduler.New("scheduled-transfers", configs.SCHEDULER_POLL_INTERVAL, serviceList.ScheduledTransferService.RunDueSchedules)
+	transferScheduler.Start()
+
+	// Keep the status of expired holds accurate, available balances already ignore them
+	holdExpiryScheduler := scheduler.New("hold-expiry", configs.HOLD_EXPIRY_INTERVAL, serviceList.AccountService.ExpireHolds)
+	holdExpiryScheduler.Start()
+
+	// Check the books balance, an out of balance currency is logged as an error to alert on
+	ledgerCheckScheduler := scheduler.New("ledger-check", configs.LEDGER_CHECK_INTERVAL, func(ctx context.Context) error {
+		_, err := serviceList.LedgerService.CheckBooksBalanced()
+		return err
+	})
+	ledgerCheckScheduler.Start()
+
+	// Drop refresh tokens that can no longer be renewed, used ones are kept until then to detect their reuse
+	refreshTokenPurgeScheduler := scheduler.New("refresh-token-purge", configs.REFRESH_TOKEN_PURGE_INTERVAL, serviceList.AuthService.PurgeExpiredTokens)
+	refreshTokenPurgeScheduler.Start()
+
+	// Drop step-up challenges that can no longer be confirmed
+	challengePurgeScheduler := scheduler.New("challenge-purge", configs.CHALLENGE_PURGE_INTERVAL, serviceList.ChallengeService.PurgeExpiredChallenges)
+	challengePurgeScheduler.Start()
+
+	// Pick up keys added to the keyring file, rotation then needs no restart
+	keyringReloadScheduler := scheduler.New("jwt-keyring-reload", configs.JWT_KEYRING_RELOAD_INTERVAL, func(ctx context.Context) error {
+		return utils.ReloadKeyring()
+	})
+	keyringReloadScheduler.Start()
+
+	// Publish domain events from the outbox, the relay lease makes a single instance publish them in order
+	outboxRelayScheduler := scheduler.New("outbox-relay", configs.OUTBOX_RELAY_INTERVAL, serviceList.OutboxService.RelayEvents)
+	outboxRelayScheduler.Start()
+
+	// Drop events published longer than the retention ago
+	outboxPurgeScheduler := scheduler.New("outbox-purge", configs.OUTBOX_PURGE_INTERVAL, serviceList.OutboxService.PurgePublishedEvents)
+	outboxPurgeScheduler.Start()
+
+	// Send webhook deliveries created by the relay, leasing makes this safe to run on every instance
+	webhookDeliveryScheduler := scheduler.New("webhook-delivery", configs.WEBHOOK_DELIVERY_INTERVAL, serviceList.WebhookService.DeliverDue)
+	webhookDeliveryScheduler.Start()
+
+	// Streams only end when their clients go away, close them so that the shutdown does not wait for them
+	utils.StartServerWithGracefulShutdown(app, redisClient, serviceList.StreamService.Close, stopCache)
+
+	// Wait for an in-flight batch to stop, unprocessed schedules are picked up again once their lease expires
+	transferScheduler.Stop()
+	holdExpiryScheduler.Stop()
+	ledgerCheckScheduler.Stop()
+	refreshTokenPurgeScheduler.Stop()
+	challengePurgeScheduler.Stop()
+	keyringReloadScheduler.Stop()
+	outboxRelayScheduler.Stop()
+	outboxPurgeScheduler.Stop()
+	webhookDeliveryScheduler.Stop()
 }
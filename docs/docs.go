@@ -23,6 +23,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys other services verify access tokens with, pick the key by the kid in the token header. Keys that start signing soon are already listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.JWKSet"
+                        }
+                    },
+                    "503": {
+                        "description": "No keyring loaded",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/accounts": {
             "get": {
                 "security": [
@@ -98,7 +124,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Transfer money between accounts",
+                "description": "Transfer money between two different accounts. The amount is in the source account's currency, transfers to an account of another currency must pass the quote_id of an fx quote for that amount. Transfers above the step-up threshold of the currency respond 202 with a challenge_id and run once the challenge is confirmed through /challenges/{id}/confirm.",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,6 +144,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.Transfer.transferRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client generated key, retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -127,6 +159,13 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "202": {
+                        "description": "Transfer waits for step-up authentication",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
@@ -246,6 +285,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.Deposit.depositRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client generated key, retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -259,24 +304,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/main": {
-            "put": {
+        "/accounts/{id}/holds": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Set an account as the main account",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the holds placed on an account, newest first. Active holds are subtracted from the available balance.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "holds"
                 ],
-                "summary": "Set main account",
+                "summary": "List account holds",
                 "parameters": [
                     {
                         "type": "string",
@@ -290,23 +332,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AccountHold"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/accounts/{id}/withdraw": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Withdraw money from an account",
+                "description": "Reserve an amount of the available balance, e.g. for a card authorization. The amount is in the account's currency, expires_at defaults to 7 days and can be at most 30 days away.",
                 "consumes": [
                     "application/json"
                 ],
@@ -314,9 +360,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "holds"
                 ],
-                "summary": "Withdraw money",
+                "summary": "Place hold",
                 "parameters": [
                     {
                         "type": "string",
@@ -326,29 +372,45 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Amount to withdraw",
-                        "name": "amount",
+                        "description": "Hold details",
+                        "name": "hold",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.Withdraw.withdrawRequest"
+                            "$ref": "#/definitions/controllers.PlaceHold.placeHoldRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.AccountHold"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hold or insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/verify-pin": {
+        "/accounts/{id}/holds/{holdId}/capture": {
             "post": {
-                "description": "Verify user PIN and return JWT token",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Debit a hold from the account. Omit amount to capture the full hold, a smaller amount captures part of it and releases the rest. Like a withdrawal, the capture counts against the transfer limits and is refused on a frozen account.",
                 "consumes": [
                     "application/json"
                 ],
@@ -356,60 +418,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "holds"
                 ],
-                "summary": "Verify PIN and get JWT token",
+                "summary": "Capture hold",
                 "parameters": [
                     {
-                        "description": "PIN verification request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "holdId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to capture",
+                        "name": "capture",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.VerifyPin.verifyPinRequest"
+                            "$ref": "#/definitions/controllers.CaptureHold.captureHoldRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT tokens",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "tokens": {
-                                    "type": "object",
-                                    "properties": {
-                                        "access": {
-                                            "type": "string"
-                                        },
-                                        "refresh": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            }
+                            "$ref": "#/definitions/models.AccountHold"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format",
+                        "description": "Invalid amount or transfer limit exceeded",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid PIN",
+                    "403": {
+                        "description": "Account is frozen",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User does not exist",
+                        "description": "Account or hold not found",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to generate token",
+                    "409": {
+                        "description": "Hold no longer active",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
@@ -417,53 +479,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/banners": {
-            "get": {
+        "/accounts/{id}/holds/{holdId}/release": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List all banners for the current user",
+                "description": "Cancel an active hold, its funds become available again",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Banners"
+                    "holds"
+                ],
+                "summary": "Release hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "holdId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List banners",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Banner"
-                            }
+                            "$ref": "#/definitions/models.AccountHold"
+                        }
+                    },
+                    "404": {
+                        "description": "Account or hold not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold no longer active",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/banners/{id}": {
+        "/accounts/{id}/limits": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a banner by its ID",
+                "description": "Get the per transaction, daily and monthly limits on money leaving an account and how much is left. Windows reset at midnight Asia/Bangkok, a null limit is unlimited.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Banners"
+                    "accounts"
                 ],
-                "summary": "Get banner by ID",
+                "summary": "Get account limits",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Banner ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -473,93 +560,77 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Banner"
+                            "$ref": "#/definitions/models.AccountLimits"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/debit-cards": {
-            "get": {
+        "/accounts/{id}/main": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List all debit cards for a user",
-                "produces": [
+                "description": "Set an account as the main account",
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "Debit Cards"
-                ],
-                "summary": "List debit cards",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.DebitCardWithDetails"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Create a new debit card with all its details",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debit Cards"
+                    "accounts"
                 ],
-                "summary": "Create debit card",
+                "summary": "Set main account",
                 "parameters": [
                     {
-                        "description": "Card details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateDebitCard.createDebitCardRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/debit-cards/{id}": {
+        "/accounts/{id}/schedules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a specific debit card by ID",
+                "description": "Get all scheduled transfers paid from an account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debit Cards"
+                    "schedules"
                 ],
-                "summary": "Get debit card",
+                "summary": "List scheduled transfers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Card ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -569,132 +640,185 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledTransfer"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update an existing debit card",
+                "description": "Create a one-off or recurring transfer from an account. The amount is in the account's currency. A schedule paying more than the step-up threshold is created once the challenge returned with 202 is confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debit Cards"
+                    "schedules"
                 ],
-                "summary": "Update debit card",
+                "summary": "Create scheduled transfer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Card ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Card details",
-                        "name": "request",
+                        "description": "Schedule details",
+                        "name": "schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.UpdateDebitCard.updateDebitCardRequest"
+                            "$ref": "#/definitions/controllers.CreateSchedule.createScheduleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                            "$ref": "#/definitions/models.ScheduledTransfer"
+                        }
+                    },
+                    "202": {
+                        "description": "Challenge to confirm with POST /challenges/{id}/confirm",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid schedule",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/accounts/{id}/schedules/{scheduleId}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete a debit card",
+                "description": "Get a scheduled transfer paid from an account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debit Cards"
+                    "schedules"
                 ],
-                "summary": "Delete debit card",
+                "summary": "Get scheduled transfer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Card ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Card deleted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ScheduledTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Account or schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/token/renew": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Renew access and refresh tokens.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Cancel a scheduled transfer, its execution history is kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Token"
+                    "schedules"
                 ],
-                "summary": "renew access and refresh tokens",
+                "summary": "Cancel scheduled transfer",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "refresh_token",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Renew"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ok",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Account or schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/transactions": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all transactions for a user.",
+                "description": "Change a scheduled transfer, set status to paused or active to pause or resume it. Occurrences missed while paused are not executed. An amount above the step-up threshold is applied once the challenge returned with 202 is confirmed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -702,49 +826,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "schedules"
                 ],
-                "summary": "List transactions",
+                "summary": "Update scheduled transfer",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateSchedule.updateScheduleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of transactions",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduledTransfer"
+                        }
+                    },
+                    "202": {
+                        "description": "Challenge to confirm with POST /challenges/{id}/confirm",
                         "schema": {
                             "type": "object",
-                            "properties": {
-                                "total": {
-                                    "type": "integer"
-                                },
-                                "transactions": {
-                                    "type": "array",
-                                    "items": {
-                                        "$ref": "#/definitions/models.Transaction"
-                                    }
-                                }
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid input format",
+                        "description": "Invalid schedule",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Account or schedule not found",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to retrieve transactions",
+                    "409": {
+                        "description": "Schedule already completed",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
@@ -752,100 +889,146 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/greeting": {
+        "/accounts/{id}/schedules/{scheduleId}/executions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the greeting message for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the most recent executions of a scheduled transfer, including skipped and retried attempts",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "schedules"
+                ],
+                "summary": "List scheduled transfer executions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user's greeting message",
                 "responses": {
                     "200": {
-                        "description": "Returns the greeting message",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.GetUserGreeting.getUserGreetingResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized - Invalid or missing token",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/base.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledTransferExecution"
+                            }
                         }
                     },
                     "404": {
-                        "description": "User greeting not found",
+                        "description": "Account or schedule not found",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/accounts/{id}/statements": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update the greeting message of the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the opening balance, every transaction with the running balance and the closing balance of an account from the day from to the day to, both included. Days run in Asia/Bangkok time. The statement is streamed as CSV, JSON or PDF, a failure while streaming ends the response early.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/pdf"
                 ],
                 "tags": [
-                    "User"
+                    "accounts"
                 ],
-                "summary": "Update user's greeting message",
+                "summary": "Get account statement",
                 "parameters": [
                     {
-                        "description": "Request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateUserGreeting.updateUserGreetingRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, e.g. 2026-09-01",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, e.g. 2026-09-30",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, json (default) or pdf",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns the updated greeting message",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.UpdateUserGreeting.updateUserGreetingResponse"
+                            "type": "object",
+                            "properties": {
+                                "account_id": {
+                                    "type": "string"
+                                },
+                                "account_number": {
+                                    "type": "string"
+                                },
+                                "closing_balance": {
+                                    "$ref": "#/definitions/types.Money"
+                                },
+                                "currency": {
+                                    "type": "string"
+                                },
+                                "from": {
+                                    "type": "string"
+                                },
+                                "opening_balance": {
+                                    "$ref": "#/definitions/types.Money"
+                                },
+                                "to": {
+                                    "type": "string"
+                                },
+                                "transactions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.StatementLine"
+                                    }
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad request - Invalid input format",
-                        "schema": {
-                            "$ref": "#/definitions/base.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized - Invalid or missing token",
+                        "description": "Invalid period or format",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/base.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Account not found",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
@@ -853,14 +1036,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/profile": {
+        "/accounts/{id}/transactions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the information of the authenticated user",
+                "description": "Lists the transactions of the account newest first, with the filters, sort and cursors of GET /transactions. Pass next_cursor of a page as cursor, with the same filters and sort, to get the next one, it is absent on the last page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -868,231 +1051,3940 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "accounts"
                 ],
-                "summary": "Get user's information",
-                "responses": {
-                    "200": {
-                        "description": "Returns the user information",
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
+                "summary": "List account transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "401": {
-                        "description": "Unauthorized - Invalid or missing token",
-                        "schema": {
-                            "$ref": "#/definitions/base.ErrorResponse"
-                        }
+                    {
+                        "type": "string",
+                        "description": "deposit, withdrawal, transfer or reversal",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Smallest amount, e.g. 100.00",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Largest amount, e.g. 500.00",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or oldest",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transactions of the account",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "next_cursor": {
+                                    "type": "string"
+                                },
+                                "transactions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Transaction"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve transactions",
                         "schema": {
                             "$ref": "#/definitions/base.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "base.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
+        },
+        "/accounts/{id}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Withdraw money from an account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Withdraw money",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to withdraw",
+                        "name": "amount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Withdraw.withdrawRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client generated key, retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{id}/freeze": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stop deposits, withdrawals, transfers and new holds on an account until it is unfrozen, operations already running complete. Requires the accounts:freeze permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Freeze account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the freeze",
+                        "name": "freeze",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.FreezeAccount.freezeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountWithDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Account is already frozen",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{id}/statements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the statement of any account, see GET /accounts/{id}/statements. Requires the audit:read permission.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get any account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, e.g. 2026-09-01",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, e.g. 2026-09-30",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, json (default) or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "account_id": {
+                                    "type": "string"
+                                },
+                                "account_number": {
+                                    "type": "string"
+                                },
+                                "closing_balance": {
+                                    "$ref": "#/definitions/types.Money"
+                                },
+                                "currency": {
+                                    "type": "string"
+                                },
+                                "from": {
+                                    "type": "string"
+                                },
+                                "opening_balance": {
+                                    "$ref": "#/definitions/types.Money"
+                                },
+                                "to": {
+                                    "type": "string"
+                                },
+                                "transactions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.StatementLine"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or format",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{id}/unfreeze": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lift the freeze of an account. Requires the accounts:freeze permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unfreeze account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountWithDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Account is not frozen",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/audit-logs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List audit log entries newest first. Every filter is optional, from and to are RFC 3339 times. Pass next_before_id of a page as before_id to get the next one. Requires the audit:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List audit logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User or staff member who acted",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. account.withdraw",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the resource acted on, e.g. account",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the resource acted on",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only entries older than this audit_id",
+                        "name": "before_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default and at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "audit_logs": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.AuditLog"
+                                    }
+                                },
+                                "next_before_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/banners": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a banner shown to a user. Requires the banners:manage permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create banner",
+                "parameters": [
+                    {
+                        "description": "Banner",
+                        "name": "banner",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateBanner.createBannerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Banner"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid banner",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/banners/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stop showing a banner. Requires the banners:manage permission.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete banner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Banner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Banner not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the title, description or image of a banner, omitted fields are left unchanged. Requires the banners:manage permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update banner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Banner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Banner fields to change",
+                        "name": "banner",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateBanner.updateBannerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Banner"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid banner",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Banner not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/debit-cards/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set the status of a debit card, e.g. unblock it. Requires the cards:status permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update debit card status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateCardStatus.cardStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Debit card not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transactions/{id}/reverse": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reverses a deposit, withdrawal, hold capture or transfer of any user by creating compensating transactions. Omit the amount to reverse everything not reversed yet. Requires the transactions:reverse permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reverse any transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to refund",
+                        "name": "reversal",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.reverseTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client generated key, retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reversed transaction and its compensating transactions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReversalResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission or account is frozen",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction already reversed or not reversible",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reverse transaction",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a user with their roles, accounts and debit cards. Requires the users:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Look up user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserOverview"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/banners": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the banners shown to a user. Requires the banners:manage permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List banners of user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Banner"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the webhook endpoints receiving the events of every user. Requires the webhooks:manage permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List global webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookEndpoint"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register an endpoint receiving the events of every user. Requires the webhooks:manage permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Register global webhook",
+                "parameters": [
+                    {
+                        "description": "Endpoint URL, description and event types, none subscribes to every type",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "description": {
+                                    "type": "string"
+                                },
+                                "event_types": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "url": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookEndpointWithSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid URL or event type",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a webhook endpoint of any user or a global one. Requires the webhooks:manage permission.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete any webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke the refresh token and every token rotated from the same sign-in. Access tokens of the sign-in are rejected from now on.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Sign out of the current device",
+                "parameters": [
+                    {
+                        "description": "Refresh token of the sign-in",
+                        "name": "refresh_token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Logout.logoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Signed out"
+                    },
+                    "400": {
+                        "description": "Invalid input format",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unknown refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to sign out",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke every refresh token and session of the user, e.g. after losing a device. Access tokens of every device are rejected from now on.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Sign out of all devices",
+                "responses": {
+                    "204": {
+                        "description": "Signed out"
+                    },
+                    "500": {
+                        "description": "Failed to sign out",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/pin-reset": {
+            "post": {
+                "description": "Send a one-time code to reset the PIN through the notifier. The response does not tell whether the user exists, a code expires after 10 minutes and a new request replaces it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Request a PIN reset code",
+                "parameters": [
+                    {
+                        "description": "User to reset the PIN of",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RequestPinReset.requestPinResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Reset code sent if the user exists",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "A code was requested moments ago",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to send reset code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/pin-reset/confirm": {
+            "post": {
+                "description": "Set a new PIN with the code sent by /auth/pin-reset. This lifts a PIN lock and revokes all refresh tokens of the user, a code stops working after 5 wrong attempts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Reset PIN with a reset code",
+                "parameters": [
+                    {
+                        "description": "Reset code and new PIN",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ConfirmPinReset.confirmPinResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "PIN reset"
+                    },
+                    "400": {
+                        "description": "Invalid or expired reset code, or new PIN rejected",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reset PIN",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-pin": {
+            "post": {
+                "description": "Verify user PIN and return JWT token. Failed attempts back off exponentially, lock the PIN for a while\nafter 5 consecutive failures and for good after 10, and an address is throttled after 20 failures across users.\nEvery sign-in starts a session listed by /user/sessions, signing in again with the same device_id replaces the session of the device.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Verify PIN and get JWT token",
+                "parameters": [
+                    {
+                        "description": "PIN verification request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VerifyPin.verifyPinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT tokens",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "tokens": {
+                                    "type": "object",
+                                    "properties": {
+                                        "access": {
+                                            "type": "string"
+                                        },
+                                        "refresh": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid PIN",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "PIN is locked, Retry-After is set for a temporary lock",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to generate token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/banners": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List all banners for the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Banners"
+                ],
+                "summary": "List banners",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Banner"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/banners/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a banner by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Banners"
+                ],
+                "summary": "Get banner by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Banner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Banner"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Confirm a challenge returned by a transfer, or a schedule creation or change, above the step-up threshold with the PIN or a code of the enabled authenticator app, then run the operation. A challenge expires after 5 minutes, can be confirmed once and fails after 3 wrong answers. Wrong PINs and authenticator codes both count towards the PIN lockout of the user, so new challenges do not give more guesses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "challenges"
+                ],
+                "summary": "Confirm challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Second factor",
+                        "name": "confirmation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ConfirmChallenge.confirmChallengeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client generated key, retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result of the transfer, or the schedule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid method or no authenticator app enabled",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong PIN or authenticator code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Challenge not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Challenge expired, failed or already confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "PIN is locked",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong answers, wait before trying again",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/debit-cards": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List all debit cards for a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debit Cards"
+                ],
+                "summary": "List debit cards",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DebitCardWithDetails"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new debit card with all its details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debit Cards"
+                ],
+                "summary": "Create debit card",
+                "parameters": [
+                    {
+                        "description": "Card details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateDebitCard.createDebitCardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/debit-cards/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a specific debit card by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debit Cards"
+                ],
+                "summary": "Get debit card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an existing debit card",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debit Cards"
+                ],
+                "summary": "Update debit card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Card details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateDebitCard.updateDebitCardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DebitCardWithDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a debit card",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debit Cards"
+                ],
+                "summary": "Delete debit card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Card deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx/quotes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lock the rate for converting an amount into another currency. Pass the quote_id to a transfer between accounts of those currencies before expires_at, a quote can be used once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Create fx quote",
+                "parameters": [
+                    {
+                        "description": "Conversion to quote",
+                        "name": "quote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateQuote.createQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FXQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency pair or amount",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No rate for the currency pair",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Push balance updates and new transactions of the user's accounts as they are committed, as Server-Sent Events or, on a WebSocket upgrade, as JSON text messages. Messages are balance, transaction or reset: a reset means messages since the last event id are no longer kept and the accounts are to be reloaded. To resume after a disconnection send the id of the last message received in the Last-Event-ID header or the last_event_id query parameter. Transactions may be pushed twice, deduplicate them by transaction_id.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream balances and transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last message received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last message received, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StreamMessage"
+                        }
+                    },
+                    "503": {
+                        "description": "Stream is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/renew": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renew access and refresh tokens. The refresh token can be used once, presenting a used refresh token again revokes every token of its sign-in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "renew access and refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "refresh_token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Renew"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unknown, expired, revoked or reused refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Without filters, returns a page of 10 transactions newest first with the total count. Any of the filters, sort, limit or cursor switches to a filtered listing instead: amounts are compared as decimals whatever the currency, from is inclusive and to exclusive (RFC 3339), q matches part of the name. Pass next_cursor of a page as cursor, with the same filters and sort, to get the next one, it is absent on the last page. page cannot be combined with the other parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "List transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, without filters",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "deposit, withdrawal, transfer or reversal",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Smallest amount, e.g. 100.00",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Largest amount, e.g. 500.00",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or oldest",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of transactions, total without filters, next_cursor with them",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "next_cursor": {
+                                    "type": "string"
+                                },
+                                "total": {
+                                    "type": "integer"
+                                },
+                                "transactions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Transaction"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve transactions",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/reverse": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a transfer received by the user to its sender by creating compensating transactions on both legs. Only the credit leg of a transfer can be returned by its recipient, other reversals are made by staff. Omit the amount to return everything not returned yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Return received transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID of the received transfer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to return",
+                        "name": "reversal",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.reverseTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client generated key, retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reversed transaction and its compensating transactions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReversalResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Account is frozen",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction already reversed or not a received transfer",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reverse transaction",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/greeting": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the greeting message for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user's greeting message",
+                "responses": {
+                    "200": {
+                        "description": "Returns the greeting message",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetUserGreeting.getUserGreetingResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User greeting not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update the greeting message of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update user's greeting message",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateUserGreeting.updateUserGreetingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the updated greeting message",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateUserGreeting.updateUserGreetingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - Invalid input format",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/pin": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace the PIN of the authenticated user. The new PIN must be 6 digits without a digit repeated or sequential digits more than twice in a row, and differ from the last 5 PINs. Wrong current PINs count towards the PIN lockout and all refresh tokens of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change user's PIN",
+                "parameters": [
+                    {
+                        "description": "Current and new PIN",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ChangePin.changePinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "PIN changed"
+                    },
+                    "400": {
+                        "description": "New PIN rejected by the PIN policy or used recently",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Current PIN is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "PIN is locked",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the information of the authenticated user and whether the PIN is locked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user's information",
+                "responses": {
+                    "200": {
+                        "description": "Returns the user information",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetUser.getUserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the signed-in devices of the user, most recently seen first. The session of the access token is marked current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List signed-in devices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Session"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get sessions",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sign a device out, e.g. a lost phone. Its refresh tokens are revoked and its access tokens are rejected immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Sign out a device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Signed out"
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to sign out",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/totp": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generate a TOTP secret for an authenticator app, replacing one that was not enabled yet. The secret confirms challenges once enabled through /user/totp/enable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Set up an authenticator app",
+                "responses": {
+                    "201": {
+                        "description": "Secret and otpauth URI for the authenticator app",
+                        "schema": {
+                            "$ref": "#/definitions/models.TOTPEnrollment"
+                        }
+                    },
+                    "409": {
+                        "description": "An authenticator app is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/totp/enable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Enable the secret from /user/totp with a code the authenticator app shows.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Enable the authenticator app",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.EnableTOTP.enableTOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Authenticator app enabled"
+                    },
+                    "400": {
+                        "description": "No authenticator app set up",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An authenticator app is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the webhook endpoints registered by the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookEndpoint"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register an endpoint receiving the events of the authenticated user's accounts and cards. Deliveries are signed with the returned secret, which is not shown again: the X-Webhook-Signature header is \"t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256 of \"\u003ct\u003e.\u003cbody\u003e\"\u003e\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register webhook",
+                "parameters": [
+                    {
+                        "description": "Endpoint URL, description and event types, none subscribes to every type",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "description": {
+                                    "type": "string"
+                                },
+                                "event_types": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "url": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookEndpointWithSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid URL or event type",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a webhook endpoint, its pending deliveries are no longer attempted",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the deliveries of a webhook endpoint newest first with the result of their last attempt, paged with before_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "List deliveries before this one",
+                        "name": "before_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "deliveries": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.WebhookDelivery"
+                                    }
+                                },
+                                "next_before_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send a delivered or dead delivery again with the same body and a fresh set of attempts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Replay webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook or delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is still being attempted",
+                        "schema": {
+                            "$ref": "#/definitions/base.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "base.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CaptureHold.captureHoldRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "200.00"
+                }
+            }
+        },
+        "controllers.ChangePin.changePinRequest": {
+            "type": "object",
+            "required": [
+                "current_pin",
+                "new_pin"
+            ],
+            "properties": {
+                "current_pin": {
+                    "type": "string"
+                },
+                "new_pin": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ConfirmChallenge.confirmChallengeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "method"
+            ],
+            "properties": {
+                "code": {
+                    "description": "the PIN or the authenticator code",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "pin",
+                        "totp"
+                    ],
+                    "example": "pin"
+                }
+            }
+        },
+        "controllers.ConfirmPinReset.confirmPinResetRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "new_pin",
+                "user_id"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "new_pin": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateAccount.createAccountRequest": {
+            "type": "object",
+            "required": [
+                "account_number",
+                "currency",
+                "issuer",
+                "type"
+            ],
+            "properties": {
+                "account_number": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "1000.00"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "is_main_account": {
+                    "type": "boolean"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "saving-account",
+                        "credit-loan",
+                        "goal-driven-saving"
+                    ]
+                }
+            }
+        },
+        "controllers.CreateBanner.createBannerRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "title",
+                "user_id"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "You can start applying"
+                },
+                "image": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "https://dummyimage.com/54x54/999/fff"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Want some money?"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateDebitCard.createDebitCardRequest": {
+            "type": "object",
+            "required": [
+                "issuer",
+                "name"
+            ],
+            "properties": {
+                "border_color": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateQuote.createQuoteRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_currency",
+                "to_currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "in from_currency",
+                    "type": "string",
+                    "example": "1000.00"
+                },
+                "from_currency": {
+                    "type": "string",
+                    "example": "THB"
+                },
+                "to_currency": {
+                    "type": "string",
+                    "example": "USD"
+                }
+            }
+        },
+        "controllers.CreateSchedule.createScheduleRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "frequency",
+                "to_account_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "once",
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ]
+                },
+                "insufficient_funds_policy": {
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "retry"
+                    ]
+                },
+                "start_at": {
+                    "description": "defaults to now",
+                    "type": "string"
+                },
+                "to_account_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.Deposit.depositRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                }
+            }
+        },
+        "controllers.EnableTOTP.enableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "controllers.FreezeAccount.freezeRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Reported as compromised"
+                }
+            }
+        },
+        "controllers.GetUser.getUserResponse": {
+            "type": "object",
+            "required": [
+                "name",
+                "user_id"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "for soft delete",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pin_lock": {
+                    "description": "omitted when the lock state cannot be read",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PinLockState"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.GetUserGreeting.getUserGreetingResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.Logout.logoutRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.PlaceHold.placeHoldRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "250.00"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.RequestPinReset.requestPinResetRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.Transfer.transferRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_account_id",
+                "to_account_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "from_account_id": {
+                    "type": "string"
+                },
+                "quote_id": {
+                    "description": "required when the accounts use different currencies",
+                    "type": "string"
+                },
+                "to_account_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateAccount.updateAccountRequest": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "saving-account",
+                        "credit-loan",
+                        "goal-driven-saving"
+                    ]
+                }
+            }
+        },
+        "controllers.UpdateBanner.updateBannerRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "You can start applying"
+                },
+                "image": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "https://dummyimage.com/54x54/999/fff"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Want some money?"
+                }
+            }
+        },
+        "controllers.UpdateCardStatus.cardStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "inactive",
+                        "in-progress",
+                        "blocked"
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.UpdateDebitCard.updateDebitCardRequest": {
+            "type": "object",
+            "properties": {
+                "border_color": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateSchedule.updateScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "once",
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ]
+                },
+                "insufficient_funds_policy": {
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "retry"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "paused"
+                    ]
+                }
+            }
+        },
+        "controllers.UpdateUserGreeting.updateUserGreetingRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateUserGreeting.updateUserGreetingResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.VerifyPin.verifyPinRequest": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "description": "the refresh token is bound to it, renewals must send the same device_id",
+                    "type": "string"
+                },
+                "device_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Jane's iPhone"
+                },
+                "pin": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "ios"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.Withdraw.withdrawRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.50"
+                }
+            }
+        },
+        "controllers.reverseTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "50.00"
+                }
+            }
+        },
+        "models.AccountFlag": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "flag_type",
+                "flag_value",
+                "user_id"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "for soft delete",
+                    "type": "string"
+                },
+                "flag_id": {
+                    "type": "integer"
+                },
+                "flag_type": {
+                    "description": "system and user",
+                    "type": "string"
+                },
+                "flag_value": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AccountHold": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "currency is stored in the currency column",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "captured_amount": {
+                    "$ref": "#/definitions/types.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "hold_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active, captured, released, expired",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HoldStatus"
+                        }
+                    ]
+                },
+                "transaction_id": {
+                    "description": "withdrawal created by the capture",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AccountLimits": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "available": {
+                    "description": "largest amount that can leave the account right now, nil when unlimited",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "daily": {
+                    "$ref": "#/definitions/models.LimitWindow"
+                },
+                "monthly": {
+                    "$ref": "#/definitions/models.LimitWindow"
+                },
+                "per_transaction": {
+                    "$ref": "#/definitions/types.Money"
+                }
+            }
+        },
+        "models.AccountWithDetails": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "Account fields",
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "AccountBalance fields",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "available_amount": {
+                    "description": "ledger balance less active holds",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "color": {
+                    "description": "AccountDetail fields",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "flags": {
+                    "description": "AccountFlags",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AccountFlag"
+                    }
+                },
+                "is_main_account": {
+                    "type": "boolean"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "saving-account, credit-loan, goal-driven-saving",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "account.withdraw"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_type": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "after": {
+                    "description": "snapshot after the action",
+                    "type": "object"
+                },
+                "audit_id": {
+                    "type": "integer"
+                },
+                "before": {
+                    "description": "snapshot before the action",
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "request_id": {
                     "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string",
+                    "example": "account"
                 }
             }
         },
-        "controllers.CreateAccount.createAccountRequest": {
+        "models.Banner": {
             "type": "object",
             "required": [
-                "account_number",
-                "currency",
-                "issuer",
-                "type"
+                "banner_id",
+                "user_id"
             ],
             "properties": {
-                "account_number": {
+                "banner_id": {
                     "type": "string"
                 },
-                "amount": {
-                    "type": "number"
+                "created_at": {
+                    "type": "string"
                 },
-                "color": {
+                "deleted_at": {
+                    "description": "for soft delete",
                     "type": "string"
                 },
-                "currency": {
+                "description": {
                     "type": "string"
                 },
-                "is_main_account": {
-                    "type": "boolean"
+                "image": {
+                    "type": "string"
                 },
-                "issuer": {
+                "title": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "saving-account",
-                        "credit-loan",
-                        "goal-driven-saving"
-                    ]
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.CreateDebitCard.createDebitCardRequest": {
+        "models.DebitCardWithDetails": {
             "type": "object",
-            "required": [
-                "issuer",
-                "name"
-            ],
             "properties": {
                 "border_color": {
                     "type": "string"
                 },
+                "card_id": {
+                    "description": "DebitCard fields",
+                    "type": "string"
+                },
                 "color": {
+                    "description": "DebitCardDesign fields",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
                     "type": "string"
                 },
                 "issuer": {
+                    "description": "DebitCardDetail fields",
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "DebitCardStatus fields",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.Deposit.depositRequest": {
+        "models.EventType": {
+            "type": "string",
+            "enum": [
+                "AccountCreated",
+                "FundsDeposited",
+                "FundsWithdrawn",
+                "TransferCompleted",
+                "CardStatusChanged",
+                "TransactionReversed"
+            ],
+            "x-enum-varnames": [
+                "EventAccountCreated",
+                "EventFundsDeposited",
+                "EventFundsWithdrawn",
+                "EventTransferCompleted",
+                "EventCardStatusChanged",
+                "EventTransactionReversed"
+            ]
+        },
+        "models.FXQuote": {
             "type": "object",
-            "required": [
-                "amount"
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "from_currency": {
+                    "type": "string"
+                },
+                "quote_id": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "units of to_currency bought by one unit of from_currency",
+                    "type": "string",
+                    "example": "36.50000000"
+                },
+                "source_amount": {
+                    "description": "debited from the source account",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "target_amount": {
+                    "description": "credited to the destination account",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "to_currency": {
+                    "type": "string"
+                },
+                "used_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HoldStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "captured",
+                "released",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "HoldCaptured": "a partial capture releases the rest of the hold"
+            },
+            "x-enum-varnames": [
+                "HoldActive",
+                "HoldCaptured",
+                "HoldReleased",
+                "HoldExpired"
+            ]
+        },
+        "models.InsufficientFundsPolicy": {
+            "type": "string",
+            "enum": [
+                "skip",
+                "retry"
             ],
+            "x-enum-comments": {
+                "RetryOnInsufficientFunds": "retry the occurrence with backoff",
+                "SkipOnInsufficientFunds": "record the occurrence as skipped and wait for the next one"
+            },
+            "x-enum-varnames": [
+                "SkipOnInsufficientFunds",
+                "RetryOnInsufficientFunds"
+            ]
+        },
+        "models.LimitWindow": {
+            "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "limit": {
+                    "$ref": "#/definitions/types.Money"
+                },
+                "remaining": {
+                    "$ref": "#/definitions/types.Money"
+                },
+                "resets_at": {
+                    "type": "string"
+                },
+                "used": {
+                    "$ref": "#/definitions/types.Money"
                 }
             }
         },
-        "controllers.GetUserGreeting.getUserGreetingResponse": {
+        "models.PinLockState": {
             "type": "object",
             "properties": {
-                "message": {
+                "failed_attempts": {
+                    "type": "integer"
+                },
+                "locked": {
+                    "type": "boolean"
+                },
+                "permanent": {
+                    "description": "the PIN has to be reset",
+                    "type": "boolean"
+                },
+                "retry_at": {
+                    "description": "when the next attempt is allowed, unless locked for good",
                     "type": "string"
                 }
             }
         },
-        "controllers.Transfer.transferRequest": {
+        "models.PostingDirection": {
+            "type": "string",
+            "enum": [
+                "debit",
+                "credit"
+            ],
+            "x-enum-comments": {
+                "Credit": "increases the account balance",
+                "Debit": "decreases the account balance"
+            },
+            "x-enum-varnames": [
+                "Debit",
+                "Credit"
+            ]
+        },
+        "models.Renew": {
             "type": "object",
-            "required": [
-                "amount",
-                "from_account_id",
-                "to_account_id"
+            "properties": {
+                "device_id": {
+                    "description": "device the refresh token was issued to",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReversalResult": {
+            "type": "object",
+            "properties": {
+                "original": {
+                    "$ref": "#/definitions/models.Transaction"
+                },
+                "reversals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "models.ScheduleExecutionStatus": {
+            "type": "string",
+            "enum": [
+                "succeeded",
+                "skipped",
+                "retrying",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ExecutionSucceeded",
+                "ExecutionSkipped",
+                "ExecutionRetrying",
+                "ExecutionFailed"
+            ]
+        },
+        "models.ScheduleFrequency": {
+            "type": "string",
+            "enum": [
+                "once",
+                "daily",
+                "weekly",
+                "monthly"
+            ],
+            "x-enum-comments": {
+                "FrequencyMonthly": "runs on the day of month of start_at, clamped to the last day of shorter months"
+            },
+            "x-enum-varnames": [
+                "FrequencyOnce",
+                "FrequencyDaily",
+                "FrequencyWeekly",
+                "FrequencyMonthly"
+            ]
+        },
+        "models.ScheduleStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "paused",
+                "completed",
+                "cancelled"
             ],
+            "x-enum-varnames": [
+                "ScheduleActive",
+                "SchedulePaused",
+                "ScheduleCompleted",
+                "ScheduleCancelled"
+            ]
+        },
+        "models.ScheduledTransfer": {
+            "type": "object",
             "properties": {
                 "amount": {
-                    "type": "number"
+                    "description": "currency is stored in the currency column",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "for soft delete",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "description": "once, daily, weekly, monthly",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScheduleFrequency"
+                        }
+                    ]
                 },
                 "from_account_id": {
                     "type": "string"
                 },
+                "insufficient_funds_policy": {
+                    "$ref": "#/definitions/models.InsufficientFundsPolicy"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "next_run_at delayed by retry backoff",
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "description": "occurrence the next execution is for",
+                    "type": "string"
+                },
+                "retry_count": {
+                    "type": "integer"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active, paused, completed, cancelled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScheduleStatus"
+                        }
+                    ]
+                },
                 "to_account_id": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.UpdateAccount.updateAccountRequest": {
+        "models.ScheduledTransferExecution": {
             "type": "object",
             "properties": {
-                "account_number": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "color": {
+                "executed_at": {
                     "type": "string"
                 },
-                "currency": {
+                "execution_id": {
                     "type": "string"
                 },
-                "issuer": {
+                "message": {
                     "type": "string"
                 },
-                "progress": {
-                    "type": "integer",
-                    "maximum": 100,
-                    "minimum": 0
+                "schedule_id": {
+                    "type": "string"
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "saving-account",
-                        "credit-loan",
-                        "goal-driven-saving"
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "succeeded, skipped, retrying, failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScheduleExecutionStatus"
+                        }
                     ]
                 }
             }
         },
-        "controllers.UpdateDebitCard.updateDebitCardRequest": {
+        "models.Session": {
             "type": "object",
             "properties": {
-                "border_color": {
+                "created_at": {
                     "type": "string"
                 },
-                "color": {
+                "current": {
+                    "description": "whether the listing was requested from this session",
+                    "type": "boolean"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "device_name": {
+                    "type": "string",
+                    "example": "Jane's iPhone"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "description": "last sign-in or token renewal",
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StatementLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/types.Money"
+                },
+                "balance": {
+                    "description": "running balance after the transaction",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PostingDirection"
+                        }
+                    ],
+                    "example": "debit"
+                },
+                "transaction_id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "transaction_type": {
+                    "type": "string",
+                    "example": "transfer"
                 }
             }
         },
-        "controllers.UpdateUserGreeting.updateUserGreetingRequest": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
-                }
-            }
+        "models.StreamEvent": {
+            "type": "string",
+            "enum": [
+                "balance",
+                "transaction",
+                "reset"
+            ],
+            "x-enum-comments": {
+                "StreamBalance": "the balance of an account changed, data is a BalanceUpdate",
+                "StreamReset": "messages since the last event id are no longer kept, the client reloads its accounts",
+                "StreamTransaction": "a transaction was posted, data is a TransactionNotice"
+            },
+            "x-enum-varnames": [
+                "StreamBalance",
+                "StreamTransaction",
+                "StreamReset"
+            ]
         },
-        "controllers.UpdateUserGreeting.updateUserGreetingResponse": {
+        "models.StreamMessage": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "data": {
+                    "type": "object"
+                },
+                "event": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.StreamEvent"
+                        }
+                    ],
+                    "example": "balance"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1760000000000-0"
                 }
             }
         },
-        "controllers.VerifyPin.verifyPinRequest": {
+        "models.TOTPEnrollment": {
             "type": "object",
             "properties": {
-                "pin": {
+                "secret": {
                     "type": "string"
                 },
-                "user_id": {
+                "uri": {
+                    "description": "otpauth:// URI, usually shown as a QR code",
                     "type": "string"
                 }
             }
         },
-        "controllers.Withdraw.withdrawRequest": {
-            "type": "object",
-            "required": [
-                "amount"
-            ],
-            "properties": {
-                "amount": {
-                    "type": "number"
-                }
-            }
-        },
-        "models.AccountFlag": {
+        "models.Transaction": {
             "type": "object",
             "required": [
                 "account_id",
-                "flag_type",
-                "flag_value",
+                "amount",
+                "transaction_id",
+                "transaction_type",
                 "user_id"
             ],
             "properties": {
                 "account_id": {
                     "type": "string"
                 },
+                "amount": {
+                    "description": "currency is stored in the currency column",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -1100,69 +4992,52 @@ const docTemplate = `{
                     "description": "for soft delete",
                     "type": "string"
                 },
-                "flag_id": {
-                    "type": "integer"
-                },
-                "flag_type": {
-                    "description": "system and user",
-                    "type": "string"
-                },
-                "flag_value": {
-                    "type": "string"
+                "direction": {
+                    "description": "debit decreases the account balance, credit increases it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PostingDirection"
+                        }
+                    ]
                 },
-                "updated_at": {
+                "exchange_rate": {
+                    "description": "rate applied to a cross-currency transfer, source to destination currency",
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.AccountWithDetails": {
-            "type": "object",
-            "properties": {
-                "account_id": {
-                    "description": "Account fields",
+                "fx_quote_id": {
+                    "description": "quote a cross-currency transfer was executed at",
                     "type": "string"
                 },
-                "account_number": {
+                "image": {
                     "type": "string"
                 },
-                "amount": {
-                    "description": "AccountBalance fields",
-                    "type": "number"
-                },
-                "color": {
-                    "description": "AccountDetail fields",
-                    "type": "string"
+                "is_bank": {
+                    "type": "boolean"
                 },
-                "created_at": {
+                "linked_transaction_id": {
+                    "description": "other leg of a transfer",
                     "type": "string"
                 },
-                "currency": {
+                "name": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "reversal_of": {
+                    "description": "transaction this one reverses",
                     "type": "string"
                 },
-                "flags": {
-                    "description": "AccountFlags",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.AccountFlag"
-                    }
-                },
-                "is_main_account": {
-                    "type": "boolean"
+                "reversed_amount": {
+                    "description": "total refunded so far",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Money"
+                        }
+                    ]
                 },
-                "issuer": {
+                "transaction_id": {
                     "type": "string"
                 },
-                "progress": {
-                    "type": "integer"
-                },
-                "type": {
-                    "description": "saving-account, credit-loan, goal-driven-saving",
+                "transaction_type": {
+                    "description": "deposit, withdrawal, transfer, reversal",
                     "type": "string"
                 },
                 "updated_at": {
@@ -1173,16 +5048,13 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Banner": {
+        "models.User": {
             "type": "object",
             "required": [
-                "banner_id",
+                "name",
                 "user_id"
             ],
             "properties": {
-                "banner_id": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
@@ -1190,13 +5062,7 @@ const docTemplate = `{
                     "description": "for soft delete",
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
-                },
-                "image": {
-                    "type": "string"
-                },
-                "title": {
+                "name": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -1207,124 +5073,267 @@ const docTemplate = `{
                 }
             }
         },
-        "models.DebitCardWithDetails": {
+        "models.UserOverview": {
             "type": "object",
             "properties": {
-                "border_color": {
-                    "type": "string"
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AccountWithDetails"
+                    }
                 },
-                "card_id": {
-                    "description": "DebitCard fields",
-                    "type": "string"
+                "debit_cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DebitCardWithDetails"
+                    }
                 },
-                "color": {
-                    "description": "DebitCardDesign fields",
-                    "type": "string"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Role"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "issuer": {
-                    "description": "DebitCardDetail fields",
+                "delivered_at": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "delivery_id": {
+                    "type": "integer"
                 },
-                "number": {
+                "endpoint_id": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "DebitCardStatus fields",
+                "event_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "event_type": {
+                    "$ref": "#/definitions/models.EventType"
+                },
+                "last_error": {
                     "type": "string"
                 },
-                "user_id": {
+                "last_status_code": {
+                    "description": "0 when no response was received",
+                    "type": "integer"
+                },
+                "next_attempt_at": {
                     "type": "string"
+                },
+                "payload": {
+                    "description": "the body sent to the endpoint",
+                    "type": "object"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WebhookDeliveryStatus"
+                        }
+                    ],
+                    "example": "delivered"
                 }
             }
         },
-        "models.Renew": {
+        "models.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "delivered",
+                "dead"
+            ],
+            "x-enum-comments": {
+                "WebhookDeliveryDead": "every attempt failed, it is only sent again when replayed",
+                "WebhookDeliveryDelivered": "the endpoint answered with a 2xx status",
+                "WebhookDeliveryPending": "waiting for its next attempt"
+            },
+            "x-enum-varnames": [
+                "WebhookDeliveryPending",
+                "WebhookDeliveryDelivered",
+                "WebhookDeliveryDead"
+            ]
+        },
+        "models.WebhookEndpoint": {
             "type": "object",
             "properties": {
-                "refresh_token": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endpoint_id": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TransferCompleted",
+                        "CardStatusChanged"
+                    ]
+                },
+                "scope": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WebhookScope"
+                        }
+                    ],
+                    "example": "user"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/webhooks"
+                },
+                "user_id": {
+                    "description": "the user or staff member who registered it",
                     "type": "string"
                 }
             }
         },
-        "models.Transaction": {
+        "models.WebhookEndpointWithSecret": {
             "type": "object",
-            "required": [
-                "account_id",
-                "amount",
-                "transaction_id",
-                "transaction_type",
-                "user_id"
-            ],
             "properties": {
-                "account_id": {
-                    "type": "string"
-                },
-                "amount": {
-                    "type": "number"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "description": "for soft delete",
+                "description": {
                     "type": "string"
                 },
-                "image": {
+                "endpoint_id": {
                     "type": "string"
                 },
-                "is_bank": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TransferCompleted",
+                        "CardStatusChanged"
+                    ]
                 },
-                "transaction_id": {
-                    "type": "string"
+                "scope": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WebhookScope"
+                        }
+                    ],
+                    "example": "user"
                 },
-                "transaction_type": {
-                    "description": "deposit, withdrawal, transfer",
-                    "type": "string"
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_5f3a..."
                 },
-                "updated_at": {
-                    "type": "string"
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/webhooks"
                 },
                 "user_id": {
+                    "description": "the user or staff member who registered it",
                     "type": "string"
                 }
             }
         },
-        "models.User": {
-            "type": "object",
-            "required": [
-                "name",
-                "user_id"
+        "models.WebhookScope": {
+            "type": "string",
+            "enum": [
+                "user",
+                "all"
             ],
+            "x-enum-comments": {
+                "WebhookScopeAll": "events of every user, endpoints registered by staff",
+                "WebhookScopeUser": "events concerning the user owning the endpoint"
+            },
+            "x-enum-varnames": [
+                "WebhookScopeUser",
+                "WebhookScopeAll"
+            ]
+        },
+        "types.Money": {
+            "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "amount": {
+                    "type": "integer"
                 },
-                "deleted_at": {
-                    "description": "for soft delete",
+                "currency": {
                     "type": "string"
+                }
+            }
+        },
+        "types.Role": {
+            "type": "string",
+            "enum": [
+                "support",
+                "operations",
+                "marketing",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "RoleSupport",
+                "RoleOperations",
+                "RoleMarketing",
+                "RoleAdmin"
+            ]
+        },
+        "utils.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "EdDSA"
                 },
-                "name": {
+                "crv": {
+                    "type": "string",
+                    "example": "Ed25519"
+                },
+                "e": {
                     "type": "string"
                 },
-                "updated_at": {
+                "kid": {
+                    "type": "string",
+                    "example": "2026-10"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "OKP"
+                },
+                "n": {
                     "type": "string"
                 },
-                "user_id": {
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                },
+                "x": {
                     "type": "string"
                 }
             }
+        },
+        "utils.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.JWK"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
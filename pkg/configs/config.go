@@ -1,8 +1,141 @@
 package configs
 
+import "time"
+
 const (
 	DEFAULT_PAGE_SIZE               = 10
+	MAX_PAGE_SIZE                   = 100
 	DEFAULT_DEBIT_CARD_COLOR        = "#ffffff"
 	DEFAULT_DEBIT_CARD_BORDER_COLOR = "#ffffff"
 	DEFAULT_ACCOUNT_COLOR           = "#ffffff"
 )
+
+// Scheduled transfer executor settings
+const (
+	SCHEDULER_POLL_INTERVAL      = 30 * time.Second
+	SCHEDULER_BATCH_SIZE         = 50
+	SCHEDULER_LEASE_DURATION     = 5 * time.Minute // must comfortably exceed the time to execute one batch
+	SCHEDULER_MAX_RETRIES        = 5
+	SCHEDULER_RETRY_BASE_BACKOFF = time.Minute // doubled on every retry
+	SCHEDULER_RETRY_MAX_BACKOFF  = time.Hour
+	SCHEDULE_EXECUTIONS_LIMIT    = 50
+)
+
+// FX_QUOTE_TTL is how long the rate of an fx quote is guaranteed
+const FX_QUOTE_TTL = 60 * time.Second
+
+// LIMIT_WINDOW_LOCATION is the time zone daily and monthly transfer limits reset in (Asia/Bangkok, no daylight saving)
+var LIMIT_WINDOW_LOCATION = time.FixedZone("ICT", 7*60*60)
+
+// Account hold settings
+const (
+	HOLD_DEFAULT_TTL     = 7 * 24 * time.Hour // card authorizations usually settle within a week
+	HOLD_MAX_TTL         = 30 * 24 * time.Hour
+	HOLD_EXPIRY_INTERVAL = time.Minute
+)
+
+// REFRESH_TOKEN_PURGE_INTERVAL is how often refresh tokens past their expiry are deleted
+const REFRESH_TOKEN_PURGE_INTERVAL = time.Hour
+
+// LEDGER_CHECK_INTERVAL is how often the ledger is checked to balance, debits against credits in every currency
+const LEDGER_CHECK_INTERVAL = time.Hour
+
+// PIN brute-force protection. Consecutive failures of a user back off exponentially, lock the PIN temporarily
+// from PIN_LOCKOUT_THRESHOLD on and for good at PIN_PERMANENT_LOCK_THRESHOLD. Failures from one IP address,
+// across users, are throttled per fixed window.
+const (
+	PIN_BACKOFF_BASE             = time.Second // doubled on every failure
+	PIN_BACKOFF_MAX              = time.Minute
+	PIN_LOCKOUT_THRESHOLD        = 5
+	PIN_LOCKOUT_DURATION         = 15 * time.Minute
+	PIN_PERMANENT_LOCK_THRESHOLD = 10
+	PIN_IP_MAX_FAILURES          = 20
+	PIN_IP_WINDOW                = 15 * time.Minute
+	PIN_LOCKOUT_CACHE_TTL        = 10 * time.Minute
+)
+
+// PIN policy and PIN reset settings
+const (
+	PIN_LENGTH                 = 6
+	PIN_MAX_DIGIT_RUN          = 2 // longest run of the same digit (11) or of sequential digits (12, 21)
+	PIN_HISTORY_SIZE           = 5 // a new PIN may not be the current PIN or one of the 4 PINs before it
+	PIN_RESET_CODE_TTL         = 10 * time.Minute
+	PIN_RESET_MAX_ATTEMPTS     = 5
+	PIN_RESET_REQUEST_INTERVAL = time.Minute
+)
+
+// Step-up authentication. Transfers above the threshold of the source currency wait for a challenge to be
+// confirmed with the PIN or a TOTP code, transfers from a currency without a threshold always do.
+const (
+	CHALLENGE_TTL            = 5 * time.Minute
+	CHALLENGE_MAX_ATTEMPTS   = 3
+	CHALLENGE_PURGE_INTERVAL = time.Hour
+	TOTP_SKEW_STEPS          = 1 // codes of the previous and the next 30 second step are accepted for clock drift
+	TOTP_ISSUER              = "Backend Developer Assignment"
+)
+
+// STEP_UP_THRESHOLDS are the default transfer thresholds per currency, STEP_UP_THRESHOLDS in the environment
+// (e.g. "THB:50000,USD:1500") replaces them
+var STEP_UP_THRESHOLDS = map[string]string{
+	"THB": "50000.00",
+	"USD": "1500.00",
+	"EUR": "1400.00",
+}
+
+// JWT keyring settings. The keyring file is reloaded so that keys added to it are used without a restart,
+// verifiers may cache the JWKS document as long, so publish a key at least that long before it signs.
+const (
+	JWT_KEYRING_RELOAD_INTERVAL = 5 * time.Minute
+	JWKS_MAX_AGE                = 5 * time.Minute
+)
+
+// Outbox relay settings. The relay publishes outbox events every OUTBOX_RELAY_INTERVAL from the instance holding
+// the relay lease, published events are deleted after OUTBOX_RETENTION.
+const (
+	OUTBOX_RELAY_INTERVAL  = time.Second
+	OUTBOX_BATCH_SIZE      = 100
+	OUTBOX_LEASE_DURATION  = 30 * time.Second // must comfortably exceed the time to publish one batch
+	OUTBOX_RETENTION       = 7 * 24 * time.Hour
+	OUTBOX_PURGE_INTERVAL  = time.Hour
+	OUTBOX_STREAM_NAME     = "domain-events"
+	OUTBOX_STREAM_MAX_SIZE = 100000
+)
+
+// Real-time stream settings. The last STREAM_HISTORY_SIZE messages of a user are kept for STREAM_HISTORY_TTL so that
+// a client reconnecting with its last event id misses nothing, older gaps make the client reload instead.
+const (
+	STREAM_HISTORY_SIZE       = 200
+	STREAM_HISTORY_TTL        = 24 * time.Hour
+	STREAM_BUFFER_SIZE        = 64 // messages a slow client may fall behind before it is disconnected to resume later
+	STREAM_HEARTBEAT_INTERVAL = 15 * time.Second
+	STREAM_CHANNEL            = "stream-messages" // Redis pub/sub channel fanning messages out to every instance
+)
+
+// In-process cache settings. CACHE_MODE=memory caches in the process alone, CACHE_MODE=tiered keeps local copies of
+// the values read from Redis for up to CACHE_LOCAL_TTL, dropped on every instance when the key is written.
+const (
+	CACHE_LOCAL_MAX_ENTRIES    = 10000
+	CACHE_LOCAL_TTL            = 30 * time.Second    // longest a copy may stay stale when an invalidation is lost
+	CACHE_INVALIDATION_CHANNEL = "cache-invalidations" // Redis pub/sub channel broadcasting the keys written
+)
+
+// Webhook delivery settings. A failed attempt is retried after WEBHOOK_RETRY_BASE, doubling up to
+// WEBHOOK_RETRY_MAX, the delivery dies after WEBHOOK_MAX_ATTEMPTS attempts.
+const (
+	WEBHOOK_DELIVERY_INTERVAL = 5 * time.Second
+	WEBHOOK_BATCH_SIZE        = 50
+	WEBHOOK_CONCURRENCY       = 10
+	WEBHOOK_TIMEOUT           = 10 * time.Second
+	WEBHOOK_LEASE_DURATION    = 2 * time.Minute // must comfortably exceed the time to attempt one batch
+	WEBHOOK_MAX_ATTEMPTS      = 8
+	WEBHOOK_RETRY_BASE        = 30 * time.Second
+	WEBHOOK_RETRY_MAX         = 6 * time.Hour
+	WEBHOOK_SIGNATURE_MAX_AGE = 5 * time.Minute // receivers reject older signatures against replays
+)
+
+// STATEMENT_PAGE_SIZE is how many transactions an account statement reads at a time, a statement of any length is
+// written without holding more than a page in memory
+const STATEMENT_PAGE_SIZE = 500
+
+// STATEMENT_LOCATION is the time zone the days of an account statement run in (Asia/Bangkok, no daylight saving)
+var STATEMENT_LOCATION = time.FixedZone("ICT", 7*60*60)
@@ -1,7 +1,9 @@
 package configs
 
 import (
+	"backend-developer-assignment/pkg/types"
 	"backend-developer-assignment/platform/cache"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -28,4 +30,27 @@ func RedisConnection() *cache.RedisClient {
 
 	// Create and return Redis client
 	return cache.NewRedisClient(redisAddr, redisPassword, redisDB)
-}
\ No newline at end of file
+}
+
+// CacheConnection selects the cache named by CACHE_MODE: redis (the default), memory for a single instance without
+// Redis, or tiered to keep local copies of the values read from Redis. The returned function stops the cache.
+// Production runs several instances, which the memory cache would split into caches and counters of their own.
+func CacheConnection(redisClient *cache.RedisClient) (types.CacheClient, func(), error) {
+	switch mode := os.Getenv("CACHE_MODE"); mode {
+	case "", "redis":
+		return redisClient, func() {}, nil
+	case "memory":
+		if os.Getenv("APP_ENV") == "prod" {
+			return nil, nil, errors.New("CACHE_MODE=memory is not allowed in production")
+		}
+		return cache.NewMemoryClient(CACHE_LOCAL_MAX_ENTRIES), func() {}, nil
+	case "tiered":
+		tieredClient, err := cache.NewTieredClient(cache.NewMemoryClient(CACHE_LOCAL_MAX_ENTRIES), redisClient, CACHE_INVALIDATION_CHANNEL, CACHE_LOCAL_TTL)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tieredClient, func() { tieredClient.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown cache mode %q", mode)
+	}
+}
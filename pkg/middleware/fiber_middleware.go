@@ -8,6 +8,7 @@ import (
 	fiber "github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"go.uber.org/zap"
 )
 
@@ -19,12 +20,18 @@ func FiberMiddleware(a *fiber.App) {
 	a.Use(
 		// Add CORS to each route.
 		cors.New(),
+		// Request ID, kept from the X-Request-ID header or generated, ties log lines and audit logs together
+		requestid.New(),
 		// logger
 		fiberzap.New(fiberzap.Config{
 			Logger: GetLogger(),
+			Fields: []string{"ip", "latency", "status", "method", "url", "requestId"},
 		}),
-		// Compression
+		// Compression, except for Server-Sent Events which are flushed one by one
 		compress.New(compress.Config{
+			Next: func(c *fiber.Ctx) bool {
+				return c.Get(fiber.HeaderAccept) == "text/event-stream"
+			},
 			Level: compress.LevelBestSpeed,
 		}),
 	)
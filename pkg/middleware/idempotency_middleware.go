@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"backend-developer-assignment/pkg/base"
+	"backend-developer-assignment/pkg/types"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client generated key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyStore persists idempotency keys and the responses they produced
+type IdempotencyStore interface {
+	Begin(userID, key, requestHash string) (*types.IdempotentResponse, error)
+	Complete(userID, key string, response *types.IdempotentResponse) error
+	Release(userID, key string) error
+}
+
+// Idempotency makes a route safe to retry with the same Idempotency-Key header.
+// The first request is processed and its response stored, repeats replay that response,
+// and the same key with a different request is rejected. Requests without the header are processed as usual.
+// This middleware should be used after ExtractJwtClaim middleware since keys are scoped per user.
+func Idempotency(store IdempotencyStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(base.ErrorResponse{
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		userID, _ := c.Locals("userID").(string)
+		requestHash := hashRequest(c)
+
+		stored, err := store.Begin(userID, key, requestHash)
+		switch {
+		case errors.Is(err, types.ErrIdempotencyKeyReused):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(base.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, types.ErrIdempotencyKeyInProgress):
+			return c.Status(fiber.StatusConflict).JSON(base.ErrorResponse{Message: err.Error()})
+		case err != nil:
+			return c.Status(fiber.StatusInternalServerError).JSON(base.ErrorResponse{Message: "Failed to process idempotency key"})
+		}
+
+		if stored != nil {
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(stored.StatusCode).Send(stored.Body)
+		}
+
+		if err := c.Next(); err != nil {
+			releaseIdempotencyKey(store, userID, key)
+			return err
+		}
+
+		// Server errors are not final, the client is allowed to retry them with the same key
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			releaseIdempotencyKey(store, userID, key)
+			return nil
+		}
+
+		response := &types.IdempotentResponse{
+			StatusCode: status,
+			Body:       append([]byte(nil), c.Response().Body()...),
+		}
+		if err := store.Complete(userID, key, response); err != nil {
+			GetLogger().Error("Failed to store idempotent response", zap.String("user_id", userID), zap.Error(err))
+		}
+
+		return nil
+	}
+}
+
+// hashRequest fingerprints the method, path and body so a key cannot be reused for another request
+func hashRequest(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Path()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func releaseIdempotencyKey(store IdempotencyStore, userID, key string) {
+	if err := store.Release(userID, key); err != nil {
+		GetLogger().Error("Failed to release idempotency key", zap.String("user_id", userID), zap.Error(err))
+	}
+}
@@ -3,18 +3,23 @@ package middleware
 import (
 	"backend-developer-assignment/pkg/base"
 	"backend-developer-assignment/pkg/utils"
-	"os"
 
 	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 
 	jwtMiddleware "github.com/gofiber/contrib/jwt"
 )
 
+// SessionRevocationList tells whether the session of an access token was signed out
+type SessionRevocationList interface {
+	IsSessionRevoked(sessionID string) (bool, error)
+}
+
 // AuthProtected combines JWT protection and user ID extraction in a single middleware chain
-func AuthProtected() []fiber.Handler {
+func AuthProtected(sessions SessionRevocationList) []fiber.Handler {
 	return []fiber.Handler{
 		JWTProtected(),
-		ExtractJwtClaim(),
+		ExtractJwtClaim(sessions),
 	}
 }
 
@@ -23,17 +28,18 @@ func AuthProtected() []fiber.Handler {
 func JWTProtected() func(*fiber.Ctx) error {
 	// Create config for JWT authentication middleware.
 	config := jwtMiddleware.Config{
-		SigningKey:   jwtMiddleware.SigningKey{Key: []byte(os.Getenv("JWT_SECRET_KEY"))},
-		ContextKey:   "jwt", // used in private routes
+		KeyFunc:      utils.JWTKeyFunc, // public key of the kid in the token header
+		ContextKey:   "jwt",            // used in private routes
 		ErrorHandler: jwtError,
 	}
 
 	return jwtMiddleware.New(config)
 }
 
-// ExtractJwtClaim middleware extracts the user ID from JWT token and stores it in context
+// ExtractJwtClaim middleware extracts the user ID from JWT token and stores it in context, tokens of a revoked
+// session are rejected although they have not expired yet.
 // This middleware should be used after JWTProtected middleware
-func ExtractJwtClaim() fiber.Handler {
+func ExtractJwtClaim(sessions SessionRevocationList) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Use the utils package to verify and extract token metadata
 		tokenMetadata, err := utils.ExtractTokenMetadata(c)
@@ -43,8 +49,28 @@ func ExtractJwtClaim() fiber.Handler {
 			})
 		}
 
+		// Reject tokens of a session signed out from another device
+		if tokenMetadata.SessionID != "" {
+			revoked, err := sessions.IsSessionRevoked(tokenMetadata.SessionID)
+			if err != nil {
+				GetLogger().Error("Failed to check session revocation", zap.String("session_id", tokenMetadata.SessionID), zap.Error(err))
+				return c.Status(fiber.StatusServiceUnavailable).JSON(base.ErrorResponse{
+					Message: "Failed to check session",
+				})
+			}
+			if revoked {
+				return c.Status(fiber.StatusUnauthorized).JSON(base.ErrorResponse{
+					Message: "Invalid token: session is signed out",
+				})
+			}
+		}
+
 		// Store user ID in context for later use in controllers
 		c.Locals("userID", tokenMetadata.UserID)
+		// Store the session for the session listing and sign-out
+		c.Locals("sessionID", tokenMetadata.SessionID)
+		// Store the permissions for RequirePermission
+		c.Locals("permissions", tokenMetadata.Permissions)
 
 		// Continue to the next middleware/handler
 		return c.Next()
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"backend-developer-assignment/pkg/base"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// OwnerLookup returns the id of the user owning a resource, or an empty string when the resource does not exist
+type OwnerLookup func(resourceID string) (string, error)
+
+// Owned lets a request through only when the resource named by the route parameter belongs to the authenticated
+// user. Resources of other users are reported as not found, like missing ones, so their ids cannot be probed.
+// This middleware should be used after ExtractJwtClaim middleware and before Idempotency.
+func Owned(param, notFoundMessage string, lookup OwnerLookup) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("userID").(string)
+		resourceID := c.Params(param)
+
+		ownerID, err := lookup(resourceID)
+		if err != nil {
+			GetLogger().Error("Failed to look up resource owner", zap.String("resource_id", resourceID), zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(base.ErrorResponse{Message: "Failed to authorize request"})
+		}
+
+		if userID == "" || ownerID != userID {
+			return c.Status(fiber.StatusNotFound).JSON(base.ErrorResponse{Message: notFoundMessage})
+		}
+
+		return c.Next()
+	}
+}
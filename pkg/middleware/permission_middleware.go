@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"backend-developer-assignment/pkg/base"
+	"backend-developer-assignment/pkg/types"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// RequirePermission lets a request through only when the access token grants the permission, the permissions
+// come from the roles of the user when the token was issued.
+// This middleware should be used after ExtractJwtClaim middleware.
+func RequirePermission(permission types.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		permissions, _ := c.Locals("permissions").([]types.Permission)
+		for _, granted := range permissions {
+			if granted == permission {
+				return c.Next()
+			}
+		}
+
+		userID, _ := c.Locals("userID").(string)
+		GetLogger().Warn("Permission denied", zap.String("user_id", userID), zap.String("permission", string(permission)),
+			zap.String("path", c.Path()))
+		return c.Status(fiber.StatusForbidden).JSON(base.ErrorResponse{Message: "Missing permission " + string(permission)})
+	}
+}
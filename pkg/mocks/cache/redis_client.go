@@ -28,4 +28,28 @@ func (m *RedisClient) Set(ctx context.Context, key string, value interface{}, ex
 func (m *RedisClient) Delete(ctx context.Context, key string) error {
 	args := m.Called(ctx, key)
 	return args.Error(0)
-}
\ No newline at end of file
+}
+
+// Increment mocks the Increment method
+func (m *RedisClient) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	args := m.Called(ctx, key, expiration)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Decrement mocks the Decrement method
+func (m *RedisClient) Decrement(ctx context.Context, key string) (int64, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// NamespaceVersion mocks the NamespaceVersion method
+func (m *RedisClient) NamespaceVersion(ctx context.Context, namespace string) (string, error) {
+	args := m.Called(ctx, namespace)
+	return args.String(0), args.Error(1)
+}
+
+// InvalidateNamespace mocks the InvalidateNamespace method
+func (m *RedisClient) InvalidateNamespace(ctx context.Context, namespace string) error {
+	args := m.Called(ctx, namespace)
+	return args.Error(0)
+}
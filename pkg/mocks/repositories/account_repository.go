@@ -51,6 +51,34 @@ func (_m *AccountRepository) DeleteAccount(accountID string) error {
 	return r0
 }
 
+// FreezeAccount provides a mock function with given fields: accountID, userID
+func (_m *AccountRepository) FreezeAccount(accountID string, userID string) (bool, error) {
+	ret := _m.Called(accountID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FreezeAccount")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+		return rf(accountID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(accountID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(accountID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAccountBalanceByID provides a mock function with given fields: accountID
 func (_m *AccountRepository) GetAccountBalanceByID(accountID string) (*models.AccountBalance, error) {
 	ret := _m.Called(accountID)
@@ -280,7 +308,7 @@ func (_m *AccountRepository) SetMainAccount(accountID string, userID string) err
 }
 
 // TransferFunds provides a mock function with given fields: fromAccountID, toAccountID, amount, updateFn
-func (_m *AccountRepository) TransferFunds(fromAccountID string, toAccountID string, amount float64, updateFn func(float64, float64) (*types.TransferResult, error)) error {
+func (_m *AccountRepository) TransferFunds(fromAccountID string, toAccountID string, amount types.Money, updateFn func(types.Money, types.Money) (*types.TransferResult, error)) error {
 	ret := _m.Called(fromAccountID, toAccountID, amount, updateFn)
 
 	if len(ret) == 0 {
@@ -288,7 +316,7 @@ func (_m *AccountRepository) TransferFunds(fromAccountID string, toAccountID str
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, float64, func(float64, float64) (*types.TransferResult, error)) error); ok {
+	if rf, ok := ret.Get(0).(func(string, string, types.Money, func(types.Money, types.Money) (*types.TransferResult, error)) error); ok {
 		r0 = rf(fromAccountID, toAccountID, amount, updateFn)
 	} else {
 		r0 = ret.Error(0)
@@ -315,6 +343,34 @@ func (_m *AccountRepository) UnSetMainAccount(userID string) error {
 	return r0
 }
 
+// UnfreezeAccount provides a mock function with given fields: accountID
+func (_m *AccountRepository) UnfreezeAccount(accountID string) (bool, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnfreezeAccount")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(accountID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateAccount provides a mock function with given fields: account
 func (_m *AccountRepository) UpdateAccount(account *models.Account) error {
 	ret := _m.Called(account)
@@ -334,7 +390,7 @@ func (_m *AccountRepository) UpdateAccount(account *models.Account) error {
 }
 
 // UpdateAccountBalance provides a mock function with given fields: accountID, updateFn
-func (_m *AccountRepository) UpdateAccountBalance(accountID string, updateFn func(float64) (float64, error)) error {
+func (_m *AccountRepository) UpdateAccountBalance(accountID string, updateFn func(types.Money) (types.Money, error)) error {
 	ret := _m.Called(accountID, updateFn)
 
 	if len(ret) == 0 {
@@ -342,7 +398,7 @@ func (_m *AccountRepository) UpdateAccountBalance(accountID string, updateFn fun
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, func(float64) (float64, error)) error); ok {
+	if rf, ok := ret.Get(0).(func(string, func(types.Money) (types.Money, error)) error); ok {
 		r0 = rf(accountID, updateFn)
 	} else {
 		r0 = ret.Error(0)
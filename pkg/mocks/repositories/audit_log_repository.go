@@ -0,0 +1,76 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditLogRepository is an autogenerated mock type for the AuditLogRepository type
+type AuditLogRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: entry
+func (_m *AuditLogRepository) Create(entry *models.AuditLog) error {
+	ret := _m.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.AuditLog) error); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: filter
+func (_m *AuditLogRepository) List(filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*models.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.AuditLogFilter) ([]*models.AuditLog, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.AuditLogFilter) []*models.AuditLog); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.AuditLogFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuditLogRepository creates a new instance of AuditLogRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditLogRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditLogRepository {
+	mock := &AuditLogRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -13,6 +13,52 @@ type BannerRepository struct {
 	mock.Mock
 }
 
+// CreateBanner provides a mock function with given fields: banner
+func (_m *BannerRepository) CreateBanner(banner *models.Banner) error {
+	ret := _m.Called(banner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBanner")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.Banner) error); ok {
+		r0 = rf(banner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBanner provides a mock function with given fields: bannerID
+func (_m *BannerRepository) DeleteBanner(bannerID string) (bool, error) {
+	ret := _m.Called(bannerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBanner")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(bannerID)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(bannerID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bannerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBannerByID provides a mock function with given fields: bannerID
 func (_m *BannerRepository) GetBannerByID(bannerID string) (*models.Banner, error) {
 	ret := _m.Called(bannerID)
@@ -73,6 +119,24 @@ func (_m *BannerRepository) GetBannersByUserID(userID string) ([]*models.Banner,
 	return r0, r1
 }
 
+// UpdateBanner provides a mock function with given fields: banner
+func (_m *BannerRepository) UpdateBanner(banner *models.Banner) error {
+	ret := _m.Called(banner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBanner")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.Banner) error); ok {
+		r0 = rf(banner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewBannerRepository creates a new instance of BannerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBannerRepository(t interface {
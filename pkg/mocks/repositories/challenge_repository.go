@@ -0,0 +1,152 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ChallengeRepository is an autogenerated mock type for the ChallengeRepository type
+type ChallengeRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: challenge
+func (_m *ChallengeRepository) Create(challenge *models.Challenge) error {
+	ret := _m.Called(challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.Challenge) error); ok {
+		r0 = rf(challenge)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *ChallengeRepository) DeleteExpired(now time.Time) (int64, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: challengeID
+func (_m *ChallengeRepository) GetByID(challengeID string) (*models.Challenge, error) {
+	ret := _m.Called(challengeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *models.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.Challenge, error)); ok {
+		return rf(challengeID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.Challenge); ok {
+		r0 = rf(challengeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(challengeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementAttempts provides a mock function with given fields: challengeID
+func (_m *ChallengeRepository) IncrementAttempts(challengeID string) error {
+	ret := _m.Called(challengeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementAttempts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(challengeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkConfirmed provides a mock function with given fields: challengeID, now, maxAttempts
+func (_m *ChallengeRepository) MarkConfirmed(challengeID string, now time.Time, maxAttempts int) (bool, error) {
+	ret := _m.Called(challengeID, now, maxAttempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkConfirmed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, int) (bool, error)); ok {
+		return rf(challengeID, now, maxAttempts)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, int) bool); ok {
+		r0 = rf(challengeID, now, maxAttempts)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, int) error); ok {
+		r1 = rf(challengeID, now, maxAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewChallengeRepository creates a new instance of ChallengeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewChallengeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ChallengeRepository {
+	mock := &ChallengeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,126 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// FXRepository is an autogenerated mock type for the FXRepository type
+type FXRepository struct {
+	mock.Mock
+}
+
+// CreateQuote provides a mock function with given fields: quote
+func (_m *FXRepository) CreateQuote(quote *models.FXQuote) error {
+	ret := _m.Called(quote)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQuote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.FXQuote) error); ok {
+		r0 = rf(quote)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetQuoteByIDForUpdate provides a mock function with given fields: quoteID
+func (_m *FXRepository) GetQuoteByIDForUpdate(quoteID string) (*models.FXQuote, error) {
+	ret := _m.Called(quoteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQuoteByIDForUpdate")
+	}
+
+	var r0 *models.FXQuote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.FXQuote, error)); ok {
+		return rf(quoteID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.FXQuote); ok {
+		r0 = rf(quoteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FXQuote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(quoteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRate provides a mock function with given fields: baseCurrency, quoteCurrency
+func (_m *FXRepository) GetRate(baseCurrency string, quoteCurrency string) (types.Rate, error) {
+	ret := _m.Called(baseCurrency, quoteCurrency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRate")
+	}
+
+	var r0 types.Rate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (types.Rate, error)); ok {
+		return rf(baseCurrency, quoteCurrency)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) types.Rate); ok {
+		r0 = rf(baseCurrency, quoteCurrency)
+	} else {
+		r0 = ret.Get(0).(types.Rate)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(baseCurrency, quoteCurrency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkQuoteUsed provides a mock function with given fields: quoteID, usedAt
+func (_m *FXRepository) MarkQuoteUsed(quoteID string, usedAt time.Time) error {
+	ret := _m.Called(quoteID, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkQuoteUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(quoteID, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFXRepository creates a new instance of FXRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFXRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FXRepository {
+	mock := &FXRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,184 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// HoldRepository is an autogenerated mock type for the HoldRepository type
+type HoldRepository struct {
+	mock.Mock
+}
+
+// CreateHold provides a mock function with given fields: hold
+func (_m *HoldRepository) CreateHold(hold *models.AccountHold) error {
+	ret := _m.Called(hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.AccountHold) error); ok {
+		r0 = rf(hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireHolds provides a mock function with given fields: now
+func (_m *HoldRepository) ExpireHolds(now time.Time) (int64, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireHolds")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHeldAmount provides a mock function with given fields: accountID, currency, now
+func (_m *HoldRepository) GetHeldAmount(accountID string, currency string, now time.Time) (types.Money, error) {
+	ret := _m.Called(accountID, currency, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHeldAmount")
+	}
+
+	var r0 types.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) (types.Money, error)); ok {
+		return rf(accountID, currency, now)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) types.Money); ok {
+		r0 = rf(accountID, currency, now)
+	} else {
+		r0 = ret.Get(0).(types.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = rf(accountID, currency, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHoldByIDForUpdate provides a mock function with given fields: holdID
+func (_m *HoldRepository) GetHoldByIDForUpdate(holdID string) (*models.AccountHold, error) {
+	ret := _m.Called(holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHoldByIDForUpdate")
+	}
+
+	var r0 *models.AccountHold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.AccountHold, error)); ok {
+		return rf(holdID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.AccountHold); ok {
+		r0 = rf(holdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountHold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHoldsByAccountID provides a mock function with given fields: accountID
+func (_m *HoldRepository) GetHoldsByAccountID(accountID string) ([]*models.AccountHold, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHoldsByAccountID")
+	}
+
+	var r0 []*models.AccountHold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*models.AccountHold, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*models.AccountHold); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AccountHold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateHold provides a mock function with given fields: hold
+func (_m *HoldRepository) UpdateHold(hold *models.AccountHold) error {
+	ret := _m.Called(hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.AccountHold) error); ok {
+		r0 = rf(hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewHoldRepository creates a new instance of HoldRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHoldRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HoldRepository {
+	mock := &HoldRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
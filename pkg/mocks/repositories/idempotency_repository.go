@@ -0,0 +1,152 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+// Complete provides a mock function with given fields: record
+func (_m *IdempotencyRepository) Complete(record *models.IdempotencyKey) error {
+	ret := _m.Called(record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.IdempotencyKey) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: record
+func (_m *IdempotencyRepository) Create(record *models.IdempotencyKey) (bool, error) {
+	ret := _m.Called(record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.IdempotencyKey) (bool, error)); ok {
+		return rf(record)
+	}
+	if rf, ok := ret.Get(0).(func(*models.IdempotencyKey) bool); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.IdempotencyKey) error); ok {
+		r1 = rf(record)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: userID, key
+func (_m *IdempotencyRepository) Delete(userID string, key string) error {
+	ret := _m.Called(userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByKey provides a mock function with given fields: userID, key
+func (_m *IdempotencyRepository) GetByKey(userID string, key string) (*models.IdempotencyKey, error) {
+	ret := _m.Called(userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByKey")
+	}
+
+	var r0 *models.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*models.IdempotencyKey, error)); ok {
+		return rf(userID, key)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *models.IdempotencyKey); ok {
+		r0 = rf(userID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reclaim provides a mock function with given fields: record, now
+func (_m *IdempotencyRepository) Reclaim(record *models.IdempotencyKey, now time.Time) (bool, error) {
+	ret := _m.Called(record, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reclaim")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.IdempotencyKey, time.Time) (bool, error)); ok {
+		return rf(record, now)
+	}
+	if rf, ok := ret.Get(0).(func(*models.IdempotencyKey, time.Time) bool); ok {
+		r0 = rf(record, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.IdempotencyKey, time.Time) error); ok {
+		r1 = rf(record, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepository {
+	mock := &IdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
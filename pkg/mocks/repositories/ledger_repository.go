@@ -0,0 +1,138 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// LedgerRepository is an autogenerated mock type for the LedgerRepository type
+type LedgerRepository struct {
+	mock.Mock
+}
+
+// GetBalanceAt provides a mock function with given fields: accountID, at
+func (_m *LedgerRepository) GetBalanceAt(accountID string, at time.Time) (types.Money, error) {
+	ret := _m.Called(accountID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAt")
+	}
+
+	var r0 types.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (types.Money, error)); ok {
+		return rf(accountID, at)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) types.Money); ok {
+		r0 = rf(accountID, at)
+	} else {
+		r0 = ret.Get(0).(types.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(accountID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntryByID provides a mock function with given fields: entryID
+func (_m *LedgerRepository) GetEntryByID(entryID string) (*models.JournalEntry, error) {
+	ret := _m.Called(entryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntryByID")
+	}
+
+	var r0 *models.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.JournalEntry, error)); ok {
+		return rf(entryID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.JournalEntry); ok {
+		r0 = rf(entryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.JournalEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(entryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrialBalance provides a mock function with no fields
+func (_m *LedgerRepository) GetTrialBalance() ([]*models.TrialBalance, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrialBalance")
+	}
+
+	var r0 []*models.TrialBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*models.TrialBalance, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*models.TrialBalance); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TrialBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostEntry provides a mock function with given fields: entry
+func (_m *LedgerRepository) PostEntry(entry *models.JournalEntry) error {
+	ret := _m.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.JournalEntry) error); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLedgerRepository creates a new instance of LedgerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerRepository {
+	mock := &LedgerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
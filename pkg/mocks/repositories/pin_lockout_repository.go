@@ -0,0 +1,172 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PinLockoutRepository is an autogenerated mock type for the PinLockoutRepository type
+type PinLockoutRepository struct {
+	mock.Mock
+}
+
+// DecrementIPFailures provides a mock function with given fields: ipAddress, windowStart
+func (_m *PinLockoutRepository) DecrementIPFailures(ipAddress string, windowStart time.Time) error {
+	ret := _m.Called(ipAddress, windowStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecrementIPFailures")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(ipAddress, windowStart)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: userID
+func (_m *PinLockoutRepository) Delete(userID string) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUserID provides a mock function with given fields: userID
+func (_m *PinLockoutRepository) GetByUserID(userID string) (*models.PinLockout, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 *models.PinLockout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.PinLockout, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.PinLockout); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PinLockout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementIPFailures provides a mock function with given fields: ipAddress, windowStart
+func (_m *PinLockoutRepository) IncrementIPFailures(ipAddress string, windowStart time.Time) (int64, error) {
+	ret := _m.Called(ipAddress, windowStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementIPFailures")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (int64, error)); ok {
+		return rf(ipAddress, windowStart)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) int64); ok {
+		r0 = rf(ipAddress, windowStart)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(ipAddress, windowStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseAttempt provides a mock function with given fields: previous, reserved
+func (_m *PinLockoutRepository) ReleaseAttempt(previous *models.PinLockout, reserved int) error {
+	ret := _m.Called(previous, reserved)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.PinLockout, int) error); ok {
+		r0 = rf(previous, reserved)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveAttempt provides a mock function with given fields: userID, reserve
+func (_m *PinLockoutRepository) ReserveAttempt(userID string, reserve func(*models.PinLockout) error) (*models.PinLockout, error) {
+	ret := _m.Called(userID, reserve)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveAttempt")
+	}
+
+	var r0 *models.PinLockout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, func(*models.PinLockout) error) (*models.PinLockout, error)); ok {
+		return rf(userID, reserve)
+	}
+	if rf, ok := ret.Get(0).(func(string, func(*models.PinLockout) error) *models.PinLockout); ok {
+		r0 = rf(userID, reserve)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PinLockout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, func(*models.PinLockout) error) error); ok {
+		r1 = rf(userID, reserve)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPinLockoutRepository creates a new instance of PinLockoutRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPinLockoutRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PinLockoutRepository {
+	mock := &PinLockoutRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
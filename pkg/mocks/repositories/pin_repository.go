@@ -0,0 +1,198 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PinRepository is an autogenerated mock type for the PinRepository type
+type PinRepository struct {
+	mock.Mock
+}
+
+// DeleteResetCode provides a mock function with given fields: code
+func (_m *PinRepository) DeleteResetCode(code *models.PinResetCode) (bool, error) {
+	ret := _m.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteResetCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.PinResetCode) (bool, error)); ok {
+		return rf(code)
+	}
+	if rf, ok := ret.Get(0).(func(*models.PinResetCode) bool); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.PinResetCode) error); ok {
+		r1 = rf(code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPinHistory provides a mock function with given fields: userID, limit
+func (_m *PinRepository) GetPinHistory(userID string, limit int) ([]string, error) {
+	ret := _m.Called(userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPinHistory")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]string, error)); ok {
+		return rf(userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []string); ok {
+		r0 = rf(userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetResetCode provides a mock function with given fields: userID
+func (_m *PinRepository) GetResetCode(userID string) (*models.PinResetCode, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResetCode")
+	}
+
+	var r0 *models.PinResetCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.PinResetCode, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.PinResetCode); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PinResetCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseResetAttempt provides a mock function with given fields: code
+func (_m *PinRepository) ReleaseResetAttempt(code *models.PinResetCode) error {
+	ret := _m.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseResetAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.PinResetCode) error); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplacePin provides a mock function with given fields: userID, pinHash, historySize
+func (_m *PinRepository) ReplacePin(userID string, pinHash string, historySize int) error {
+	ret := _m.Called(userID, pinHash, historySize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplacePin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(userID, pinHash, historySize)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveResetAttempt provides a mock function with given fields: code, maxAttempts
+func (_m *PinRepository) ReserveResetAttempt(code *models.PinResetCode, maxAttempts int) (bool, error) {
+	ret := _m.Called(code, maxAttempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveResetAttempt")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.PinResetCode, int) (bool, error)); ok {
+		return rf(code, maxAttempts)
+	}
+	if rf, ok := ret.Get(0).(func(*models.PinResetCode, int) bool); ok {
+		r0 = rf(code, maxAttempts)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.PinResetCode, int) error); ok {
+		r1 = rf(code, maxAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveResetCode provides a mock function with given fields: code
+func (_m *PinRepository) SaveResetCode(code *models.PinResetCode) error {
+	ret := _m.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveResetCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.PinResetCode) error); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPinRepository creates a new instance of PinRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPinRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PinRepository {
+	mock := &PinRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
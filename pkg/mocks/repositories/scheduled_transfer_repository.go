@@ -0,0 +1,222 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ScheduledTransferRepository is an autogenerated mock type for the ScheduledTransferRepository type
+type ScheduledTransferRepository struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: scheduleID
+func (_m *ScheduledTransferRepository) Cancel(scheduleID string) error {
+	ret := _m.Called(scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(scheduleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimDue provides a mock function with given fields: leaseOwner, now, leaseDuration, limit
+func (_m *ScheduledTransferRepository) ClaimDue(leaseOwner string, now time.Time, leaseDuration time.Duration, limit int) ([]*models.ScheduledTransfer, error) {
+	ret := _m.Called(leaseOwner, now, leaseDuration, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDue")
+	}
+
+	var r0 []*models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration, int) ([]*models.ScheduledTransfer, error)); ok {
+		return rf(leaseOwner, now, leaseDuration, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration, int) []*models.ScheduledTransfer); ok {
+		r0 = rf(leaseOwner, now, leaseDuration, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Duration, int) error); ok {
+		r1 = rf(leaseOwner, now, leaseDuration, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: schedule
+func (_m *ScheduledTransferRepository) Create(schedule *models.ScheduledTransfer) error {
+	ret := _m.Called(schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ScheduledTransfer) error); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByAccountID provides a mock function with given fields: accountID
+func (_m *ScheduledTransferRepository) GetByAccountID(accountID string) ([]*models.ScheduledTransfer, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAccountID")
+	}
+
+	var r0 []*models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*models.ScheduledTransfer, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*models.ScheduledTransfer); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: scheduleID
+func (_m *ScheduledTransferRepository) GetByID(scheduleID string) (*models.ScheduledTransfer, error) {
+	ret := _m.Called(scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.ScheduledTransfer, error)); ok {
+		return rf(scheduleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.ScheduledTransfer); ok {
+		r0 = rf(scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExecutions provides a mock function with given fields: scheduleID, limit
+func (_m *ScheduledTransferRepository) GetExecutions(scheduleID string, limit int) ([]*models.ScheduledTransferExecution, error) {
+	ret := _m.Called(scheduleID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExecutions")
+	}
+
+	var r0 []*models.ScheduledTransferExecution
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]*models.ScheduledTransferExecution, error)); ok {
+		return rf(scheduleID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []*models.ScheduledTransferExecution); ok {
+		r0 = rf(scheduleID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ScheduledTransferExecution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(scheduleID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordExecution provides a mock function with given fields: schedule, execution
+func (_m *ScheduledTransferRepository) RecordExecution(schedule *models.ScheduledTransfer, execution *models.ScheduledTransferExecution) error {
+	ret := _m.Called(schedule, execution)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordExecution")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ScheduledTransfer, *models.ScheduledTransferExecution) error); ok {
+		r0 = rf(schedule, execution)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: schedule
+func (_m *ScheduledTransferRepository) Update(schedule *models.ScheduledTransfer) error {
+	ret := _m.Called(schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ScheduledTransfer) error); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewScheduledTransferRepository creates a new instance of ScheduledTransferRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScheduledTransferRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScheduledTransferRepository {
+	mock := &ScheduledTransferRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
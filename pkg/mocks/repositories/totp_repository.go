@@ -0,0 +1,124 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TOTPRepository is an autogenerated mock type for the TOTPRepository type
+type TOTPRepository struct {
+	mock.Mock
+}
+
+// Enable provides a mock function with given fields: userID, now
+func (_m *TOTPRepository) Enable(userID string, now time.Time) error {
+	ret := _m.Called(userID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(userID, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUserID provides a mock function with given fields: userID
+func (_m *TOTPRepository) GetByUserID(userID string) (*models.UserTOTP, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 *models.UserTOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.UserTOTP, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.UserTOTP); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserTOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkUsedStep provides a mock function with given fields: userID, step
+func (_m *TOTPRepository) MarkUsedStep(userID string, step int64) (bool, error) {
+	ret := _m.Called(userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsedStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64) (bool, error)); ok {
+		return rf(userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64) bool); ok {
+		r0 = rf(userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: totp
+func (_m *TOTPRepository) Save(totp *models.UserTOTP) error {
+	ret := _m.Called(totp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.UserTOTP) error); ok {
+		r0 = rf(totp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTOTPRepository creates a new instance of TOTPRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOTPRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOTPRepository {
+	mock := &TOTPRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
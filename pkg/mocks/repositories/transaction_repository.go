@@ -6,6 +6,8 @@ import (
 	models "backend-developer-assignment/app/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TransactionRepository is an autogenerated mock type for the TransactionRepository type
@@ -31,6 +33,36 @@ func (_m *TransactionRepository) Create(transaction *models.Transaction) error {
 	return r0
 }
 
+// GetByAccountIDInPeriod provides a mock function with given fields: accountID, from, to, after, limit
+func (_m *TransactionRepository) GetByAccountIDInPeriod(accountID string, from time.Time, to time.Time, after *models.TransactionCursor, limit int) ([]*models.Transaction, error) {
+	ret := _m.Called(accountID, from, to, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAccountIDInPeriod")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time, *models.TransactionCursor, int) ([]*models.Transaction, error)); ok {
+		return rf(accountID, from, to, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time, *models.TransactionCursor, int) []*models.Transaction); ok {
+		r0 = rf(accountID, from, to, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time, *models.TransactionCursor, int) error); ok {
+		r1 = rf(accountID, from, to, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: id
 func (_m *TransactionRepository) GetByID(id string) (*models.Transaction, error) {
 	ret := _m.Called(id)
@@ -61,6 +93,36 @@ func (_m *TransactionRepository) GetByID(id string) (*models.Transaction, error)
 	return r0, r1
 }
 
+// GetByIDForUpdate provides a mock function with given fields: id
+func (_m *TransactionRepository) GetByIDForUpdate(id string) (*models.Transaction, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDForUpdate")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.Transaction, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.Transaction); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByUserIDWithPagination provides a mock function with given fields: userID, orderBy, limit, offset
 func (_m *TransactionRepository) GetByUserIDWithPagination(userID string, orderBy string, limit int, offset int) ([]*models.Transaction, int, error) {
 	ret := _m.Called(userID, orderBy, limit, offset)
@@ -98,6 +160,66 @@ func (_m *TransactionRepository) GetByUserIDWithPagination(userID string, orderB
 	return r0, r1, r2
 }
 
+// Search provides a mock function with given fields: filter
+func (_m *TransactionRepository) Search(filter models.TransactionFilter) ([]*models.Transaction, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter) ([]*models.Transaction, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter) []*models.Transaction); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.TransactionFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchByAccountID provides a mock function with given fields: filter
+func (_m *TransactionRepository) SearchByAccountID(filter models.TransactionFilter) ([]*models.Transaction, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchByAccountID")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter) ([]*models.Transaction, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter) []*models.Transaction); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.TransactionFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: transaction
 func (_m *TransactionRepository) Update(transaction *models.Transaction) error {
 	ret := _m.Called(transaction)
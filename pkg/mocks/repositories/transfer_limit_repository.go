@@ -0,0 +1,90 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// TransferLimitRepository is an autogenerated mock type for the TransferLimitRepository type
+type TransferLimitRepository struct {
+	mock.Mock
+}
+
+// GetApplicableLimits provides a mock function with given fields: userID, accountType, currency
+func (_m *TransferLimitRepository) GetApplicableLimits(userID string, accountType string, currency string) ([]*models.TransferLimit, error) {
+	ret := _m.Called(userID, accountType, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetApplicableLimits")
+	}
+
+	var r0 []*models.TransferLimit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) ([]*models.TransferLimit, error)); ok {
+		return rf(userID, accountType, currency)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) []*models.TransferLimit); ok {
+		r0 = rf(userID, accountType, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TransferLimit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userID, accountType, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDebitTotalSince provides a mock function with given fields: accountID, currency, since
+func (_m *TransferLimitRepository) GetDebitTotalSince(accountID string, currency string, since time.Time) (types.Money, error) {
+	ret := _m.Called(accountID, currency, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDebitTotalSince")
+	}
+
+	var r0 types.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) (types.Money, error)); ok {
+		return rf(accountID, currency, since)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) types.Money); ok {
+		r0 = rf(accountID, currency, since)
+	} else {
+		r0 = ret.Get(0).(types.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = rf(accountID, currency, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTransferLimitRepository creates a new instance of TransferLimitRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTransferLimitRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TransferLimitRepository {
+	mock := &TransferLimitRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
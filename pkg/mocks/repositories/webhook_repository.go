@@ -0,0 +1,330 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookRepository is an autogenerated mock type for the WebhookRepository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+// ClaimDueDeliveries provides a mock function with given fields: leaseOwner, now, leaseDuration, limit
+func (_m *WebhookRepository) ClaimDueDeliveries(leaseOwner string, now time.Time, leaseDuration time.Duration, limit int) ([]*models.WebhookDispatch, error) {
+	ret := _m.Called(leaseOwner, now, leaseDuration, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDueDeliveries")
+	}
+
+	var r0 []*models.WebhookDispatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration, int) ([]*models.WebhookDispatch, error)); ok {
+		return rf(leaseOwner, now, leaseDuration, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration, int) []*models.WebhookDispatch); ok {
+		r0 = rf(leaseOwner, now, leaseDuration, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookDispatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Duration, int) error); ok {
+		r1 = rf(leaseOwner, now, leaseDuration, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDelivery provides a mock function with given fields: delivery
+func (_m *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.WebhookDelivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateEndpoint provides a mock function with given fields: endpoint
+func (_m *WebhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	ret := _m.Called(endpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEndpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.WebhookEndpoint) error); ok {
+		r0 = rf(endpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEndpoint provides a mock function with given fields: endpointID
+func (_m *WebhookRepository) DeleteEndpoint(endpointID string) error {
+	ret := _m.Called(endpointID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEndpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(endpointID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDelivery provides a mock function with given fields: endpointID, deliveryID
+func (_m *WebhookRepository) GetDelivery(endpointID string, deliveryID int64) (*models.WebhookDelivery, error) {
+	ret := _m.Called(endpointID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelivery")
+	}
+
+	var r0 *models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64) (*models.WebhookDelivery, error)); ok {
+		return rf(endpointID, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64) *models.WebhookDelivery); ok {
+		r0 = rf(endpointID, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(endpointID, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointByID provides a mock function with given fields: endpointID
+func (_m *WebhookRepository) GetEndpointByID(endpointID string) (*models.WebhookEndpoint, error) {
+	ret := _m.Called(endpointID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointByID")
+	}
+
+	var r0 *models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.WebhookEndpoint, error)); ok {
+		return rf(endpointID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.WebhookEndpoint); ok {
+		r0 = rf(endpointID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(endpointID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointsByScope provides a mock function with given fields: scope
+func (_m *WebhookRepository) GetEndpointsByScope(scope models.WebhookScope) ([]*models.WebhookEndpoint, error) {
+	ret := _m.Called(scope)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointsByScope")
+	}
+
+	var r0 []*models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.WebhookScope) ([]*models.WebhookEndpoint, error)); ok {
+		return rf(scope)
+	}
+	if rf, ok := ret.Get(0).(func(models.WebhookScope) []*models.WebhookEndpoint); ok {
+		r0 = rf(scope)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.WebhookScope) error); ok {
+		r1 = rf(scope)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointsByUserID provides a mock function with given fields: userID
+func (_m *WebhookRepository) GetEndpointsByUserID(userID string) ([]*models.WebhookEndpoint, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointsByUserID")
+	}
+
+	var r0 []*models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*models.WebhookEndpoint, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*models.WebhookEndpoint); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointsForUsers provides a mock function with given fields: userIDs
+func (_m *WebhookRepository) GetEndpointsForUsers(userIDs []string) ([]*models.WebhookEndpoint, error) {
+	ret := _m.Called(userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointsForUsers")
+	}
+
+	var r0 []*models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) ([]*models.WebhookEndpoint, error)); ok {
+		return rf(userIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]string) []*models.WebhookEndpoint); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: endpointID, filter
+func (_m *WebhookRepository) ListDeliveries(endpointID string, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	ret := _m.Called(endpointID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []*models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error)); ok {
+		return rf(endpointID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.WebhookDeliveryFilter) []*models.WebhookDelivery); ok {
+		r0 = rf(endpointID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.WebhookDeliveryFilter) error); ok {
+		r1 = rf(endpointID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetDelivery provides a mock function with given fields: endpointID, deliveryID, now
+func (_m *WebhookRepository) ResetDelivery(endpointID string, deliveryID int64, now time.Time) error {
+	ret := _m.Called(endpointID, deliveryID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64, time.Time) error); ok {
+		r0 = rf(endpointID, deliveryID, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDelivery provides a mock function with given fields: delivery
+func (_m *WebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.WebhookDelivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,9 +4,14 @@ package mocks
 
 import (
 	models "backend-developer-assignment/app/models"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
+	repositories "backend-developer-assignment/app/repositories"
+
+	time "time"
+
 	types "backend-developer-assignment/pkg/types"
 )
 
@@ -15,6 +20,36 @@ type AccountService struct {
 	mock.Mock
 }
 
+// CaptureHold provides a mock function with given fields: accountID, holdID, amount
+func (_m *AccountService) CaptureHold(accountID string, holdID string, amount *types.Money) (*models.AccountHold, error) {
+	ret := _m.Called(accountID, holdID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CaptureHold")
+	}
+
+	var r0 *models.AccountHold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, *types.Money) (*models.AccountHold, error)); ok {
+		return rf(accountID, holdID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, *types.Money) *models.AccountHold); ok {
+		r0 = rf(accountID, holdID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountHold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, *types.Money) error); ok {
+		r1 = rf(accountID, holdID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateAccountWithDetails provides a mock function with given fields: accountWithDetails
 func (_m *AccountService) CreateAccountWithDetails(accountWithDetails *models.AccountWithDetails) error {
 	ret := _m.Called(accountWithDetails)
@@ -52,25 +87,25 @@ func (_m *AccountService) DeleteAccount(accountID string) error {
 }
 
 // DepositToAccount provides a mock function with given fields: accountID, amount
-func (_m *AccountService) DepositToAccount(accountID string, amount float64) (float64, error) {
+func (_m *AccountService) DepositToAccount(accountID string, amount types.Money) (types.Money, error) {
 	ret := _m.Called(accountID, amount)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DepositToAccount")
 	}
 
-	var r0 float64
+	var r0 types.Money
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, float64) (float64, error)); ok {
+	if rf, ok := ret.Get(0).(func(string, types.Money) (types.Money, error)); ok {
 		return rf(accountID, amount)
 	}
-	if rf, ok := ret.Get(0).(func(string, float64) float64); ok {
+	if rf, ok := ret.Get(0).(func(string, types.Money) types.Money); ok {
 		r0 = rf(accountID, amount)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Get(0).(types.Money)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, float64) error); ok {
+	if rf, ok := ret.Get(1).(func(string, types.Money) error); ok {
 		r1 = rf(accountID, amount)
 	} else {
 		r1 = ret.Error(1)
@@ -79,6 +114,54 @@ func (_m *AccountService) DepositToAccount(accountID string, amount float64) (fl
 	return r0, r1
 }
 
+// ExpireHolds provides a mock function with given fields: ctx
+func (_m *AccountService) ExpireHolds(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireHolds")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FreezeAccount provides a mock function with given fields: accountID
+func (_m *AccountService) FreezeAccount(accountID string) (*models.AccountWithDetails, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FreezeAccount")
+	}
+
+	var r0 *models.AccountWithDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.AccountWithDetails, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.AccountWithDetails); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountWithDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAccountByID provides a mock function with given fields: accountID
 func (_m *AccountService) GetAccountByID(accountID string) (*models.Account, error) {
 	ret := _m.Called(accountID)
@@ -199,6 +282,96 @@ func (_m *AccountService) GetAccountsWithDetailByUserID(userID string) ([]*model
 	return r0, r1
 }
 
+// GetHolds provides a mock function with given fields: accountID
+func (_m *AccountService) GetHolds(accountID string) ([]*models.AccountHold, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHolds")
+	}
+
+	var r0 []*models.AccountHold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*models.AccountHold, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*models.AccountHold); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AccountHold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlaceHold provides a mock function with given fields: accountID, amount, description, expiresAt
+func (_m *AccountService) PlaceHold(accountID string, amount types.Money, description string, expiresAt time.Time) (*models.AccountHold, error) {
+	ret := _m.Called(accountID, amount, description, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceHold")
+	}
+
+	var r0 *models.AccountHold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, types.Money, string, time.Time) (*models.AccountHold, error)); ok {
+		return rf(accountID, amount, description, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(string, types.Money, string, time.Time) *models.AccountHold); ok {
+		r0 = rf(accountID, amount, description, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountHold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, types.Money, string, time.Time) error); ok {
+		r1 = rf(accountID, amount, description, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseHold provides a mock function with given fields: accountID, holdID
+func (_m *AccountService) ReleaseHold(accountID string, holdID string) (*models.AccountHold, error) {
+	ret := _m.Called(accountID, holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseHold")
+	}
+
+	var r0 *models.AccountHold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*models.AccountHold, error)); ok {
+		return rf(accountID, holdID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *models.AccountHold); ok {
+		r0 = rf(accountID, holdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountHold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(accountID, holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetMainAccount provides a mock function with given fields: account
 func (_m *AccountService) SetMainAccount(account *models.Account) error {
 	ret := _m.Called(account)
@@ -218,7 +391,7 @@ func (_m *AccountService) SetMainAccount(account *models.Account) error {
 }
 
 // TransferBetweenAccounts provides a mock function with given fields: fromAccountID, toAccountID, amount
-func (_m *AccountService) TransferBetweenAccounts(fromAccountID string, toAccountID string, amount float64) (*types.TransferResult, error) {
+func (_m *AccountService) TransferBetweenAccounts(fromAccountID string, toAccountID string, amount types.Money) (*types.TransferResult, error) {
 	ret := _m.Called(fromAccountID, toAccountID, amount)
 
 	if len(ret) == 0 {
@@ -227,10 +400,10 @@ func (_m *AccountService) TransferBetweenAccounts(fromAccountID string, toAccoun
 
 	var r0 *types.TransferResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, float64) (*types.TransferResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(string, string, types.Money) (*types.TransferResult, error)); ok {
 		return rf(fromAccountID, toAccountID, amount)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, float64) *types.TransferResult); ok {
+	if rf, ok := ret.Get(0).(func(string, string, types.Money) *types.TransferResult); ok {
 		r0 = rf(fromAccountID, toAccountID, amount)
 	} else {
 		if ret.Get(0) != nil {
@@ -238,7 +411,7 @@ func (_m *AccountService) TransferBetweenAccounts(fromAccountID string, toAccoun
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, float64) error); ok {
+	if rf, ok := ret.Get(1).(func(string, string, types.Money) error); ok {
 		r1 = rf(fromAccountID, toAccountID, amount)
 	} else {
 		r1 = ret.Error(1)
@@ -247,6 +420,96 @@ func (_m *AccountService) TransferBetweenAccounts(fromAccountID string, toAccoun
 	return r0, r1
 }
 
+// TransferBetweenAccountsWith provides a mock function with given fields: fromAccountID, toAccountID, amount, inTx
+func (_m *AccountService) TransferBetweenAccountsWith(fromAccountID string, toAccountID string, amount types.Money, inTx func(repositories.Adapters) error) (*types.TransferResult, error) {
+	ret := _m.Called(fromAccountID, toAccountID, amount, inTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferBetweenAccountsWith")
+	}
+
+	var r0 *types.TransferResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, types.Money, func(repositories.Adapters) error) (*types.TransferResult, error)); ok {
+		return rf(fromAccountID, toAccountID, amount, inTx)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, types.Money, func(repositories.Adapters) error) *types.TransferResult); ok {
+		r0 = rf(fromAccountID, toAccountID, amount, inTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TransferResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, types.Money, func(repositories.Adapters) error) error); ok {
+		r1 = rf(fromAccountID, toAccountID, amount, inTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferWithQuote provides a mock function with given fields: fromAccountID, toAccountID, amount, quoteID
+func (_m *AccountService) TransferWithQuote(fromAccountID string, toAccountID string, amount types.Money, quoteID string) (*types.TransferResult, error) {
+	ret := _m.Called(fromAccountID, toAccountID, amount, quoteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferWithQuote")
+	}
+
+	var r0 *types.TransferResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, types.Money, string) (*types.TransferResult, error)); ok {
+		return rf(fromAccountID, toAccountID, amount, quoteID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, types.Money, string) *types.TransferResult); ok {
+		r0 = rf(fromAccountID, toAccountID, amount, quoteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TransferResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, types.Money, string) error); ok {
+		r1 = rf(fromAccountID, toAccountID, amount, quoteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnfreezeAccount provides a mock function with given fields: accountID
+func (_m *AccountService) UnfreezeAccount(accountID string) (*models.AccountWithDetails, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnfreezeAccount")
+	}
+
+	var r0 *models.AccountWithDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.AccountWithDetails, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.AccountWithDetails); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountWithDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateAccount provides a mock function with given fields: account
 func (_m *AccountService) UpdateAccount(account *models.AccountWithDetails) error {
 	ret := _m.Called(account)
@@ -266,25 +529,25 @@ func (_m *AccountService) UpdateAccount(account *models.AccountWithDetails) erro
 }
 
 // WithdrawFromAccount provides a mock function with given fields: accountID, amount
-func (_m *AccountService) WithdrawFromAccount(accountID string, amount float64) (float64, error) {
+func (_m *AccountService) WithdrawFromAccount(accountID string, amount types.Money) (types.Money, error) {
 	ret := _m.Called(accountID, amount)
 
 	if len(ret) == 0 {
 		panic("no return value specified for WithdrawFromAccount")
 	}
 
-	var r0 float64
+	var r0 types.Money
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, float64) (float64, error)); ok {
+	if rf, ok := ret.Get(0).(func(string, types.Money) (types.Money, error)); ok {
 		return rf(accountID, amount)
 	}
-	if rf, ok := ret.Get(0).(func(string, float64) float64); ok {
+	if rf, ok := ret.Get(0).(func(string, types.Money) types.Money); ok {
 		r0 = rf(accountID, amount)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Get(0).(types.Money)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, float64) error); ok {
+	if rf, ok := ret.Get(1).(func(string, types.Money) error); ok {
 		r1 = rf(accountID, amount)
 	} else {
 		r1 = ret.Error(1)
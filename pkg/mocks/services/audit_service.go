@@ -0,0 +1,63 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditService is an autogenerated mock type for the AuditService type
+type AuditService struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: filter
+func (_m *AuditService) List(filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*models.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.AuditLogFilter) ([]*models.AuditLog, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.AuditLogFilter) []*models.AuditLog); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.AuditLogFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Record provides a mock function with given fields: entry, before, after
+func (_m *AuditService) Record(entry *models.AuditLog, before interface{}, after interface{}) {
+	_m.Called(entry, before, after)
+}
+
+// NewAuditService creates a new instance of AuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditService {
+	mock := &AuditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
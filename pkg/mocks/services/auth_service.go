@@ -0,0 +1,231 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	utils "backend-developer-assignment/pkg/utils"
+)
+
+// AuthService is an autogenerated mock type for the AuthService type
+type AuthService struct {
+	mock.Mock
+}
+
+// IsSessionRevoked provides a mock function with given fields: sessionID
+func (_m *AuthService) IsSessionRevoked(sessionID string) (bool, error) {
+	ret := _m.Called(sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsSessionRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(sessionID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueTokens provides a mock function with given fields: userID, device
+func (_m *AuthService) IssueTokens(userID string, device models.DeviceInfo) (*utils.Tokens, error) {
+	ret := _m.Called(userID, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueTokens")
+	}
+
+	var r0 *utils.Tokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.DeviceInfo) (*utils.Tokens, error)); ok {
+		return rf(userID, device)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.DeviceInfo) *utils.Tokens); ok {
+		r0 = rf(userID, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.Tokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.DeviceInfo) error); ok {
+		r1 = rf(userID, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: userID, currentSessionID
+func (_m *AuthService) ListSessions(userID string, currentSessionID string) ([]*models.Session, error) {
+	ret := _m.Called(userID, currentSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []*models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]*models.Session, error)); ok {
+		return rf(userID, currentSessionID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []*models.Session); ok {
+		r0 = rf(userID, currentSessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userID, currentSessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: userID, refreshToken
+func (_m *AuthService) Logout(userID string, refreshToken string) error {
+	ret := _m.Called(userID, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutAll provides a mock function with given fields: userID
+func (_m *AuthService) LogoutAll(userID string) (int64, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutAll")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int64, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeExpiredTokens provides a mock function with given fields: ctx
+func (_m *AuthService) PurgeExpiredTokens(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpiredTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenewTokens provides a mock function with given fields: userID, deviceID, refreshToken
+func (_m *AuthService) RenewTokens(userID string, deviceID string, refreshToken string) (*utils.Tokens, error) {
+	ret := _m.Called(userID, deviceID, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewTokens")
+	}
+
+	var r0 *utils.Tokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*utils.Tokens, error)); ok {
+		return rf(userID, deviceID, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *utils.Tokens); ok {
+		r0 = rf(userID, deviceID, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*utils.Tokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userID, deviceID, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: userID, sessionID
+func (_m *AuthService) RevokeSession(userID string, sessionID string) error {
+	ret := _m.Called(userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuthService creates a new instance of AuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthService {
+	mock := &AuthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
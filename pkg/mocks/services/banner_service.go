@@ -13,6 +13,54 @@ type BannerService struct {
 	mock.Mock
 }
 
+// CreateBanner provides a mock function with given fields: userID, title, description, image
+func (_m *BannerService) CreateBanner(userID string, title string, description string, image string) (*models.Banner, error) {
+	ret := _m.Called(userID, title, description, image)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBanner")
+	}
+
+	var r0 *models.Banner
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (*models.Banner, error)); ok {
+		return rf(userID, title, description, image)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) *models.Banner); ok {
+		r0 = rf(userID, title, description, image)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Banner)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(userID, title, description, image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBanner provides a mock function with given fields: bannerID
+func (_m *BannerService) DeleteBanner(bannerID string) error {
+	ret := _m.Called(bannerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBanner")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(bannerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetBannerByID provides a mock function with given fields: bannerID
 func (_m *BannerService) GetBannerByID(bannerID string) (*models.Banner, error) {
 	ret := _m.Called(bannerID)
@@ -73,6 +121,36 @@ func (_m *BannerService) GetBannersByUserID(userID string) ([]*models.Banner, er
 	return r0, r1
 }
 
+// UpdateBanner provides a mock function with given fields: bannerID, title, description, image
+func (_m *BannerService) UpdateBanner(bannerID string, title string, description string, image string) (*models.Banner, error) {
+	ret := _m.Called(bannerID, title, description, image)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBanner")
+	}
+
+	var r0 *models.Banner
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (*models.Banner, error)); ok {
+		return rf(bannerID, title, description, image)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) *models.Banner); ok {
+		r0 = rf(bannerID, title, description, image)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Banner)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(bannerID, title, description, image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBannerService creates a new instance of BannerService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBannerService(t interface {
@@ -0,0 +1,217 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// ChallengeService is an autogenerated mock type for the ChallengeService type
+type ChallengeService struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: userID, challengeID, method, code
+func (_m *ChallengeService) Confirm(userID string, challengeID string, method string, code string) (*models.Challenge, error) {
+	ret := _m.Called(userID, challengeID, method, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 *models.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (*models.Challenge, error)); ok {
+		return rf(userID, challengeID, method, code)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) *models.Challenge); ok {
+		r0 = rf(userID, challengeID, method, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(userID, challengeID, method, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateScheduleChallenge provides a mock function with given fields: userID, schedule
+func (_m *ChallengeService) CreateScheduleChallenge(userID string, schedule *models.ScheduledTransfer) (*models.Challenge, error) {
+	ret := _m.Called(userID, schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduleChallenge")
+	}
+
+	var r0 *models.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *models.ScheduledTransfer) (*models.Challenge, error)); ok {
+		return rf(userID, schedule)
+	}
+	if rf, ok := ret.Get(0).(func(string, *models.ScheduledTransfer) *models.Challenge); ok {
+		r0 = rf(userID, schedule)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *models.ScheduledTransfer) error); ok {
+		r1 = rf(userID, schedule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateScheduleUpdateChallenge provides a mock function with given fields: userID, update
+func (_m *ChallengeService) CreateScheduleUpdateChallenge(userID string, update *models.PendingScheduleUpdate) (*models.Challenge, error) {
+	ret := _m.Called(userID, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduleUpdateChallenge")
+	}
+
+	var r0 *models.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *models.PendingScheduleUpdate) (*models.Challenge, error)); ok {
+		return rf(userID, update)
+	}
+	if rf, ok := ret.Get(0).(func(string, *models.PendingScheduleUpdate) *models.Challenge); ok {
+		r0 = rf(userID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *models.PendingScheduleUpdate) error); ok {
+		r1 = rf(userID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTransferChallenge provides a mock function with given fields: userID, transfer
+func (_m *ChallengeService) CreateTransferChallenge(userID string, transfer *models.PendingTransfer) (*models.Challenge, error) {
+	ret := _m.Called(userID, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransferChallenge")
+	}
+
+	var r0 *models.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *models.PendingTransfer) (*models.Challenge, error)); ok {
+		return rf(userID, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(string, *models.PendingTransfer) *models.Challenge); ok {
+		r0 = rf(userID, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *models.PendingTransfer) error); ok {
+		r1 = rf(userID, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChallengeByID provides a mock function with given fields: challengeID
+func (_m *ChallengeService) GetChallengeByID(challengeID string) (*models.Challenge, error) {
+	ret := _m.Called(challengeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChallengeByID")
+	}
+
+	var r0 *models.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.Challenge, error)); ok {
+		return rf(challengeID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.Challenge); ok {
+		r0 = rf(challengeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(challengeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeExpiredChallenges provides a mock function with given fields: ctx
+func (_m *ChallengeService) PurgeExpiredChallenges(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpiredChallenges")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequiresStepUp provides a mock function with given fields: amount
+func (_m *ChallengeService) RequiresStepUp(amount types.Money) bool {
+	ret := _m.Called(amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequiresStepUp")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(types.Money) bool); ok {
+		r0 = rf(amount)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewChallengeService creates a new instance of ChallengeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewChallengeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ChallengeService {
+	mock := &ChallengeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -187,6 +187,36 @@ func (_m *DebitCardService) UpdateCard(card *models.DebitCard, name string, colo
 	return r0
 }
 
+// UpdateCardStatus provides a mock function with given fields: cardID, status
+func (_m *DebitCardService) UpdateCardStatus(cardID string, status models.CardStatus) (*models.DebitCardWithDetails, error) {
+	ret := _m.Called(cardID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCardStatus")
+	}
+
+	var r0 *models.DebitCardWithDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.CardStatus) (*models.DebitCardWithDetails, error)); ok {
+		return rf(cardID, status)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.CardStatus) *models.DebitCardWithDetails); ok {
+		r0 = rf(cardID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DebitCardWithDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.CardStatus) error); ok {
+		r1 = rf(cardID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewDebitCardService creates a new instance of DebitCardService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDebitCardService(t interface {
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// FXService is an autogenerated mock type for the FXService type
+type FXService struct {
+	mock.Mock
+}
+
+// CreateQuote provides a mock function with given fields: userID, toCurrency, amount
+func (_m *FXService) CreateQuote(userID string, toCurrency string, amount types.Money) (*models.FXQuote, error) {
+	ret := _m.Called(userID, toCurrency, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQuote")
+	}
+
+	var r0 *models.FXQuote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, types.Money) (*models.FXQuote, error)); ok {
+		return rf(userID, toCurrency, amount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, types.Money) *models.FXQuote); ok {
+		r0 = rf(userID, toCurrency, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FXQuote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, types.Money) error); ok {
+		r1 = rf(userID, toCurrency, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFXService creates a new instance of FXService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFXService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FXService {
+	mock := &FXService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// IdempotencyService is an autogenerated mock type for the IdempotencyService type
+type IdempotencyService struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: userID, key, requestHash
+func (_m *IdempotencyService) Begin(userID string, key string, requestHash string) (*types.IdempotentResponse, error) {
+	ret := _m.Called(userID, key, requestHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *types.IdempotentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*types.IdempotentResponse, error)); ok {
+		return rf(userID, key, requestHash)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *types.IdempotentResponse); ok {
+		r0 = rf(userID, key, requestHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.IdempotentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userID, key, requestHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: userID, key, response
+func (_m *IdempotencyService) Complete(userID string, key string, response *types.IdempotentResponse) error {
+	ret := _m.Called(userID, key, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *types.IdempotentResponse) error); ok {
+		r0 = rf(userID, key, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: userID, key
+func (_m *IdempotencyService) Release(userID string, key string) error {
+	ret := _m.Called(userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyService {
+	mock := &IdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
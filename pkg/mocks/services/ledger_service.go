@@ -0,0 +1,90 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// LedgerService is an autogenerated mock type for the LedgerService type
+type LedgerService struct {
+	mock.Mock
+}
+
+// CheckBooksBalanced provides a mock function with no fields
+func (_m *LedgerService) CheckBooksBalanced() ([]*models.TrialBalance, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckBooksBalanced")
+	}
+
+	var r0 []*models.TrialBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*models.TrialBalance, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*models.TrialBalance); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TrialBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceAt provides a mock function with given fields: accountID, at
+func (_m *LedgerService) GetBalanceAt(accountID string, at time.Time) (types.Money, error) {
+	ret := _m.Called(accountID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAt")
+	}
+
+	var r0 types.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (types.Money, error)); ok {
+		return rf(accountID, at)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) types.Money); ok {
+		r0 = rf(accountID, at)
+	} else {
+		r0 = ret.Get(0).(types.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(accountID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLedgerService creates a new instance of LedgerService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerService {
+	mock := &LedgerService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
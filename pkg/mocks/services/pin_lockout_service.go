@@ -0,0 +1,172 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PinLockoutService is an autogenerated mock type for the PinLockoutService type
+type PinLockoutService struct {
+	mock.Mock
+}
+
+// GetLockState provides a mock function with given fields: userID
+func (_m *PinLockoutService) GetLockState(userID string) (*models.PinLockState, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLockState")
+	}
+
+	var r0 *models.PinLockState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.PinLockState, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.PinLockState); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PinLockState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordSuccess provides a mock function with given fields: attempt
+func (_m *PinLockoutService) RecordSuccess(attempt *models.PinAttempt) error {
+	ret := _m.Called(attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordSuccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.PinAttempt) error); ok {
+		r0 = rf(attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: attempt
+func (_m *PinLockoutService) Release(attempt *models.PinAttempt) error {
+	ret := _m.Called(attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.PinAttempt) error); ok {
+		r0 = rf(attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reserve provides a mock function with given fields: userID, ipAddress
+func (_m *PinLockoutService) Reserve(userID string, ipAddress string) (*models.PinAttempt, error) {
+	ret := _m.Called(userID, ipAddress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 *models.PinAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*models.PinAttempt, error)); ok {
+		return rf(userID, ipAddress)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *models.PinAttempt); ok {
+		r0 = rf(userID, ipAddress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PinAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userID, ipAddress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveIP provides a mock function with given fields: ipAddress
+func (_m *PinLockoutService) ReserveIP(ipAddress string) (*models.PinAttempt, error) {
+	ret := _m.Called(ipAddress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveIP")
+	}
+
+	var r0 *models.PinAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.PinAttempt, error)); ok {
+		return rf(ipAddress)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.PinAttempt); ok {
+		r0 = rf(ipAddress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PinAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ipAddress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlock provides a mock function with given fields: userID
+func (_m *PinLockoutService) Unlock(userID string) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPinLockoutService creates a new instance of PinLockoutService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPinLockoutService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PinLockoutService {
+	mock := &PinLockoutService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PinService is an autogenerated mock type for the PinService type
+type PinService struct {
+	mock.Mock
+}
+
+// ChangePin provides a mock function with given fields: userID, currentPIN, newPIN
+func (_m *PinService) ChangePin(userID string, currentPIN string, newPIN string) error {
+	ret := _m.Called(userID, currentPIN, newPIN)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userID, currentPIN, newPIN)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmReset provides a mock function with given fields: userID, code, newPIN
+func (_m *PinService) ConfirmReset(userID string, code string, newPIN string) error {
+	ret := _m.Called(userID, code, newPIN)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(userID, code, newPIN)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestReset provides a mock function with given fields: userID
+func (_m *PinService) RequestReset(userID string) (time.Time, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestReset")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (time.Time, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPinService creates a new instance of PinService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPinService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PinService {
+	mock := &PinService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
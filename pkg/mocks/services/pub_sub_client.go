@@ -0,0 +1,156 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// PubSubClient is an autogenerated mock type for the PubSubClient type
+type PubSubClient struct {
+	mock.Mock
+}
+
+// AddToStream provides a mock function with given fields: ctx, stream, maxLen, values
+func (_m *PubSubClient) AddToStream(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error) {
+	ret := _m.Called(ctx, stream, maxLen, values)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToStream")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, map[string]interface{}) (string, error)); ok {
+		return rf(ctx, stream, maxLen, values)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, map[string]interface{}) string); ok {
+		r0 = rf(ctx, stream, maxLen, values)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, map[string]interface{}) error); ok {
+		r1 = rf(ctx, stream, maxLen, values)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Expire provides a mock function with given fields: ctx, key, expiration
+func (_m *PubSubClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	ret := _m.Called(ctx, key, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Expire")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishMessage provides a mock function with given fields: ctx, channel, message
+func (_m *PubSubClient) PublishMessage(ctx context.Context, channel string, message string) error {
+	ret := _m.Called(ctx, channel, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, channel, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadStream provides a mock function with given fields: ctx, stream, fromID, count
+func (_m *PubSubClient) ReadStream(ctx context.Context, stream string, fromID string, count int64) ([]types.StreamEntry, error) {
+	ret := _m.Called(ctx, stream, fromID, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadStream")
+	}
+
+	var r0 []types.StreamEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) ([]types.StreamEntry, error)); ok {
+		return rf(ctx, stream, fromID, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []types.StreamEntry); ok {
+		r0 = rf(ctx, stream, fromID, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.StreamEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, stream, fromID, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeChannel provides a mock function with given fields: ctx, channel
+func (_m *PubSubClient) SubscribeChannel(ctx context.Context, channel string) (<-chan string, error) {
+	ret := _m.Called(ctx, channel)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeChannel")
+	}
+
+	var r0 <-chan string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan string, error)); ok {
+		return rf(ctx, channel)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan string); ok {
+		r0 = rf(ctx, channel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, channel)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPubSubClient creates a new instance of PubSubClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPubSubClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PubSubClient {
+	mock := &PubSubClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
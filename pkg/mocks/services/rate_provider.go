@@ -0,0 +1,56 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	types "backend-developer-assignment/pkg/types"
+)
+
+// RateProvider is an autogenerated mock type for the RateProvider type
+type RateProvider struct {
+	mock.Mock
+}
+
+// GetRate provides a mock function with given fields: fromCurrency, toCurrency
+func (_m *RateProvider) GetRate(fromCurrency string, toCurrency string) (types.Rate, error) {
+	ret := _m.Called(fromCurrency, toCurrency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRate")
+	}
+
+	var r0 types.Rate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (types.Rate, error)); ok {
+		return rf(fromCurrency, toCurrency)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) types.Rate); ok {
+		r0 = rf(fromCurrency, toCurrency)
+	} else {
+		r0 = ret.Get(0).(types.Rate)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(fromCurrency, toCurrency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRateProvider creates a new instance of RateProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRateProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RateProvider {
+	mock := &RateProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
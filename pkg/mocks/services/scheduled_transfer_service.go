@@ -0,0 +1,203 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ScheduledTransferService is an autogenerated mock type for the ScheduledTransferService type
+type ScheduledTransferService struct {
+	mock.Mock
+}
+
+// CancelSchedule provides a mock function with given fields: accountID, scheduleID
+func (_m *ScheduledTransferService) CancelSchedule(accountID string, scheduleID string) error {
+	ret := _m.Called(accountID, scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(accountID, scheduleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSchedule provides a mock function with given fields: schedule
+func (_m *ScheduledTransferService) CreateSchedule(schedule *models.ScheduledTransfer) error {
+	ret := _m.Called(schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ScheduledTransfer) error); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetExecutions provides a mock function with given fields: accountID, scheduleID
+func (_m *ScheduledTransferService) GetExecutions(accountID string, scheduleID string) ([]*models.ScheduledTransferExecution, error) {
+	ret := _m.Called(accountID, scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExecutions")
+	}
+
+	var r0 []*models.ScheduledTransferExecution
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]*models.ScheduledTransferExecution, error)); ok {
+		return rf(accountID, scheduleID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []*models.ScheduledTransferExecution); ok {
+		r0 = rf(accountID, scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ScheduledTransferExecution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(accountID, scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedule provides a mock function with given fields: accountID, scheduleID
+func (_m *ScheduledTransferService) GetSchedule(accountID string, scheduleID string) (*models.ScheduledTransfer, error) {
+	ret := _m.Called(accountID, scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedule")
+	}
+
+	var r0 *models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*models.ScheduledTransfer, error)); ok {
+		return rf(accountID, scheduleID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *models.ScheduledTransfer); ok {
+		r0 = rf(accountID, scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(accountID, scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedulesByAccountID provides a mock function with given fields: accountID
+func (_m *ScheduledTransferService) GetSchedulesByAccountID(accountID string) ([]*models.ScheduledTransfer, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedulesByAccountID")
+	}
+
+	var r0 []*models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*models.ScheduledTransfer, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*models.ScheduledTransfer); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunDueSchedules provides a mock function with given fields: ctx
+func (_m *ScheduledTransferService) RunDueSchedules(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunDueSchedules")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSchedule provides a mock function with given fields: accountID, scheduleID, update
+func (_m *ScheduledTransferService) UpdateSchedule(accountID string, scheduleID string, update *models.ScheduledTransferUpdate) (*models.ScheduledTransfer, error) {
+	ret := _m.Called(accountID, scheduleID, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSchedule")
+	}
+
+	var r0 *models.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, *models.ScheduledTransferUpdate) (*models.ScheduledTransfer, error)); ok {
+		return rf(accountID, scheduleID, update)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, *models.ScheduledTransferUpdate) *models.ScheduledTransfer); ok {
+		r0 = rf(accountID, scheduleID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, *models.ScheduledTransferUpdate) error); ok {
+		r1 = rf(accountID, scheduleID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewScheduledTransferService creates a new instance of ScheduledTransferService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScheduledTransferService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScheduledTransferService {
+	mock := &ScheduledTransferService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
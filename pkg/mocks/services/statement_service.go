@@ -0,0 +1,79 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// StatementService is an autogenerated mock type for the StatementService type
+type StatementService struct {
+	mock.Mock
+}
+
+// OpenStatement provides a mock function with given fields: accountID, from, to
+func (_m *StatementService) OpenStatement(accountID string, from time.Time, to time.Time) (*models.Statement, error) {
+	ret := _m.Called(accountID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenStatement")
+	}
+
+	var r0 *models.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) (*models.Statement, error)); ok {
+		return rf(accountID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) *models.Statement); ok {
+		r0 = rf(accountID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(accountID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WriteStatement provides a mock function with given fields: w, statement, format
+func (_m *StatementService) WriteStatement(w io.Writer, statement *models.Statement, format models.StatementFormat) error {
+	ret := _m.Called(w, statement, format)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(io.Writer, *models.Statement, models.StatementFormat) error); ok {
+		r0 = rf(w, statement, format)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStatementService creates a new instance of StatementService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatementService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatementService {
+	mock := &StatementService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
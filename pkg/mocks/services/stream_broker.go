@@ -0,0 +1,121 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StreamBroker is an autogenerated mock type for the StreamBroker type
+type StreamBroker struct {
+	mock.Mock
+}
+
+// Close provides a mock function with no fields
+func (_m *StreamBroker) Close() {
+	_m.Called()
+}
+
+// History provides a mock function with given fields: ctx, userID, fromID
+func (_m *StreamBroker) History(ctx context.Context, userID string, fromID string) ([]*models.StreamMessage, error) {
+	ret := _m.Called(ctx, userID, fromID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 []*models.StreamMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*models.StreamMessage, error)); ok {
+		return rf(ctx, userID, fromID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*models.StreamMessage); ok {
+		r0 = rf(ctx, userID, fromID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StreamMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, fromID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, userID, message
+func (_m *StreamBroker) Publish(ctx context.Context, userID string, message *models.StreamMessage) error {
+	ret := _m.Called(ctx, userID, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.StreamMessage) error); ok {
+		r0 = rf(ctx, userID, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Subscribe provides a mock function with given fields: userID
+func (_m *StreamBroker) Subscribe(userID string) (<-chan *models.StreamMessage, func(), error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan *models.StreamMessage
+	var r1 func()
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (<-chan *models.StreamMessage, func(), error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) <-chan *models.StreamMessage); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *models.StreamMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) func()); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewStreamBroker creates a new instance of StreamBroker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStreamBroker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StreamBroker {
+	mock := &StreamBroker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
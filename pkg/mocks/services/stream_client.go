@@ -0,0 +1,56 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StreamClient is an autogenerated mock type for the StreamClient type
+type StreamClient struct {
+	mock.Mock
+}
+
+// AddToStream provides a mock function with given fields: ctx, stream, maxLen, values
+func (_m *StreamClient) AddToStream(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error) {
+	ret := _m.Called(ctx, stream, maxLen, values)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToStream")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, map[string]interface{}) (string, error)); ok {
+		return rf(ctx, stream, maxLen, values)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, map[string]interface{}) string); ok {
+		r0 = rf(ctx, stream, maxLen, values)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, map[string]interface{}) error); ok {
+		r1 = rf(ctx, stream, maxLen, values)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStreamClient creates a new instance of StreamClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStreamClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StreamClient {
+	mock := &StreamClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
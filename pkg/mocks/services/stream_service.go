@@ -0,0 +1,91 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StreamService is an autogenerated mock type for the StreamService type
+type StreamService struct {
+	mock.Mock
+}
+
+// Close provides a mock function with no fields
+func (_m *StreamService) Close() {
+	_m.Called()
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *StreamService) Publish(ctx context.Context, event *models.DomainEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DomainEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Subscribe provides a mock function with given fields: ctx, userID, lastEventID
+func (_m *StreamService) Subscribe(ctx context.Context, userID string, lastEventID string) (<-chan *models.StreamMessage, func(), error) {
+	ret := _m.Called(ctx, userID, lastEventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan *models.StreamMessage
+	var r1 func()
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (<-chan *models.StreamMessage, func(), error)); ok {
+		return rf(ctx, userID, lastEventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) <-chan *models.StreamMessage); ok {
+		r0 = rf(ctx, userID, lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *models.StreamMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) func()); ok {
+		r1 = rf(ctx, userID, lastEventID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, userID, lastEventID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewStreamService creates a new instance of StreamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStreamService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StreamService {
+	mock := &StreamService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,6 +6,8 @@ import (
 	models "backend-developer-assignment/app/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	types "backend-developer-assignment/pkg/types"
 )
 
 // TransactionService is an autogenerated mock type for the TransactionService type
@@ -31,6 +33,43 @@ func (_m *TransactionService) CreateTransaction(transaction *models.Transaction)
 	return r0
 }
 
+// GetAccountTransactions provides a mock function with given fields: filter, cursor
+func (_m *TransactionService) GetAccountTransactions(filter models.TransactionFilter, cursor string) ([]*models.Transaction, string, error) {
+	ret := _m.Called(filter, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountTransactions")
+	}
+
+	var r0 []*models.Transaction
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter, string) ([]*models.Transaction, string, error)); ok {
+		return rf(filter, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter, string) []*models.Transaction); ok {
+		r0 = rf(filter, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.TransactionFilter, string) string); ok {
+		r1 = rf(filter, cursor)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(models.TransactionFilter, string) error); ok {
+		r2 = rf(filter, cursor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTransactionByID provides a mock function with given fields: id
 func (_m *TransactionService) GetTransactionByID(id string) (*models.Transaction, error) {
 	ret := _m.Called(id)
@@ -98,6 +137,103 @@ func (_m *TransactionService) GetTransactionsByUserID(userID string, page int) (
 	return r0, r1, r2
 }
 
+// ReturnTransfer provides a mock function with given fields: userID, transactionID, amount
+func (_m *TransactionService) ReturnTransfer(userID string, transactionID string, amount *types.Money) (*models.ReversalResult, error) {
+	ret := _m.Called(userID, transactionID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnTransfer")
+	}
+
+	var r0 *models.ReversalResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, *types.Money) (*models.ReversalResult, error)); ok {
+		return rf(userID, transactionID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, *types.Money) *models.ReversalResult); ok {
+		r0 = rf(userID, transactionID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReversalResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, *types.Money) error); ok {
+		r1 = rf(userID, transactionID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReverseTransaction provides a mock function with given fields: transactionID, amount
+func (_m *TransactionService) ReverseTransaction(transactionID string, amount *types.Money) (*models.ReversalResult, error) {
+	ret := _m.Called(transactionID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReverseTransaction")
+	}
+
+	var r0 *models.ReversalResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *types.Money) (*models.ReversalResult, error)); ok {
+		return rf(transactionID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(string, *types.Money) *models.ReversalResult); ok {
+		r0 = rf(transactionID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReversalResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *types.Money) error); ok {
+		r1 = rf(transactionID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTransactions provides a mock function with given fields: filter, cursor
+func (_m *TransactionService) SearchTransactions(filter models.TransactionFilter, cursor string) ([]*models.Transaction, string, error) {
+	ret := _m.Called(filter, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTransactions")
+	}
+
+	var r0 []*models.Transaction
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter, string) ([]*models.Transaction, string, error)); ok {
+		return rf(filter, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(models.TransactionFilter, string) []*models.Transaction); ok {
+		r0 = rf(filter, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.TransactionFilter, string) string); ok {
+		r1 = rf(filter, cursor)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(models.TransactionFilter, string) error); ok {
+		r2 = rf(filter, cursor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewTransactionService creates a new instance of TransactionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTransactionService(t interface {
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TransferLimitService is an autogenerated mock type for the TransferLimitService type
+type TransferLimitService struct {
+	mock.Mock
+}
+
+// GetAccountLimits provides a mock function with given fields: account
+func (_m *TransferLimitService) GetAccountLimits(account *models.Account) (*models.AccountLimits, error) {
+	ret := _m.Called(account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountLimits")
+	}
+
+	var r0 *models.AccountLimits
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.Account) (*models.AccountLimits, error)); ok {
+		return rf(account)
+	}
+	if rf, ok := ret.Get(0).(func(*models.Account) *models.AccountLimits); ok {
+		r0 = rf(account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountLimits)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.Account) error); ok {
+		r1 = rf(account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTransferLimitService creates a new instance of TransferLimitService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTransferLimitService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TransferLimitService {
+	mock := &TransferLimitService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
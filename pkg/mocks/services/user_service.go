@@ -6,6 +6,8 @@ import (
 	models "backend-developer-assignment/app/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	types "backend-developer-assignment/pkg/types"
 )
 
 // UserService is an autogenerated mock type for the UserService type
@@ -73,6 +75,36 @@ func (_m *UserService) GetUserGreetingByID(id string) (*models.UserGreeting, err
 	return r0, r1
 }
 
+// GetUserRoles provides a mock function with given fields: id
+func (_m *UserService) GetUserRoles(id string) ([]types.Role, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRoles")
+	}
+
+	var r0 []types.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]types.Role, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) []types.Role); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateUser provides a mock function with given fields: user
 func (_m *UserService) UpdateUser(user *models.User) error {
 	ret := _m.Called(user)
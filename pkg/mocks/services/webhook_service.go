@@ -0,0 +1,263 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "backend-developer-assignment/app/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookService is an autogenerated mock type for the WebhookService type
+type WebhookService struct {
+	mock.Mock
+}
+
+// CreateEndpoint provides a mock function with given fields: userID, scope, endpointURL, description, eventTypes
+func (_m *WebhookService) CreateEndpoint(userID string, scope models.WebhookScope, endpointURL string, description string, eventTypes models.EventTypeList) (*models.WebhookEndpoint, error) {
+	ret := _m.Called(userID, scope, endpointURL, description, eventTypes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEndpoint")
+	}
+
+	var r0 *models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.WebhookScope, string, string, models.EventTypeList) (*models.WebhookEndpoint, error)); ok {
+		return rf(userID, scope, endpointURL, description, eventTypes)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.WebhookScope, string, string, models.EventTypeList) *models.WebhookEndpoint); ok {
+		r0 = rf(userID, scope, endpointURL, description, eventTypes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.WebhookScope, string, string, models.EventTypeList) error); ok {
+		r1 = rf(userID, scope, endpointURL, description, eventTypes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEndpoint provides a mock function with given fields: endpointID
+func (_m *WebhookService) DeleteEndpoint(endpointID string) error {
+	ret := _m.Called(endpointID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEndpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(endpointID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeliverDue provides a mock function with given fields: ctx
+func (_m *WebhookService) DeliverDue(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeliverDue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetEndpointByID provides a mock function with given fields: endpointID
+func (_m *WebhookService) GetEndpointByID(endpointID string) (*models.WebhookEndpoint, error) {
+	ret := _m.Called(endpointID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointByID")
+	}
+
+	var r0 *models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.WebhookEndpoint, error)); ok {
+		return rf(endpointID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.WebhookEndpoint); ok {
+		r0 = rf(endpointID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(endpointID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointsByScope provides a mock function with given fields: scope
+func (_m *WebhookService) GetEndpointsByScope(scope models.WebhookScope) ([]*models.WebhookEndpoint, error) {
+	ret := _m.Called(scope)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointsByScope")
+	}
+
+	var r0 []*models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.WebhookScope) ([]*models.WebhookEndpoint, error)); ok {
+		return rf(scope)
+	}
+	if rf, ok := ret.Get(0).(func(models.WebhookScope) []*models.WebhookEndpoint); ok {
+		r0 = rf(scope)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.WebhookScope) error); ok {
+		r1 = rf(scope)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointsByUserID provides a mock function with given fields: userID
+func (_m *WebhookService) GetEndpointsByUserID(userID string) ([]*models.WebhookEndpoint, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndpointsByUserID")
+	}
+
+	var r0 []*models.WebhookEndpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*models.WebhookEndpoint, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*models.WebhookEndpoint); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookEndpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: endpointID, filter
+func (_m *WebhookService) ListDeliveries(endpointID string, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	ret := _m.Called(endpointID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []*models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error)); ok {
+		return rf(endpointID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.WebhookDeliveryFilter) []*models.WebhookDelivery); ok {
+		r0 = rf(endpointID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.WebhookDeliveryFilter) error); ok {
+		r1 = rf(endpointID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *WebhookService) Publish(ctx context.Context, event *models.DomainEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DomainEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayDelivery provides a mock function with given fields: endpointID, deliveryID
+func (_m *WebhookService) ReplayDelivery(endpointID string, deliveryID int64) (*models.WebhookDelivery, error) {
+	ret := _m.Called(endpointID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDelivery")
+	}
+
+	var r0 *models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64) (*models.WebhookDelivery, error)); ok {
+		return rf(endpointID, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64) *models.WebhookDelivery); ok {
+		r0 = rf(endpointID, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(endpointID, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookService creates a new instance of WebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookService {
+	mock := &WebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"backend-developer-assignment/pkg/middleware"
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work. It should return promptly once ctx is cancelled.
+type Job func(ctx context.Context) error
+
+// Scheduler runs a job on a fixed interval in its own goroutine until it is stopped
+type Scheduler struct {
+	name     string
+	interval time.Duration
+	job      Job
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a scheduler that runs job every interval
+func New(name string, interval time.Duration, job Job) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+// Start runs the job immediately and then on every tick. Calling Start on a running scheduler does nothing.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx, s.done)
+}
+
+// Stop cancels the running job and waits for it to return
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (s *Scheduler) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	logger := middleware.GetLogger()
+	logger.Info("Scheduler started", zap.String("scheduler", s.name), zap.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runJob(ctx, logger)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Scheduler stopped", zap.String("scheduler", s.name))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJob runs the job once, a panic is logged instead of taking down the process
+func (s *Scheduler) runJob(ctx context.Context, logger *zap.Logger) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Scheduler job panicked", zap.String("scheduler", s.name), zap.Any("panic", r))
+		}
+	}()
+
+	if err := s.job(ctx); err != nil && ctx.Err() == nil {
+		logger.Error("Scheduler job failed", zap.String("scheduler", s.name), zap.Error(err))
+	}
+}
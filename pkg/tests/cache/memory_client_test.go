@@ -0,0 +1,135 @@
+package cache_test
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/platform/cache"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryClientGetSet(t *testing.T) {
+	ctx := context.Background()
+	client := cache.NewMemoryClient(10)
+
+	_, err := client.Get(ctx, "missing")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+
+	// Values are kept as Redis would write them
+	require.NoError(t, client.Set(ctx, "string", "value", time.Minute))
+	require.NoError(t, client.Set(ctx, "bytes", []byte(`{"id":1}`), time.Minute))
+	require.NoError(t, client.Set(ctx, "int", 42, time.Minute))
+	require.NoError(t, client.Set(ctx, "bool", true, time.Minute))
+	assert.Error(t, client.Set(ctx, "struct", struct{}{}, time.Minute))
+
+	for key, expected := range map[string]string{"string": "value", "bytes": `{"id":1}`, "int": "42", "bool": "1"} {
+		value, err := client.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, key)
+	}
+
+	require.NoError(t, client.Delete(ctx, "string"))
+	_, err = client.Get(ctx, "string")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+}
+
+func TestMemoryClientExpiry(t *testing.T) {
+	ctx := context.Background()
+	client := cache.NewMemoryClient(10)
+
+	require.NoError(t, client.Set(ctx, "short", "value", 20*time.Millisecond))
+	require.NoError(t, client.Set(ctx, "forever", "value", 0))
+
+	time.Sleep(40 * time.Millisecond)
+
+	_, err := client.Get(ctx, "short")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+	_, err = client.Get(ctx, "forever")
+	assert.NoError(t, err)
+}
+
+func TestMemoryClientEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	client := cache.NewMemoryClient(3)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key-%d", i), i, time.Minute))
+	}
+
+	// Reading key-1 makes key-2 the least recently used
+	_, err := client.Get(ctx, "key-1")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "key-4", 4, time.Minute))
+
+	assert.Equal(t, 3, client.Len())
+	_, err = client.Get(ctx, "key-2")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+	for _, key := range []string{"key-1", "key-3", "key-4"} {
+		_, err := client.Get(ctx, key)
+		assert.NoError(t, err, key)
+	}
+}
+
+func TestMemoryClientIncrement(t *testing.T) {
+	ctx := context.Background()
+	client := cache.NewMemoryClient(10)
+
+	for expected := int64(1); expected <= 3; expected++ {
+		count, err := client.Increment(ctx, "counter", 20*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, expected, count)
+	}
+
+	// The window starts with the counter and is not extended by later increments
+	time.Sleep(40 * time.Millisecond)
+	count, err := client.Increment(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, client.Set(ctx, "text", "abc", time.Minute))
+	_, err = client.Increment(ctx, "text", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestMemoryClientDecrement(t *testing.T) {
+	ctx := context.Background()
+	client := cache.NewMemoryClient(10)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Increment(ctx, "counter", time.Minute)
+		require.NoError(t, err)
+	}
+	count, err := client.Decrement(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// A missing counter is not created
+	count, err = client.Decrement(ctx, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	_, err = client.Get(ctx, "missing")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+}
+
+func TestMemoryClientNamespaces(t *testing.T) {
+	ctx := context.Background()
+	client := cache.NewMemoryClient(10)
+
+	version, err := client.NamespaceVersion(ctx, "transactions:user:user-123")
+	require.NoError(t, err)
+	assert.Equal(t, "0", version)
+
+	require.NoError(t, client.InvalidateNamespace(ctx, "transactions:user:user-123"))
+	invalidated, err := client.NamespaceVersion(ctx, "transactions:user:user-123")
+	require.NoError(t, err)
+	assert.NotEqual(t, version, invalidated)
+
+	// Other namespaces keep their version
+	other, err := client.NamespaceVersion(ctx, "transactions:user:user-456")
+	require.NoError(t, err)
+	assert.Equal(t, "0", other)
+}
@@ -0,0 +1,221 @@
+package cache_test
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/platform/cache"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedCache stands in for Redis: a MemoryClient counting the reads, with an in-process pub/sub channel
+type sharedCache struct {
+	*cache.MemoryClient
+
+	mu          sync.Mutex
+	reads       int
+	subscribers []chan string
+	publishErr  error
+}
+
+func newSharedCache() *sharedCache {
+	return &sharedCache{MemoryClient: cache.NewMemoryClient(100)}
+}
+
+func (s *sharedCache) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	s.reads++
+	s.mu.Unlock()
+	return s.MemoryClient.Get(ctx, key)
+}
+
+func (s *sharedCache) NamespaceVersion(ctx context.Context, namespace string) (string, error) {
+	s.mu.Lock()
+	s.reads++
+	s.mu.Unlock()
+	return s.MemoryClient.NamespaceVersion(ctx, namespace)
+}
+
+func (s *sharedCache) readCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reads
+}
+
+func (s *sharedCache) PublishMessage(ctx context.Context, channel, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.publishErr != nil {
+		return s.publishErr
+	}
+	for _, subscriber := range s.subscribers {
+		subscriber <- message
+	}
+	return nil
+}
+
+func (s *sharedCache) SubscribeChannel(ctx context.Context, channel string) (<-chan string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make(chan string, 100)
+	s.subscribers = append(s.subscribers, messages)
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, subscriber := range s.subscribers {
+			if subscriber == messages {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				break
+			}
+		}
+		close(messages)
+	}()
+	return messages, nil
+}
+
+func newTieredClient(t *testing.T, shared *sharedCache) *cache.TieredClient {
+	client, err := cache.NewTieredClient(cache.NewMemoryClient(10), shared, "cache-invalidations", time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestTieredClientReadsLocalCopy(t *testing.T) {
+	ctx := context.Background()
+	shared := newSharedCache()
+	client := newTieredClient(t, shared)
+
+	require.NoError(t, shared.Set(ctx, "transaction:txn-1", `{"transaction_id":"txn-1"}`, time.Hour))
+
+	for i := 0; i < 3; i++ {
+		value, err := client.Get(ctx, "transaction:txn-1")
+		require.NoError(t, err)
+		assert.Equal(t, `{"transaction_id":"txn-1"}`, value)
+	}
+	assert.Equal(t, 1, shared.readCount(), "only the first read goes to the shared cache")
+
+	// Misses are not kept locally
+	_, err := client.Get(ctx, "transaction:txn-2")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+	_, err = client.Get(ctx, "transaction:txn-2")
+	assert.ErrorIs(t, err, types.ErrCacheMiss)
+	assert.Equal(t, 3, shared.readCount())
+}
+
+func TestTieredClientBroadcastsWrites(t *testing.T) {
+	ctx := context.Background()
+	shared := newSharedCache()
+	writer := newTieredClient(t, shared)
+	reader := newTieredClient(t, shared)
+
+	require.NoError(t, writer.Set(ctx, "transaction:txn-1", "v1", time.Hour))
+	value, err := reader.Get(ctx, "transaction:txn-1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	// The reader drops its copy once the write is broadcast
+	require.NoError(t, writer.Set(ctx, "transaction:txn-1", "v2", time.Hour))
+	assert.Eventually(t, func() bool {
+		value, err := reader.Get(ctx, "transaction:txn-1")
+		return err == nil && value == "v2"
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, writer.Delete(ctx, "transaction:txn-1"))
+	assert.Eventually(t, func() bool {
+		_, err := reader.Get(ctx, "transaction:txn-1")
+		return errors.Is(err, types.ErrCacheMiss)
+	}, time.Second, 5*time.Millisecond)
+
+	// The writer serves its own write locally
+	require.NoError(t, writer.Set(ctx, "transaction:txn-3", "v1", time.Hour))
+	reads := shared.readCount()
+	value, err = writer.Get(ctx, "transaction:txn-3")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+	assert.Equal(t, reads, shared.readCount())
+}
+
+func TestTieredClientNamespaces(t *testing.T) {
+	ctx := context.Background()
+	shared := newSharedCache()
+	writer := newTieredClient(t, shared)
+	reader := newTieredClient(t, shared)
+
+	version, err := reader.NamespaceVersion(ctx, "transactions:account:acc-123")
+	require.NoError(t, err)
+	assert.Equal(t, "0", version)
+
+	require.NoError(t, writer.InvalidateNamespace(ctx, "transactions:account:acc-123"))
+	assert.Eventually(t, func() bool {
+		invalidated, err := reader.NamespaceVersion(ctx, "transactions:account:acc-123")
+		return err == nil && invalidated != version
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestTieredClientIncrementIsShared(t *testing.T) {
+	ctx := context.Background()
+	shared := newSharedCache()
+	first := newTieredClient(t, shared)
+	second := newTieredClient(t, shared)
+
+	_, err := first.Increment(ctx, "pin_ip_failures:10.0.0.1", time.Minute)
+	require.NoError(t, err)
+	value, err := second.Get(ctx, "pin_ip_failures:10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+
+	count, err := first.Increment(ctx, "pin_ip_failures:10.0.0.1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Eventually(t, func() bool {
+		value, err := second.Get(ctx, "pin_ip_failures:10.0.0.1")
+		return err == nil && value == "2"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestTieredClientBroadcastFailure(t *testing.T) {
+	ctx := context.Background()
+	shared := newSharedCache()
+	client := newTieredClient(t, shared)
+
+	// The value is written, the caller learns the other instances may keep a stale copy
+	shared.mu.Lock()
+	shared.publishErr = errors.New("connection refused")
+	shared.mu.Unlock()
+	err := client.Set(ctx, "transaction:txn-1", "v1", time.Hour)
+	assert.ErrorContains(t, err, "failed to broadcast cache invalidation")
+
+	value, err := shared.MemoryClient.Get(ctx, "transaction:txn-1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+}
+
+func TestTieredClientStopsCopyingOnClose(t *testing.T) {
+	ctx := context.Background()
+	shared := newSharedCache()
+	client, err := cache.NewTieredClient(cache.NewMemoryClient(10), shared, "cache-invalidations", time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, shared.Set(ctx, "transaction:txn-1", "v1", time.Hour))
+	_, err = client.Get(ctx, "transaction:txn-1")
+	require.NoError(t, err)
+
+	// Without the subscription, every read goes to the shared cache
+	require.NoError(t, client.Close())
+	assert.Eventually(t, func() bool {
+		reads := shared.readCount()
+		_, err := client.Get(ctx, "transaction:txn-1")
+		return err == nil && shared.readCount() == reads+1
+	}, time.Second, 5*time.Millisecond)
+	reads := shared.readCount()
+	_, err = client.Get(ctx, "transaction:txn-1")
+	require.NoError(t, err)
+	assert.Equal(t, reads+1, shared.readCount())
+}
@@ -0,0 +1,30 @@
+package configs_test
+
+import (
+	"backend-developer-assignment/pkg/configs"
+	"backend-developer-assignment/platform/cache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheConnectionMemory(t *testing.T) {
+	t.Setenv("CACHE_MODE", "memory")
+
+	t.Setenv("APP_ENV", "prod")
+	_, _, err := configs.CacheConnection(nil)
+	assert.Error(t, err, "production instances share the cache")
+
+	t.Setenv("APP_ENV", "dev")
+	cacheClient, stop, err := configs.CacheConnection(nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &cache.MemoryClient{}, cacheClient)
+	stop()
+}
+
+func TestCacheConnectionUnknownMode(t *testing.T) {
+	t.Setenv("CACHE_MODE", "memcached")
+
+	_, _, err := configs.CacheConnection(nil)
+	assert.Error(t, err)
+}
@@ -23,35 +23,44 @@ import (
 // AccountControllerTestSuite defines the test suite
 type AccountControllerTestSuite struct {
 	suite.Suite
-	app             *fiber.App
-	accountService  *mocks.AccountService
-	controller      *controllers.AccountController
-	testUserID      string
-	testAccountID   string
-	testAccountData *models.AccountWithDetails
+	app              *fiber.App
+	accountService   *mocks.AccountService
+	challengeService *mocks.ChallengeService
+	auditService     *mocks.AuditService
+	controller       *controllers.AccountController
+	testUserID       string
+	testAccountID    string
+	testAccountData  *models.AccountWithDetails
 }
 
 // SetupTest runs before each test
 func (s *AccountControllerTestSuite) SetupTest() {
 	s.app = fiber.New()
 	s.accountService = new(mocks.AccountService)
-	s.controller = controllers.NewAccountController(s.accountService)
+	s.challengeService = new(mocks.ChallengeService)
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.controller = controllers.NewAccountController(s.accountService, s.challengeService, s.auditService)
+
+	// Transfers run right away unless a test asks for step-up authentication
+	s.challengeService.On("RequiresStepUp", mock.Anything).Return(false).Maybe()
 	s.testUserID = "test-user-id"
 	s.testAccountID = "test-account-id"
 
 	now := time.Now()
 	s.testAccountData = &models.AccountWithDetails{
-		AccountID:     s.testAccountID,
-		UserID:        s.testUserID,
-		Type:          "saving-account",
-		Currency:      "USD",
-		AccountNumber: "123456789",
-		Issuer:        "TestBank",
-		Color:         "#FF0000",
-		Progress:      0,
-		Amount:        1000.0,
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		AccountID:       s.testAccountID,
+		UserID:          s.testUserID,
+		Type:            "saving-account",
+		Currency:        "USD",
+		AccountNumber:   "123456789",
+		Issuer:          "TestBank",
+		Color:           "#FF0000",
+		Progress:        0,
+		Amount:          usd(100000),
+		AvailableAmount: usd(100000),
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
 	// Setup routes
@@ -316,13 +325,13 @@ func (s *AccountControllerTestSuite) TestSetMainAccount() {
 func (s *AccountControllerTestSuite) TestWithdraw() {
 	// Test case: successful withdrawal
 	withdrawRequest := map[string]interface{}{
-		"amount": 500.0,
+		"amount": "500.00",
 	}
 
 	requestBody, _ := json.Marshal(withdrawRequest)
 
 	s.accountService.On("GetAccountWithDetailByID", s.testAccountID).Return(s.testAccountData, nil).Once()
-	s.accountService.On("WithdrawFromAccount", s.testAccountID, 500.0).Return(500.0, nil).Once()
+	s.accountService.On("WithdrawFromAccount", s.testAccountID, usd(50000)).Return(usd(50000), nil).Once()
 
 	req := httptest.NewRequest(http.MethodPost, "/accounts/"+s.testAccountID+"/withdraw", bytes.NewReader(requestBody))
 	req.Header.Set("Content-Type", "application/json")
@@ -335,14 +344,19 @@ func (s *AccountControllerTestSuite) TestWithdraw() {
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "Withdrawal successful", response["message"])
-	assert.Equal(s.T(), 500.0, response["amount"])
-	assert.Equal(s.T(), 500.0, response["balance"])
+	assert.Equal(s.T(), moneyJSON("500.00", "USD"), response["amount"])
+	assert.Equal(s.T(), moneyJSON("500.00", "USD"), response["balance"])
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditWithdrawal && entry.Outcome == "" && entry.ActorID == s.testUserID && entry.ResourceID == s.testAccountID
+	}), fiber.Map{"balance": usd(100000)}, fiber.Map{"amount": usd(50000), "balance": usd(50000)})
 
 	// Test case: insufficient funds
 	s.accountService.On("GetAccountWithDetailByID", "low-balance-id").Return(&models.AccountWithDetails{
-		AccountID: "low-balance-id",
-		UserID:    s.testUserID,
-		Amount:    100.0,
+		AccountID:       "low-balance-id",
+		UserID:          s.testUserID,
+		Currency:        "USD",
+		Amount:          usd(10000),
+		AvailableAmount: usd(10000),
 	}, nil).Once()
 
 	req = httptest.NewRequest(http.MethodPost, "/accounts/low-balance-id/withdraw", bytes.NewReader(requestBody))
@@ -352,9 +366,35 @@ func (s *AccountControllerTestSuite) TestWithdraw() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
 
+	// Test case: the balance covers the amount but most of it is on hold
+	s.accountService.On("GetAccountWithDetailByID", "held-balance-id").Return(&models.AccountWithDetails{
+		AccountID:       "held-balance-id",
+		UserID:          s.testUserID,
+		Currency:        "USD",
+		Amount:          usd(100000),
+		AvailableAmount: usd(10000),
+	}, nil).Once()
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/held-balance-id/withdraw", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+
+	// Test case: amount with more precision than the currency allows
+	s.accountService.On("GetAccountWithDetailByID", s.testAccountID).Return(s.testAccountData, nil).Once()
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/"+s.testAccountID+"/withdraw", bytes.NewReader([]byte(`{"amount": 10.001}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+
 	// Test case: service error
 	s.accountService.On("GetAccountWithDetailByID", "error-id").Return(s.testAccountData, nil).Once()
-	s.accountService.On("WithdrawFromAccount", "error-id", 500.0).Return(0.0, errors.New("database error")).Once()
+	s.accountService.On("WithdrawFromAccount", "error-id", usd(50000)).Return(types.Money{}, errors.New("database error")).Once()
 
 	req = httptest.NewRequest(http.MethodPost, "/accounts/error-id/withdraw", bytes.NewReader(requestBody))
 	req.Header.Set("Content-Type", "application/json")
@@ -362,20 +402,20 @@ func (s *AccountControllerTestSuite) TestWithdraw() {
 
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), http.StatusInternalServerError, resp.StatusCode)
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditWithdrawal && entry.Outcome == models.AuditFailure && entry.ResourceID == "error-id"
+	}), mock.Anything, mock.Anything)
 
 	s.accountService.AssertExpectations(s.T())
 }
 
 // TestDeposit tests the Deposit controller method
 func (s *AccountControllerTestSuite) TestDeposit() {
-	// Test case: successful deposit
-	depositRequest := map[string]interface{}{
-		"amount": 500.0,
-	}
-
-	requestBody, _ := json.Marshal(depositRequest)
+	// Test case: successful deposit, numeric amounts are parsed without rounding through float64
+	requestBody := []byte(`{"amount": 500.10}`)
 
-	s.accountService.On("DepositToAccount", s.testAccountID, 500.0).Return(1500.0, nil).Once()
+	s.accountService.On("GetAccountWithDetailByID", s.testAccountID).Return(s.testAccountData, nil).Once()
+	s.accountService.On("DepositToAccount", s.testAccountID, usd(50010)).Return(usd(150010), nil).Once()
 
 	req := httptest.NewRequest(http.MethodPost, "/accounts/"+s.testAccountID+"/deposit", bytes.NewReader(requestBody))
 	req.Header.Set("Content-Type", "application/json")
@@ -388,11 +428,32 @@ func (s *AccountControllerTestSuite) TestDeposit() {
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "Deposit successful", response["message"])
-	assert.Equal(s.T(), 500.0, response["amount"])
-	assert.Equal(s.T(), 1500.0, response["balance"])
+	assert.Equal(s.T(), moneyJSON("500.10", "USD"), response["amount"])
+	assert.Equal(s.T(), moneyJSON("1500.10", "USD"), response["balance"])
+
+	// Test case: account not found
+	s.accountService.On("GetAccountWithDetailByID", "nonexistent-id").Return(nil, errors.New("account not found")).Once()
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/nonexistent-id/deposit", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+
+	// Test case: negative amount
+	s.accountService.On("GetAccountWithDetailByID", s.testAccountID).Return(s.testAccountData, nil).Once()
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/"+s.testAccountID+"/deposit", bytes.NewReader([]byte(`{"amount": "-5"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
 
 	// Test case: service error
-	s.accountService.On("DepositToAccount", "error-id", 500.0).Return(0.0, errors.New("database error")).Once()
+	s.accountService.On("GetAccountWithDetailByID", "error-id").Return(s.testAccountData, nil).Once()
+	s.accountService.On("DepositToAccount", "error-id", usd(50010)).Return(types.Money{}, errors.New("database error")).Once()
 
 	req = httptest.NewRequest(http.MethodPost, "/accounts/error-id/deposit", bytes.NewReader(requestBody))
 	req.Header.Set("Content-Type", "application/json")
@@ -410,17 +471,18 @@ func (s *AccountControllerTestSuite) TestTransfer() {
 	transferRequest := map[string]interface{}{
 		"from_account_id": "source-account-id",
 		"to_account_id":   "dest-account-id",
-		"amount":          500.0,
+		"amount":          "500.00",
 	}
 
 	requestBody, _ := json.Marshal(transferRequest)
 
 	transferResult := &types.TransferResult{
-		SourceBalance:      500.0,
-		DestinationBalance: 1500.0,
+		SourceBalance:      usd(50000),
+		DestinationBalance: usd(150000),
 	}
 
-	s.accountService.On("TransferBetweenAccounts", "source-account-id", "dest-account-id", 500.0).Return(transferResult, nil).Once()
+	s.accountService.On("GetAccountWithDetailByID", "source-account-id").Return(s.testAccountData, nil).Once()
+	s.accountService.On("TransferBetweenAccounts", "source-account-id", "dest-account-id", usd(50000)).Return(transferResult, nil).Once()
 
 	req := httptest.NewRequest(http.MethodPost, "/accounts/transfer", bytes.NewReader(requestBody))
 	req.Header.Set("Content-Type", "application/json")
@@ -433,14 +495,15 @@ func (s *AccountControllerTestSuite) TestTransfer() {
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "Transfer successful", response["message"])
-	assert.Equal(s.T(), 500.0, response["amount"])
+	assert.Equal(s.T(), moneyJSON("500.00", "USD"), response["amount"])
 	assert.Equal(s.T(), "source-account-id", response["from_account"])
 	assert.Equal(s.T(), "dest-account-id", response["to_account"])
-	assert.Equal(s.T(), 500.0, response["source_balance"])
-	assert.Equal(s.T(), 1500.0, response["destination_balance"])
+	assert.Equal(s.T(), moneyJSON("500.00", "USD"), response["source_balance"])
+	assert.Equal(s.T(), moneyJSON("1500.00", "USD"), response["destination_balance"])
 
 	// Test case: insufficient funds
-	s.accountService.On("TransferBetweenAccounts", "low-balance-id", "dest-account-id", 500.0).Return(nil, services.ErrInsufficientFunds).Once()
+	s.accountService.On("GetAccountWithDetailByID", "low-balance-id").Return(s.testAccountData, nil).Once()
+	s.accountService.On("TransferBetweenAccounts", "low-balance-id", "dest-account-id", usd(50000)).Return(nil, services.ErrInsufficientFunds).Once()
 
 	transferRequest["from_account_id"] = "low-balance-id"
 	requestBody, _ = json.Marshal(transferRequest)
@@ -452,8 +515,23 @@ func (s *AccountControllerTestSuite) TestTransfer() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
 
+	// Test case: currency mismatch between accounts
+	s.accountService.On("GetAccountWithDetailByID", "thb-account-id").Return(s.testAccountData, nil).Once()
+	s.accountService.On("TransferBetweenAccounts", "thb-account-id", "dest-account-id", usd(50000)).Return(nil, services.ErrCurrencyMismatch).Once()
+
+	transferRequest["from_account_id"] = "thb-account-id"
+	requestBody, _ = json.Marshal(transferRequest)
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/transfer", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+
 	// Test case: service error
-	s.accountService.On("TransferBetweenAccounts", "error-id", "dest-account-id", 500.0).Return(nil, errors.New("database error")).Once()
+	s.accountService.On("GetAccountWithDetailByID", "error-id").Return(s.testAccountData, nil).Once()
+	s.accountService.On("TransferBetweenAccounts", "error-id", "dest-account-id", usd(50000)).Return(nil, errors.New("database error")).Once()
 
 	transferRequest["from_account_id"] = "error-id"
 	requestBody, _ = json.Marshal(transferRequest)
@@ -465,9 +543,138 @@ func (s *AccountControllerTestSuite) TestTransfer() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), http.StatusInternalServerError, resp.StatusCode)
 
+	// Test case: source account of another user
+	s.accountService.On("GetAccountWithDetailByID", "foreign-account-id").Return(&models.AccountWithDetails{
+		AccountID: "foreign-account-id",
+		UserID:    "other-user-id",
+		Currency:  "USD",
+		Amount:    usd(100000),
+	}, nil).Once()
+
+	transferRequest["from_account_id"] = "foreign-account-id"
+	requestBody, _ = json.Marshal(transferRequest)
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/transfer", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", "foreign-account-id", mock.Anything, mock.Anything)
+
+	// Test case: transfer to the source account itself
+	transferRequest["from_account_id"] = "dest-account-id"
+	requestBody, _ = json.Marshal(transferRequest)
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/transfer", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", "dest-account-id", "dest-account-id", mock.Anything)
+
 	s.accountService.AssertExpectations(s.T())
 }
 
+// TestTransferWithQuote tests transfers between accounts of different currencies
+func (s *AccountControllerTestSuite) TestTransferWithQuote() {
+	rate := types.MustParseRate("36.5")
+	transferResult := &types.TransferResult{
+		SourceBalance:      usd(50000),
+		DestinationBalance: types.NewMoney(1825000, "THB"),
+		CreditedAmount:     types.NewMoney(1825000, "THB"),
+		ExchangeRate:       &rate,
+	}
+
+	testCases := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusOK},
+		{name: "Quote required", serviceErr: services.ErrQuoteRequired, expectedStatus: http.StatusBadRequest},
+		{name: "Quote does not match", serviceErr: services.ErrQuoteMismatch, expectedStatus: http.StatusBadRequest},
+		{name: "Quote not found", serviceErr: services.ErrQuoteNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Quote expired", serviceErr: services.ErrQuoteExpired, expectedStatus: http.StatusConflict},
+		{name: "Quote used", serviceErr: services.ErrQuoteUsed, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			result := transferResult
+			if tc.serviceErr != nil {
+				result = nil
+			}
+			s.accountService.On("GetAccountWithDetailByID", "source-account-id").Return(s.testAccountData, nil).Once()
+			s.accountService.On("TransferWithQuote", "source-account-id", "thb-account-id", usd(50000), "quote-123").Return(result, tc.serviceErr).Once()
+
+			requestBody, _ := json.Marshal(map[string]interface{}{
+				"from_account_id": "source-account-id",
+				"to_account_id":   "thb-account-id",
+				"amount":          "500.00",
+				"quote_id":        "quote-123",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/accounts/transfer", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := s.app.Test(req)
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+			if tc.serviceErr != nil {
+				return
+			}
+
+			var response map[string]interface{}
+			assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(s.T(), moneyJSON("500.00", "USD"), response["amount"])
+			assert.Equal(s.T(), moneyJSON("18250.00", "THB"), response["credited_amount"])
+			assert.Equal(s.T(), "36.50000000", response["exchange_rate"])
+		})
+	}
+
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestTransferStepUp tests that a transfer above the threshold waits for a challenge instead of running
+func (s *AccountControllerTestSuite) TestTransferStepUp() {
+	s.challengeService.ExpectedCalls = nil
+	expiresAt := time.Now().Add(5 * time.Minute)
+	expectedTransfer := &models.PendingTransfer{FromAccountID: "source-account-id", ToAccountID: "dest-account-id", Amount: usd(500000)}
+	s.accountService.On("GetAccountWithDetailByID", "source-account-id").Return(s.testAccountData, nil).Once()
+	s.challengeService.On("RequiresStepUp", usd(500000)).Return(true).Once()
+	s.challengeService.On("CreateTransferChallenge", s.testUserID, expectedTransfer).
+		Return(&models.Challenge{ChallengeID: "challenge-123", UserID: s.testUserID, ExpiresAt: expiresAt}, nil).Once()
+
+	requestBody, _ := json.Marshal(map[string]interface{}{
+		"from_account_id": "source-account-id",
+		"to_account_id":   "dest-account-id",
+		"amount":          "5000.00",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/accounts/transfer", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusAccepted, resp.StatusCode)
+
+	var response map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(s.T(), "challenge-123", response["challenge_id"])
+	s.challengeService.AssertExpectations(s.T())
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// usd creates a USD amount from cents
+func usd(cents int64) types.Money {
+	return types.NewMoney(cents, "USD")
+}
+
+// moneyJSON is how a types.Money value looks after decoding a response into a generic map
+func moneyJSON(amount, currency string) map[string]interface{} {
+	return map[string]interface{}{"amount": amount, "currency": currency}
+}
+
 // TestAccountControllerSuite runs the test suite
 func TestAccountControllerSuite(t *testing.T) {
 	suite.Run(t, new(AccountControllerTestSuite))
@@ -0,0 +1,193 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// AdminControllerTestSuite defines the test suite
+type AdminControllerTestSuite struct {
+	suite.Suite
+	app              *fiber.App
+	userService      *mocks.UserService
+	accountService   *mocks.AccountService
+	debitCardService *mocks.DebitCardService
+	bannerService    *mocks.BannerService
+	auditService     *mocks.AuditService
+	controller       *controllers.AdminController
+}
+
+// SetupTest runs before each test
+func (s *AdminControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.userService = new(mocks.UserService)
+	s.accountService = new(mocks.AccountService)
+	s.debitCardService = new(mocks.DebitCardService)
+	s.bannerService = new(mocks.BannerService)
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.controller = controllers.NewAdminController(s.userService, s.accountService, s.debitCardService, s.bannerService, s.auditService)
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", "staff-user")
+		return c.Next()
+	})
+	s.app.Get("/admin/users/:id", s.controller.GetUser)
+	s.app.Post("/admin/accounts/:id/freeze", s.controller.FreezeAccount)
+	s.app.Post("/admin/accounts/:id/unfreeze", s.controller.UnfreezeAccount)
+	s.app.Put("/admin/debit-cards/:id/status", s.controller.UpdateCardStatus)
+	s.app.Post("/admin/banners", s.controller.CreateBanner)
+	s.app.Patch("/admin/banners/:id", s.controller.UpdateBanner)
+	s.app.Delete("/admin/banners/:id", s.controller.DeleteBanner)
+	s.app.Get("/admin/audit-logs", s.controller.ListAuditLogs)
+}
+
+func (s *AdminControllerTestSuite) request(method, path, body string) *http.Response {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestGetUser tests the GetUser controller method
+func (s *AdminControllerTestSuite) TestGetUser() {
+	s.userService.On("GetUserByID", "user-123").Return(&models.User{UserID: "user-123", Name: "Jane"}, nil).Once()
+	s.userService.On("GetUserRoles", "user-123").Return([]types.Role{}, nil).Once()
+	s.accountService.On("GetAccountsWithDetailByUserID", "user-123").
+		Return([]*models.AccountWithDetails{{AccountID: "acc-123", UserID: "user-123"}}, nil).Once()
+	s.debitCardService.On("GetCardWithDetailByUserID", "user-123").Return([]*models.DebitCardWithDetails{}, nil).Once()
+
+	resp := s.request(http.MethodGet, "/admin/users/user-123", "")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var overview models.UserOverview
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&overview))
+	assert.Equal(s.T(), "user-123", overview.User.UserID)
+	assert.Len(s.T(), overview.Accounts, 1)
+
+	s.userService.On("GetUserByID", "missing-user").Return(nil, sql.ErrNoRows).Once()
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodGet, "/admin/users/missing-user", "").StatusCode)
+}
+
+// TestFreezeAccount tests the FreezeAccount and UnfreezeAccount controller methods
+func (s *AdminControllerTestSuite) TestFreezeAccount() {
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodPost, "/admin/accounts/acc-123/freeze", `{}`).StatusCode,
+		"a freeze needs a reason")
+
+	s.accountService.On("FreezeAccount", "acc-123").Return(&models.AccountWithDetails{AccountID: "acc-123"}, nil).Once()
+	resp := s.request(http.MethodPost, "/admin/accounts/acc-123/freeze", `{"reason": "Reported as compromised"}`)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditAccountFreeze && entry.ActorID == "staff-user" &&
+			entry.ActorType == models.AuditActorStaff && entry.ResourceID == "acc-123"
+	}), mock.Anything, mock.Anything)
+
+	s.accountService.On("FreezeAccount", "acc-123").Return(nil, services.ErrAccountFrozen).Once()
+	resp = s.request(http.MethodPost, "/admin/accounts/acc-123/freeze", `{"reason": "Reported as compromised"}`)
+	assert.Equal(s.T(), http.StatusConflict, resp.StatusCode)
+
+	s.accountService.On("FreezeAccount", "missing-account").Return(nil, sql.ErrNoRows).Once()
+	resp = s.request(http.MethodPost, "/admin/accounts/missing-account/freeze", `{"reason": "Reported as compromised"}`)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+
+	s.accountService.On("UnfreezeAccount", "acc-123").Return(nil, services.ErrAccountNotFrozen).Once()
+	assert.Equal(s.T(), http.StatusConflict, s.request(http.MethodPost, "/admin/accounts/acc-123/unfreeze", "").StatusCode)
+
+	s.accountService.On("UnfreezeAccount", "acc-123").Return(nil, errors.New("database error")).Once()
+	assert.Equal(s.T(), http.StatusInternalServerError, s.request(http.MethodPost, "/admin/accounts/acc-123/unfreeze", "").StatusCode)
+}
+
+// TestUpdateCardStatus tests the UpdateCardStatus controller method
+func (s *AdminControllerTestSuite) TestUpdateCardStatus() {
+	s.debitCardService.On("GetCardWithDetailByID", "card-123").
+		Return(&models.DebitCardWithDetails{CardID: "card-123", Status: string(models.CardStatusBlocked)}, nil).Once()
+	s.debitCardService.On("UpdateCardStatus", "card-123", models.CardStatusActive).
+		Return(&models.DebitCardWithDetails{CardID: "card-123", Status: string(models.CardStatusActive)}, nil).Once()
+
+	resp := s.request(http.MethodPut, "/admin/debit-cards/card-123/status", `{"status": "active"}`)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditCardStatusChange && entry.ResourceID == "card-123"
+	}), fiber.Map{"status": string(models.CardStatusBlocked)}, fiber.Map{"status": string(models.CardStatusActive)})
+
+	resp = s.request(http.MethodPut, "/admin/debit-cards/card-123/status", `{"status": "stolen"}`)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+
+	s.debitCardService.On("GetCardWithDetailByID", "missing-card").Return(nil, sql.ErrNoRows).Once()
+	resp = s.request(http.MethodPut, "/admin/debit-cards/missing-card/status", `{"status": "blocked"}`)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// TestManageBanners tests the banner controller methods
+func (s *AdminControllerTestSuite) TestManageBanners() {
+	s.userService.On("GetUserByID", "user-123").Return(&models.User{UserID: "user-123"}, nil).Once()
+	s.bannerService.On("CreateBanner", "user-123", "Cashback", "5% back on groceries", "").
+		Return(&models.Banner{BannerID: "banner-123", UserID: "user-123", Title: "Cashback"}, nil).Once()
+	resp := s.request(http.MethodPost, "/admin/banners", `{"user_id": "user-123", "title": "Cashback", "description": "5% back on groceries"}`)
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+
+	s.userService.On("GetUserByID", "missing-user").Return(nil, sql.ErrNoRows).Once()
+	resp = s.request(http.MethodPost, "/admin/banners", `{"user_id": "missing-user", "title": "Cashback", "description": "5% back on groceries"}`)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+
+	s.bannerService.On("UpdateBanner", "missing-banner", "New title", "", "").Return(nil, services.ErrBannerNotFound).Once()
+	resp = s.request(http.MethodPatch, "/admin/banners/missing-banner", `{"title": "New title"}`)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+
+	s.bannerService.On("DeleteBanner", "banner-123").Return(nil).Once()
+	assert.Equal(s.T(), http.StatusNoContent, s.request(http.MethodDelete, "/admin/banners/banner-123", "").StatusCode)
+
+	s.bannerService.On("DeleteBanner", "banner-123").Return(services.ErrBannerNotFound).Once()
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodDelete, "/admin/banners/banner-123", "").StatusCode)
+}
+
+// TestListAuditLogs tests the ListAuditLogs controller method
+func (s *AdminControllerTestSuite) TestListAuditLogs() {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	s.auditService.On("List", models.AuditLogFilter{ActorID: "user-123", Action: models.AuditWithdrawal, From: &from, BeforeID: 90, Limit: 2}).
+		Return([]*models.AuditLog{{AuditID: 89}, {AuditID: 85}}, nil).Once()
+
+	resp := s.request(http.MethodGet, "/admin/audit-logs?actor_id=user-123&action=account.withdraw&from=2026-10-01T00:00:00Z&before_id=90&limit=2", "")
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var page struct {
+		AuditLogs    []*models.AuditLog `json:"audit_logs"`
+		NextBeforeID int64              `json:"next_before_id"`
+	}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&page))
+	assert.Len(s.T(), page.AuditLogs, 2)
+	assert.Equal(s.T(), int64(85), page.NextBeforeID, "a full page points at the next one")
+
+	s.auditService.On("List", models.AuditLogFilter{ResourceType: "account", ResourceID: "acc-123"}).
+		Return([]*models.AuditLog{{AuditID: 12}}, nil).Once()
+	resp = s.request(http.MethodGet, "/admin/audit-logs?resource_type=account&resource_id=acc-123", "")
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	page.NextBeforeID = 0
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&page))
+	assert.Zero(s.T(), page.NextBeforeID, "the last page has no next one")
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodGet, "/admin/audit-logs?from=yesterday", "").StatusCode)
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodGet, "/admin/audit-logs?limit=1000", "").StatusCode)
+}
+
+// TestAdminControllerSuite runs the test suite
+func TestAdminControllerSuite(t *testing.T) {
+	suite.Run(t, new(AdminControllerTestSuite))
+}
@@ -3,19 +3,23 @@ package controllers_test
 import (
 	"backend-developer-assignment/app/controllers"
 	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
 	mocks "backend-developer-assignment/pkg/mocks/services"
 	"backend-developer-assignment/pkg/utils"
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -24,6 +28,10 @@ type AuthControllerTestSuite struct {
 	suite.Suite
 	app         *fiber.App
 	mockService *mocks.UserService
+	authService *mocks.AuthService
+	pinLockout  *mocks.PinLockoutService
+	pinService  *mocks.PinService
+	audit       *mocks.AuditService
 	userID      string
 	tokens      *utils.Tokens
 }
@@ -31,7 +39,9 @@ type AuthControllerTestSuite struct {
 // SetupSuite runs once before all tests
 func (s *AuthControllerTestSuite) SetupSuite() {
 	// Set up environment for JWT
-	os.Setenv("JWT_SECRET_KEY", "test-secret-key")
+	keyring, err := utils.NewEphemeralKeyring()
+	s.Require().NoError(err)
+	utils.SetKeyring(keyring)
 	os.Setenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT", "15")
 	os.Setenv("JWT_REFRESH_KEY", "test-refresh-key")
 	os.Setenv("JWT_REFRESH_KEY_EXPIRE_HOURS_COUNT", "720")
@@ -44,14 +54,36 @@ func (s *AuthControllerTestSuite) SetupSuite() {
 func (s *AuthControllerTestSuite) SetupTest() {
 	s.app = fiber.New()
 	s.mockService = new(mocks.UserService)
+	s.authService = new(mocks.AuthService)
+	s.pinLockout = new(mocks.PinLockoutService)
+	s.pinService = new(mocks.PinService)
+
+	s.audit = new(mocks.AuditService)
+	s.audit.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
 
-	authController := controllers.NewAuthController(s.mockService)
+	authController := controllers.NewAuthController(s.mockService, s.authService, s.pinLockout, s.pinService, s.audit)
 	s.app.Post("/verify-pin", authController.VerifyPin)
 	s.app.Post("/token/renew", authController.RenewTokens)
+	s.app.Post("/auth/pin-reset", authController.RequestPinReset)
+	s.app.Post("/auth/pin-reset/confirm", authController.ConfirmPinReset)
+
+	withUser := func(handler fiber.Handler) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("userID", s.userID)
+			return handler(c)
+		}
+	}
+	s.app.Post("/auth/logout", withUser(authController.Logout))
+	s.app.Post("/auth/logout-all", withUser(authController.LogoutAll))
+	s.app.Get("/user/sessions", func(c *fiber.Ctx) error {
+		c.Locals("sessionID", "phone-session")
+		return withUser(authController.ListSessions)(c)
+	})
+	s.app.Delete("/user/sessions/:id", withUser(authController.RevokeSession))
 
 	// Generate tokens for testing
 	var err error
-	s.tokens, err = utils.GenerateNewTokens(s.userID)
+	s.tokens, err = utils.GenerateNewTokens(s.userID, "", nil)
 	s.Require().NoError(err)
 }
 
@@ -86,14 +118,25 @@ func (s *AuthControllerTestSuite) TestVerifyPin_Success() {
 		Name:   "Test User",
 		PIN:    hashPin,
 	}, nil)
+	s.authService.On("IssueTokens", userID, mock.MatchedBy(func(device models.DeviceInfo) bool {
+		return device.DeviceID == "device-1" && device.DeviceName == "Jane's iPhone" && device.Platform == "ios" &&
+			device.UserAgent == "BankApp/3.2 (iPhone; iOS 18.0)" && device.IPAddress != ""
+	})).Return(s.tokens, nil).Once()
+	attempt := &models.PinAttempt{UserID: userID, State: &models.PinLockState{FailedAttempts: 1}}
+	s.pinLockout.On("Reserve", userID, mock.Anything).Return(attempt, nil).Once()
+	s.pinLockout.On("RecordSuccess", attempt).Return(nil).Once()
 
 	// Create request
 	reqBody, _ := json.Marshal(map[string]string{
-		"user_id": userID,
-		"pin":     pin,
+		"user_id":     userID,
+		"pin":         pin,
+		"device_id":   "device-1",
+		"device_name": "Jane's iPhone",
+		"platform":    "ios",
 	})
 	req := httptest.NewRequest(http.MethodPost, "/verify-pin", bytes.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BankApp/3.2 (iPhone; iOS 18.0)")
 
 	// Test the endpoint
 	resp, err := s.app.Test(req)
@@ -110,6 +153,8 @@ func (s *AuthControllerTestSuite) TestVerifyPin_Success() {
 
 	// Verify expected method calls
 	s.mockService.AssertExpectations(s.T())
+	s.authService.AssertExpectations(s.T())
+	s.pinLockout.AssertExpectations(s.T())
 }
 
 // TestVerifyPin_UserNotFound checks if a missing user returns a 404
@@ -118,7 +163,10 @@ func (s *AuthControllerTestSuite) TestVerifyPin_UserNotFound() {
 	userID := uuid.New().String()
 
 	// Setup mock expectations
-	s.mockService.On("GetUserByID", userID).Return(nil, errors.New("user not found"))
+	s.mockService.On("GetUserByID", userID).Return(nil, sql.ErrNoRows)
+	s.pinLockout.On("Reserve", userID, mock.Anything).Return(&models.PinAttempt{UserID: userID}, nil).Once()
+	// The attempt stays counted for the address only
+	s.pinLockout.On("Unlock", userID).Return(nil).Once()
 
 	// Create request
 	reqBody, _ := json.Marshal(map[string]string{
@@ -141,6 +189,22 @@ func (s *AuthControllerTestSuite) TestVerifyPin_UserNotFound() {
 
 	// Verify expected method calls
 	s.mockService.AssertExpectations(s.T())
+	s.pinLockout.AssertExpectations(s.T())
+	s.pinLockout.AssertNotCalled(s.T(), "Release", mock.Anything)
+}
+
+// TestVerifyPin_LookupFailed checks that an attempt is taken back when the user could not be looked up
+func (s *AuthControllerTestSuite) TestVerifyPin_LookupFailed() {
+	attempt := &models.PinAttempt{UserID: s.userID}
+	s.mockService.On("GetUserByID", s.userID).Return(nil, errors.New("database error"))
+	s.pinLockout.On("Reserve", s.userID, mock.Anything).Return(attempt, nil).Once()
+	s.pinLockout.On("Release", attempt).Return(nil).Once()
+
+	resp := s.postJSON("/verify-pin", map[string]string{"user_id": s.userID, "pin": "123456"})
+
+	s.Equal(fiber.StatusNotFound, resp.StatusCode)
+	s.pinLockout.AssertExpectations(s.T())
+	s.pinLockout.AssertNotCalled(s.T(), "Unlock", mock.Anything)
 }
 
 // TestVerifyPin_InvalidPIN checks if incorrect PIN returns an unauthorized error
@@ -156,6 +220,9 @@ func (s *AuthControllerTestSuite) TestVerifyPin_InvalidPIN() {
 		Name:   "Test User",
 		PIN:    hashPin,
 	}, nil)
+	retryAt := time.Now().Add(2 * time.Second)
+	s.pinLockout.On("Reserve", userID, mock.Anything).
+		Return(&models.PinAttempt{UserID: userID, State: &models.PinLockState{FailedAttempts: 2, RetryAt: &retryAt}}, nil).Once()
 
 	// Create request with wrong PIN
 	reqBody, _ := json.Marshal(map[string]string{
@@ -175,9 +242,59 @@ func (s *AuthControllerTestSuite) TestVerifyPin_InvalidPIN() {
 		"message": "Invalid PIN",
 	}
 	s.testResponse(resp, fiber.StatusUnauthorized, expectedBody)
+	s.NotEmpty(resp.Header.Get(fiber.HeaderRetryAfter))
+	s.audit.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditPinVerify && entry.Outcome == models.AuditFailure && entry.ActorID == userID
+	}), nil, fiber.Map{"reason": "invalid PIN"})
 
-	// Verify expected method calls
+	// Verify expected method calls, the wrong PIN stays counted
 	s.mockService.AssertExpectations(s.T())
+	s.pinLockout.AssertExpectations(s.T())
+	s.pinLockout.AssertNotCalled(s.T(), "RecordSuccess", mock.Anything)
+	s.pinLockout.AssertNotCalled(s.T(), "Release", mock.Anything)
+}
+
+// TestVerifyPin_Locked checks that attempts are refused without checking the PIN while locked or throttled
+func (s *AuthControllerTestSuite) TestVerifyPin_Locked() {
+	retryAt := time.Now().Add(10 * time.Minute)
+	testCases := []struct {
+		name           string
+		state          *models.PinLockState
+		checkErr       error
+		expectedStatus int
+		retryAfter     bool
+	}{
+		{"Permanently locked", &models.PinLockState{Locked: true, Permanent: true}, services.ErrPinLocked, fiber.StatusLocked, false},
+		{"Temporarily locked", &models.PinLockState{Locked: true, RetryAt: &retryAt}, services.ErrPinTemporarilyLocked, fiber.StatusLocked, true},
+		{"Backing off", &models.PinLockState{FailedAttempts: 3, RetryAt: &retryAt}, services.ErrPinBackoff, fiber.StatusTooManyRequests, true},
+		{"Address throttled", &models.PinLockState{Locked: true, RetryAt: &retryAt}, services.ErrPinThrottled, fiber.StatusTooManyRequests, true},
+		{"Lock state unavailable", nil, errors.New("database error"), fiber.StatusInternalServerError, false},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			var attempt *models.PinAttempt
+			if tc.state != nil {
+				attempt = &models.PinAttempt{UserID: s.userID, State: tc.state}
+			}
+			s.pinLockout.On("Reserve", s.userID, mock.Anything).Return(attempt, tc.checkErr).Once()
+
+			reqBody, _ := json.Marshal(map[string]string{"user_id": s.userID, "pin": "123456"})
+			req := httptest.NewRequest(http.MethodPost, "/verify-pin", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := s.app.Test(req)
+			s.NoError(err)
+
+			s.Equal(tc.expectedStatus, resp.StatusCode)
+			if tc.retryAfter {
+				s.Equal("600", resp.Header.Get(fiber.HeaderRetryAfter))
+			}
+			s.mockService.AssertNotCalled(s.T(), "GetUserByID", mock.Anything)
+			s.pinLockout.AssertNotCalled(s.T(), "Release", mock.Anything)
+		})
+	}
 }
 
 // TestRenewTokens_Success tests successful token renewal
@@ -187,10 +304,12 @@ func (s *AuthControllerTestSuite) TestRenewTokens_Success() {
 		UserID: s.userID,
 		Name:   "Test User",
 	}, nil)
+	s.authService.On("RenewTokens", s.userID, "device-1", s.tokens.Refresh).Return(&utils.Tokens{Access: "new-access", Refresh: "new-refresh"}, nil).Once()
 
 	// Create request body
 	reqBody, _ := json.Marshal(models.Renew{
 		RefreshToken: s.tokens.Refresh,
+		DeviceID:     "device-1",
 	})
 
 	// Create request
@@ -213,11 +332,46 @@ func (s *AuthControllerTestSuite) TestRenewTokens_Success() {
 	s.Contains(respBody, "tokens")
 	tokens, ok := respBody["tokens"].(map[string]interface{})
 	s.True(ok)
-	s.Contains(tokens, "access")
-	s.Contains(tokens, "refresh")
+	s.Equal("new-access", tokens["access"])
+	s.Equal("new-refresh", tokens["refresh"])
 
 	// Verify expected method calls
 	s.mockService.AssertExpectations(s.T())
+	s.authService.AssertExpectations(s.T())
+}
+
+// TestRenewTokens_Rejected tests that unknown, expired and reused refresh tokens are unauthorized
+func (s *AuthControllerTestSuite) TestRenewTokens_Rejected() {
+	testCases := []struct {
+		name       string
+		serviceErr error
+		message    string
+	}{
+		{"Unknown token", services.ErrInvalidRefreshToken, "unauthorized, invalid refresh token"},
+		{"Expired token", services.ErrRefreshTokenExpired, "unauthorized, your refresh token is expired"},
+		{"Reused token", services.ErrRefreshTokenReused, "unauthorized, " + services.ErrRefreshTokenReused.Error()},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.mockService.On("GetUserByID", s.userID).Return(&models.User{UserID: s.userID}, nil)
+			s.authService.On("RenewTokens", s.userID, "", s.tokens.Refresh).Return(nil, tc.serviceErr).Once()
+
+			reqBody, _ := json.Marshal(models.Renew{RefreshToken: s.tokens.Refresh})
+			req := httptest.NewRequest(http.MethodPost, "/token/renew", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+s.tokens.Access)
+
+			resp, err := s.app.Test(req)
+			s.NoError(err)
+
+			s.testResponse(resp, fiber.StatusUnauthorized, map[string]interface{}{
+				"code":    "401",
+				"message": tc.message,
+			})
+		})
+	}
 }
 
 // TestRenewTokens_InvalidRefreshToken tests renewal with invalid refresh token
@@ -285,6 +439,184 @@ func (s *AuthControllerTestSuite) TestRenewTokens_UserNotFound() {
 	s.mockService.AssertExpectations(s.T())
 }
 
+// TestLogout tests that the sign-in of the refresh token is revoked
+func (s *AuthControllerTestSuite) TestLogout() {
+	s.authService.On("Logout", s.userID, s.tokens.Refresh).Return(nil).Once()
+
+	reqBody, _ := json.Marshal(map[string]string{"refresh_token": s.tokens.Refresh})
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+
+	s.Equal(fiber.StatusNoContent, resp.StatusCode)
+	s.authService.AssertExpectations(s.T())
+}
+
+// TestLogout_UnknownToken tests that a refresh token of another user or an unknown one is unauthorized
+func (s *AuthControllerTestSuite) TestLogout_UnknownToken() {
+	s.authService.On("Logout", s.userID, "foreign-token").Return(services.ErrInvalidRefreshToken).Once()
+
+	reqBody, _ := json.Marshal(map[string]string{"refresh_token": "foreign-token"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+
+	s.Equal(fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// TestLogout_MissingToken tests that the refresh token is required
+func (s *AuthControllerTestSuite) TestLogout_MissingToken() {
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+
+	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+	s.authService.AssertNotCalled(s.T(), "Logout", mock.Anything, mock.Anything)
+}
+
+// TestLogoutAll tests that every sign-in of the user is revoked
+func (s *AuthControllerTestSuite) TestLogoutAll() {
+	s.authService.On("LogoutAll", s.userID).Return(int64(3), nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodPost, "/auth/logout-all", http.NoBody))
+	s.NoError(err)
+
+	s.Equal(fiber.StatusNoContent, resp.StatusCode)
+	s.authService.AssertExpectations(s.T())
+}
+
+// TestListSessions tests that the sessions of the user are listed with the current one marked
+func (s *AuthControllerTestSuite) TestListSessions() {
+	s.authService.On("ListSessions", s.userID, "phone-session").Return([]*models.Session{
+		{SessionID: "phone-session", DeviceName: "Jane's iPhone", Platform: "ios", Current: true},
+		{SessionID: "laptop-session", DeviceName: "Firefox", Platform: "web"},
+	}, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/user/sessions", http.NoBody))
+	s.NoError(err)
+
+	s.Equal(fiber.StatusOK, resp.StatusCode)
+	var sessions []map[string]interface{}
+	s.NoError(json.NewDecoder(resp.Body).Decode(&sessions))
+	s.Len(sessions, 2)
+	s.Equal("phone-session", sessions[0]["session_id"])
+	s.Equal(true, sessions[0]["current"])
+	s.NotContains(sessions[0], "user_id")
+}
+
+// TestRevokeSession tests signing out a device, sessions of other users are not found
+func (s *AuthControllerTestSuite) TestRevokeSession() {
+	s.authService.On("RevokeSession", s.userID, "laptop-session").Return(nil).Once()
+	s.authService.On("RevokeSession", s.userID, "victim-session").Return(services.ErrSessionNotFound).Once()
+	s.authService.On("RevokeSession", s.userID, "broken-session").Return(errors.New("database error")).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodDelete, "/user/sessions/laptop-session", http.NoBody))
+	s.NoError(err)
+	s.Equal(fiber.StatusNoContent, resp.StatusCode)
+
+	resp, err = s.app.Test(httptest.NewRequest(http.MethodDelete, "/user/sessions/victim-session", http.NoBody))
+	s.NoError(err)
+	s.Equal(fiber.StatusNotFound, resp.StatusCode)
+
+	resp, err = s.app.Test(httptest.NewRequest(http.MethodDelete, "/user/sessions/broken-session", http.NoBody))
+	s.NoError(err)
+	s.Equal(fiber.StatusInternalServerError, resp.StatusCode)
+
+	s.authService.AssertExpectations(s.T())
+}
+
+// postJSON sends a JSON body to the path
+func (s *AuthControllerTestSuite) postJSON(path string, body map[string]string) *http.Response {
+	reqBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+	return resp
+}
+
+// TestRequestPinReset tests that known and unknown users get the same answer
+func (s *AuthControllerTestSuite) TestRequestPinReset() {
+	s.pinService.On("RequestReset", s.userID).Return(time.Now().Add(10*time.Minute), nil).Once()
+	s.pinService.On("RequestReset", "unknown-user").Return(time.Time{}, sql.ErrNoRows).Once()
+
+	known := s.postJSON("/auth/pin-reset", map[string]string{"user_id": s.userID})
+	unknown := s.postJSON("/auth/pin-reset", map[string]string{"user_id": "unknown-user"})
+
+	s.Equal(fiber.StatusAccepted, known.StatusCode)
+	s.Equal(fiber.StatusAccepted, unknown.StatusCode)
+	s.pinService.AssertExpectations(s.T())
+}
+
+// TestRequestPinReset_TooSoon tests that codes cannot be requested back to back
+func (s *AuthControllerTestSuite) TestRequestPinReset_TooSoon() {
+	s.pinService.On("RequestReset", s.userID).Return(time.Time{}, services.ErrResetTooSoon).Once()
+
+	resp := s.postJSON("/auth/pin-reset", map[string]string{"user_id": s.userID})
+
+	s.testResponse(resp, fiber.StatusTooManyRequests, map[string]interface{}{"code": "429", "message": services.ErrResetTooSoon.Error()})
+}
+
+// TestConfirmPinReset tests that a locked PIN can be reset with the code
+func (s *AuthControllerTestSuite) TestConfirmPinReset() {
+	attempt := &models.PinAttempt{IPAddress: "0.0.0.0"}
+	s.pinLockout.On("ReserveIP", mock.Anything).Return(attempt, nil).Once()
+	s.pinService.On("ConfirmReset", s.userID, "482913", "258147").Return(nil).Once()
+	s.pinLockout.On("RecordSuccess", attempt).Return(nil).Once()
+
+	resp := s.postJSON("/auth/pin-reset/confirm", map[string]string{"user_id": s.userID, "code": "482913", "new_pin": "258147"})
+
+	s.Equal(fiber.StatusNoContent, resp.StatusCode)
+	s.pinService.AssertExpectations(s.T())
+	s.pinLockout.AssertExpectations(s.T())
+	s.pinLockout.AssertNotCalled(s.T(), "Reserve", mock.Anything, mock.Anything)
+}
+
+// TestConfirmPinReset_InvalidCode tests that a wrong code counts against the address
+func (s *AuthControllerTestSuite) TestConfirmPinReset_InvalidCode() {
+	s.pinLockout.On("ReserveIP", mock.Anything).Return(&models.PinAttempt{IPAddress: "0.0.0.0"}, nil).Once()
+	s.pinService.On("ConfirmReset", s.userID, "000000", "258147").Return(services.ErrInvalidResetCode).Once()
+
+	resp := s.postJSON("/auth/pin-reset/confirm", map[string]string{"user_id": s.userID, "code": "000000", "new_pin": "258147"})
+
+	s.testResponse(resp, fiber.StatusBadRequest, map[string]interface{}{"code": "400", "message": services.ErrInvalidResetCode.Error()})
+	s.pinLockout.AssertExpectations(s.T())
+	s.pinLockout.AssertNotCalled(s.T(), "RecordSuccess", mock.Anything)
+	s.pinLockout.AssertNotCalled(s.T(), "Release", mock.Anything)
+}
+
+// TestConfirmPinReset_PolicyViolation tests that an attempt refused for the new PIN is taken back
+func (s *AuthControllerTestSuite) TestConfirmPinReset_PolicyViolation() {
+	attempt := &models.PinAttempt{IPAddress: "0.0.0.0"}
+	s.pinLockout.On("ReserveIP", mock.Anything).Return(attempt, nil).Once()
+	s.pinService.On("ConfirmReset", s.userID, "482913", "111111").Return(services.ErrPinPolicy).Once()
+	s.pinLockout.On("Release", attempt).Return(nil).Once()
+
+	resp := s.postJSON("/auth/pin-reset/confirm", map[string]string{"user_id": s.userID, "code": "482913", "new_pin": "111111"})
+
+	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+	s.pinLockout.AssertExpectations(s.T())
+}
+
+// TestConfirmPinReset_Throttled tests that an address guessing codes is refused before the code is checked
+func (s *AuthControllerTestSuite) TestConfirmPinReset_Throttled() {
+	retryAt := time.Now().Add(5 * time.Minute)
+	s.pinLockout.On("ReserveIP", mock.Anything).
+		Return(&models.PinAttempt{IPAddress: "0.0.0.0", State: &models.PinLockState{Locked: true, RetryAt: &retryAt}}, services.ErrPinThrottled).Once()
+
+	resp := s.postJSON("/auth/pin-reset/confirm", map[string]string{"user_id": s.userID, "code": "000000", "new_pin": "258147"})
+
+	s.Equal(fiber.StatusTooManyRequests, resp.StatusCode)
+	s.pinService.AssertNotCalled(s.T(), "ConfirmReset", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Run the test suite
 func TestAuthControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthControllerTestSuite))
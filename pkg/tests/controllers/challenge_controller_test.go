@@ -0,0 +1,224 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ChallengeControllerTestSuite defines the test suite
+type ChallengeControllerTestSuite struct {
+	suite.Suite
+	app                      *fiber.App
+	challengeService         *mocks.ChallengeService
+	accountService           *mocks.AccountService
+	scheduledTransferService *mocks.ScheduledTransferService
+	pinLockoutService        *mocks.PinLockoutService
+	auditService             *mocks.AuditService
+	testUserID               string
+}
+
+// SetupTest runs before each test
+func (s *ChallengeControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.challengeService = new(mocks.ChallengeService)
+	s.accountService = new(mocks.AccountService)
+	s.scheduledTransferService = new(mocks.ScheduledTransferService)
+	s.pinLockoutService = new(mocks.PinLockoutService)
+	s.testUserID = "test-user-id"
+
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	controller := controllers.NewChallengeController(s.challengeService, s.accountService, s.scheduledTransferService, s.pinLockoutService, s.auditService)
+	s.app.Post("/challenges/:id/confirm", func(c *fiber.Ctx) error {
+		c.Locals("userID", s.testUserID)
+		return controller.ConfirmChallenge(c)
+	})
+}
+
+// transferChallenge returns a challenge holding a transfer of 5000.00 USD
+func (s *ChallengeControllerTestSuite) transferChallenge() *models.Challenge {
+	now := time.Now()
+	payload, _ := json.Marshal(&models.PendingTransfer{FromAccountID: "source-account-id", ToAccountID: "dest-account-id", Amount: usd(500000)})
+	return &models.Challenge{
+		ChallengeID: "challenge-123",
+		UserID:      s.testUserID,
+		Action:      models.ChallengeActionTransfer,
+		Payload:     payload,
+		ExpiresAt:   now.Add(5 * time.Minute),
+		ConfirmedAt: &now,
+	}
+}
+
+func (s *ChallengeControllerTestSuite) confirm(method, code string) *http.Response {
+	requestBody, _ := json.Marshal(map[string]string{"method": method, "code": code})
+	req := httptest.NewRequest(http.MethodPost, "/challenges/challenge-123/confirm", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestConfirmWithPin tests that the held transfer runs once the PIN confirms the challenge
+func (s *ChallengeControllerTestSuite) TestConfirmWithPin() {
+	attempt := &models.PinAttempt{UserID: s.testUserID}
+	s.pinLockoutService.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Once()
+	s.challengeService.On("Confirm", s.testUserID, "challenge-123", "pin", "123456").Return(s.transferChallenge(), nil).Once()
+	s.pinLockoutService.On("RecordSuccess", attempt).Return(nil).Once()
+	s.accountService.On("TransferBetweenAccounts", "source-account-id", "dest-account-id", usd(500000)).
+		Return(&types.TransferResult{SourceBalance: usd(100000), DestinationBalance: usd(600000)}, nil).Once()
+
+	resp := s.confirm("pin", "123456")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var response map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(s.T(), "Transfer successful", response["message"])
+	assert.Equal(s.T(), moneyJSON("5000.00", "USD"), response["amount"])
+	s.accountService.AssertExpectations(s.T())
+	s.pinLockoutService.AssertExpectations(s.T())
+}
+
+// TestConfirmWithTOTP tests that a right authenticator code also forgets the failed attempts of the user
+func (s *ChallengeControllerTestSuite) TestConfirmWithTOTP() {
+	attempt := &models.PinAttempt{UserID: s.testUserID}
+	s.pinLockoutService.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Once()
+	s.pinLockoutService.On("RecordSuccess", attempt).Return(nil).Once()
+	s.challengeService.On("Confirm", s.testUserID, "challenge-123", "totp", "654321").Return(s.transferChallenge(), nil).Once()
+	s.accountService.On("TransferBetweenAccounts", "source-account-id", "dest-account-id", usd(500000)).
+		Return(&types.TransferResult{SourceBalance: usd(100000), DestinationBalance: usd(600000)}, nil).Once()
+
+	resp := s.confirm("totp", "654321")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.pinLockoutService.AssertExpectations(s.T())
+}
+
+// TestConfirmSchedule tests that the held schedule is created, or the held change applied, once confirmed
+func (s *ChallengeControllerTestSuite) TestConfirmSchedule() {
+	now := time.Now()
+	schedule, _ := json.Marshal(&models.ScheduledTransfer{UserID: s.testUserID, FromAccountID: "source-account-id", ToAccountID: "dest-account-id", Amount: usd(500000), Frequency: models.FrequencyDaily})
+	amount := usd(600000)
+	update, _ := json.Marshal(&models.PendingScheduleUpdate{AccountID: "source-account-id", ScheduleID: "schedule-123", Update: models.ScheduledTransferUpdate{Amount: &amount}})
+	attempt := &models.PinAttempt{UserID: s.testUserID}
+	s.pinLockoutService.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Twice()
+	s.pinLockoutService.On("RecordSuccess", attempt).Return(nil).Twice()
+
+	s.challengeService.On("Confirm", s.testUserID, "challenge-123", "totp", "654321").
+		Return(&models.Challenge{ChallengeID: "challenge-123", UserID: s.testUserID, Action: models.ChallengeActionSchedule, Payload: schedule, ConfirmedAt: &now}, nil).Once()
+	s.scheduledTransferService.On("CreateSchedule", mock.MatchedBy(func(schedule *models.ScheduledTransfer) bool {
+		return schedule.FromAccountID == "source-account-id" && schedule.Amount == usd(500000) && schedule.Frequency == models.FrequencyDaily
+	})).Return(nil).Once()
+
+	resp := s.confirm("totp", "654321")
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+
+	s.challengeService.On("Confirm", s.testUserID, "challenge-123", "totp", "654321").
+		Return(&models.Challenge{ChallengeID: "challenge-123", UserID: s.testUserID, Action: models.ChallengeActionScheduleUpdate, Payload: update, ConfirmedAt: &now}, nil).Once()
+	s.scheduledTransferService.On("UpdateSchedule", "source-account-id", "schedule-123", mock.MatchedBy(func(update *models.ScheduledTransferUpdate) bool {
+		return update.Amount != nil && *update.Amount == amount && update.Status == nil
+	})).Return(&models.ScheduledTransfer{ScheduleID: "schedule-123"}, nil).Once()
+
+	resp = s.confirm("totp", "654321")
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.scheduledTransferService.AssertExpectations(s.T())
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestConfirmWrongPin tests that a wrong PIN stays counted towards the PIN lockout and runs nothing
+func (s *ChallengeControllerTestSuite) TestConfirmWrongPin() {
+	s.pinLockoutService.On("Reserve", s.testUserID, mock.Anything).Return(&models.PinAttempt{UserID: s.testUserID}, nil).Once()
+	s.challengeService.On("Confirm", s.testUserID, "challenge-123", "pin", "000000").Return(nil, services.ErrIncorrectPin).Once()
+
+	resp := s.confirm("pin", "000000")
+
+	assert.Equal(s.T(), http.StatusUnauthorized, resp.StatusCode)
+	s.pinLockoutService.AssertExpectations(s.T())
+	s.pinLockoutService.AssertNotCalled(s.T(), "RecordSuccess", mock.Anything)
+	s.pinLockoutService.AssertNotCalled(s.T(), "Release", mock.Anything)
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestConfirmLockedPin tests that a locked PIN cannot confirm a challenge, with either factor
+func (s *ChallengeControllerTestSuite) TestConfirmLockedPin() {
+	for _, method := range []string{"pin", "totp"} {
+		s.Run(method, func() {
+			s.SetupTest()
+			s.pinLockoutService.On("Reserve", s.testUserID, mock.Anything).Return(&models.PinAttempt{
+				UserID: s.testUserID,
+				State:  &models.PinLockState{FailedAttempts: 10, Locked: true, Permanent: true},
+			}, services.ErrPinLocked).Once()
+
+			resp := s.confirm(method, "123456")
+
+			assert.Equal(s.T(), http.StatusLocked, resp.StatusCode)
+			s.challengeService.AssertNotCalled(s.T(), "Confirm", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestConfirmErrors tests the answers to challenges that cannot be confirmed
+func (s *ChallengeControllerTestSuite) TestConfirmErrors() {
+	testCases := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		countsFailure  bool
+	}{
+		{name: "Wrong authenticator code", serviceErr: services.ErrInvalidTOTP, expectedStatus: http.StatusUnauthorized, countsFailure: true},
+		{name: "No authenticator enabled", serviceErr: services.ErrTOTPNotEnabled, expectedStatus: http.StatusBadRequest},
+		{name: "Not found", serviceErr: services.ErrChallengeNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Expired", serviceErr: services.ErrChallengeExpired, expectedStatus: http.StatusConflict},
+		{name: "Already confirmed", serviceErr: services.ErrChallengeUsed, expectedStatus: http.StatusConflict},
+		{name: "Too many attempts", serviceErr: services.ErrChallengeFailed, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			attempt := &models.PinAttempt{UserID: s.testUserID}
+			s.pinLockoutService.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Once()
+			s.challengeService.On("Confirm", s.testUserID, "challenge-123", "totp", "654321").Return(nil, tc.serviceErr).Once()
+			if !tc.countsFailure {
+				s.pinLockoutService.On("Release", attempt).Return(nil).Once()
+			}
+
+			resp := s.confirm("totp", "654321")
+
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+			s.pinLockoutService.AssertExpectations(s.T())
+			if tc.countsFailure {
+				s.pinLockoutService.AssertNotCalled(s.T(), "Release", mock.Anything)
+			}
+			s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccounts", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestConfirmInvalidMethod tests that only the PIN and authenticator codes are accepted
+func (s *ChallengeControllerTestSuite) TestConfirmInvalidMethod() {
+	resp := s.confirm("sms", "123456")
+
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	s.challengeService.AssertNotCalled(s.T(), "Confirm", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestChallengeControllerSuite runs the test suite
+func TestChallengeControllerSuite(t *testing.T) {
+	suite.Run(t, new(ChallengeControllerTestSuite))
+}
@@ -11,19 +11,43 @@ import (
 
 func TestInitController(t *testing.T) {
 	// Create mock services
+	mockAuthService := new(mockServices.AuthService)
 	mockUserService := new(mockServices.UserService)
+	mockPinLockoutService := new(mockServices.PinLockoutService)
+	mockPinService := new(mockServices.PinService)
 	mockTransactionService := new(mockServices.TransactionService)
 	mockDebitCardService := new(mockServices.DebitCardService)
 	mockAccountService := new(mockServices.AccountService)
 	mockBannerService := new(mockServices.BannerService)
+	mockScheduledTransferService := new(mockServices.ScheduledTransferService)
+	mockTransferLimitService := new(mockServices.TransferLimitService)
+	mockFXService := new(mockServices.FXService)
+	mockTOTPService := new(mockServices.TOTPService)
+	mockChallengeService := new(mockServices.ChallengeService)
+	mockAuditService := new(mockServices.AuditService)
+	mockWebhookService := new(mockServices.WebhookService)
+	mockStreamService := new(mockServices.StreamService)
+	mockStatementService := new(mockServices.StatementService)
 
 	// Create service struct with mocks
 	service := &services.Service{
-		UserService:        mockUserService,
-		TransactionService: mockTransactionService,
-		DebitCardService:   mockDebitCardService,
-		AccountService:     mockAccountService,
-		BannerService:      mockBannerService,
+		AuthService:              mockAuthService,
+		UserService:              mockUserService,
+		PinLockoutService:        mockPinLockoutService,
+		PinService:               mockPinService,
+		TransactionService:       mockTransactionService,
+		DebitCardService:         mockDebitCardService,
+		AccountService:           mockAccountService,
+		BannerService:            mockBannerService,
+		ScheduledTransferService: mockScheduledTransferService,
+		TransferLimitService:     mockTransferLimitService,
+		FXService:                mockFXService,
+		TOTPService:              mockTOTPService,
+		ChallengeService:         mockChallengeService,
+		AuditService:             mockAuditService,
+		WebhookService:           mockWebhookService,
+		StreamService:            mockStreamService,
+		StatementService:         mockStatementService,
 	}
 
 	// Initialize controller
@@ -37,6 +61,16 @@ func TestInitController(t *testing.T) {
 	assert.NotNil(t, controller.DebitCardController)
 	assert.NotNil(t, controller.AccountController)
 	assert.NotNil(t, controller.BannerController)
+	assert.NotNil(t, controller.ScheduledTransferController)
+	assert.NotNil(t, controller.TransferLimitController)
+	assert.NotNil(t, controller.FXController)
+	assert.NotNil(t, controller.HoldController)
+	assert.NotNil(t, controller.ChallengeController)
+	assert.NotNil(t, controller.WellKnownController)
+	assert.NotNil(t, controller.AdminController)
+	assert.NotNil(t, controller.WebhookController)
+	assert.NotNil(t, controller.StreamController)
+	assert.NotNil(t, controller.StatementController)
 
 	// Verify that the controllers are initialized with the correct services
 	// This is a bit tricky since we can't directly access the private fields
@@ -49,4 +83,8 @@ func TestInitController(t *testing.T) {
 	assert.IsType(t, controllers.DebitCardController{}, controller.DebitCardController)
 	assert.IsType(t, controllers.AccountController{}, controller.AccountController)
 	assert.IsType(t, controllers.BannerController{}, controller.BannerController)
+	assert.IsType(t, controllers.ScheduledTransferController{}, controller.ScheduledTransferController)
+	assert.IsType(t, controllers.TransferLimitController{}, controller.TransferLimitController)
+	assert.IsType(t, controllers.FXController{}, controller.FXController)
+	assert.IsType(t, controllers.HoldController{}, controller.HoldController)
 }
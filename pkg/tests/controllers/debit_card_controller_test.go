@@ -23,6 +23,7 @@ type DebitCardControllerTestSuite struct {
 	suite.Suite
 	app              *fiber.App
 	debitCardService *mocks.DebitCardService
+	auditService     *mocks.AuditService
 	controller       *controllers.DebitCardController
 	testUserID       string
 	testCardID       string
@@ -33,7 +34,9 @@ type DebitCardControllerTestSuite struct {
 func (s *DebitCardControllerTestSuite) SetupTest() {
 	s.app = fiber.New()
 	s.debitCardService = new(mocks.DebitCardService)
-	s.controller = controllers.NewDebitCardController(s.debitCardService)
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.controller = controllers.NewDebitCardController(s.debitCardService, s.auditService)
 	s.testUserID = "test-user-id"
 	s.testCardID = "test-card-id"
 
@@ -0,0 +1,138 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// FXControllerTestSuite defines the test suite
+type FXControllerTestSuite struct {
+	suite.Suite
+	app        *fiber.App
+	fxService  *mocks.FXService
+	controller *controllers.FXController
+	testUserID string
+}
+
+// SetupTest runs before each test
+func (s *FXControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.fxService = new(mocks.FXService)
+	s.controller = controllers.NewFXController(s.fxService)
+	s.testUserID = "test-user-id"
+
+	s.app.Post("/fx/quotes", func(c *fiber.Ctx) error {
+		c.Locals("userID", s.testUserID)
+		return s.controller.CreateQuote(c)
+	})
+}
+
+func (s *FXControllerTestSuite) createQuote(body map[string]interface{}) *http.Response {
+	requestBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/fx/quotes", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(req)
+	assert.NoError(s.T(), err)
+	return resp
+}
+
+// TestCreateQuote tests the CreateQuote controller method
+func (s *FXControllerTestSuite) TestCreateQuote() {
+	quote := &models.FXQuote{
+		QuoteID:      "quote-123",
+		UserID:       s.testUserID,
+		FromCurrency: "THB",
+		ToCurrency:   "USD",
+		Rate:         types.MustParseRate("0.0274"),
+		SourceAmount: types.NewMoney(100000, "THB"),
+		TargetAmount: types.NewMoney(2740, "USD"),
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	s.fxService.On("CreateQuote", s.testUserID, "USD", types.NewMoney(100000, "THB")).Return(quote, nil).Once()
+
+	resp := s.createQuote(map[string]interface{}{"from_currency": "thb", "to_currency": "usd", "amount": "1000.00"})
+
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+
+	var response map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(s.T(), "quote-123", response["quote_id"])
+	assert.Equal(s.T(), "0.02740000", response["rate"])
+	assert.Equal(s.T(), moneyJSON("27.40", "USD"), response["target_amount"])
+	s.fxService.AssertExpectations(s.T())
+}
+
+// TestCreateQuote_Errors tests the CreateQuote controller method with invalid requests and service errors
+func (s *FXControllerTestSuite) TestCreateQuote_Errors() {
+	testCases := []struct {
+		name           string
+		body           map[string]interface{}
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "Missing target currency",
+			body:           map[string]interface{}{"from_currency": "THB", "amount": "1000.00"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid amount",
+			body:           map[string]interface{}{"from_currency": "THB", "to_currency": "USD", "amount": "-1"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Same currency",
+			body:           map[string]interface{}{"from_currency": "THB", "to_currency": "THB", "amount": "1000.00"},
+			serviceErr:     services.ErrInvalidCurrencyPair,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Rate unavailable",
+			body:           map[string]interface{}{"from_currency": "THB", "to_currency": "USD", "amount": "1000.00"},
+			serviceErr:     services.ErrRateUnavailable,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service error",
+			body:           map[string]interface{}{"from_currency": "THB", "to_currency": "USD", "amount": "1000.00"},
+			serviceErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.serviceErr != nil {
+				s.fxService.On("CreateQuote", s.testUserID, mock.Anything, mock.Anything).Return(nil, tc.serviceErr).Once()
+			}
+
+			resp := s.createQuote(tc.body)
+
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+			if tc.serviceErr == nil {
+				s.fxService.AssertNotCalled(s.T(), "CreateQuote", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestFXControllerSuite runs the test suite
+func TestFXControllerSuite(t *testing.T) {
+	suite.Run(t, new(FXControllerTestSuite))
+}
@@ -0,0 +1,270 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HoldControllerTestSuite defines the test suite
+type HoldControllerTestSuite struct {
+	suite.Suite
+	app            *fiber.App
+	accountService *mocks.AccountService
+	auditService   *mocks.AuditService
+	controller     *controllers.HoldController
+	testUserID     string
+	testAccount    *models.Account
+	testHold       *models.AccountHold
+}
+
+// SetupTest runs before each test
+func (s *HoldControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.accountService = new(mocks.AccountService)
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.controller = controllers.NewHoldController(s.accountService, s.auditService)
+	s.testUserID = "test-user-id"
+	s.testAccount = &models.Account{
+		AccountID: "test-account-id",
+		UserID:    s.testUserID,
+		Currency:  "THB",
+	}
+	s.testHold = &models.AccountHold{
+		HoldID:         "test-hold-id",
+		AccountID:      s.testAccount.AccountID,
+		UserID:         s.testUserID,
+		Amount:         types.NewMoney(25000, "THB"),
+		CapturedAmount: types.NewMoney(0, "THB"),
+		Status:         models.HoldActive,
+		ExpiresAt:      time.Now().Add(time.Hour).UTC(),
+	}
+
+	withUser := func(handler fiber.Handler) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("userID", s.testUserID)
+			return handler(c)
+		}
+	}
+	s.app.Get("/accounts/:id/holds", withUser(s.controller.ListHolds))
+	s.app.Post("/accounts/:id/holds", withUser(s.controller.PlaceHold))
+	s.app.Post("/accounts/:id/holds/:holdId/capture", withUser(s.controller.CaptureHold))
+	s.app.Post("/accounts/:id/holds/:holdId/release", withUser(s.controller.ReleaseHold))
+}
+
+// TestListHolds tests the ListHolds controller method
+func (s *HoldControllerTestSuite) TestListHolds() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.accountService.On("GetHolds", s.testAccount.AccountID).Return([]*models.AccountHold{s.testHold}, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/holds", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	var result []map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&result))
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), moneyJSON("250.00", "THB"), result[0]["amount"])
+}
+
+// TestPlaceHold tests the PlaceHold controller method
+func (s *HoldControllerTestSuite) TestPlaceHold() {
+	testCases := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			body:           `{"amount": "250.00", "description": "Coffee shop"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Insufficient available funds",
+			body:           `{"amount": "250.00"}`,
+			serviceErr:     services.ErrInsufficientFunds,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing amount",
+			body:           `{"description": "Coffee shop"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+			hold := s.testHold
+			if tc.serviceErr != nil {
+				hold = nil
+			}
+			s.accountService.On("PlaceHold", s.testAccount.AccountID, types.NewMoney(25000, "THB"), mock.Anything, time.Time{}).
+				Return(hold, tc.serviceErr).Maybe()
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/test-account-id/holds", bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := s.app.Test(req)
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+// TestPlaceHold_AccountOfAnotherUser tests that holds cannot be placed on accounts of other users
+func (s *HoldControllerTestSuite) TestPlaceHold_AccountOfAnotherUser() {
+	s.accountService.On("GetAccountByID", "other-account-id").Return(&models.Account{AccountID: "other-account-id", UserID: "other-user-id"}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/other-account-id/holds", bytes.NewReader([]byte(`{"amount": "250.00"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	s.accountService.AssertNotCalled(s.T(), "PlaceHold", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCaptureHold tests full and partial captures and their errors
+func (s *HoldControllerTestSuite) TestCaptureHold() {
+	partial := types.NewMoney(20000, "THB")
+	testCases := []struct {
+		name           string
+		body           string
+		amount         *types.Money
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "Full capture without a body",
+			body:           "",
+			amount:         nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Partial capture",
+			body:           `{"amount": "200.00"}`,
+			amount:         &partial,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Capture above the hold",
+			body:           `{"amount": "200.00"}`,
+			amount:         &partial,
+			serviceErr:     services.ErrCaptureExceedsHold,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Over the transfer limits",
+			body:           "",
+			serviceErr:     services.ErrTransferLimitExceeded,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Frozen account",
+			body:           "",
+			serviceErr:     services.ErrAccountFrozen,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Hold already released",
+			body:           "",
+			serviceErr:     services.ErrHoldNotActive,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Unknown hold",
+			body:           "",
+			serviceErr:     services.ErrHoldNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+			hold := s.testHold
+			if tc.serviceErr != nil {
+				hold = nil
+			}
+			s.accountService.On("CaptureHold", s.testAccount.AccountID, "test-hold-id", tc.amount).Return(hold, tc.serviceErr).Once()
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/test-account-id/holds/test-hold-id/capture", bytes.NewReader([]byte(tc.body)))
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := s.app.Test(req)
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+			s.accountService.AssertExpectations(s.T())
+
+			// Every capture is audited, a refused one as a failure
+			outcome := ""
+			if tc.serviceErr != nil {
+				outcome = models.AuditFailure
+			}
+			s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+				return entry.Action == models.AuditHoldCapture && entry.Outcome == outcome &&
+					entry.ActorID == s.testUserID && entry.ResourceType == "hold" && entry.ResourceID == "test-hold-id"
+			}), nil, mock.Anything)
+		})
+	}
+}
+
+// TestReleaseHold tests the ReleaseHold controller method
+func (s *HoldControllerTestSuite) TestReleaseHold() {
+	released := *s.testHold
+	released.Status = models.HoldReleased
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.accountService.On("ReleaseHold", s.testAccount.AccountID, "test-hold-id").Return(&released, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodPost, "/accounts/test-account-id/holds/test-hold-id/release", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(s.T(), "released", result["status"])
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditHoldRelease && entry.Outcome == "" &&
+			entry.ActorID == s.testUserID && entry.ResourceType == "hold" && entry.ResourceID == "test-hold-id"
+	}), nil, &released)
+
+	// A refused release is audited as a failure
+	s.SetupTest()
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.accountService.On("ReleaseHold", s.testAccount.AccountID, "test-hold-id").Return(nil, services.ErrHoldNotActive).Once()
+
+	resp, err = s.app.Test(httptest.NewRequest(http.MethodPost, "/accounts/test-account-id/holds/test-hold-id/release", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusConflict, resp.StatusCode)
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditHoldRelease && entry.Outcome == models.AuditFailure
+	}), nil, mock.Anything)
+}
+
+// TestHoldControllerSuite runs the test suite
+func TestHoldControllerSuite(t *testing.T) {
+	suite.Run(t, new(HoldControllerTestSuite))
+}
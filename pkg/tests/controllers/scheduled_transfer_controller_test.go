@@ -0,0 +1,325 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ScheduledTransferControllerTestSuite defines the test suite
+type ScheduledTransferControllerTestSuite struct {
+	suite.Suite
+	app                      *fiber.App
+	accountService           *mocks.AccountService
+	scheduledTransferService *mocks.ScheduledTransferService
+	challengeService         *mocks.ChallengeService
+	controller               *controllers.ScheduledTransferController
+	testUserID               string
+	testAccount              *models.Account
+	testSchedule             *models.ScheduledTransfer
+}
+
+// SetupTest runs before each test
+func (s *ScheduledTransferControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.accountService = new(mocks.AccountService)
+	s.scheduledTransferService = new(mocks.ScheduledTransferService)
+	s.challengeService = new(mocks.ChallengeService)
+	s.challengeService.On("RequiresStepUp", mock.Anything).Return(false).Maybe()
+	s.controller = controllers.NewScheduledTransferController(s.accountService, s.scheduledTransferService, s.challengeService)
+	s.testUserID = "test-user-id"
+	s.testAccount = &models.Account{
+		AccountID: "test-account-id",
+		UserID:    s.testUserID,
+		Currency:  "THB",
+	}
+
+	now := time.Now().UTC()
+	s.testSchedule = &models.ScheduledTransfer{
+		BaseModel:     &models.BaseModel{CreatedAt: now, UpdatedAt: now},
+		ScheduleID:    "test-schedule-id",
+		UserID:        s.testUserID,
+		FromAccountID: s.testAccount.AccountID,
+		ToAccountID:   "goal-account-id",
+		Amount:        types.NewMoney(150000, "THB"),
+		Frequency:     models.FrequencyMonthly,
+		StartAt:       now,
+		NextRunAt:     now,
+		NextAttemptAt: now,
+		Status:        models.ScheduleActive,
+	}
+
+	withUser := func(handler fiber.Handler) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("userID", s.testUserID)
+			return handler(c)
+		}
+	}
+	s.app.Get("/accounts/:id/schedules", withUser(s.controller.ListSchedules))
+	s.app.Post("/accounts/:id/schedules", withUser(s.controller.CreateSchedule))
+	s.app.Get("/accounts/:id/schedules/:scheduleId", withUser(s.controller.GetSchedule))
+	s.app.Patch("/accounts/:id/schedules/:scheduleId", withUser(s.controller.UpdateSchedule))
+	s.app.Delete("/accounts/:id/schedules/:scheduleId", withUser(s.controller.CancelSchedule))
+	s.app.Get("/accounts/:id/schedules/:scheduleId/executions", withUser(s.controller.ListExecutions))
+}
+
+func (s *ScheduledTransferControllerTestSuite) jsonRequest(method, url string, body map[string]interface{}) *http.Request {
+	requestBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, url, bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// TestListSchedules tests the ListSchedules controller method
+func (s *ScheduledTransferControllerTestSuite) TestListSchedules() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.scheduledTransferService.On("GetSchedulesByAccountID", s.testAccount.AccountID).Return([]*models.ScheduledTransfer{s.testSchedule}, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/schedules", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	var schedules []*models.ScheduledTransfer
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&schedules))
+	assert.Len(s.T(), schedules, 1)
+	assert.Equal(s.T(), s.testSchedule.ScheduleID, schedules[0].ScheduleID)
+	s.scheduledTransferService.AssertExpectations(s.T())
+}
+
+// TestListSchedules_AccountOfAnotherUser tests that accounts of other users are not found
+func (s *ScheduledTransferControllerTestSuite) TestListSchedules_AccountOfAnotherUser() {
+	s.accountService.On("GetAccountByID", "other-account-id").Return(&models.Account{AccountID: "other-account-id", UserID: "other-user-id"}, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/other-account-id/schedules", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	s.scheduledTransferService.AssertNotCalled(s.T(), "GetSchedulesByAccountID", mock.Anything)
+}
+
+// TestCreateSchedule tests the CreateSchedule controller method
+func (s *ScheduledTransferControllerTestSuite) TestCreateSchedule() {
+	startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.scheduledTransferService.On("CreateSchedule", mock.MatchedBy(func(schedule *models.ScheduledTransfer) bool {
+		return schedule.UserID == s.testUserID &&
+			schedule.FromAccountID == s.testAccount.AccountID &&
+			schedule.ToAccountID == "goal-account-id" &&
+			schedule.Amount == types.NewMoney(150000, "THB") &&
+			schedule.Frequency == models.FrequencyWeekly &&
+			schedule.StartAt.Equal(startAt) &&
+			schedule.InsufficientFundsPolicy == models.RetryOnInsufficientFunds
+	})).Return(nil).Once()
+
+	req := s.jsonRequest(http.MethodPost, "/accounts/test-account-id/schedules", map[string]interface{}{
+		"to_account_id":             "goal-account-id",
+		"amount":                    "1500.00",
+		"frequency":                 "weekly",
+		"start_at":                  startAt.Format(time.RFC3339),
+		"insufficient_funds_policy": "retry",
+	})
+	resp, err := s.app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	s.scheduledTransferService.AssertExpectations(s.T())
+}
+
+// TestCreateSchedule_Invalid tests request validation and service errors of CreateSchedule
+func (s *ScheduledTransferControllerTestSuite) TestCreateSchedule_Invalid() {
+	testCases := []struct {
+		name           string
+		body           map[string]interface{}
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "Invalid Frequency",
+			body:           map[string]interface{}{"to_account_id": "goal-account-id", "amount": "10.00", "frequency": "yearly"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Amount",
+			body:           map[string]interface{}{"to_account_id": "goal-account-id", "amount": "-10.00", "frequency": "daily"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Schedule",
+			body:           map[string]interface{}{"to_account_id": "goal-account-id", "amount": "10.00", "frequency": "daily"},
+			serviceError:   services.ErrInvalidSchedule,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Destination Not Found",
+			body:           map[string]interface{}{"to_account_id": "missing", "amount": "10.00", "frequency": "daily"},
+			serviceError:   sql.ErrNoRows,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+			if tc.serviceError != nil {
+				s.scheduledTransferService.On("CreateSchedule", mock.Anything).Return(tc.serviceError).Once()
+			}
+
+			resp, err := s.app.Test(s.jsonRequest(http.MethodPost, "/accounts/test-account-id/schedules", tc.body))
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+// TestCreateScheduleStepUp tests that a schedule above the threshold waits for a challenge instead of being created
+func (s *ScheduledTransferControllerTestSuite) TestCreateScheduleStepUp() {
+	s.challengeService.ExpectedCalls = nil
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.challengeService.On("RequiresStepUp", types.NewMoney(9000000, "THB")).Return(true).Once()
+	s.challengeService.On("CreateScheduleChallenge", s.testUserID, mock.MatchedBy(func(schedule *models.ScheduledTransfer) bool {
+		return schedule.FromAccountID == s.testAccount.AccountID && schedule.ToAccountID == "goal-account-id" &&
+			schedule.Amount == types.NewMoney(9000000, "THB")
+	})).Return(&models.Challenge{ChallengeID: "challenge-123", UserID: s.testUserID}, nil).Once()
+
+	resp, err := s.app.Test(s.jsonRequest(http.MethodPost, "/accounts/test-account-id/schedules", map[string]interface{}{
+		"to_account_id": "goal-account-id",
+		"amount":        "90000.00",
+		"frequency":     "daily",
+	}))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusAccepted, resp.StatusCode)
+	var response map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(s.T(), "challenge-123", response["challenge_id"])
+	s.challengeService.AssertExpectations(s.T())
+	s.scheduledTransferService.AssertNotCalled(s.T(), "CreateSchedule", mock.Anything)
+}
+
+// TestGetSchedule tests the GetSchedule controller method
+func (s *ScheduledTransferControllerTestSuite) TestGetSchedule() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Twice()
+	s.scheduledTransferService.On("GetSchedule", s.testAccount.AccountID, "test-schedule-id").Return(s.testSchedule, nil).Once()
+	s.scheduledTransferService.On("GetSchedule", s.testAccount.AccountID, "missing").Return(nil, services.ErrScheduleNotFound).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/schedules/test-schedule-id", http.NoBody))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	resp, err = s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/schedules/missing", http.NoBody))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// TestUpdateSchedule tests the UpdateSchedule controller method
+func (s *ScheduledTransferControllerTestSuite) TestUpdateSchedule() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Twice()
+	s.scheduledTransferService.On("UpdateSchedule", s.testAccount.AccountID, "test-schedule-id", mock.MatchedBy(func(update *models.ScheduledTransferUpdate) bool {
+		return update.Status != nil && *update.Status == models.SchedulePaused &&
+			update.Amount != nil && *update.Amount == types.NewMoney(200000, "THB") &&
+			update.Frequency == nil
+	})).Return(s.testSchedule, nil).Once()
+	s.scheduledTransferService.On("UpdateSchedule", s.testAccount.AccountID, "completed-schedule-id", mock.Anything).
+		Return(nil, services.ErrScheduleNotEditable).Once()
+
+	resp, err := s.app.Test(s.jsonRequest(http.MethodPatch, "/accounts/test-account-id/schedules/test-schedule-id",
+		map[string]interface{}{"status": "paused", "amount": "2000.00"}))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	resp, err = s.app.Test(s.jsonRequest(http.MethodPatch, "/accounts/test-account-id/schedules/completed-schedule-id",
+		map[string]interface{}{"status": "active"}))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusConflict, resp.StatusCode)
+
+	s.scheduledTransferService.AssertExpectations(s.T())
+}
+
+// TestUpdateScheduleStepUp tests that raising the amount above the threshold waits for a challenge
+func (s *ScheduledTransferControllerTestSuite) TestUpdateScheduleStepUp() {
+	s.challengeService.ExpectedCalls = nil
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.challengeService.On("RequiresStepUp", types.NewMoney(9000000, "THB")).Return(true).Once()
+	s.challengeService.On("CreateScheduleUpdateChallenge", s.testUserID, mock.MatchedBy(func(pending *models.PendingScheduleUpdate) bool {
+		return pending.AccountID == s.testAccount.AccountID && pending.ScheduleID == "test-schedule-id" &&
+			*pending.Update.Amount == types.NewMoney(9000000, "THB")
+	})).Return(&models.Challenge{ChallengeID: "challenge-123", UserID: s.testUserID}, nil).Once()
+
+	resp, err := s.app.Test(s.jsonRequest(http.MethodPatch, "/accounts/test-account-id/schedules/test-schedule-id",
+		map[string]interface{}{"amount": "90000.00"}))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusAccepted, resp.StatusCode)
+	s.challengeService.AssertExpectations(s.T())
+	s.scheduledTransferService.AssertNotCalled(s.T(), "UpdateSchedule", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUpdateSchedule_InvalidStatus tests that only active and paused can be set
+func (s *ScheduledTransferControllerTestSuite) TestUpdateSchedule_InvalidStatus() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+
+	resp, err := s.app.Test(s.jsonRequest(http.MethodPatch, "/accounts/test-account-id/schedules/test-schedule-id",
+		map[string]interface{}{"status": "completed"}))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	s.scheduledTransferService.AssertNotCalled(s.T(), "UpdateSchedule", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCancelSchedule tests the CancelSchedule controller method
+func (s *ScheduledTransferControllerTestSuite) TestCancelSchedule() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Twice()
+	s.scheduledTransferService.On("CancelSchedule", s.testAccount.AccountID, "test-schedule-id").Return(nil).Once()
+	s.scheduledTransferService.On("CancelSchedule", s.testAccount.AccountID, "broken-schedule-id").Return(errors.New("database error")).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodDelete, "/accounts/test-account-id/schedules/test-schedule-id", http.NoBody))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	resp, err = s.app.Test(httptest.NewRequest(http.MethodDelete, "/accounts/test-account-id/schedules/broken-schedule-id", http.NoBody))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusInternalServerError, resp.StatusCode)
+}
+
+// TestListExecutions tests the ListExecutions controller method
+func (s *ScheduledTransferControllerTestSuite) TestListExecutions() {
+	executions := []*models.ScheduledTransferExecution{
+		{ExecutionID: "execution-2", ScheduleID: "test-schedule-id", Attempt: 1, Status: models.ExecutionSkipped, Message: "insufficient funds"},
+		{ExecutionID: "execution-1", ScheduleID: "test-schedule-id", Attempt: 1, Status: models.ExecutionSucceeded},
+	}
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.scheduledTransferService.On("GetExecutions", s.testAccount.AccountID, "test-schedule-id").Return(executions, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/schedules/test-schedule-id/executions", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	var result []*models.ScheduledTransferExecution
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&result))
+	assert.Len(s.T(), result, 2)
+	assert.Equal(s.T(), models.ExecutionSkipped, result[0].Status)
+}
+
+// TestScheduledTransferControllerSuite runs the test suite
+func TestScheduledTransferControllerSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledTransferControllerTestSuite))
+}
@@ -0,0 +1,142 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/configs"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// StatementControllerTestSuite defines the test suite
+type StatementControllerTestSuite struct {
+	suite.Suite
+	app              *fiber.App
+	statementService *mocks.StatementService
+	auditService     *mocks.AuditService
+	controller       *controllers.StatementController
+}
+
+// SetupTest runs before each test
+func (s *StatementControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.statementService = new(mocks.StatementService)
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.controller = controllers.NewStatementController(s.statementService, s.auditService)
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", "user-123")
+		return c.Next()
+	})
+	s.app.Get("/accounts/:id/statements", s.controller.GetStatement)
+	s.app.Get("/admin/accounts/:id/statements", s.controller.GetAnyStatement)
+}
+
+func (s *StatementControllerTestSuite) get(path string) (*http.Response, string) {
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	s.Require().NoError(err)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp, string(body)
+}
+
+// expectStatement opens the September statement of acc-123 and writes body for it in the format
+func (s *StatementControllerTestSuite) expectStatement(format models.StatementFormat, body string) {
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, configs.STATEMENT_LOCATION)
+	to := time.Date(2026, time.September, 30, 0, 0, 0, 0, configs.STATEMENT_LOCATION)
+	statement := &models.Statement{AccountID: "acc-123", AccountNumber: "123-4-56789-0", Currency: "THB", From: from, To: to}
+
+	s.statementService.On("OpenStatement", "acc-123", from, to).Return(statement, nil).Once()
+	s.statementService.On("WriteStatement", mock.Anything, statement, format).Run(func(args mock.Arguments) {
+		io.WriteString(args.Get(0).(io.Writer), body)
+	}).Return(nil).Once()
+}
+
+// TestGetStatementCSV tests that a CSV statement is streamed as a download
+func (s *StatementControllerTestSuite) TestGetStatementCSV() {
+	s.expectStatement(models.StatementCSV, "date,transaction_id\n")
+
+	resp, body := s.get("/accounts/acc-123/statements?from=2026-09-01&to=2026-09-30&format=csv")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(s.T(), `attachment; filename="statement-123-4-56789-0-2026-09-01-2026-09-30.csv"`, resp.Header.Get("Content-Disposition"))
+	assert.Equal(s.T(), "date,transaction_id\n", body)
+	s.statementService.AssertExpectations(s.T())
+}
+
+// TestGetStatementJSON tests that statements are JSON by default
+func (s *StatementControllerTestSuite) TestGetStatementJSON() {
+	s.expectStatement(models.StatementJSON, `{"account_id":"acc-123"}`)
+
+	resp, body := s.get("/accounts/acc-123/statements?from=2026-09-01&to=2026-09-30")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), fiber.MIMEApplicationJSONCharsetUTF8, resp.Header.Get("Content-Type"))
+	assert.Empty(s.T(), resp.Header.Get("Content-Disposition"))
+	assert.Equal(s.T(), `{"account_id":"acc-123"}`, body)
+}
+
+// TestGetStatementInvalid tests that the period and format are validated
+func (s *StatementControllerTestSuite) TestGetStatementInvalid() {
+	s.statementService.On("OpenStatement", "acc-123", mock.Anything, mock.Anything).Return(nil, services.ErrStatementPeriod).Once()
+
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "Missing period", query: "format=csv"},
+		{name: "Not a day", query: "from=2026-09-01T00:00:00Z&to=2026-09-30"},
+		{name: "Unknown format", query: "from=2026-09-01&to=2026-09-30&format=xlsx"},
+		{name: "Inverted period", query: "from=2026-09-30&to=2026-09-01"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			resp, _ := s.get("/accounts/acc-123/statements?" + tc.query)
+			assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+	s.statementService.AssertNotCalled(s.T(), "WriteStatement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetStatementNotFound tests that a missing account is reported before anything is streamed
+func (s *StatementControllerTestSuite) TestGetStatementNotFound() {
+	s.statementService.On("OpenStatement", "acc-404", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
+
+	resp, _ := s.get("/admin/accounts/acc-404/statements?from=2026-09-01&to=2026-09-30")
+
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	s.auditService.AssertNotCalled(s.T(), "Record", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetAnyStatement tests that statements read by staff are audited
+func (s *StatementControllerTestSuite) TestGetAnyStatement() {
+	s.expectStatement(models.StatementPDF, "%PDF-1.4\n")
+
+	resp, body := s.get("/admin/accounts/acc-123/statements?from=2026-09-01&to=2026-09-30&format=pdf")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "application/pdf", resp.Header.Get("Content-Type"))
+	assert.Equal(s.T(), "%PDF-1.4\n", body)
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditStatementRead && entry.ActorType == models.AuditActorStaff && entry.ResourceID == "acc-123"
+	}), mock.Anything, mock.Anything)
+}
+
+// TestStatementControllerSuite runs the test suite
+func TestStatementControllerSuite(t *testing.T) {
+	suite.Run(t, new(StatementControllerTestSuite))
+}
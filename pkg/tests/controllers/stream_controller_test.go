@@ -0,0 +1,163 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// StreamControllerTestSuite defines the test suite
+type StreamControllerTestSuite struct {
+	suite.Suite
+	app           *fiber.App
+	streamService *mocks.StreamService
+	controller    *controllers.StreamController
+	closed        chan struct{}
+}
+
+// SetupTest runs before each test
+func (s *StreamControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.streamService = new(mocks.StreamService)
+	s.controller = controllers.NewStreamController(s.streamService)
+	s.closed = make(chan struct{})
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", "user-123")
+		return c.Next()
+	})
+	s.app.Get("/stream", s.controller.Stream)
+}
+
+// expectSubscribe makes the subscription of the user return the messages, closing it is recorded in s.closed
+func (s *StreamControllerTestSuite) expectSubscribe(lastEventID string, messages chan *models.StreamMessage) {
+	var closeOnce sync.Once
+	closeSubscription := func() { closeOnce.Do(func() { close(s.closed) }) }
+	s.streamService.On("Subscribe", mock.Anything, "user-123", lastEventID).
+		Return((<-chan *models.StreamMessage)(messages), closeSubscription, nil).Once()
+}
+
+func balanceMessage(id string) *models.StreamMessage {
+	return &models.StreamMessage{ID: id, Event: models.StreamBalance, Data: json.RawMessage(`{"account_id":"acc-123"}`)}
+}
+
+// TestStreamEvents tests that messages are written as Server-Sent Events
+func (s *StreamControllerTestSuite) TestStreamEvents() {
+	messages := make(chan *models.StreamMessage, 2)
+	messages <- balanceMessage("1760000000000-1")
+	messages <- &models.StreamMessage{Event: models.StreamReset, Data: json.RawMessage(`{}`)}
+	close(messages)
+	s.expectSubscribe("1760000000000-0", messages)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "1760000000000-0")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "text/event-stream", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "retry: 3000\n\n"+
+		"id: 1760000000000-1\nevent: balance\ndata: {\"account_id\":\"acc-123\"}\n\n"+
+		"event: reset\ndata: {}\n\n", string(body))
+	assert.Eventually(s.T(), func() bool { return isClosed(s.closed) }, time.Second, 10*time.Millisecond)
+}
+
+// TestStreamLastEventIDQuery tests that clients that cannot set headers resume with a query parameter
+func (s *StreamControllerTestSuite) TestStreamLastEventIDQuery() {
+	messages := make(chan *models.StreamMessage)
+	close(messages)
+	s.expectSubscribe("1760000000000-5", messages)
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/stream?last_event_id=1760000000000-5", nil))
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.streamService.AssertExpectations(s.T())
+}
+
+// TestStreamUnavailable tests that a failed subscription is reported
+func (s *StreamControllerTestSuite) TestStreamUnavailable() {
+	s.streamService.On("Subscribe", mock.Anything, "user-123", "").Return(nil, nil, errors.New("redis is down")).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/stream", nil))
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+// TestStreamWebSocket tests that messages are sent as JSON text messages over a WebSocket
+func (s *StreamControllerTestSuite) TestStreamWebSocket() {
+	messages := make(chan *models.StreamMessage, 1)
+	messages <- balanceMessage("1760000000000-1")
+	s.expectSubscribe("1760000000000-0", messages)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	go s.app.Listener(listener)
+	defer s.app.Shutdown()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/stream?last_event_id=1760000000000-0", nil)
+	s.Require().NoError(err)
+	defer conn.Close()
+
+	var message models.StreamMessage
+	s.Require().NoError(conn.ReadJSON(&message))
+	assert.Equal(s.T(), "1760000000000-1", message.ID)
+	assert.Equal(s.T(), models.StreamBalance, message.Event)
+	assert.JSONEq(s.T(), `{"account_id":"acc-123"}`, string(message.Data))
+
+	// The end of the subscription closes the socket, the client is told to come back
+	close(messages)
+	_, _, err = conn.ReadMessage()
+	assert.True(s.T(), websocket.IsCloseError(err, websocket.CloseTryAgainLater), "unexpected error %v", err)
+	assert.Eventually(s.T(), func() bool { return isClosed(s.closed) }, time.Second, 10*time.Millisecond)
+}
+
+// TestStreamWebSocketClientGoesAway tests that the subscription ends when the client closes the socket
+func (s *StreamControllerTestSuite) TestStreamWebSocketClientGoesAway() {
+	messages := make(chan *models.StreamMessage)
+	s.expectSubscribe("", messages)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	go s.app.Listener(listener)
+	defer s.app.Shutdown()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/stream", nil)
+	s.Require().NoError(err)
+	conn.Close()
+
+	assert.Eventually(s.T(), func() bool { return isClosed(s.closed) }, time.Second, 10*time.Millisecond)
+}
+
+func isClosed(done chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// TestStreamControllerSuite runs the test suite
+func TestStreamControllerSuite(t *testing.T) {
+	suite.Run(t, new(StreamControllerTestSuite))
+}
@@ -3,18 +3,22 @@ package controllers_test
 import (
 	"backend-developer-assignment/app/controllers"
 	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
 	"backend-developer-assignment/pkg/middleware"
 	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -22,14 +26,17 @@ type TransactionControllerTestSuite struct {
 	suite.Suite
 	app                    *fiber.App
 	mockTransactionService *mocks.TransactionService
+	mockAuditService       *mocks.AuditService
 	testToken              string
 	testUserID             string
 }
 
 // SetupSuite runs once before all tests
 func (s *TransactionControllerTestSuite) SetupSuite() {
-	// Set up JWT environment for testing
-	os.Setenv("JWT_SECRET_KEY", "test-secret-key")
+	// Set up the JWT keyring for testing
+	keyring, err := utils.NewEphemeralKeyring()
+	s.Require().NoError(err)
+	utils.SetKeyring(keyring)
 }
 
 func (s *TransactionControllerTestSuite) SetupTest() {
@@ -42,16 +49,26 @@ func (s *TransactionControllerTestSuite) SetupTest() {
 		"id":  s.testUserID,
 		"exp": time.Now().Add(time.Hour * 72).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	key, err := utils.CurrentKeyring().SigningKey(time.Now())
+	s.Require().NoError(err)
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.KID
+	tokenString, _ := token.SignedString(key.PrivateKey)
 	s.testToken = tokenString
 
 	// Setup controller and routes
-	transactionController := controllers.NewTransactionController(s.mockTransactionService)
+	s.mockAuditService = new(mocks.AuditService)
+	s.mockAuditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	transactionController := controllers.NewTransactionController(s.mockTransactionService, s.mockAuditService)
 
 	// Group routes with auth middleware
-	route := s.app.Group("/transactions", middleware.AuthProtected()...)
+	route := s.app.Group("/transactions", middleware.AuthProtected(new(mocks.AuthService))...)
 	route.Get("/", transactionController.ListTransactions)
+	route.Post("/:id/reverse", transactionController.ReverseTransaction)
+	adminRoute := s.app.Group("/admin/transactions", middleware.AuthProtected(new(mocks.AuthService))...)
+	adminRoute.Post("/:id/reverse", transactionController.ReverseAnyTransaction)
+	accountRoute := s.app.Group("/accounts", middleware.AuthProtected(new(mocks.AuthService))...)
+	accountRoute.Get("/:id/transactions", transactionController.ListAccountTransactions)
 }
 
 func (s *TransactionControllerTestSuite) TestListTransactions_Success() {
@@ -61,7 +78,7 @@ func (s *TransactionControllerTestSuite) TestListTransactions_Success() {
 			TransactionID:   "transaction-1",
 			UserID:          s.testUserID,
 			Name:            "Test Transaction 1",
-			Amount:          100.00,
+			Amount:          types.NewMoney(10000, "THB"),
 			TransactionType: "deposit",
 			BaseModel: &models.BaseModel{
 				CreatedAt: time.Now(),
@@ -72,7 +89,7 @@ func (s *TransactionControllerTestSuite) TestListTransactions_Success() {
 			TransactionID:   "transaction-2",
 			UserID:          s.testUserID,
 			Name:            "Test Transaction 2",
-			Amount:          200.00,
+			Amount:          types.NewMoney(20000, "THB"),
 			TransactionType: "withdrawal",
 			BaseModel: &models.BaseModel{
 				CreatedAt: time.Now(),
@@ -119,7 +136,7 @@ func (s *TransactionControllerTestSuite) TestListTransactions_WithPagination() {
 			TransactionID:   "transaction-3",
 			UserID:          s.testUserID,
 			Name:            "Test Transaction 3",
-			Amount:          300.00,
+			Amount:          types.NewMoney(30000, "THB"),
 			TransactionType: "deposit",
 			BaseModel: &models.BaseModel{
 				CreatedAt: time.Now(),
@@ -215,7 +232,218 @@ func (s *TransactionControllerTestSuite) TestListTransactions_Unauthorized() {
 	s.Equal(http.StatusUnauthorized, resp.StatusCode)
 }
 
+func (s *TransactionControllerTestSuite) listRequest(query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/transactions?"+query, http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+	return req
+}
+
+func (s *TransactionControllerTestSuite) TestListTransactions_Search() {
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	filter := models.TransactionFilter{
+		UserID:          s.testUserID,
+		AccountID:       "account-1",
+		TransactionType: "withdrawal",
+		MinAmount:       "100",
+		MaxAmount:       "500.50",
+		From:            &from,
+		To:              &to,
+		Query:           "coffee",
+		Sort:            models.SortOldest,
+		Limit:           20,
+	}
+	s.mockTransactionService.On("SearchTransactions", filter, "cursor-1").
+		Return([]*models.Transaction{{TransactionID: "transaction-1", BaseModel: &models.BaseModel{}}}, "cursor-2", nil)
+
+	resp, err := s.app.Test(s.listRequest("account_id=account-1&type=withdrawal&min_amount=100&max_amount=500.50" +
+		"&from=2026-09-01T00:00:00Z&to=2026-10-01T00:00:00Z&q=coffee&sort=oldest&limit=20&cursor=cursor-1"))
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		Transactions []*models.Transaction `json:"transactions"`
+		NextCursor   string                `json:"next_cursor"`
+	}
+	s.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	s.Len(response.Transactions, 1)
+	s.Equal("cursor-2", response.NextCursor)
+	s.mockTransactionService.AssertExpectations(s.T())
+}
+
+func (s *TransactionControllerTestSuite) TestListTransactions_SearchInvalid() {
+	for _, query := range []string{
+		"page=2&type=deposit",
+		"type=fee",
+		"min_amount=ten",
+		"from=2026-09-01",
+		"sort=amount",
+		"limit=101",
+		"limit=many",
+	} {
+		resp, err := s.app.Test(s.listRequest(query))
+		s.NoError(err)
+		s.Equal(http.StatusBadRequest, resp.StatusCode, query)
+	}
+	s.mockTransactionService.AssertNotCalled(s.T(), "SearchTransactions", mock.Anything, mock.Anything)
+	s.mockTransactionService.AssertNotCalled(s.T(), "GetTransactionsByUserID", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionControllerTestSuite) TestListTransactions_SearchInvalidCursor() {
+	s.mockTransactionService.On("SearchTransactions", mock.Anything, "stale").Return(nil, "", services.ErrInvalidTransactionCursor)
+
+	resp, err := s.app.Test(s.listRequest("cursor=stale"))
+	s.NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TransactionControllerTestSuite) TestListAccountTransactions() {
+	filter := models.TransactionFilter{AccountID: "account-1", TransactionType: "deposit", Sort: models.SortNewest, Limit: 5}
+	s.mockTransactionService.On("GetAccountTransactions", filter, "").
+		Return([]*models.Transaction{{TransactionID: "transaction-1", BaseModel: &models.BaseModel{}}}, "", nil)
+
+	// The account in the path wins over an account_id filter
+	req := httptest.NewRequest(http.MethodGet, "/accounts/account-1/transactions?type=deposit&sort=newest&limit=5&account_id=account-2", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]json.RawMessage
+	s.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	s.Contains(response, "transactions")
+	s.NotContains(response, "next_cursor", "the last page has no next cursor")
+	s.mockTransactionService.AssertExpectations(s.T())
+}
+
 // Run the test suite
+func (s *TransactionControllerTestSuite) reverseRequest(body string) *http.Request {
+	return s.reverseRequestTo("/transactions/transaction-1/reverse", body)
+}
+
+func (s *TransactionControllerTestSuite) reverseRequestTo(path, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+	return req
+}
+
+func (s *TransactionControllerTestSuite) ownedTransaction() *models.Transaction {
+	return &models.Transaction{
+		TransactionID:   "transaction-1",
+		UserID:          s.testUserID,
+		Amount:          types.NewMoney(10000, "THB"),
+		TransactionType: "transfer",
+		Direction:       models.Credit,
+		BaseModel:       &models.BaseModel{},
+	}
+}
+
+func (s *TransactionControllerTestSuite) TestReverseTransaction_FullReversal() {
+	original := s.ownedTransaction()
+	s.mockTransactionService.On("GetTransactionByID", "transaction-1").Return(original, nil)
+	s.mockTransactionService.On("ReturnTransfer", s.testUserID, "transaction-1", (*types.Money)(nil)).
+		Return(&models.ReversalResult{Original: original, Reversals: []*models.Transaction{{TransactionID: "reversal-1", BaseModel: &models.BaseModel{}}}}, nil)
+
+	resp, err := s.app.Test(s.reverseRequest(""))
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var response models.ReversalResult
+	s.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	s.Equal("transaction-1", response.Original.TransactionID)
+	s.Len(response.Reversals, 1)
+	s.mockTransactionService.AssertExpectations(s.T())
+	s.mockAuditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditTransactionReverse && entry.Outcome == "" && entry.ActorType == models.AuditActorUser &&
+			entry.ActorID == s.testUserID && entry.ResourceType == "transaction" && entry.ResourceID == "transaction-1"
+	}), original, mock.AnythingOfType("*models.ReversalResult"))
+}
+
+func (s *TransactionControllerTestSuite) TestReverseTransaction_PartialRefund() {
+	original := s.ownedTransaction()
+	s.mockTransactionService.On("GetTransactionByID", "transaction-1").Return(original, nil)
+	s.mockTransactionService.On("ReturnTransfer", s.testUserID, "transaction-1", mock.MatchedBy(func(amount *types.Money) bool {
+		return amount != nil && *amount == types.NewMoney(2550, "THB")
+	})).Return(&models.ReversalResult{Original: original}, nil)
+
+	resp, err := s.app.Test(s.reverseRequest(`{"amount":"25.50"}`))
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.mockTransactionService.AssertExpectations(s.T())
+}
+
+func (s *TransactionControllerTestSuite) TestReverseTransaction_NotOwned() {
+	original := s.ownedTransaction()
+	original.UserID = "other-user"
+	s.mockTransactionService.On("GetTransactionByID", "transaction-1").Return(original, nil)
+
+	resp, err := s.app.Test(s.reverseRequest(""))
+	s.NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+	s.mockTransactionService.AssertNotCalled(s.T(), "ReturnTransfer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TransactionControllerTestSuite) TestReverseTransaction_InvalidAmount() {
+	s.mockTransactionService.On("GetTransactionByID", "transaction-1").Return(s.ownedTransaction(), nil)
+
+	resp, err := s.app.Test(s.reverseRequest(`{"amount":"-1"}`))
+	s.NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TransactionControllerTestSuite) TestReverseTransaction_ServiceErrors() {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{name: "Already reversed", serviceError: services.ErrTransactionAlreadyReversed, expectedStatus: http.StatusConflict},
+		{name: "Not reversible", serviceError: services.ErrTransactionNotReversible, expectedStatus: http.StatusConflict},
+		{name: "Not a received transfer", serviceError: services.ErrTransferNotReturnable, expectedStatus: http.StatusConflict},
+		{name: "Frozen account", serviceError: services.ErrAccountFrozen, expectedStatus: http.StatusForbidden},
+		{name: "Exceeds amount", serviceError: services.ErrReversalExceedsAmount, expectedStatus: http.StatusBadRequest},
+		{name: "Insufficient funds", serviceError: services.ErrInsufficientFunds, expectedStatus: http.StatusBadRequest},
+		{name: "Unexpected error", serviceError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.mockTransactionService.On("GetTransactionByID", "transaction-1").Return(s.ownedTransaction(), nil)
+			s.mockTransactionService.On("ReturnTransfer", s.testUserID, "transaction-1", (*types.Money)(nil)).Return(nil, tc.serviceError)
+
+			resp, err := s.app.Test(s.reverseRequest(""))
+			s.NoError(err)
+			s.Equal(tc.expectedStatus, resp.StatusCode)
+			s.mockAuditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+				return entry.Action == models.AuditTransactionReverse && entry.Outcome == models.AuditFailure
+			}), mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *TransactionControllerTestSuite) TestReverseAnyTransaction() {
+	// Staff reverse the transactions of any user, a withdrawal included
+	original := s.ownedTransaction()
+	original.UserID = "other-user"
+	original.TransactionType = "withdrawal"
+	original.Direction = models.Debit
+	s.mockTransactionService.On("GetTransactionByID", "transaction-1").Return(original, nil)
+	s.mockTransactionService.On("ReverseTransaction", "transaction-1", mock.MatchedBy(func(amount *types.Money) bool {
+		return amount != nil && *amount == types.NewMoney(1000, "THB")
+	})).Return(&models.ReversalResult{Original: original}, nil)
+
+	resp, err := s.app.Test(s.reverseRequestTo("/admin/transactions/transaction-1/reverse", `{"amount":"10.00"}`))
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.mockTransactionService.AssertNotCalled(s.T(), "ReturnTransfer", mock.Anything, mock.Anything, mock.Anything)
+	s.mockTransactionService.AssertExpectations(s.T())
+	s.mockAuditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditTransactionReverse && entry.ActorType == models.AuditActorStaff && entry.ActorID == s.testUserID
+	}), original, mock.Anything)
+}
+
 func TestTransactionControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(TransactionControllerTestSuite))
 }
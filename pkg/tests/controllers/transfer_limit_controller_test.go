@@ -0,0 +1,106 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// TransferLimitControllerTestSuite defines the test suite
+type TransferLimitControllerTestSuite struct {
+	suite.Suite
+	app                  *fiber.App
+	accountService       *mocks.AccountService
+	transferLimitService *mocks.TransferLimitService
+	controller           *controllers.TransferLimitController
+	testUserID           string
+	testAccount          *models.Account
+}
+
+// SetupTest runs before each test
+func (s *TransferLimitControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.accountService = new(mocks.AccountService)
+	s.transferLimitService = new(mocks.TransferLimitService)
+	s.controller = controllers.NewTransferLimitController(s.accountService, s.transferLimitService)
+	s.testUserID = "test-user-id"
+	s.testAccount = &models.Account{
+		AccountID: "test-account-id",
+		UserID:    s.testUserID,
+		Type:      "saving-account",
+		Currency:  "THB",
+	}
+
+	s.app.Get("/accounts/:id/limits", func(c *fiber.Ctx) error {
+		c.Locals("userID", s.testUserID)
+		return s.controller.GetAccountLimits(c)
+	})
+}
+
+// TestGetAccountLimits tests the GetAccountLimits controller method
+func (s *TransferLimitControllerTestSuite) TestGetAccountLimits() {
+	daily := types.NewMoney(50000000, "THB")
+	remaining := types.NewMoney(30000000, "THB")
+	limits := &models.AccountLimits{
+		AccountID: s.testAccount.AccountID,
+		Currency:  "THB",
+		Daily: models.LimitWindow{
+			Limit:     &daily,
+			Used:      types.NewMoney(20000000, "THB"),
+			Remaining: &remaining,
+		},
+		Available: &remaining,
+	}
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.transferLimitService.On("GetAccountLimits", s.testAccount).Return(limits, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/limits", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(s.T(), moneyJSON("300000.00", "THB"), result["available"])
+	assert.Nil(s.T(), result["per_transaction"])
+	assert.Equal(s.T(), moneyJSON("200000.00", "THB"), result["daily"].(map[string]interface{})["used"])
+	s.transferLimitService.AssertExpectations(s.T())
+}
+
+// TestGetAccountLimits_AccountOfAnotherUser tests that accounts of other users are not found
+func (s *TransferLimitControllerTestSuite) TestGetAccountLimits_AccountOfAnotherUser() {
+	s.accountService.On("GetAccountByID", "other-account-id").Return(&models.Account{AccountID: "other-account-id", UserID: "other-user-id"}, nil).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/other-account-id/limits", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	s.transferLimitService.AssertNotCalled(s.T(), "GetAccountLimits", mock.Anything)
+}
+
+// TestGetAccountLimits_ServiceError tests the GetAccountLimits controller method with a service error
+func (s *TransferLimitControllerTestSuite) TestGetAccountLimits_ServiceError() {
+	s.accountService.On("GetAccountByID", s.testAccount.AccountID).Return(s.testAccount, nil).Once()
+	s.transferLimitService.On("GetAccountLimits", s.testAccount).Return(nil, errors.New("database error")).Once()
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/accounts/test-account-id/limits", http.NoBody))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), http.StatusInternalServerError, resp.StatusCode)
+}
+
+// TestTransferLimitControllerSuite runs the test suite
+func TestTransferLimitControllerSuite(t *testing.T) {
+	suite.Run(t, new(TransferLimitControllerTestSuite))
+}
@@ -3,14 +3,15 @@ package controllers
 import (
 	"backend-developer-assignment/app/controllers"
 	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
 	"backend-developer-assignment/pkg/middleware"
 	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/utils"
 	"bytes"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -27,14 +28,19 @@ type UserControllerTestSuite struct {
 	suite.Suite
 	app         *fiber.App
 	mockService *mocks.UserService
+	pinLockout  *mocks.PinLockoutService
+	pinService  *mocks.PinService
+	totpService *mocks.TOTPService
 	testToken   string
 	testUserID  string
 }
 
 // SetupSuite runs once before all tests
 func (s *UserControllerTestSuite) SetupSuite() {
-	// Set up JWT environment for testing
-	os.Setenv("JWT_SECRET_KEY", "test-secret-key")
+	// Set up the JWT keyring for testing
+	keyring, err := utils.NewEphemeralKeyring()
+	s.Require().NoError(err)
+	utils.SetKeyring(keyring)
 
 	// Generate a test user ID
 	s.testUserID = uuid.New().String()
@@ -47,13 +53,19 @@ func (s *UserControllerTestSuite) SetupSuite() {
 func (s *UserControllerTestSuite) SetupTest() {
 	s.app = fiber.New()
 	s.mockService = new(mocks.UserService)
+	s.pinLockout = new(mocks.PinLockoutService)
+	s.pinService = new(mocks.PinService)
+	s.totpService = new(mocks.TOTPService)
 
-	userController := controllers.NewUserController(s.mockService)
-	route := s.app.Group("/users", middleware.AuthProtected()...)
+	userController := controllers.NewUserController(s.mockService, s.pinLockout, s.pinService, s.totpService)
+	route := s.app.Group("/users", middleware.AuthProtected(new(mocks.AuthService))...)
 	route.Get("/greeting", userController.GetUserGreeting)
 	route.Put("/greeting", userController.UpdateUserGreeting)
 	route.Get("/profile", userController.GetUser)
 	route.Patch("/profile", userController.UpdateUser)
+	route.Put("/pin", userController.ChangePin)
+	route.Post("/totp", userController.EnrollTOTP)
+	route.Post("/totp/enable", userController.EnableTOTP)
 }
 
 // Helper function to generate a test JWT token
@@ -64,11 +76,14 @@ func (s *UserControllerTestSuite) generateTestToken(userID string) string {
 		"exp": time.Now().Add(time.Hour * 24).Unix(), // 24 hours
 	}
 
-	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Create token with the key of the keyring
+	key, err := utils.CurrentKeyring().SigningKey(time.Now())
+	s.Require().NoError(err)
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.KID
 
 	// Sign token
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	tokenString, err := token.SignedString(key.PrivateKey)
 	s.NoError(err)
 
 	return tokenString
@@ -244,6 +259,7 @@ func (s *UserControllerTestSuite) TestGetUser_Success() {
 
 	// Setup mock expectations
 	s.mockService.On("GetUserByID", s.testUserID).Return(testUser, nil)
+	s.pinLockout.On("GetLockState", s.testUserID).Return(&models.PinLockState{}, nil)
 
 	// Create request
 	req := httptest.NewRequest(http.MethodGet, "/users/profile", http.NoBody)
@@ -269,6 +285,32 @@ func (s *UserControllerTestSuite) TestGetUser_Success() {
 	s.mockService.AssertExpectations(s.T())
 }
 
+// TestGetUser_PinLocked checks if the profile shows a locked PIN
+func (s *UserControllerTestSuite) TestGetUser_PinLocked() {
+	retryAt := time.Now().Add(10 * time.Minute).UTC().Truncate(time.Second)
+	s.mockService.On("GetUserByID", s.testUserID).Return(&models.User{UserID: s.testUserID, Name: "Test User"}, nil)
+	s.pinLockout.On("GetLockState", s.testUserID).Return(&models.PinLockState{FailedAttempts: 5, Locked: true, RetryAt: &retryAt}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/profile", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+	s.Equal(fiber.StatusOK, resp.StatusCode)
+
+	var respBody struct {
+		UserID  string              `json:"user_id"`
+		PinLock models.PinLockState `json:"pin_lock"`
+	}
+	s.NoError(json.NewDecoder(resp.Body).Decode(&respBody))
+
+	s.Equal(s.testUserID, respBody.UserID)
+	s.True(respBody.PinLock.Locked)
+	s.False(respBody.PinLock.Permanent)
+	s.Equal(5, respBody.PinLock.FailedAttempts)
+	s.Equal(retryAt, respBody.PinLock.RetryAt.UTC())
+}
+
 // TestGetUser_NotFound checks if missing user returns 404
 func (s *UserControllerTestSuite) TestGetUser_NotFound() {
 	// Setup mock expectations
@@ -432,6 +474,145 @@ func (s *UserControllerTestSuite) TestUpdateUser_EmptyName() {
 	s.mockService.AssertExpectations(s.T())
 }
 
+// changePinRequest sends a PIN change for the test user
+func (s *UserControllerTestSuite) changePinRequest(currentPIN, newPIN string) *http.Response {
+	requestJSON, _ := json.Marshal(map[string]string{"current_pin": currentPIN, "new_pin": newPIN})
+	req := httptest.NewRequest(http.MethodPut, "/users/pin", bytes.NewReader(requestJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+	return resp
+}
+
+// TestChangePin_Success checks that a PIN change takes the attempt back and forgets earlier failed attempts
+func (s *UserControllerTestSuite) TestChangePin_Success() {
+	attempt := &models.PinAttempt{UserID: s.testUserID}
+	s.pinLockout.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Once()
+	s.pinService.On("ChangePin", s.testUserID, "123456", "258147").Return(nil).Once()
+	s.pinLockout.On("RecordSuccess", attempt).Return(nil).Once()
+
+	resp := s.changePinRequest("123456", "258147")
+
+	s.Equal(fiber.StatusNoContent, resp.StatusCode)
+	s.pinService.AssertExpectations(s.T())
+	s.pinLockout.AssertExpectations(s.T())
+}
+
+// TestChangePin_IncorrectPin checks that a wrong current PIN stays counted as a failed attempt
+func (s *UserControllerTestSuite) TestChangePin_IncorrectPin() {
+	s.pinLockout.On("Reserve", s.testUserID, mock.Anything).Return(&models.PinAttempt{UserID: s.testUserID}, nil).Once()
+	s.pinService.On("ChangePin", s.testUserID, "000000", "258147").Return(services.ErrIncorrectPin).Once()
+
+	resp := s.changePinRequest("000000", "258147")
+
+	s.testResponse(resp, fiber.StatusUnauthorized, services.ErrIncorrectPin.Error())
+	s.pinLockout.AssertExpectations(s.T())
+	s.pinLockout.AssertNotCalled(s.T(), "RecordSuccess", mock.Anything)
+	s.pinLockout.AssertNotCalled(s.T(), "Release", mock.Anything)
+}
+
+// TestChangePin_Rejected checks that a new PIN refused by the policy or used recently is a bad request, the current
+// PIN was right so the attempt is taken back
+func (s *UserControllerTestSuite) TestChangePin_Rejected() {
+	for _, rejection := range []error{services.ErrPinPolicy, services.ErrPinReused} {
+		s.Run(rejection.Error(), func() {
+			s.SetupTest()
+			attempt := &models.PinAttempt{UserID: s.testUserID}
+			s.pinLockout.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Once()
+			s.pinService.On("ChangePin", s.testUserID, "123456", "111111").Return(rejection).Once()
+			s.pinLockout.On("RecordSuccess", attempt).Return(nil).Once()
+
+			resp := s.changePinRequest("123456", "111111")
+
+			s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+			s.pinLockout.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestChangePin_Failed checks that an attempt is taken back when the PIN could not be checked
+func (s *UserControllerTestSuite) TestChangePin_Failed() {
+	attempt := &models.PinAttempt{UserID: s.testUserID}
+	s.pinLockout.On("Reserve", s.testUserID, mock.Anything).Return(attempt, nil).Once()
+	s.pinService.On("ChangePin", s.testUserID, "123456", "258147").Return(errors.New("database error")).Once()
+	s.pinLockout.On("Release", attempt).Return(nil).Once()
+
+	resp := s.changePinRequest("123456", "258147")
+
+	s.Equal(fiber.StatusInternalServerError, resp.StatusCode)
+	s.pinLockout.AssertExpectations(s.T())
+}
+
+// TestChangePin_Locked checks that a locked PIN cannot be changed with the current PIN
+func (s *UserControllerTestSuite) TestChangePin_Locked() {
+	s.pinLockout.On("Reserve", s.testUserID, mock.Anything).Return(&models.PinAttempt{
+		UserID: s.testUserID,
+		State:  &models.PinLockState{FailedAttempts: 10, Locked: true, Permanent: true},
+	}, services.ErrPinLocked).Once()
+
+	resp := s.changePinRequest("123456", "258147")
+
+	s.Equal(fiber.StatusLocked, resp.StatusCode)
+	s.pinService.AssertNotCalled(s.T(), "ChangePin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestEnrollTOTP checks that the secret is returned once and an enabled authenticator is kept
+func (s *UserControllerTestSuite) TestEnrollTOTP() {
+	enrollment := &models.TOTPEnrollment{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/test"}
+	s.totpService.On("Enroll", s.testUserID).Return(enrollment, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/users/totp", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+	resp, err := s.app.Test(req)
+	s.NoError(err)
+
+	s.Equal(fiber.StatusCreated, resp.StatusCode)
+	var respBody models.TOTPEnrollment
+	s.NoError(json.NewDecoder(resp.Body).Decode(&respBody))
+	s.Equal(*enrollment, respBody)
+
+	s.totpService.On("Enroll", s.testUserID).Return(nil, services.ErrTOTPAlreadyEnabled).Once()
+
+	req = httptest.NewRequest(http.MethodPost, "/users/totp", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+s.testToken)
+	resp, err = s.app.Test(req)
+	s.NoError(err)
+
+	s.Equal(fiber.StatusConflict, resp.StatusCode)
+}
+
+// TestEnableTOTP checks the answers to enabling the authenticator
+func (s *UserControllerTestSuite) TestEnableTOTP() {
+	testCases := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "Enabled", expectedStatus: fiber.StatusNoContent},
+		{name: "Not set up", serviceErr: services.ErrTOTPNotEnrolled, expectedStatus: fiber.StatusBadRequest},
+		{name: "Invalid code", serviceErr: services.ErrInvalidTOTP, expectedStatus: fiber.StatusUnauthorized},
+		{name: "Already enabled", serviceErr: services.ErrTOTPAlreadyEnabled, expectedStatus: fiber.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.totpService.On("Enable", s.testUserID, "123456").Return(tc.serviceErr).Once()
+
+			requestJSON, _ := json.Marshal(map[string]string{"code": "123456"})
+			req := httptest.NewRequest(http.MethodPost, "/users/totp/enable", bytes.NewReader(requestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+s.testToken)
+			resp, err := s.app.Test(req)
+			s.NoError(err)
+
+			s.Equal(tc.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
 // Run the test suite
 func TestUserControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(UserControllerTestSuite))
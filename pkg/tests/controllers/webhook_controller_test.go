@@ -0,0 +1,170 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// WebhookControllerTestSuite defines the test suite
+type WebhookControllerTestSuite struct {
+	suite.Suite
+	app            *fiber.App
+	webhookService *mocks.WebhookService
+	auditService   *mocks.AuditService
+	controller     *controllers.WebhookController
+}
+
+// SetupTest runs before each test
+func (s *WebhookControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.webhookService = new(mocks.WebhookService)
+	s.auditService = new(mocks.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.controller = controllers.NewWebhookController(s.webhookService, s.auditService)
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", "user-123")
+		return c.Next()
+	})
+	s.app.Get("/webhooks", s.controller.ListWebhooks)
+	s.app.Post("/webhooks", s.controller.CreateWebhook)
+	s.app.Delete("/webhooks/:id", s.controller.DeleteWebhook)
+	s.app.Get("/webhooks/:id/deliveries", s.controller.ListDeliveries)
+	s.app.Post("/webhooks/:id/deliveries/:deliveryId/replay", s.controller.ReplayDelivery)
+	s.app.Get("/admin/webhooks", s.controller.ListGlobalWebhooks)
+	s.app.Post("/admin/webhooks", s.controller.CreateGlobalWebhook)
+}
+
+func (s *WebhookControllerTestSuite) request(method, path, body string) *http.Response {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestCreateWebhook tests that the secret is returned once on registration
+func (s *WebhookControllerTestSuite) TestCreateWebhook() {
+	s.webhookService.On("CreateEndpoint", "user-123", models.WebhookScopeUser, "https://partner.example.com/hooks", "Partner",
+		models.EventTypeList{models.EventTransferCompleted}).
+		Return(&models.WebhookEndpoint{EndpointID: "endpoint-1", UserID: "user-123", Scope: models.WebhookScopeUser, Secret: "whsec_abc"}, nil).Once()
+
+	resp := s.request(http.MethodPost, "/webhooks",
+		`{"url": "https://partner.example.com/hooks", "description": "Partner", "event_types": ["TransferCompleted"]}`)
+
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	var body map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(s.T(), "endpoint-1", body["endpoint_id"])
+	assert.Equal(s.T(), "whsec_abc", body["secret"])
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditWebhookCreate && entry.ActorType == models.AuditActorUser
+	}), mock.Anything, mock.Anything)
+}
+
+// TestCreateWebhookInvalid tests that invalid requests are rejected
+func (s *WebhookControllerTestSuite) TestCreateWebhookInvalid() {
+	s.webhookService.On("CreateEndpoint", "user-123", models.WebhookScopeUser, "http://partner.example.com", "", models.EventTypeList{}).
+		Return(nil, services.ErrInvalidWebhookURL).Once()
+	s.webhookService.On("CreateEndpoint", "user-123", models.WebhookScopeUser, "https://partner.example.com", "", models.EventTypeList{"Unknown"}).
+		Return(nil, fmt.Errorf("%w %q", services.ErrUnknownEventType, "Unknown")).Once()
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodPost, "/webhooks", `{"url": "not a url"}`).StatusCode)
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodPost, "/webhooks", `{"url": "http://partner.example.com"}`).StatusCode)
+	assert.Equal(s.T(), http.StatusBadRequest,
+		s.request(http.MethodPost, "/webhooks", `{"url": "https://partner.example.com", "event_types": ["Unknown"]}`).StatusCode)
+}
+
+// TestCreateGlobalWebhook tests that staff register endpoints of the all scope
+func (s *WebhookControllerTestSuite) TestCreateGlobalWebhook() {
+	s.webhookService.On("CreateEndpoint", "user-123", models.WebhookScopeAll, "https://ops.example.com/hooks", "", models.EventTypeList{}).
+		Return(&models.WebhookEndpoint{EndpointID: "endpoint-2", Scope: models.WebhookScopeAll, Secret: "whsec_def"}, nil).Once()
+
+	resp := s.request(http.MethodPost, "/admin/webhooks", `{"url": "https://ops.example.com/hooks"}`)
+
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditWebhookCreate && entry.ActorType == models.AuditActorStaff
+	}), mock.Anything, mock.Anything)
+}
+
+// TestListWebhooks tests that the secrets of listed endpoints are not returned
+func (s *WebhookControllerTestSuite) TestListWebhooks() {
+	s.webhookService.On("GetEndpointsByUserID", "user-123").
+		Return([]*models.WebhookEndpoint{{EndpointID: "endpoint-1", Secret: "whsec_abc"}}, nil).Once()
+	s.webhookService.On("GetEndpointsByScope", models.WebhookScopeAll).Return([]*models.WebhookEndpoint{}, nil).Once()
+
+	resp := s.request(http.MethodGet, "/webhooks", "")
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var body []map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&body))
+	s.Require().Len(body, 1)
+	assert.NotContains(s.T(), body[0], "secret")
+
+	assert.Equal(s.T(), http.StatusOK, s.request(http.MethodGet, "/admin/webhooks", "").StatusCode)
+}
+
+// TestDeleteWebhook tests the DeleteWebhook controller method
+func (s *WebhookControllerTestSuite) TestDeleteWebhook() {
+	s.webhookService.On("GetEndpointByID", "endpoint-1").Return(&models.WebhookEndpoint{EndpointID: "endpoint-1"}, nil).Once()
+	s.webhookService.On("DeleteEndpoint", "endpoint-1").Return(nil).Once()
+	s.webhookService.On("GetEndpointByID", "missing").Return(nil, sql.ErrNoRows).Once()
+
+	assert.Equal(s.T(), http.StatusNoContent, s.request(http.MethodDelete, "/webhooks/endpoint-1", "").StatusCode)
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodDelete, "/webhooks/missing", "").StatusCode)
+	s.webhookService.AssertExpectations(s.T())
+}
+
+// TestListDeliveries tests the ListDeliveries controller method
+func (s *WebhookControllerTestSuite) TestListDeliveries() {
+	s.webhookService.On("GetEndpointByID", "endpoint-1").Return(&models.WebhookEndpoint{EndpointID: "endpoint-1"}, nil)
+	s.webhookService.On("ListDeliveries", "endpoint-1", models.WebhookDeliveryFilter{Status: models.WebhookDeliveryDead, BeforeID: 90, Limit: 2}).
+		Return([]*models.WebhookDelivery{{DeliveryID: 89}, {DeliveryID: 85}}, nil).Once()
+
+	resp := s.request(http.MethodGet, "/webhooks/endpoint-1/deliveries?status=dead&before_id=90&limit=2", "")
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var page struct {
+		Deliveries   []*models.WebhookDelivery `json:"deliveries"`
+		NextBeforeID int64                     `json:"next_before_id"`
+	}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&page))
+	assert.Len(s.T(), page.Deliveries, 2)
+	assert.Equal(s.T(), int64(85), page.NextBeforeID, "a full page points at the next one")
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodGet, "/webhooks/endpoint-1/deliveries?status=lost", "").StatusCode)
+	assert.Equal(s.T(), http.StatusBadRequest, s.request(http.MethodGet, "/webhooks/endpoint-1/deliveries?limit=1000", "").StatusCode)
+}
+
+// TestReplayDelivery tests the ReplayDelivery controller method
+func (s *WebhookControllerTestSuite) TestReplayDelivery() {
+	s.webhookService.On("GetEndpointByID", "endpoint-1").Return(&models.WebhookEndpoint{EndpointID: "endpoint-1"}, nil)
+	s.webhookService.On("ReplayDelivery", "endpoint-1", int64(7)).
+		Return(&models.WebhookDelivery{DeliveryID: 7, Status: models.WebhookDeliveryPending}, nil).Once()
+	s.webhookService.On("ReplayDelivery", "endpoint-1", int64(8)).Return(nil, services.ErrDeliveryNotFound).Once()
+	s.webhookService.On("ReplayDelivery", "endpoint-1", int64(9)).Return(nil, services.ErrDeliveryInProgress).Once()
+
+	assert.Equal(s.T(), http.StatusAccepted, s.request(http.MethodPost, "/webhooks/endpoint-1/deliveries/7/replay", "").StatusCode)
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodPost, "/webhooks/endpoint-1/deliveries/8/replay", "").StatusCode)
+	assert.Equal(s.T(), http.StatusConflict, s.request(http.MethodPost, "/webhooks/endpoint-1/deliveries/9/replay", "").StatusCode)
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodPost, "/webhooks/endpoint-1/deliveries/abc/replay", "").StatusCode)
+}
+
+// TestWebhookControllerSuite runs the test suite
+func TestWebhookControllerSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerTestSuite))
+}
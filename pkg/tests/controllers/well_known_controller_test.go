@@ -0,0 +1,67 @@
+package controllers_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// WellKnownControllerTestSuite defines the test suite
+type WellKnownControllerTestSuite struct {
+	suite.Suite
+	app *fiber.App
+}
+
+// SetupTest runs before each test
+func (s *WellKnownControllerTestSuite) SetupTest() {
+	s.app = fiber.New()
+	controller := controllers.NewWellKnownController()
+	s.app.Get("/.well-known/jwks.json", controller.GetJWKS)
+}
+
+// TestGetJWKS tests that the public key of the keyring is published without its private part
+func (s *WellKnownControllerTestSuite) TestGetJWKS() {
+	keyring, err := utils.NewEphemeralKeyring()
+	s.Require().NoError(err)
+	utils.SetKeyring(keyring)
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "public, max-age=300", resp.Header.Get("Cache-Control"))
+
+	var response map[string][]map[string]interface{}
+	assert.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&response))
+	s.Require().Len(response["keys"], 1)
+	key := response["keys"][0]
+	signingKey, _ := keyring.SigningKey(time.Now())
+	assert.Equal(s.T(), signingKey.KID, key["kid"])
+	assert.Equal(s.T(), "OKP", key["kty"])
+	assert.Equal(s.T(), "EdDSA", key["alg"])
+	assert.Equal(s.T(), "sig", key["use"])
+	assert.NotContains(s.T(), key, "d")
+}
+
+// TestGetJWKSWithoutKeyring tests the answer before a keyring is loaded
+func (s *WellKnownControllerTestSuite) TestGetJWKSWithoutKeyring() {
+	utils.SetKeyring(nil)
+
+	resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+// TestWellKnownControllerSuite runs the test suite
+func TestWellKnownControllerSuite(t *testing.T) {
+	suite.Run(t, new(WellKnownControllerTestSuite))
+}
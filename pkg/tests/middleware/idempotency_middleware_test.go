@@ -0,0 +1,168 @@
+package middleware_test
+
+import (
+	"backend-developer-assignment/pkg/middleware"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// IdempotencyMiddlewareTestSuite is a test suite for the Idempotency middleware
+type IdempotencyMiddlewareTestSuite struct {
+	suite.Suite
+	app         *fiber.App
+	store       *mockServices.IdempotencyService
+	handlerHits int
+	handlerCode int
+}
+
+// SetupTest sets up the test suite
+func (s *IdempotencyMiddlewareTestSuite) SetupTest() {
+	s.store = new(mockServices.IdempotencyService)
+	s.handlerHits = 0
+	s.handlerCode = fiber.StatusOK
+
+	s.app = fiber.New()
+	s.app.Post("/accounts/:id/deposit",
+		func(c *fiber.Ctx) error {
+			c.Locals("userID", "user-123")
+			return c.Next()
+		},
+		middleware.Idempotency(s.store),
+		func(c *fiber.Ctx) error {
+			s.handlerHits++
+			return c.Status(s.handlerCode).JSON(fiber.Map{"balance": "100.00"})
+		},
+	)
+}
+
+func (s *IdempotencyMiddlewareTestSuite) request(key, body string) (int, string, string) {
+	req := httptest.NewRequest("POST", "/accounts/acc-123/deposit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	respBody, _ := io.ReadAll(resp.Body)
+
+	return resp.StatusCode, string(respBody), resp.Header.Get(middleware.IdempotentReplayedHeader)
+}
+
+// TestWithoutKey tests that requests without the header are processed as usual
+func (s *IdempotencyMiddlewareTestSuite) TestWithoutKey() {
+	status, _, _ := s.request("", `{"amount":"100"}`)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.Equal(s.T(), 1, s.handlerHits)
+	s.store.AssertNotCalled(s.T(), "Begin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestFirstRequestStoresResponse tests that the first request is processed and its response stored
+func (s *IdempotencyMiddlewareTestSuite) TestFirstRequestStoresResponse() {
+	var requestHash string
+	s.store.On("Begin", "user-123", "key-1", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { requestHash = args.String(2) }).
+		Return(nil, nil).Once()
+	s.store.On("Complete", "user-123", "key-1", &types.IdempotentResponse{
+		StatusCode: fiber.StatusOK,
+		Body:       []byte(`{"balance":"100.00"}`),
+	}).Return(nil).Once()
+
+	status, body, replayed := s.request("key-1", `{"amount":"100"}`)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.JSONEq(s.T(), `{"balance":"100.00"}`, body)
+	assert.Empty(s.T(), replayed)
+	assert.Equal(s.T(), 1, s.handlerHits)
+	assert.Len(s.T(), requestHash, 64)
+	s.store.AssertExpectations(s.T())
+}
+
+// TestRepeatedRequestReplaysResponse tests that a repeated key replays the stored response without calling the handler
+func (s *IdempotencyMiddlewareTestSuite) TestRepeatedRequestReplaysResponse() {
+	s.store.On("Begin", "user-123", "key-1", mock.AnythingOfType("string")).
+		Return(&types.IdempotentResponse{StatusCode: fiber.StatusOK, Body: []byte(`{"balance":"100.00"}`)}, nil).Once()
+
+	status, body, replayed := s.request("key-1", `{"amount":"100"}`)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.JSONEq(s.T(), `{"balance":"100.00"}`, body)
+	assert.Equal(s.T(), "true", replayed)
+	assert.Equal(s.T(), 0, s.handlerHits)
+	s.store.AssertExpectations(s.T())
+}
+
+// TestDifferentBodiesHashDifferently tests that the fingerprint covers the request body
+func (s *IdempotencyMiddlewareTestSuite) TestDifferentBodiesHashDifferently() {
+	hashes := []string{}
+	s.store.On("Begin", "user-123", "key-1", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { hashes = append(hashes, args.String(2)) }).
+		Return(nil, types.ErrIdempotencyKeyReused).Twice()
+
+	s.request("key-1", `{"amount":"100"}`)
+	s.request("key-1", `{"amount":"200"}`)
+
+	assert.Len(s.T(), hashes, 2)
+	assert.NotEqual(s.T(), hashes[0], hashes[1])
+}
+
+// TestBeginErrors tests the status codes returned when the key cannot be used
+func (s *IdempotencyMiddlewareTestSuite) TestBeginErrors() {
+	testCases := []struct {
+		name           string
+		beginError     error
+		expectedStatus int
+	}{
+		{name: "Failure - Key Reused", beginError: types.ErrIdempotencyKeyReused, expectedStatus: fiber.StatusUnprocessableEntity},
+		{name: "Failure - Key In Progress", beginError: types.ErrIdempotencyKeyInProgress, expectedStatus: fiber.StatusConflict},
+		{name: "Failure - Store Error", beginError: errors.New("database error"), expectedStatus: fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.store.On("Begin", "user-123", "key-1", mock.AnythingOfType("string")).Return(nil, tc.beginError).Once()
+
+			status, _, _ := s.request("key-1", `{"amount":"100"}`)
+
+			assert.Equal(s.T(), tc.expectedStatus, status)
+			assert.Equal(s.T(), 0, s.handlerHits)
+		})
+	}
+}
+
+// TestServerErrorReleasesKey tests that a server error does not store the response so the client can retry
+func (s *IdempotencyMiddlewareTestSuite) TestServerErrorReleasesKey() {
+	s.handlerCode = fiber.StatusInternalServerError
+	s.store.On("Begin", "user-123", "key-1", mock.AnythingOfType("string")).Return(nil, nil).Once()
+	s.store.On("Release", "user-123", "key-1").Return(nil).Once()
+
+	status, _, _ := s.request("key-1", `{"amount":"100"}`)
+
+	assert.Equal(s.T(), fiber.StatusInternalServerError, status)
+	s.store.AssertExpectations(s.T())
+	s.store.AssertNotCalled(s.T(), "Complete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestKeyTooLong tests that oversized keys are rejected
+func (s *IdempotencyMiddlewareTestSuite) TestKeyTooLong() {
+	status, _, _ := s.request(strings.Repeat("k", 256), `{"amount":"100"}`)
+
+	assert.Equal(s.T(), fiber.StatusBadRequest, status)
+	assert.Equal(s.T(), 0, s.handlerHits)
+}
+
+func TestIdempotencyMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyMiddlewareTestSuite))
+}
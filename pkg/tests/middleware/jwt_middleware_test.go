@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"backend-developer-assignment/pkg/middleware"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/utils"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthProtectedRejectsRevokedSessions(t *testing.T) {
+	keyring, err := utils.NewEphemeralKeyring()
+	require.NoError(t, err)
+	utils.SetKeyring(keyring)
+	t.Setenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT", "15")
+
+	sessions := new(mockServices.AuthService)
+	sessions.On("IsSessionRevoked", "active-session").Return(false, nil)
+	sessions.On("IsSessionRevoked", "revoked-session").Return(true, nil)
+	sessions.On("IsSessionRevoked", "broken-session").Return(false, errors.New("database error"))
+
+	app := fiber.New()
+	app.Get("/user/profile", append(middleware.AuthProtected(sessions), func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("sessionID").(string))
+	})...)
+
+	testCases := []struct {
+		name         string
+		sessionID    string
+		expectedCode int
+	}{
+		{name: "Active session", sessionID: "active-session", expectedCode: fiber.StatusOK},
+		{name: "Revoked session", sessionID: "revoked-session", expectedCode: fiber.StatusUnauthorized},
+		{name: "Revocation list unavailable", sessionID: "broken-session", expectedCode: fiber.StatusServiceUnavailable},
+		{name: "Token without session", sessionID: "", expectedCode: fiber.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens, err := utils.GenerateNewTokens("user-123", tc.sessionID, nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("GET", "/user/profile", nil)
+			req.Header.Set("Authorization", "Bearer "+tokens.Access)
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+		})
+	}
+	sessions.AssertNotCalled(t, "IsSessionRevoked", "")
+}
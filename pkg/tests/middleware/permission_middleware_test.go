@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"backend-developer-assignment/pkg/middleware"
+	"backend-developer-assignment/pkg/types"
+	"net/http/httptest"
+	"testing"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	testCases := []struct {
+		name         string
+		permissions  interface{}
+		expectedCode int
+	}{
+		{name: "Granted", permissions: types.PermissionsOf([]types.Role{types.RoleOperations}), expectedCode: fiber.StatusOK},
+		{name: "Other permissions", permissions: types.PermissionsOf([]types.Role{types.RoleMarketing}), expectedCode: fiber.StatusForbidden},
+		{name: "Customer token", permissions: []types.Permission(nil), expectedCode: fiber.StatusForbidden},
+		{name: "No claims", permissions: nil, expectedCode: fiber.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/admin/accounts/:id/freeze",
+				func(c *fiber.Ctx) error {
+					c.Locals("userID", "staff-user")
+					c.Locals("permissions", tc.permissions)
+					return c.Next()
+				},
+				middleware.RequirePermission(types.PermissionAccountsFreeze),
+				func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+			)
+
+			resp, err := app.Test(httptest.NewRequest("POST", "/admin/accounts/acc-123/freeze", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package routes_test
+
+import (
+	"backend-developer-assignment/app/controllers"
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/routes"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// OwnershipTestSuite sends requests for resources of another user through the registered routes
+type OwnershipTestSuite struct {
+	suite.Suite
+	app              *fiber.App
+	accountService   *mocks.AccountService
+	debitCardService *mocks.DebitCardService
+	bannerService    *mocks.BannerService
+	challengeService *mocks.ChallengeService
+	webhookService   *mocks.WebhookService
+	token            string
+}
+
+// ownedRoute is a route addressing a single resource, with the path used to request a resource of another user
+type ownedRoute struct {
+	method string
+	route  string
+	path   string
+}
+
+// ownedRoutes lists every route addressing a single account, debit card, banner, challenge or webhook
+var ownedRoutes = []ownedRoute{
+	{http.MethodGet, "/api/v1/accounts/:id", "/api/v1/accounts/victim-account"},
+	{http.MethodPatch, "/api/v1/accounts/:id", "/api/v1/accounts/victim-account"},
+	{http.MethodPut, "/api/v1/accounts/:id/main", "/api/v1/accounts/victim-account/main"},
+	{http.MethodGet, "/api/v1/accounts/:id/limits", "/api/v1/accounts/victim-account/limits"},
+	{http.MethodGet, "/api/v1/accounts/:id/statements", "/api/v1/accounts/victim-account/statements?from=2026-09-01&to=2026-09-30"},
+	{http.MethodGet, "/api/v1/accounts/:id/transactions", "/api/v1/accounts/victim-account/transactions"},
+	{http.MethodPost, "/api/v1/accounts/:id/deposit", "/api/v1/accounts/victim-account/deposit"},
+	{http.MethodPost, "/api/v1/accounts/:id/withdraw", "/api/v1/accounts/victim-account/withdraw"},
+	{http.MethodPost, "/api/v1/accounts/:id/transfer", "/api/v1/accounts/victim-account/transfer"},
+	{http.MethodGet, "/api/v1/accounts/:id/schedules", "/api/v1/accounts/victim-account/schedules"},
+	{http.MethodPost, "/api/v1/accounts/:id/schedules", "/api/v1/accounts/victim-account/schedules"},
+	{http.MethodGet, "/api/v1/accounts/:id/schedules/:scheduleId", "/api/v1/accounts/victim-account/schedules/schedule-1"},
+	{http.MethodPatch, "/api/v1/accounts/:id/schedules/:scheduleId", "/api/v1/accounts/victim-account/schedules/schedule-1"},
+	{http.MethodDelete, "/api/v1/accounts/:id/schedules/:scheduleId", "/api/v1/accounts/victim-account/schedules/schedule-1"},
+	{http.MethodGet, "/api/v1/accounts/:id/schedules/:scheduleId/executions", "/api/v1/accounts/victim-account/schedules/schedule-1/executions"},
+	{http.MethodGet, "/api/v1/accounts/:id/holds", "/api/v1/accounts/victim-account/holds"},
+	{http.MethodPost, "/api/v1/accounts/:id/holds", "/api/v1/accounts/victim-account/holds"},
+	{http.MethodPost, "/api/v1/accounts/:id/holds/:holdId/capture", "/api/v1/accounts/victim-account/holds/hold-1/capture"},
+	{http.MethodPost, "/api/v1/accounts/:id/holds/:holdId/release", "/api/v1/accounts/victim-account/holds/hold-1/release"},
+	{http.MethodGet, "/api/v1/debit-cards/:id", "/api/v1/debit-cards/victim-card"},
+	{http.MethodPut, "/api/v1/debit-cards/:id", "/api/v1/debit-cards/victim-card"},
+	{http.MethodDelete, "/api/v1/debit-cards/:id", "/api/v1/debit-cards/victim-card"},
+	{http.MethodGet, "/api/v1/banners/:id", "/api/v1/banners/victim-banner"},
+	{http.MethodPost, "/api/v1/challenges/:id/confirm", "/api/v1/challenges/victim-challenge/confirm"},
+	{http.MethodDelete, "/api/v1/webhooks/:id", "/api/v1/webhooks/victim-webhook"},
+	{http.MethodGet, "/api/v1/webhooks/:id/deliveries", "/api/v1/webhooks/victim-webhook/deliveries"},
+	{http.MethodPost, "/api/v1/webhooks/:id/deliveries/:deliveryId/replay", "/api/v1/webhooks/victim-webhook/deliveries/1/replay"},
+}
+
+// adminRoutes lists every staff route, they address resources of any user and are guarded by permissions instead
+var adminRoutes = []ownedRoute{
+	{http.MethodGet, "/api/v1/admin/users/:id", "/api/v1/admin/users/victim-user"},
+	{http.MethodGet, "/api/v1/admin/users/:id/banners", "/api/v1/admin/users/victim-user/banners"},
+	{http.MethodPost, "/api/v1/admin/accounts/:id/freeze", "/api/v1/admin/accounts/victim-account/freeze"},
+	{http.MethodPost, "/api/v1/admin/accounts/:id/unfreeze", "/api/v1/admin/accounts/victim-account/unfreeze"},
+	{http.MethodPut, "/api/v1/admin/debit-cards/:id/status", "/api/v1/admin/debit-cards/victim-card/status"},
+	{http.MethodPost, "/api/v1/admin/banners", "/api/v1/admin/banners"},
+	{http.MethodPatch, "/api/v1/admin/banners/:id", "/api/v1/admin/banners/victim-banner"},
+	{http.MethodDelete, "/api/v1/admin/banners/:id", "/api/v1/admin/banners/victim-banner"},
+	{http.MethodPost, "/api/v1/admin/transactions/:id/reverse", "/api/v1/admin/transactions/victim-transaction/reverse"},
+	{http.MethodGet, "/api/v1/admin/audit-logs", "/api/v1/admin/audit-logs?actor_id=victim-user"},
+	{http.MethodGet, "/api/v1/admin/accounts/:id/statements", "/api/v1/admin/accounts/victim-account/statements?from=2026-09-01&to=2026-09-30"},
+	{http.MethodGet, "/api/v1/admin/webhooks", "/api/v1/admin/webhooks"},
+	{http.MethodPost, "/api/v1/admin/webhooks", "/api/v1/admin/webhooks"},
+	{http.MethodDelete, "/api/v1/admin/webhooks/:id", "/api/v1/admin/webhooks/victim-webhook"},
+	{http.MethodGet, "/api/v1/admin/webhooks/:id/deliveries", "/api/v1/admin/webhooks/victim-webhook/deliveries"},
+	{http.MethodPost, "/api/v1/admin/webhooks/:id/deliveries/:deliveryId/replay", "/api/v1/admin/webhooks/victim-webhook/deliveries/1/replay"},
+}
+
+// SetupTest builds the application routes on top of service mocks
+func (s *OwnershipTestSuite) SetupTest() {
+	keyring, err := utils.NewEphemeralKeyring()
+	s.Require().NoError(err)
+	utils.SetKeyring(keyring)
+	s.T().Setenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT", "15")
+
+	s.accountService = new(mocks.AccountService)
+	s.debitCardService = new(mocks.DebitCardService)
+	s.bannerService = new(mocks.BannerService)
+	s.challengeService = new(mocks.ChallengeService)
+	s.webhookService = new(mocks.WebhookService)
+
+	// Only the owner lookups are mocked, any call past the guard fails the test
+	s.accountService.On("GetAccountByID", "victim-account").Return(&models.Account{AccountID: "victim-account", UserID: "victim-user"}, nil)
+	s.accountService.On("GetAccountByID", "missing-account").Return(nil, sql.ErrNoRows)
+	s.accountService.On("GetAccountByID", "broken-account").Return(nil, errors.New("database error"))
+	s.debitCardService.On("GetCardByID", "victim-card").Return(&models.DebitCard{CardID: "victim-card", UserID: "victim-user"}, nil)
+	s.bannerService.On("GetBannerByID", "victim-banner").Return(&models.Banner{BannerID: "victim-banner", UserID: "victim-user"}, nil)
+	s.bannerService.On("GetBannerByID", "missing-banner").Return(nil, nil)
+	s.challengeService.On("GetChallengeByID", "victim-challenge").Return(&models.Challenge{ChallengeID: "victim-challenge", UserID: "victim-user"}, nil)
+	s.webhookService.On("GetEndpointByID", "victim-webhook").Return(&models.WebhookEndpoint{EndpointID: "victim-webhook", UserID: "victim-user"}, nil)
+
+	auditService := new(mocks.AuditService)
+	auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	s.app = fiber.New()
+	routes.InitRoutes(s.app, controllers.InitController(&services.Service{
+		AccountService:     s.accountService,
+		DebitCardService:   s.debitCardService,
+		BannerService:      s.bannerService,
+		ChallengeService:   s.challengeService,
+		WebhookService:     s.webhookService,
+		IdempotencyService: new(mocks.IdempotencyService),
+		AuditService:       auditService,
+	}))
+
+	tokens, err := utils.GenerateNewTokens("intruder-user", "", nil)
+	s.Require().NoError(err)
+	s.token = tokens.Access
+}
+
+func (s *OwnershipTestSuite) request(method, path string) *http.Response {
+	req := httptest.NewRequest(method, path, strings.NewReader(`{"amount": "100.00"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestForeignResourcesAreNotFound tests that every route responds 404 for a resource of another user
+func (s *OwnershipTestSuite) TestForeignResourcesAreNotFound() {
+	for _, route := range ownedRoutes {
+		s.Run(route.method+" "+route.route, func() {
+			resp := s.request(route.method, route.path)
+
+			assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+		})
+	}
+}
+
+// TestEveryOwnedRouteIsCovered tests that routes added under an id parameter are listed in ownedRoutes
+func (s *OwnershipTestSuite) TestEveryOwnedRouteIsCovered() {
+	covered := map[string]bool{}
+	for _, route := range append(ownedRoutes, adminRoutes...) {
+		covered[route.method+" "+route.route] = true
+	}
+
+	for _, route := range s.app.GetRoutes(true) {
+		if route.Method == http.MethodHead || !strings.Contains(route.Path, "/:id") {
+			continue
+		}
+		if strings.HasPrefix(route.Path, "/api/v1/transactions") {
+			continue // transactions are checked against the user by their handler
+		}
+		if strings.HasPrefix(route.Path, "/api/v1/user/sessions") {
+			continue // sessions are checked against the user by AuthService.RevokeSession
+		}
+		assert.True(s.T(), covered[route.Method+" "+route.Path], "%s %s is not covered by an ownership test", route.Method, route.Path)
+	}
+}
+
+// TestMissingResourcesAreNotFound tests that missing resources look the same as foreign ones
+func (s *OwnershipTestSuite) TestMissingResourcesAreNotFound() {
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodGet, "/api/v1/accounts/missing-account").StatusCode)
+	assert.Equal(s.T(), http.StatusNotFound, s.request(http.MethodGet, "/api/v1/banners/missing-banner").StatusCode)
+}
+
+// TestOwnerLookupFailure tests that a failed owner lookup is not reported as not found
+func (s *OwnershipTestSuite) TestOwnerLookupFailure() {
+	assert.Equal(s.T(), http.StatusInternalServerError, s.request(http.MethodGet, "/api/v1/accounts/broken-account").StatusCode)
+}
+
+// TestOwnerIsLetThrough tests that the owner of a resource reaches the handler
+func (s *OwnershipTestSuite) TestOwnerIsLetThrough() {
+	tokens, err := utils.GenerateNewTokens("victim-user", "", nil)
+	s.Require().NoError(err)
+	s.token = tokens.Access
+	s.accountService.On("GetAccountWithDetailByID", "victim-account").
+		Return(&models.AccountWithDetails{AccountID: "victim-account", UserID: "victim-user", Currency: "THB"}, nil).Once()
+
+	resp := s.request(http.MethodGet, "/api/v1/accounts/victim-account")
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.accountService.AssertCalled(s.T(), "GetAccountWithDetailByID", "victim-account")
+}
+
+// TestAdminRoutesNeedPermission tests that a customer token is forbidden on every staff route
+func (s *OwnershipTestSuite) TestAdminRoutesNeedPermission() {
+	for _, route := range adminRoutes {
+		s.Run(route.method+" "+route.route, func() {
+			resp := s.request(route.method, route.path)
+
+			assert.Equal(s.T(), http.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
+
+// TestAdminRoutesCheckThePermission tests that a staff role only reaches the routes of its permissions
+func (s *OwnershipTestSuite) TestAdminRoutesCheckThePermission() {
+	tokens, err := utils.GenerateNewTokens("staff-user", "", []types.Role{types.RoleMarketing})
+	s.Require().NoError(err)
+	s.token = tokens.Access
+
+	assert.Equal(s.T(), http.StatusForbidden, s.request(http.MethodPost, "/api/v1/admin/accounts/victim-account/freeze").StatusCode)
+
+	tokens, err = utils.GenerateNewTokens("staff-user", "", []types.Role{types.RoleOperations})
+	s.Require().NoError(err)
+	s.token = tokens.Access
+	s.accountService.On("FreezeAccount", "victim-account").
+		Return(&models.AccountWithDetails{AccountID: "victim-account", UserID: "victim-user", Currency: "THB"}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/accounts/victim-account/freeze", strings.NewReader(`{"reason": "Suspected fraud"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	s.accountService.AssertCalled(s.T(), "FreezeAccount", "victim-account")
+}
+
+// TestOwnershipSuite runs the test suite
+func TestOwnershipSuite(t *testing.T) {
+	suite.Run(t, new(OwnershipTestSuite))
+}
@@ -0,0 +1,78 @@
+package scheduler_test
+
+import (
+	"backend-developer-assignment/pkg/scheduler"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerRunsJobOnEveryTick(t *testing.T) {
+	var runs atomic.Int32
+	s := scheduler.New("test", 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	s.Start()
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	s.Stop()
+
+	// No job runs once Stop has returned
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
+
+func TestSchedulerStopCancelsRunningJob(t *testing.T) {
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	s := scheduler.New("test", time.Hour, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	})
+
+	s.Start()
+	<-started
+	s.Stop()
+
+	assert.True(t, cancelled.Load())
+}
+
+func TestSchedulerSurvivesFailingJob(t *testing.T) {
+	var runs atomic.Int32
+	s := scheduler.New("test", 10*time.Millisecond, func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+		return errors.New("job failed")
+	})
+
+	s.Start()
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+}
+
+func TestSchedulerStartAndStopAreIdempotent(t *testing.T) {
+	var runs atomic.Int32
+	s := scheduler.New("test", time.Hour, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	s.Stop()
+	s.Start()
+	s.Start()
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 5*time.Millisecond)
+	s.Stop()
+	s.Stop()
+
+	assert.Equal(t, int32(1), runs.Load())
+}
@@ -0,0 +1,347 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// AccountHoldServiceTestSuite tests placing, capturing and releasing holds
+type AccountHoldServiceTestSuite struct {
+	suite.Suite
+	accountRepository     *mocks.AccountRepository
+	transactionRepository *mocks.TransactionRepository
+	ledgerRepository      *mocks.LedgerRepository
+	limitRepository       *mocks.TransferLimitRepository
+	holdRepository        *mocks.HoldRepository
+	outboxRepository      *mocks.OutboxRepository
+	txProvider            *mocks.TxProvider
+	service               services.AccountService
+	account               *models.AccountWithDetails
+}
+
+// SetupTest runs before each test
+func (s *AccountHoldServiceTestSuite) SetupTest() {
+	s.accountRepository = new(mocks.AccountRepository)
+	s.transactionRepository = new(mocks.TransactionRepository)
+	s.ledgerRepository = new(mocks.LedgerRepository)
+	s.limitRepository = new(mocks.TransferLimitRepository)
+	s.holdRepository = new(mocks.HoldRepository)
+	s.outboxRepository = new(mocks.OutboxRepository)
+	s.outboxRepository.On("Create", mock.AnythingOfType("*models.DomainEvent")).Return(nil).Maybe()
+	s.txProvider = new(mocks.TxProvider)
+	s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.holdRepository, s.txProvider, newCacheStub())
+	s.account = &models.AccountWithDetails{
+		AccountID: "acc-123",
+		UserID:    "user-123",
+		Type:      "saving-account",
+		Currency:  "THB",
+	}
+
+	s.accountRepository.On("GetAccountWithDetailByID", "acc-123").Return(s.account, nil).Maybe()
+	s.limitRepository.On("GetApplicableLimits", mock.Anything, mock.Anything, mock.Anything).Return([]*models.TransferLimit{}, nil).Maybe()
+	s.limitRepository.On("GetDebitTotalSince", mock.Anything, mock.Anything, mock.Anything).Return(types.NewMoney(0, "THB"), nil).Maybe()
+	s.txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+		Return(func(txFunc func(repositories.Adapters) error) error {
+			return txFunc(repositories.Adapters{
+				AccountRepository:       s.accountRepository,
+				TransactionRepository:   s.transactionRepository,
+				LedgerRepository:        s.ledgerRepository,
+				TransferLimitRepository: s.limitRepository,
+				HoldRepository:          s.holdRepository,
+				OutboxRepository:        s.outboxRepository,
+			})
+		}).Maybe()
+}
+
+// mockBalance runs the balance update function of the account against balance and records the new balance
+func (s *AccountHoldServiceTestSuite) mockBalance(balance types.Money, updated *types.Money) {
+	s.accountRepository.On("UpdateAccountBalance", "acc-123", mock.AnythingOfType("func(types.Money) (types.Money, error)")).
+		Return(func(_ string, updateFn func(types.Money) (types.Money, error)) error {
+			newBalance, err := updateFn(balance)
+			if err == nil && updated != nil {
+				*updated = newBalance
+			}
+			return err
+		}).Once()
+}
+
+func activeHold(amount int64) *models.AccountHold {
+	return &models.AccountHold{
+		HoldID:         "hold-123",
+		AccountID:      "acc-123",
+		UserID:         "user-123",
+		Amount:         types.NewMoney(amount, "THB"),
+		CapturedAmount: types.NewMoney(0, "THB"),
+		Description:    "Coffee shop",
+		Status:         models.HoldActive,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+}
+
+// TestPlaceHold tests that a hold can only reserve the available balance
+func (s *AccountHoldServiceTestSuite) TestPlaceHold() {
+	testCases := []struct {
+		name          string
+		amount        types.Money
+		expiresAt     time.Time
+		held          types.Money
+		expectedError error
+	}{
+		{
+			name:   "Success - default expiry",
+			amount: types.NewMoney(30000, "THB"),
+			held:   types.NewMoney(50000, "THB"),
+		},
+		{
+			name:          "Held funds cannot be held twice",
+			amount:        types.NewMoney(60000, "THB"),
+			held:          types.NewMoney(50000, "THB"),
+			expectedError: services.ErrInsufficientFunds,
+		},
+		{
+			name:          "Expiry in the past",
+			amount:        types.NewMoney(30000, "THB"),
+			expiresAt:     time.Now().Add(-time.Minute),
+			expectedError: services.ErrInvalidHold,
+		},
+		{
+			name:          "Wrong currency",
+			amount:        types.NewMoney(30000, "USD"),
+			expectedError: services.ErrCurrencyMismatch,
+		},
+		{
+			name:          "Zero amount",
+			amount:        types.NewMoney(0, "THB"),
+			expectedError: services.ErrInvalidAmount,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.holdRepository.On("GetHeldAmount", "acc-123", "THB", mock.AnythingOfType("time.Time")).Return(tc.held, nil).Maybe()
+			s.accountRepository.On("UpdateAccountBalance", "acc-123", mock.AnythingOfType("func(types.Money) (types.Money, error)")).
+				Return(func(_ string, updateFn func(types.Money) (types.Money, error)) error {
+					newBalance, err := updateFn(types.NewMoney(100000, "THB"))
+					if err == nil {
+						// Placing a hold leaves the ledger balance unchanged
+						assert.Equal(s.T(), types.NewMoney(100000, "THB"), newBalance)
+					}
+					return err
+				}).Maybe()
+			s.holdRepository.On("CreateHold", mock.AnythingOfType("*models.AccountHold")).Return(nil).Maybe()
+
+			hold, err := s.service.PlaceHold("acc-123", tc.amount, "Coffee shop", tc.expiresAt)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(s.T(), err, tc.expectedError)
+				assert.Nil(s.T(), hold)
+				s.holdRepository.AssertNotCalled(s.T(), "CreateHold", mock.Anything)
+				return
+			}
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), models.HoldActive, hold.Status)
+			assert.Equal(s.T(), "user-123", hold.UserID)
+			assert.WithinDuration(s.T(), time.Now().Add(7*24*time.Hour), hold.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+// TestWithdrawRespectsHolds tests that held funds cannot be withdrawn
+func (s *AccountHoldServiceTestSuite) TestWithdrawRespectsHolds() {
+	s.holdRepository.On("GetHeldAmount", "acc-123", "THB", mock.AnythingOfType("time.Time")).Return(types.NewMoney(80000, "THB"), nil)
+	s.mockBalance(types.NewMoney(100000, "THB"), nil)
+
+	_, err := s.service.WithdrawFromAccount("acc-123", types.NewMoney(30000, "THB"))
+
+	assert.ErrorIs(s.T(), err, services.ErrInsufficientFunds)
+	s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// TestTransferRespectsHolds tests that held funds cannot be transferred
+func (s *AccountHoldServiceTestSuite) TestTransferRespectsHolds() {
+	s.accountRepository.On("GetAccountWithDetailByID", "acc-456").Return(&models.AccountWithDetails{
+		AccountID: "acc-456", UserID: "user-123", Currency: "THB",
+	}, nil)
+	s.holdRepository.On("GetHeldAmount", "acc-123", "THB", mock.AnythingOfType("time.Time")).Return(types.NewMoney(80000, "THB"), nil)
+	s.accountRepository.On("TransferFunds", "acc-123", "acc-456", types.NewMoney(30000, "THB"),
+		mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
+		Return(func(_, _ string, _ types.Money, updateFn func(types.Money, types.Money) (*types.TransferResult, error)) error {
+			_, err := updateFn(types.NewMoney(100000, "THB"), types.NewMoney(0, "THB"))
+			return err
+		}).Once()
+
+	_, err := s.service.TransferBetweenAccounts("acc-123", "acc-456", types.NewMoney(30000, "THB"))
+
+	assert.ErrorIs(s.T(), err, services.ErrInsufficientFunds)
+	s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// TestCaptureHold tests full and partial captures
+func (s *AccountHoldServiceTestSuite) TestCaptureHold() {
+	testCases := []struct {
+		name            string
+		amount          *types.Money
+		expectedCapture types.Money
+		expectedBalance types.Money
+		expectedError   error
+	}{
+		{
+			name:            "Full capture",
+			amount:          nil,
+			expectedCapture: types.NewMoney(50000, "THB"),
+			expectedBalance: types.NewMoney(50000, "THB"),
+		},
+		{
+			name:            "Partial capture releases the rest",
+			amount:          thbPtr(20000),
+			expectedCapture: types.NewMoney(20000, "THB"),
+			expectedBalance: types.NewMoney(80000, "THB"),
+		},
+		{
+			name:          "Capture above the hold",
+			amount:        thbPtr(60000),
+			expectedError: services.ErrCaptureExceedsHold,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			var balance types.Money
+			var withdrawal *models.Transaction
+			s.mockBalance(types.NewMoney(100000, "THB"), &balance)
+			s.holdRepository.On("GetHoldByIDForUpdate", "hold-123").Return(activeHold(50000), nil).Once()
+			s.transactionRepository.On("Create", mock.AnythingOfType("*models.Transaction")).
+				Run(func(args mock.Arguments) { withdrawal = args.Get(0).(*models.Transaction) }).
+				Return(nil).Maybe()
+			s.ledgerRepository.On("PostEntry", mock.AnythingOfType("*models.JournalEntry")).Return(nil).Maybe()
+			s.holdRepository.On("UpdateHold", mock.AnythingOfType("*models.AccountHold")).Return(nil).Maybe()
+
+			hold, err := s.service.CaptureHold("acc-123", "hold-123", tc.amount)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(s.T(), err, tc.expectedError)
+				s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+				return
+			}
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), models.HoldCaptured, hold.Status)
+			assert.Equal(s.T(), tc.expectedCapture, hold.CapturedAmount)
+			assert.Equal(s.T(), tc.expectedBalance, balance)
+			assert.Equal(s.T(), tc.expectedCapture, withdrawal.Amount)
+			assert.Equal(s.T(), string(models.Withdrawal), withdrawal.TransactionType)
+			assert.Equal(s.T(), &withdrawal.TransactionID, hold.TransactionID)
+			s.outboxRepository.AssertCalled(s.T(), "Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+				var payload models.FundsMovedPayload
+				return event.EventType == models.EventFundsWithdrawn && json.Unmarshal(event.Payload, &payload) == nil &&
+					payload.TransactionID == withdrawal.TransactionID && payload.Amount == tc.expectedCapture && payload.Balance == tc.expectedBalance
+			}))
+		})
+	}
+}
+
+// TestCaptureHold_NotCapturable tests holds that can no longer be captured
+func (s *AccountHoldServiceTestSuite) TestCaptureHold_NotCapturable() {
+	released := activeHold(50000)
+	released.Status = models.HoldReleased
+	expired := activeHold(50000)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	otherAccount := activeHold(50000)
+	otherAccount.AccountID = "acc-456"
+
+	testCases := []struct {
+		name          string
+		hold          *models.AccountHold
+		repoErr       error
+		expectedError error
+	}{
+		{name: "Released", hold: released, expectedError: services.ErrHoldNotActive},
+		{name: "Expired", hold: expired, expectedError: services.ErrHoldExpired},
+		{name: "Hold of another account", hold: otherAccount, expectedError: services.ErrHoldNotFound},
+		{name: "Unknown hold", repoErr: sql.ErrNoRows, expectedError: services.ErrHoldNotFound},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.mockBalance(types.NewMoney(100000, "THB"), nil)
+			s.holdRepository.On("GetHoldByIDForUpdate", "hold-123").Return(tc.hold, tc.repoErr).Once()
+
+			_, err := s.service.CaptureHold("acc-123", "hold-123", nil)
+
+			assert.ErrorIs(s.T(), err, tc.expectedError)
+			s.holdRepository.AssertNotCalled(s.T(), "UpdateHold", mock.Anything)
+		})
+	}
+}
+
+// TestCaptureHold_Refused tests that a capture is refused like a withdrawal on a frozen account or over the limits
+func (s *AccountHoldServiceTestSuite) TestCaptureHold_Refused() {
+	s.Run("Frozen account", func() {
+		s.SetupTest()
+		s.account.Flags = []*models.AccountFlag{{FlagType: models.AccountFlagSystem, FlagValue: models.AccountFlagFrozen}}
+
+		_, err := s.service.CaptureHold("acc-123", "hold-123", nil)
+
+		assert.ErrorIs(s.T(), err, services.ErrAccountFrozen)
+		s.accountRepository.AssertNotCalled(s.T(), "UpdateAccountBalance", mock.Anything, mock.Anything)
+	})
+
+	s.Run("Over the per transaction limit", func() {
+		s.SetupTest()
+		perTransaction := types.NewMoney(30000, "THB")
+		s.limitRepository = new(mocks.TransferLimitRepository)
+		s.limitRepository.On("GetApplicableLimits", "user-123", "saving-account", "THB").
+			Return([]*models.TransferLimit{{AccountType: "saving-account", Currency: "THB", PerTransaction: &perTransaction}}, nil).Once()
+		s.limitRepository.On("GetDebitTotalSince", mock.Anything, mock.Anything, mock.Anything).Return(types.NewMoney(0, "THB"), nil).Maybe()
+		s.mockBalance(types.NewMoney(100000, "THB"), nil)
+		s.holdRepository.On("GetHoldByIDForUpdate", "hold-123").Return(activeHold(50000), nil).Once()
+
+		_, err := s.service.CaptureHold("acc-123", "hold-123", nil)
+
+		assert.ErrorIs(s.T(), err, services.ErrTransferLimitExceeded)
+		s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+		s.holdRepository.AssertNotCalled(s.T(), "UpdateHold", mock.Anything)
+	})
+}
+
+// TestReleaseHold tests that releasing a hold does not touch the balance
+func (s *AccountHoldServiceTestSuite) TestReleaseHold() {
+	s.holdRepository.On("GetHoldByIDForUpdate", "hold-123").Return(activeHold(50000), nil).Once()
+	s.holdRepository.On("UpdateHold", mock.MatchedBy(func(hold *models.AccountHold) bool {
+		return hold.Status == models.HoldReleased
+	})).Return(nil).Once()
+
+	hold, err := s.service.ReleaseHold("acc-123", "hold-123")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), models.HoldReleased, hold.Status)
+	s.accountRepository.AssertNotCalled(s.T(), "UpdateAccountBalance", mock.Anything, mock.Anything)
+	s.holdRepository.AssertExpectations(s.T())
+}
+
+// TestExpireHolds tests the housekeeping job
+func (s *AccountHoldServiceTestSuite) TestExpireHolds() {
+	s.holdRepository.On("ExpireHolds", mock.AnythingOfType("time.Time")).Return(int64(2), nil).Once()
+
+	assert.NoError(s.T(), s.service.ExpireHolds(context.Background()))
+	s.holdRepository.AssertExpectations(s.T())
+}
+
+// TestAccountHoldServiceSuite runs the test suite
+func TestAccountHoldServiceSuite(t *testing.T) {
+	suite.Run(t, new(AccountHoldServiceTestSuite))
+}
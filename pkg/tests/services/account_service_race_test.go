@@ -4,6 +4,7 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
 	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/types"
 	"backend-developer-assignment/platform/database"
 	"sync"
 	"testing"
@@ -48,9 +49,10 @@ func TestWithdrawRaceCondition(t *testing.T) {
 	txProvider := repositories.NewTransactionProvider(db)
 	accountRepo := repositories.NewAccountRepository(db)
 	transactionRepo := repositories.NewTransactionRepository(db)
-	service := services.NewAccountService(accountRepo, transactionRepo, txProvider)
+	holdRepo := repositories.NewHoldRepository(db)
+	service := services.NewAccountService(accountRepo, transactionRepo, holdRepo, txProvider, newCacheStub())
 
-	initAmount := 10000.0
+	initAmount := types.NewMoney(1000000, "THB")
 	account := &models.AccountWithDetails{
 		AccountID:     "test-account",
 		UserID:        "test-user",
@@ -65,8 +67,8 @@ func TestWithdrawRaceCondition(t *testing.T) {
 
 	var wg sync.WaitGroup
 	numWorkers := 100 // Simulate 100 concurrent withdrawals
-	withdrawAmount := 10.0
-	expectedFinalBalance := initAmount - (float64(numWorkers) * withdrawAmount)
+	withdrawAmount := types.NewMoney(1000, "THB")
+	expectedFinalBalance := initAmount.Amount - (int64(numWorkers) * withdrawAmount.Amount)
 
 	// Create a channel to collect errors
 	errChan := make(chan error, numWorkers)
@@ -94,25 +96,25 @@ func TestWithdrawRaceCondition(t *testing.T) {
 	}
 
 	// Check the final balance
-	var finalBalance float64
+	var finalBalance types.Money
 	err = db.Get(&finalBalance, `SELECT amount FROM account_balances WHERE account_id = 'test-account'`)
 	assert.NoError(t, err, "Failed to get final balance")
 
-	t.Logf("Initial amount: %.2f", initAmount)
-	t.Logf("Expected final balance: %.2f", expectedFinalBalance)
-	t.Logf("Actual final balance: %.2f", finalBalance)
+	t.Logf("Initial amount: %s", initAmount)
+	t.Logf("Expected final balance (minor units): %d", expectedFinalBalance)
+	t.Logf("Actual final balance: %s", finalBalance)
 	t.Logf("Number of failed withdrawals: %d", errorCount)
 
 	// If there were no errors, the final balance should match our expectation
 	if errorCount == 0 {
-		assert.Equal(t, expectedFinalBalance, finalBalance, "Balance mismatch, possible race condition")
+		assert.Equal(t, expectedFinalBalance, finalBalance.Amount, "Balance mismatch, possible race condition")
 	} else {
 		// If there were errors, we should still have a valid balance (not negative)
-		assert.GreaterOrEqual(t, finalBalance, 0.0, "Balance should not be negative")
+		assert.GreaterOrEqual(t, finalBalance.Amount, int64(0), "Balance should not be negative")
 
 		// And the balance should be the initial amount minus successful withdrawals
 		successfulWithdrawals := numWorkers - errorCount
-		expectedWithErrors := initAmount - (float64(successfulWithdrawals) * withdrawAmount)
-		assert.Equal(t, expectedWithErrors, finalBalance, "Balance doesn't match expected value with errors")
+		expectedWithErrors := initAmount.Amount - (int64(successfulWithdrawals) * withdrawAmount.Amount)
+		assert.Equal(t, expectedWithErrors, finalBalance.Amount, "Balance doesn't match expected value with errors")
 	}
 }
@@ -4,8 +4,10 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
 	"backend-developer-assignment/app/services"
+	mockCache "backend-developer-assignment/pkg/mocks/cache"
 	mocks "backend-developer-assignment/pkg/mocks/repositories"
 	"backend-developer-assignment/pkg/types"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -21,16 +23,61 @@ type AccountServiceTestSuite struct {
 	suite.Suite
 	accountRepository     *mocks.AccountRepository
 	transactionRepository *mocks.TransactionRepository
+	ledgerRepository      *mocks.LedgerRepository
+	limitRepository       *mocks.TransferLimitRepository
+	holdRepository        *mocks.HoldRepository
+	outboxRepository      *mocks.OutboxRepository
 	txProvider            *mocks.TxProvider
+	redisClient           *mockCache.RedisClient
 	service               services.AccountService
 }
 
+// newCacheStub returns a cache that accepts every invalidation, for services that only invalidate entries
+func newCacheStub() *mockCache.RedisClient {
+	redisClient := new(mockCache.RedisClient)
+	redisClient.On("InvalidateNamespace", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return redisClient
+}
+
 // SetupTest runs before each test
 func (s *AccountServiceTestSuite) SetupTest() {
 	s.accountRepository = new(mocks.AccountRepository)
 	s.transactionRepository = new(mocks.TransactionRepository)
+	s.ledgerRepository = new(mocks.LedgerRepository)
+	s.limitRepository = new(mocks.TransferLimitRepository)
+	s.holdRepository = new(mocks.HoldRepository)
+	s.outboxRepository = new(mocks.OutboxRepository)
+	s.outboxRepository.On("Create", mock.AnythingOfType("*models.DomainEvent")).Return(nil).Maybe()
 	s.txProvider = new(mocks.TxProvider)
-	s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.txProvider)
+	s.redisClient = newCacheStub()
+	s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.holdRepository, s.txProvider, s.redisClient)
+
+	// No limits apply unless a test sets them up
+	s.limitRepository.On("GetApplicableLimits", mock.Anything, mock.Anything, mock.Anything).Return([]*models.TransferLimit{}, nil).Maybe()
+	s.limitRepository.On("GetDebitTotalSince", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(accountID, currency string, since time.Time) types.Money { return types.NewMoney(0, currency) }, nil).Maybe()
+
+	// Nothing is held unless a test places holds
+	s.holdRepository.On("GetHeldAmount", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(accountID, currency string, now time.Time) types.Money { return types.NewMoney(0, currency) }, nil).Maybe()
+}
+
+// mockTransact runs the transaction function against the suite's repository mocks
+func (s *AccountServiceTestSuite) mockTransact(expectedError error) {
+	s.txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+		Return(expectedError).
+		Run(func(args mock.Arguments) {
+			txFunc := args.Get(0).(func(adapters repositories.Adapters) error)
+			err := txFunc(repositories.Adapters{
+				AccountRepository:       s.accountRepository,
+				TransactionRepository:   s.transactionRepository,
+				LedgerRepository:        s.ledgerRepository,
+				TransferLimitRepository: s.limitRepository,
+				HoldRepository:          s.holdRepository,
+				OutboxRepository:        s.outboxRepository,
+			})
+			assert.Equal(s.T(), expectedError, err)
+		}).Once()
 }
 
 // TestGetAccountByID tests the GetAccountByID function
@@ -230,7 +277,7 @@ func (s *AccountServiceTestSuite) TestGetAccountWithDetailByID() {
 				Issuer:        "Bank",
 				Color:         "#FF0000",
 				Progress:      75,
-				Amount:        1000.50,
+				Amount:        types.NewMoney(100050, "USD"),
 				CreatedAt:     now,
 				UpdatedAt:     now,
 			},
@@ -244,7 +291,7 @@ func (s *AccountServiceTestSuite) TestGetAccountWithDetailByID() {
 				Issuer:        "Bank",
 				Color:         "#FF0000",
 				Progress:      75,
-				Amount:        1000.50,
+				Amount:        types.NewMoney(100050, "USD"),
 				CreatedAt:     now,
 				UpdatedAt:     now,
 			},
@@ -308,7 +355,7 @@ func (s *AccountServiceTestSuite) TestGetAccountsWithDetailByUserID() {
 					Issuer:        "Bank A",
 					Color:         "#FF0000",
 					Progress:      75,
-					Amount:        1000.50,
+					Amount:        types.NewMoney(100050, "USD"),
 					CreatedAt:     now,
 					UpdatedAt:     now,
 				},
@@ -321,7 +368,7 @@ func (s *AccountServiceTestSuite) TestGetAccountsWithDetailByUserID() {
 					Issuer:        "Bank B",
 					Color:         "#00FF00",
 					Progress:      50,
-					Amount:        2500.75,
+					Amount:        types.NewMoney(250075, "USD"),
 					CreatedAt:     now,
 					UpdatedAt:     now,
 				},
@@ -337,7 +384,7 @@ func (s *AccountServiceTestSuite) TestGetAccountsWithDetailByUserID() {
 					Issuer:        "Bank A",
 					Color:         "#FF0000",
 					Progress:      75,
-					Amount:        1000.50,
+					Amount:        types.NewMoney(100050, "USD"),
 					CreatedAt:     now,
 					UpdatedAt:     now,
 				},
@@ -350,7 +397,7 @@ func (s *AccountServiceTestSuite) TestGetAccountsWithDetailByUserID() {
 					Issuer:        "Bank B",
 					Color:         "#00FF00",
 					Progress:      50,
-					Amount:        2500.75,
+					Amount:        types.NewMoney(250075, "USD"),
 					CreatedAt:     now,
 					UpdatedAt:     now,
 				},
@@ -409,15 +456,25 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsWithProvidedID() {
 		Currency:      "USD",
 		AccountNumber: "123456789",
 		Issuer:        "Test Bank",
-		Amount:        1000.00,
+		Amount:        types.NewMoney(100000, "USD"),
 		IsMainAccount: false,
 		Color:         "#FF5733",
 		Progress:      75,
 	}
 
 	// Mock repository behavior
+	s.mockTransact(nil)
 	s.accountRepository.On("CreateAccount", accountWithDetails).Return(nil).Once()
 
+	// The initial balance is booked as an opening-balance entry
+	s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+		return entry.EntryType == models.OpeningBalanceEntry &&
+			entry.ReferenceID == accountID &&
+			entry.Postings[0].AccountID == models.LedgerExternalAccountPrefix+"USD" && entry.Postings[0].Direction == models.Debit &&
+			entry.Postings[1].AccountID == accountID && entry.Postings[1].Direction == models.Credit &&
+			entry.Postings[1].Amount == types.NewMoney(100000, "USD")
+	})).Return(nil).Once()
+
 	// Call the service method
 	err := s.service.CreateAccountWithDetails(accountWithDetails)
 
@@ -425,6 +482,16 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsWithProvidedID() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), accountID, accountWithDetails.AccountID)
 	s.accountRepository.AssertExpectations(s.T())
+	s.ledgerRepository.AssertExpectations(s.T())
+	s.txProvider.AssertExpectations(s.T())
+
+	// The event is recorded in the same transaction
+	s.outboxRepository.AssertCalled(s.T(), "Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+		var payload models.AccountCreatedPayload
+		return event.EventType == models.EventAccountCreated && event.AggregateType == models.AggregateAccount &&
+			event.AggregateID == accountID && json.Unmarshal(event.Payload, &payload) == nil &&
+			payload.UserID == userID && payload.Balance == types.NewMoney(100000, "USD")
+	}))
 }
 
 // TestCreateAccountWithDetailsWithGeneratedID tests creating an account with a generated ID
@@ -438,13 +505,14 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsWithGeneratedID()
 		Currency:      "USD",
 		AccountNumber: "123456789",
 		Issuer:        "Test Bank",
-		Amount:        1000.00,
+		Amount:        types.NewMoney(0, "USD"),
 		IsMainAccount: false,
 		Color:         "#FF5733",
 		Progress:      75,
 	}
 
-	// Mock repository behavior with ID matcher
+	// Mock repository behavior with ID matcher, a zero balance needs no ledger entry
+	s.mockTransact(nil)
 	s.accountRepository.On("CreateAccount", mock.MatchedBy(func(a *models.AccountWithDetails) bool {
 		// Verify that an ID was generated (non-empty)
 		return a.AccountID != "" && a.UserID == userID
@@ -459,6 +527,10 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsWithGeneratedID()
 	_, err = uuid.Parse(accountWithDetails.AccountID)
 	assert.NoError(s.T(), err) // ID should be a valid UUID
 	s.accountRepository.AssertExpectations(s.T())
+	s.ledgerRepository.AssertNotCalled(s.T(), "PostEntry", mock.Anything)
+	s.outboxRepository.AssertCalled(s.T(), "Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+		return event.EventType == models.EventAccountCreated && event.AggregateID == accountWithDetails.AccountID
+	}))
 }
 
 // TestCreateAccountWithDetailsError tests creating an account with a repository error
@@ -473,7 +545,7 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsError() {
 		Currency:      "USD",
 		AccountNumber: "123456789",
 		Issuer:        "Test Bank",
-		Amount:        1000.00,
+		Amount:        types.NewMoney(100000, "USD"),
 		IsMainAccount: false,
 		Color:         "#FF5733",
 		Progress:      75,
@@ -481,6 +553,7 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsError() {
 
 	// Mock repository error
 	expectedError := errors.New("database error")
+	s.mockTransact(expectedError)
 	s.accountRepository.On("CreateAccount", accountWithDetails).Return(expectedError).Once()
 
 	// Call the service method
@@ -490,6 +563,7 @@ func (s *AccountServiceTestSuite) TestCreateAccountWithDetailsError() {
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), expectedError, err)
 	s.accountRepository.AssertExpectations(s.T())
+	s.ledgerRepository.AssertNotCalled(s.T(), "PostEntry", mock.Anything)
 }
 
 // TestUpdateAccountWithChanges tests updating an account with changes
@@ -504,7 +578,7 @@ func (s *AccountServiceTestSuite) TestUpdateAccountWithChanges() {
 		Currency:      "EUR",
 		AccountNumber: "987654321",
 		Issuer:        "New Bank",
-		Amount:        1000.00,
+		Amount:        types.NewMoney(100000, "USD"),
 		IsMainAccount: false,
 		Color:         "#33FF57",
 		Progress:      90,
@@ -699,23 +773,25 @@ func (s *AccountServiceTestSuite) TestSetMainAccountSetError() {
 func (s *AccountServiceTestSuite) TestTransferBetweenAccounts() {
 	fromAccountID := "acc-123"
 	toAccountID := "acc-456"
-	amount := 100.0
+	amount := types.NewMoney(10000, "USD")
 
 	sourceAccount := &models.AccountWithDetails{
 		AccountID:     fromAccountID,
 		UserID:        "user-123",
+		Currency:      "USD",
 		AccountNumber: "123456789",
 	}
 
 	destAccount := &models.AccountWithDetails{
 		AccountID:     toAccountID,
 		UserID:        "user-456",
+		Currency:      "USD",
 		AccountNumber: "987654321",
 	}
 
 	expectedResult := &types.TransferResult{
-		SourceBalance:      900.0,
-		DestinationBalance: 200.0,
+		SourceBalance:      types.NewMoney(90000, "USD"),
+		DestinationBalance: types.NewMoney(20000, "USD"),
 	}
 
 	// Mock GetAccountWithDetailByID for source account
@@ -733,18 +809,22 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccounts() {
 
 			// Create mock adapters
 			mockAdapters := repositories.Adapters{
-				AccountRepository:     s.accountRepository,
-				TransactionRepository: s.transactionRepository,
+				AccountRepository:       s.accountRepository,
+				TransactionRepository:   s.transactionRepository,
+				LedgerRepository:        s.ledgerRepository,
+				TransferLimitRepository: s.limitRepository,
+				HoldRepository:          s.holdRepository,
+				OutboxRepository:        s.outboxRepository,
 			}
 
 			// Mock TransferFunds
 			s.accountRepository.On("TransferFunds", fromAccountID, toAccountID, amount,
-				mock.AnythingOfType("func(float64, float64) (*types.TransferResult, error)")).
+				mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
 				Return(nil).
 				Run(func(args mock.Arguments) {
 					// Extract and call the update function
-					updateFn := args.Get(3).(func(float64, float64) (*types.TransferResult, error))
-					result, _ := updateFn(1000.0, 100.0) // Source has 1000, dest has 100
+					updateFn := args.Get(3).(func(types.Money, types.Money) (*types.TransferResult, error))
+					result, _ := updateFn(types.NewMoney(100000, "USD"), types.NewMoney(10000, "USD")) // Source has 1000, dest has 100
 
 					// Verify the result matches expected
 					assert.Equal(s.T(), expectedResult.SourceBalance, result.SourceBalance)
@@ -765,6 +845,15 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccounts() {
 					tx.TransactionType == string(models.Transfer)
 			})).Return(nil)
 
+			// Mock PostEntry for the transfer journal entry
+			s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+				return entry.EntryType == models.TransferEntry &&
+					len(entry.Postings) == 2 &&
+					entry.Postings[0].AccountID == fromAccountID && entry.Postings[0].Direction == models.Debit &&
+					entry.Postings[1].AccountID == toAccountID && entry.Postings[1].Direction == models.Credit &&
+					entry.Postings[0].Amount == amount && entry.Postings[1].Amount == amount
+			})).Return(nil)
+
 			// Execute the transaction function
 			err := txFunc(mockAdapters)
 			assert.NoError(s.T(), err)
@@ -782,24 +871,75 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccounts() {
 	// Verify all mocks were called
 	s.accountRepository.AssertExpectations(s.T())
 	s.transactionRepository.AssertExpectations(s.T())
+	s.ledgerRepository.AssertExpectations(s.T())
 	s.txProvider.AssertExpectations(s.T())
+
+	// A single event covers both accounts and is ordered with the events of each of them
+	s.outboxRepository.AssertCalled(s.T(), "Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+		var payload models.TransferCompletedPayload
+		return event.EventType == models.EventTransferCompleted &&
+			event.AggregateID == fromAccountID && event.RelatedAggregateID == toAccountID &&
+			json.Unmarshal(event.Payload, &payload) == nil &&
+			payload.FromUserID == "user-123" && payload.ToUserID == "user-456" &&
+			payload.SourceBalance == expectedResult.SourceBalance && payload.DestinationBalance == expectedResult.DestinationBalance
+	}))
+}
+
+// TestTransferBetweenAccountsWithBookkeeping tests that the bookkeeping of the caller runs in the transaction of the
+// transfer, its failure fails the transfer
+func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithBookkeeping() {
+	amount := types.NewMoney(10000, "USD")
+	s.accountRepository.On("GetAccountWithDetailByID", "acc-123").
+		Return(&models.AccountWithDetails{AccountID: "acc-123", UserID: "user-123", Currency: "USD"}, nil)
+	s.accountRepository.On("GetAccountWithDetailByID", "acc-456").
+		Return(&models.AccountWithDetails{AccountID: "acc-456", UserID: "user-456", Currency: "USD"}, nil)
+	adapters := repositories.Adapters{
+		AccountRepository:       s.accountRepository,
+		TransactionRepository:   s.transactionRepository,
+		LedgerRepository:        s.ledgerRepository,
+		TransferLimitRepository: s.limitRepository,
+		HoldRepository:          s.holdRepository,
+		OutboxRepository:        s.outboxRepository,
+	}
+	s.txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+		Return(func(txFunc func(repositories.Adapters) error) error { return txFunc(adapters) })
+	s.accountRepository.On("TransferFunds", "acc-123", "acc-456", amount, mock.Anything).
+		Return(func(_, _ string, _ types.Money, updateFn func(types.Money, types.Money) (*types.TransferResult, error)) error {
+			_, err := updateFn(types.NewMoney(100000, "USD"), types.NewMoney(0, "USD"))
+			return err
+		})
+	s.transactionRepository.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil)
+	s.ledgerRepository.On("PostEntry", mock.AnythingOfType("*models.JournalEntry")).Return(nil)
+
+	var bookkept bool
+	_, err := s.service.TransferBetweenAccountsWith("acc-123", "acc-456", amount, func(inTx repositories.Adapters) error {
+		bookkept = true
+		assert.Equal(s.T(), adapters.AccountRepository, inTx.AccountRepository)
+		return repositories.ErrScheduleLeaseLost
+	})
+
+	assert.ErrorIs(s.T(), err, repositories.ErrScheduleLeaseLost)
+	assert.True(s.T(), bookkept)
+	s.ledgerRepository.AssertNumberOfCalls(s.T(), "PostEntry", 1)
 }
 
 // TestTransferBetweenAccountsWithInsufficientFunds tests the TransferBetweenAccounts function with insufficient funds
 func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithInsufficientFunds() {
 	fromAccountID := "acc-123"
 	toAccountID := "acc-456"
-	amount := 100.0
+	amount := types.NewMoney(10000, "USD")
 
 	sourceAccount := &models.AccountWithDetails{
 		AccountID:     fromAccountID,
 		UserID:        "user-123",
+		Currency:      "USD",
 		AccountNumber: "123456789",
 	}
 
 	destAccount := &models.AccountWithDetails{
 		AccountID:     toAccountID,
 		UserID:        "user-456",
+		Currency:      "USD",
 		AccountNumber: "987654321",
 	}
 
@@ -818,18 +958,22 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithInsufficientFun
 
 			// Create mock adapters
 			mockAdapters := repositories.Adapters{
-				AccountRepository:     s.accountRepository,
-				TransactionRepository: s.transactionRepository,
+				AccountRepository:       s.accountRepository,
+				TransactionRepository:   s.transactionRepository,
+				LedgerRepository:        s.ledgerRepository,
+				TransferLimitRepository: s.limitRepository,
+				HoldRepository:          s.holdRepository,
+				OutboxRepository:        s.outboxRepository,
 			}
 
 			// Mock TransferFunds with insufficient funds error
 			s.accountRepository.On("TransferFunds", fromAccountID, toAccountID, amount,
-				mock.AnythingOfType("func(float64, float64) (*types.TransferResult, error)")).
+				mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
 				Return(services.ErrInsufficientFunds).
 				Run(func(args mock.Arguments) {
 					// Extract and call the update function
-					updateFn := args.Get(3).(func(float64, float64) (*types.TransferResult, error))
-					_, err := updateFn(50.0, 100.0) // Source has only 50, not enough
+					updateFn := args.Get(3).(func(types.Money, types.Money) (*types.TransferResult, error))
+					_, err := updateFn(types.NewMoney(5000, "USD"), types.NewMoney(10000, "USD")) // Source has only 50, not enough
 
 					// Verify the error is insufficient funds
 					assert.Equal(s.T(), services.ErrInsufficientFunds, err)
@@ -857,17 +1001,19 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithInsufficientFun
 func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithTransactionCreationError() {
 	fromAccountID := "acc-123"
 	toAccountID := "acc-456"
-	amount := 100.0
+	amount := types.NewMoney(10000, "USD")
 
 	sourceAccount := &models.AccountWithDetails{
 		AccountID:     fromAccountID,
 		UserID:        "user-123",
+		Currency:      "USD",
 		AccountNumber: "123456789",
 	}
 
 	destAccount := &models.AccountWithDetails{
 		AccountID:     toAccountID,
 		UserID:        "user-456",
+		Currency:      "USD",
 		AccountNumber: "987654321",
 	}
 
@@ -888,18 +1034,22 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithTransactionCrea
 
 			// Create mock adapters
 			mockAdapters := repositories.Adapters{
-				AccountRepository:     s.accountRepository,
-				TransactionRepository: s.transactionRepository,
+				AccountRepository:       s.accountRepository,
+				TransactionRepository:   s.transactionRepository,
+				LedgerRepository:        s.ledgerRepository,
+				TransferLimitRepository: s.limitRepository,
+				HoldRepository:          s.holdRepository,
+				OutboxRepository:        s.outboxRepository,
 			}
 
 			// Mock TransferFunds success
 			s.accountRepository.On("TransferFunds", fromAccountID, toAccountID, amount,
-				mock.AnythingOfType("func(float64, float64) (*types.TransferResult, error)")).
+				mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
 				Return(nil).
 				Run(func(args mock.Arguments) {
 					// Extract and call the update function
-					updateFn := args.Get(3).(func(float64, float64) (*types.TransferResult, error))
-					_, _ = updateFn(1000.0, 100.0) // Source has 1000, dest has 100
+					updateFn := args.Get(3).(func(types.Money, types.Money) (*types.TransferResult, error))
+					_, _ = updateFn(types.NewMoney(100000, "USD"), types.NewMoney(10000, "USD")) // Source has 1000, dest has 100
 				})
 
 			// Mock Create for withdrawal transaction with error
@@ -929,7 +1079,7 @@ func (s *AccountServiceTestSuite) TestTransferBetweenAccountsWithTransactionCrea
 // TestWithdrawFromAccount tests the WithdrawFromAccount function
 func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 	accountID := "acc-123"
-	amount := 50.0
+	amount := types.NewMoney(5000, "USD")
 
 	account := &models.AccountWithDetails{
 		AccountID:     accountID,
@@ -941,34 +1091,34 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 
 	testCases := []struct {
 		name            string
-		currentBalance  float64
-		amount          float64
+		currentBalance  types.Money
+		amount          types.Money
 		expectedError   error
-		expectedBalance float64
+		expectedBalance types.Money
 		txCreateError   error
 	}{
 		{
 			name:            "Success - Sufficient Funds",
-			currentBalance:  100.0,
-			amount:          50.0,
+			currentBalance:  types.NewMoney(10000, "USD"),
+			amount:          types.NewMoney(5000, "USD"),
 			expectedError:   nil,
-			expectedBalance: 50.0,
+			expectedBalance: types.NewMoney(5000, "USD"),
 			txCreateError:   nil,
 		},
 		{
 			name:            "Failure - Insufficient Funds",
-			currentBalance:  30.0,
-			amount:          50.0,
+			currentBalance:  types.NewMoney(3000, "USD"),
+			amount:          types.NewMoney(5000, "USD"),
 			expectedError:   services.ErrInsufficientFunds,
-			expectedBalance: 0.0,
+			expectedBalance: types.NewMoney(0, "USD"),
 			txCreateError:   nil,
 		},
 		{
 			name:            "Failure - Transaction Creation Error",
-			currentBalance:  100.0,
-			amount:          50.0,
+			currentBalance:  types.NewMoney(10000, "USD"),
+			amount:          types.NewMoney(5000, "USD"),
 			expectedError:   errors.New("failed to create transaction record"),
-			expectedBalance: 50.0,
+			expectedBalance: types.NewMoney(5000, "USD"),
 			txCreateError:   errors.New("failed to create transaction record"),
 		},
 	}
@@ -978,8 +1128,10 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 			// Reset mocks
 			s.accountRepository = new(mocks.AccountRepository)
 			s.transactionRepository = new(mocks.TransactionRepository)
+			s.ledgerRepository = new(mocks.LedgerRepository)
 			s.txProvider = new(mocks.TxProvider)
-			s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.txProvider)
+			s.redisClient = newCacheStub()
+			s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.holdRepository, s.txProvider, s.redisClient)
 
 			// Mock GetAccountWithDetailByID
 			s.accountRepository.On("GetAccountWithDetailByID", accountID).Return(account, nil)
@@ -993,8 +1145,12 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 
 					// Create mock adapters
 					mockAdapters := repositories.Adapters{
-						AccountRepository:     s.accountRepository,
-						TransactionRepository: s.transactionRepository,
+						AccountRepository:       s.accountRepository,
+						TransactionRepository:   s.transactionRepository,
+						LedgerRepository:        s.ledgerRepository,
+						TransferLimitRepository: s.limitRepository,
+						HoldRepository:          s.holdRepository,
+						OutboxRepository:        s.outboxRepository,
 					}
 
 					// Mock UpdateAccountBalance
@@ -1005,11 +1161,11 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 					}
 
 					s.accountRepository.On("UpdateAccountBalance", accountID,
-						mock.AnythingOfType("func(float64) (float64, error)")).
+						mock.AnythingOfType("func(types.Money) (types.Money, error)")).
 						Return(updateBalanceErr).
 						Run(func(args mock.Arguments) {
 							// Extract and call the update function
-							updateFn := args.Get(1).(func(float64) (float64, error))
+							updateFn := args.Get(1).(func(types.Money) (types.Money, error))
 							balance, err := updateFn(tc.currentBalance)
 
 							if updateBalanceErr == nil {
@@ -1029,6 +1185,15 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 						})).Return(tc.txCreateError)
 					}
 
+					if tc.expectedError == nil {
+						// Mock PostEntry debiting the customer account
+						s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+							return entry.EntryType == models.WithdrawalEntry &&
+								entry.Postings[0].AccountID == accountID && entry.Postings[0].Direction == models.Debit &&
+								entry.Postings[1].AccountID == models.LedgerExternalAccountPrefix+"USD"
+						})).Return(nil)
+					}
+
 					// Execute the transaction function
 					err := txFunc(mockAdapters)
 					assert.Equal(s.T(), tc.expectedError, err)
@@ -1041,7 +1206,7 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 			if tc.expectedError != nil {
 				assert.Error(s.T(), err)
 				assert.Equal(s.T(), tc.expectedError.Error(), err.Error())
-				assert.Equal(s.T(), 0.0, balance)
+				assert.Equal(s.T(), types.Money{}, balance)
 			} else {
 				assert.NoError(s.T(), err)
 				assert.Equal(s.T(), tc.expectedBalance, balance)
@@ -1050,6 +1215,7 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 			// Verify all mocks were called
 			s.accountRepository.AssertExpectations(s.T())
 			s.transactionRepository.AssertExpectations(s.T())
+			s.ledgerRepository.AssertExpectations(s.T())
 			s.txProvider.AssertExpectations(s.T())
 		})
 	}
@@ -1058,7 +1224,7 @@ func (s *AccountServiceTestSuite) TestWithdrawFromAccount() {
 // TestDepositToAccount tests the DepositToAccount function
 func (s *AccountServiceTestSuite) TestDepositToAccount() {
 	accountID := "acc-123"
-	amount := 50.0
+	amount := types.NewMoney(5000, "USD")
 
 	account := &models.AccountWithDetails{
 		AccountID:     accountID,
@@ -1070,24 +1236,24 @@ func (s *AccountServiceTestSuite) TestDepositToAccount() {
 
 	testCases := []struct {
 		name            string
-		currentBalance  float64
-		amount          float64
+		currentBalance  types.Money
+		amount          types.Money
 		expectedError   error
-		expectedBalance float64
+		expectedBalance types.Money
 	}{
 		{
 			name:            "Success - Deposit Funds",
-			currentBalance:  100.0,
-			amount:          50.0,
+			currentBalance:  types.NewMoney(10000, "USD"),
+			amount:          types.NewMoney(5000, "USD"),
 			expectedError:   nil,
-			expectedBalance: 150.0,
+			expectedBalance: types.NewMoney(15000, "USD"),
 		},
 		{
 			name:            "Failure - Transaction Creation Error",
-			currentBalance:  100.0,
-			amount:          50.0,
+			currentBalance:  types.NewMoney(10000, "USD"),
+			amount:          types.NewMoney(5000, "USD"),
 			expectedError:   errors.New("failed to create transaction record"),
-			expectedBalance: 150.0,
+			expectedBalance: types.NewMoney(15000, "USD"),
 		},
 	}
 
@@ -1096,8 +1262,10 @@ func (s *AccountServiceTestSuite) TestDepositToAccount() {
 			// Reset mocks
 			s.accountRepository = new(mocks.AccountRepository)
 			s.transactionRepository = new(mocks.TransactionRepository)
+			s.ledgerRepository = new(mocks.LedgerRepository)
 			s.txProvider = new(mocks.TxProvider)
-			s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.txProvider)
+			s.redisClient = newCacheStub()
+			s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.holdRepository, s.txProvider, s.redisClient)
 
 			// Mock GetAccountWithDetailByID
 			s.accountRepository.On("GetAccountWithDetailByID", accountID).Return(account, nil)
@@ -1111,17 +1279,21 @@ func (s *AccountServiceTestSuite) TestDepositToAccount() {
 
 					// Create mock adapters
 					mockAdapters := repositories.Adapters{
-						AccountRepository:     s.accountRepository,
-						TransactionRepository: s.transactionRepository,
+						AccountRepository:       s.accountRepository,
+						TransactionRepository:   s.transactionRepository,
+						LedgerRepository:        s.ledgerRepository,
+						TransferLimitRepository: s.limitRepository,
+						HoldRepository:          s.holdRepository,
+						OutboxRepository:        s.outboxRepository,
 					}
 
 					// Mock UpdateAccountBalance
 					s.accountRepository.On("UpdateAccountBalance", accountID,
-						mock.AnythingOfType("func(float64) (float64, error)")).
+						mock.AnythingOfType("func(types.Money) (types.Money, error)")).
 						Return(nil).
 						Run(func(args mock.Arguments) {
 							// Extract and call the update function
-							updateFn := args.Get(1).(func(float64) (float64, error))
+							updateFn := args.Get(1).(func(types.Money) (types.Money, error))
 							balance, err := updateFn(tc.currentBalance)
 
 							assert.NoError(s.T(), err)
@@ -1135,6 +1307,13 @@ func (s *AccountServiceTestSuite) TestDepositToAccount() {
 								tx.Amount == amount &&
 								tx.TransactionType == string(models.Deposit)
 						})).Return(nil)
+
+						// Mock PostEntry crediting the customer account
+						s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+							return entry.EntryType == models.DepositEntry &&
+								entry.Postings[0].AccountID == models.LedgerExternalAccountPrefix+"USD" &&
+								entry.Postings[1].AccountID == accountID && entry.Postings[1].Direction == models.Credit
+						})).Return(nil)
 					} else {
 						s.transactionRepository.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
 							return tx.AccountID == accountID &&
@@ -1155,20 +1334,117 @@ func (s *AccountServiceTestSuite) TestDepositToAccount() {
 			if tc.expectedError != nil {
 				assert.Error(s.T(), err)
 				assert.Equal(s.T(), tc.expectedError, err)
-				assert.Equal(s.T(), 0.0, balance)
+				assert.Equal(s.T(), types.Money{}, balance)
+				s.redisClient.AssertNotCalled(s.T(), "InvalidateNamespace", mock.Anything, mock.Anything)
 			} else {
 				assert.NoError(s.T(), err)
 				assert.Equal(s.T(), tc.expectedBalance, balance)
+				// The cached transaction listings of the user and of the account move to a new version
+				s.redisClient.AssertCalled(s.T(), "InvalidateNamespace", mock.Anything, "transactions:user:user-123")
+				s.redisClient.AssertCalled(s.T(), "InvalidateNamespace", mock.Anything, "transactions:account:acc-123")
+				s.outboxRepository.AssertCalled(s.T(), "Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+					var payload models.FundsMovedPayload
+					return event.EventType == models.EventFundsDeposited && event.AggregateID == accountID &&
+						json.Unmarshal(event.Payload, &payload) == nil &&
+						payload.Amount == amount && payload.Balance == tc.expectedBalance && payload.TransactionID != ""
+				}))
 			}
 
 			// Verify all mocks were called
 			s.accountRepository.AssertExpectations(s.T())
 			s.transactionRepository.AssertExpectations(s.T())
+			s.ledgerRepository.AssertExpectations(s.T())
 			s.txProvider.AssertExpectations(s.T())
 		})
 	}
 }
 
+// frozenAccount returns an account carrying the system frozen flag
+func frozenAccount(accountID string) *models.AccountWithDetails {
+	return &models.AccountWithDetails{
+		AccountID: accountID,
+		UserID:    "user-123",
+		Currency:  "USD",
+		Flags:     []*models.AccountFlag{{FlagType: models.AccountFlagSystem, FlagValue: models.AccountFlagFrozen}},
+	}
+}
+
+// TestFrozenAccountCannotMoveMoney tests that withdrawals, deposits and transfers of a frozen account are refused
+func (s *AccountServiceTestSuite) TestFrozenAccountCannotMoveMoney() {
+	s.accountRepository.On("GetAccountWithDetailByID", "frozen-account").Return(frozenAccount("frozen-account"), nil)
+	s.accountRepository.On("GetAccountWithDetailByID", "acc-123").
+		Return(&models.AccountWithDetails{AccountID: "acc-123", UserID: "user-123", Currency: "USD"}, nil)
+
+	_, err := s.service.WithdrawFromAccount("frozen-account", types.NewMoney(100, "USD"))
+	assert.ErrorIs(s.T(), err, services.ErrAccountFrozen)
+
+	_, err = s.service.DepositToAccount("frozen-account", types.NewMoney(100, "USD"))
+	assert.ErrorIs(s.T(), err, services.ErrAccountFrozen)
+
+	_, err = s.service.TransferBetweenAccounts("frozen-account", "acc-123", types.NewMoney(100, "USD"))
+	assert.ErrorIs(s.T(), err, services.ErrAccountFrozen)
+
+	_, err = s.service.TransferBetweenAccounts("acc-123", "frozen-account", types.NewMoney(100, "USD"))
+	assert.ErrorIs(s.T(), err, services.ErrAccountFrozen)
+
+	s.txProvider.AssertNotCalled(s.T(), "Transact", mock.Anything)
+}
+
+// TestTransferToSameAccount tests that an account cannot transfer to itself
+func (s *AccountServiceTestSuite) TestTransferToSameAccount() {
+	_, err := s.service.TransferBetweenAccounts("acc-123", "acc-123", types.NewMoney(100, "USD"))
+
+	assert.ErrorIs(s.T(), err, services.ErrSameAccount)
+	s.accountRepository.AssertNotCalled(s.T(), "GetAccountWithDetailByID", mock.Anything)
+	s.txProvider.AssertNotCalled(s.T(), "Transact", mock.Anything)
+}
+
+// TestFreezeAccount tests freezing and unfreezing an account
+func (s *AccountServiceTestSuite) TestFreezeAccount() {
+	account := &models.AccountWithDetails{AccountID: "acc-123", UserID: "user-123", Currency: "USD"}
+
+	testCases := []struct {
+		name          string
+		freeze        bool
+		applied       bool
+		repoErr       error
+		expectedError error
+	}{
+		{name: "Freeze", freeze: true, applied: true},
+		{name: "Freeze - already frozen", freeze: true, applied: false, expectedError: services.ErrAccountFrozen},
+		{name: "Freeze - repository error", freeze: true, repoErr: errors.New("database error"), expectedError: errors.New("database error")},
+		{name: "Unfreeze", freeze: false, applied: true},
+		{name: "Unfreeze - not frozen", freeze: false, applied: false, expectedError: services.ErrAccountNotFrozen},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.accountRepository = new(mocks.AccountRepository)
+			s.service = services.NewAccountService(s.accountRepository, s.transactionRepository, s.holdRepository, s.txProvider, s.redisClient)
+			s.accountRepository.On("GetAccountWithDetailByID", "acc-123").Return(account, nil)
+
+			var result *models.AccountWithDetails
+			var err error
+			if tc.freeze {
+				s.accountRepository.On("FreezeAccount", "acc-123", "user-123").Return(tc.applied, tc.repoErr).Once()
+				result, err = s.service.FreezeAccount("acc-123")
+			} else {
+				s.accountRepository.On("UnfreezeAccount", "acc-123").Return(tc.applied, tc.repoErr).Once()
+				result, err = s.service.UnfreezeAccount("acc-123")
+			}
+
+			if tc.expectedError != nil {
+				assert.EqualError(s.T(), err, tc.expectedError.Error())
+				assert.Nil(s.T(), result)
+			} else {
+				assert.NoError(s.T(), err)
+				assert.Equal(s.T(), account, result)
+			}
+			s.accountRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
 // TestAccountServiceSuite runs the test suite
 func TestAccountServiceSuite(t *testing.T) {
 	suite.Run(t, new(AccountServiceTestSuite))
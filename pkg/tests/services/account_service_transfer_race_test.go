@@ -4,6 +4,7 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/repositories"
 	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/types"
 	"sync"
 	"testing"
 
@@ -15,10 +16,11 @@ func TestTransferRaceCondition(t *testing.T) {
 	txProvider := repositories.NewTransactionProvider(db)
 	accountRepo := repositories.NewAccountRepository(db)
 	transactionRepo := repositories.NewTransactionRepository(db)
-	service := services.NewAccountService(accountRepo, transactionRepo, txProvider)
+	holdRepo := repositories.NewHoldRepository(db)
+	service := services.NewAccountService(accountRepo, transactionRepo, holdRepo, txProvider, newCacheStub())
 
 	// Create source account with initial balance
-	sourceInitAmount := 10000.0
+	sourceInitAmount := types.NewMoney(1000000, "THB")
 	sourceAccount := &models.AccountWithDetails{
 		AccountID:     "source-account",
 		UserID:        "test-user",
@@ -33,7 +35,7 @@ func TestTransferRaceCondition(t *testing.T) {
 	assert.NoError(t, err, "Failed to create source account")
 
 	// Create destination account
-	destInitAmount := 0.0
+	destInitAmount := types.NewMoney(0, "THB")
 	destAccount := &models.AccountWithDetails{
 		AccountID:     "dest-account",
 		UserID:        "test-user",
@@ -49,9 +51,9 @@ func TestTransferRaceCondition(t *testing.T) {
 
 	var wg sync.WaitGroup
 	numWorkers := 10 // Simulate 10 concurrent transfers
-	transferAmount := 100.0
-	expectedSourceBalance := sourceInitAmount - (float64(numWorkers) * transferAmount)
-	expectedDestBalance := destInitAmount + (float64(numWorkers) * transferAmount)
+	transferAmount := types.NewMoney(10000, "THB")
+	expectedSourceBalance := sourceInitAmount.Amount - (int64(numWorkers) * transferAmount.Amount)
+	expectedDestBalance := destInitAmount.Amount + (int64(numWorkers) * transferAmount.Amount)
 
 	// Create a channel to collect errors
 	errChan := make(chan error, numWorkers)
@@ -79,41 +81,41 @@ func TestTransferRaceCondition(t *testing.T) {
 	}
 
 	// Check the final balances
-	var finalSourceBalance float64
+	var finalSourceBalance types.Money
 	err = db.Get(&finalSourceBalance, `SELECT amount FROM account_balances WHERE account_id = 'source-account'`)
 	assert.NoError(t, err, "Failed to get source account balance")
 
-	var finalDestBalance float64
+	var finalDestBalance types.Money
 	err = db.Get(&finalDestBalance, `SELECT amount FROM account_balances WHERE account_id = 'dest-account'`)
 	assert.NoError(t, err, "Failed to get destination account balance")
 
-	t.Logf("Initial source amount: %.2f", sourceInitAmount)
-	t.Logf("Initial destination amount: %.2f", destInitAmount)
-	t.Logf("Expected final source balance: %.2f", expectedSourceBalance)
-	t.Logf("Expected final destination balance: %.2f", expectedDestBalance)
-	t.Logf("Actual final source balance: %.2f", finalSourceBalance)
-	t.Logf("Actual final destination balance: %.2f", finalDestBalance)
+	t.Logf("Initial source amount: %s", sourceInitAmount)
+	t.Logf("Initial destination amount: %s", destInitAmount)
+	t.Logf("Expected final source balance (minor units): %d", expectedSourceBalance)
+	t.Logf("Expected final destination balance (minor units): %d", expectedDestBalance)
+	t.Logf("Actual final source balance: %s", finalSourceBalance)
+	t.Logf("Actual final destination balance: %s", finalDestBalance)
 	t.Logf("Number of failed transfers: %d", errorCount)
 
 	// If there were no errors, the final balances should match our expectations
 	if errorCount == 0 {
-		assert.Equal(t, expectedSourceBalance, finalSourceBalance, "Source balance mismatch, possible race condition")
-		assert.Equal(t, expectedDestBalance, finalDestBalance, "Destination balance mismatch, possible race condition")
+		assert.Equal(t, expectedSourceBalance, finalSourceBalance.Amount, "Source balance mismatch, possible race condition")
+		assert.Equal(t, expectedDestBalance, finalDestBalance.Amount, "Destination balance mismatch, possible race condition")
 	} else {
 		// If there were errors, we should still have valid balances
-		assert.GreaterOrEqual(t, finalSourceBalance, 0.0, "Source balance should not be negative")
+		assert.GreaterOrEqual(t, finalSourceBalance.Amount, int64(0), "Source balance should not be negative")
 
 		// Calculate expected balances with errors
 		successfulTransfers := numWorkers - errorCount
-		expectedSourceWithErrors := sourceInitAmount - (float64(successfulTransfers) * transferAmount)
-		expectedDestWithErrors := destInitAmount + (float64(successfulTransfers) * transferAmount)
+		expectedSourceWithErrors := sourceInitAmount.Amount - (int64(successfulTransfers) * transferAmount.Amount)
+		expectedDestWithErrors := destInitAmount.Amount + (int64(successfulTransfers) * transferAmount.Amount)
 
-		assert.Equal(t, expectedSourceWithErrors, finalSourceBalance, "Source balance doesn't match expected value with errors")
-		assert.Equal(t, expectedDestWithErrors, finalDestBalance, "Destination balance doesn't match expected value with errors")
+		assert.Equal(t, expectedSourceWithErrors, finalSourceBalance.Amount, "Source balance doesn't match expected value with errors")
+		assert.Equal(t, expectedDestWithErrors, finalDestBalance.Amount, "Destination balance doesn't match expected value with errors")
 	}
 
 	// Verify that the sum of both accounts remains constant (conservation of money)
-	totalBefore := sourceInitAmount + destInitAmount
-	totalAfter := finalSourceBalance + finalDestBalance
+	totalBefore := sourceInitAmount.Amount + destInitAmount.Amount
+	totalAfter := finalSourceBalance.Amount + finalDestBalance.Amount
 	assert.Equal(t, totalBefore, totalAfter, "Total money in the system should remain constant")
 }
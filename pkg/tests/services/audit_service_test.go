@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// AuditServiceTestSuite is a test suite for AuditService
+type AuditServiceTestSuite struct {
+	suite.Suite
+	auditLogRepository *mocks.AuditLogRepository
+	service            services.AuditService
+}
+
+// SetupTest sets up the test suite
+func (s *AuditServiceTestSuite) SetupTest() {
+	s.auditLogRepository = new(mocks.AuditLogRepository)
+	s.service = services.NewAuditService(s.auditLogRepository)
+}
+
+// TestRecord tests that entries are stored with their snapshots and defaults
+func (s *AuditServiceTestSuite) TestRecord() {
+	var stored *models.AuditLog
+	s.auditLogRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.AuditLog)
+	}).Return(nil).Once()
+
+	var noAccount *models.Account
+	s.service.Record(&models.AuditLog{Action: models.AuditAccountUpdate, ActorID: "user-123", ResourceType: "account", ResourceID: "acc-123"},
+		noAccount, map[string]string{"color": "#FF0000"})
+
+	s.Require().NotNil(stored)
+	assert.Equal(s.T(), models.AuditSuccess, stored.Outcome)
+	assert.Equal(s.T(), models.AuditActorUser, stored.ActorType)
+	assert.False(s.T(), stored.CreatedAt.IsZero())
+	assert.Nil(s.T(), stored.Before, "a nil snapshot is left out")
+	s.Require().NotNil(stored.After)
+	assert.JSONEq(s.T(), `{"color": "#FF0000"}`, string(*stored.After))
+}
+
+// TestRecordDoesNotFail tests that a failing store does not surface to the audited action
+func (s *AuditServiceTestSuite) TestRecordDoesNotFail() {
+	s.auditLogRepository.On("Create", mock.Anything).Return(errors.New("database error")).Once()
+
+	assert.NotPanics(s.T(), func() {
+		s.service.Record(&models.AuditLog{Action: models.AuditWithdrawal, Outcome: models.AuditFailure}, nil, nil)
+	})
+	s.auditLogRepository.AssertExpectations(s.T())
+}
+
+// TestList tests the page size limits of List
+func (s *AuditServiceTestSuite) TestList() {
+	testCases := []struct {
+		name          string
+		limit         int
+		expectedLimit int
+	}{
+		{name: "Default page size", limit: 0, expectedLimit: services.DefaultAuditLogLimit},
+		{name: "Requested page size", limit: 20, expectedLimit: 20},
+		{name: "Page size above the maximum", limit: 1000, expectedLimit: services.MaxAuditLogLimit},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.auditLogRepository.On("List", models.AuditLogFilter{Action: models.AuditTransfer, Limit: tc.expectedLimit}).
+				Return([]*models.AuditLog{{AuditID: 1}}, nil).Once()
+
+			entries, err := s.service.List(models.AuditLogFilter{Action: models.AuditTransfer, Limit: tc.limit})
+
+			assert.NoError(s.T(), err)
+			assert.Len(s.T(), entries, 1)
+		})
+	}
+
+	s.auditLogRepository.On("List", mock.Anything).Return(nil, errors.New("database error")).Once()
+	_, err := s.service.List(models.AuditLogFilter{})
+	assert.Error(s.T(), err)
+}
+
+// TestAuditServiceSuite runs the test suite
+func TestAuditServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
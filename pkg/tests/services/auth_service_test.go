@@ -0,0 +1,455 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mockCache "backend-developer-assignment/pkg/mocks/cache"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// AuthServiceTestSuite is a test suite for AuthService
+type AuthServiceTestSuite struct {
+	suite.Suite
+	refreshTokenRepository *mocks.RefreshTokenRepository
+	sessionRepository      *mocks.SessionRepository
+	roleRepository         *mocks.RoleRepository
+	redisClient            *mockCache.RedisClient
+	service                services.AuthService
+}
+
+const (
+	authUserID       = "user-123"
+	authDeviceID     = "device-123"
+	authFamilyID     = "family-123"
+	authRefreshToken = "refresh-token-123"
+)
+
+// SetupTest sets up the test suite
+func (s *AuthServiceTestSuite) SetupTest() {
+	keyring, err := utils.NewEphemeralKeyring()
+	s.Require().NoError(err)
+	utils.SetKeyring(keyring)
+	s.T().Setenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT", "15")
+	s.T().Setenv("JWT_REFRESH_KEY", "test-refresh-key")
+	s.T().Setenv("JWT_REFRESH_KEY_EXPIRE_HOURS_COUNT", "720")
+
+	s.refreshTokenRepository = new(mocks.RefreshTokenRepository)
+	s.redisClient = new(mockCache.RedisClient)
+	s.sessionRepository = new(mocks.SessionRepository)
+	s.roleRepository = new(mocks.RoleRepository)
+	s.service = services.NewAuthService(s.refreshTokenRepository, s.sessionRepository, s.roleRepository, s.redisClient)
+
+	// Customers have no roles unless a test grants them
+	s.roleRepository.On("GetRolesByUserID", authUserID).Return([]types.Role{}, nil).Maybe()
+
+	// Users have no other session unless a test signs them in elsewhere
+	s.sessionRepository.On("GetActiveByUserID", authUserID).Return([]*models.Session{}, nil).Maybe()
+	s.sessionRepository.On("Create", mock.AnythingOfType("*models.Session")).Return(nil).Maybe()
+	s.sessionRepository.On("Touch", mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.sessionRepository.On("Revoke", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+
+	// Cache writes are best effort and not asserted unless a test cares
+	s.redisClient.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.redisClient.On("Delete", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+func authDevice() models.DeviceInfo {
+	return models.DeviceInfo{DeviceID: authDeviceID, DeviceName: "Jane's iPhone", Platform: "ios", IPAddress: "203.0.113.7"}
+}
+
+func activeRefreshToken() *models.RefreshToken {
+	return &models.RefreshToken{
+		TokenHash: utils.HashRefreshToken(authRefreshToken),
+		FamilyID:  authFamilyID,
+		UserID:    authUserID,
+		DeviceID:  authDeviceID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// expectStoredToken expects the lookup of the token to miss the cache and hit the database
+func (s *AuthServiceTestSuite) expectStoredToken(token *models.RefreshToken) {
+	s.redisClient.On("Get", mock.Anything, "refresh_token:"+token.TokenHash).Return("", errors.New("cache miss")).Once()
+	s.refreshTokenRepository.On("GetByHash", token.TokenHash).Return(token, nil).Once()
+}
+
+// TestIssueTokens tests that only the hash of a new refresh token is stored, in a new family
+func (s *AuthServiceTestSuite) TestIssueTokens() {
+	var stored *models.RefreshToken
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.RefreshToken) }).
+		Return(nil).Once()
+
+	tokens, err := s.service.IssueTokens(authUserID, authDevice())
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), tokens.Access)
+	assert.Equal(s.T(), utils.HashRefreshToken(tokens.Refresh), stored.TokenHash)
+	assert.NotContains(s.T(), stored.TokenHash, tokens.Refresh)
+	assert.NotEmpty(s.T(), stored.FamilyID)
+	assert.Equal(s.T(), authUserID, stored.UserID)
+	assert.Equal(s.T(), authDeviceID, stored.DeviceID)
+	assert.Equal(s.T(), tokens.RefreshExpiresAt, stored.ExpiresAt)
+}
+
+// TestIssueTokensStartsSession tests that a sign-in stores a session of the device, shared by the refresh token
+// family and the sid claim of the access token
+func (s *AuthServiceTestSuite) TestIssueTokensStartsSession() {
+	var session *models.Session
+	s.sessionRepository.ExpectedCalls = nil
+	s.sessionRepository.On("GetActiveByUserID", authUserID).Return([]*models.Session{}, nil).Once()
+	s.sessionRepository.On("Create", mock.AnythingOfType("*models.Session")).
+		Run(func(args mock.Arguments) { session = args.Get(0).(*models.Session) }).
+		Return(nil).Once()
+	var stored *models.RefreshToken
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.RefreshToken) }).
+		Return(nil).Once()
+
+	tokens, err := s.service.IssueTokens(authUserID, authDevice())
+	s.Require().NoError(err)
+
+	assert.Equal(s.T(), authUserID, session.UserID)
+	assert.Equal(s.T(), authDeviceID, session.DeviceID)
+	assert.Equal(s.T(), "Jane's iPhone", session.DeviceName)
+	assert.Equal(s.T(), "ios", session.Platform)
+	assert.Equal(s.T(), "203.0.113.7", session.IPAddress)
+	assert.Equal(s.T(), session.SessionID, stored.FamilyID)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokens.Access, claims, utils.JWTKeyFunc)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), session.SessionID, claims["sid"])
+}
+
+// TestIssueTokensReplacesDeviceSession tests that signing in again on a device revokes its previous session only
+func (s *AuthServiceTestSuite) TestIssueTokensReplacesDeviceSession() {
+	s.sessionRepository.ExpectedCalls = nil
+	s.sessionRepository.On("GetActiveByUserID", authUserID).Return([]*models.Session{
+		{SessionID: "old-session", UserID: authUserID, DeviceID: authDeviceID},
+		{SessionID: "laptop-session", UserID: authUserID, DeviceID: "laptop"},
+	}, nil).Once()
+	s.sessionRepository.On("Revoke", "old-session", mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+	s.sessionRepository.On("Create", mock.AnythingOfType("*models.Session")).Return(nil).Once()
+	s.refreshTokenRepository.On("RevokeFamily", "old-session", mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil).Once()
+
+	_, err := s.service.IssueTokens(authUserID, authDevice())
+
+	assert.NoError(s.T(), err)
+	s.sessionRepository.AssertExpectations(s.T())
+	s.refreshTokenRepository.AssertExpectations(s.T())
+	s.redisClient.AssertCalled(s.T(), "Set", mock.Anything, "revoked_session:old-session", "1", 15*time.Minute)
+	s.redisClient.AssertNotCalled(s.T(), "Set", mock.Anything, "revoked_session:laptop-session", mock.Anything, mock.Anything)
+}
+
+// TestIssueTokensCarriesRoles tests that the access token of staff carries their roles and permissions
+func (s *AuthServiceTestSuite) TestIssueTokensCarriesRoles() {
+	s.roleRepository.ExpectedCalls = nil
+	s.roleRepository.On("GetRolesByUserID", authUserID).Return([]types.Role{types.RoleSupport, types.RoleMarketing}, nil).Once()
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil).Once()
+
+	tokens, err := s.service.IssueTokens(authUserID, authDevice())
+	s.Require().NoError(err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokens.Access, claims, utils.JWTKeyFunc)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []interface{}{"support", "marketing"}, claims["roles"])
+	assert.Equal(s.T(), []interface{}{"banners:manage", "cards:status", "users:read"}, claims["permissions"])
+}
+
+// TestIssueTokensWithoutRoles tests that customer tokens have no role claims
+func (s *AuthServiceTestSuite) TestIssueTokensWithoutRoles() {
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil).Once()
+
+	tokens, err := s.service.IssueTokens(authUserID, authDevice())
+	s.Require().NoError(err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokens.Access, claims, utils.JWTKeyFunc)
+	s.Require().NoError(err)
+	assert.NotContains(s.T(), claims, "roles")
+	assert.NotContains(s.T(), claims, "permissions")
+}
+
+// TestIssueTokensStartsNewFamilies tests that every sign-in gets its own family
+func (s *AuthServiceTestSuite) TestIssueTokensStartsNewFamilies() {
+	families := []string{}
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) { families = append(families, args.Get(0).(*models.RefreshToken).FamilyID) }).
+		Return(nil).Twice()
+
+	_, err := s.service.IssueTokens(authUserID, authDevice())
+	assert.NoError(s.T(), err)
+	_, err = s.service.IssueTokens(authUserID, authDevice())
+	assert.NoError(s.T(), err)
+
+	assert.Len(s.T(), families, 2)
+	assert.NotEqual(s.T(), families[0], families[1])
+}
+
+// TestRenewTokensRotates tests that renewing consumes the token and issues the next one of the same family
+func (s *AuthServiceTestSuite) TestRenewTokensRotates() {
+	token := activeRefreshToken()
+	s.expectStoredToken(token)
+	s.refreshTokenRepository.On("MarkUsed", token.TokenHash, mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+	var next *models.RefreshToken
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) { next = args.Get(0).(*models.RefreshToken) }).
+		Return(nil).Once()
+
+	tokens, err := s.service.RenewTokens(authUserID, authDeviceID, authRefreshToken)
+
+	assert.NoError(s.T(), err)
+	assert.NotEqual(s.T(), authRefreshToken, tokens.Refresh)
+	assert.Equal(s.T(), authFamilyID, next.FamilyID)
+	assert.Equal(s.T(), utils.HashRefreshToken(tokens.Refresh), next.TokenHash)
+	s.sessionRepository.AssertCalled(s.T(), "Touch", authFamilyID, mock.AnythingOfType("time.Time"))
+	s.redisClient.AssertCalled(s.T(), "Delete", mock.Anything, "refresh_token:"+token.TokenHash)
+	s.refreshTokenRepository.AssertNotCalled(s.T(), "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+// TestRenewTokensFromCache tests that a cached token is renewed without reading it from the database
+func (s *AuthServiceTestSuite) TestRenewTokensFromCache() {
+	token := activeRefreshToken()
+	cachedData, _ := json.Marshal(token)
+	s.redisClient.On("Get", mock.Anything, "refresh_token:"+token.TokenHash).Return(string(cachedData), nil).Once()
+	s.refreshTokenRepository.On("MarkUsed", token.TokenHash, mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+	s.refreshTokenRepository.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil).Once()
+
+	_, err := s.service.RenewTokens(authUserID, authDeviceID, authRefreshToken)
+
+	assert.NoError(s.T(), err)
+	s.refreshTokenRepository.AssertNotCalled(s.T(), "GetByHash", mock.Anything)
+}
+
+// TestRenewTokensReuseRevokesFamily tests that presenting a used token revokes every token of its family
+func (s *AuthServiceTestSuite) TestRenewTokensReuseRevokesFamily() {
+	token := activeRefreshToken()
+	usedAt := time.Now().Add(-time.Minute)
+	token.UsedAt = &usedAt
+	s.expectStoredToken(token)
+	s.refreshTokenRepository.On("RevokeFamily", authFamilyID, mock.AnythingOfType("time.Time")).Return(int64(2), nil).Once()
+
+	tokens, err := s.service.RenewTokens(authUserID, authDeviceID, authRefreshToken)
+
+	assert.ErrorIs(s.T(), err, services.ErrRefreshTokenReused)
+	assert.Nil(s.T(), tokens)
+	s.refreshTokenRepository.AssertExpectations(s.T())
+	s.refreshTokenRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// TestRenewTokensConcurrentRenewal tests that the loser of two renewals with the same token is treated as reuse
+func (s *AuthServiceTestSuite) TestRenewTokensConcurrentRenewal() {
+	token := activeRefreshToken()
+	usedAt := time.Now()
+	used := *token
+	used.UsedAt = &usedAt
+
+	// The cached copy is stale, the database already has the token used
+	cachedData, _ := json.Marshal(token)
+	s.redisClient.On("Get", mock.Anything, "refresh_token:"+token.TokenHash).Return(string(cachedData), nil).Once()
+	s.refreshTokenRepository.On("MarkUsed", token.TokenHash, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+	s.refreshTokenRepository.On("GetByHash", token.TokenHash).Return(&used, nil).Once()
+	s.refreshTokenRepository.On("RevokeFamily", authFamilyID, mock.AnythingOfType("time.Time")).Return(int64(2), nil).Once()
+
+	_, err := s.service.RenewTokens(authUserID, authDeviceID, authRefreshToken)
+
+	assert.ErrorIs(s.T(), err, services.ErrRefreshTokenReused)
+	s.refreshTokenRepository.AssertExpectations(s.T())
+	s.refreshTokenRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// TestRenewTokensRejected tests the tokens that cannot be renewed without revoking their family
+func (s *AuthServiceTestSuite) TestRenewTokensRejected() {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name        string
+		userID      string
+		deviceID    string
+		modify      func(token *models.RefreshToken)
+		expectedErr error
+	}{
+		{
+			name:        "Token of another user",
+			userID:      "other-user",
+			deviceID:    authDeviceID,
+			expectedErr: services.ErrInvalidRefreshToken,
+		},
+		{
+			name:        "Token of another device",
+			userID:      authUserID,
+			deviceID:    "other-device",
+			expectedErr: services.ErrInvalidRefreshToken,
+		},
+		{
+			name:        "Revoked token",
+			userID:      authUserID,
+			deviceID:    authDeviceID,
+			modify:      func(token *models.RefreshToken) { token.RevokedAt = &revokedAt },
+			expectedErr: services.ErrInvalidRefreshToken,
+		},
+		{
+			name:        "Expired token",
+			userID:      authUserID,
+			deviceID:    authDeviceID,
+			modify:      func(token *models.RefreshToken) { token.ExpiresAt = time.Now().Add(-time.Minute) },
+			expectedErr: services.ErrRefreshTokenExpired,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			token := activeRefreshToken()
+			if tc.modify != nil {
+				tc.modify(token)
+			}
+			s.expectStoredToken(token)
+
+			tokens, err := s.service.RenewTokens(tc.userID, tc.deviceID, authRefreshToken)
+
+			assert.ErrorIs(s.T(), err, tc.expectedErr)
+			assert.Nil(s.T(), tokens)
+			s.refreshTokenRepository.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+			s.refreshTokenRepository.AssertNotCalled(s.T(), "RevokeFamily", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestRenewTokensUnknownToken tests that a token that was never issued, e.g. a forged one, is rejected
+func (s *AuthServiceTestSuite) TestRenewTokensUnknownToken() {
+	tokenHash := utils.HashRefreshToken(authRefreshToken)
+	s.redisClient.On("Get", mock.Anything, "refresh_token:"+tokenHash).Return("", errors.New("cache miss")).Once()
+	s.refreshTokenRepository.On("GetByHash", tokenHash).Return(nil, sql.ErrNoRows).Once()
+
+	tokens, err := s.service.RenewTokens(authUserID, authDeviceID, authRefreshToken)
+
+	assert.ErrorIs(s.T(), err, services.ErrInvalidRefreshToken)
+	assert.Nil(s.T(), tokens)
+}
+
+// TestLogout tests that signing out revokes the family of the token
+func (s *AuthServiceTestSuite) TestLogout() {
+	token := activeRefreshToken()
+	s.expectStoredToken(token)
+	s.refreshTokenRepository.On("RevokeFamily", authFamilyID, mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+
+	err := s.service.Logout(authUserID, authRefreshToken)
+
+	assert.NoError(s.T(), err)
+	s.refreshTokenRepository.AssertExpectations(s.T())
+	s.redisClient.AssertCalled(s.T(), "Delete", mock.Anything, "refresh_token:"+token.TokenHash)
+	s.sessionRepository.AssertCalled(s.T(), "Revoke", authFamilyID, mock.AnythingOfType("time.Time"))
+	s.redisClient.AssertCalled(s.T(), "Set", mock.Anything, "revoked_session:"+authFamilyID, "1", 15*time.Minute)
+}
+
+// TestLogoutTokenOfAnotherUser tests that a user cannot sign out the sessions of another user
+func (s *AuthServiceTestSuite) TestLogoutTokenOfAnotherUser() {
+	s.expectStoredToken(activeRefreshToken())
+
+	err := s.service.Logout("other-user", authRefreshToken)
+
+	assert.ErrorIs(s.T(), err, services.ErrInvalidRefreshToken)
+	s.refreshTokenRepository.AssertNotCalled(s.T(), "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+// TestLogoutAll tests that every token and session of the user is revoked
+func (s *AuthServiceTestSuite) TestLogoutAll() {
+	s.sessionRepository.ExpectedCalls = nil
+	s.sessionRepository.On("GetActiveByUserID", authUserID).Return([]*models.Session{
+		{SessionID: "phone-session", UserID: authUserID},
+		{SessionID: "laptop-session", UserID: authUserID},
+	}, nil).Once()
+	s.sessionRepository.On("Revoke", "phone-session", mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+	s.sessionRepository.On("Revoke", "laptop-session", mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+	s.refreshTokenRepository.On("RevokeByUserID", authUserID, mock.AnythingOfType("time.Time")).Return(int64(3), nil).Once()
+
+	revoked, err := s.service.LogoutAll(authUserID)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(3), revoked)
+	s.sessionRepository.AssertExpectations(s.T())
+	s.redisClient.AssertCalled(s.T(), "Set", mock.Anything, "revoked_session:phone-session", "1", 15*time.Minute)
+	s.redisClient.AssertCalled(s.T(), "Set", mock.Anything, "revoked_session:laptop-session", "1", 15*time.Minute)
+}
+
+// TestListSessions tests that the session of the request is marked current
+func (s *AuthServiceTestSuite) TestListSessions() {
+	s.sessionRepository.ExpectedCalls = nil
+	s.sessionRepository.On("GetActiveByUserID", authUserID).Return([]*models.Session{
+		{SessionID: "phone-session", UserID: authUserID},
+		{SessionID: "laptop-session", UserID: authUserID},
+	}, nil).Once()
+
+	sessions, err := s.service.ListSessions(authUserID, "laptop-session")
+
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2)
+	assert.False(s.T(), sessions[0].Current)
+	assert.True(s.T(), sessions[1].Current)
+}
+
+// TestRevokeSession tests that a user can sign out their own sessions only
+func (s *AuthServiceTestSuite) TestRevokeSession() {
+	revokedAt := time.Now().Add(-time.Hour)
+	s.sessionRepository.On("GetByID", "phone-session").Return(&models.Session{SessionID: "phone-session", UserID: authUserID}, nil)
+	s.sessionRepository.On("GetByID", "victim-session").Return(&models.Session{SessionID: "victim-session", UserID: "other-user"}, nil)
+	s.sessionRepository.On("GetByID", "revoked-session").Return(&models.Session{SessionID: "revoked-session", UserID: authUserID, RevokedAt: &revokedAt}, nil)
+	s.sessionRepository.On("GetByID", "missing-session").Return(nil, sql.ErrNoRows)
+	s.refreshTokenRepository.On("RevokeFamily", "phone-session", mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+
+	assert.NoError(s.T(), s.service.RevokeSession(authUserID, "phone-session"))
+	s.redisClient.AssertCalled(s.T(), "Set", mock.Anything, "revoked_session:phone-session", "1", 15*time.Minute)
+
+	for _, sessionID := range []string{"victim-session", "revoked-session", "missing-session"} {
+		assert.ErrorIs(s.T(), s.service.RevokeSession(authUserID, sessionID), services.ErrSessionNotFound, sessionID)
+	}
+	s.refreshTokenRepository.AssertExpectations(s.T())
+}
+
+// TestIsSessionRevoked tests the revocation list and the fallback to the session row when Redis is unavailable
+func (s *AuthServiceTestSuite) TestIsSessionRevoked() {
+	revokedAt := time.Now().Add(-time.Minute)
+	s.redisClient.On("Get", mock.Anything, "revoked_session:revoked-session").Return("1", nil).Once()
+	s.redisClient.On("Get", mock.Anything, "revoked_session:active-session").Return("", types.ErrCacheMiss).Once()
+	s.redisClient.On("Get", mock.Anything, "revoked_session:db-revoked-session").Return("", errors.New("connection refused")).Once()
+	s.sessionRepository.On("GetByID", "db-revoked-session").Return(&models.Session{SessionID: "db-revoked-session", RevokedAt: &revokedAt}, nil).Once()
+	s.redisClient.On("Get", mock.Anything, "revoked_session:broken-session").Return("", errors.New("connection refused")).Once()
+	s.sessionRepository.On("GetByID", "broken-session").Return(nil, errors.New("database error")).Once()
+
+	revoked, err := s.service.IsSessionRevoked("revoked-session")
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	revoked, err = s.service.IsSessionRevoked("active-session")
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	revoked, err = s.service.IsSessionRevoked("db-revoked-session")
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	_, err = s.service.IsSessionRevoked("broken-session")
+	assert.Error(s.T(), err)
+}
+
+// TestAuthServiceSuite runs the test suite
+func TestAuthServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceTestSuite))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -208,6 +209,54 @@ func (s *BannerServiceTestSuite) TestGetBannersByUserID() {
 	}
 }
 
+// TestCreateBanner tests the CreateBanner function
+func (s *BannerServiceTestSuite) TestCreateBanner() {
+	var created *models.Banner
+	s.bannerRepository.On("CreateBanner", mock.AnythingOfType("*models.Banner")).
+		Run(func(args mock.Arguments) { created = args.Get(0).(*models.Banner) }).
+		Return(nil).Once()
+	s.bannerRepository.On("GetBannerByID", mock.AnythingOfType("string")).
+		Return(func(bannerID string) *models.Banner { return created }, nil).Once()
+
+	banner, err := s.service.CreateBanner("user-123", "Cashback", "5% back on groceries", "https://example.com/cashback.png")
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), banner.BannerID)
+	assert.Equal(s.T(), "user-123", banner.UserID)
+	assert.Equal(s.T(), "Cashback", banner.Title)
+	s.bannerRepository.AssertExpectations(s.T())
+}
+
+// TestUpdateBanner tests that UpdateBanner only changes the given values
+func (s *BannerServiceTestSuite) TestUpdateBanner() {
+	s.bannerRepository.On("GetBannerByID", "banner-123").
+		Return(&models.Banner{BannerID: "banner-123", UserID: "user-123", Title: "Old", Description: "Kept", Image: "old.png"}, nil).Once()
+	s.bannerRepository.On("UpdateBanner", mock.MatchedBy(func(banner *models.Banner) bool {
+		return banner.Title == "New" && banner.Description == "Kept" && banner.Image == "old.png"
+	})).Return(nil).Once()
+	s.bannerRepository.On("GetBannerByID", "missing-banner").Return(nil, nil).Once()
+
+	banner, err := s.service.UpdateBanner("banner-123", "New", "", "")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "New", banner.Title)
+
+	_, err = s.service.UpdateBanner("missing-banner", "New", "", "")
+	assert.ErrorIs(s.T(), err, services.ErrBannerNotFound)
+
+	s.bannerRepository.AssertExpectations(s.T())
+}
+
+// TestDeleteBanner tests the DeleteBanner function
+func (s *BannerServiceTestSuite) TestDeleteBanner() {
+	s.bannerRepository.On("DeleteBanner", "banner-123").Return(true, nil).Once()
+	s.bannerRepository.On("DeleteBanner", "missing-banner").Return(false, nil).Once()
+	s.bannerRepository.On("DeleteBanner", "broken-banner").Return(false, errors.New("database error")).Once()
+
+	assert.NoError(s.T(), s.service.DeleteBanner("banner-123"))
+	assert.ErrorIs(s.T(), s.service.DeleteBanner("missing-banner"), services.ErrBannerNotFound)
+	assert.EqualError(s.T(), s.service.DeleteBanner("broken-banner"), "database error")
+}
+
 // TestMain runs the test suite
 func TestBannerService(t *testing.T) {
 	suite.Run(t, new(BannerServiceTestSuite))
@@ -0,0 +1,249 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ChallengeServiceTestSuite is a test suite for ChallengeService
+type ChallengeServiceTestSuite struct {
+	suite.Suite
+	challengeRepository *mocks.ChallengeRepository
+	userRepository      *mocks.UserRepository
+	totpService         *mockServices.TOTPService
+	service             services.ChallengeService
+	user                *models.User
+}
+
+const (
+	challengeUserID = "user-123"
+	challengeID     = "challenge-123"
+	challengePIN    = "135790"
+)
+
+// SetupSuite hashes the PIN once, bcrypt is slow
+func (s *ChallengeServiceTestSuite) SetupSuite() {
+	pinHash, err := utils.HashPIN(challengePIN)
+	s.Require().NoError(err)
+	s.user = &models.User{UserID: challengeUserID, PIN: pinHash}
+}
+
+// SetupTest sets up the test suite
+func (s *ChallengeServiceTestSuite) SetupTest() {
+	s.challengeRepository = new(mocks.ChallengeRepository)
+	s.userRepository = new(mocks.UserRepository)
+	s.totpService = new(mockServices.TOTPService)
+	s.service = services.NewChallengeService(s.challengeRepository, s.userRepository, s.totpService, map[string]types.Money{
+		"THB": types.NewMoney(5000000, "THB"),
+	})
+}
+
+func pendingChallenge() *models.Challenge {
+	return &models.Challenge{
+		ChallengeID: challengeID,
+		UserID:      challengeUserID,
+		Action:      models.ChallengeActionTransfer,
+		Payload:     []byte(`{"from_account_id":"account-1","to_account_id":"account-2","amount":{"amount":"60000.00","currency":"THB"}}`),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+}
+
+// TestRequiresStepUp tests the amounts that need a second factor
+func (s *ChallengeServiceTestSuite) TestRequiresStepUp() {
+	assert.False(s.T(), s.service.RequiresStepUp(types.NewMoney(4999999, "THB")))
+	assert.False(s.T(), s.service.RequiresStepUp(types.NewMoney(5000000, "THB")))
+	assert.True(s.T(), s.service.RequiresStepUp(types.NewMoney(5000001, "THB")))
+	assert.True(s.T(), s.service.RequiresStepUp(types.NewMoney(100, "GBP")), "currencies without a threshold always need it")
+}
+
+// TestCreateTransferChallenge tests that the transfer is held in the challenge until it expires
+func (s *ChallengeServiceTestSuite) TestCreateTransferChallenge() {
+	transfer := &models.PendingTransfer{FromAccountID: "account-1", ToAccountID: "account-2", Amount: types.NewMoney(6000000, "THB")}
+	var stored *models.Challenge
+	s.challengeRepository.On("Create", mock.AnythingOfType("*models.Challenge")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.Challenge) }).Return(nil).Once()
+
+	challenge, err := s.service.CreateTransferChallenge(challengeUserID, transfer)
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), challenge.ChallengeID)
+	assert.Equal(s.T(), models.ChallengeActionTransfer, stored.Action)
+	assert.WithinDuration(s.T(), time.Now().Add(5*time.Minute), stored.ExpiresAt, time.Second)
+	held, err := stored.Transfer()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), transfer, held)
+}
+
+// TestCreateScheduleChallenges tests that a schedule, or a change of one, is held in the challenge
+func (s *ChallengeServiceTestSuite) TestCreateScheduleChallenges() {
+	var stored []*models.Challenge
+	s.challengeRepository.On("Create", mock.AnythingOfType("*models.Challenge")).
+		Run(func(args mock.Arguments) { stored = append(stored, args.Get(0).(*models.Challenge)) }).Return(nil).Twice()
+
+	schedule := &models.ScheduledTransfer{UserID: challengeUserID, FromAccountID: "account-1", ToAccountID: "account-2", Amount: types.NewMoney(6000000, "THB"), Frequency: models.FrequencyMonthly}
+	_, err := s.service.CreateScheduleChallenge(challengeUserID, schedule)
+	assert.NoError(s.T(), err)
+	amount := types.NewMoney(7000000, "THB")
+	update := &models.PendingScheduleUpdate{AccountID: "account-1", ScheduleID: "schedule-1", Update: models.ScheduledTransferUpdate{Amount: &amount}}
+	_, err = s.service.CreateScheduleUpdateChallenge(challengeUserID, update)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), models.ChallengeActionSchedule, stored[0].Action)
+	heldSchedule, err := stored[0].Schedule()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), schedule.Amount, heldSchedule.Amount)
+	assert.Equal(s.T(), schedule.Frequency, heldSchedule.Frequency)
+
+	assert.Equal(s.T(), models.ChallengeActionScheduleUpdate, stored[1].Action)
+	heldUpdate, err := stored[1].ScheduleUpdate()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), update, heldUpdate)
+}
+
+// TestConfirmWithPin tests that the right PIN consumes the challenge
+func (s *ChallengeServiceTestSuite) TestConfirmWithPin() {
+	s.challengeRepository.On("GetByID", challengeID).Return(pendingChallenge(), nil).Once()
+	s.userRepository.On("GetByID", challengeUserID).Return(s.user, nil).Once()
+	s.challengeRepository.On("MarkConfirmed", challengeID, mock.AnythingOfType("time.Time"), 3).Return(true, nil).Once()
+
+	challenge, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodPIN, challengePIN)
+
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), challenge.ConfirmedAt)
+	s.challengeRepository.AssertExpectations(s.T())
+}
+
+// TestConfirmWithTOTP tests that an authenticator code is checked by the TOTP service
+func (s *ChallengeServiceTestSuite) TestConfirmWithTOTP() {
+	s.challengeRepository.On("GetByID", challengeID).Return(pendingChallenge(), nil).Once()
+	s.totpService.On("Verify", challengeUserID, "654321").Return(nil).Once()
+	s.challengeRepository.On("MarkConfirmed", challengeID, mock.AnythingOfType("time.Time"), 3).Return(true, nil).Once()
+
+	_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodTOTP, "654321")
+
+	assert.NoError(s.T(), err)
+	s.userRepository.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+}
+
+// TestConfirmWrongFactor tests that wrong answers count towards the attempts of the challenge
+func (s *ChallengeServiceTestSuite) TestConfirmWrongFactor() {
+	s.Run("Wrong PIN", func() {
+		s.SetupTest()
+		s.challengeRepository.On("GetByID", challengeID).Return(pendingChallenge(), nil).Once()
+		s.userRepository.On("GetByID", challengeUserID).Return(s.user, nil).Once()
+		s.challengeRepository.On("IncrementAttempts", challengeID).Return(nil).Once()
+
+		_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodPIN, "000000")
+
+		assert.ErrorIs(s.T(), err, services.ErrIncorrectPin)
+		s.challengeRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("Wrong authenticator code", func() {
+		s.SetupTest()
+		s.challengeRepository.On("GetByID", challengeID).Return(pendingChallenge(), nil).Once()
+		s.totpService.On("Verify", challengeUserID, "000000").Return(services.ErrInvalidTOTP).Once()
+		s.challengeRepository.On("IncrementAttempts", challengeID).Return(nil).Once()
+
+		_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodTOTP, "000000")
+
+		assert.ErrorIs(s.T(), err, services.ErrInvalidTOTP)
+		s.challengeRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("No authenticator enabled", func() {
+		s.SetupTest()
+		s.challengeRepository.On("GetByID", challengeID).Return(pendingChallenge(), nil).Once()
+		s.totpService.On("Verify", challengeUserID, "000000").Return(services.ErrTOTPNotEnabled).Once()
+
+		_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodTOTP, "000000")
+
+		assert.ErrorIs(s.T(), err, services.ErrTOTPNotEnabled)
+		s.challengeRepository.AssertNotCalled(s.T(), "IncrementAttempts", mock.Anything)
+	})
+}
+
+// TestConfirmUnusableChallenge tests the challenges that cannot be confirmed anymore
+func (s *ChallengeServiceTestSuite) TestConfirmUnusableChallenge() {
+	confirmedAt := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name        string
+		challenge   func() *models.Challenge
+		expectedErr error
+	}{
+		{
+			name:        "Challenge of another user",
+			challenge:   func() *models.Challenge { c := pendingChallenge(); c.UserID = "other-user"; return c },
+			expectedErr: services.ErrChallengeNotFound,
+		},
+		{
+			name:        "Already confirmed",
+			challenge:   func() *models.Challenge { c := pendingChallenge(); c.ConfirmedAt = &confirmedAt; return c },
+			expectedErr: services.ErrChallengeUsed,
+		},
+		{
+			name: "Expired",
+			challenge: func() *models.Challenge {
+				c := pendingChallenge()
+				c.ExpiresAt = time.Now().Add(-time.Second)
+				return c
+			},
+			expectedErr: services.ErrChallengeExpired,
+		},
+		{
+			name:        "Too many attempts",
+			challenge:   func() *models.Challenge { c := pendingChallenge(); c.Attempts = 3; return c },
+			expectedErr: services.ErrChallengeFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.challengeRepository.On("GetByID", challengeID).Return(tc.challenge(), nil).Once()
+
+			_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodPIN, challengePIN)
+
+			assert.ErrorIs(s.T(), err, tc.expectedErr)
+			s.userRepository.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+			s.challengeRepository.AssertNotCalled(s.T(), "MarkConfirmed", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	s.Run("Unknown challenge", func() {
+		s.SetupTest()
+		s.challengeRepository.On("GetByID", challengeID).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodPIN, challengePIN)
+
+		assert.ErrorIs(s.T(), err, services.ErrChallengeNotFound)
+	})
+}
+
+// TestConfirmConcurrently tests that a challenge confirmed by a concurrent request is not confirmed again
+func (s *ChallengeServiceTestSuite) TestConfirmConcurrently() {
+	s.challengeRepository.On("GetByID", challengeID).Return(pendingChallenge(), nil).Once()
+	s.userRepository.On("GetByID", challengeUserID).Return(s.user, nil).Once()
+	s.challengeRepository.On("MarkConfirmed", challengeID, mock.AnythingOfType("time.Time"), 3).Return(false, nil).Once()
+
+	_, err := s.service.Confirm(challengeUserID, challengeID, models.ChallengeMethodPIN, challengePIN)
+
+	assert.ErrorIs(s.T(), err, services.ErrChallengeUsed)
+}
+
+// TestChallengeServiceSuite runs the test suite
+func TestChallengeServiceSuite(t *testing.T) {
+	suite.Run(t, new(ChallengeServiceTestSuite))
+}
@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
 	"backend-developer-assignment/app/services"
 	mocks "backend-developer-assignment/pkg/mocks/repositories"
 	"errors"
@@ -18,13 +19,23 @@ import (
 type DebitCardServiceTestSuite struct {
 	suite.Suite
 	debitCardRepository *mocks.DebitCardRepository
+	outboxRepository    *mocks.OutboxRepository
+	txProvider          *mocks.TxProvider
 	service             services.DebitCardService
 }
 
 // SetupTest runs before each test
 func (s *DebitCardServiceTestSuite) SetupTest() {
 	s.debitCardRepository = new(mocks.DebitCardRepository)
-	s.service = services.NewDebitCardService(s.debitCardRepository)
+	s.outboxRepository = new(mocks.OutboxRepository)
+	s.txProvider = new(mocks.TxProvider)
+	s.service = services.NewDebitCardService(s.debitCardRepository, s.txProvider)
+
+	// Transactions run against the suite's repository mocks
+	s.txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+		Return(func(txFunc func(repositories.Adapters) error) error {
+			return txFunc(repositories.Adapters{DebitCardRepository: s.debitCardRepository, OutboxRepository: s.outboxRepository})
+		}).Maybe()
 }
 
 // TestGetCardByID tests the GetCardByID function
@@ -362,7 +373,7 @@ func (s *DebitCardServiceTestSuite) TestCreateCardWithDetails() {
 		s.Run(tc.name, func() {
 			// Reset mocks
 			s.debitCardRepository = new(mocks.DebitCardRepository)
-			s.service = services.NewDebitCardService(s.debitCardRepository)
+			s.service = services.NewDebitCardService(s.debitCardRepository, s.txProvider)
 
 			// Save the original CardID for later comparison
 			originalCardID := tc.cardWithDetails.CardID
@@ -490,7 +501,7 @@ func (s *DebitCardServiceTestSuite) TestUpdateCard() {
 		s.Run(tc.name, func() {
 			// Reset mocks
 			s.debitCardRepository = new(mocks.DebitCardRepository)
-			s.service = services.NewDebitCardService(s.debitCardRepository)
+			s.service = services.NewDebitCardService(s.debitCardRepository, s.txProvider)
 
 			// Mock the UpdateCardByID method
 			s.debitCardRepository.On("UpdateCardByID", tc.card.CardID, tc.card.UserID, mock.AnythingOfType("func(*models.DebitCardWithDetails) (bool, error)")).
@@ -551,35 +562,50 @@ func (s *DebitCardServiceTestSuite) TestDeleteCard() {
 	testCases := []struct {
 		name          string
 		cardID        string
-		mockError     error
+		getError      error
+		updateError   error
 		expectedError error
 	}{
 		{
 			name:          "Success - Card Deleted",
 			cardID:        "card-123",
-			mockError:     nil,
 			expectedError: nil,
 		},
 		{
 			name:          "Failure - Card Not Found",
 			cardID:        "nonexistent-card",
-			mockError:     errors.New("card not found"),
+			getError:      errors.New("card not found"),
 			expectedError: errors.New("card not found"),
 		},
 		{
 			name:          "Failure - Database Error",
 			cardID:        "card-123",
-			mockError:     errors.New("database connection failed"),
+			updateError:   errors.New("database connection failed"),
 			expectedError: errors.New("database connection failed"),
 		},
 	}
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
-			// Mock the repository method with the expected DebitCardStatus object
-			s.debitCardRepository.On("UpdateCardStatus", mock.MatchedBy(func(status *models.DebitCardStatus) bool {
-				return status.CardID == tc.cardID && status.Status == string(models.CardStatusInactive)
-			})).Return(tc.mockError).Once()
+			var card *models.DebitCardWithDetails
+			if tc.getError == nil {
+				card = &models.DebitCardWithDetails{CardID: tc.cardID, UserID: "user-123", Status: string(models.CardStatusActive)}
+			}
+			s.debitCardRepository.On("GetCardWithDetailByID", tc.cardID).Return(card, tc.getError).Once()
+
+			if tc.getError == nil {
+				// Mock the repository method with the expected DebitCardStatus object
+				s.debitCardRepository.On("UpdateCardStatus", mock.MatchedBy(func(status *models.DebitCardStatus) bool {
+					return status.CardID == tc.cardID && status.Status == string(models.CardStatusInactive)
+				})).Return(tc.updateError).Once()
+			}
+
+			if tc.expectedError == nil {
+				s.outboxRepository.On("Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+					return event.EventType == models.EventCardStatusChanged && event.AggregateID == tc.cardID &&
+						string(event.Payload) == `{"card_id":"card-123","user_id":"user-123","status":"inactive","previous_status":"active"}`
+				})).Return(nil).Once()
+			}
 
 			// Call the service method
 			err := s.service.DeleteCard(tc.cardID)
@@ -594,10 +620,44 @@ func (s *DebitCardServiceTestSuite) TestDeleteCard() {
 
 			// Verify expected method calls
 			s.debitCardRepository.AssertExpectations(s.T())
+			s.outboxRepository.AssertExpectations(s.T())
 		})
 	}
 }
 
+// TestUpdateCardStatus tests the UpdateCardStatus function
+func (s *DebitCardServiceTestSuite) TestUpdateCardStatus() {
+	s.debitCardRepository.On("GetCardWithDetailByID", "card-123").
+		Return(&models.DebitCardWithDetails{CardID: "card-123", UserID: "user-123", Status: string(models.CardStatusBlocked)}, nil).Once()
+	s.debitCardRepository.On("UpdateCardStatus", mock.MatchedBy(func(status *models.DebitCardStatus) bool {
+		return status.CardID == "card-123" && status.Status == string(models.CardStatusActive)
+	})).Return(nil).Once()
+	s.outboxRepository.On("Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+		return event.EventType == models.EventCardStatusChanged && event.AggregateType == models.AggregateDebitCard &&
+			event.AggregateID == "card-123" && event.EventID != "" &&
+			string(event.Payload) == `{"card_id":"card-123","user_id":"user-123","status":"active","previous_status":"blocked"}`
+	})).Return(nil).Once()
+
+	card, err := s.service.UpdateCardStatus("card-123", models.CardStatusActive)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), string(models.CardStatusActive), card.Status)
+
+	_, err = s.service.UpdateCardStatus("card-123", models.CardStatus("stolen"))
+	assert.ErrorIs(s.T(), err, services.ErrInvalidCardStatus)
+
+	// The status change fails, and is rolled back, when its event cannot be recorded
+	s.debitCardRepository.On("GetCardWithDetailByID", "card-123").
+		Return(&models.DebitCardWithDetails{CardID: "card-123", Status: string(models.CardStatusActive)}, nil).Once()
+	s.debitCardRepository.On("UpdateCardStatus", mock.Anything).Return(nil).Once()
+	s.outboxRepository.On("Create", mock.Anything).Return(errors.New("database error")).Once()
+	_, err = s.service.UpdateCardStatus("card-123", models.CardStatusBlocked)
+	assert.Error(s.T(), err)
+
+	s.debitCardRepository.AssertExpectations(s.T())
+	s.outboxRepository.AssertExpectations(s.T())
+}
+
 // Run the test suite
 func TestDebitCardServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(DebitCardServiceTestSuite))
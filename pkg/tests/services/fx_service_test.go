@@ -0,0 +1,399 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// FXServiceTestSuite is a test suite for FXService, the rate providers and transfers at a quoted rate
+type FXServiceTestSuite struct {
+	suite.Suite
+	fxRepository *mocks.FXRepository
+	rateProvider *mockServices.RateProvider
+	service      services.FXService
+}
+
+// SetupTest sets up the test suite
+func (s *FXServiceTestSuite) SetupTest() {
+	s.fxRepository = new(mocks.FXRepository)
+	s.rateProvider = new(mockServices.RateProvider)
+	s.service = services.NewFXService(s.fxRepository, s.rateProvider)
+}
+
+// TestCreateQuote tests the CreateQuote function
+func (s *FXServiceTestSuite) TestCreateQuote() {
+	dbErr := errors.New("database error")
+
+	testCases := []struct {
+		name           string
+		toCurrency     string
+		amount         types.Money
+		rate           string
+		rateErr        error
+		createErr      error
+		expectedError  error
+		expectedTarget types.Money
+	}{
+		{
+			name:           "Success",
+			toCurrency:     "USD",
+			amount:         types.NewMoney(100000, "THB"),
+			rate:           "0.0274",
+			expectedTarget: types.NewMoney(2740, "USD"),
+		},
+		{
+			name:          "Same currency",
+			toCurrency:    "THB",
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrInvalidCurrencyPair,
+		},
+		{
+			name:          "Invalid target currency",
+			toCurrency:    "DOLLAR",
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrInvalidCurrencyPair,
+		},
+		{
+			name:          "Amount not positive",
+			toCurrency:    "USD",
+			amount:        types.NewMoney(0, "THB"),
+			expectedError: services.ErrInvalidAmount,
+		},
+		{
+			name:          "Converts to nothing",
+			toCurrency:    "USD",
+			amount:        types.NewMoney(1, "THB"),
+			rate:          "0.0274",
+			expectedError: services.ErrInvalidAmount,
+		},
+		{
+			name:          "Rate unavailable",
+			toCurrency:    "USD",
+			amount:        types.NewMoney(100000, "THB"),
+			rateErr:       services.ErrRateUnavailable,
+			expectedError: services.ErrRateUnavailable,
+		},
+		{
+			name:          "Repository error",
+			toCurrency:    "USD",
+			amount:        types.NewMoney(100000, "THB"),
+			rate:          "0.0274",
+			createErr:     dbErr,
+			expectedError: dbErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			if tc.rate != "" || tc.rateErr != nil {
+				rate := types.Rate{}
+				if tc.rate != "" {
+					rate = types.MustParseRate(tc.rate)
+				}
+				s.rateProvider.On("GetRate", tc.amount.Currency, tc.toCurrency).Return(rate, tc.rateErr).Once()
+			}
+			s.fxRepository.On("CreateQuote", mock.AnythingOfType("*models.FXQuote")).Return(tc.createErr).Maybe()
+
+			quote, err := s.service.CreateQuote("user-123", tc.toCurrency, tc.amount)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(s.T(), err, tc.expectedError)
+				assert.Nil(s.T(), quote)
+				return
+			}
+
+			assert.NoError(s.T(), err)
+			assert.NotEmpty(s.T(), quote.QuoteID)
+			assert.Equal(s.T(), "user-123", quote.UserID)
+			assert.Equal(s.T(), "THB", quote.FromCurrency)
+			assert.Equal(s.T(), "USD", quote.ToCurrency)
+			assert.Equal(s.T(), tc.amount, quote.SourceAmount)
+			assert.Equal(s.T(), tc.expectedTarget, quote.TargetAmount)
+			assert.True(s.T(), quote.ExpiresAt.After(time.Now()))
+			s.rateProvider.AssertExpectations(s.T())
+			s.fxRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestDBRateProvider tests that missing pairs fall back to the inverse of the opposite pair
+func (s *FXServiceTestSuite) TestDBRateProvider() {
+	provider := services.NewDBRateProvider(s.fxRepository)
+	s.fxRepository.On("GetRate", "USD", "THB").Return(types.MustParseRate("36.5"), nil)
+	s.fxRepository.On("GetRate", "THB", "USD").Return(types.Rate{}, sql.ErrNoRows)
+	s.fxRepository.On("GetRate", "THB", "EUR").Return(types.Rate{}, sql.ErrNoRows)
+	s.fxRepository.On("GetRate", "EUR", "THB").Return(types.Rate{}, sql.ErrNoRows)
+	s.fxRepository.On("GetRate", "GBP", "THB").Return(types.Rate{}, errors.New("database error"))
+
+	rate, err := provider.GetRate("USD", "THB")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "36.50000000", rate.String())
+
+	rate, err = provider.GetRate("THB", "USD")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "0.02739726", rate.String())
+
+	_, err = provider.GetRate("THB", "EUR")
+	assert.ErrorIs(s.T(), err, services.ErrRateUnavailable)
+
+	_, err = provider.GetRate("GBP", "THB")
+	assert.EqualError(s.T(), err, "database error")
+}
+
+// TestFileRateProvider tests loading rates from a JSON file
+func (s *FXServiceTestSuite) TestFileRateProvider() {
+	dir := s.T().TempDir()
+	path := filepath.Join(dir, "rates.json")
+	assert.NoError(s.T(), os.WriteFile(path, []byte(`{"usd/thb": "36.50", "EUR/USD": 1.09}`), 0o600))
+
+	provider, err := services.NewFileRateProvider(path)
+	assert.NoError(s.T(), err)
+
+	rate, err := provider.GetRate("USD", "THB")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "36.50000000", rate.String())
+
+	rate, err = provider.GetRate("USD", "EUR")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "0.91743119", rate.String())
+
+	_, err = provider.GetRate("JPY", "THB")
+	assert.ErrorIs(s.T(), err, services.ErrRateUnavailable)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(s.T(), os.WriteFile(invalid, []byte(`{"USDTHB": "36.50"}`), 0o600))
+	_, err = services.NewFileRateProvider(invalid)
+	assert.Error(s.T(), err)
+
+	_, err = services.NewFileRateProvider(filepath.Join(dir, "missing.json"))
+	assert.Error(s.T(), err)
+}
+
+// fxTransferFixture holds the mocks of a transfer between a THB and a USD account
+type fxTransferFixture struct {
+	accountRepository     *mocks.AccountRepository
+	transactionRepository *mocks.TransactionRepository
+	ledgerRepository      *mocks.LedgerRepository
+	limitRepository       *mocks.TransferLimitRepository
+	holdRepository        *mocks.HoldRepository
+	outboxRepository      *mocks.OutboxRepository
+	fxRepository          *mocks.FXRepository
+	txProvider            *mocks.TxProvider
+	service               services.AccountService
+}
+
+func newFXTransferFixture() *fxTransferFixture {
+	f := &fxTransferFixture{
+		accountRepository:     new(mocks.AccountRepository),
+		transactionRepository: new(mocks.TransactionRepository),
+		ledgerRepository:      new(mocks.LedgerRepository),
+		limitRepository:       new(mocks.TransferLimitRepository),
+		holdRepository:        new(mocks.HoldRepository),
+		outboxRepository:      new(mocks.OutboxRepository),
+		fxRepository:          new(mocks.FXRepository),
+		txProvider:            new(mocks.TxProvider),
+	}
+	f.service = services.NewAccountService(f.accountRepository, f.transactionRepository, f.holdRepository, f.txProvider, newCacheStub())
+
+	f.accountRepository.On("GetAccountWithDetailByID", "acc-thb").Return(&models.AccountWithDetails{
+		AccountID: "acc-thb", UserID: "user-123", Type: "saving-account", Currency: "THB", AccountNumber: "111",
+	}, nil)
+	f.accountRepository.On("GetAccountWithDetailByID", "acc-usd").Return(&models.AccountWithDetails{
+		AccountID: "acc-usd", UserID: "user-123", Type: "saving-account", Currency: "USD", AccountNumber: "222",
+	}, nil)
+	f.limitRepository.On("GetApplicableLimits", mock.Anything, mock.Anything, mock.Anything).Return([]*models.TransferLimit{}, nil).Maybe()
+	f.limitRepository.On("GetDebitTotalSince", mock.Anything, mock.Anything, mock.Anything).Return(types.NewMoney(0, "THB"), nil).Maybe()
+	f.holdRepository.On("GetHeldAmount", mock.Anything, mock.Anything, mock.Anything).Return(types.NewMoney(0, "THB"), nil).Maybe()
+	f.outboxRepository.On("Create", mock.AnythingOfType("*models.DomainEvent")).Return(nil).Maybe()
+	f.txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+		Return(func(txFunc func(repositories.Adapters) error) error {
+			return txFunc(repositories.Adapters{
+				AccountRepository:       f.accountRepository,
+				TransactionRepository:   f.transactionRepository,
+				LedgerRepository:        f.ledgerRepository,
+				TransferLimitRepository: f.limitRepository,
+				FXRepository:            f.fxRepository,
+				HoldRepository:          f.holdRepository,
+				OutboxRepository:        f.outboxRepository,
+			})
+		}).Maybe()
+
+	return f
+}
+
+func thbToUSDQuote() *models.FXQuote {
+	return &models.FXQuote{
+		QuoteID:      "quote-123",
+		UserID:       "user-123",
+		FromCurrency: "THB",
+		ToCurrency:   "USD",
+		Rate:         types.MustParseRate("0.0274"),
+		SourceAmount: types.NewMoney(100000, "THB"),
+		TargetAmount: types.NewMoney(2740, "USD"),
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+}
+
+// TestTransferWithQuote tests that both legs of a cross-currency transfer are recorded in their own currency
+func (s *FXServiceTestSuite) TestTransferWithQuote() {
+	f := newFXTransferFixture()
+	quote := thbToUSDQuote()
+	amount := types.NewMoney(100000, "THB")
+
+	f.fxRepository.On("GetQuoteByIDForUpdate", "quote-123").Return(quote, nil).Once()
+	f.fxRepository.On("MarkQuoteUsed", "quote-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	f.accountRepository.On("TransferFunds", "acc-thb", "acc-usd", amount,
+		mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
+		Return(func(_, _ string, _ types.Money, updateFn func(types.Money, types.Money) (*types.TransferResult, error)) error {
+			_, err := updateFn(types.NewMoney(500000, "THB"), types.NewMoney(1000, "USD"))
+			return err
+		}).Once()
+
+	var legs []*models.Transaction
+	f.transactionRepository.On("Create", mock.AnythingOfType("*models.Transaction")).
+		Run(func(args mock.Arguments) { legs = append(legs, args.Get(0).(*models.Transaction)) }).
+		Return(nil).Twice()
+
+	var entry *models.JournalEntry
+	f.ledgerRepository.On("PostEntry", mock.AnythingOfType("*models.JournalEntry")).
+		Run(func(args mock.Arguments) { entry = args.Get(0).(*models.JournalEntry) }).
+		Return(nil).Once()
+
+	result, err := f.service.TransferWithQuote("acc-thb", "acc-usd", amount, "quote-123")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), types.NewMoney(400000, "THB"), result.SourceBalance)
+	assert.Equal(s.T(), types.NewMoney(3740, "USD"), result.DestinationBalance)
+	assert.Equal(s.T(), types.NewMoney(2740, "USD"), result.CreditedAmount)
+	assert.Equal(s.T(), &quote.Rate, result.ExchangeRate)
+
+	assert.Len(s.T(), legs, 2)
+	assert.Equal(s.T(), amount, legs[0].Amount)
+	assert.Equal(s.T(), models.Debit, legs[0].Direction)
+	assert.Equal(s.T(), types.NewMoney(2740, "USD"), legs[1].Amount)
+	assert.Equal(s.T(), models.Credit, legs[1].Direction)
+	for _, leg := range legs {
+		assert.Equal(s.T(), "0.02740000", leg.ExchangeRate.String())
+		assert.Equal(s.T(), "quote-123", *leg.FXQuoteID)
+	}
+
+	// Each currency balances against its fx position account
+	assert.Len(s.T(), entry.Postings, 4)
+	assert.Equal(s.T(), models.LedgerFXAccountPrefix+"THB", entry.Postings[1].AccountID)
+	assert.Equal(s.T(), models.LedgerFXAccountPrefix+"USD", entry.Postings[2].AccountID)
+	assert.Equal(s.T(), "acc-usd", entry.Postings[3].AccountID)
+	assert.Equal(s.T(), types.NewMoney(2740, "USD"), entry.Postings[3].Amount)
+
+	f.fxRepository.AssertExpectations(s.T())
+	f.accountRepository.AssertExpectations(s.T())
+	f.ledgerRepository.AssertExpectations(s.T())
+}
+
+// TestTransferWithQuote_Rejected tests the quotes a transfer cannot use
+func (s *FXServiceTestSuite) TestTransferWithQuote_Rejected() {
+	usedAt := time.Now().Add(-time.Second)
+
+	testCases := []struct {
+		name          string
+		mutate        func(quote *models.FXQuote)
+		quoteErr      error
+		markErr       error
+		amount        types.Money
+		expectedError error
+	}{
+		{
+			name:          "Not found",
+			quoteErr:      sql.ErrNoRows,
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrQuoteNotFound,
+		},
+		{
+			name:          "Quote of another user",
+			mutate:        func(quote *models.FXQuote) { quote.UserID = "user-456" },
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrQuoteNotFound,
+		},
+		{
+			name:          "Expired",
+			mutate:        func(quote *models.FXQuote) { quote.ExpiresAt = time.Now().Add(-time.Second) },
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrQuoteExpired,
+		},
+		{
+			name:          "Already used",
+			mutate:        func(quote *models.FXQuote) { quote.UsedAt = &usedAt },
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrQuoteUsed,
+		},
+		{
+			name:          "Used concurrently",
+			markErr:       repositories.ErrQuoteAlreadyUsed,
+			amount:        types.NewMoney(100000, "THB"),
+			expectedError: services.ErrQuoteUsed,
+		},
+		{
+			name:          "Different amount",
+			amount:        types.NewMoney(50000, "THB"),
+			expectedError: services.ErrQuoteMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			f := newFXTransferFixture()
+			quote := thbToUSDQuote()
+			if tc.mutate != nil {
+				tc.mutate(quote)
+			}
+			if tc.quoteErr != nil {
+				f.fxRepository.On("GetQuoteByIDForUpdate", "quote-123").Return(nil, tc.quoteErr).Once()
+			} else {
+				f.fxRepository.On("GetQuoteByIDForUpdate", "quote-123").Return(quote, nil).Once()
+			}
+			f.fxRepository.On("MarkQuoteUsed", "quote-123", mock.AnythingOfType("time.Time")).Return(tc.markErr).Maybe()
+
+			result, err := f.service.TransferWithQuote("acc-thb", "acc-usd", tc.amount, "quote-123")
+
+			assert.ErrorIs(s.T(), err, tc.expectedError)
+			assert.Nil(s.T(), result)
+			f.accountRepository.AssertNotCalled(s.T(), "TransferFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestTransferBetweenAccounts_QuoteRequired tests that cross-currency transfers need a quote and same currency ones do not take one
+func (s *FXServiceTestSuite) TestTransferBetweenAccounts_QuoteRequired() {
+	f := newFXTransferFixture()
+
+	_, err := f.service.TransferBetweenAccounts("acc-thb", "acc-usd", types.NewMoney(100000, "THB"))
+	assert.ErrorIs(s.T(), err, services.ErrQuoteRequired)
+
+	f.accountRepository.On("GetAccountWithDetailByID", "acc-thb-2").Return(&models.AccountWithDetails{
+		AccountID: "acc-thb-2", UserID: "user-123", Currency: "THB",
+	}, nil)
+	_, err = f.service.TransferWithQuote("acc-thb", "acc-thb-2", types.NewMoney(100000, "THB"), "quote-123")
+	assert.ErrorIs(s.T(), err, services.ErrQuoteMismatch)
+
+	f.txProvider.AssertNotCalled(s.T(), "Transact", mock.Anything)
+}
+
+// TestFXServiceSuite runs the test suite
+func TestFXServiceSuite(t *testing.T) {
+	suite.Run(t, new(FXServiceTestSuite))
+}
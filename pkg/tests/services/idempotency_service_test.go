@@ -0,0 +1,269 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mockCache "backend-developer-assignment/pkg/mocks/cache"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// IdempotencyServiceTestSuite is a test suite for IdempotencyService
+type IdempotencyServiceTestSuite struct {
+	suite.Suite
+	idempotencyRepository *mocks.IdempotencyRepository
+	redisClient           *mockCache.RedisClient
+	service               services.IdempotencyService
+	ctx                   context.Context
+}
+
+const (
+	idempotencyUserID = "user-123"
+	idempotencyKey    = "key-123"
+	idempotencyHash   = "hash-123"
+	idempotencyCache  = "idempotency:user-123:key-123"
+)
+
+// SetupTest sets up the test suite
+func (s *IdempotencyServiceTestSuite) SetupTest() {
+	s.idempotencyRepository = new(mocks.IdempotencyRepository)
+	s.redisClient = new(mockCache.RedisClient)
+	s.service = services.NewIdempotencyService(s.idempotencyRepository, s.redisClient)
+	s.ctx = context.Background()
+}
+
+func completedIdempotencyKey(requestHash string) *models.IdempotencyKey {
+	return &models.IdempotencyKey{
+		UserID:         idempotencyUserID,
+		IdempotencyKey: idempotencyKey,
+		RequestHash:    requestHash,
+		Status:         models.IdempotencyCompleted,
+		ResponseStatus: 200,
+		ResponseBody:   []byte(`{"balance":"100.00"}`),
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+}
+
+// TestBeginCacheHit tests that a completed response is replayed from the cache
+func (s *IdempotencyServiceTestSuite) TestBeginCacheHit() {
+	cachedData, _ := json.Marshal(completedIdempotencyKey(idempotencyHash))
+	s.redisClient.On("Get", s.ctx, idempotencyCache).Return(string(cachedData), nil).Once()
+
+	response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &types.IdempotentResponse{StatusCode: 200, Body: []byte(`{"balance":"100.00"}`)}, response)
+	s.idempotencyRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.redisClient.AssertExpectations(s.T())
+}
+
+// TestBeginCacheHitDifferentRequest tests that a cached key is rejected for a different request
+func (s *IdempotencyServiceTestSuite) TestBeginCacheHitDifferentRequest() {
+	cachedData, _ := json.Marshal(completedIdempotencyKey("other-hash"))
+	s.redisClient.On("Get", s.ctx, idempotencyCache).Return(string(cachedData), nil).Once()
+
+	response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+	assert.Equal(s.T(), services.ErrIdempotencyKeyReused, err)
+	assert.Nil(s.T(), response)
+}
+
+// TestBeginNewKey tests that a new key is claimed and the request proceeds
+func (s *IdempotencyServiceTestSuite) TestBeginNewKey() {
+	s.redisClient.On("Get", s.ctx, idempotencyCache).Return("", errors.New("cache miss")).Once()
+	s.idempotencyRepository.On("Create", mock.MatchedBy(func(record *models.IdempotencyKey) bool {
+		return record.UserID == idempotencyUserID &&
+			record.IdempotencyKey == idempotencyKey &&
+			record.RequestHash == idempotencyHash &&
+			record.Status == models.IdempotencyProcessing &&
+			record.LockedUntil != nil && record.LockedUntil.After(time.Now()) &&
+			record.ExpiresAt.After(*record.LockedUntil)
+	})).Return(true, nil).Once()
+
+	response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), response)
+	s.idempotencyRepository.AssertExpectations(s.T())
+}
+
+// TestBeginExistingKey tests the outcomes of a key that is already stored in the database
+func (s *IdempotencyServiceTestSuite) TestBeginExistingKey() {
+	leasedUntil := time.Now().Add(time.Minute)
+	processing := completedIdempotencyKey(idempotencyHash)
+	processing.Status = models.IdempotencyProcessing
+	processing.LockedUntil = &leasedUntil
+	leaseExpired := time.Now().Add(-time.Minute)
+	otherRequest := completedIdempotencyKey("other-hash")
+	otherRequest.Status = models.IdempotencyProcessing
+	otherRequest.LockedUntil = &leaseExpired
+
+	testCases := []struct {
+		name             string
+		existing         *models.IdempotencyKey
+		expectedResponse *types.IdempotentResponse
+		expectedError    error
+	}{
+		{
+			name:             "Success - Replay Completed Response",
+			existing:         completedIdempotencyKey(idempotencyHash),
+			expectedResponse: &types.IdempotentResponse{StatusCode: 200, Body: []byte(`{"balance":"100.00"}`)},
+		},
+		{
+			name:          "Failure - Different Request",
+			existing:      completedIdempotencyKey("other-hash"),
+			expectedError: services.ErrIdempotencyKeyReused,
+		},
+		{
+			name:          "Failure - Still Processing",
+			existing:      processing,
+			expectedError: services.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name:          "Failure - Lease Expired For A Different Request",
+			existing:      otherRequest,
+			expectedError: services.ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.redisClient.On("Get", s.ctx, idempotencyCache).Return("", errors.New("cache miss")).Once()
+			s.idempotencyRepository.On("Create", mock.Anything).Return(false, nil).Once()
+			s.idempotencyRepository.On("GetByKey", idempotencyUserID, idempotencyKey).Return(tc.existing, nil).Once()
+			if tc.existing.Status == models.IdempotencyCompleted {
+				s.redisClient.On("Set", s.ctx, idempotencyCache, mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+			assert.Equal(s.T(), tc.expectedError, err)
+			assert.Equal(s.T(), tc.expectedResponse, response)
+			s.idempotencyRepository.AssertExpectations(s.T())
+			s.redisClient.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestBeginExpiredKey tests that an expired key is claimed again
+func (s *IdempotencyServiceTestSuite) TestBeginExpiredKey() {
+	expired := completedIdempotencyKey("other-hash")
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	s.redisClient.On("Get", s.ctx, idempotencyCache).Return("", errors.New("cache miss")).Once()
+	s.idempotencyRepository.On("Create", mock.Anything).Return(false, nil).Once()
+	s.idempotencyRepository.On("GetByKey", idempotencyUserID, idempotencyKey).Return(expired, nil).Once()
+	s.idempotencyRepository.On("Reclaim", mock.MatchedBy(func(record *models.IdempotencyKey) bool {
+		return record.RequestHash == idempotencyHash && record.Status == models.IdempotencyProcessing
+	}), mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+	response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), response)
+	s.idempotencyRepository.AssertExpectations(s.T())
+}
+
+// TestBeginLeaseExpired tests that a retry claims a key left processing past its lease, unless another retry
+// claimed it first
+func (s *IdempotencyServiceTestSuite) TestBeginLeaseExpired() {
+	testCases := []struct {
+		name    string
+		claimed bool
+	}{
+		{name: "Success - Claimed", claimed: true},
+		{name: "Failure - Claimed By Another Retry", claimed: false},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			leaseExpired := time.Now().Add(-time.Minute)
+			stuck := completedIdempotencyKey(idempotencyHash)
+			stuck.Status = models.IdempotencyProcessing
+			stuck.LockedUntil = &leaseExpired
+
+			s.redisClient.On("Get", s.ctx, idempotencyCache).Return("", errors.New("cache miss")).Once()
+			s.idempotencyRepository.On("Create", mock.Anything).Return(false, nil).Once()
+			s.idempotencyRepository.On("GetByKey", idempotencyUserID, idempotencyKey).Return(stuck, nil).Once()
+			s.idempotencyRepository.On("Reclaim", mock.MatchedBy(func(record *models.IdempotencyKey) bool {
+				return record.LockedUntil.After(time.Now())
+			}), mock.AnythingOfType("time.Time")).Return(tc.claimed, nil).Once()
+
+			response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+			if tc.claimed {
+				assert.NoError(s.T(), err)
+			} else {
+				assert.Equal(s.T(), services.ErrIdempotencyKeyInProgress, err)
+			}
+			assert.Nil(s.T(), response)
+			s.idempotencyRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestBeginDatabaseError tests that a repository error is returned
+func (s *IdempotencyServiceTestSuite) TestBeginDatabaseError() {
+	s.redisClient.On("Get", s.ctx, idempotencyCache).Return("", errors.New("cache miss")).Once()
+	s.idempotencyRepository.On("Create", mock.Anything).Return(false, errors.New("database error")).Once()
+
+	response, err := s.service.Begin(idempotencyUserID, idempotencyKey, idempotencyHash)
+
+	assert.EqualError(s.T(), err, "database error")
+	assert.Nil(s.T(), response)
+}
+
+// TestComplete tests that the response is stored and cached
+func (s *IdempotencyServiceTestSuite) TestComplete() {
+	leasedUntil := time.Now().Add(time.Minute)
+	processing := completedIdempotencyKey(idempotencyHash)
+	processing.Status = models.IdempotencyProcessing
+	processing.LockedUntil = &leasedUntil
+	leaseExpired := time.Now().Add(-time.Minute)
+	otherRequest := completedIdempotencyKey("other-hash")
+	otherRequest.Status = models.IdempotencyProcessing
+	otherRequest.LockedUntil = &leaseExpired
+	processing.ResponseStatus = 0
+	processing.ResponseBody = nil
+
+	s.idempotencyRepository.On("GetByKey", idempotencyUserID, idempotencyKey).Return(processing, nil).Once()
+	s.idempotencyRepository.On("Complete", mock.MatchedBy(func(record *models.IdempotencyKey) bool {
+		return record.Status == models.IdempotencyCompleted &&
+			record.ResponseStatus == 201 &&
+			string(record.ResponseBody) == `{"ok":true}`
+	})).Return(nil).Once()
+	s.redisClient.On("Set", s.ctx, idempotencyCache, mock.Anything, mock.AnythingOfType("time.Duration")).Return(nil).Once()
+
+	err := s.service.Complete(idempotencyUserID, idempotencyKey, &types.IdempotentResponse{StatusCode: 201, Body: []byte(`{"ok":true}`)})
+
+	assert.NoError(s.T(), err)
+	s.idempotencyRepository.AssertExpectations(s.T())
+	s.redisClient.AssertExpectations(s.T())
+}
+
+// TestRelease tests that a released key is removed from the cache and the database
+func (s *IdempotencyServiceTestSuite) TestRelease() {
+	s.redisClient.On("Delete", s.ctx, idempotencyCache).Return(nil).Once()
+	s.idempotencyRepository.On("Delete", idempotencyUserID, idempotencyKey).Return(nil).Once()
+
+	err := s.service.Release(idempotencyUserID, idempotencyKey)
+
+	assert.NoError(s.T(), err)
+	s.idempotencyRepository.AssertExpectations(s.T())
+	s.redisClient.AssertExpectations(s.T())
+}
+
+func TestIdempotencyServiceSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyServiceTestSuite))
+}
@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// LedgerServiceTestSuite is a test suite for LedgerService
+type LedgerServiceTestSuite struct {
+	suite.Suite
+	ledgerRepository *mocks.LedgerRepository
+	service          services.LedgerService
+}
+
+// SetupTest sets up the test suite
+func (s *LedgerServiceTestSuite) SetupTest() {
+	s.ledgerRepository = new(mocks.LedgerRepository)
+	s.service = services.NewLedgerService(s.ledgerRepository)
+}
+
+// TestGetBalanceAt tests the GetBalanceAt function
+func (s *LedgerServiceTestSuite) TestGetBalanceAt() {
+	accountID := "acc-123"
+	at := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		mockBalance     types.Money
+		mockError       error
+		expectedBalance types.Money
+		expectedError   error
+	}{
+		{
+			name:            "Success - Balance Rebuilt",
+			mockBalance:     types.NewMoney(150050, "THB"),
+			expectedBalance: types.NewMoney(150050, "THB"),
+		},
+		{
+			name:          "Failure - No Ledger History",
+			mockError:     sql.ErrNoRows,
+			expectedError: sql.ErrNoRows,
+		},
+		{
+			name:          "Failure - Database Error",
+			mockError:     errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.ledgerRepository.On("GetBalanceAt", accountID, at).Return(tc.mockBalance, tc.mockError).Once()
+
+			balance, err := s.service.GetBalanceAt(accountID, at)
+
+			assert.Equal(s.T(), tc.expectedError, err)
+			assert.Equal(s.T(), tc.expectedBalance, balance)
+			s.ledgerRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestCheckBooksBalanced tests the CheckBooksBalanced function
+func (s *LedgerServiceTestSuite) TestCheckBooksBalanced() {
+	testCases := []struct {
+		name          string
+		mockTrial     []*models.TrialBalance
+		mockError     error
+		expectedError error
+	}{
+		{
+			name: "Success - Books Balanced",
+			mockTrial: []*models.TrialBalance{
+				{Currency: "THB", TotalDebits: types.NewMoney(500000, "THB"), TotalCredits: types.NewMoney(500000, "THB")},
+				{Currency: "USD", TotalDebits: types.NewMoney(1000, "USD"), TotalCredits: types.NewMoney(1000, "USD")},
+			},
+		},
+		{
+			name: "Failure - Currency Out Of Balance",
+			mockTrial: []*models.TrialBalance{
+				{Currency: "THB", TotalDebits: types.NewMoney(500000, "THB"), TotalCredits: types.NewMoney(500000, "THB")},
+				{Currency: "USD", TotalDebits: types.NewMoney(1000, "USD"), TotalCredits: types.NewMoney(999, "USD")},
+			},
+			expectedError: services.ErrLedgerUnbalanced,
+		},
+		{
+			name:          "Failure - Database Error",
+			mockError:     errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.ledgerRepository.On("GetTrialBalance").Return(tc.mockTrial, tc.mockError).Once()
+
+			trialBalances, err := s.service.CheckBooksBalanced()
+
+			assert.Equal(s.T(), tc.expectedError, err)
+			assert.Equal(s.T(), tc.mockTrial, trialBalances)
+			s.ledgerRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+func TestLedgerServiceSuite(t *testing.T) {
+	suite.Run(t, new(LedgerServiceTestSuite))
+}
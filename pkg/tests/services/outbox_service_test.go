@@ -0,0 +1,187 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// OutboxServiceTestSuite is a test suite for OutboxService
+type OutboxServiceTestSuite struct {
+	suite.Suite
+	outboxRepository *mocks.OutboxRepository
+	publisher        *services.MemoryEventPublisher
+	service          services.OutboxService
+}
+
+// SetupTest sets up the test suite
+func (s *OutboxServiceTestSuite) SetupTest() {
+	s.outboxRepository = new(mocks.OutboxRepository)
+	s.publisher = services.NewMemoryEventPublisher()
+	s.service = services.NewOutboxService(s.outboxRepository, s.publisher)
+}
+
+func accountEvent(sequence int64, eventType models.EventType, accountID, relatedAccountID string) *models.DomainEvent {
+	return &models.DomainEvent{
+		Sequence:           sequence,
+		EventID:            fmt.Sprintf("event-%d", sequence),
+		EventType:          eventType,
+		AggregateType:      models.AggregateAccount,
+		AggregateID:        accountID,
+		RelatedAggregateID: relatedAccountID,
+		Payload:            json.RawMessage(`{}`),
+		OccurredAt:         time.Now(),
+	}
+}
+
+func publishedSequences(events []*models.DomainEvent) []int64 {
+	sequences := make([]int64, 0, len(events))
+	for _, event := range events {
+		sequences = append(sequences, event.Sequence)
+	}
+	return sequences
+}
+
+// TestRelayEvents tests that events are published and marked in sequence order
+func (s *OutboxServiceTestSuite) TestRelayEvents() {
+	events := []*models.DomainEvent{
+		accountEvent(1, models.EventAccountCreated, "acc-123", ""),
+		accountEvent(2, models.EventFundsDeposited, "acc-123", ""),
+		accountEvent(3, models.EventTransferCompleted, "acc-123", "acc-456"),
+	}
+	s.outboxRepository.On("ClaimRelayLease", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	s.outboxRepository.On("GetUnpublished", mock.Anything).Return(events, nil).Once()
+	for _, event := range events {
+		s.outboxRepository.On("MarkPublished", event.Sequence, mock.Anything).Return(nil).Once()
+	}
+
+	err := s.service.RelayEvents(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []int64{1, 2, 3}, publishedSequences(s.publisher.Events()))
+	s.outboxRepository.AssertExpectations(s.T())
+}
+
+// TestRelayEventsKeepsAccountOrder tests that a failed event holds back the later events of its accounts only
+func (s *OutboxServiceTestSuite) TestRelayEventsKeepsAccountOrder() {
+	events := []*models.DomainEvent{
+		accountEvent(1, models.EventFundsDeposited, "acc-123", ""),
+		accountEvent(2, models.EventFundsDeposited, "acc-456", ""),
+		accountEvent(3, models.EventFundsWithdrawn, "acc-123", ""),
+		accountEvent(4, models.EventTransferCompleted, "acc-789", "acc-123"),
+		accountEvent(5, models.EventFundsDeposited, "acc-789", ""),
+		accountEvent(6, models.EventFundsDeposited, "acc-456", ""),
+	}
+	s.publisher.Subscribe(func(_ context.Context, event *models.DomainEvent) error {
+		if event.Sequence == 1 {
+			return errors.New("consumer unavailable")
+		}
+		return nil
+	})
+	s.outboxRepository.On("ClaimRelayLease", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	s.outboxRepository.On("GetUnpublished", mock.Anything).Return(events, nil).Once()
+	s.outboxRepository.On("RecordFailure", int64(1), "consumer unavailable").Return(nil).Once()
+	s.outboxRepository.On("MarkPublished", int64(2), mock.Anything).Return(nil).Once()
+	s.outboxRepository.On("MarkPublished", int64(6), mock.Anything).Return(nil).Once()
+
+	err := s.service.RelayEvents(context.Background())
+
+	// acc-123 waits for its first event, the transfer holds back acc-789 too
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []int64{2, 6}, publishedSequences(s.publisher.Events()))
+	s.outboxRepository.AssertExpectations(s.T())
+}
+
+// TestRelayEventsWithoutLease tests that only the instance holding the lease relays
+func (s *OutboxServiceTestSuite) TestRelayEventsWithoutLease() {
+	s.outboxRepository.On("ClaimRelayLease", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	err := s.service.RelayEvents(context.Background())
+
+	assert.NoError(s.T(), err)
+	s.outboxRepository.AssertNotCalled(s.T(), "GetUnpublished", mock.Anything)
+}
+
+// TestRelayEventsMarkError tests that the relay stops when a published event cannot be marked
+func (s *OutboxServiceTestSuite) TestRelayEventsMarkError() {
+	events := []*models.DomainEvent{
+		accountEvent(1, models.EventFundsDeposited, "acc-123", ""),
+		accountEvent(2, models.EventFundsDeposited, "acc-456", ""),
+	}
+	s.outboxRepository.On("ClaimRelayLease", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	s.outboxRepository.On("GetUnpublished", mock.Anything).Return(events, nil).Once()
+	s.outboxRepository.On("MarkPublished", int64(1), mock.Anything).Return(errors.New("database error")).Once()
+
+	err := s.service.RelayEvents(context.Background())
+
+	// The event is published again by the next run
+	assert.EqualError(s.T(), err, "database error")
+	assert.Equal(s.T(), []int64{1}, publishedSequences(s.publisher.Events()))
+	s.outboxRepository.AssertNotCalled(s.T(), "MarkPublished", int64(2), mock.Anything)
+}
+
+// TestPurgePublishedEvents tests that events older than the retention are deleted
+func (s *OutboxServiceTestSuite) TestPurgePublishedEvents() {
+	s.outboxRepository.On("DeletePublished", mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-24 * time.Hour))
+	})).Return(int64(3), nil).Once()
+
+	assert.NoError(s.T(), s.service.PurgePublishedEvents(context.Background()))
+	s.outboxRepository.AssertExpectations(s.T())
+}
+
+// TestFileEventPublisher tests that events are appended to the file as JSON lines
+func (s *OutboxServiceTestSuite) TestFileEventPublisher() {
+	path := filepath.Join(s.T().TempDir(), "events.jsonl")
+	publisher := services.NewFileEventPublisher(path)
+
+	assert.NoError(s.T(), publisher.Publish(context.Background(), accountEvent(1, models.EventAccountCreated, "acc-123", "")))
+	assert.NoError(s.T(), publisher.Publish(context.Background(), accountEvent(2, models.EventFundsDeposited, "acc-123", "")))
+
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	defer file.Close()
+
+	var published []*models.DomainEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event models.DomainEvent
+		s.Require().NoError(json.Unmarshal(scanner.Bytes(), &event))
+		published = append(published, &event)
+	}
+	assert.Equal(s.T(), []int64{1, 2}, publishedSequences(published))
+	assert.Equal(s.T(), models.EventFundsDeposited, published[1].EventType)
+}
+
+// TestRedisStreamPublisher tests the stream entries of published events
+func (s *OutboxServiceTestSuite) TestRedisStreamPublisher() {
+	client := new(mockServices.StreamClient)
+	client.On("AddToStream", mock.Anything, "domain-events", int64(1000), mock.MatchedBy(func(values map[string]interface{}) bool {
+		return values["event_type"] == string(models.EventFundsDeposited) && values["aggregate_id"] == "acc-123" &&
+			values["sequence"] == "2" && values["payload"] == "{}"
+	})).Return("1-0", nil).Once()
+
+	publisher := services.NewRedisStreamPublisher(client, "domain-events", 1000)
+
+	assert.NoError(s.T(), publisher.Publish(context.Background(), accountEvent(2, models.EventFundsDeposited, "acc-123", "")))
+	client.AssertExpectations(s.T())
+}
+
+// TestOutboxServiceSuite runs the test suite
+func TestOutboxServiceSuite(t *testing.T) {
+	suite.Run(t, new(OutboxServiceTestSuite))
+}
@@ -0,0 +1,296 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mockCache "backend-developer-assignment/pkg/mocks/cache"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// PinLockoutServiceTestSuite is a test suite for PinLockoutService
+type PinLockoutServiceTestSuite struct {
+	suite.Suite
+	pinLockoutRepository *mocks.PinLockoutRepository
+	redisClient          *mockCache.RedisClient
+	service              services.PinLockoutService
+}
+
+const (
+	pinUserID    = "user-123"
+	pinIP        = "203.0.113.7"
+	pinCacheKey  = "pin_lockout:user-123"
+	pinIPKeyPart = "pin_ip_failures:203.0.113.7:"
+)
+
+// SetupTest sets up the test suite
+func (s *PinLockoutServiceTestSuite) SetupTest() {
+	s.pinLockoutRepository = new(mocks.PinLockoutRepository)
+	s.redisClient = new(mockCache.RedisClient)
+	s.service = services.NewPinLockoutService(s.pinLockoutRepository, s.redisClient)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func isPinIPKey(key string) bool {
+	return strings.HasPrefix(key, pinIPKeyPart)
+}
+
+// expectIPCount expects the attempt to be counted for the address in the cache, returning the new count
+func (s *PinLockoutServiceTestSuite) expectIPCount(count int64) {
+	s.redisClient.On("Increment", mock.Anything, mock.MatchedBy(isPinIPKey), 15*time.Minute).Return(count, nil).Once()
+}
+
+// expectIPRelease expects the attempt counted for the address in the cache to be taken back
+func (s *PinLockoutServiceTestSuite) expectIPRelease() {
+	s.redisClient.On("Decrement", mock.Anything, mock.MatchedBy(isPinIPKey)).Return(int64(0), nil).Once()
+}
+
+// expectReserveAttempt runs the reservation on the stored failures of the user like the repository does, nothing is
+// stored when it refuses
+func (s *PinLockoutServiceTestSuite) expectReserveAttempt(stored *models.PinLockout) {
+	s.pinLockoutRepository.On("ReserveAttempt", pinUserID, mock.Anything).Return(
+		func(userID string, reserve func(lockout *models.PinLockout) error) (*models.PinLockout, error) {
+			lockout := *stored
+			if err := reserve(&lockout); err != nil {
+				return nil, err
+			}
+			return &lockout, nil
+		}).Once()
+}
+
+// TestReserveCountsAttempt tests that an attempt is counted as failed before it is verified, locking the PIN at
+// the thresholds
+func (s *PinLockoutServiceTestSuite) TestReserveCountsAttempt() {
+	longAgo := time.Now().Add(-time.Hour)
+	lockExpired := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name      string
+		stored    *models.PinLockout
+		locked    bool
+		permanent bool
+	}{
+		{name: "First attempt", stored: &models.PinLockout{UserID: pinUserID}},
+		{name: "Backoff elapsed", stored: &models.PinLockout{UserID: pinUserID, FailedAttempts: 3, LastFailedAt: &longAgo}},
+		{name: "Lockout threshold", stored: &models.PinLockout{UserID: pinUserID, FailedAttempts: 4, LastFailedAt: &longAgo}, locked: true},
+		{name: "Temporary lock expired", stored: &models.PinLockout{UserID: pinUserID, FailedAttempts: 5, LastFailedAt: &longAgo, LockedUntil: &lockExpired}, locked: true},
+		{name: "Permanent lock threshold", stored: &models.PinLockout{UserID: pinUserID, FailedAttempts: 9, LastFailedAt: &longAgo}, locked: true, permanent: true},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.expectIPCount(1)
+			s.expectReserveAttempt(tc.stored)
+			s.redisClient.On("Set", mock.Anything, pinCacheKey, mock.Anything, mock.Anything).Return(nil).Once()
+
+			attempt, err := s.service.Reserve(pinUserID, pinIP)
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), pinUserID, attempt.UserID)
+			assert.Equal(s.T(), tc.stored, attempt.Previous)
+			assert.Equal(s.T(), tc.stored.FailedAttempts+1, attempt.State.FailedAttempts)
+			assert.Equal(s.T(), tc.locked, attempt.State.Locked)
+			assert.Equal(s.T(), tc.permanent, attempt.State.Permanent)
+			if !tc.permanent {
+				// A wrong PIN backs off or locks for a while
+				assert.NotNil(s.T(), attempt.State.RetryAt)
+			}
+			s.redisClient.AssertNotCalled(s.T(), "Decrement", mock.Anything, mock.Anything)
+			s.redisClient.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestReserveRefusesLocked tests that an attempt is refused while the PIN is locked and not counted for the address
+func (s *PinLockoutServiceTestSuite) TestReserveRefusesLocked() {
+	now := time.Now()
+	justNow := now.Add(-time.Second)
+	longAgo := now.Add(-time.Hour)
+	lockedUntil := now.Add(10 * time.Minute)
+
+	testCases := []struct {
+		name        string
+		stored      *models.PinLockout
+		expectedErr error
+		retryAt     *time.Time
+	}{
+		{
+			name:        "Backing off after a recent failure",
+			stored:      &models.PinLockout{UserID: pinUserID, FailedAttempts: 3, LastFailedAt: &justNow},
+			expectedErr: services.ErrPinBackoff,
+			retryAt:     timePtr(justNow.Add(4 * time.Second)),
+		},
+		{
+			name:        "Temporarily locked",
+			stored:      &models.PinLockout{UserID: pinUserID, FailedAttempts: 5, LastFailedAt: &justNow, LockedUntil: &lockedUntil},
+			expectedErr: services.ErrPinTemporarilyLocked,
+			retryAt:     &lockedUntil,
+		},
+		{
+			name:        "Permanently locked",
+			stored:      &models.PinLockout{UserID: pinUserID, FailedAttempts: 10, LastFailedAt: &longAgo, LockedAt: &longAgo},
+			expectedErr: services.ErrPinLocked,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.expectIPCount(1)
+			s.expectReserveAttempt(tc.stored)
+			s.expectIPRelease()
+			s.redisClient.On("Set", mock.Anything, pinCacheKey, mock.Anything, mock.Anything).Return(nil).Once()
+
+			attempt, err := s.service.Reserve(pinUserID, pinIP)
+
+			assert.ErrorIs(s.T(), err, tc.expectedErr)
+			assert.Equal(s.T(), tc.stored.FailedAttempts, attempt.State.FailedAttempts)
+			assert.True(s.T(), attempt.State.Locked || attempt.State.RetryAt != nil)
+			if tc.retryAt != nil {
+				assert.WithinDuration(s.T(), *tc.retryAt, *attempt.State.RetryAt, time.Millisecond)
+			} else {
+				assert.Nil(s.T(), attempt.State.RetryAt)
+			}
+			s.redisClient.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestReserveConcurrentAttempts tests that attempts racing on the same failures are each counted, the second one
+// is refused by the backoff the first one started
+func (s *PinLockoutServiceTestSuite) TestReserveConcurrentAttempts() {
+	stored := &models.PinLockout{UserID: pinUserID, FailedAttempts: 1}
+	s.redisClient.On("Increment", mock.Anything, mock.MatchedBy(isPinIPKey), mock.Anything).Return(int64(1), nil)
+	s.redisClient.On("Decrement", mock.Anything, mock.MatchedBy(isPinIPKey)).Return(int64(0), nil)
+	s.redisClient.On("Set", mock.Anything, pinCacheKey, mock.Anything, mock.Anything).Return(nil)
+	s.pinLockoutRepository.On("ReserveAttempt", pinUserID, mock.Anything).Return(
+		func(userID string, reserve func(lockout *models.PinLockout) error) (*models.PinLockout, error) {
+			// The row lock serializes the reservations, each one sees the row the previous one stored
+			lockout := *stored
+			if err := reserve(&lockout); err != nil {
+				return nil, err
+			}
+			stored = &lockout
+			return &lockout, nil
+		})
+
+	first, err := s.service.Reserve(pinUserID, pinIP)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), first.State.RetryAt)
+
+	_, err = s.service.Reserve(pinUserID, pinIP)
+	assert.ErrorIs(s.T(), err, services.ErrPinBackoff)
+	assert.Equal(s.T(), 2, stored.FailedAttempts)
+}
+
+// TestReserveThrottledAddress tests that an address with too many failures is refused before the user is looked at
+func (s *PinLockoutServiceTestSuite) TestReserveThrottledAddress() {
+	s.expectIPCount(21)
+
+	attempt, err := s.service.Reserve(pinUserID, pinIP)
+
+	assert.ErrorIs(s.T(), err, services.ErrPinThrottled)
+	assert.True(s.T(), attempt.State.Locked)
+	assert.True(s.T(), attempt.State.RetryAt.After(time.Now()))
+	s.pinLockoutRepository.AssertNotCalled(s.T(), "ReserveAttempt", mock.Anything, mock.Anything)
+	s.redisClient.AssertNotCalled(s.T(), "Decrement", mock.Anything, mock.Anything)
+}
+
+// TestReserveIPFallsBackToDatabase tests that failures of an address are counted in the database while the cache
+// is unavailable, and taken back there
+func (s *PinLockoutServiceTestSuite) TestReserveIPFallsBackToDatabase() {
+	s.redisClient.On("Increment", mock.Anything, mock.MatchedBy(isPinIPKey), mock.Anything).Return(int64(0), errors.New("connection refused")).Once()
+	s.pinLockoutRepository.On("IncrementIPFailures", pinIP, mock.AnythingOfType("time.Time")).Return(int64(3), nil).Once()
+	s.pinLockoutRepository.On("DecrementIPFailures", pinIP, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	attempt, err := s.service.ReserveIP(pinIP)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), attempt.CountedInCache)
+
+	err = s.service.Release(attempt)
+
+	assert.NoError(s.T(), err)
+	s.pinLockoutRepository.AssertExpectations(s.T())
+	s.redisClient.AssertNotCalled(s.T(), "Decrement", mock.Anything, mock.Anything)
+}
+
+// TestRecordSuccess tests that the right PIN takes the attempt back and forgets earlier failures
+func (s *PinLockoutServiceTestSuite) TestRecordSuccess() {
+	lastFailedAt := time.Now().Add(-time.Minute)
+	attempt := &models.PinAttempt{
+		UserID:         pinUserID,
+		IPAddress:      pinIP,
+		WindowStart:    time.Now().Truncate(15 * time.Minute),
+		CountedInCache: true,
+		Previous:       &models.PinLockout{UserID: pinUserID, FailedAttempts: 2, LastFailedAt: &lastFailedAt},
+	}
+	s.expectIPRelease()
+	s.pinLockoutRepository.On("Delete", pinUserID).Return(nil).Once()
+	s.redisClient.On("Set", mock.Anything, pinCacheKey, mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := s.service.RecordSuccess(attempt)
+
+	assert.NoError(s.T(), err)
+	s.pinLockoutRepository.AssertExpectations(s.T())
+	s.redisClient.AssertExpectations(s.T())
+}
+
+// TestRelease tests that an attempt in which no PIN was guessed puts the failures from before it back
+func (s *PinLockoutServiceTestSuite) TestRelease() {
+	lastFailedAt := time.Now().Add(-time.Minute)
+	previous := &models.PinLockout{UserID: pinUserID, FailedAttempts: 2, LastFailedAt: &lastFailedAt}
+	attempt := &models.PinAttempt{
+		UserID:         pinUserID,
+		IPAddress:      pinIP,
+		WindowStart:    time.Now().Truncate(15 * time.Minute),
+		CountedInCache: true,
+		Previous:       previous,
+	}
+	s.expectIPRelease()
+	s.pinLockoutRepository.On("ReleaseAttempt", previous, 3).Return(nil).Once()
+	s.redisClient.On("Delete", mock.Anything, pinCacheKey).Return(nil).Once()
+
+	err := s.service.Release(attempt)
+
+	assert.NoError(s.T(), err)
+	s.pinLockoutRepository.AssertExpectations(s.T())
+	s.redisClient.AssertExpectations(s.T())
+}
+
+// TestReleaseNothingReserved tests that releasing an attempt that was never reserved does nothing
+func (s *PinLockoutServiceTestSuite) TestReleaseNothingReserved() {
+	assert.NoError(s.T(), s.service.Release(nil))
+	assert.NoError(s.T(), s.service.RecordSuccess(nil))
+	s.redisClient.AssertNotCalled(s.T(), "Decrement", mock.Anything, mock.Anything)
+}
+
+// TestUnlock tests that unlocking lifts a permanent lock
+func (s *PinLockoutServiceTestSuite) TestUnlock() {
+	s.pinLockoutRepository.On("Delete", pinUserID).Return(nil).Once()
+	s.redisClient.On("Set", mock.Anything, pinCacheKey, mock.MatchedBy(func(data []byte) bool {
+		var lockout models.PinLockout
+		return json.Unmarshal(data, &lockout) == nil && lockout.FailedAttempts == 0 && lockout.LockedAt == nil
+	}), mock.Anything).Return(nil).Once()
+
+	err := s.service.Unlock(pinUserID)
+
+	assert.NoError(s.T(), err)
+	s.redisClient.AssertExpectations(s.T())
+}
+
+// TestPinLockoutServiceSuite runs the test suite
+func TestPinLockoutServiceSuite(t *testing.T) {
+	suite.Run(t, new(PinLockoutServiceTestSuite))
+}
@@ -0,0 +1,335 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/utils"
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// PinServiceTestSuite is a test suite for PinService
+type PinServiceTestSuite struct {
+	suite.Suite
+	userRepository    *mocks.UserRepository
+	pinRepository     *mocks.PinRepository
+	authService       *mockServices.AuthService
+	pinLockoutService *mockServices.PinLockoutService
+	notifier          *mockServices.Notifier
+	service           services.PinService
+	user              *models.User
+}
+
+const (
+	pinServiceUserID = "user-123"
+	currentPIN       = "135790"
+	newPIN           = "258147"
+)
+
+// SetupSuite hashes the current PIN once, bcrypt is slow
+func (s *PinServiceTestSuite) SetupSuite() {
+	pinHash, err := utils.HashPIN(currentPIN)
+	s.Require().NoError(err)
+	s.user = &models.User{UserID: pinServiceUserID, PIN: pinHash}
+}
+
+// SetupTest sets up the test suite
+func (s *PinServiceTestSuite) SetupTest() {
+	s.userRepository = new(mocks.UserRepository)
+	s.pinRepository = new(mocks.PinRepository)
+	s.authService = new(mockServices.AuthService)
+	s.pinLockoutService = new(mockServices.PinLockoutService)
+	s.notifier = new(mockServices.Notifier)
+	s.service = services.NewPinService(s.userRepository, s.pinRepository, s.authService, s.pinLockoutService, s.notifier)
+}
+
+// resetCode returns a reset code for the test user hashed like RequestReset stores it
+func (s *PinServiceTestSuite) resetCode(code string, attempts int, expiresAt time.Time) *models.PinResetCode {
+	codeHash, err := utils.HashPIN(code)
+	s.Require().NoError(err)
+	return &models.PinResetCode{UserID: pinServiceUserID, CodeHash: codeHash, Attempts: attempts, ExpiresAt: expiresAt}
+}
+
+// TestChangePin tests that a new PIN is stored and every refresh token of the user is revoked
+func (s *PinServiceTestSuite) TestChangePin() {
+	s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+	s.pinRepository.On("GetPinHistory", pinServiceUserID, 4).Return([]string{}, nil).Once()
+	s.pinRepository.On("ReplacePin", pinServiceUserID, mock.MatchedBy(func(pinHash string) bool {
+		return utils.VerifyPIN(pinHash, newPIN)
+	}), 4).Return(nil).Once()
+	s.authService.On("LogoutAll", pinServiceUserID).Return(int64(2), nil).Once()
+
+	err := s.service.ChangePin(pinServiceUserID, currentPIN, newPIN)
+
+	assert.NoError(s.T(), err)
+	s.pinRepository.AssertExpectations(s.T())
+	s.authService.AssertExpectations(s.T())
+}
+
+// TestChangePinIncorrectPin tests that the current PIN is required
+func (s *PinServiceTestSuite) TestChangePinIncorrectPin() {
+	s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+
+	err := s.service.ChangePin(pinServiceUserID, "000000", newPIN)
+
+	assert.ErrorIs(s.T(), err, services.ErrIncorrectPin)
+	s.pinRepository.AssertNotCalled(s.T(), "ReplacePin", mock.Anything, mock.Anything, mock.Anything)
+	s.authService.AssertNotCalled(s.T(), "LogoutAll", mock.Anything)
+}
+
+// TestChangePinPolicy tests the PINs refused by the PIN policy
+func (s *PinServiceTestSuite) TestChangePinPolicy() {
+	testCases := []struct {
+		name   string
+		newPIN string
+		valid  bool
+	}{
+		{name: "Too short", newPIN: "25814"},
+		{name: "Too long", newPIN: "2581470"},
+		{name: "Not digits", newPIN: "25a147"},
+		{name: "Repeated digits", newPIN: "258889"},
+		{name: "Ascending digits", newPIN: "912347"},
+		{name: "Descending digits", newPIN: "865439"},
+		{name: "Two repeated digits", newPIN: "225588", valid: true},
+		{name: "Two sequential digits", newPIN: "129856", valid: true},
+		{name: "Digits wrapping around", newPIN: "890258", valid: true},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+			if tc.valid {
+				s.pinRepository.On("GetPinHistory", pinServiceUserID, 4).Return([]string{}, nil).Once()
+				s.pinRepository.On("ReplacePin", pinServiceUserID, mock.Anything, 4).Return(nil).Once()
+				s.authService.On("LogoutAll", pinServiceUserID).Return(int64(0), nil).Once()
+			}
+
+			err := s.service.ChangePin(pinServiceUserID, currentPIN, tc.newPIN)
+
+			if tc.valid {
+				assert.NoError(s.T(), err)
+			} else {
+				assert.ErrorIs(s.T(), err, services.ErrPinPolicy)
+				s.pinRepository.AssertNotCalled(s.T(), "ReplacePin", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestChangePinReused tests that the current PIN and the recent PINs cannot be chosen again
+func (s *PinServiceTestSuite) TestChangePinReused() {
+	recentHash, err := utils.HashPIN(newPIN)
+	s.Require().NoError(err)
+
+	s.Run("Current PIN", func() {
+		s.SetupTest()
+		s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+		s.pinRepository.On("GetPinHistory", pinServiceUserID, 4).Return([]string{}, nil).Once()
+
+		err := s.service.ChangePin(pinServiceUserID, currentPIN, currentPIN)
+
+		assert.ErrorIs(s.T(), err, services.ErrPinReused)
+	})
+
+	s.Run("Recent PIN", func() {
+		s.SetupTest()
+		s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+		s.pinRepository.On("GetPinHistory", pinServiceUserID, 4).Return([]string{recentHash}, nil).Once()
+
+		err := s.service.ChangePin(pinServiceUserID, currentPIN, newPIN)
+
+		assert.ErrorIs(s.T(), err, services.ErrPinReused)
+		s.pinRepository.AssertNotCalled(s.T(), "ReplacePin", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestRequestReset tests that a code is stored hashed and sent through the notifier
+func (s *PinServiceTestSuite) TestRequestReset() {
+	var stored *models.PinResetCode
+	s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+	s.pinRepository.On("GetResetCode", pinServiceUserID).Return(nil, sql.ErrNoRows).Once()
+	s.pinRepository.On("SaveResetCode", mock.AnythingOfType("*models.PinResetCode")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.PinResetCode) }).Return(nil).Once()
+	s.notifier.On("Notify", pinServiceUserID, mock.AnythingOfType("string")).Return(nil).Once()
+
+	expiresAt, err := s.service.RequestReset(pinServiceUserID)
+
+	assert.NoError(s.T(), err)
+	assert.WithinDuration(s.T(), time.Now().Add(10*time.Minute), expiresAt, time.Second)
+	message := s.notifier.Calls[0].Arguments.String(1)
+	code := regexp.MustCompile(`\d{6}`).FindString(message)
+	assert.NotEmpty(s.T(), code)
+	assert.True(s.T(), utils.VerifyPIN(stored.CodeHash, code))
+	assert.NotContains(s.T(), stored.CodeHash, code)
+}
+
+// TestRequestResetTooSoon tests that codes cannot be requested back to back
+func (s *PinServiceTestSuite) TestRequestResetTooSoon() {
+	s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+	s.pinRepository.On("GetResetCode", pinServiceUserID).
+		Return(&models.PinResetCode{UserID: pinServiceUserID, ExpiresAt: time.Now().Add(10 * time.Minute), CreatedAt: time.Now()}, nil).Once()
+
+	_, err := s.service.RequestReset(pinServiceUserID)
+
+	assert.ErrorIs(s.T(), err, services.ErrResetTooSoon)
+	s.notifier.AssertNotCalled(s.T(), "Notify", mock.Anything, mock.Anything)
+}
+
+// TestRequestResetUnknownUser tests that nothing is sent for an unknown user
+func (s *PinServiceTestSuite) TestRequestResetUnknownUser() {
+	s.userRepository.On("GetByID", "unknown-user").Return(nil, sql.ErrNoRows).Once()
+
+	_, err := s.service.RequestReset("unknown-user")
+
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+	s.notifier.AssertNotCalled(s.T(), "Notify", mock.Anything, mock.Anything)
+}
+
+// TestConfirmReset tests that the right code sets the PIN, is used up and lifts the PIN lock
+func (s *PinServiceTestSuite) TestConfirmReset() {
+	resetCode := s.resetCode("482913", 1, time.Now().Add(time.Minute))
+	s.pinRepository.On("GetResetCode", pinServiceUserID).Return(resetCode, nil).Once()
+	s.pinRepository.On("ReserveResetAttempt", resetCode, 5).Return(true, nil).Once()
+	s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+	s.pinRepository.On("GetPinHistory", pinServiceUserID, 4).Return([]string{}, nil).Once()
+	s.pinRepository.On("DeleteResetCode", resetCode).Return(true, nil).Once()
+	s.pinRepository.On("ReplacePin", pinServiceUserID, mock.Anything, 4).Return(nil).Once()
+	s.authService.On("LogoutAll", pinServiceUserID).Return(int64(1), nil).Once()
+	s.pinLockoutService.On("Unlock", pinServiceUserID).Return(nil).Once()
+
+	err := s.service.ConfirmReset(pinServiceUserID, "482913", newPIN)
+
+	assert.NoError(s.T(), err)
+	s.pinRepository.AssertExpectations(s.T())
+	s.authService.AssertExpectations(s.T())
+	s.pinLockoutService.AssertExpectations(s.T())
+}
+
+// TestConfirmResetInvalidCode tests the codes that cannot reset the PIN
+func (s *PinServiceTestSuite) TestConfirmResetInvalidCode() {
+	testCases := []struct {
+		name      string
+		resetCode *models.PinResetCode
+		code      string
+		expect    func(resetCode *models.PinResetCode)
+	}{
+		{
+			name:   "No code requested",
+			code:   "482913",
+			expect: func(*models.PinResetCode) {},
+		},
+		{
+			name:      "Wrong code",
+			resetCode: s.resetCode("482913", 0, time.Now().Add(time.Minute)),
+			code:      "000000",
+			expect: func(resetCode *models.PinResetCode) {
+				// The attempt is counted before the code is checked and stays counted
+				s.pinRepository.On("ReserveResetAttempt", resetCode, 5).Return(true, nil).Once()
+			},
+		},
+		{
+			name:      "Expired code",
+			resetCode: s.resetCode("482913", 0, time.Now().Add(-time.Minute)),
+			code:      "482913",
+			expect: func(resetCode *models.PinResetCode) {
+				s.pinRepository.On("DeleteResetCode", resetCode).Return(true, nil).Once()
+			},
+		},
+		{
+			name:      "Too many attempts",
+			resetCode: s.resetCode("482913", 4, time.Now().Add(time.Minute)),
+			code:      "482913",
+			expect: func(resetCode *models.PinResetCode) {
+				// A concurrent attempt took the last one
+				s.pinRepository.On("ReserveResetAttempt", resetCode, 5).Return(false, nil).Once()
+				s.pinRepository.On("DeleteResetCode", resetCode).Return(true, nil).Once()
+			},
+		},
+		{
+			name:      "Used concurrently",
+			resetCode: s.resetCode("482913", 0, time.Now().Add(time.Minute)),
+			code:      "482913",
+			expect: func(resetCode *models.PinResetCode) {
+				s.pinRepository.On("ReserveResetAttempt", resetCode, 5).Return(true, nil).Once()
+				s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+				s.pinRepository.On("GetPinHistory", pinServiceUserID, 4).Return([]string{}, nil).Once()
+				s.pinRepository.On("DeleteResetCode", resetCode).Return(false, nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.resetCode != nil {
+				s.pinRepository.On("GetResetCode", pinServiceUserID).Return(tc.resetCode, nil).Once()
+			} else {
+				s.pinRepository.On("GetResetCode", pinServiceUserID).Return(nil, sql.ErrNoRows).Once()
+			}
+			tc.expect(tc.resetCode)
+
+			err := s.service.ConfirmReset(pinServiceUserID, tc.code, newPIN)
+
+			assert.ErrorIs(s.T(), err, services.ErrInvalidResetCode)
+			s.pinRepository.AssertExpectations(s.T())
+			s.pinRepository.AssertNotCalled(s.T(), "ReplacePin", mock.Anything, mock.Anything, mock.Anything)
+			s.pinRepository.AssertNotCalled(s.T(), "ReleaseResetAttempt", mock.Anything)
+			s.pinLockoutService.AssertNotCalled(s.T(), "Unlock", mock.Anything)
+		})
+	}
+}
+
+// TestConfirmResetRejectedPin tests that the code stays usable when the new PIN is refused, without using up an attempt
+func (s *PinServiceTestSuite) TestConfirmResetRejectedPin() {
+	resetCode := s.resetCode("482913", 0, time.Now().Add(time.Minute))
+	s.pinRepository.On("GetResetCode", pinServiceUserID).Return(resetCode, nil).Once()
+	s.pinRepository.On("ReserveResetAttempt", resetCode, 5).Return(true, nil).Once()
+	s.userRepository.On("GetByID", pinServiceUserID).Return(s.user, nil).Once()
+	s.pinRepository.On("ReleaseResetAttempt", resetCode).Return(nil).Once()
+
+	err := s.service.ConfirmReset(pinServiceUserID, "482913", "123456")
+
+	assert.ErrorIs(s.T(), err, services.ErrPinPolicy)
+	s.pinRepository.AssertExpectations(s.T())
+	s.pinRepository.AssertNotCalled(s.T(), "DeleteResetCode", mock.Anything)
+}
+
+// TestFileNotifier tests that notifications are appended to the file as JSON lines
+func (s *PinServiceTestSuite) TestFileNotifier() {
+	path := filepath.Join(s.T().TempDir(), "notifications.log")
+	notifier := services.NewFileNotifier(path)
+
+	s.Require().NoError(notifier.Notify("user-1", "first"))
+	s.Require().NoError(notifier.Notify("user-2", "second"))
+
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	defer file.Close()
+
+	var messages []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		s.Require().NoError(json.Unmarshal(scanner.Bytes(), &line))
+		messages = append(messages, line["user_id"].(string)+":"+line["message"].(string))
+	}
+	assert.Equal(s.T(), []string{"user-1:first", "user-2:second"}, messages)
+}
+
+// TestPinServiceSuite runs the test suite
+func TestPinServiceSuite(t *testing.T) {
+	suite.Run(t, new(PinServiceTestSuite))
+}
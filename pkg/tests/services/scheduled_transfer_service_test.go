@@ -0,0 +1,459 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ScheduledTransferServiceTestSuite is a test suite for ScheduledTransferService
+type ScheduledTransferServiceTestSuite struct {
+	suite.Suite
+	scheduledTransferRepository *mocks.ScheduledTransferRepository
+	accountService              *mockServices.AccountService
+	auditService                *mockServices.AuditService
+	service                     services.ScheduledTransferService
+}
+
+// SetupTest sets up the test suite
+func (s *ScheduledTransferServiceTestSuite) SetupTest() {
+	s.scheduledTransferRepository = new(mocks.ScheduledTransferRepository)
+	s.accountService = new(mockServices.AccountService)
+	s.auditService = new(mockServices.AuditService)
+	s.auditService.On("Record", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	s.service = services.NewScheduledTransferService(s.scheduledTransferRepository, s.accountService, s.auditService)
+}
+
+func activeSchedule(frequency models.ScheduleFrequency, startAt, nextRunAt time.Time) *models.ScheduledTransfer {
+	return &models.ScheduledTransfer{
+		BaseModel:               &models.BaseModel{},
+		ScheduleID:              "schedule-123",
+		UserID:                  "user-123",
+		FromAccountID:           "acc-from",
+		ToAccountID:             "acc-to",
+		Amount:                  types.NewMoney(150000, "THB"),
+		Frequency:               frequency,
+		StartAt:                 startAt,
+		NextRunAt:               nextRunAt,
+		NextAttemptAt:           nextRunAt,
+		Status:                  models.ScheduleActive,
+		InsufficientFundsPolicy: models.SkipOnInsufficientFunds,
+		LeaseOwner:              strPtr("lease-owner"),
+	}
+}
+
+// TestCreateSchedule tests the CreateSchedule function
+func (s *ScheduledTransferServiceTestSuite) TestCreateSchedule() {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+	beforeStart := future.Add(-time.Hour)
+
+	testCases := []struct {
+		name          string
+		schedule      *models.ScheduledTransfer
+		toCurrency    string
+		expectCreate  bool
+		expectedError error
+	}{
+		{
+			name: "Success - Defaults Applied",
+			schedule: &models.ScheduledTransfer{
+				FromAccountID: "acc-from",
+				ToAccountID:   "acc-to",
+				Amount:        types.NewMoney(150000, "THB"),
+				Frequency:     models.FrequencyMonthly,
+				StartAt:       future,
+			},
+			toCurrency:   "THB",
+			expectCreate: true,
+		},
+		{
+			name: "Failure - Same Account",
+			schedule: &models.ScheduledTransfer{
+				FromAccountID: "acc-from",
+				ToAccountID:   "acc-from",
+				Amount:        types.NewMoney(150000, "THB"),
+				Frequency:     models.FrequencyDaily,
+			},
+			expectedError: services.ErrInvalidSchedule,
+		},
+		{
+			name: "Failure - Zero Amount",
+			schedule: &models.ScheduledTransfer{
+				FromAccountID: "acc-from",
+				ToAccountID:   "acc-to",
+				Amount:        types.NewMoney(0, "THB"),
+				Frequency:     models.FrequencyDaily,
+			},
+			expectedError: services.ErrInvalidAmount,
+		},
+		{
+			name: "Failure - Currency Mismatch",
+			schedule: &models.ScheduledTransfer{
+				FromAccountID: "acc-from",
+				ToAccountID:   "acc-to",
+				Amount:        types.NewMoney(150000, "THB"),
+				Frequency:     models.FrequencyDaily,
+			},
+			toCurrency:    "USD",
+			expectedError: services.ErrCurrencyMismatch,
+		},
+		{
+			name: "Failure - Start In The Past",
+			schedule: &models.ScheduledTransfer{
+				FromAccountID: "acc-from",
+				ToAccountID:   "acc-to",
+				Amount:        types.NewMoney(150000, "THB"),
+				Frequency:     models.FrequencyDaily,
+				StartAt:       past,
+			},
+			toCurrency:    "THB",
+			expectedError: services.ErrInvalidSchedule,
+		},
+		{
+			name: "Failure - End Before Start",
+			schedule: &models.ScheduledTransfer{
+				FromAccountID: "acc-from",
+				ToAccountID:   "acc-to",
+				Amount:        types.NewMoney(150000, "THB"),
+				Frequency:     models.FrequencyDaily,
+				StartAt:       future,
+				EndAt:         &beforeStart,
+			},
+			toCurrency:    "THB",
+			expectedError: services.ErrInvalidSchedule,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.toCurrency != "" {
+				s.accountService.On("GetAccountByID", "acc-from").Return(&models.Account{AccountID: "acc-from", Currency: "THB"}, nil).Once()
+				s.accountService.On("GetAccountByID", "acc-to").Return(&models.Account{AccountID: "acc-to", Currency: tc.toCurrency}, nil).Once()
+			}
+			if tc.expectCreate {
+				s.scheduledTransferRepository.On("Create", tc.schedule).Return(nil).Once()
+			}
+
+			err := s.service.CreateSchedule(tc.schedule)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(s.T(), err, tc.expectedError)
+			} else {
+				assert.NoError(s.T(), err)
+				assert.NotEmpty(s.T(), tc.schedule.ScheduleID)
+				assert.Equal(s.T(), models.ScheduleActive, tc.schedule.Status)
+				assert.Equal(s.T(), models.SkipOnInsufficientFunds, tc.schedule.InsufficientFundsPolicy)
+				assert.True(s.T(), tc.schedule.NextRunAt.Equal(future))
+				assert.True(s.T(), tc.schedule.NextAttemptAt.Equal(future))
+			}
+			s.accountService.AssertExpectations(s.T())
+			s.scheduledTransferRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestGetScheduleOfAnotherAccount tests that a schedule is only visible through its source account
+func (s *ScheduledTransferServiceTestSuite) TestGetScheduleOfAnotherAccount() {
+	now := time.Now().UTC()
+	s.scheduledTransferRepository.On("GetByID", "schedule-123").Return(activeSchedule(models.FrequencyDaily, now, now), nil).Once()
+	s.scheduledTransferRepository.On("GetByID", "missing").Return(nil, sql.ErrNoRows).Once()
+
+	_, err := s.service.GetSchedule("acc-other", "schedule-123")
+	assert.ErrorIs(s.T(), err, services.ErrScheduleNotFound)
+
+	_, err = s.service.GetSchedule("acc-from", "missing")
+	assert.ErrorIs(s.T(), err, services.ErrScheduleNotFound)
+}
+
+// TestUpdateSchedule tests the UpdateSchedule function
+func (s *ScheduledTransferServiceTestSuite) TestUpdateSchedule() {
+	s.Run("Pause", func() {
+		s.SetupTest()
+		now := time.Now().UTC()
+		schedule := activeSchedule(models.FrequencyDaily, now, now.Add(time.Hour))
+		paused := models.SchedulePaused
+		s.scheduledTransferRepository.On("GetByID", "schedule-123").Return(schedule, nil).Once()
+		s.scheduledTransferRepository.On("Update", schedule).Return(nil).Once()
+
+		updated, err := s.service.UpdateSchedule("acc-from", "schedule-123", &models.ScheduledTransferUpdate{Status: &paused})
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), models.SchedulePaused, updated.Status)
+		assert.True(s.T(), updated.NextRunAt.Equal(now.Add(time.Hour)))
+		s.scheduledTransferRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("Resume Skips Missed Occurrences", func() {
+		s.SetupTest()
+		startAt := time.Now().UTC().AddDate(0, 0, -10).Add(time.Hour)
+		schedule := activeSchedule(models.FrequencyDaily, startAt, startAt.AddDate(0, 0, 2))
+		schedule.Status = models.SchedulePaused
+		schedule.RetryCount = 3
+		active := models.ScheduleActive
+		s.scheduledTransferRepository.On("GetByID", "schedule-123").Return(schedule, nil).Once()
+		s.scheduledTransferRepository.On("Update", schedule).Return(nil).Once()
+
+		updated, err := s.service.UpdateSchedule("acc-from", "schedule-123", &models.ScheduledTransferUpdate{Status: &active})
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), models.ScheduleActive, updated.Status)
+		assert.True(s.T(), updated.NextRunAt.Equal(startAt.AddDate(0, 0, 10)))
+		assert.True(s.T(), updated.NextAttemptAt.Equal(updated.NextRunAt))
+		assert.Equal(s.T(), 0, updated.RetryCount)
+	})
+
+	s.Run("Failure - Currency Mismatch", func() {
+		s.SetupTest()
+		now := time.Now().UTC()
+		amount := types.NewMoney(100, "USD")
+		s.scheduledTransferRepository.On("GetByID", "schedule-123").Return(activeSchedule(models.FrequencyDaily, now, now), nil).Once()
+
+		_, err := s.service.UpdateSchedule("acc-from", "schedule-123", &models.ScheduledTransferUpdate{Amount: &amount})
+
+		assert.ErrorIs(s.T(), err, services.ErrCurrencyMismatch)
+		s.scheduledTransferRepository.AssertNotCalled(s.T(), "Update", mock.Anything)
+	})
+
+	s.Run("Failure - Completed", func() {
+		s.SetupTest()
+		now := time.Now().UTC()
+		schedule := activeSchedule(models.FrequencyOnce, now, now)
+		schedule.Status = models.ScheduleCompleted
+		description := "rent"
+		s.scheduledTransferRepository.On("GetByID", "schedule-123").Return(schedule, nil).Once()
+
+		_, err := s.service.UpdateSchedule("acc-from", "schedule-123", &models.ScheduledTransferUpdate{Description: &description})
+
+		assert.ErrorIs(s.T(), err, services.ErrScheduleNotEditable)
+	})
+}
+
+// TestCancelSchedule tests the CancelSchedule function
+func (s *ScheduledTransferServiceTestSuite) TestCancelSchedule() {
+	now := time.Now().UTC()
+	s.scheduledTransferRepository.On("GetByID", "schedule-123").Return(activeSchedule(models.FrequencyDaily, now, now), nil).Once()
+	s.scheduledTransferRepository.On("Cancel", "schedule-123").Return(nil).Once()
+
+	err := s.service.CancelSchedule("acc-from", "schedule-123")
+
+	assert.NoError(s.T(), err)
+	s.scheduledTransferRepository.AssertExpectations(s.T())
+}
+
+// transfer stands in for a transfer failing with transferErr, a transfer that goes through runs the bookkeeping
+// of the caller in its transaction and fails with it
+func (s *ScheduledTransferServiceTestSuite) transfer(transferErr error) func(string, string, types.Money, func(repositories.Adapters) error) (*types.TransferResult, error) {
+	return func(_, _ string, _ types.Money, inTx func(repositories.Adapters) error) (*types.TransferResult, error) {
+		if transferErr != nil {
+			return nil, transferErr
+		}
+		if err := inTx(repositories.Adapters{ScheduledTransferRepository: s.scheduledTransferRepository}); err != nil {
+			return nil, err
+		}
+		return &types.TransferResult{}, nil
+	}
+}
+
+// runDue claims the given schedule, executes it with transferErr as the transfer outcome and returns the recorded execution
+func (s *ScheduledTransferServiceTestSuite) runDue(schedule *models.ScheduledTransfer, transferErr error) *models.ScheduledTransferExecution {
+	var recorded *models.ScheduledTransferExecution
+
+	s.scheduledTransferRepository.On("ClaimDue", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.Anything, mock.AnythingOfType("int")).
+		Return([]*models.ScheduledTransfer{schedule}, nil).Once()
+	s.accountService.On("TransferBetweenAccountsWith", "acc-from", "acc-to", schedule.Amount, mock.Anything).
+		Return(s.transfer(transferErr)).Once()
+	s.scheduledTransferRepository.On("RecordExecution", schedule, mock.AnythingOfType("*models.ScheduledTransferExecution")).
+		Run(func(args mock.Arguments) {
+			recorded = args.Get(1).(*models.ScheduledTransferExecution)
+		}).Return(nil).Once()
+
+	err := s.service.RunDueSchedules(context.Background())
+	assert.NoError(s.T(), err)
+	s.accountService.AssertExpectations(s.T())
+	s.scheduledTransferRepository.AssertExpectations(s.T())
+
+	return recorded
+}
+
+// TestRunDueSchedulesMonthly tests that a monthly schedule keeps its day of month, clamped to shorter months
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesMonthly() {
+	now := time.Now().UTC()
+	startAt := time.Date(now.Year()-1, time.January, 31, 9, 0, 0, 0, time.UTC)
+	schedule := activeSchedule(models.FrequencyMonthly, startAt, now.Add(-time.Minute))
+	scheduledFor := schedule.NextRunAt
+
+	execution := s.runDue(schedule, nil)
+
+	assert.Equal(s.T(), models.ExecutionSucceeded, execution.Status)
+	assert.Equal(s.T(), 1, execution.Attempt)
+	assert.True(s.T(), execution.ScheduledFor.Equal(scheduledFor))
+	assert.Equal(s.T(), models.ScheduleActive, schedule.Status)
+	assert.True(s.T(), schedule.NextRunAt.After(now))
+	assert.True(s.T(), schedule.NextRunAt.Before(now.AddDate(0, 1, 1)))
+	lastDay := time.Date(schedule.NextRunAt.Year(), schedule.NextRunAt.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	assert.Equal(s.T(), min(31, lastDay), schedule.NextRunAt.Day())
+	assert.Equal(s.T(), 9, schedule.NextRunAt.Hour())
+	assert.True(s.T(), schedule.NextAttemptAt.Equal(schedule.NextRunAt))
+	s.assertExecutionAudited(execution, "")
+}
+
+// assertExecutionAudited asserts the execution was audited as an action of the system with the given outcome
+func (s *ScheduledTransferServiceTestSuite) assertExecutionAudited(execution *models.ScheduledTransferExecution, outcome string) {
+	s.auditService.AssertCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == models.AuditScheduleExecute && entry.Outcome == outcome && entry.ActorType == models.AuditActorSystem &&
+			entry.ResourceType == "schedule" && entry.ResourceID == "schedule-123"
+	}), nil, mock.MatchedBy(func(after map[string]interface{}) bool {
+		return after["execution_id"] == execution.ExecutionID && after["status"] == execution.Status
+	}))
+}
+
+// TestRunDueSchedulesOnceCompletes tests that a one-off schedule completes after running
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesOnceCompletes() {
+	now := time.Now().UTC()
+	schedule := activeSchedule(models.FrequencyOnce, now.Add(-time.Minute), now.Add(-time.Minute))
+
+	execution := s.runDue(schedule, nil)
+
+	assert.Equal(s.T(), models.ExecutionSucceeded, execution.Status)
+	assert.Equal(s.T(), models.ScheduleCompleted, schedule.Status)
+}
+
+// TestRunDueSchedulesEndAtCompletes tests that a schedule completes when its next occurrence is past end_at
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesEndAtCompletes() {
+	now := time.Now().UTC()
+	schedule := activeSchedule(models.FrequencyWeekly, now.Add(-time.Minute), now.Add(-time.Minute))
+	endAt := now.Add(24 * time.Hour)
+	schedule.EndAt = &endAt
+
+	s.runDue(schedule, nil)
+
+	assert.Equal(s.T(), models.ScheduleCompleted, schedule.Status)
+}
+
+// TestRunDueSchedulesSkipOnInsufficientFunds tests the skip policy
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesSkipOnInsufficientFunds() {
+	now := time.Now().UTC()
+	schedule := activeSchedule(models.FrequencyDaily, now.Add(-time.Minute), now.Add(-time.Minute))
+
+	execution := s.runDue(schedule, services.ErrInsufficientFunds)
+
+	assert.Equal(s.T(), models.ExecutionSkipped, execution.Status)
+	assert.Equal(s.T(), services.ErrInsufficientFunds.Error(), execution.Message)
+	assert.Equal(s.T(), models.ScheduleActive, schedule.Status)
+	assert.Equal(s.T(), 0, schedule.RetryCount)
+	assert.True(s.T(), schedule.NextRunAt.Equal(now.Add(-time.Minute).AddDate(0, 0, 1)))
+	s.assertExecutionAudited(execution, models.AuditFailure)
+}
+
+// TestRunDueSchedulesRetryWithBackoff tests the retry policy backs off exponentially
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesRetryWithBackoff() {
+	now := time.Now().UTC()
+	nextRunAt := now.Add(-time.Minute)
+	schedule := activeSchedule(models.FrequencyDaily, nextRunAt, nextRunAt)
+	schedule.InsufficientFundsPolicy = models.RetryOnInsufficientFunds
+	schedule.RetryCount = 2
+
+	execution := s.runDue(schedule, services.ErrInsufficientFunds)
+
+	assert.Equal(s.T(), models.ExecutionRetrying, execution.Status)
+	assert.Equal(s.T(), 3, execution.Attempt)
+	assert.Equal(s.T(), 3, schedule.RetryCount)
+	assert.True(s.T(), schedule.NextRunAt.Equal(nextRunAt))
+	// Third attempt waits four times the base backoff
+	assert.WithinDuration(s.T(), now.Add(4*time.Minute), schedule.NextAttemptAt, 5*time.Second)
+}
+
+// TestRunDueSchedulesRetriesExhausted tests that a schedule moves on after the last retry fails
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesRetriesExhausted() {
+	now := time.Now().UTC()
+	nextRunAt := now.Add(-time.Hour)
+	schedule := activeSchedule(models.FrequencyDaily, nextRunAt, nextRunAt)
+	schedule.RetryCount = 5
+
+	execution := s.runDue(schedule, errors.New("database error"))
+
+	assert.Equal(s.T(), models.ExecutionFailed, execution.Status)
+	assert.Equal(s.T(), 6, execution.Attempt)
+	assert.Equal(s.T(), 0, schedule.RetryCount)
+	assert.True(s.T(), schedule.NextRunAt.Equal(nextRunAt.AddDate(0, 0, 1)))
+}
+
+// TestRunDueSchedulesPermanentFailure tests that errors a retry cannot fix are not retried
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesPermanentFailure() {
+	now := time.Now().UTC()
+	schedule := activeSchedule(models.FrequencyDaily, now.Add(-time.Minute), now.Add(-time.Minute))
+
+	execution := s.runDue(schedule, sql.ErrNoRows)
+
+	assert.Equal(s.T(), models.ExecutionFailed, execution.Status)
+	assert.Equal(s.T(), 1, execution.Attempt)
+}
+
+// TestRunDueSchedulesClaimError tests that a failed claim executes nothing
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesClaimError() {
+	s.scheduledTransferRepository.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("database error")).Once()
+
+	err := s.service.RunDueSchedules(context.Background())
+
+	assert.EqualError(s.T(), err, "database error")
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccountsWith", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRunDueSchedulesLeaseLost tests that losing the lease rolls the transfer back without stopping the batch
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesLeaseLost() {
+	now := time.Now().UTC()
+	first := activeSchedule(models.FrequencyDaily, now.Add(-time.Minute), now.Add(-time.Minute))
+	second := activeSchedule(models.FrequencyDaily, now.Add(-time.Minute), now.Add(-time.Minute))
+	second.ScheduleID = "schedule-456"
+
+	s.scheduledTransferRepository.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*models.ScheduledTransfer{first, second}, nil).Once()
+	s.accountService.On("TransferBetweenAccountsWith", "acc-from", "acc-to", first.Amount, mock.Anything).Return(s.transfer(nil)).Twice()
+	s.scheduledTransferRepository.On("RecordExecution", first, mock.Anything).Return(repositories.ErrScheduleLeaseLost).Once()
+	s.scheduledTransferRepository.On("RecordExecution", second, mock.Anything).Return(nil).Once()
+
+	err := s.service.RunDueSchedules(context.Background())
+
+	assert.NoError(s.T(), err)
+	s.scheduledTransferRepository.AssertExpectations(s.T())
+	// The transfer of the first schedule was rolled back with its bookkeeping, nothing else is recorded for it
+	s.scheduledTransferRepository.AssertNumberOfCalls(s.T(), "RecordExecution", 2)
+	s.auditService.AssertNotCalled(s.T(), "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.ResourceID == "schedule-123"
+	}), mock.Anything, mock.Anything)
+}
+
+// TestRunDueSchedulesStopsWhenCancelled tests that no schedule is executed after the context is cancelled
+func (s *ScheduledTransferServiceTestSuite) TestRunDueSchedulesStopsWhenCancelled() {
+	now := time.Now().UTC()
+	schedule := activeSchedule(models.FrequencyDaily, now, now)
+	s.scheduledTransferRepository.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*models.ScheduledTransfer{schedule}, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := s.service.RunDueSchedules(ctx)
+
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	s.accountService.AssertNotCalled(s.T(), "TransferBetweenAccountsWith", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestScheduledTransferServiceSuite runs the test suite
+func TestScheduledTransferServiceSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledTransferServiceTestSuite))
+}
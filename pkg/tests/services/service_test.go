@@ -18,6 +18,16 @@ func TestInitService(t *testing.T) {
 	mockDebitCardRepo := new(mockRepo.DebitCardRepository)
 	mockAccountRepo := new(mockRepo.AccountRepository)
 	mockBannerRepo := new(mockRepo.BannerRepository)
+	mockLedgerRepo := new(mockRepo.LedgerRepository)
+	mockScheduledTransferRepo := new(mockRepo.ScheduledTransferRepository)
+	mockTransferLimitRepo := new(mockRepo.TransferLimitRepository)
+	mockFXRepo := new(mockRepo.FXRepository)
+	mockHoldRepo := new(mockRepo.HoldRepository)
+	mockRefreshTokenRepo := new(mockRepo.RefreshTokenRepository)
+	mockPinLockoutRepo := new(mockRepo.PinLockoutRepository)
+	mockPinRepo := new(mockRepo.PinRepository)
+	mockChallengeRepo := new(mockRepo.ChallengeRepository)
+	mockTOTPRepo := new(mockRepo.TOTPRepository)
 	mockTxProvider := new(mockRepo.TxProvider)
 
 	// Create mock redis client
@@ -25,22 +35,46 @@ func TestInitService(t *testing.T) {
 
 	// Create repository struct with mocks
 	repo := &repositories.Repository{
-		UserRepository:        mockUserRepo,
-		TransactionRepository: mockTransactionRepo,
-		DebitCardRepository:   mockDebitCardRepo,
-		AccountRepository:     mockAccountRepo,
-		BannerRepository:      mockBannerRepo,
+		UserRepository:              mockUserRepo,
+		TransactionRepository:       mockTransactionRepo,
+		DebitCardRepository:         mockDebitCardRepo,
+		AccountRepository:           mockAccountRepo,
+		BannerRepository:            mockBannerRepo,
+		LedgerRepository:            mockLedgerRepo,
+		ScheduledTransferRepository: mockScheduledTransferRepo,
+		TransferLimitRepository:     mockTransferLimitRepo,
+		FXRepository:                mockFXRepo,
+		HoldRepository:              mockHoldRepo,
+		RefreshTokenRepository:      mockRefreshTokenRepo,
+		PinLockoutRepository:        mockPinLockoutRepo,
+		PinRepository:               mockPinRepo,
+		ChallengeRepository:         mockChallengeRepo,
+		TOTPRepository:              mockTOTPRepo,
 	}
 	// Initialize service
 	service := services.InitService(repo, mockTxProvider, mockRedisClient)
 
 	// Assert that all services are initialized
 	assert.NotNil(t, service)
+	assert.NotNil(t, service.AuthService)
 	assert.NotNil(t, service.UserService)
+	assert.NotNil(t, service.PinLockoutService)
+	assert.NotNil(t, service.PinService)
 	assert.NotNil(t, service.TransactionService)
 	assert.NotNil(t, service.DebitCardService)
 	assert.NotNil(t, service.AccountService)
 	assert.NotNil(t, service.BannerService)
+	assert.NotNil(t, service.LedgerService)
+	assert.NotNil(t, service.ScheduledTransferService)
+	assert.NotNil(t, service.TransferLimitService)
+	assert.NotNil(t, service.FXService)
+	assert.NotNil(t, service.TOTPService)
+	assert.NotNil(t, service.ChallengeService)
+	assert.NotNil(t, service.AuditService)
+	assert.NotNil(t, service.OutboxService)
+	assert.NotNil(t, service.WebhookService)
+	assert.NotNil(t, service.StreamService)
+	assert.NotNil(t, service.StatementService)
 
 	// Verify that the services are initialized with the correct dependencies
 	// This is a bit tricky since we can't directly access the private fields
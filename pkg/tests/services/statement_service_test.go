@@ -0,0 +1,216 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/configs"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// updateGolden rewrites the golden files with the current output: go test ./pkg/tests/services -run Statement -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the statement tests")
+
+// StatementServiceTestSuite is a test suite for StatementService
+type StatementServiceTestSuite struct {
+	suite.Suite
+	accountRepository     *mocks.AccountRepository
+	transactionRepository *mocks.TransactionRepository
+	ledgerService         *mockServices.LedgerService
+	service               services.StatementService
+}
+
+// SetupTest sets up the test suite
+func (s *StatementServiceTestSuite) SetupTest() {
+	s.accountRepository = new(mocks.AccountRepository)
+	s.transactionRepository = new(mocks.TransactionRepository)
+	s.ledgerService = new(mockServices.LedgerService)
+	s.service = services.NewStatementService(s.accountRepository, s.transactionRepository, s.ledgerService)
+}
+
+func statementDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, configs.STATEMENT_LOCATION)
+}
+
+func statementTransaction(id string, createdAt time.Time, transactionType models.TransactionType, direction models.PostingDirection, name string, amount int64) *models.Transaction {
+	return &models.Transaction{
+		BaseModel:       &models.BaseModel{CreatedAt: createdAt},
+		TransactionID:   id,
+		AccountID:       "acc-123",
+		Name:            name,
+		Amount:          types.NewMoney(amount, "THB"),
+		TransactionType: string(transactionType),
+		Direction:       direction,
+	}
+}
+
+// septemberStatement is the statement the golden files are written from
+func septemberStatement() *models.Statement {
+	return &models.Statement{
+		AccountID:      "acc-123",
+		AccountNumber:  "123-4-56789-0",
+		Currency:       "THB",
+		From:           statementDay(2026, time.September, 1),
+		To:             statementDay(2026, time.September, 30),
+		OpeningBalance: types.NewMoney(100000, "THB"),
+	}
+}
+
+// checkGolden compares the output with testdata/<name>, or rewrites it with -update
+func (s *StatementServiceTestSuite) checkGolden(name string, output []byte) {
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		s.Require().NoError(os.MkdirAll("testdata", 0o755))
+		s.Require().NoError(os.WriteFile(path, output, 0o644))
+	}
+
+	golden, err := os.ReadFile(path)
+	s.Require().NoError(err, "run the test with -update to create the golden file")
+	assert.Equal(s.T(), string(golden), string(output), "output differs from %s, rerun with -update if the change is intended", path)
+}
+
+// TestWriteStatementGolden tests every format against its golden file
+func (s *StatementServiceTestSuite) TestWriteStatementGolden() {
+	statement := septemberStatement()
+	transactions := []*models.Transaction{
+		statementTransaction("txn-1", time.Date(2026, time.September, 2, 3, 15, 0, 0, time.UTC), models.Deposit, models.Credit, "Salary", 5000000),
+		statementTransaction("txn-2", time.Date(2026, time.September, 5, 12, 0, 0, 0, time.UTC), models.Transfer, models.Debit, "Transfer to 987-6-54321-0", 1250050),
+		statementTransaction("txn-3", time.Date(2026, time.September, 12, 18, 30, 0, 0, time.UTC), models.Withdrawal, models.Debit, "=HYPERLINK(\"http://example.com\")", 20000),
+		statementTransaction("txn-4", time.Date(2026, time.September, 20, 9, 45, 0, 0, time.UTC), models.Withdrawal, models.Debit, "Café ร้านกาแฟ (Siam), a description longer than its column", 8900),
+		statementTransaction("txn-5", time.Date(2026, time.September, 29, 17, 0, 0, 0, time.UTC), models.Reversal, models.Credit, "Reversal of card payment", 8900),
+	}
+	s.transactionRepository.On("GetByAccountIDInPeriod", "acc-123", statement.From, statement.End(), (*models.TransactionCursor)(nil), configs.STATEMENT_PAGE_SIZE).
+		Return(transactions, nil)
+
+	for _, format := range []models.StatementFormat{models.StatementCSV, models.StatementJSON, models.StatementPDF} {
+		s.Run(string(format), func() {
+			statement := septemberStatement()
+			var output bytes.Buffer
+			s.Require().NoError(s.service.WriteStatement(&output, statement, format))
+
+			assert.Equal(s.T(), "38299.50", statement.ClosingBalance.Decimal())
+			s.checkGolden("statement."+string(format)+".golden", output.Bytes())
+		})
+	}
+}
+
+// TestWriteStatementPages tests that transactions are read a page at a time, each page after the last one
+func (s *StatementServiceTestSuite) TestWriteStatementPages() {
+	statement := septemberStatement()
+	start := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	firstPage := make([]*models.Transaction, configs.STATEMENT_PAGE_SIZE)
+	for i := range firstPage {
+		firstPage[i] = statementTransaction(fmt.Sprintf("txn-%04d", i), start.Add(time.Duration(i)*time.Minute), models.Deposit, models.Credit, "Deposit", 100)
+	}
+	last := firstPage[len(firstPage)-1]
+	secondPage := []*models.Transaction{
+		statementTransaction("txn-last", last.CreatedAt, models.Withdrawal, models.Debit, "Withdrawal", 50),
+	}
+
+	s.transactionRepository.On("GetByAccountIDInPeriod", "acc-123", statement.From, statement.End(), (*models.TransactionCursor)(nil), configs.STATEMENT_PAGE_SIZE).
+		Return(firstPage, nil).Twice()
+	s.transactionRepository.On("GetByAccountIDInPeriod", "acc-123", statement.From, statement.End(),
+		&models.TransactionCursor{CreatedAt: last.CreatedAt, TransactionID: last.TransactionID}, configs.STATEMENT_PAGE_SIZE).
+		Return(secondPage, nil).Twice()
+
+	var output bytes.Buffer
+	s.Require().NoError(s.service.WriteStatement(&output, statement, models.StatementJSON))
+
+	var document struct {
+		Transactions   []models.StatementLine `json:"transactions"`
+		ClosingBalance types.Money            `json:"closing_balance"`
+	}
+	s.Require().NoError(json.Unmarshal(output.Bytes(), &document))
+	assert.Len(s.T(), document.Transactions, configs.STATEMENT_PAGE_SIZE+1)
+	assert.Equal(s.T(), "1499.50", document.ClosingBalance.Decimal())
+	assert.Equal(s.T(), document.ClosingBalance, document.Transactions[len(document.Transactions)-1].Balance)
+
+	// The PDF table runs over several pages
+	output.Reset()
+	s.Require().NoError(s.service.WriteStatement(&output, septemberStatement(), models.StatementPDF))
+	assert.Contains(s.T(), output.String(), "/Count 8")
+	assert.Contains(s.T(), output.String(), "(Page 8)")
+	s.transactionRepository.AssertExpectations(s.T())
+}
+
+// TestWriteStatementEmpty tests that a period without transactions closes at its opening balance
+func (s *StatementServiceTestSuite) TestWriteStatementEmpty() {
+	statement := septemberStatement()
+	s.transactionRepository.On("GetByAccountIDInPeriod", "acc-123", statement.From, statement.End(), mock.Anything, configs.STATEMENT_PAGE_SIZE).
+		Return([]*models.Transaction{}, nil).Once()
+
+	var output bytes.Buffer
+	s.Require().NoError(s.service.WriteStatement(&output, statement, models.StatementJSON))
+
+	assert.JSONEq(s.T(), `{"account_id":"acc-123","account_number":"123-4-56789-0","currency":"THB","from":"2026-09-01","to":"2026-09-30",
+		"opening_balance":{"amount":"1000.00","currency":"THB"},"transactions":[],"closing_balance":{"amount":"1000.00","currency":"THB"}}`, output.String())
+}
+
+// TestWriteStatementUnknownFormat tests that nothing is written in an unknown format
+func (s *StatementServiceTestSuite) TestWriteStatementUnknownFormat() {
+	var output bytes.Buffer
+	err := s.service.WriteStatement(&output, septemberStatement(), "xlsx")
+
+	assert.ErrorIs(s.T(), err, services.ErrStatementFormat)
+	assert.Zero(s.T(), output.Len())
+}
+
+// TestOpenStatement tests that the period covers whole days in the statement time zone
+func (s *StatementServiceTestSuite) TestOpenStatement() {
+	from := statementDay(2026, time.September, 1)
+	s.accountRepository.On("GetAccountByID", "acc-123").
+		Return(&models.Account{AccountID: "acc-123", AccountNumber: "123-4-56789-0", Currency: "THB"}, nil).Once()
+	s.ledgerService.On("GetBalanceAt", "acc-123", from.Add(-time.Nanosecond)).Return(types.NewMoney(100000, "THB"), nil).Once()
+
+	// 2026-08-31 20:00 UTC is already September 1st in Bangkok
+	statement, err := s.service.OpenStatement("acc-123", time.Date(2026, time.August, 31, 20, 0, 0, 0, time.UTC), statementDay(2026, time.September, 30).Add(23*time.Hour))
+
+	s.Require().NoError(err)
+	assert.True(s.T(), from.Equal(statement.From))
+	assert.True(s.T(), statementDay(2026, time.October, 1).Equal(statement.End()))
+	assert.Equal(s.T(), "123-4-56789-0", statement.AccountNumber)
+	assert.Equal(s.T(), "1000.00", statement.OpeningBalance.Decimal())
+}
+
+// TestOpenStatementErrors tests an inverted period and a missing account
+func (s *StatementServiceTestSuite) TestOpenStatementErrors() {
+	_, err := s.service.OpenStatement("acc-123", statementDay(2026, time.September, 30), statementDay(2026, time.September, 1))
+	assert.ErrorIs(s.T(), err, services.ErrStatementPeriod)
+
+	s.accountRepository.On("GetAccountByID", "acc-404").Return(nil, sql.ErrNoRows).Once()
+	_, err = s.service.OpenStatement("acc-404", statementDay(2026, time.September, 1), statementDay(2026, time.September, 30))
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+	s.ledgerService.AssertNotCalled(s.T(), "GetBalanceAt", mock.Anything, mock.Anything)
+}
+
+// TestOpenStatementNoLedgerHistory tests that an account never posted to opens at zero
+func (s *StatementServiceTestSuite) TestOpenStatementNoLedgerHistory() {
+	s.accountRepository.On("GetAccountByID", "acc-123").
+		Return(&models.Account{AccountID: "acc-123", AccountNumber: "123-4-56789-0", Currency: "THB"}, nil).Once()
+	s.ledgerService.On("GetBalanceAt", "acc-123", mock.Anything).Return(types.Money{}, sql.ErrNoRows).Once()
+
+	statement, err := s.service.OpenStatement("acc-123", statementDay(2026, time.September, 1), statementDay(2026, time.September, 30))
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), types.NewMoney(0, "THB"), statement.OpeningBalance)
+}
+
+// TestStatementServiceSuite runs the test suite
+func TestStatementServiceSuite(t *testing.T) {
+	suite.Run(t, new(StatementServiceTestSuite))
+}
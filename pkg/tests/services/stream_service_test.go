@@ -0,0 +1,237 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/configs"
+	mockServices "backend-developer-assignment/pkg/mocks/services"
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// StreamServiceTestSuite is a test suite for StreamService
+type StreamServiceTestSuite struct {
+	suite.Suite
+	broker  *services.MemoryStreamBroker
+	service services.StreamService
+}
+
+// SetupTest sets up the test suite
+func (s *StreamServiceTestSuite) SetupTest() {
+	s.broker = services.NewMemoryStreamBroker()
+	s.service = services.NewStreamService(s.broker)
+}
+
+func streamEvent(eventType models.EventType, payload interface{}) *models.DomainEvent {
+	data, _ := json.Marshal(payload)
+	return &models.DomainEvent{EventID: "event-1", EventType: eventType, Payload: data, OccurredAt: time.Now()}
+}
+
+func depositEvent(userID, accountID string, balance int64) *models.DomainEvent {
+	return streamEvent(models.EventFundsDeposited, models.FundsMovedPayload{
+		AccountID:     accountID,
+		UserID:        userID,
+		TransactionID: "txn-" + accountID,
+		Amount:        types.NewMoney(1000, "THB"),
+		Balance:       types.NewMoney(balance, "THB"),
+	})
+}
+
+// receive returns the next message of the subscription, nil when the subscription ended
+func (s *StreamServiceTestSuite) receive(messages <-chan *models.StreamMessage) *models.StreamMessage {
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(time.Second):
+		s.FailNow("no stream message received")
+		return nil
+	}
+}
+
+func (s *StreamServiceTestSuite) subscribe(userID, lastEventID string) <-chan *models.StreamMessage {
+	messages, closeSubscription, err := s.service.Subscribe(context.Background(), userID, lastEventID)
+	s.Require().NoError(err)
+	s.T().Cleanup(closeSubscription)
+	return messages
+}
+
+// TestPublishTransfer tests that both sides of a transfer receive their transaction and new balance
+func (s *StreamServiceTestSuite) TestPublishTransfer() {
+	sender := s.subscribe("user-1", "")
+	recipient := s.subscribe("user-2", "")
+
+	err := s.service.Publish(context.Background(), streamEvent(models.EventTransferCompleted, models.TransferCompletedPayload{
+		FromAccountID:       "acc-1",
+		FromUserID:          "user-1",
+		ToAccountID:         "acc-2",
+		ToUserID:            "user-2",
+		DebitTransactionID:  "txn-debit",
+		CreditTransactionID: "txn-credit",
+		Amount:              types.NewMoney(10000, "THB"),
+		CreditedAmount:      types.NewMoney(10000, "THB"),
+		SourceBalance:       types.NewMoney(90000, "THB"),
+		DestinationBalance:  types.NewMoney(60000, "THB"),
+	}))
+	s.Require().NoError(err)
+
+	message := s.receive(sender)
+	assert.Equal(s.T(), models.StreamTransaction, message.Event)
+	var transaction models.TransactionNotice
+	s.Require().NoError(json.Unmarshal(message.Data, &transaction))
+	assert.Equal(s.T(), "txn-debit", transaction.TransactionID)
+	assert.Equal(s.T(), models.Debit, transaction.Direction)
+	assert.Equal(s.T(), "acc-2", transaction.CounterpartyAccountID)
+
+	message = s.receive(sender)
+	assert.Equal(s.T(), models.StreamBalance, message.Event)
+	var balance models.BalanceUpdate
+	s.Require().NoError(json.Unmarshal(message.Data, &balance))
+	assert.Equal(s.T(), "acc-1", balance.AccountID)
+	assert.Equal(s.T(), "900.00", balance.Balance.Decimal())
+
+	message = s.receive(recipient)
+	s.Require().NoError(json.Unmarshal(message.Data, &transaction))
+	assert.Equal(s.T(), "txn-credit", transaction.TransactionID)
+	assert.Equal(s.T(), models.Credit, transaction.Direction)
+	message = s.receive(recipient)
+	s.Require().NoError(json.Unmarshal(message.Data, &balance))
+	assert.Equal(s.T(), "acc-2", balance.AccountID)
+	assert.Equal(s.T(), "600.00", balance.Balance.Decimal())
+}
+
+// TestPublishIgnoresOtherEvents tests that card events and unreadable payloads push nothing
+func (s *StreamServiceTestSuite) TestPublishIgnoresOtherEvents() {
+	messages := s.subscribe("user-1", "")
+
+	assert.NoError(s.T(), s.service.Publish(context.Background(), streamEvent(models.EventCardStatusChanged, models.CardStatusChangedPayload{UserID: "user-1"})))
+	assert.NoError(s.T(), s.service.Publish(context.Background(), &models.DomainEvent{EventType: models.EventFundsDeposited, Payload: json.RawMessage(`[]`)}))
+
+	history, err := s.broker.History(context.Background(), "user-1", "0-0")
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), history)
+	assert.Empty(s.T(), messages)
+}
+
+// TestResume tests that the messages after the last event id are replayed before live ones
+func (s *StreamServiceTestSuite) TestResume() {
+	s.Require().NoError(s.service.Publish(context.Background(), depositEvent("user-1", "acc-1", 1000)))
+	s.Require().NoError(s.service.Publish(context.Background(), depositEvent("user-1", "acc-2", 2000)))
+	history, err := s.broker.History(context.Background(), "user-1", "0-0")
+	s.Require().NoError(err)
+	s.Require().Len(history, 4)
+
+	messages := s.subscribe("user-1", history[1].ID)
+	s.Require().NoError(s.service.Publish(context.Background(), depositEvent("user-1", "acc-3", 3000)))
+
+	assert.Equal(s.T(), history[2].ID, s.receive(messages).ID)
+	assert.Equal(s.T(), history[3].ID, s.receive(messages).ID)
+	live := s.receive(messages)
+	assert.Equal(s.T(), models.StreamTransaction, live.Event)
+	assert.Equal(s.T(), 1, models.CompareStreamIDs(live.ID, history[3].ID))
+	assert.Equal(s.T(), models.StreamBalance, s.receive(messages).Event)
+}
+
+// TestResumeAfterGap tests that a last event id that is no longer kept resets the client
+func (s *StreamServiceTestSuite) TestResumeAfterGap() {
+	for i := 0; i < configs.STREAM_HISTORY_SIZE; i++ {
+		s.Require().NoError(s.service.Publish(context.Background(), depositEvent("user-1", "acc-1", int64(i))))
+	}
+	history, err := s.broker.History(context.Background(), "user-1", "0-0")
+	s.Require().NoError(err)
+	s.Require().Len(history, configs.STREAM_HISTORY_SIZE)
+
+	message := s.receive(s.subscribe("user-1", "1-0"))
+	assert.Equal(s.T(), models.StreamReset, message.Event)
+	assert.Equal(s.T(), history[len(history)-1].ID, message.ID, "the client resumes from the latest message")
+
+	message = s.receive(s.subscribe("user-1", "not-an-id"))
+	assert.Equal(s.T(), models.StreamReset, message.Event)
+
+	message = s.receive(s.subscribe("user-2", history[0].ID))
+	assert.Equal(s.T(), models.StreamReset, message.Event)
+	assert.Empty(s.T(), message.ID, "nothing is kept for the user")
+}
+
+// TestSlowSubscriberIsDisconnected tests that a subscriber falling behind is ended instead of missing messages
+func (s *StreamServiceTestSuite) TestSlowSubscriberIsDisconnected() {
+	messages := s.subscribe("user-1", "")
+
+	for i := 0; i < 2*configs.STREAM_HISTORY_SIZE; i++ {
+		s.Require().NoError(s.service.Publish(context.Background(), depositEvent("user-1", "acc-1", int64(i))))
+	}
+
+	received := 0
+	for range messages {
+		received++
+	}
+	assert.Less(s.T(), received, 4*configs.STREAM_HISTORY_SIZE)
+}
+
+// TestClose tests that closing ends the subscriptions and refuses new ones
+func (s *StreamServiceTestSuite) TestClose() {
+	messages := s.subscribe("user-1", "")
+
+	s.service.Close()
+
+	_, ok := <-messages
+	assert.False(s.T(), ok)
+	_, _, err := s.service.Subscribe(context.Background(), "user-1", "")
+	assert.ErrorIs(s.T(), err, services.ErrStreamClosed)
+}
+
+// TestRedisStreamBroker tests that messages published on one instance reach the subscribers of another
+func (s *StreamServiceTestSuite) TestRedisStreamBroker() {
+	client := new(mockServices.PubSubClient)
+	var feeds []chan string
+	client.On("SubscribeChannel", mock.Anything, configs.STREAM_CHANNEL).Return(func(context.Context, string) (<-chan string, error) {
+		feed := make(chan string, 10)
+		feeds = append(feeds, feed)
+		return feed, nil
+	})
+	client.On("AddToStream", mock.Anything, "stream:user-1", int64(configs.STREAM_HISTORY_SIZE), mock.MatchedBy(func(values map[string]interface{}) bool {
+		return values["event"] == "balance" && values["data"] == `{"account_id":"acc-1"}`
+	})).Return("1760000000000-0", nil).Once()
+	client.On("Expire", mock.Anything, "stream:user-1", configs.STREAM_HISTORY_TTL).Return(nil).Once()
+	client.On("PublishMessage", mock.Anything, configs.STREAM_CHANNEL, mock.Anything).Run(func(args mock.Arguments) {
+		for _, feed := range feeds {
+			feed <- args.String(2)
+		}
+	}).Return(nil).Once()
+	client.On("ReadStream", mock.Anything, "stream:user-1", "1760000000000-0", int64(configs.STREAM_HISTORY_SIZE+1)).
+		Return([]types.StreamEntry{{ID: "1760000000000-0", Values: map[string]interface{}{"event": "balance", "data": `{"account_id":"acc-1"}`}}}, nil).Once()
+
+	subscriberInstance := services.NewRedisStreamBroker(client, configs.STREAM_CHANNEL)
+	publisherInstance := services.NewRedisStreamBroker(client, configs.STREAM_CHANNEL)
+	defer subscriberInstance.Close()
+	defer publisherInstance.Close()
+
+	messages, unsubscribe, err := subscriberInstance.Subscribe("user-1")
+	s.Require().NoError(err)
+	defer unsubscribe()
+
+	message := &models.StreamMessage{Event: models.StreamBalance, Data: json.RawMessage(`{"account_id":"acc-1"}`)}
+	s.Require().NoError(publisherInstance.Publish(context.Background(), "user-1", message))
+	assert.Equal(s.T(), "1760000000000-0", message.ID)
+
+	received := s.receive(messages)
+	assert.Equal(s.T(), "1760000000000-0", received.ID)
+	assert.JSONEq(s.T(), `{"account_id":"acc-1"}`, string(received.Data))
+
+	history, err := publisherInstance.History(context.Background(), "user-1", "1760000000000-0")
+	assert.NoError(s.T(), err)
+	s.Require().Len(history, 1)
+	assert.Equal(s.T(), models.StreamBalance, history[0].Event)
+	client.AssertExpectations(s.T())
+}
+
+// TestStreamServiceSuite runs the test suite
+func TestStreamServiceSuite(t *testing.T) {
+	suite.Run(t, new(StreamServiceTestSuite))
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/utils"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// TOTPServiceTestSuite is a test suite for TOTPService
+type TOTPServiceTestSuite struct {
+	suite.Suite
+	totpRepository *mocks.TOTPRepository
+	service        services.TOTPService
+}
+
+const (
+	totpUserID = "user-123"
+	totpSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+)
+
+// SetupTest sets up the test suite
+func (s *TOTPServiceTestSuite) SetupTest() {
+	s.totpRepository = new(mocks.TOTPRepository)
+	s.service = services.NewTOTPService(s.totpRepository)
+}
+
+// currentCode returns the code an authenticator app shows now and its time step
+func (s *TOTPServiceTestSuite) currentCode() (string, int64) {
+	step := utils.TOTPStep(time.Now())
+	code, err := utils.TOTPCode(totpSecret, step)
+	s.Require().NoError(err)
+	return code, step
+}
+
+// TestEnroll tests that a new secret is stored and returned with its otpauth URI
+func (s *TOTPServiceTestSuite) TestEnroll() {
+	var stored *models.UserTOTP
+	s.totpRepository.On("GetByUserID", totpUserID).Return(nil, sql.ErrNoRows).Once()
+	s.totpRepository.On("Save", mock.AnythingOfType("*models.UserTOTP")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.UserTOTP) }).Return(nil).Once()
+
+	enrollment, err := s.service.Enroll(totpUserID)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), enrollment.Secret, 32)
+	assert.Equal(s.T(), stored.Secret, enrollment.Secret)
+	assert.True(s.T(), strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+	assert.Contains(s.T(), enrollment.URI, "secret="+enrollment.Secret)
+}
+
+// TestEnrollWhenEnabled tests that an enabled authenticator is not replaced
+func (s *TOTPServiceTestSuite) TestEnrollWhenEnabled() {
+	enabledAt := time.Now()
+	s.totpRepository.On("GetByUserID", totpUserID).Return(&models.UserTOTP{UserID: totpUserID, Secret: totpSecret, EnabledAt: &enabledAt}, nil).Once()
+
+	_, err := s.service.Enroll(totpUserID)
+
+	assert.ErrorIs(s.T(), err, services.ErrTOTPAlreadyEnabled)
+	s.totpRepository.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+// TestEnable tests that a valid code enables the secret
+func (s *TOTPServiceTestSuite) TestEnable() {
+	code, step := s.currentCode()
+	s.totpRepository.On("GetByUserID", totpUserID).Return(&models.UserTOTP{UserID: totpUserID, Secret: totpSecret}, nil).Once()
+	s.totpRepository.On("MarkUsedStep", totpUserID, step).Return(true, nil).Once()
+	s.totpRepository.On("Enable", totpUserID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	err := s.service.Enable(totpUserID, code)
+
+	assert.NoError(s.T(), err)
+	s.totpRepository.AssertExpectations(s.T())
+}
+
+// TestEnableInvalidCode tests that a wrong code does not enable the secret
+func (s *TOTPServiceTestSuite) TestEnableInvalidCode() {
+	code, _ := s.currentCode()
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "000001"
+	}
+	s.totpRepository.On("GetByUserID", totpUserID).Return(&models.UserTOTP{UserID: totpUserID, Secret: totpSecret}, nil).Once()
+
+	err := s.service.Enable(totpUserID, wrongCode)
+
+	assert.ErrorIs(s.T(), err, services.ErrInvalidTOTP)
+	s.totpRepository.AssertNotCalled(s.T(), "Enable", mock.Anything, mock.Anything)
+}
+
+// TestVerify tests codes of an enabled authenticator
+func (s *TOTPServiceTestSuite) TestVerify() {
+	enabledAt := time.Now().Add(-time.Hour)
+	enabled := &models.UserTOTP{UserID: totpUserID, Secret: totpSecret, EnabledAt: &enabledAt}
+
+	s.Run("Valid code", func() {
+		s.SetupTest()
+		code, step := s.currentCode()
+		s.totpRepository.On("GetByUserID", totpUserID).Return(enabled, nil).Once()
+		s.totpRepository.On("MarkUsedStep", totpUserID, step).Return(true, nil).Once()
+
+		assert.NoError(s.T(), s.service.Verify(totpUserID, code))
+	})
+
+	s.Run("Code of the previous step", func() {
+		s.SetupTest()
+		step := utils.TOTPStep(time.Now()) - 1
+		code, err := utils.TOTPCode(totpSecret, step)
+		s.Require().NoError(err)
+		s.totpRepository.On("GetByUserID", totpUserID).Return(enabled, nil).Once()
+		s.totpRepository.On("MarkUsedStep", totpUserID, step).Return(true, nil).Once()
+
+		assert.NoError(s.T(), s.service.Verify(totpUserID, code))
+	})
+
+	s.Run("Replayed code", func() {
+		s.SetupTest()
+		code, step := s.currentCode()
+		s.totpRepository.On("GetByUserID", totpUserID).Return(enabled, nil).Once()
+		s.totpRepository.On("MarkUsedStep", totpUserID, step).Return(false, nil).Once()
+
+		assert.ErrorIs(s.T(), s.service.Verify(totpUserID, code), services.ErrInvalidTOTP)
+	})
+
+	s.Run("Not enabled", func() {
+		s.SetupTest()
+		code, _ := s.currentCode()
+		s.totpRepository.On("GetByUserID", totpUserID).Return(&models.UserTOTP{UserID: totpUserID, Secret: totpSecret}, nil).Once()
+
+		assert.ErrorIs(s.T(), s.service.Verify(totpUserID, code), services.ErrTOTPNotEnabled)
+		s.totpRepository.AssertNotCalled(s.T(), "MarkUsedStep", mock.Anything, mock.Anything)
+	})
+}
+
+// TestTOTPServiceSuite runs the test suite
+func TestTOTPServiceSuite(t *testing.T) {
+	suite.Run(t, new(TOTPServiceTestSuite))
+}
@@ -0,0 +1,534 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/app/services"
+	mockCache "backend-developer-assignment/pkg/mocks/cache"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// TransactionReversalTestSuite tests TransactionService.ReverseTransaction and TransactionService.ReturnTransfer
+type TransactionReversalTestSuite struct {
+	suite.Suite
+	transactionRepository *mocks.TransactionRepository
+	accountRepository     *mocks.AccountRepository
+	ledgerRepository      *mocks.LedgerRepository
+	holdRepository        *mocks.HoldRepository
+	outboxRepository      *mocks.OutboxRepository
+	txProvider            *mocks.TxProvider
+	redisClient           *mockCache.RedisClient
+	service               services.TransactionService
+	frozen                map[string]bool  // accounts frozen by staff
+	held                  map[string]int64 // funds held per account
+}
+
+// SetupTest runs before each test
+func (s *TransactionReversalTestSuite) SetupTest() {
+	s.transactionRepository = new(mocks.TransactionRepository)
+	s.accountRepository = new(mocks.AccountRepository)
+	s.ledgerRepository = new(mocks.LedgerRepository)
+	s.holdRepository = new(mocks.HoldRepository)
+	s.outboxRepository = new(mocks.OutboxRepository)
+	s.txProvider = new(mocks.TxProvider)
+	s.frozen = map[string]bool{}
+	s.held = map[string]int64{}
+	s.redisClient = new(mockCache.RedisClient)
+	s.service = services.NewTransactionService(s.transactionRepository, s.txProvider, s.redisClient)
+
+	// Run the transaction function against the repository mocks and return its error
+	s.txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+		Return(func(txFunc func(repositories.Adapters) error) error {
+			return txFunc(repositories.Adapters{
+				AccountRepository:     s.accountRepository,
+				TransactionRepository: s.transactionRepository,
+				LedgerRepository:      s.ledgerRepository,
+				HoldRepository:        s.holdRepository,
+				OutboxRepository:      s.outboxRepository,
+			})
+		})
+	s.redisClient.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	s.redisClient.On("InvalidateNamespace", mock.Anything, mock.Anything).Return(nil)
+
+	// Accounts are open and nothing is held unless a test sets frozen or held
+	s.accountRepository.On("GetAccountWithDetailByID", mock.Anything).
+		Return(func(accountID string) *models.AccountWithDetails {
+			account := &models.AccountWithDetails{AccountID: accountID}
+			if s.frozen[accountID] {
+				account.Flags = []*models.AccountFlag{{FlagType: models.AccountFlagSystem, FlagValue: models.AccountFlagFrozen}}
+			}
+			return account
+		}, nil).Maybe()
+	s.holdRepository.On("GetHeldAmount", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(accountID, currency string, now time.Time) types.Money {
+			return types.NewMoney(s.held[accountID], currency)
+		}, nil).Maybe()
+	s.outboxRepository.On("Create", mock.AnythingOfType("*models.DomainEvent")).Return(nil).Maybe()
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// expectLegs expects the reversed transaction to be read and its legs to be locked
+func (s *TransactionReversalTestSuite) expectLegs(original *models.Transaction, legs ...*models.Transaction) {
+	s.transactionRepository.On("GetByID", original.TransactionID).Return(original, nil).Once()
+	for _, leg := range append([]*models.Transaction{original}, legs...) {
+		s.transactionRepository.On("GetByIDForUpdate", leg.TransactionID).Return(leg, nil).Once()
+	}
+}
+
+func depositTransaction(amount, reversed int64) *models.Transaction {
+	return &models.Transaction{
+		BaseModel:       &models.BaseModel{},
+		TransactionID:   "tx-deposit",
+		UserID:          "user-123",
+		AccountID:       "acc-123",
+		Name:            "Deposit",
+		Amount:          types.NewMoney(amount, "THB"),
+		TransactionType: string(models.Deposit),
+		Direction:       models.Credit,
+		ReversedAmount:  types.NewMoney(reversed, "THB"),
+	}
+}
+
+// TestReverseDepositFully tests reversing the whole deposit
+func (s *TransactionReversalTestSuite) TestReverseDepositFully() {
+	original := depositTransaction(10000, 0)
+	s.expectLegs(original)
+	s.accountRepository.On("UpdateAccountBalance", "acc-123", mock.AnythingOfType("func(types.Money) (types.Money, error)")).
+		Run(func(args mock.Arguments) {
+			updateFn := args.Get(1).(func(types.Money) (types.Money, error))
+			balance, err := updateFn(types.NewMoney(25000, "THB"))
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), types.NewMoney(15000, "THB"), balance)
+		}).Return(nil).Once()
+	s.transactionRepository.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.TransactionType == string(models.Reversal) &&
+			tx.Direction == models.Debit &&
+			*tx.ReversalOf == "tx-deposit" &&
+			tx.Amount == types.NewMoney(10000, "THB")
+	})).Return(nil).Once()
+	s.transactionRepository.On("Update", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.TransactionID == "tx-deposit" && tx.ReversedAmount == types.NewMoney(10000, "THB")
+	})).Return(nil).Once()
+	s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+		return entry.EntryType == models.ReversalEntry &&
+			entry.Postings[0].AccountID == "acc-123" && entry.Postings[0].Direction == models.Debit &&
+			entry.Postings[1].AccountID == models.LedgerExternalAccountPrefix+"THB"
+	})).Return(nil).Once()
+
+	result, err := s.service.ReverseTransaction("tx-deposit", nil)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), original, result.Original)
+	assert.Len(s.T(), result.Reversals, 1)
+	s.transactionRepository.AssertExpectations(s.T())
+	s.accountRepository.AssertExpectations(s.T())
+	s.ledgerRepository.AssertExpectations(s.T())
+}
+
+// TestReverseRejected tests the cases where nothing may be reversed
+func (s *TransactionReversalTestSuite) TestReverseRejected() {
+	reversal := depositTransaction(10000, 0)
+	reversal.ReversalOf = strPtr("tx-other")
+	unlinkedTransfer := depositTransaction(10000, 0)
+	unlinkedTransfer.TransactionType = string(models.Transfer)
+
+	testCases := []struct {
+		name          string
+		original      *models.Transaction
+		findError     error
+		amount        *types.Money
+		expectedError error
+	}{
+		{name: "Failure - Not Found", findError: sql.ErrNoRows, expectedError: services.ErrTransactionNotFound},
+		{name: "Failure - Already Reversed", original: depositTransaction(10000, 10000), expectedError: services.ErrTransactionAlreadyReversed},
+		{name: "Failure - Refund Exceeds Remaining", original: depositTransaction(10000, 6000), amount: &types.Money{Amount: 5000, Currency: "THB"}, expectedError: services.ErrReversalExceedsAmount},
+		{name: "Failure - Reversal Of Reversal", original: reversal, expectedError: services.ErrTransactionNotReversible},
+		{name: "Failure - Transfer Without Linked Leg", original: unlinkedTransfer, expectedError: services.ErrTransactionNotReversible},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.original != nil {
+				s.expectLegs(tc.original)
+			} else {
+				s.transactionRepository.On("GetByID", "tx-deposit").Return(nil, tc.findError).Once()
+			}
+
+			result, err := s.service.ReverseTransaction("tx-deposit", tc.amount)
+
+			assert.Equal(s.T(), tc.expectedError, err)
+			assert.Nil(s.T(), result)
+			s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+			s.accountRepository.AssertNotCalled(s.T(), "UpdateAccountBalance", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestReversePartialRefund tests refunding exactly what is left after an earlier partial refund
+func (s *TransactionReversalTestSuite) TestReversePartialRefund() {
+	original := depositTransaction(10000, 4000)
+	refund := types.NewMoney(6000, "THB")
+	s.expectLegs(original)
+	s.accountRepository.On("UpdateAccountBalance", "acc-123", mock.Anything).Return(nil).Once()
+	s.transactionRepository.On("Create", mock.Anything).Return(nil).Once()
+	s.transactionRepository.On("Update", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.ReversedAmount == types.NewMoney(10000, "THB")
+	})).Return(nil).Once()
+	s.ledgerRepository.On("PostEntry", mock.Anything).Return(nil).Once()
+
+	result, err := s.service.ReverseTransaction("tx-deposit", &refund)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), refund, result.Reversals[0].Amount)
+	s.transactionRepository.AssertExpectations(s.T())
+}
+
+// TestReverseDepositInsufficientFunds tests that a deposit that was already spent cannot be reversed
+func (s *TransactionReversalTestSuite) TestReverseDepositInsufficientFunds() {
+	s.expectLegs(depositTransaction(10000, 0))
+	s.accountRepository.On("UpdateAccountBalance", "acc-123", mock.AnythingOfType("func(types.Money) (types.Money, error)")).
+		Return(func(accountID string, updateFn func(types.Money) (types.Money, error)) error {
+			_, err := updateFn(types.NewMoney(5000, "THB"))
+			return err
+		}).Once()
+
+	result, err := s.service.ReverseTransaction("tx-deposit", nil)
+
+	assert.Equal(s.T(), services.ErrInsufficientFunds, err)
+	assert.Nil(s.T(), result)
+	s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// transferLegs returns the legs of a transfer of 100.00 THB from user-123 to user-456
+func transferLegs() (*models.Transaction, *models.Transaction) {
+	sourceLeg := &models.Transaction{
+		BaseModel:           &models.BaseModel{},
+		TransactionID:       "tx-source",
+		UserID:              "user-123",
+		AccountID:           "acc-source",
+		Name:                "Transfer to 222",
+		Amount:              types.NewMoney(10000, "THB"),
+		TransactionType:     string(models.Transfer),
+		Direction:           models.Debit,
+		LinkedTransactionID: strPtr("tx-dest"),
+		ReversedAmount:      types.NewMoney(0, "THB"),
+	}
+	destLeg := &models.Transaction{
+		BaseModel:           &models.BaseModel{},
+		TransactionID:       "tx-dest",
+		UserID:              "user-456",
+		AccountID:           "acc-dest",
+		Name:                "Transfer from 111",
+		Amount:              types.NewMoney(10000, "THB"),
+		TransactionType:     string(models.Transfer),
+		Direction:           models.Credit,
+		LinkedTransactionID: strPtr("tx-source"),
+		ReversedAmount:      types.NewMoney(0, "THB"),
+	}
+	return sourceLeg, destLeg
+}
+
+// TestReverseTransfer tests that both legs of a transfer are reversed
+func (s *TransactionReversalTestSuite) TestReverseTransfer() {
+	sourceLeg, destLeg := transferLegs()
+	s.expectLegs(sourceLeg, destLeg)
+	s.accountRepository.On("TransferFunds", "acc-dest", "acc-source", types.NewMoney(10000, "THB"),
+		mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
+		Run(func(args mock.Arguments) {
+			updateFn := args.Get(3).(func(types.Money, types.Money) (*types.TransferResult, error))
+			result, err := updateFn(types.NewMoney(30000, "THB"), types.NewMoney(0, "THB"))
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), types.NewMoney(20000, "THB"), result.SourceBalance)
+			assert.Equal(s.T(), types.NewMoney(10000, "THB"), result.DestinationBalance)
+		}).Return(nil).Once()
+	s.transactionRepository.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.AccountID == "acc-dest" && tx.Direction == models.Debit && *tx.ReversalOf == "tx-dest"
+	})).Return(nil).Once()
+	s.transactionRepository.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.AccountID == "acc-source" && tx.Direction == models.Credit && *tx.ReversalOf == "tx-source"
+	})).Return(nil).Once()
+	s.transactionRepository.On("Update", mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.ReversedAmount == types.NewMoney(10000, "THB")
+	})).Return(nil).Twice()
+	s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+		return entry.Postings[0].AccountID == "acc-dest" && entry.Postings[0].Direction == models.Debit &&
+			entry.Postings[1].AccountID == "acc-source" && entry.Postings[1].Direction == models.Credit
+	})).Return(nil).Once()
+
+	result, err := s.service.ReverseTransaction("tx-source", nil)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Reversals, 2)
+	assert.Equal(s.T(), result.Reversals[1].TransactionID, *result.Reversals[0].LinkedTransactionID)
+	s.transactionRepository.AssertExpectations(s.T())
+	s.accountRepository.AssertExpectations(s.T())
+	s.ledgerRepository.AssertExpectations(s.T())
+
+	// Each leg publishes its reversal with the balance of its own account
+	for accountID, direction := range map[string]models.PostingDirection{"acc-dest": models.Debit, "acc-source": models.Credit} {
+		s.outboxRepository.AssertCalled(s.T(), "Create", mock.MatchedBy(func(event *models.DomainEvent) bool {
+			var payload models.TransactionReversedPayload
+			return event.EventType == models.EventTransactionReversed && event.AggregateID == accountID &&
+				json.Unmarshal(event.Payload, &payload) == nil && payload.Direction == direction
+		}))
+	}
+}
+
+// TestReverseTransferLockOrder tests that the legs of a transfer are locked in the same order whichever leg is
+// reversed, so that reversals of both legs at once cannot deadlock
+func (s *TransactionReversalTestSuite) TestReverseTransferLockOrder() {
+	for _, transactionID := range []string{"tx-source", "tx-dest"} {
+		s.Run(transactionID, func() {
+			s.SetupTest()
+			sourceLeg, destLeg := transferLegs()
+			legs := map[string]*models.Transaction{"tx-source": sourceLeg, "tx-dest": destLeg}
+			var locked []string
+			s.transactionRepository.On("GetByID", transactionID).Return(legs[transactionID], nil).Once()
+			s.transactionRepository.On("GetByIDForUpdate", mock.Anything).Return(func(id string) (*models.Transaction, error) {
+				locked = append(locked, id)
+				return legs[id], nil
+			}).Twice()
+			s.accountRepository.On("TransferFunds", "acc-dest", "acc-source", types.NewMoney(10000, "THB"), mock.Anything).Return(nil).Once()
+			s.transactionRepository.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil).Twice()
+			s.transactionRepository.On("Update", mock.AnythingOfType("*models.Transaction")).Return(nil).Twice()
+			s.ledgerRepository.On("PostEntry", mock.Anything).Return(nil).Once()
+
+			_, err := s.service.ReverseTransaction(transactionID, nil)
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), []string{"tx-dest", "tx-source"}, locked)
+		})
+	}
+}
+
+// TestReturnTransfer tests that the recipient of a transfer can send it back to the sender
+func (s *TransactionReversalTestSuite) TestReturnTransfer() {
+	sourceLeg, destLeg := transferLegs()
+	refund := types.NewMoney(4000, "THB")
+	s.expectLegs(destLeg, sourceLeg)
+	s.accountRepository.On("TransferFunds", "acc-dest", "acc-source", refund, mock.Anything).Return(nil).Once()
+	s.transactionRepository.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil).Twice()
+	s.transactionRepository.On("Update", mock.AnythingOfType("*models.Transaction")).Return(nil).Twice()
+	s.ledgerRepository.On("PostEntry", mock.Anything).Return(nil).Once()
+
+	result, err := s.service.ReturnTransfer("user-456", "tx-dest", &refund)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Reversals, 2)
+	assert.Equal(s.T(), refund, destLeg.ReversedAmount)
+	s.accountRepository.AssertExpectations(s.T())
+}
+
+// TestReturnTransferRejected tests that customers can only return the transfers they received
+func (s *TransactionReversalTestSuite) TestReturnTransferRejected() {
+	sourceLeg, destLeg := transferLegs()
+	withdrawal := depositTransaction(10000, 0)
+	withdrawal.TransactionType = string(models.Withdrawal)
+	withdrawal.Direction = models.Debit
+
+	testCases := []struct {
+		name          string
+		original      *models.Transaction
+		userID        string
+		expectedError error
+	}{
+		{name: "Failure - Other User", original: destLeg, userID: "user-123", expectedError: services.ErrTransactionNotFound},
+		{name: "Failure - Deposit", original: depositTransaction(10000, 0), userID: "user-123", expectedError: services.ErrTransferNotReturnable},
+		{name: "Failure - Withdrawal", original: withdrawal, userID: "user-123", expectedError: services.ErrTransferNotReturnable},
+		{name: "Failure - Transfer Sent", original: sourceLeg, userID: "user-123", expectedError: services.ErrTransferNotReturnable},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.transactionRepository.On("GetByID", "tx-1").Return(tc.original, nil).Once()
+			s.transactionRepository.On("GetByIDForUpdate", mock.Anything).Return(tc.original, nil)
+
+			result, err := s.service.ReturnTransfer(tc.userID, "tx-1", nil)
+
+			assert.Equal(s.T(), tc.expectedError, err)
+			assert.Nil(s.T(), result)
+			s.accountRepository.AssertNotCalled(s.T(), "UpdateAccountBalance", mock.Anything, mock.Anything)
+			s.accountRepository.AssertNotCalled(s.T(), "TransferFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestReverseFrozenAccount tests that no leg of a reversal moves money on a frozen account
+func (s *TransactionReversalTestSuite) TestReverseFrozenAccount() {
+	for _, accountID := range []string{"acc-source", "acc-dest"} {
+		s.Run(accountID, func() {
+			s.SetupTest()
+			s.frozen[accountID] = true
+			sourceLeg, destLeg := transferLegs()
+			s.expectLegs(sourceLeg, destLeg)
+
+			result, err := s.service.ReverseTransaction("tx-source", nil)
+
+			assert.Equal(s.T(), services.ErrAccountFrozen, err)
+			assert.Nil(s.T(), result)
+			s.accountRepository.AssertNotCalled(s.T(), "TransferFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	s.Run("Deposit", func() {
+		s.SetupTest()
+		s.frozen["acc-123"] = true
+		s.expectLegs(depositTransaction(10000, 0))
+
+		_, err := s.service.ReverseTransaction("tx-deposit", nil)
+
+		assert.Equal(s.T(), services.ErrAccountFrozen, err)
+		s.accountRepository.AssertNotCalled(s.T(), "UpdateAccountBalance", mock.Anything, mock.Anything)
+	})
+}
+
+// TestReverseHeldFunds tests that a reversal cannot take funds held on the account it debits
+func (s *TransactionReversalTestSuite) TestReverseHeldFunds() {
+	s.Run("Deposit", func() {
+		s.SetupTest()
+		s.held["acc-123"] = 20000
+		s.expectLegs(depositTransaction(10000, 0))
+		s.accountRepository.On("UpdateAccountBalance", "acc-123", mock.AnythingOfType("func(types.Money) (types.Money, error)")).
+			Return(func(accountID string, updateFn func(types.Money) (types.Money, error)) error {
+				_, err := updateFn(types.NewMoney(25000, "THB"))
+				return err
+			}).Once()
+
+		_, err := s.service.ReverseTransaction("tx-deposit", nil)
+
+		assert.Equal(s.T(), services.ErrInsufficientFunds, err)
+		s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+	})
+
+	s.Run("Transfer", func() {
+		s.SetupTest()
+		s.held["acc-dest"] = 5000
+		sourceLeg, destLeg := transferLegs()
+		s.expectLegs(destLeg, sourceLeg)
+		s.accountRepository.On("TransferFunds", "acc-dest", "acc-source", types.NewMoney(10000, "THB"),
+			mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
+			Return(func(fromAccountID, toAccountID string, amount types.Money, updateFn func(types.Money, types.Money) (*types.TransferResult, error)) error {
+				_, err := updateFn(types.NewMoney(12000, "THB"), types.NewMoney(0, "THB"))
+				return err
+			}).Once()
+
+		_, err := s.service.ReturnTransfer("user-456", "tx-dest", nil)
+
+		assert.Equal(s.T(), services.ErrInsufficientFunds, err)
+		s.transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+	})
+}
+
+// TestReverseCrossCurrencyTransfer tests that each leg of a converted transfer is refunded in its own currency
+func (s *TransactionReversalTestSuite) TestReverseCrossCurrencyTransfer() {
+	rate := types.MustParseRate("0.0274")
+	newLegs := func(reversedTHB, reversedUSD int64) (*models.Transaction, *models.Transaction) {
+		sourceLeg := &models.Transaction{
+			BaseModel:           &models.BaseModel{},
+			TransactionID:       "tx-source",
+			UserID:              "user-123",
+			AccountID:           "acc-thb",
+			Name:                "Transfer to 222",
+			Amount:              types.NewMoney(100000, "THB"),
+			TransactionType:     string(models.Transfer),
+			Direction:           models.Debit,
+			LinkedTransactionID: strPtr("tx-dest"),
+			ReversedAmount:      types.NewMoney(reversedTHB, "THB"),
+			ExchangeRate:        &rate,
+		}
+		destLeg := &models.Transaction{
+			BaseModel:           &models.BaseModel{},
+			TransactionID:       "tx-dest",
+			UserID:              "user-123",
+			AccountID:           "acc-usd",
+			Name:                "Transfer from 111",
+			Amount:              types.NewMoney(2740, "USD"),
+			TransactionType:     string(models.Transfer),
+			Direction:           models.Credit,
+			LinkedTransactionID: strPtr("tx-source"),
+			ReversedAmount:      types.NewMoney(reversedUSD, "USD"),
+			ExchangeRate:        &rate,
+		}
+		return sourceLeg, destLeg
+	}
+
+	testCases := []struct {
+		name        string
+		reversedTHB int64
+		reversedUSD int64
+		refund      *types.Money
+		expectedTHB types.Money
+		expectedUSD types.Money
+	}{
+		{
+			name:        "Partial refund is proportional",
+			refund:      &types.Money{Amount: 33333, Currency: "THB"},
+			expectedTHB: types.NewMoney(33333, "THB"),
+			expectedUSD: types.NewMoney(913, "USD"),
+		},
+		{
+			name:        "Last refund returns what is left of both legs",
+			reversedTHB: 33333,
+			reversedUSD: 913,
+			expectedTHB: types.NewMoney(66667, "THB"),
+			expectedUSD: types.NewMoney(1827, "USD"),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			sourceLeg, destLeg := newLegs(tc.reversedTHB, tc.reversedUSD)
+
+			s.expectLegs(sourceLeg, destLeg)
+			s.accountRepository.On("TransferFunds", "acc-usd", "acc-thb", tc.expectedUSD,
+				mock.AnythingOfType("func(types.Money, types.Money) (*types.TransferResult, error)")).
+				Run(func(args mock.Arguments) {
+					updateFn := args.Get(3).(func(types.Money, types.Money) (*types.TransferResult, error))
+					result, err := updateFn(types.NewMoney(5000, "USD"), types.NewMoney(0, "THB"))
+					assert.NoError(s.T(), err)
+					assert.Equal(s.T(), types.NewMoney(5000-tc.expectedUSD.Amount, "USD"), result.SourceBalance)
+					assert.Equal(s.T(), tc.expectedTHB, result.DestinationBalance)
+				}).Return(nil).Once()
+			s.transactionRepository.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+				return tx.AccountID == "acc-usd" && tx.Amount == tc.expectedUSD && tx.ExchangeRate == &rate
+			})).Return(nil).Once()
+			s.transactionRepository.On("Create", mock.MatchedBy(func(tx *models.Transaction) bool {
+				return tx.AccountID == "acc-thb" && tx.Amount == tc.expectedTHB
+			})).Return(nil).Once()
+			s.transactionRepository.On("Update", mock.AnythingOfType("*models.Transaction")).Return(nil).Twice()
+			s.ledgerRepository.On("PostEntry", mock.MatchedBy(func(entry *models.JournalEntry) bool {
+				return len(entry.Postings) == 4 &&
+					entry.Postings[0].AccountID == "acc-usd" && entry.Postings[0].Amount == tc.expectedUSD &&
+					entry.Postings[3].AccountID == "acc-thb" && entry.Postings[3].Amount == tc.expectedTHB
+			})).Return(nil).Once()
+
+			_, err := s.service.ReverseTransaction("tx-source", tc.refund)
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), types.NewMoney(tc.reversedTHB+tc.expectedTHB.Amount, "THB"), sourceLeg.ReversedAmount)
+			assert.Equal(s.T(), types.NewMoney(tc.reversedUSD+tc.expectedUSD.Amount, "USD"), destLeg.ReversedAmount)
+			s.transactionRepository.AssertExpectations(s.T())
+			s.accountRepository.AssertExpectations(s.T())
+			s.ledgerRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+func TestTransactionReversalSuite(t *testing.T) {
+	suite.Run(t, new(TransactionReversalTestSuite))
+}
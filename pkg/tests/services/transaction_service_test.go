@@ -6,6 +6,7 @@ import (
 	"backend-developer-assignment/pkg/configs"
 	mockCache "backend-developer-assignment/pkg/mocks/cache"
 	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
 	"context"
 	"encoding/json"
 	"errors"
@@ -24,6 +25,7 @@ import (
 type TransactionServiceTestSuite struct {
 	suite.Suite
 	transactionRepository *mocks.TransactionRepository
+	txProvider            *mocks.TxProvider
 	redisClient           *mockCache.RedisClient
 	service               services.TransactionService
 	ctx                   context.Context
@@ -32,8 +34,9 @@ type TransactionServiceTestSuite struct {
 // SetupTest runs before each test
 func (s *TransactionServiceTestSuite) SetupTest() {
 	s.transactionRepository = new(mocks.TransactionRepository)
+	s.txProvider = new(mocks.TxProvider)
 	s.redisClient = new(mockCache.RedisClient)
-	s.service = services.NewTransactionService(s.transactionRepository, s.redisClient)
+	s.service = services.NewTransactionService(s.transactionRepository, s.txProvider, s.redisClient)
 	s.ctx = context.Background()
 }
 
@@ -48,7 +51,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionByIDCacheHit() {
 		Name:            "Test Transaction",
 		Image:           "transaction.jpg",
 		IsBank:          true,
-		Amount:          100.50,
+		Amount:          types.NewMoney(10050, "THB"),
 		TransactionType: "deposit",
 		BaseModel: &models.BaseModel{
 			CreatedAt: now,
@@ -93,7 +96,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionByIDCacheMiss() {
 		Name:            "Test Transaction",
 		Image:           "transaction.jpg",
 		IsBank:          true,
-		Amount:          100.50,
+		Amount:          types.NewMoney(10050, "THB"),
 		TransactionType: "deposit",
 		BaseModel: &models.BaseModel{
 			CreatedAt: now,
@@ -168,7 +171,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionByIDCacheUnmarshalError(
 		Name:            "Test Transaction",
 		Image:           "transaction.jpg",
 		IsBank:          true,
-		Amount:          100.50,
+		Amount:          types.NewMoney(10050, "THB"),
 		TransactionType: "deposit",
 		BaseModel: &models.BaseModel{
 			CreatedAt: now,
@@ -215,7 +218,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDCacheHit() {
 			Name:            "Transaction 1",
 			Image:           "transaction1.jpg",
 			IsBank:          true,
-			Amount:          100.50,
+			Amount:          types.NewMoney(10050, "THB"),
 			TransactionType: "deposit",
 			BaseModel: &models.BaseModel{
 				CreatedAt: now,
@@ -228,7 +231,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDCacheHit() {
 			Name:            "Transaction 2",
 			Image:           "transaction2.jpg",
 			IsBank:          false,
-			Amount:          200.75,
+			Amount:          types.NewMoney(20075, "THB"),
 			TransactionType: "withdrawal",
 			BaseModel: &models.BaseModel{
 				CreatedAt: now,
@@ -241,8 +244,11 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDCacheHit() {
 	cachedData, _ := json.Marshal(expectedTransactions)
 	cachedCount := fmt.Sprintf("%d", expectedCount)
 
-	cacheKey := fmt.Sprintf("transactions:user:%s:page:%d", userID, page)
-	countCacheKey := fmt.Sprintf("transactions:user:%s:count", userID)
+	cacheKey := fmt.Sprintf("transactions:user:%s:v7:page:%d", userID, page)
+	countCacheKey := fmt.Sprintf("transactions:user:%s:v7:count", userID)
+
+	// The pages are cached under the current version of the user's listings
+	s.redisClient.On("NamespaceVersion", s.ctx, "transactions:user:"+userID).Return("7", nil).Once()
 
 	// Mock Redis cache hit
 	s.redisClient.On("Get", s.ctx, cacheKey).Return(string(cachedData), nil).Once()
@@ -280,7 +286,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDCacheMiss() {
 			Name:            "Transaction 1",
 			Image:           "transaction1.jpg",
 			IsBank:          true,
-			Amount:          100.50,
+			Amount:          types.NewMoney(10050, "THB"),
 			TransactionType: "deposit",
 			BaseModel: &models.BaseModel{
 				CreatedAt: now,
@@ -293,7 +299,7 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDCacheMiss() {
 			Name:            "Transaction 2",
 			Image:           "transaction2.jpg",
 			IsBank:          false,
-			Amount:          200.75,
+			Amount:          types.NewMoney(20075, "THB"),
 			TransactionType: "withdrawal",
 			BaseModel: &models.BaseModel{
 				CreatedAt: now,
@@ -302,8 +308,11 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDCacheMiss() {
 		},
 	}
 
-	cacheKey := fmt.Sprintf("transactions:user:%s:page:%d", userID, page)
-	countCacheKey := fmt.Sprintf("transactions:user:%s:count", userID)
+	cacheKey := fmt.Sprintf("transactions:user:%s:v7:page:%d", userID, page)
+	countCacheKey := fmt.Sprintf("transactions:user:%s:v7:count", userID)
+
+	// The pages are cached under the current version of the user's listings
+	s.redisClient.On("NamespaceVersion", s.ctx, "transactions:user:"+userID).Return("7", nil).Once()
 
 	// Mock Redis cache miss
 	s.redisClient.On("Get", s.ctx, cacheKey).Return("", errors.New("cache miss")).Once()
@@ -342,8 +351,11 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDDatabaseError()
 	orderBy := "created_at desc"
 	expectedError := errors.New("database connection failed")
 
-	cacheKey := fmt.Sprintf("transactions:user:%s:page:%d", userID, page)
-	countCacheKey := fmt.Sprintf("transactions:user:%s:count", userID)
+	cacheKey := fmt.Sprintf("transactions:user:%s:v7:page:%d", userID, page)
+	countCacheKey := fmt.Sprintf("transactions:user:%s:v7:count", userID)
+
+	// The pages are cached under the current version of the user's listings
+	s.redisClient.On("NamespaceVersion", s.ctx, "transactions:user:"+userID).Return("7", nil).Once()
 
 	// Mock Redis cache miss
 	s.redisClient.On("Get", s.ctx, cacheKey).Return("", errors.New("cache miss")).Once()
@@ -367,6 +379,125 @@ func (s *TransactionServiceTestSuite) TestGetTransactionsByUserIDDatabaseError()
 	s.transactionRepository.AssertExpectations(s.T())
 }
 
+func searchedTransactions(count int) []*models.Transaction {
+	start := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	transactions := make([]*models.Transaction, count)
+	for i := range transactions {
+		transactions[i] = &models.Transaction{
+			BaseModel:     &models.BaseModel{CreatedAt: start.Add(-time.Duration(i) * time.Minute)},
+			TransactionID: fmt.Sprintf("txn-%d", i),
+			UserID:        "user-123",
+			Amount:        types.NewMoney(10000, "THB"),
+		}
+	}
+	return transactions
+}
+
+// TestSearchTransactionsPages tests that the cursor of a page continues after its last transaction
+func (s *TransactionServiceTestSuite) TestSearchTransactionsPages() {
+	filter := models.TransactionFilter{UserID: "user-123", TransactionType: "deposit", Limit: 2}
+	transactions := searchedTransactions(3)
+
+	s.transactionRepository.On("Search", models.TransactionFilter{UserID: "user-123", TransactionType: "deposit", Sort: models.SortNewest, Limit: 3}).
+		Return(transactions, nil).Once()
+
+	page, cursor, err := s.service.SearchTransactions(filter, "")
+	s.Require().NoError(err)
+	assert.Len(s.T(), page, 2)
+	assert.NotEmpty(s.T(), cursor)
+
+	s.transactionRepository.On("Search", models.TransactionFilter{UserID: "user-123", TransactionType: "deposit", Sort: models.SortNewest, Limit: 3,
+		After: &models.TransactionCursor{CreatedAt: transactions[1].CreatedAt, TransactionID: "txn-1"}}).
+		Return(transactions[2:], nil).Once()
+
+	page, cursor, err = s.service.SearchTransactions(filter, cursor)
+	s.Require().NoError(err)
+	assert.Len(s.T(), page, 1)
+	assert.Empty(s.T(), cursor, "the last page has no next cursor")
+
+	s.redisClient.AssertNotCalled(s.T(), "Get", mock.Anything, mock.Anything)
+	s.transactionRepository.AssertExpectations(s.T())
+}
+
+// TestSearchTransactionsPageSize tests the default and the largest page size
+func (s *TransactionServiceTestSuite) TestSearchTransactionsPageSize() {
+	s.transactionRepository.On("Search", mock.MatchedBy(func(filter models.TransactionFilter) bool { return filter.Limit == configs.DEFAULT_PAGE_SIZE+1 })).
+		Return([]*models.Transaction{}, nil).Once()
+	s.transactionRepository.On("Search", mock.MatchedBy(func(filter models.TransactionFilter) bool { return filter.Limit == configs.MAX_PAGE_SIZE+1 })).
+		Return([]*models.Transaction{}, nil).Once()
+
+	_, _, err := s.service.SearchTransactions(models.TransactionFilter{UserID: "user-123"}, "")
+	s.Require().NoError(err)
+	_, _, err = s.service.SearchTransactions(models.TransactionFilter{UserID: "user-123", Limit: 1000}, "")
+	s.Require().NoError(err)
+
+	s.transactionRepository.AssertExpectations(s.T())
+}
+
+// TestSearchTransactionsInvalidCursor tests that a malformed cursor, or one of another sort, is refused
+func (s *TransactionServiceTestSuite) TestSearchTransactionsInvalidCursor() {
+	s.transactionRepository.On("Search", mock.Anything).Return(searchedTransactions(2), nil).Once()
+	_, cursor, err := s.service.SearchTransactions(models.TransactionFilter{UserID: "user-123", Limit: 1}, "")
+	s.Require().NoError(err)
+
+	_, _, err = s.service.SearchTransactions(models.TransactionFilter{UserID: "user-123", Sort: models.SortOldest}, cursor)
+	assert.ErrorIs(s.T(), err, services.ErrInvalidTransactionCursor)
+
+	_, _, err = s.service.SearchTransactions(models.TransactionFilter{UserID: "user-123"}, "not a cursor")
+	assert.ErrorIs(s.T(), err, services.ErrInvalidTransactionCursor)
+
+	s.transactionRepository.AssertNumberOfCalls(s.T(), "Search", 1)
+}
+
+// TestGetAccountTransactionsCache tests that a page is cached under the version of the account's listings
+func (s *TransactionServiceTestSuite) TestGetAccountTransactionsCache() {
+	filter := models.TransactionFilter{AccountID: "acc-123", Limit: 2}
+	transactions := searchedTransactions(3)
+	namespace := "transactions:account:acc-123"
+	isPageKey := func(version string) interface{} {
+		return mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "transactions:account:acc-123:v"+version+":") })
+	}
+
+	// No transaction since the cache started, the listing is at version 0
+	s.redisClient.On("NamespaceVersion", s.ctx, namespace).Return("0", nil).Once()
+	s.redisClient.On("Get", s.ctx, isPageKey("0")).Return("", types.ErrCacheMiss).Once()
+	s.transactionRepository.On("SearchByAccountID", models.TransactionFilter{AccountID: "acc-123", Sort: models.SortNewest, Limit: 3}).
+		Return(transactions, nil).Once()
+	var cached []byte
+	s.redisClient.On("Set", s.ctx, isPageKey("0"), mock.Anything, services.TransactionListCacheDuration).
+		Run(func(args mock.Arguments) { cached = args.Get(2).([]byte) }).Return(nil).Once()
+
+	page, cursor, err := s.service.GetAccountTransactions(filter, "")
+	s.Require().NoError(err)
+	assert.Len(s.T(), page, 2)
+	assert.NotEmpty(s.T(), cursor)
+
+	// The same page is read from the cache
+	s.redisClient.On("NamespaceVersion", s.ctx, namespace).Return("0", nil).Once()
+	s.redisClient.On("Get", s.ctx, isPageKey("0")).Return(string(cached), nil).Once()
+
+	cachedPage, cachedCursor, err := s.service.GetAccountTransactions(filter, "")
+	s.Require().NoError(err)
+	assert.Len(s.T(), cachedPage, 2)
+	assert.Equal(s.T(), cursor, cachedCursor)
+
+	s.redisClient.AssertExpectations(s.T())
+	s.transactionRepository.AssertNumberOfCalls(s.T(), "SearchByAccountID", 1)
+}
+
+// TestGetAccountTransactionsCacheUnavailable tests that nothing is cached without the version of the listings
+func (s *TransactionServiceTestSuite) TestGetAccountTransactionsCacheUnavailable() {
+	s.redisClient.On("NamespaceVersion", s.ctx, "transactions:account:acc-123").Return("", errors.New("connection refused")).Once()
+	s.transactionRepository.On("SearchByAccountID", mock.Anything).Return(searchedTransactions(1), nil).Once()
+
+	page, cursor, err := s.service.GetAccountTransactions(models.TransactionFilter{AccountID: "acc-123"}, "")
+
+	s.Require().NoError(err)
+	assert.Len(s.T(), page, 1)
+	assert.Empty(s.T(), cursor)
+	s.redisClient.AssertNotCalled(s.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // TestCreateTransactionWithExistingID tests the CreateTransaction function with an existing ID
 func (s *TransactionServiceTestSuite) TestCreateTransactionWithExistingID() {
 	now := time.Now().Truncate(time.Second)
@@ -379,8 +510,9 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionWithExistingID() {
 		Name:            "Test Transaction",
 		Image:           "transaction.jpg",
 		IsBank:          true,
-		Amount:          100.50,
+		Amount:          types.NewMoney(10050, "THB"),
 		TransactionType: "deposit",
+		Direction:       models.Credit,
 		BaseModel: &models.BaseModel{
 			CreatedAt: now,
 			UpdatedAt: now,
@@ -390,9 +522,8 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionWithExistingID() {
 	// Mock repository call
 	s.transactionRepository.On("Create", transaction).Return(nil).Once()
 
-	// Mock cache operations
-	userTransactionsPattern := fmt.Sprintf("transactions:user:%s:*", userID)
-	s.redisClient.On("Delete", s.ctx, strings.TrimSuffix(userTransactionsPattern, "*")).Return(nil).Once()
+	// The cached listings of the user move to a new version
+	s.redisClient.On("InvalidateNamespace", s.ctx, "transactions:user:"+userID).Return(nil).Once()
 
 	// Mock cache set for the new transaction
 	cacheKey := fmt.Sprintf("transaction:%s", transactionID)
@@ -422,7 +553,7 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionWithGeneratedID() {
 		Name:            "Test Transaction",
 		Image:           "transaction.jpg",
 		IsBank:          true,
-		Amount:          100.50,
+		Amount:          types.NewMoney(10050, "THB"),
 		TransactionType: "deposit",
 		BaseModel: &models.BaseModel{
 			CreatedAt: now,
@@ -436,12 +567,11 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionWithGeneratedID() {
 		return t.TransactionID != "" &&
 			t.UserID == userID &&
 			t.Name == "Test Transaction" &&
-			t.Amount == 100.50
+			t.Amount == types.NewMoney(10050, "THB")
 	})).Return(nil).Once()
 
-	// Mock cache operations
-	userTransactionsPattern := fmt.Sprintf("transactions:user:%s:*", userID)
-	s.redisClient.On("Delete", s.ctx, strings.TrimSuffix(userTransactionsPattern, "*")).Return(nil).Once()
+	// The cached listings of the user move to a new version
+	s.redisClient.On("InvalidateNamespace", s.ctx, "transactions:user:"+userID).Return(nil).Once()
 
 	// Mock cache set for the new transaction (with any ID)
 	s.redisClient.On("Set", s.ctx, mock.MatchedBy(func(key string) bool {
@@ -475,7 +605,7 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionDatabaseError() {
 		Name:            "Test Transaction",
 		Image:           "transaction.jpg",
 		IsBank:          true,
-		Amount:          100.50,
+		Amount:          types.NewMoney(10050, "THB"),
 		TransactionType: "deposit",
 		BaseModel: &models.BaseModel{
 			CreatedAt: now,
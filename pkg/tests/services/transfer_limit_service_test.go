@@ -0,0 +1,226 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/repositories"
+	"backend-developer-assignment/app/services"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// TransferLimitServiceTestSuite is a test suite for TransferLimitService
+type TransferLimitServiceTestSuite struct {
+	suite.Suite
+	transferLimitRepository *mocks.TransferLimitRepository
+	service                 services.TransferLimitService
+}
+
+// SetupTest sets up the test suite
+func (s *TransferLimitServiceTestSuite) SetupTest() {
+	s.transferLimitRepository = new(mocks.TransferLimitRepository)
+	s.service = services.NewTransferLimitService(s.transferLimitRepository)
+}
+
+func thbPtr(amount int64) *types.Money {
+	money := types.NewMoney(amount, "THB")
+	return &money
+}
+
+// TestGetAccountLimits tests the GetAccountLimits function
+func (s *TransferLimitServiceTestSuite) TestGetAccountLimits() {
+	account := &models.Account{
+		AccountID: "acc-123",
+		UserID:    "user-123",
+		Type:      "saving-account",
+		Currency:  "THB",
+	}
+
+	testCases := []struct {
+		name                   string
+		rows                   []*models.TransferLimit
+		used                   types.Money
+		repoErr                error
+		expectedError          error
+		expectedPerTransaction *types.Money
+		expectedDailyRemaining *types.Money
+		expectedMonthlyLimit   *types.Money
+		expectedAvailable      *types.Money
+	}{
+		{
+			name:    "Unlimited without rows",
+			rows:    []*models.TransferLimit{},
+			used:    types.NewMoney(500000, "THB"),
+			repoErr: nil,
+		},
+		{
+			name: "User override beats account type default",
+			rows: []*models.TransferLimit{
+				{UserID: "user-123", Currency: "THB", Daily: thbPtr(1000000)},
+				{AccountType: "saving-account", Currency: "THB", PerTransaction: thbPtr(2000000), Daily: thbPtr(5000000), Monthly: thbPtr(50000000)},
+				{Currency: "THB", PerTransaction: thbPtr(100)},
+			},
+			used:                   types.NewMoney(400000, "THB"),
+			expectedPerTransaction: thbPtr(2000000),
+			expectedDailyRemaining: thbPtr(600000),
+			expectedMonthlyLimit:   thbPtr(50000000),
+			expectedAvailable:      thbPtr(600000),
+		},
+		{
+			name: "Remaining never goes below zero",
+			rows: []*models.TransferLimit{
+				{AccountType: "saving-account", Currency: "THB", Daily: thbPtr(100000)},
+			},
+			used:                   types.NewMoney(150000, "THB"),
+			expectedDailyRemaining: thbPtr(0),
+			expectedAvailable:      thbPtr(0),
+		},
+		{
+			name:          "Repository error",
+			repoErr:       errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			s.transferLimitRepository.On("GetApplicableLimits", account.UserID, account.Type, account.Currency).Return(tc.rows, tc.repoErr)
+			s.transferLimitRepository.On("GetDebitTotalSince", account.AccountID, account.Currency, mock.AnythingOfType("time.Time")).Return(tc.used, nil).Maybe()
+
+			limits, err := s.service.GetAccountLimits(account)
+
+			if tc.expectedError != nil {
+				assert.EqualError(s.T(), err, tc.expectedError.Error())
+				assert.Nil(s.T(), limits)
+				return
+			}
+
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), account.AccountID, limits.AccountID)
+			assert.Equal(s.T(), tc.expectedPerTransaction, limits.PerTransaction)
+			assert.Equal(s.T(), tc.expectedDailyRemaining, limits.Daily.Remaining)
+			assert.Equal(s.T(), tc.expectedMonthlyLimit, limits.Monthly.Limit)
+			assert.Equal(s.T(), tc.expectedAvailable, limits.Available)
+			assert.Equal(s.T(), tc.used, limits.Daily.Used)
+			assert.True(s.T(), limits.Daily.ResetsAt.After(time.Now()))
+			assert.False(s.T(), limits.Monthly.ResetsAt.Before(limits.Daily.ResetsAt))
+			s.transferLimitRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestWithdrawEnforcesLimits tests that withdrawals over a limit are rejected inside the balance lock
+func (s *TransferLimitServiceTestSuite) TestWithdrawEnforcesLimits() {
+	account := &models.AccountWithDetails{
+		AccountID: "acc-123",
+		UserID:    "user-123",
+		Type:      "saving-account",
+		Currency:  "THB",
+	}
+	rows := []*models.TransferLimit{
+		{AccountType: "saving-account", Currency: "THB", PerTransaction: thbPtr(200000), Daily: thbPtr(500000), Monthly: thbPtr(1000000)},
+	}
+
+	testCases := []struct {
+		name          string
+		amount        types.Money
+		dailyUsed     types.Money
+		monthlyUsed   types.Money
+		expectedError error
+	}{
+		{
+			name:        "Within limits",
+			amount:      types.NewMoney(100000, "THB"),
+			dailyUsed:   types.NewMoney(100000, "THB"),
+			monthlyUsed: types.NewMoney(100000, "THB"),
+		},
+		{
+			name:          "Per transaction limit exceeded",
+			amount:        types.NewMoney(200001, "THB"),
+			dailyUsed:     types.NewMoney(0, "THB"),
+			monthlyUsed:   types.NewMoney(0, "THB"),
+			expectedError: services.ErrTransferLimitExceeded,
+		},
+		{
+			name:          "Daily limit exceeded",
+			amount:        types.NewMoney(150000, "THB"),
+			dailyUsed:     types.NewMoney(400000, "THB"),
+			monthlyUsed:   types.NewMoney(400000, "THB"),
+			expectedError: services.ErrTransferLimitExceeded,
+		},
+		{
+			name:          "Monthly limit exceeded",
+			amount:        types.NewMoney(150000, "THB"),
+			dailyUsed:     types.NewMoney(0, "THB"),
+			monthlyUsed:   types.NewMoney(900000, "THB"),
+			expectedError: services.ErrTransferLimitExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			accountRepository := new(mocks.AccountRepository)
+			transactionRepository := new(mocks.TransactionRepository)
+			ledgerRepository := new(mocks.LedgerRepository)
+			limitRepository := new(mocks.TransferLimitRepository)
+			holdRepository := new(mocks.HoldRepository)
+			outboxRepository := new(mocks.OutboxRepository)
+			txProvider := new(mocks.TxProvider)
+			service := services.NewAccountService(accountRepository, transactionRepository, holdRepository, txProvider, newCacheStub())
+
+			accountRepository.On("GetAccountWithDetailByID", account.AccountID).Return(account, nil)
+			limitRepository.On("GetApplicableLimits", account.UserID, account.Type, "THB").Return(rows, nil)
+			// Daily usage is read before monthly usage
+			limitRepository.On("GetDebitTotalSince", account.AccountID, "THB", mock.AnythingOfType("time.Time")).Return(tc.dailyUsed, nil).Once()
+			limitRepository.On("GetDebitTotalSince", account.AccountID, "THB", mock.AnythingOfType("time.Time")).Return(tc.monthlyUsed, nil).Once()
+			holdRepository.On("GetHeldAmount", account.AccountID, "THB", mock.AnythingOfType("time.Time")).Return(types.NewMoney(0, "THB"), nil)
+
+			var balanceErr error
+			accountRepository.On("UpdateAccountBalance", account.AccountID,
+				mock.AnythingOfType("func(types.Money) (types.Money, error)")).
+				Run(func(args mock.Arguments) {
+					updateFn := args.Get(1).(func(types.Money) (types.Money, error))
+					_, balanceErr = updateFn(types.NewMoney(10000000, "THB"))
+				}).
+				Return(func(string, func(types.Money) (types.Money, error)) error { return balanceErr })
+			transactionRepository.On("Create", mock.AnythingOfType("*models.Transaction")).Return(nil).Maybe()
+			ledgerRepository.On("PostEntry", mock.AnythingOfType("*models.JournalEntry")).Return(nil).Maybe()
+			outboxRepository.On("Create", mock.AnythingOfType("*models.DomainEvent")).Return(nil).Maybe()
+
+			txProvider.On("Transact", mock.AnythingOfType("func(repositories.Adapters) error")).
+				Return(func(txFunc func(repositories.Adapters) error) error {
+					return txFunc(repositories.Adapters{
+						AccountRepository:       accountRepository,
+						TransactionRepository:   transactionRepository,
+						LedgerRepository:        ledgerRepository,
+						TransferLimitRepository: limitRepository,
+						HoldRepository:          holdRepository,
+						OutboxRepository:        outboxRepository,
+					})
+				})
+
+			_, err := service.WithdrawFromAccount(account.AccountID, tc.amount)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(s.T(), err, tc.expectedError)
+				transactionRepository.AssertNotCalled(s.T(), "Create", mock.Anything)
+				return
+			}
+			assert.NoError(s.T(), err)
+			limitRepository.AssertExpectations(s.T())
+		})
+	}
+}
+
+// TestTransferLimitServiceSuite runs the test suite
+func TestTransferLimitServiceSuite(t *testing.T) {
+	suite.Run(t, new(TransferLimitServiceTestSuite))
+}
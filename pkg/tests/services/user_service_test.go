@@ -4,6 +4,7 @@ import (
 	"backend-developer-assignment/app/models"
 	"backend-developer-assignment/app/services"
 	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
 	"errors"
 	"testing"
 
@@ -16,6 +17,7 @@ type UserServiceTestSuite struct {
 	suite.Suite
 	userRepository         *mocks.UserRepository
 	userGreetingRepository *mocks.UserGreetingRepository
+	roleRepository         *mocks.RoleRepository
 	service                services.UserService
 }
 
@@ -23,7 +25,8 @@ type UserServiceTestSuite struct {
 func (s *UserServiceTestSuite) SetupTest() {
 	s.userRepository = new(mocks.UserRepository)
 	s.userGreetingRepository = new(mocks.UserGreetingRepository)
-	s.service = services.NewUserService(s.userRepository, s.userGreetingRepository)
+	s.roleRepository = new(mocks.RoleRepository)
+	s.service = services.NewUserService(s.userRepository, s.userGreetingRepository, s.roleRepository)
 }
 
 // TestGetUserByID tests the GetUserByID function
@@ -238,6 +241,17 @@ func (s *UserServiceTestSuite) TestGetUserGreetingByID() {
 	}
 }
 
+// TestGetUserRoles tests the GetUserRoles function
+func (s *UserServiceTestSuite) TestGetUserRoles() {
+	s.roleRepository.On("GetRolesByUserID", "staff-user").Return([]types.Role{types.RoleOperations}, nil).Once()
+
+	roles, err := s.service.GetUserRoles("staff-user")
+
+	s.NoError(err)
+	s.Equal([]types.Role{types.RoleOperations}, roles)
+	s.roleRepository.AssertExpectations(s.T())
+}
+
 // TestUpdateUserGreeting tests the UpdateUserGreeting function
 func (s *UserServiceTestSuite) TestUpdateUserGreeting() {
 	testCases := []struct {
@@ -0,0 +1,404 @@
+package services_test
+
+import (
+	"backend-developer-assignment/app/models"
+	"backend-developer-assignment/app/services"
+	"backend-developer-assignment/pkg/configs"
+	mocks "backend-developer-assignment/pkg/mocks/repositories"
+	"backend-developer-assignment/pkg/types"
+	"backend-developer-assignment/pkg/utils"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// webhookReceiver is a local partner endpoint verifying the signature of every request it receives
+type webhookReceiver struct {
+	server   *httptest.Server
+	secret   string
+	status   int
+	mu       sync.Mutex
+	received []receivedWebhook
+}
+
+type receivedWebhook struct {
+	event     string
+	body      []byte
+	signature error
+}
+
+func newWebhookReceiver(secret string) *webhookReceiver {
+	receiver := &webhookReceiver{secret: secret, status: http.StatusOK}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receiver.mu.Lock()
+		receiver.received = append(receiver.received, receivedWebhook{
+			event:     r.Header.Get("X-Webhook-Event"),
+			body:      body,
+			signature: utils.VerifyWebhook(receiver.secret, r.Header.Get(utils.WebhookSignatureHeader), body, configs.WEBHOOK_SIGNATURE_MAX_AGE, time.Now()),
+		})
+		status := receiver.status
+		receiver.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	return receiver
+}
+
+func (r *webhookReceiver) requests() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.received...)
+}
+
+// WebhookServiceTestSuite is a test suite for WebhookService
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	webhookRepository *mocks.WebhookRepository
+	receiver          *webhookReceiver
+	service           services.WebhookService
+}
+
+// SetupTest sets up the test suite
+func (s *WebhookServiceTestSuite) SetupTest() {
+	s.webhookRepository = new(mocks.WebhookRepository)
+	s.receiver = newWebhookReceiver("whsec_test")
+	// The receiver listens on the loopback address
+	s.service = services.NewWebhookService(s.webhookRepository, services.NewWebhookHTTPClient(true))
+}
+
+// TearDownTest stops the receiver
+func (s *WebhookServiceTestSuite) TearDownTest() {
+	s.receiver.server.Close()
+}
+
+func (s *WebhookServiceTestSuite) dispatch(attempts int) *models.WebhookDispatch {
+	return &models.WebhookDispatch{
+		WebhookDelivery: models.WebhookDelivery{
+			DeliveryID: 7,
+			EndpointID: "endpoint-1",
+			EventID:    "event-1",
+			EventType:  models.EventTransferCompleted,
+			Payload:    json.RawMessage(`{"id":"event-1","type":"TransferCompleted"}`),
+			Status:     models.WebhookDeliveryPending,
+			Attempts:   attempts,
+		},
+		URL:    s.receiver.server.URL,
+		Secret: "whsec_test",
+	}
+}
+
+// deliverDue runs DeliverDue over the dispatch and returns the delivery stored after the attempt
+func (s *WebhookServiceTestSuite) deliverDue(dispatch *models.WebhookDispatch) *models.WebhookDelivery {
+	var stored *models.WebhookDelivery
+	s.webhookRepository.On("ClaimDueDeliveries", mock.Anything, mock.Anything, configs.WEBHOOK_LEASE_DURATION, configs.WEBHOOK_BATCH_SIZE).
+		Return([]*models.WebhookDispatch{dispatch}, nil).Once()
+	s.webhookRepository.On("UpdateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.WebhookDelivery)
+	}).Return(nil).Once()
+
+	err := s.service.DeliverDue(context.Background())
+
+	s.Require().NoError(err)
+	s.Require().NotNil(stored)
+	return stored
+}
+
+// TestCreateEndpoint tests that an endpoint is registered with a signing secret
+func (s *WebhookServiceTestSuite) TestCreateEndpoint() {
+	s.webhookRepository.On("CreateEndpoint", mock.AnythingOfType("*models.WebhookEndpoint")).Return(nil).Once()
+
+	endpoint, err := s.service.CreateEndpoint("user-123", models.WebhookScopeUser, "https://partner.example.com/hooks", "Partner",
+		models.EventTypeList{models.EventTransferCompleted})
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), endpoint.EndpointID)
+	assert.Equal(s.T(), "user-123", endpoint.UserID)
+	assert.Equal(s.T(), models.WebhookScopeUser, endpoint.Scope)
+	assert.Contains(s.T(), endpoint.Secret, "whsec_")
+	s.webhookRepository.AssertExpectations(s.T())
+}
+
+// TestCreateEndpointValidation tests that invalid URLs and unknown event types are rejected
+func (s *WebhookServiceTestSuite) TestCreateEndpointValidation() {
+	s.T().Setenv("APP_ENV", "prod")
+
+	for _, endpointURL := range []string{"not a url", "/relative", "ftp://partner.example.com", "http://partner.example.com"} {
+		_, err := s.service.CreateEndpoint("user-123", models.WebhookScopeUser, endpointURL, "", nil)
+		assert.ErrorIs(s.T(), err, services.ErrInvalidWebhookURL, endpointURL)
+	}
+
+	_, err := s.service.CreateEndpoint("user-123", models.WebhookScopeUser, "https://partner.example.com", "", models.EventTypeList{"Unknown"})
+	assert.ErrorIs(s.T(), err, services.ErrUnknownEventType)
+
+	s.webhookRepository.AssertNotCalled(s.T(), "CreateEndpoint", mock.Anything)
+}
+
+// TestCreateEndpointRejectsPrivateAddresses tests that endpoints on local and private networks are rejected
+func (s *WebhookServiceTestSuite) TestCreateEndpointRejectsPrivateAddresses() {
+	privateURLs := []string{
+		"https://localhost:8443/hooks",
+		"https://api.localhost/hooks",
+		"https://127.0.0.1/hooks",
+		"https://10.0.0.5/hooks",
+		"https://172.16.0.1/hooks",
+		"https://192.168.1.10/hooks",
+		"http://169.254.169.254/latest/meta-data",
+		"https://100.64.0.1/hooks",
+		"https://0.0.0.0/hooks",
+		"https://[::1]/hooks",
+		"https://[fd00::1]/hooks",
+		"https://[fe80::1]/hooks",
+		"https://[::ffff:127.0.0.1]/hooks",
+	}
+	for _, endpointURL := range privateURLs {
+		_, err := s.service.CreateEndpoint("user-123", models.WebhookScopeUser, endpointURL, "", nil)
+		assert.ErrorIs(s.T(), err, services.ErrPrivateWebhookURL, endpointURL)
+	}
+	s.webhookRepository.AssertNotCalled(s.T(), "CreateEndpoint", mock.Anything)
+
+	// Developers may opt in to local receivers, never in production
+	s.T().Setenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "true")
+	s.webhookRepository.On("CreateEndpoint", mock.AnythingOfType("*models.WebhookEndpoint")).Return(nil).Once()
+	_, err := s.service.CreateEndpoint("user-123", models.WebhookScopeUser, "http://localhost:9000/hooks", "", nil)
+	assert.NoError(s.T(), err)
+
+	s.T().Setenv("APP_ENV", "prod")
+	_, err = s.service.CreateEndpoint("user-123", models.WebhookScopeUser, "https://10.0.0.5/hooks", "", nil)
+	assert.ErrorIs(s.T(), err, services.ErrPrivateWebhookURL)
+	s.webhookRepository.AssertExpectations(s.T())
+}
+
+// transferCompletedEvent returns the event of a transfer of 100.00 THB from user-1 to user-2
+func transferCompletedEvent(fromUserID, toUserID string) *models.DomainEvent {
+	payload, _ := json.Marshal(models.TransferCompletedPayload{
+		FromAccountID:       "account-1",
+		FromUserID:          fromUserID,
+		ToAccountID:         "account-2",
+		ToUserID:            toUserID,
+		DebitTransactionID:  "tx-debit",
+		CreditTransactionID: "tx-credit",
+		Amount:              types.NewMoney(10000, "THB"),
+		CreditedAmount:      types.NewMoney(10000, "THB"),
+		SourceBalance:       types.NewMoney(40000, "THB"),
+		DestinationBalance:  types.NewMoney(90000, "THB"),
+	})
+	return &models.DomainEvent{
+		EventID:    "event-1",
+		EventType:  models.EventTransferCompleted,
+		Payload:    payload,
+		OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// publish publishes the event to the endpoints and returns the deliveries stored
+func (s *WebhookServiceTestSuite) publish(event *models.DomainEvent, userIDs []string, endpoints []*models.WebhookEndpoint) []*models.WebhookDelivery {
+	s.webhookRepository.On("GetEndpointsForUsers", userIDs).Return(endpoints, nil).Once()
+	var deliveries []*models.WebhookDelivery
+	s.webhookRepository.On("CreateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		deliveries = append(deliveries, args.Get(0).(*models.WebhookDelivery))
+	}).Return(nil)
+
+	s.Require().NoError(s.service.Publish(context.Background(), event))
+	return deliveries
+}
+
+// TestPublish tests that an event is stored as a delivery to the endpoints subscribed to its type, each user
+// receiving only their own side of a transfer
+func (s *WebhookServiceTestSuite) TestPublish() {
+	event := transferCompletedEvent("user-1", "user-2")
+	deliveries := s.publish(event, []string{"user-1", "user-2"}, []*models.WebhookEndpoint{
+		{EndpointID: "sender", UserID: "user-1", Scope: models.WebhookScopeUser},
+		{EndpointID: "cards-only", UserID: "user-1", Scope: models.WebhookScopeUser, EventTypes: models.EventTypeList{models.EventCardStatusChanged}},
+		{EndpointID: "recipient", UserID: "user-2", Scope: models.WebhookScopeUser},
+		{EndpointID: "staff", UserID: "staff-1", Scope: models.WebhookScopeAll, EventTypes: models.EventTypeList{models.EventTransferCompleted}},
+	})
+
+	s.Require().Len(deliveries, 3)
+	assert.Equal(s.T(), "sender", deliveries[0].EndpointID)
+	assert.Equal(s.T(), models.WebhookDeliveryPending, deliveries[0].Status)
+	assert.JSONEq(s.T(), `{"id":"event-1","type":"TransferCompleted","occurred_at":"2026-01-02T03:04:05Z",
+		"data":{"account_id":"account-1","user_id":"user-1","transaction_id":"tx-debit","direction":"debit",
+		"amount":{"amount":"100.00","currency":"THB"},"counterparty_account_id":"account-2",
+		"balance":{"amount":"400.00","currency":"THB"}}}`, string(deliveries[0].Payload))
+
+	assert.Equal(s.T(), "recipient", deliveries[1].EndpointID)
+	assert.JSONEq(s.T(), `{"id":"event-1","type":"TransferCompleted","occurred_at":"2026-01-02T03:04:05Z",
+		"data":{"account_id":"account-2","user_id":"user-2","transaction_id":"tx-credit","direction":"credit",
+		"amount":{"amount":"100.00","currency":"THB"},"counterparty_account_id":"account-1",
+		"balance":{"amount":"900.00","currency":"THB"}}}`, string(deliveries[1].Payload))
+
+	// Staff receive the whole payload
+	assert.Equal(s.T(), "staff", deliveries[2].EndpointID)
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(deliveries[2].Payload, &body))
+	assert.JSONEq(s.T(), string(event.Payload), string(body.Data))
+}
+
+// TestPublishTransferBetweenOwnAccounts tests that a user sending to their own account receives both sides
+func (s *WebhookServiceTestSuite) TestPublishTransferBetweenOwnAccounts() {
+	event := transferCompletedEvent("user-1", "user-1")
+	deliveries := s.publish(event, []string{"user-1"}, []*models.WebhookEndpoint{
+		{EndpointID: "owner", UserID: "user-1", Scope: models.WebhookScopeUser},
+	})
+
+	s.Require().Len(deliveries, 1)
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(deliveries[0].Payload, &body))
+	assert.JSONEq(s.T(), string(event.Payload), string(body.Data))
+}
+
+// TestDeliverDue tests that a delivery is signed, sent and marked delivered on a 2xx response
+func (s *WebhookServiceTestSuite) TestDeliverDue() {
+	delivery := s.deliverDue(s.dispatch(0))
+
+	requests := s.receiver.requests()
+	s.Require().Len(requests, 1)
+	assert.NoError(s.T(), requests[0].signature)
+	assert.Equal(s.T(), "TransferCompleted", requests[0].event)
+	assert.JSONEq(s.T(), `{"id":"event-1","type":"TransferCompleted"}`, string(requests[0].body))
+
+	assert.Equal(s.T(), models.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(s.T(), 1, delivery.Attempts)
+	assert.Equal(s.T(), http.StatusOK, delivery.LastStatusCode)
+	assert.NotNil(s.T(), delivery.DeliveredAt)
+}
+
+// TestDeliverDueRetries tests that a failed attempt is retried with an exponential backoff
+func (s *WebhookServiceTestSuite) TestDeliverDueRetries() {
+	s.receiver.status = http.StatusInternalServerError
+
+	before := time.Now()
+	delivery := s.deliverDue(s.dispatch(2))
+
+	assert.Len(s.T(), s.receiver.requests(), 1)
+	assert.Equal(s.T(), models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(s.T(), 3, delivery.Attempts)
+	assert.Equal(s.T(), http.StatusInternalServerError, delivery.LastStatusCode)
+	assert.Contains(s.T(), delivery.LastError, "500")
+	assert.WithinDuration(s.T(), before.Add(4*configs.WEBHOOK_RETRY_BASE), delivery.NextAttemptAt, 5*time.Second)
+	assert.Nil(s.T(), delivery.DeliveredAt)
+}
+
+// TestDeliverDueUnreachable tests that an endpoint that cannot be reached is retried
+func (s *WebhookServiceTestSuite) TestDeliverDueUnreachable() {
+	dispatch := s.dispatch(0)
+	s.receiver.server.Close()
+
+	delivery := s.deliverDue(dispatch)
+
+	assert.Equal(s.T(), models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(s.T(), 0, delivery.LastStatusCode)
+	assert.NotEmpty(s.T(), delivery.LastError)
+}
+
+// TestDeliverDueRefusesPrivateAddresses tests that the delivery client does not connect to a host name
+// resolving to a private address, as a name rebound after it was registered would
+func (s *WebhookServiceTestSuite) TestDeliverDueRefusesPrivateAddresses() {
+	s.service = services.NewWebhookService(s.webhookRepository, services.NewWebhookHTTPClient(false))
+	dispatch := s.dispatch(0)
+	dispatch.URL = strings.Replace(s.receiver.server.URL, "127.0.0.1", "localhost", 1)
+
+	delivery := s.deliverDue(dispatch)
+
+	assert.Empty(s.T(), s.receiver.requests())
+	assert.Equal(s.T(), models.WebhookDeliveryPending, delivery.Status)
+	assert.Contains(s.T(), delivery.LastError, services.ErrPrivateWebhookURL.Error())
+}
+
+// TestDeliverDueDeadLetters tests that a delivery dies once its last attempt failed
+func (s *WebhookServiceTestSuite) TestDeliverDueDeadLetters() {
+	s.receiver.status = http.StatusBadGateway
+
+	delivery := s.deliverDue(s.dispatch(configs.WEBHOOK_MAX_ATTEMPTS - 1))
+
+	assert.Equal(s.T(), models.WebhookDeliveryDead, delivery.Status)
+	assert.Equal(s.T(), configs.WEBHOOK_MAX_ATTEMPTS, delivery.Attempts)
+}
+
+// TestDeliverDueDoesNotFollowRedirects tests that a redirect is a failed attempt
+func (s *WebhookServiceTestSuite) TestDeliverDueDoesNotFollowRedirects() {
+	s.receiver.status = http.StatusFound
+
+	delivery := s.deliverDue(s.dispatch(0))
+
+	assert.Equal(s.T(), models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(s.T(), http.StatusFound, delivery.LastStatusCode)
+}
+
+// TestWebhookRetryDelay tests that the delay doubles from the base up to the cap
+func (s *WebhookServiceTestSuite) TestWebhookRetryDelay() {
+	assert.Equal(s.T(), configs.WEBHOOK_RETRY_BASE, services.WebhookRetryDelay(1))
+	assert.Equal(s.T(), 2*configs.WEBHOOK_RETRY_BASE, services.WebhookRetryDelay(2))
+	assert.Equal(s.T(), 8*configs.WEBHOOK_RETRY_BASE, services.WebhookRetryDelay(4))
+	assert.Equal(s.T(), configs.WEBHOOK_RETRY_MAX, services.WebhookRetryDelay(50))
+}
+
+// TestReplayDelivery tests that a dead delivery is made due again
+func (s *WebhookServiceTestSuite) TestReplayDelivery() {
+	s.webhookRepository.On("GetDelivery", "endpoint-1", int64(7)).
+		Return(&models.WebhookDelivery{DeliveryID: 7, EndpointID: "endpoint-1", Status: models.WebhookDeliveryDead, Attempts: 8}, nil).Once()
+	s.webhookRepository.On("ResetDelivery", "endpoint-1", int64(7), mock.Anything).Return(nil).Once()
+
+	delivery, err := s.service.ReplayDelivery("endpoint-1", 7)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(s.T(), 0, delivery.Attempts)
+	s.webhookRepository.AssertExpectations(s.T())
+}
+
+// TestReplayDeliveryErrors tests that missing and pending deliveries are not replayed
+func (s *WebhookServiceTestSuite) TestReplayDeliveryErrors() {
+	s.webhookRepository.On("GetDelivery", "endpoint-1", int64(1)).Return(nil, sql.ErrNoRows).Once()
+	s.webhookRepository.On("GetDelivery", "endpoint-1", int64(2)).
+		Return(&models.WebhookDelivery{DeliveryID: 2, Status: models.WebhookDeliveryPending}, nil).Once()
+	s.webhookRepository.On("GetDelivery", "endpoint-1", int64(3)).
+		Return(&models.WebhookDelivery{DeliveryID: 3, Status: models.WebhookDeliveryDelivered}, nil).Once()
+	s.webhookRepository.On("ResetDelivery", "endpoint-1", int64(3), mock.Anything).Return(sql.ErrNoRows).Once()
+
+	_, err := s.service.ReplayDelivery("endpoint-1", 1)
+	assert.ErrorIs(s.T(), err, services.ErrDeliveryNotFound)
+
+	_, err = s.service.ReplayDelivery("endpoint-1", 2)
+	assert.ErrorIs(s.T(), err, services.ErrDeliveryInProgress)
+
+	_, err = s.service.ReplayDelivery("endpoint-1", 3)
+	assert.ErrorIs(s.T(), err, services.ErrDeliveryInProgress)
+}
+
+// TestListDeliveriesLimit tests that the page size is defaulted and capped
+func (s *WebhookServiceTestSuite) TestListDeliveriesLimit() {
+	s.webhookRepository.On("ListDeliveries", "endpoint-1", models.WebhookDeliveryFilter{Limit: services.DefaultWebhookDeliveryLimit}).
+		Return([]*models.WebhookDelivery{}, nil).Once()
+	s.webhookRepository.On("ListDeliveries", "endpoint-1", models.WebhookDeliveryFilter{Limit: services.MaxWebhookDeliveryLimit}).
+		Return([]*models.WebhookDelivery{}, nil).Once()
+
+	_, err := s.service.ListDeliveries("endpoint-1", models.WebhookDeliveryFilter{})
+	assert.NoError(s.T(), err)
+	_, err = s.service.ListDeliveries("endpoint-1", models.WebhookDeliveryFilter{Limit: 10000})
+	assert.NoError(s.T(), err)
+
+	s.webhookRepository.AssertExpectations(s.T())
+}
+
+// TestWebhookServiceSuite runs the test suite
+func TestWebhookServiceSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
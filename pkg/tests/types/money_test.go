@@ -0,0 +1,128 @@
+package types_test
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name          string
+		amount        string
+		currency      string
+		expected      types.Money
+		expectedError error
+	}{
+		{name: "Whole amount", amount: "100", currency: "THB", expected: types.NewMoney(10000, "THB")},
+		{name: "Two decimals", amount: "100.55", currency: "THB", expected: types.NewMoney(10055, "THB")},
+		{name: "One decimal", amount: "0.1", currency: "USD", expected: types.NewMoney(10, "USD")},
+		{name: "Lowercase currency", amount: "1.00", currency: "usd", expected: types.NewMoney(100, "USD")},
+		{name: "Negative amount", amount: "-12.34", currency: "THB", expected: types.NewMoney(-1234, "THB")},
+		{name: "Zero decimal currency", amount: "1500", currency: "JPY", expected: types.NewMoney(1500, "JPY")},
+		{name: "Trailing zeros are insignificant", amount: "12.3400", currency: "THB", expected: types.NewMoney(1234, "THB")},
+		{name: "Too many decimals", amount: "12.345", currency: "THB", expectedError: types.ErrInvalidAmount},
+		{name: "Fraction for zero decimal currency", amount: "10.5", currency: "JPY", expectedError: types.ErrInvalidAmount},
+		{name: "Not a number", amount: "abc", currency: "THB", expectedError: types.ErrInvalidAmount},
+		{name: "Empty amount", amount: "", currency: "THB", expectedError: types.ErrInvalidAmount},
+		{name: "Invalid currency", amount: "10", currency: "BAHT", expectedError: types.ErrInvalidCurrency},
+		{name: "Overflow", amount: "99999999999999999999", currency: "THB", expectedError: types.ErrAmountOverflow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			money, err := types.ParseMoney(tc.amount, tc.currency)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, money)
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	assert.Equal(t, "0.00", types.NewMoney(0, "THB").Decimal())
+	assert.Equal(t, "0.05", types.NewMoney(5, "THB").Decimal())
+	assert.Equal(t, "1234.56", types.NewMoney(123456, "THB").Decimal())
+	assert.Equal(t, "-0.50", types.NewMoney(-50, "USD").Decimal())
+	assert.Equal(t, "1500", types.NewMoney(1500, "JPY").Decimal())
+	assert.Equal(t, "1234.56 THB", types.NewMoney(123456, "THB").String())
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := types.NewMoney(1010, "THB")
+	b := types.NewMoney(20, "THB")
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMoney(1030, "THB"), sum)
+
+	diff, err := a.Sub(b)
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMoney(990, "THB"), diff)
+
+	cmp, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+	assert.True(t, b.LessThan(a))
+
+	// 0.1 + 0.2 must be exactly 0.3, which float64 cannot represent
+	tenth, _ := types.ParseMoney("0.1", "THB")
+	fifth, _ := types.ParseMoney("0.2", "THB")
+	total, err := tenth.Add(fifth)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.30", total.Decimal())
+
+	_, err = a.Add(types.NewMoney(100, "USD"))
+	assert.ErrorIs(t, err, types.ErrCurrencyMismatch)
+	assert.False(t, a.LessThan(types.NewMoney(999999, "USD")))
+
+	_, err = types.NewMoney(1<<62, "THB").Add(types.NewMoney(1<<62, "THB"))
+	assert.ErrorIs(t, err, types.ErrAmountOverflow)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(types.NewMoney(10050, "THB"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"100.50","currency":"THB"}`, string(data))
+
+	var fromString types.Money
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"100.50","currency":"THB"}`), &fromString))
+	assert.Equal(t, types.NewMoney(10050, "THB"), fromString)
+
+	var fromNumber types.Money
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":100.1,"currency":"usd"}`), &fromNumber))
+	assert.Equal(t, types.NewMoney(10010, "USD"), fromNumber)
+
+	var invalid types.Money
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.001","currency":"THB"}`), &invalid))
+}
+
+func TestMoneyScanAndValue(t *testing.T) {
+	var withCurrency types.Money
+	assert.NoError(t, withCurrency.Scan([]byte("1234.50 THB")))
+	assert.Equal(t, types.NewMoney(123450, "THB"), withCurrency)
+
+	var zeroDecimal types.Money
+	assert.NoError(t, zeroDecimal.Scan("1500.00 JPY"))
+	assert.Equal(t, types.NewMoney(1500, "JPY"), zeroDecimal)
+
+	var bare types.Money
+	assert.NoError(t, bare.Scan([]byte("99.99")))
+	assert.Equal(t, int64(9999), bare.Amount)
+	assert.Empty(t, bare.Currency)
+
+	var null types.Money
+	assert.NoError(t, null.Scan(nil))
+	assert.True(t, null.IsZero())
+
+	value, err := types.NewMoney(123450, "THB").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1234.50", value)
+}
@@ -0,0 +1,123 @@
+package types_test
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rate          string
+		expected      string
+		expectedError error
+	}{
+		{name: "Whole rate", rate: "36", expected: "36.00000000"},
+		{name: "Eight decimals", rate: "0.02739726", expected: "0.02739726"},
+		{name: "Trailing zeros are insignificant", rate: "1.090000000", expected: "1.09000000"},
+		{name: "Too many decimals", rate: "0.123456789", expectedError: types.ErrInvalidRate},
+		{name: "Zero", rate: "0", expectedError: types.ErrInvalidRate},
+		{name: "Negative", rate: "-1.5", expectedError: types.ErrInvalidRate},
+		{name: "Not a number", rate: "abc", expectedError: types.ErrInvalidRate},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := types.ParseRate(tc.rate)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rate.String())
+		})
+	}
+}
+
+func TestRateConvert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rate     string
+		amount   types.Money
+		currency string
+		expected types.Money
+	}{
+		{name: "USD to THB", rate: "36.5", amount: types.NewMoney(10000, "USD"), currency: "THB", expected: types.NewMoney(365000, "THB")},
+		{name: "Rounds half away from zero", rate: "0.02739726", amount: types.NewMoney(18250, "THB"), currency: "USD", expected: types.NewMoney(500, "USD")},
+		{name: "Rounds down below half", rate: "0.0273", amount: types.NewMoney(100, "THB"), currency: "USD", expected: types.NewMoney(3, "USD")},
+		{name: "Into zero decimal currency", rate: "149.5", amount: types.NewMoney(1001, "USD"), currency: "JPY", expected: types.NewMoney(1496, "JPY")},
+		{name: "From zero decimal currency", rate: "0.245", amount: types.NewMoney(1000, "JPY"), currency: "THB", expected: types.NewMoney(24500, "THB")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := types.MustParseRate(tc.rate).Convert(tc.amount, tc.currency)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, converted)
+		})
+	}
+
+	_, err := types.MustParseRate("36.5").Convert(types.NewMoney(100, "USD"), "BAHT")
+	assert.ErrorIs(t, err, types.ErrInvalidCurrency)
+
+	_, err = types.Rate{}.Convert(types.NewMoney(100, "USD"), "THB")
+	assert.ErrorIs(t, err, types.ErrInvalidRate)
+}
+
+func TestRateInverse(t *testing.T) {
+	inverse, err := types.MustParseRate("36.5").Inverse()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.02739726", inverse.String())
+
+	inverse, err = types.MustParseRate("0.25").Inverse()
+	assert.NoError(t, err)
+	assert.Equal(t, "4.00000000", inverse.String())
+
+	_, err = types.MustParseRate("999999999").Inverse()
+	assert.ErrorIs(t, err, types.ErrInvalidRate)
+}
+
+func TestRateJSONScanAndValue(t *testing.T) {
+	data, err := json.Marshal(types.MustParseRate("36.5"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"36.50000000"`, string(data))
+
+	var rate types.Rate
+	assert.NoError(t, json.Unmarshal([]byte(`"1.09"`), &rate))
+	assert.Equal(t, types.MustParseRate("1.09"), rate)
+	assert.NoError(t, json.Unmarshal([]byte(`0.245`), &rate))
+	assert.Equal(t, types.MustParseRate("0.245"), rate)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"abc"`), &rate), types.ErrInvalidRate)
+
+	assert.NoError(t, rate.Scan([]byte("36.50000000")))
+	assert.Equal(t, types.MustParseRate("36.5"), rate)
+	assert.NoError(t, rate.Scan(nil))
+	assert.True(t, rate.IsZero())
+
+	value, err := types.MustParseRate("0.245").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.24500000", value)
+}
+
+func TestMoneyMulDiv(t *testing.T) {
+	share, err := types.NewMoney(365000, "THB").MulDiv(2500, 10000)
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMoney(91250, "THB"), share)
+
+	share, err = types.NewMoney(100, "USD").MulDiv(1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMoney(33, "USD"), share)
+
+	share, err = types.NewMoney(100, "USD").MulDiv(2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMoney(67, "USD"), share)
+
+	_, err = types.NewMoney(100, "USD").MulDiv(1, 0)
+	assert.ErrorIs(t, err, types.ErrInvalidAmount)
+}
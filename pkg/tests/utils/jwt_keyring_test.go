@@ -0,0 +1,189 @@
+package utils_test
+
+import (
+	"backend-developer-assignment/pkg/utils"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func writeEd25519Key(t *testing.T, path string) ed25519.PublicKey {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	writePEM(t, path, "PRIVATE KEY", der)
+	return publicKey
+}
+
+func writeRSAKey(t *testing.T, path string, bits int) {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	writePEM(t, path, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey))
+}
+
+func signToken(t *testing.T, key *utils.JWTKey) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), jwt.MapClaims{"id": "user-123", "exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = key.KID
+	signed, err := token.SignedString(key.PrivateKey)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	writeRSAKey(t, filepath.Join(dir, "2026-07.pem"), 2048)
+	writeEd25519Key(t, filepath.Join(dir, "2026-10.pem"))
+	retired := writeEd25519Key(t, filepath.Join(dir, "2026-04.pem"))
+	der, err := x509.MarshalPKIXPublicKey(retired)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "2026-04.pub.pem"), "PUBLIC KEY", der)
+
+	manifest := `{"keys": [
+		{"kid": "2026-04", "public_key": "2026-04.pub.pem", "not_before": "` + now.Add(-48*time.Hour).Format(time.RFC3339) + `", "not_after": "` + now.Add(-time.Hour).Format(time.RFC3339) + `"},
+		{"kid": "2026-07", "private_key": "2026-07.pem", "not_before": "` + now.Add(-24*time.Hour).Format(time.RFC3339) + `", "not_after": "` + now.Add(time.Hour).Format(time.RFC3339) + `"},
+		{"kid": "2026-10", "private_key": "2026-10.pem", "not_before": "` + now.Add(-time.Minute).Format(time.RFC3339) + `"}
+	]}`
+	path := filepath.Join(dir, "keyring.json")
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0o600))
+
+	keyring, err := utils.LoadKeyring(path)
+	require.NoError(t, err)
+
+	// The newest key signs once it has started, the previous one until then
+	key, err := keyring.SigningKey(now)
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10", key.KID)
+	assert.Equal(t, "EdDSA", key.Algorithm)
+
+	key, err = keyring.SigningKey(now.Add(-2 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-07", key.KID)
+	assert.Equal(t, "RS256", key.Algorithm)
+
+	// Tokens of the previous key are accepted during the overlap, tokens of the retired key are not
+	_, err = keyring.VerificationKey("2026-07", now)
+	assert.NoError(t, err)
+	_, err = keyring.VerificationKey("2026-07", now.Add(time.Hour))
+	assert.ErrorIs(t, err, utils.ErrUnknownKey)
+	_, err = keyring.VerificationKey("2026-04", now)
+	assert.ErrorIs(t, err, utils.ErrUnknownKey)
+
+	jwks := keyring.JWKS(now)
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "2026-10", jwks.Keys[0].KeyID)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+	assert.Equal(t, "2026-07", jwks.Keys[1].KeyID)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+	assert.NotEmpty(t, jwks.Keys[1].N)
+}
+
+func TestLoadKeyringErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, filepath.Join(dir, "ed25519.pem"))
+	writeRSAKey(t, filepath.Join(dir, "weak.pem"), 1024)
+
+	testCases := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "No keys", manifest: `{"keys": []}`},
+		{name: "Missing kid", manifest: `{"keys": [{"private_key": "ed25519.pem"}]}`},
+		{name: "Duplicate kid", manifest: `{"keys": [{"kid": "a", "private_key": "ed25519.pem"}, {"kid": "a", "private_key": "ed25519.pem"}]}`},
+		{name: "Missing key file", manifest: `{"keys": [{"kid": "a", "private_key": "missing.pem"}]}`},
+		{name: "No key", manifest: `{"keys": [{"kid": "a"}]}`},
+		{name: "Weak RSA key", manifest: `{"keys": [{"kid": "a", "private_key": "weak.pem"}]}`},
+		{name: "Empty validity", manifest: `{"keys": [{"kid": "a", "private_key": "ed25519.pem", "not_before": "2026-10-01T00:00:00Z", "not_after": "2026-09-01T00:00:00Z"}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "keyring.json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.manifest), 0o600))
+
+			_, err := utils.LoadKeyring(path)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadKeyringFromEnv(t *testing.T) {
+	t.Setenv("JWT_KEYRING_FILE", "")
+
+	t.Setenv("APP_ENV", "prod")
+	_, err := utils.LoadKeyringFromEnv()
+	assert.Error(t, err, "production needs a keyring file")
+
+	t.Setenv("APP_ENV", "dev")
+	keyring, err := utils.LoadKeyringFromEnv()
+	assert.NoError(t, err)
+	_, err = keyring.SigningKey(time.Now())
+	assert.NoError(t, err)
+}
+
+func TestJWTKeyFunc(t *testing.T) {
+	now := time.Now()
+	_, oldPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, newPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyring, err := utils.NewKeyring(
+		&utils.JWTKey{KID: "old", PrivateKey: oldPrivate, NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour)},
+		&utils.JWTKey{KID: "new", PrivateKey: newPrivate, NotBefore: now.Add(-time.Minute)},
+	)
+	require.NoError(t, err)
+	utils.SetKeyring(keyring)
+	t.Cleanup(func() { utils.SetKeyring(nil) })
+
+	t.Setenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT", "15")
+	tokens, err := utils.GenerateNewTokens("user-123", "", nil)
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(tokens.Access, utils.JWTKeyFunc)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", token.Header["kid"])
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+
+	oldKey, err := keyring.VerificationKey("old", now)
+	require.NoError(t, err)
+	_, err = jwt.Parse(signToken(t, oldKey), utils.JWTKeyFunc)
+	assert.NoError(t, err, "tokens of the previous key are accepted until it expires")
+
+	unknownKey := *oldKey
+	unknownKey.KID = "unknown"
+	_, err = jwt.Parse(signToken(t, &unknownKey), utils.JWTKeyFunc)
+	assert.ErrorIs(t, err, utils.ErrUnknownKey)
+
+	// A token signed with HS256 and the public key as secret must not pass for the key
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": "user-123", "exp": now.Add(time.Minute).Unix()})
+	forged.Header["kid"] = "new"
+	forgedString, err := forged.SignedString([]byte(newPrivate.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+	_, err = jwt.Parse(forgedString, utils.JWTKeyFunc)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package utils_test
+
+import (
+	"backend-developer-assignment/pkg/utils"
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPDFWriter(t *testing.T) {
+	var output bytes.Buffer
+	pdf := utils.NewPDFWriter(&output)
+	pdf.Text(utils.PDFCourier, 8, 40, 800, "First page (1)")
+	assert.NoError(t, pdf.NewPage())
+	pdf.Text(utils.PDFCourierBold, 8, 40, 800, `Caf\é ยินดี`)
+	assert.NoError(t, pdf.Close())
+
+	document := output.Bytes()
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(document, []byte("%%EOF\n")))
+	assert.Contains(t, string(document), `(First page \(1\)) Tj`)
+	assert.Contains(t, string(document), `(Caf\\\351 ?????) Tj`, "escaped, Latin-1 in octal, other characters replaced")
+	assert.Contains(t, string(document), "/Count 2")
+
+	// Every cross-reference entry points at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(document)
+	assert.NotNil(t, startxref)
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(document[xref:], -1)
+	assert.Len(t, entries, 8) // catalog, page tree, 2 fonts, 2 contents and 2 pages
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(document[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+func TestPDFWriterEmpty(t *testing.T) {
+	var output bytes.Buffer
+	assert.NoError(t, utils.NewPDFWriter(&output).Close())
+
+	assert.Contains(t, output.String(), "/Count 1", "a document has at least one page")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestPDFWriterError(t *testing.T) {
+	pdf := utils.NewPDFWriter(failingWriter{})
+	pdf.Text(utils.PDFCourier, 8, 40, 800, "Lost")
+
+	assert.EqualError(t, pdf.NewPage(), "connection closed")
+	assert.EqualError(t, pdf.Close(), "connection closed")
+}
@@ -0,0 +1,81 @@
+package utils_test
+
+import (
+	"backend-developer-assignment/pkg/utils"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	testCases := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1111111111, expected: "050471"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+		{unix: 20000000000, expected: "353130"},
+	}
+
+	for _, tc := range testCases {
+		code, err := utils.TOTPCode(rfcSecret, utils.TOTPStep(time.Unix(tc.unix, 0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, code, "time %d", tc.unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := utils.TOTPStep(now)
+
+	matched, ok := utils.VerifyTOTP(rfcSecret, "005924", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, step, matched)
+
+	previous, _ := utils.TOTPCode(rfcSecret, step-1)
+	matched, ok = utils.VerifyTOTP(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, step-1, matched)
+
+	tooOld, _ := utils.TOTPCode(rfcSecret, step-2)
+	_, ok = utils.VerifyTOTP(rfcSecret, tooOld, now, 1)
+	assert.False(t, ok)
+
+	_, ok = utils.VerifyTOTP("not base32!", "005924", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	first, err := utils.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	second, err := utils.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+	_, err = utils.TOTPCode(first, 1)
+	assert.NoError(t, err)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(utils.TOTPURI("Example Bank", "user-123", rfcSecret))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Example Bank:user-123", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Example Bank", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	"backend-developer-assignment/pkg/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWebhookSecret(t *testing.T) {
+	secret, err := utils.NewWebhookSecret()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "whsec_"))
+	assert.Len(t, secret, len("whsec_")+64)
+
+	other, err := utils.NewWebhookSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestSignWebhook(t *testing.T) {
+	// HMAC-SHA256("whsec_test", "1760000000.{}")
+	signature := utils.SignWebhook("whsec_test", time.Unix(1760000000, 0), []byte(`{}`))
+
+	assert.Equal(t, "t=1760000000,v1=36bd79465b49ac7ce6ef02dc49dc372557425a18a75801997a2c67271410b927", signature)
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"id":"event-1","type":"TransferCompleted"}`)
+	sentAt := time.Unix(1760000000, 0)
+	header := utils.SignWebhook(secret, sentAt, body)
+
+	testCases := []struct {
+		name     string
+		secret   string
+		header   string
+		body     []byte
+		now      time.Time
+		expected error
+	}{
+		{name: "valid", secret: secret, header: header, body: body, now: sentAt.Add(time.Minute)},
+		{name: "tampered body", secret: secret, header: header, body: []byte(`{"id":"event-2"}`), now: sentAt, expected: utils.ErrWebhookSignatureInvalid},
+		{name: "wrong secret", secret: "whsec_other", header: header, body: body, now: sentAt, expected: utils.ErrWebhookSignatureInvalid},
+		{name: "expired", secret: secret, header: header, body: body, now: sentAt.Add(6 * time.Minute), expected: utils.ErrWebhookSignatureExpired},
+		{name: "from the future", secret: secret, header: header, body: body, now: sentAt.Add(-6 * time.Minute), expected: utils.ErrWebhookSignatureExpired},
+		{name: "missing signature", secret: secret, header: "t=1760000000", body: body, now: sentAt, expected: utils.ErrWebhookSignatureInvalid},
+		{name: "malformed", secret: secret, header: "garbage", body: body, now: sentAt, expected: utils.ErrWebhookSignatureInvalid},
+		{name: "rotated secret", secret: secret, header: header + ",v1=" + strings.Repeat("0", 64), body: body, now: sentAt},
+	}
+
+	for _, tc := range testCases {
+		err := utils.VerifyWebhook(tc.secret, tc.header, tc.body, 5*time.Minute, tc.now)
+
+		assert.ErrorIs(t, err, tc.expected, tc.name)
+	}
+}
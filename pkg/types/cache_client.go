@@ -2,12 +2,54 @@ package types
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 )
 
+// ErrCacheMiss is returned by Get for a key that is not cached, any other error means the cache is unavailable
+var ErrCacheMiss = errors.New("cache miss")
+
 // Define a cache interface
 type CacheClient interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
+	// Increment adds one to the counter at key and returns the new count, a new counter expires after expiration
+	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// Decrement takes one off the counter at key and returns the new count, a counter that is missing or expired is
+	// left missing and reported as 0
+	Decrement(ctx context.Context, key string) (int64, error)
+	// NamespaceVersion returns the version the entries of a namespace are cached under, see NamespacedKey. A
+	// namespace that was never invalidated is at version "0".
+	NamespaceVersion(ctx context.Context, namespace string) (string, error)
+	// InvalidateNamespace moves a namespace to a version never used before, the entries cached under the previous
+	// one are never read again and expire on their own
+	InvalidateNamespace(ctx context.Context, namespace string) error
+}
+
+// NamespaceVersionTTL is how long the version of a namespace is kept after its last invalidation. Entries of a
+// namespace must expire sooner, so that none cached before the version expired can be read at version "0" again.
+const NamespaceVersionTTL = 24 * time.Hour
+
+// NamespacedKey is the key of an entry of a namespace at a version
+func NamespacedKey(namespace, version, key string) string {
+	return namespace + ":v" + version + ":" + key
+}
+
+// NamespaceVersionKey is the key holding the version of a namespace
+func NamespaceVersionKey(namespace string) string {
+	return namespace + ":version"
+}
+
+// NewNamespaceVersion returns a version for InvalidateNamespace. Versions come from the clock rather than a counter,
+// so that a version lost with its key, to expiry or eviction, is not handed out again.
+func NewNamespaceVersion() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// StreamEntry is an entry of a Redis stream
+type StreamEntry struct {
+	ID     string
+	Values map[string]interface{}
 }
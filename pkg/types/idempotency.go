@@ -0,0 +1,15 @@
+package types
+
+import "errors"
+
+// Custom errors for idempotent requests
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+)
+
+// IdempotentResponse is the stored response replayed for a repeated idempotency key
+type IdempotentResponse struct {
+	StatusCode int    `json:"status_code"`
+	Body       []byte `json:"body"`
+}
@@ -0,0 +1,334 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Custom errors for money operations
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidCurrency  = errors.New("invalid currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrAmountOverflow   = errors.New("amount overflow")
+)
+
+// DefaultCurrencyExponent is the number of decimal places used for currencies not listed in currencyExponents.
+// It matches the scale of the DECIMAL(15,2) money columns.
+const DefaultCurrencyExponent = 2
+
+// currencyExponents lists ISO 4217 currencies whose minor unit differs from DefaultCurrencyExponent
+var currencyExponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+}
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Money is an exact monetary amount stored in the minor unit of its ISO 4217 currency (e.g. satang for THB)
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney creates a Money value from an amount in minor units
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// ParseMoney parses a decimal string such as "1234.56" into Money without going through float64
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !IsValidCurrency(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	minor, err := parseMinorUnits(amount, CurrencyExponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// IsValidCurrency reports whether the given code looks like an ISO 4217 currency code
+func IsValidCurrency(currency string) bool {
+	return currencyCodePattern.MatchString(currency)
+}
+
+// CurrencyExponent returns the number of decimal places of the currency's minor unit
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return DefaultCurrencyExponent
+}
+
+// parseMinorUnits converts a decimal string into an integer amount scaled by 10^exp.
+// Trailing fractional zeros beyond exp are accepted so values read back from DECIMAL columns parse cleanly.
+func parseMinorUnits(s string, exp int) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, ErrInvalidAmount
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !isDigits(intPart) || (fracPart != "" && !isDigits(fracPart)) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	// Drop insignificant trailing zeros, then reject any precision the currency cannot represent
+	if len(fracPart) > exp {
+		if strings.Trim(fracPart[exp:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, s, exp)
+		}
+		fracPart = fracPart[:exp]
+	}
+	fracPart += strings.Repeat("0", exp-len(fracPart))
+
+	minor, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, ErrAmountOverflow
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return minor, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Decimal formats the amount as a plain decimal string in major units, e.g. "1234.56"
+func (m Money) Decimal() string {
+	return m.decimal(CurrencyExponent(m.Currency))
+}
+
+// decimal formats the amount with exp decimal places
+func (m Money) decimal(exp int) string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	// Work on the unsigned magnitude so math.MinInt64 formats correctly
+	magnitude := strconv.FormatUint(absUint64(amount), 10)
+	if exp == 0 {
+		return sign + magnitude
+	}
+
+	if len(magnitude) <= exp {
+		magnitude = strings.Repeat("0", exp-len(magnitude)+1) + magnitude
+	}
+
+	return sign + magnitude[:len(magnitude)-exp] + "." + magnitude[len(magnitude)-exp:]
+}
+
+func absUint64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+// String formats the money as "1234.56 THB"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative reports whether the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Neg returns the money with its sign flipped
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// SameCurrency reports whether both values are in the same currency
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency
+}
+
+// Add returns m + other, failing if the currencies differ or the result overflows
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other, failing if the currencies differ or the result overflows
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrAmountOverflow
+	}
+	return m.Add(other.Neg())
+}
+
+// MulDiv returns m * numerator / denominator rounded half away from zero, e.g. the share of m matching part of a whole
+func (m Money) MulDiv(numerator, denominator int64) (Money, error) {
+	if denominator == 0 {
+		return Money{}, ErrInvalidAmount
+	}
+
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator))
+	amount, err := divRound(product, big.NewInt(denominator))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if !m.SameCurrency(other) {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// LessThan reports whether m < other. Values in different currencies never compare as less.
+func (m Money) LessThan(other Money) bool {
+	c, err := m.Cmp(other)
+	return err == nil && c < 0
+}
+
+// moneyJSON is the wire format of Money. The amount is a decimal string so clients never round through floats.
+type moneyJSON struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON encodes money as {"amount":"1234.56","currency":"THB"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.Decimal(),
+		Currency: m.Currency,
+	})
+}
+
+// UnmarshalJSON decodes the object produced by MarshalJSON. The amount may be a JSON string or number.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	currency := strings.ToUpper(raw.Currency)
+	minor, err := parseMinorUnits(raw.Amount.String(), CurrencyExponent(currency))
+	if err != nil {
+		return err
+	}
+
+	m.Amount = minor
+	m.Currency = currency
+	return nil
+}
+
+// Value implements driver.Valuer, writing the amount as a decimal in major units.
+// The currency is persisted separately by the repositories.
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+// Scan implements sql.Scanner. It accepts a bare decimal ("1234.56") or a decimal followed by
+// its currency ("1234.56 THB"), which the repositories select with CONCAT(amount, ' ', currency).
+func (m *Money) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		// Only reached by drivers that do not return DECIMAL as text
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	amount, currency, _ := strings.Cut(strings.TrimSpace(s), " ")
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+
+	minor, err := parseMinorUnits(amount, CurrencyExponent(currency))
+	if err != nil {
+		return err
+	}
+
+	m.Amount = minor
+	m.Currency = currency
+	return nil
+}
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRate is returned for exchange rates that are not a positive decimal
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// RateExponent is the number of decimal places kept for exchange rates. It matches the DECIMAL(20,8) rate columns.
+const RateExponent = 8
+
+// rateScale is 10^RateExponent
+var rateScale = big.NewInt(100000000)
+
+// Rate is an exact exchange rate: one unit of the source currency buys Rate units of the target currency
+type Rate struct {
+	value int64 // rate scaled by 10^RateExponent
+}
+
+// ParseRate parses a decimal string such as "36.5025" into a Rate without going through float64
+func ParseRate(rate string) (Rate, error) {
+	value, err := parseMinorUnits(rate, RateExponent)
+	if err != nil {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+	if value <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q must be greater than zero", ErrInvalidRate, rate)
+	}
+
+	return Rate{value: value}, nil
+}
+
+// MustParseRate is like ParseRate but panics on invalid input. Meant for constants and tests.
+func MustParseRate(rate string) Rate {
+	r, err := ParseRate(rate)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// IsZero reports whether the rate is unset
+func (r Rate) IsZero() bool {
+	return r.value == 0
+}
+
+// String formats the rate as a plain decimal string, e.g. "36.50000000"
+func (r Rate) String() string {
+	return Money{Amount: r.value}.decimal(RateExponent)
+}
+
+// Convert returns amount expressed in the target currency, rounded half away from zero to the target's minor unit
+func (r Rate) Convert(amount Money, currency string) (Money, error) {
+	if r.value <= 0 {
+		return Money{}, ErrInvalidRate
+	}
+	currency = strings.ToUpper(currency)
+	if !IsValidCurrency(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	// converted = amount * rate * 10^targetExp / (10^RateExponent * 10^sourceExp)
+	numerator := new(big.Int).Mul(big.NewInt(amount.Amount), big.NewInt(r.value))
+	numerator.Mul(numerator, pow10(CurrencyExponent(currency)))
+	denominator := new(big.Int).Mul(rateScale, pow10(CurrencyExponent(amount.Currency)))
+
+	converted, err := divRound(numerator, denominator)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: converted, Currency: currency}, nil
+}
+
+// Inverse returns the rate of the opposite direction, rounded to RateExponent decimal places
+func (r Rate) Inverse() (Rate, error) {
+	if r.value <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	numerator := new(big.Int).Mul(rateScale, rateScale)
+	value, err := divRound(numerator, big.NewInt(r.value))
+	if err != nil {
+		return Rate{}, err
+	}
+	if value == 0 {
+		return Rate{}, fmt.Errorf("%w: inverse of %s is below %d decimal places", ErrInvalidRate, r, RateExponent)
+	}
+
+	return Rate{value: value}, nil
+}
+
+// pow10 returns 10^exp as a big.Int
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+// divRound divides n by d rounding half away from zero, failing when the quotient does not fit in an int64
+func divRound(n, d *big.Int) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+
+	// Round away from zero when the remainder is at least half of the divisor
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(d)) >= 0 {
+		if n.Sign()*d.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return quotient.Int64(), nil
+}
+
+// MarshalJSON encodes the rate as a decimal string so clients never round through floats
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON decodes a rate given as a JSON string or number
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var raw json.Number
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRate, data)
+	}
+
+	parsed, err := ParseRate(raw.String())
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the rate as a decimal
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL rate columns
+func (r *Rate) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		// Only reached by drivers that do not return DECIMAL as text
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Rate", src)
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import "sort"
+
+// Role is a staff role granting permissions on the admin API, customers have no role
+type Role string
+
+const (
+	RoleSupport    Role = "support"
+	RoleOperations Role = "operations"
+	RoleMarketing  Role = "marketing"
+	RoleAdmin      Role = "admin"
+)
+
+// Permission is an operation of the admin API
+type Permission string
+
+const (
+	PermissionUsersRead           Permission = "users:read"
+	PermissionAccountsFreeze      Permission = "accounts:freeze"
+	PermissionCardsStatus         Permission = "cards:status"
+	PermissionBannersManage       Permission = "banners:manage"
+	PermissionAuditRead           Permission = "audit:read"
+	PermissionWebhooksManage      Permission = "webhooks:manage"
+	PermissionTransactionsReverse Permission = "transactions:reverse"
+)
+
+// RolePermissions lists the permissions granted by each role
+var RolePermissions = map[Role][]Permission{
+	RoleSupport:    {PermissionUsersRead, PermissionCardsStatus},
+	RoleOperations: {PermissionUsersRead, PermissionAccountsFreeze, PermissionCardsStatus, PermissionTransactionsReverse},
+	RoleMarketing:  {PermissionBannersManage},
+	RoleAdmin:      {PermissionUsersRead, PermissionAccountsFreeze, PermissionCardsStatus, PermissionBannersManage, PermissionAuditRead, PermissionWebhooksManage, PermissionTransactionsReverse},
+}
+
+// PermissionsOf returns the sorted permissions granted by any of the roles, unknown roles grant nothing
+func PermissionsOf(roles []Role) []Permission {
+	granted := map[Permission]bool{}
+	for _, role := range roles {
+		for _, permission := range RolePermissions[role] {
+			granted[permission] = true
+		}
+	}
+
+	permissions := make([]Permission, 0, len(granted))
+	for permission := range granted {
+		permissions = append(permissions, permission)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
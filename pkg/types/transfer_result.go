@@ -2,6 +2,8 @@ package types
 
 // TransferResult contains the result of a transfer operation
 type TransferResult struct {
-	SourceBalance      float64
-	DestinationBalance float64
+	SourceBalance      Money
+	DestinationBalance Money
+	CreditedAmount     Money // amount credited to the destination, in its currency
+	ExchangeRate       *Rate // applied rate, nil when both accounts use the same currency
 }
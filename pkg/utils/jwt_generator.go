@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"backend-developer-assignment/pkg/types"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -14,51 +16,75 @@ import (
 
 // Tokens struct to describe tokens object.
 type Tokens struct {
-	Access  string
-	Refresh string
+	Access           string
+	Refresh          string
+	RefreshExpiresAt time.Time
 }
 
 // GenerateNewTokens func for generate a new Access & Refresh tokens.
-func GenerateNewTokens(id string) (*Tokens, error) {
+func GenerateNewTokens(id, sessionID string, roles []types.Role) (*Tokens, error) {
 	// Generate JWT Access token.
-	accessToken, err := generateNewAccessToken(id)
+	accessToken, err := generateNewAccessToken(id, sessionID, roles)
 	if err != nil {
 		// Return token generation error.
 		return nil, err
 	}
 
 	// Generate JWT Refresh token.
-	refreshToken, err := generateNewRefreshToken()
+	refreshToken, refreshExpiresAt, err := generateNewRefreshToken()
 	if err != nil {
 		// Return token generation error.
 		return nil, err
 	}
 
 	return &Tokens{
-		Access:  accessToken,
-		Refresh: refreshToken,
+		Access:           accessToken,
+		Refresh:          refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
 	}, nil
 }
 
-func generateNewAccessToken(id string) (string, error) {
-	// Set secret key from .env file.
-	secret := os.Getenv("JWT_SECRET_KEY")
-
-	// Set expires minutes count for secret key from .env file.
+// AccessTokenTTL func for the lifetime of access tokens, set in minutes in .env file.
+func AccessTokenTTL() time.Duration {
 	minutesCount, _ := strconv.Atoi(os.Getenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT"))
+	return time.Minute * time.Duration(minutesCount)
+}
+
+func generateNewAccessToken(id, sessionID string, roles []types.Role) (string, error) {
+	// Get the newest key of the keyring.
+	keyring := CurrentKeyring()
+	if keyring == nil {
+		return "", ErrKeyringNotLoaded
+	}
+	key, err := keyring.SigningKey(time.Now())
+	if err != nil {
+		return "", err
+	}
 
 	// Create a new claims.
 	claims := jwt.MapClaims{}
 
 	// Set public claims:
 	claims["id"] = id
-	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutesCount)).Unix()
+	claims["exp"] = time.Now().Add(AccessTokenTTL()).Unix()
+
+	// Set the session of the sign-in, so that the token stops working when the session is revoked:
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+
+	// Set the roles of staff and the permissions they grant, so that other services can authorize without a lookup:
+	if len(roles) > 0 {
+		claims["roles"] = roles
+		claims["permissions"] = types.PermissionsOf(roles)
+	}
 
-	// Create a new JWT access token with claims.
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Create a new JWT access token with claims, the kid tells verifiers which public key to use.
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.KID
 
 	// Generate token.
-	t, err := token.SignedString([]byte(secret))
+	t, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		// Return error, it JWT token generation failed.
 		return "", err
@@ -67,30 +93,33 @@ func generateNewAccessToken(id string) (string, error) {
 	return t, nil
 }
 
-func generateNewRefreshToken() (string, error) {
-	// Create a new SHA256 hash.
-	hash := sha256.New()
-
-	// Create a new now date and time string with salt.
-	refresh := os.Getenv("JWT_REFRESH_KEY") + time.Now().String()
+// RefreshTokenTTL func for the lifetime of refresh tokens, set in hours in .env file.
+func RefreshTokenTTL() time.Duration {
+	hoursCount, _ := strconv.Atoi(os.Getenv("JWT_REFRESH_KEY_EXPIRE_HOURS_COUNT"))
+	return time.Hour * time.Duration(hoursCount)
+}
 
-	// See: https://pkg.go.dev/io#Writer.Write
-	_, err := hash.Write([]byte(refresh))
-	if err != nil {
+func generateNewRefreshToken() (string, time.Time, error) {
+	// Create a new random secret, the token is only valid once its hash is stored by the refresh token store.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
 		// Return error, it refresh token generation failed.
-		return "", err
+		return "", time.Time{}, err
 	}
 
-	// Set expires hours count for refresh key from .env file.
-	hoursCount, _ := strconv.Atoi(os.Getenv("JWT_REFRESH_KEY_EXPIRE_HOURS_COUNT"))
-
 	// Set expiration time.
-	expireTime := fmt.Sprint(time.Now().Add(time.Hour * time.Duration(hoursCount)).Unix())
+	expireTime := time.Now().Add(RefreshTokenTTL()).Truncate(time.Second)
 
-	// Create a new refresh token (sha256 string with salt + expire time).
-	t := hex.EncodeToString(hash.Sum(nil)) + "." + expireTime
+	// Create a new refresh token (random hex string + expire time).
+	t := hex.EncodeToString(secret) + "." + fmt.Sprint(expireTime.Unix())
 
-	return t, nil
+	return t, expireTime, nil
+}
+
+// HashRefreshToken func for hashing a refresh token before it is stored or looked up.
+func HashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(os.Getenv("JWT_REFRESH_KEY") + refreshToken))
+	return hex.EncodeToString(sum[:])
 }
 
 // ParseRefreshToken func for parse second argument from refresh token.
@@ -0,0 +1,350 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Minimum size of RSA signing keys
+const minRSAKeyBits = 2048
+
+var (
+	ErrKeyringNotLoaded = errors.New("JWT keyring is not loaded")
+	ErrNoSigningKey     = errors.New("no JWT signing key is valid now")
+	ErrUnknownKey       = errors.New("unknown or expired JWT key")
+)
+
+// JWTKey is a key of the keyring. Keys without a private key only verify tokens, they are kept
+// after their private key has been destroyed so that tokens signed with it stay valid until they expire.
+type JWTKey struct {
+	KID        string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+	NotBefore  time.Time // tokens are signed with the key from then on
+	NotAfter   time.Time // tokens signed with the key are accepted until then, zero means no end
+}
+
+func (k *JWTKey) verifiesAt(now time.Time) bool {
+	return k.NotAfter.IsZero() || now.Before(k.NotAfter)
+}
+
+func (k *JWTKey) signsAt(now time.Time) bool {
+	return k.PrivateKey != nil && !now.Before(k.NotBefore) && k.verifiesAt(now)
+}
+
+// Keyring holds the keys access tokens are signed and verified with. Validity windows of keys may overlap,
+// new tokens are signed with the newest key while tokens of the previous key are still accepted.
+type Keyring struct {
+	keys []*JWTKey
+}
+
+// NewKeyring creates a keyring from keys with unique key IDs
+func NewKeyring(keys ...*JWTKey) (*Keyring, error) {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.KID == "" {
+			return nil, errors.New("JWT key without kid")
+		}
+		if seen[key.KID] {
+			return nil, fmt.Errorf("duplicate JWT key %q", key.KID)
+		}
+		seen[key.KID] = true
+
+		if !key.NotAfter.IsZero() && !key.NotAfter.After(key.NotBefore) {
+			return nil, fmt.Errorf("JWT key %q: not_after must be after not_before", key.KID)
+		}
+		if key.PrivateKey != nil {
+			key.PublicKey = key.PrivateKey.Public()
+		}
+		algorithm, err := jwtAlgorithm(key.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("JWT key %q: %w", key.KID, err)
+		}
+		key.Algorithm = algorithm
+	}
+
+	// Newest keys first, the signing key is the first one that can sign
+	sorted := append([]*JWTKey(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].NotBefore.After(sorted[j].NotBefore) })
+
+	return &Keyring{keys: sorted}, nil
+}
+
+// NewEphemeralKeyring creates a keyring with a single Ed25519 key that only lives in memory
+func NewEphemeralKeyring() (*Keyring, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+
+	return NewKeyring(&JWTKey{
+		KID:        "ephemeral-" + base64.RawURLEncoding.EncodeToString(kid),
+		PrivateKey: privateKey,
+	})
+}
+
+// SigningKey returns the newest key that can sign tokens at the given time
+func (k *Keyring) SigningKey(now time.Time) (*JWTKey, error) {
+	for _, key := range k.keys {
+		if key.signsAt(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// VerificationKey returns the key tokens with the given key ID are verified with at the given time
+func (k *Keyring) VerificationKey(kid string, now time.Time) (*JWTKey, error) {
+	for _, key := range k.keys {
+		if key.KID == kid && key.verifiesAt(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// JWK is the public part of a key as published in the JWKS document, RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty" example:"OKP"`
+	KeyID     string `json:"kid" example:"2026-10"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"EdDSA"`
+	Curve     string `json:"crv,omitempty" example:"Ed25519"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKSet is the JWKS document other services verify our access tokens with
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that are not expired at the given time. Keys that only start signing later
+// are published too, so that verifiers have them before the first token signed with them arrives.
+func (k *Keyring) JWKS(now time.Time) JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		if !key.verifiesAt(now) {
+			continue
+		}
+
+		jwk := JWK{KeyID: key.KID, Use: "sig", Algorithm: key.Algorithm}
+		switch publicKey := key.PublicKey.(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func jwtAlgorithm(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey := publicKey.(type) {
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA.Alg(), nil
+	case *rsa.PublicKey:
+		if publicKey.N.BitLen() < minRSAKeyBits {
+			return "", fmt.Errorf("RSA key must have at least %d bits", minRSAKeyBits)
+		}
+		return jwt.SigningMethodRS256.Alg(), nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T, use RSA or Ed25519", publicKey)
+	}
+}
+
+// keyringFile describes the keys of the keyring, key paths are relative to the file
+type keyringFile struct {
+	Keys []struct {
+		KID        string    `json:"kid"`
+		PrivateKey string    `json:"private_key"`
+		PublicKey  string    `json:"public_key"`
+		NotBefore  time.Time `json:"not_before"`
+		NotAfter   time.Time `json:"not_after"`
+	} `json:"keys"`
+}
+
+// LoadKeyring loads the keyring described by a JSON file listing PEM encoded keys and their validity
+func LoadKeyring(path string) (*Keyring, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid keyring file %s: %w", path, err)
+	}
+	if len(file.Keys) == 0 {
+		return nil, fmt.Errorf("keyring file %s has no keys", path)
+	}
+
+	dir := filepath.Dir(path)
+	keys := make([]*JWTKey, 0, len(file.Keys))
+	for _, entry := range file.Keys {
+		key := &JWTKey{KID: entry.KID, NotBefore: entry.NotBefore, NotAfter: entry.NotAfter}
+
+		switch {
+		case entry.PrivateKey != "":
+			key.PrivateKey, err = readPrivateKey(resolveKeyPath(dir, entry.PrivateKey))
+		case entry.PublicKey != "":
+			key.PublicKey, err = readPublicKey(resolveKeyPath(dir, entry.PublicKey))
+		default:
+			err = errors.New("private_key or public_key is required")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("JWT key %q: %w", entry.KID, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeyring(keys...)
+}
+
+// LoadKeyringFromEnv loads the keyring file set in JWT_KEYRING_FILE. Without it an ephemeral key is used
+// outside of production, tokens then do not survive a restart and are not accepted by other instances.
+func LoadKeyringFromEnv() (*Keyring, error) {
+	path := os.Getenv("JWT_KEYRING_FILE")
+	if path != "" {
+		return LoadKeyring(path)
+	}
+
+	if os.Getenv("APP_ENV") == "prod" {
+		return nil, errors.New("JWT_KEYRING_FILE is required in production")
+	}
+
+	log.Println("JWT_KEYRING_FILE is not set, signing access tokens with an ephemeral key")
+	return NewEphemeralKeyring()
+}
+
+func resolveKeyPath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+var currentKeyring atomic.Pointer[Keyring]
+
+// SetKeyring replaces the keyring tokens are signed and verified with
+func SetKeyring(keyring *Keyring) {
+	currentKeyring.Store(keyring)
+}
+
+// CurrentKeyring returns the keyring tokens are signed and verified with, nil until one is set
+func CurrentKeyring() *Keyring {
+	return currentKeyring.Load()
+}
+
+// ReloadKeyring reloads the keyring file so that keys added to it are used without a restart,
+// the current keyring is kept when the file cannot be loaded
+func ReloadKeyring() error {
+	path := os.Getenv("JWT_KEYRING_FILE")
+	if path == "" {
+		return nil
+	}
+
+	keyring, err := LoadKeyring(path)
+	if err != nil {
+		return err
+	}
+
+	SetKeyring(keyring)
+	return nil
+}
+
+// JWTKeyFunc returns the public key of the kid in the token header, the algorithm has to be the one of the key
+func JWTKeyFunc(token *jwt.Token) (interface{}, error) {
+	keyring := CurrentKeyring()
+	if keyring == nil {
+		return nil, ErrKeyringNotLoaded
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, err := keyring.VerificationKey(kid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+
+	return key.PublicKey, nil
+}
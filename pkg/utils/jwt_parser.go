@@ -1,7 +1,7 @@
 package utils
 
 import (
-	"os"
+	"backend-developer-assignment/pkg/types"
 	"strings"
 
 	fiber "github.com/gofiber/fiber/v2"
@@ -10,8 +10,11 @@ import (
 
 // TokenMetadata struct to describe metadata in JWT.
 type TokenMetadata struct {
-	UserID  string
-	Expires int64
+	UserID      string
+	SessionID   string
+	Roles       []types.Role
+	Permissions []types.Permission
+	Expires     int64
 }
 
 // ExtractTokenMetadata func to extract metadata from JWT.
@@ -37,15 +40,44 @@ func ExtractTokenMetadata(ctx *fiber.Ctx) (*TokenMetadata, error) {
 		}
 		expires := int64(expiresFloat)
 
+		// Session of the sign-in, tokens issued before sessions existed have none.
+		sessionID, _ := claims["sid"].(string)
+
+		// Roles and permissions, only staff tokens have them.
+		var roles []types.Role
+		for _, role := range stringClaims(claims, "roles") {
+			roles = append(roles, types.Role(role))
+		}
+		var permissions []types.Permission
+		for _, permission := range stringClaims(claims, "permissions") {
+			permissions = append(permissions, types.Permission(permission))
+		}
+
 		return &TokenMetadata{
-			UserID:  userID,
-			Expires: expires,
+			UserID:      userID,
+			SessionID:   sessionID,
+			Roles:       roles,
+			Permissions: permissions,
+			Expires:     expires,
 		}, nil
 	}
 
 	return nil, err
 }
 
+// stringClaims returns the strings of an array claim, missing claims and other values are skipped
+func stringClaims(claims jwt.MapClaims, name string) []string {
+	values, _ := claims[name].([]interface{})
+
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
 func extractToken(c *fiber.Ctx) string {
 	bearToken := c.Get("Authorization")
 
@@ -61,7 +93,7 @@ func extractToken(c *fiber.Ctx) string {
 func verifyToken(c *fiber.Ctx) (*jwt.Token, error) {
 	tokenString := extractToken(c)
 
-	token, err := jwt.Parse(tokenString, jwtKeyFunc)
+	token, err := jwt.Parse(tokenString, JWTKeyFunc, jwt.WithValidMethods(jwtValidMethods))
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +101,5 @@ func verifyToken(c *fiber.Ctx) (*jwt.Token, error) {
 	return token, nil
 }
 
-func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
-	return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-}
+// jwtValidMethods are the asymmetric algorithms of the keyring, symmetric tokens are never accepted
+var jwtValidMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
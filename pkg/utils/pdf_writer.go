@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A4 page size in points, the unit of PDF coordinates
+const (
+	PDFPageWidth  = 595
+	PDFPageHeight = 842
+)
+
+// PDFFont is one of the standard fonts every PDF reader provides, so none is embedded. They only cover Latin-1,
+// other characters are written as "?".
+type PDFFont string
+
+const (
+	PDFCourier     PDFFont = "F1"
+	PDFCourierBold PDFFont = "F2"
+)
+
+// Objects written before the pages, the page tree and catalog are written once every page is known
+const (
+	pdfCatalogObject = 1
+	pdfPagesObject   = 2
+	pdfFontObjects   = 2 // Courier and Courier-Bold follow the page tree
+)
+
+// PDFWriter writes a PDF document of text pages as it goes: a page is written out once the next one starts, so only
+// the page being filled is kept in memory however long the document is. Errors of the underlying writer are kept
+// and returned by NewPage and Close.
+type PDFWriter struct {
+	w       io.Writer
+	written int64
+	offsets []int64 // byte offset of each object, object n at index n-1
+	pages   []int   // object numbers of the pages written
+	page    *bytes.Buffer
+	err     error
+}
+
+// NewPDFWriter starts a PDF document on w
+func NewPDFWriter(w io.Writer) *PDFWriter {
+	p := &PDFWriter{w: w, offsets: make([]int64, pdfPagesObject+pdfFontObjects)}
+
+	// The binary comment tells transfer tools the file is not text
+	p.write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	p.writeObject(pdfPagesObject+1, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	p.writeObject(pdfPagesObject+2, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	return p
+}
+
+// NewPage ends the page being filled, if any, and starts a new one
+func (p *PDFWriter) NewPage() error {
+	p.endPage()
+	p.page = &bytes.Buffer{}
+	return p.err
+}
+
+// Text writes text in the font and size with its baseline starting x points from the left and y points from the
+// bottom of the page
+func (p *PDFWriter) Text(font PDFFont, size, x, y float64, text string) {
+	if p.page == nil {
+		p.NewPage()
+	}
+	fmt.Fprintf(p.page, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfString(text))
+}
+
+// Close ends the last page and writes the page tree and the cross-reference table. It does not close the
+// underlying writer.
+func (p *PDFWriter) Close() error {
+	if p.page == nil && len(p.pages) == 0 {
+		p.NewPage() // a document has at least one page
+	}
+	p.endPage()
+
+	kids := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		kids[i] = strconv.Itoa(page) + " 0 R"
+	}
+	p.writeObject(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	p.writeObject(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject))
+
+	xref := p.written
+	p.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)+1))
+	for _, offset := range p.offsets {
+		p.write(fmt.Sprintf("%010d 00000 n \n", offset))
+	}
+	p.write(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.offsets)+1, pdfCatalogObject, xref))
+	return p.err
+}
+
+// endPage writes the content of the page being filled and the page itself
+func (p *PDFWriter) endPage() {
+	if p.page == nil {
+		return
+	}
+
+	content := p.newObject()
+	p.writeObject(content, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.page.Len(), p.page.Bytes()))
+
+	page := p.newObject()
+	p.writeObject(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s %d 0 R /%s %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObject, PDFPageWidth, PDFPageHeight, PDFCourier, pdfPagesObject+1, PDFCourierBold, pdfPagesObject+2, content))
+
+	p.pages = append(p.pages, page)
+	p.page = nil
+}
+
+// newObject reserves the next object number
+func (p *PDFWriter) newObject() int {
+	p.offsets = append(p.offsets, 0)
+	return len(p.offsets)
+}
+
+func (p *PDFWriter) writeObject(object int, body string) {
+	p.offsets[object-1] = p.written
+	p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", object, body))
+}
+
+func (p *PDFWriter) write(s string) {
+	if p.err != nil {
+		return
+	}
+	n, err := io.WriteString(p.w, s)
+	p.written += int64(n)
+	p.err = err
+}
+
+func pdfNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// pdfString escapes text for a literal string in WinAnsiEncoding, which matches Latin-1 for the characters kept
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
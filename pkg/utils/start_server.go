@@ -10,8 +10,9 @@ import (
 	fiber "github.com/gofiber/fiber/v2"
 )
 
-// StartServerWithGracefulShutdown function for starting server with a graceful shutdown.
-func StartServerWithGracefulShutdown(app *fiber.App, redisClient *cache.RedisClient) {
+// StartServerWithGracefulShutdown function for starting server with a graceful shutdown. The beforeShutdown functions
+// run once the signal is received, e.g. to end long-lived connections the shutdown would otherwise wait for.
+func StartServerWithGracefulShutdown(app *fiber.App, redisClient *cache.RedisClient, beforeShutdown ...func()) {
 	// Build Fiber connection URL
 	fiberConnURL, _ := ConnectionURLBuilder("fiber")
 
@@ -28,6 +29,10 @@ func StartServerWithGracefulShutdown(app *fiber.App, redisClient *cache.RedisCli
 	sig := <-sigChannel // This blocks the main thread until an interrupt is received
 	log.Printf("Received signal: %v. Shutting down gracefully...", sig)
 
+	for _, fn := range beforeShutdown {
+		fn()
+	}
+
 	// Shutdown Fiber server
 	if err := app.Shutdown(); err != nil {
 		// Error from closing listeners, or context timeout:
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"container/list"
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryClient is an in-process cache of at most maxEntries keys, the least recently used key is evicted to make
+// room for a new one. Values are kept as the strings Redis would store, so it stands in for RedisClient in local
+// development and tests, and keeps the local copies of a TieredClient.
+type MemoryClient struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	recency    *list.List // most recently used entry first
+}
+
+// memoryEntry is a cached value, expiresAt is zero for a value that does not expire
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// NewMemoryClient creates an in-process cache keeping at most maxEntries keys
+func NewMemoryClient(maxEntries int) *MemoryClient {
+	return &MemoryClient{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+	}
+}
+
+// Set stores a key-value pair with expiration, a zero expiration keeps it until it is evicted
+func (m *MemoryClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	stored, err := memoryValue(value)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(key, stored, expiration)
+	return nil
+}
+
+// Get retrieves a value by key, a missing or expired key is reported as types.ErrCacheMiss
+func (m *MemoryClient) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		return "", types.ErrCacheMiss
+	}
+	return entry.value, nil
+}
+
+// Delete removes a key
+func (m *MemoryClient) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		m.remove(element)
+	}
+	return nil
+}
+
+// Increment increments the counter at key, a new counter expires after expiration
+func (m *MemoryClient) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		m.set(key, "1", expiration)
+		return 1, nil
+	}
+
+	count, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of %s is not an integer", key)
+	}
+	count++
+	entry.value = strconv.FormatInt(count, 10)
+	return count, nil
+}
+
+// Decrement decrements the counter at key, a missing counter is left missing
+func (m *MemoryClient) Decrement(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		return 0, nil
+	}
+
+	count, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of %s is not an integer", key)
+	}
+	count--
+	entry.value = strconv.FormatInt(count, 10)
+	return count, nil
+}
+
+// NamespaceVersion returns the version of a namespace, "0" until it is first invalidated
+func (m *MemoryClient) NamespaceVersion(ctx context.Context, namespace string) (string, error) {
+	version, err := m.Get(ctx, types.NamespaceVersionKey(namespace))
+	if errors.Is(err, types.ErrCacheMiss) {
+		return "0", nil
+	}
+	return version, err
+}
+
+// InvalidateNamespace moves a namespace to a new version
+func (m *MemoryClient) InvalidateNamespace(ctx context.Context, namespace string) error {
+	return m.Set(ctx, types.NamespaceVersionKey(namespace), types.NewNamespaceVersion(), types.NamespaceVersionTTL)
+}
+
+// Len returns the number of keys kept, expired keys included until they are read or evicted
+func (m *MemoryClient) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.recency.Len()
+}
+
+// Flush removes every key
+func (m *MemoryClient) Flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*list.Element)
+	m.recency.Init()
+}
+
+// get returns the live entry of key and marks it as the most recently used, an expired entry is removed
+func (m *MemoryClient) get(key string) *memoryEntry {
+	element, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		m.remove(element)
+		return nil
+	}
+	m.recency.MoveToFront(element)
+	return entry
+}
+
+// set stores the value of key as the most recently used, evicting the least recently used keys over maxEntries
+func (m *MemoryClient) set(key, value string, expiration time.Duration) {
+	entry := &memoryEntry{key: key, value: value}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.recency.MoveToFront(element)
+		return
+	}
+	m.entries[key] = m.recency.PushFront(entry)
+
+	for m.recency.Len() > m.maxEntries {
+		m.remove(m.recency.Back())
+	}
+}
+
+func (m *MemoryClient) remove(element *list.Element) {
+	m.recency.Remove(element)
+	delete(m.entries, element.Value.(*memoryEntry).key)
+}
+
+// memoryValue formats a value the way the Redis client writes it
+func memoryValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		return string(data), err
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("can't cache a value of type %T", value)
+	}
+}
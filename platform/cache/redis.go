@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"backend-developer-assignment/pkg/types"
 	"context"
+	"errors"
 	"time"
 
 	redis "github.com/go-redis/redis/v8"
@@ -30,9 +32,13 @@ func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, ex
 	return r.Client.Set(ctx, key, value, expiration).Err()
 }
 
-// Get retrieves a value from Redis by key
+// Get retrieves a value from Redis by key, a missing key is reported as types.ErrCacheMiss
 func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
-	return r.Client.Get(ctx, key).Result()
+	value, err := r.Client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", types.ErrCacheMiss
+	}
+	return value, err
 }
 
 // Delete removes a key from Redis
@@ -40,6 +46,115 @@ func (r *RedisClient) Delete(ctx context.Context, key string) error {
 	return r.Client.Del(ctx, key).Err()
 }
 
+// NamespaceVersion returns the version of a namespace, "0" until it is first invalidated
+func (r *RedisClient) NamespaceVersion(ctx context.Context, namespace string) (string, error) {
+	version, err := r.Client.Get(ctx, types.NamespaceVersionKey(namespace)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return version, err
+}
+
+// InvalidateNamespace moves a namespace to a new version, shared by every instance through Redis
+func (r *RedisClient) InvalidateNamespace(ctx context.Context, namespace string) error {
+	return r.Client.Set(ctx, types.NamespaceVersionKey(namespace), types.NewNamespaceVersion(), types.NamespaceVersionTTL).Err()
+}
+
+// incrementScript starts the expiry with the counter, so a counter never outlives its window
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Increment atomically increments a counter in Redis, a new counter expires after expiration
+func (r *RedisClient) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, r.Client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
+// decrementScript only decrements a counter that exists, so that a counter is never created without its expiry
+var decrementScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+return redis.call("DECR", KEYS[1])
+`)
+
+// Decrement atomically decrements a counter in Redis, keeping its expiry
+func (r *RedisClient) Decrement(ctx context.Context, key string) (int64, error) {
+	return decrementScript.Run(ctx, r.Client, []string{key}).Int64()
+}
+
+// AddToStream appends an entry to a Redis stream trimmed to about maxLen entries and returns the entry ID
+func (r *RedisClient) AddToStream(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error) {
+	return r.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+}
+
+// ReadStream returns up to count entries of a Redis stream from the entry fromID on, "-" reads from the first entry
+func (r *RedisClient) ReadStream(ctx context.Context, stream, fromID string, count int64) ([]types.StreamEntry, error) {
+	messages, err := r.Client.XRangeN(ctx, stream, fromID, "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]types.StreamEntry, len(messages))
+	for i, message := range messages {
+		entries[i] = types.StreamEntry{ID: message.ID, Values: message.Values}
+	}
+	return entries, nil
+}
+
+// Expire sets the time to live of a key
+func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return r.Client.Expire(ctx, key, expiration).Err()
+}
+
+// PublishMessage sends a message to the subscribers of a pub/sub channel
+func (r *RedisClient) PublishMessage(ctx context.Context, channel, message string) error {
+	return r.Client.Publish(ctx, channel, message).Err()
+}
+
+// SubscribeChannel subscribes to a pub/sub channel and returns its messages until ctx is done. It returns once the
+// subscription is confirmed, the connection is restored after a failure but messages sent meanwhile are lost.
+func (r *RedisClient) SubscribeChannel(ctx context.Context, channel string) (<-chan string, error) {
+	pubSub := r.Client.Subscribe(ctx, channel)
+	if _, err := pubSub.Receive(ctx); err != nil {
+		pubSub.Close()
+		return nil, err
+	}
+
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		defer pubSub.Close()
+
+		received := pubSub.Channel()
+		for {
+			select {
+			case message, ok := <-received:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- message.Payload:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return messages, nil
+}
+
 // Close closes the Redis client connection
 func (r *RedisClient) Close() error {
 	return r.Client.Close()
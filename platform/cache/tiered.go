@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"backend-developer-assignment/pkg/types"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SharedCache is the cache shared by every instance behind a TieredClient, with the pub/sub channel its writes
+// are broadcast on. It is implemented by RedisClient.
+type SharedCache interface {
+	types.CacheClient
+	PublishMessage(ctx context.Context, channel, message string) error
+	SubscribeChannel(ctx context.Context, channel string) (<-chan string, error)
+}
+
+var _ SharedCache = (*RedisClient)(nil)
+
+// TieredClient keeps local copies (L1) of the values read from a shared cache (L2), so that a hot key is read
+// without a network round trip. Every write goes to L2 and is broadcast on a channel, each instance then drops its
+// copy of the key. Pub/sub delivers at most once, a broadcast lost while the subscription reconnects leaves a stale
+// copy for up to localTTL.
+type TieredClient struct {
+	local    *MemoryClient
+	shared   SharedCache
+	channel  string
+	localTTL time.Duration
+	origin   string // tells the broadcasts of this instance apart
+	stopFeed context.CancelFunc
+
+	// invalidations counts the keys dropped from L1, a value read from L2 is only copied to L1 when no key was
+	// dropped while it was read, so that a copy never outlives a write broadcast meanwhile
+	mu            sync.Mutex
+	invalidations uint64
+	feedDone      bool // once the subscription ends, nothing is copied to L1 any more
+}
+
+// invalidationMessage is a write broadcast on the channel
+type invalidationMessage struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key"`
+}
+
+// NewTieredClient creates a TieredClient keeping copies in local for up to localTTL. It returns once subscribed to
+// the channel, copies are only kept while the subscription lasts.
+func NewTieredClient(local *MemoryClient, shared SharedCache, channel string, localTTL time.Duration) (*TieredClient, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	feed, err := shared.SubscribeChannel(ctx, channel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	t := &TieredClient{
+		local:    local,
+		shared:   shared,
+		channel:  channel,
+		localTTL: localTTL,
+		origin:   uuid.NewString(),
+		stopFeed: cancel,
+	}
+	go t.consume(feed)
+	return t, nil
+}
+
+// Shared returns the cache shared by every instance, for the features L1 can't serve such as Redis streams
+func (t *TieredClient) Shared() types.CacheClient {
+	return t.shared
+}
+
+// Get returns the local copy of key, or reads it from L2 and keeps a copy. Misses are not kept, a key written by
+// another instance is seen at once.
+func (t *TieredClient) Get(ctx context.Context, key string) (string, error) {
+	if value, err := t.local.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	t.mu.Lock()
+	invalidations := t.invalidations
+	t.mu.Unlock()
+
+	value, err := t.shared.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	t.keepCopy(ctx, key, value, t.localTTL, invalidations)
+	return value, nil
+}
+
+// Set stores the value in L2 and keeps a copy, the copies of the other instances are dropped
+func (t *TieredClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if err := t.shared.Set(ctx, key, value, expiration); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.invalidations++
+	var err error
+	if !t.feedDone {
+		ttl := t.localTTL
+		if expiration > 0 && expiration < ttl {
+			ttl = expiration
+		}
+		err = t.local.Set(ctx, key, value, ttl)
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return t.broadcast(ctx, key)
+}
+
+// Delete removes the key from L2 and from every instance
+func (t *TieredClient) Delete(ctx context.Context, key string) error {
+	if err := t.shared.Delete(ctx, key); err != nil {
+		return err
+	}
+	t.drop(key)
+	return t.broadcast(ctx, key)
+}
+
+// Increment increments the counter in L2, counters are shared by every instance so copies of it are dropped
+func (t *TieredClient) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	count, err := t.shared.Increment(ctx, key, expiration)
+	if err != nil {
+		return 0, err
+	}
+	t.drop(key)
+	return count, t.broadcast(ctx, key)
+}
+
+// Decrement decrements the counter in L2 and drops the copies of it, like Increment
+func (t *TieredClient) Decrement(ctx context.Context, key string) (int64, error) {
+	count, err := t.shared.Decrement(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	t.drop(key)
+	return count, t.broadcast(ctx, key)
+}
+
+// NamespaceVersion returns the local copy of the version of a namespace, or reads it from L2 and keeps a copy
+func (t *TieredClient) NamespaceVersion(ctx context.Context, namespace string) (string, error) {
+	key := types.NamespaceVersionKey(namespace)
+	if version, err := t.local.Get(ctx, key); err == nil {
+		return version, nil
+	}
+
+	t.mu.Lock()
+	invalidations := t.invalidations
+	t.mu.Unlock()
+
+	version, err := t.shared.NamespaceVersion(ctx, namespace)
+	if err != nil {
+		return "", err
+	}
+	t.keepCopy(ctx, key, version, t.localTTL, invalidations)
+	return version, nil
+}
+
+// InvalidateNamespace moves a namespace to a new version in L2, every instance drops its copy of the version
+func (t *TieredClient) InvalidateNamespace(ctx context.Context, namespace string) error {
+	if err := t.shared.InvalidateNamespace(ctx, namespace); err != nil {
+		return err
+	}
+	key := types.NamespaceVersionKey(namespace)
+	t.drop(key)
+	return t.broadcast(ctx, key)
+}
+
+// Close unsubscribes from the channel, the copies are dropped once the subscription ends. The shared cache is
+// left open.
+func (t *TieredClient) Close() error {
+	t.stopFeed()
+	return nil
+}
+
+// keepCopy copies a value read from L2 to L1 unless a key was dropped since invalidations
+func (t *TieredClient) keepCopy(ctx context.Context, key, value string, ttl time.Duration, invalidations uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.invalidations != invalidations || t.feedDone {
+		return
+	}
+	t.local.Set(ctx, key, value, ttl)
+}
+
+// drop removes the local copy of key
+func (t *TieredClient) drop(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.invalidations++
+	t.local.Delete(context.Background(), key)
+}
+
+// broadcast tells the other instances to drop their copy of key
+func (t *TieredClient) broadcast(ctx context.Context, key string) error {
+	message, err := json.Marshal(invalidationMessage{Origin: t.origin, Key: key})
+	if err != nil {
+		return err
+	}
+	if err := t.shared.PublishMessage(ctx, t.channel, string(message)); err != nil {
+		return fmt.Errorf("failed to broadcast cache invalidation: %w", err)
+	}
+	return nil
+}
+
+// consume drops the copies of the keys written by the other instances until the subscription ends
+func (t *TieredClient) consume(feed <-chan string) {
+	for raw := range feed {
+		var message invalidationMessage
+		if err := json.Unmarshal([]byte(raw), &message); err != nil || message.Origin == t.origin {
+			continue
+		}
+		t.drop(message.Key)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.feedDone = true
+	t.invalidations++
+	t.local.Flush()
+}
@@ -0,0 +1,3 @@
+ALTER TABLE `transactions`
+DROP COLUMN `currency`,
+MODIFY COLUMN `amount` INTEGER NOT NULL DEFAULT 0;
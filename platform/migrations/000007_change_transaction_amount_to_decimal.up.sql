@@ -0,0 +1,10 @@
+-- Store transaction amounts with the same precision as account balances
+ALTER TABLE `transactions`
+MODIFY COLUMN `amount` decimal(15, 2) NOT NULL DEFAULT 0,
+ADD COLUMN `currency` varchar(10) NOT NULL DEFAULT 'THB';
+
+-- Backfill currency from the owning account
+UPDATE `transactions` t
+JOIN `accounts` a ON t.account_id = a.account_id
+SET t.currency = a.currency
+WHERE a.currency IS NOT NULL;
@@ -0,0 +1,62 @@
+CREATE TABLE `journal_entries` (
+    `entry_id` varchar(50) NOT NULL,
+    `entry_type` varchar(30) NOT NULL,
+    `reference_id` varchar(50) DEFAULT NULL,
+    `description` varchar(255) DEFAULT NULL,
+    `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`entry_id`),
+    KEY `idx_journal_entries_reference_id` (`reference_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- Postings are append-only; amount is always positive and direction gives the sign
+CREATE TABLE `ledger_postings` (
+    `posting_id` bigint NOT NULL AUTO_INCREMENT,
+    `entry_id` varchar(50) NOT NULL,
+    `account_id` varchar(50) NOT NULL,
+    `direction` enum('debit', 'credit') NOT NULL,
+    `amount` decimal(15, 2) NOT NULL,
+    `currency` varchar(10) NOT NULL,
+    `balance_after` decimal(15, 2) NOT NULL,
+    `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`posting_id`),
+    KEY `idx_ledger_postings_entry_id` (`entry_id`),
+    KEY `idx_ledger_postings_account_created` (`account_id`, `created_at`),
+    CONSTRAINT `chk_ledger_postings_amount` CHECK (`amount` > 0)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- Running balance per ledger account, locked while posting to serialize balance_after
+CREATE TABLE `ledger_accounts` (
+    `account_id` varchar(50) NOT NULL,
+    `currency` varchar(10) NOT NULL,
+    `balance` decimal(15, 2) NOT NULL DEFAULT 0,
+    `updated_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`account_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- Open a ledger account for every existing account
+INSERT INTO `ledger_accounts` (`account_id`, `currency`, `balance`)
+SELECT b.account_id, COALESCE(a.currency, 'THB'), COALESCE(b.amount, 0)
+FROM `account_balances` b
+JOIN `accounts` a ON a.account_id = b.account_id;
+
+-- Book existing balances as opening-balance entries against the external account of their currency
+INSERT INTO `journal_entries` (`entry_id`, `entry_type`, `reference_id`, `description`)
+SELECT CONCAT('opening-', account_id), 'opening-balance', account_id, 'Opening balance'
+FROM `ledger_accounts`
+WHERE balance <> 0;
+
+INSERT INTO `ledger_postings` (`entry_id`, `account_id`, `direction`, `amount`, `currency`, `balance_after`)
+SELECT CONCAT('opening-', account_id), account_id, IF(balance > 0, 'credit', 'debit'), ABS(balance), currency, balance
+FROM `ledger_accounts`
+WHERE balance <> 0;
+
+INSERT INTO `ledger_postings` (`entry_id`, `account_id`, `direction`, `amount`, `currency`, `balance_after`)
+SELECT CONCAT('opening-', account_id), CONCAT('ledger:external:', currency), IF(balance > 0, 'debit', 'credit'), ABS(balance), currency,
+    -SUM(balance) OVER (PARTITION BY currency ORDER BY account_id)
+FROM `ledger_accounts`
+WHERE balance <> 0;
+
+INSERT INTO `ledger_accounts` (`account_id`, `currency`, `balance`)
+SELECT CONCAT('ledger:external:', currency), currency, -SUM(balance)
+FROM `ledger_accounts`
+GROUP BY currency;
@@ -0,0 +1,12 @@
+CREATE TABLE `idempotency_keys` (
+    `user_id` varchar(50) NOT NULL,
+    `idempotency_key` varchar(255) NOT NULL,
+    `request_hash` char(64) NOT NULL,
+    `status` enum('processing', 'completed') NOT NULL DEFAULT 'processing',
+    `response_status` int NOT NULL DEFAULT 0,
+    `response_body` mediumblob,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `expires_at` timestamp NOT NULL,
+    PRIMARY KEY (`user_id`, `idempotency_key`),
+    KEY `idx_idempotency_keys_expires_at` (`expires_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
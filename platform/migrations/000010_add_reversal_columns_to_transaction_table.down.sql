@@ -0,0 +1,6 @@
+ALTER TABLE `transactions`
+DROP INDEX `idx_transactions_reversal_of`,
+DROP COLUMN `reversed_amount`,
+DROP COLUMN `reversal_of`,
+DROP COLUMN `linked_transaction_id`,
+DROP COLUMN `direction`;
@@ -0,0 +1,13 @@
+-- direction is the effect on the account: debit decreases the balance, credit increases it
+ALTER TABLE `transactions`
+ADD COLUMN `direction` enum('debit', 'credit') NOT NULL DEFAULT 'credit',
+ADD COLUMN `linked_transaction_id` varchar(50) DEFAULT NULL,
+ADD COLUMN `reversal_of` varchar(50) DEFAULT NULL,
+ADD COLUMN `reversed_amount` decimal(15, 2) NOT NULL DEFAULT 0,
+ADD INDEX `idx_transactions_reversal_of` (`reversal_of`);
+
+-- Backfill direction, transfers were named "Transfer to <account number>" on the source side
+UPDATE `transactions`
+SET `direction` = 'debit'
+WHERE `transaction_type` = 'withdrawal'
+    OR (`transaction_type` = 'transfer' AND `name` LIKE 'Transfer to %');
@@ -0,0 +1,43 @@
+-- next_run_at is the occurrence being executed, next_attempt_at is when the scheduler may pick it up (includes retry backoff)
+DROP TABLE IF EXISTS `scheduled_transfers`;
+CREATE TABLE `scheduled_transfers` (
+    `schedule_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `from_account_id` varchar(50) NOT NULL,
+    `to_account_id` varchar(50) NOT NULL,
+    `amount` decimal(15, 2) NOT NULL,
+    `currency` varchar(10) NOT NULL,
+    `description` varchar(255) NOT NULL DEFAULT '',
+    `frequency` enum('once', 'daily', 'weekly', 'monthly') NOT NULL,
+    `start_at` timestamp NOT NULL,
+    `end_at` timestamp NULL DEFAULT NULL,
+    `next_run_at` timestamp NOT NULL,
+    `next_attempt_at` timestamp NOT NULL,
+    `last_run_at` timestamp NULL DEFAULT NULL,
+    `status` enum('active', 'paused', 'completed', 'cancelled') NOT NULL DEFAULT 'active',
+    `insufficient_funds_policy` enum('skip', 'retry') NOT NULL DEFAULT 'skip',
+    `retry_count` int NOT NULL DEFAULT 0,
+    `lease_owner` varchar(50) DEFAULT NULL,
+    `lease_expires_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `deleted_at` timestamp NULL DEFAULT NULL,
+    PRIMARY KEY (`schedule_id`),
+    KEY `idx_scheduled_transfers_from_account_id` (`from_account_id`),
+    KEY `idx_scheduled_transfers_due` (`status`, `next_attempt_at`),
+    KEY `idx_scheduled_transfers_lease_owner` (`lease_owner`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+DROP TABLE IF EXISTS `scheduled_transfer_executions`;
+CREATE TABLE `scheduled_transfer_executions` (
+    `execution_id` varchar(50) NOT NULL,
+    `schedule_id` varchar(50) NOT NULL,
+    `scheduled_for` timestamp NOT NULL,
+    `executed_at` timestamp NOT NULL,
+    `attempt` int NOT NULL DEFAULT 1,
+    `status` enum('succeeded', 'skipped', 'retrying', 'failed') NOT NULL,
+    `message` varchar(255) NOT NULL DEFAULT '',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`execution_id`),
+    KEY `idx_scheduled_transfer_executions_schedule_id` (`schedule_id`, `executed_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE `transactions` DROP INDEX `idx_transactions_account_created_at`;
+
+DROP TABLE IF EXISTS `transfer_limits`;
@@ -0,0 +1,24 @@
+-- Limits on money leaving an account. An empty account_type or user_id matches any, the most specific row wins
+-- per column: user and account type > user > account type > global. A NULL limit falls through to the next row,
+-- a currency without any row is unlimited.
+DROP TABLE IF EXISTS `transfer_limits`;
+CREATE TABLE `transfer_limits` (
+    `account_type` varchar(50) NOT NULL DEFAULT '',
+    `user_id` varchar(50) NOT NULL DEFAULT '',
+    `currency` varchar(10) NOT NULL,
+    `per_transaction_limit` decimal(15, 2) DEFAULT NULL,
+    `daily_limit` decimal(15, 2) DEFAULT NULL,
+    `monthly_limit` decimal(15, 2) DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`account_type`, `user_id`, `currency`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+INSERT INTO `transfer_limits` (`account_type`, `currency`, `per_transaction_limit`, `daily_limit`, `monthly_limit`)
+VALUES
+    ('saving-account', 'THB', 200000.00, 500000.00, 5000000.00),
+    ('credit-loan', 'THB', 50000.00, 100000.00, 1000000.00),
+    ('goal-driven-saving', 'THB', 50000.00, 100000.00, 500000.00);
+
+-- Usage is summed from the debits of an account within the window
+ALTER TABLE `transactions` ADD INDEX `idx_transactions_account_created_at` (`account_id`, `created_at`);
@@ -0,0 +1,6 @@
+ALTER TABLE `transactions`
+DROP COLUMN `fx_quote_id`,
+DROP COLUMN `exchange_rate`;
+
+DROP TABLE IF EXISTS `fx_quotes`;
+DROP TABLE IF EXISTS `fx_rates`;
@@ -0,0 +1,41 @@
+-- Reference rates used by the database-backed rate provider, one unit of base_currency buys rate units of
+-- quote_currency. The opposite direction is derived from the inverse when it has no row of its own.
+DROP TABLE IF EXISTS `fx_rates`;
+CREATE TABLE `fx_rates` (
+    `base_currency` varchar(10) NOT NULL,
+    `quote_currency` varchar(10) NOT NULL,
+    `rate` decimal(20, 8) NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`base_currency`, `quote_currency`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+INSERT INTO `fx_rates` (`base_currency`, `quote_currency`, `rate`)
+VALUES
+    ('USD', 'THB', 36.50000000),
+    ('EUR', 'THB', 39.80000000),
+    ('GBP', 'THB', 46.20000000),
+    ('JPY', 'THB', 0.24500000),
+    ('EUR', 'USD', 1.09000000);
+
+-- A quote locks a rate and both amounts for one user until it expires, a transfer consumes it once
+DROP TABLE IF EXISTS `fx_quotes`;
+CREATE TABLE `fx_quotes` (
+    `quote_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `from_currency` varchar(10) NOT NULL,
+    `to_currency` varchar(10) NOT NULL,
+    `rate` decimal(20, 8) NOT NULL,
+    `source_amount` decimal(15, 2) NOT NULL,
+    `target_amount` decimal(15, 2) NOT NULL,
+    `expires_at` timestamp NOT NULL,
+    `used_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`quote_id`),
+    INDEX `idx_fx_quotes_user_id` (`user_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- Both legs of a cross-currency transfer keep the applied rate and the quote it came from
+ALTER TABLE `transactions`
+ADD COLUMN `exchange_rate` decimal(20, 8) DEFAULT NULL,
+ADD COLUMN `fx_quote_id` varchar(50) DEFAULT NULL;
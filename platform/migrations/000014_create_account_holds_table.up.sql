@@ -0,0 +1,21 @@
+-- Funds reserved on an account, e.g. by a card authorization. The available balance is the ledger balance
+-- (account_balances.amount) less the active holds that have not expired yet, so a hold stops counting as soon
+-- as expires_at passes even before it is marked expired.
+DROP TABLE IF EXISTS `account_holds`;
+CREATE TABLE `account_holds` (
+    `hold_id` varchar(50) NOT NULL,
+    `account_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `amount` decimal(15, 2) NOT NULL,
+    `captured_amount` decimal(15, 2) NOT NULL DEFAULT 0,
+    `currency` varchar(10) NOT NULL,
+    `description` varchar(255) NOT NULL DEFAULT '',
+    `status` enum('active', 'captured', 'released', 'expired') NOT NULL DEFAULT 'active',
+    `expires_at` timestamp NOT NULL,
+    `transaction_id` varchar(50) DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`hold_id`),
+    KEY `idx_account_holds_active` (`account_id`, `status`, `expires_at`),
+    KEY `idx_account_holds_expiry` (`status`, `expires_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
@@ -0,0 +1,19 @@
+-- Issued refresh tokens, only the sha256 of a token is stored. Every token is used once: renewing marks it used
+-- and issues the next token of the same family, a family being the chain of tokens of one sign-in on one device.
+-- Presenting a used token again means it leaked, so the whole family is revoked. Used and revoked rows are kept
+-- until they expire to recognise such reuse.
+DROP TABLE IF EXISTS `refresh_tokens`;
+CREATE TABLE `refresh_tokens` (
+    `token_hash` char(64) NOT NULL,
+    `family_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `device_id` varchar(100) NOT NULL DEFAULT '',
+    `expires_at` timestamp NOT NULL,
+    `used_at` timestamp NULL DEFAULT NULL,
+    `revoked_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`token_hash`),
+    KEY `idx_refresh_tokens_family` (`family_id`),
+    KEY `idx_refresh_tokens_user` (`user_id`, `revoked_at`),
+    KEY `idx_refresh_tokens_expiry` (`expires_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
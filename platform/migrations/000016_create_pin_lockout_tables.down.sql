@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `pin_ip_failures`;
+DROP TABLE IF EXISTS `pin_lockouts`;
@@ -0,0 +1,22 @@
+-- Failed PIN verifications of a user since the last successful one. Failures back off exponentially, lock the PIN
+-- temporarily (locked_until) and finally for good (locked_at) until the PIN is reset. Redis caches the rows.
+DROP TABLE IF EXISTS `pin_lockouts`;
+CREATE TABLE `pin_lockouts` (
+    `user_id` varchar(50) NOT NULL,
+    `failed_attempts` int NOT NULL DEFAULT 0,
+    `last_failed_at` timestamp NULL DEFAULT NULL,
+    `locked_until` timestamp NULL DEFAULT NULL,
+    `locked_at` timestamp NULL DEFAULT NULL,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- Failed PIN verifications per IP address and fixed window, only counted here while Redis is unavailable
+DROP TABLE IF EXISTS `pin_ip_failures`;
+CREATE TABLE `pin_ip_failures` (
+    `ip_address` varchar(45) NOT NULL,
+    `window_start` timestamp NOT NULL,
+    `failures` int NOT NULL DEFAULT 0,
+    PRIMARY KEY (`ip_address`, `window_start`),
+    KEY `idx_pin_ip_failures_window` (`window_start`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `pin_reset_codes`;
+DROP TABLE IF EXISTS `pin_history`;
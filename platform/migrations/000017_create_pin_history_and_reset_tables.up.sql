@@ -0,0 +1,22 @@
+-- Hashes of the PINs a user replaced, a new PIN may not be the current PIN or one of the most recent ones here
+DROP TABLE IF EXISTS `pin_history`;
+CREATE TABLE `pin_history` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `user_id` varchar(50) NOT NULL,
+    `pin_hash` varchar(255) NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_pin_history_user` (`user_id`, `id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- The outstanding one-time code to reset a forgotten PIN, stored hashed. A new request replaces the code of the
+-- user, and a code stops working when it expires, is used or was guessed wrong too often.
+DROP TABLE IF EXISTS `pin_reset_codes`;
+CREATE TABLE `pin_reset_codes` (
+    `user_id` varchar(50) NOT NULL,
+    `code_hash` varchar(255) NOT NULL,
+    `attempts` int NOT NULL DEFAULT 0,
+    `expires_at` timestamp NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
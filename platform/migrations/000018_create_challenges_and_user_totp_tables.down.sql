@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `user_totp`;
+DROP TABLE IF EXISTS `challenges`;
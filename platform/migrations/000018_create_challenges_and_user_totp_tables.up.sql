@@ -0,0 +1,30 @@
+-- Second factor challenges for operations that need step-up authentication, e.g. transfers above the threshold.
+-- The operation is stored as JSON in payload and runs once the challenge is confirmed with the PIN or a TOTP code.
+-- A challenge is confirmed at most once and stops working when it expires or was answered wrong too often.
+DROP TABLE IF EXISTS `challenges`;
+CREATE TABLE `challenges` (
+    `challenge_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `action` enum('transfer') NOT NULL,
+    `payload` json NOT NULL,
+    `attempts` int NOT NULL DEFAULT 0,
+    `expires_at` timestamp NOT NULL,
+    `confirmed_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`challenge_id`),
+    KEY `idx_challenges_user` (`user_id`),
+    KEY `idx_challenges_expiry` (`expires_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- TOTP (RFC 6238) authenticators of users. The base32 secret is kept as is since codes are computed from it, a
+-- secret only confirms challenges once enabled with a first valid code. last_used_step stops a code from being
+-- replayed within its validity window.
+DROP TABLE IF EXISTS `user_totp`;
+CREATE TABLE `user_totp` (
+    `user_id` varchar(50) NOT NULL,
+    `secret` varchar(64) NOT NULL,
+    `enabled_at` timestamp NULL DEFAULT NULL,
+    `last_used_step` bigint DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
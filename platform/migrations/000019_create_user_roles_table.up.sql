@@ -0,0 +1,10 @@
+-- Staff roles of users for the admin API, customers have no row. The permissions of a role are defined in code
+-- (types.RolePermissions) and carried in the access token together with the roles, so a role granted or revoked
+-- here takes effect when the user next signs in or renews their token.
+DROP TABLE IF EXISTS `user_roles`;
+CREATE TABLE `user_roles` (
+    `user_id` varchar(50) NOT NULL,
+    `role` enum('support', 'operations', 'marketing', 'admin') NOT NULL,
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`, `role`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
@@ -0,0 +1,19 @@
+-- Signed-in devices of users. A session is created by each PIN sign-in and shares its id with the refresh token
+-- family of the sign-in, access tokens carry it in the sid claim. Revoking a session revokes its refresh tokens
+-- and puts the session on a revocation list in Redis until its last access token has expired.
+DROP TABLE IF EXISTS `user_sessions`;
+CREATE TABLE `user_sessions` (
+    `session_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `device_id` varchar(100) NOT NULL DEFAULT '',
+    `device_name` varchar(100) NOT NULL DEFAULT '',
+    `platform` varchar(50) NOT NULL DEFAULT '',
+    `user_agent` varchar(255) NOT NULL DEFAULT '',
+    `ip_address` varchar(45) NOT NULL DEFAULT '',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `last_seen_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `revoked_at` timestamp NULL DEFAULT NULL,
+    PRIMARY KEY (`session_id`),
+    KEY `idx_user_sessions_user` (`user_id`, `revoked_at`),
+    KEY `idx_user_sessions_last_seen` (`last_seen_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
@@ -0,0 +1,29 @@
+-- Append-only audit trail of security and money events: who did what, from where, and the state before and after.
+-- Entries are only ever inserted, the triggers refuse updates and deletes so that not even the application can
+-- rewrite history. Creating triggers with binary logging enabled needs log_bin_trust_function_creators or SUPER.
+DROP TABLE IF EXISTS `audit_logs`;
+CREATE TABLE `audit_logs` (
+    `audit_id` bigint unsigned NOT NULL AUTO_INCREMENT,
+    `action` varchar(50) NOT NULL,
+    `outcome` enum('success', 'failure') NOT NULL DEFAULT 'success',
+    `actor_id` varchar(50) NOT NULL DEFAULT '',
+    `actor_type` enum('user', 'staff', 'system') NOT NULL DEFAULT 'user',
+    `resource_type` varchar(50) NOT NULL DEFAULT '',
+    `resource_id` varchar(50) NOT NULL DEFAULT '',
+    `before_state` json NULL,
+    `after_state` json NULL,
+    `ip_address` varchar(45) NOT NULL DEFAULT '',
+    `request_id` varchar(64) NOT NULL DEFAULT '',
+    `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`audit_id`),
+    KEY `idx_audit_logs_actor` (`actor_id`, `audit_id`),
+    KEY `idx_audit_logs_resource` (`resource_type`, `resource_id`, `audit_id`),
+    KEY `idx_audit_logs_action` (`action`, `audit_id`),
+    KEY `idx_audit_logs_created` (`created_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TRIGGER `audit_logs_no_update` BEFORE UPDATE ON `audit_logs`
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+
+CREATE TRIGGER `audit_logs_no_delete` BEFORE DELETE ON `audit_logs`
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
@@ -0,0 +1,33 @@
+-- Transactional outbox of domain events. Events are inserted in the database transaction of the change they
+-- describe and published afterwards by the relay, so an event is published if and only if its change committed.
+-- The sequence orders events: changes of one account lock its balance row, so its events get increasing sequences.
+-- A transfer concerns two accounts, the destination is kept in related_aggregate_id to order its events too.
+DROP TABLE IF EXISTS `outbox_events`;
+CREATE TABLE `outbox_events` (
+    `sequence` bigint unsigned NOT NULL AUTO_INCREMENT,
+    `event_id` varchar(50) NOT NULL,
+    `event_type` varchar(50) NOT NULL,
+    `aggregate_type` varchar(50) NOT NULL,
+    `aggregate_id` varchar(50) NOT NULL,
+    `related_aggregate_id` varchar(50) NOT NULL DEFAULT '',
+    `payload` json NOT NULL,
+    `occurred_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `published_at` timestamp(6) NULL DEFAULT NULL,
+    `attempts` int NOT NULL DEFAULT 0,
+    `last_error` varchar(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (`sequence`),
+    UNIQUE KEY `uq_outbox_events_event_id` (`event_id`),
+    KEY `idx_outbox_events_published` (`published_at`, `sequence`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- A single instance relays at a time, it holds the lease of this row. Relaying from several instances at once
+-- could publish later events of an account before earlier ones that another instance failed to publish.
+DROP TABLE IF EXISTS `outbox_relay_lease`;
+CREATE TABLE `outbox_relay_lease` (
+    `lease_name` varchar(50) NOT NULL,
+    `lease_owner` varchar(100) NOT NULL DEFAULT '',
+    `lease_expires_at` timestamp(6) NULL DEFAULT NULL,
+    PRIMARY KEY (`lease_name`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+INSERT INTO `outbox_relay_lease` (`lease_name`) VALUES ('outbox-relay');
@@ -0,0 +1,41 @@
+-- Endpoints partner apps receive domain events at. Endpoints of the user scope receive the events concerning the
+-- user who registered them, staff register endpoints of the all scope that receive every event.
+DROP TABLE IF EXISTS `webhook_endpoints`;
+CREATE TABLE `webhook_endpoints` (
+    `endpoint_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `scope` varchar(10) NOT NULL DEFAULT 'user',
+    `url` varchar(500) NOT NULL,
+    `description` varchar(255) NOT NULL DEFAULT '',
+    `event_types` varchar(500) NOT NULL DEFAULT '', -- comma separated, empty subscribes to every type
+    `secret` varchar(100) NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `deleted_at` timestamp NULL DEFAULT NULL,
+    PRIMARY KEY (`endpoint_id`),
+    KEY `idx_webhook_endpoints_user_id` (`user_id`),
+    KEY `idx_webhook_endpoints_scope` (`scope`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+-- One delivery per event and endpoint, it doubles as the delivery log. Failed attempts are retried with an
+-- exponential backoff until the delivery dies, dead deliveries are only sent again when replayed.
+DROP TABLE IF EXISTS `webhook_deliveries`;
+CREATE TABLE `webhook_deliveries` (
+    `delivery_id` bigint unsigned NOT NULL AUTO_INCREMENT,
+    `endpoint_id` varchar(50) NOT NULL,
+    `event_id` varchar(50) NOT NULL,
+    `event_type` varchar(50) NOT NULL,
+    `payload` json NOT NULL,
+    `status` varchar(20) NOT NULL DEFAULT 'pending',
+    `attempts` int NOT NULL DEFAULT 0,
+    `next_attempt_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `last_status_code` int NOT NULL DEFAULT 0,
+    `last_error` varchar(255) NOT NULL DEFAULT '',
+    `delivered_at` timestamp(6) NULL DEFAULT NULL,
+    `lease_owner` varchar(100) NULL DEFAULT NULL,
+    `lease_expires_at` timestamp(6) NULL DEFAULT NULL,
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`delivery_id`),
+    UNIQUE KEY `uq_webhook_deliveries_endpoint_event` (`endpoint_id`, `event_id`),
+    KEY `idx_webhook_deliveries_due` (`status`, `next_attempt_at`),
+    CONSTRAINT `fk_webhook_deliveries_endpoint` FOREIGN KEY (`endpoint_id`) REFERENCES `webhook_endpoints` (`endpoint_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE `transactions`
+ADD INDEX `idx_transactions_user_id` (`user_id`),
+DROP INDEX `idx_transactions_user_created_at`;
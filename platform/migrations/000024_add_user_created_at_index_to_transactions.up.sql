@@ -0,0 +1,5 @@
+-- Transaction listings of a user seek to a (created_at, transaction_id) cursor, the primary key stored in every
+-- secondary index breaks the ties. The index on user_id alone is a prefix of this one.
+ALTER TABLE `transactions`
+ADD INDEX `idx_transactions_user_created_at` (`user_id`, `created_at`),
+DROP INDEX `idx_transactions_user_id`;
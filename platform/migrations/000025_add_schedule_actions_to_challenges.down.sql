@@ -0,0 +1,3 @@
+DELETE FROM `challenges` WHERE `action` IN ('schedule', 'schedule_update');
+ALTER TABLE `challenges`
+MODIFY `action` enum('transfer') NOT NULL;
@@ -0,0 +1,4 @@
+-- Schedules paying more than the step-up threshold are created or changed once a challenge is confirmed, the
+-- payload holds the ScheduledTransfer or the PendingScheduleUpdate.
+ALTER TABLE `challenges`
+MODIFY `action` enum('transfer', 'schedule', 'schedule_update') NOT NULL;
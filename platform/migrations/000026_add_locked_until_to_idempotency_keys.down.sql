@@ -0,0 +1,2 @@
+ALTER TABLE `idempotency_keys`
+DROP `locked_until`;
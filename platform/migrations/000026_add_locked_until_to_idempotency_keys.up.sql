@@ -0,0 +1,6 @@
+-- A key is leased to the request processing it, a key left processing past its lease by a request that never
+-- finished can be claimed again by a retry of the same request.
+ALTER TABLE `idempotency_keys`
+ADD `locked_until` timestamp NULL DEFAULT NULL AFTER `status`;
+
+UPDATE `idempotency_keys` SET `locked_until` = `created_at` + INTERVAL 5 MINUTE WHERE `status` = 'processing';